@@ -0,0 +1,196 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Code generated by thriftrw-plugin-yarpc
+// @generated
+
+package adminserviceserver
+
+import (
+	"context"
+	"github.com/uber/cadence/.gen/go/admin"
+	"github.com/uber/cadence/.gen/go/shared"
+	"go.uber.org/thriftrw/wire"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/encoding/thrift"
+)
+
+// Interface is the server-side interface for the AdminService service.
+type Interface interface {
+	ListTaskLists(
+		ctx context.Context,
+		Request *shared.ListTaskListsRequest,
+	) (*shared.ListTaskListsResponse, error)
+
+	PauseTaskList(
+		ctx context.Context,
+		Request *shared.PauseTaskListRequest,
+	) error
+
+	ResumeTaskList(
+		ctx context.Context,
+		Request *shared.ResumeTaskListRequest,
+	) error
+
+	UpdateTaskListBuildIDs(
+		ctx context.Context,
+		Request *shared.UpdateTaskListBuildIDsRequest,
+	) error
+}
+
+// New prepares an implementation of the AdminService service for
+// registration.
+//
+// 	handler := AdminServiceHandler{}
+// 	dispatcher.Register(adminserviceserver.New(handler))
+func New(impl Interface, opts ...thrift.RegisterOption) []transport.Procedure {
+	h := handler{impl}
+	service := thrift.Service{
+		Name: "AdminService",
+		Methods: []thrift.Method{
+
+			thrift.Method{
+				Name: "ListTaskLists",
+				HandlerSpec: thrift.HandlerSpec{
+
+					Type:  transport.Unary,
+					Unary: thrift.UnaryHandler(h.ListTaskLists),
+				},
+				Signature:    "ListTaskLists(Request *shared.ListTaskListsRequest) (*shared.ListTaskListsResponse)",
+				ThriftModule: admin.ThriftModule,
+			},
+
+			thrift.Method{
+				Name: "PauseTaskList",
+				HandlerSpec: thrift.HandlerSpec{
+
+					Type:  transport.Unary,
+					Unary: thrift.UnaryHandler(h.PauseTaskList),
+				},
+				Signature:    "PauseTaskList(Request *shared.PauseTaskListRequest)",
+				ThriftModule: admin.ThriftModule,
+			},
+
+			thrift.Method{
+				Name: "ResumeTaskList",
+				HandlerSpec: thrift.HandlerSpec{
+
+					Type:  transport.Unary,
+					Unary: thrift.UnaryHandler(h.ResumeTaskList),
+				},
+				Signature:    "ResumeTaskList(Request *shared.ResumeTaskListRequest)",
+				ThriftModule: admin.ThriftModule,
+			},
+
+			thrift.Method{
+				Name: "UpdateTaskListBuildIDs",
+				HandlerSpec: thrift.HandlerSpec{
+
+					Type:  transport.Unary,
+					Unary: thrift.UnaryHandler(h.UpdateTaskListBuildIDs),
+				},
+				Signature:    "UpdateTaskListBuildIDs(Request *shared.UpdateTaskListBuildIDsRequest)",
+				ThriftModule: admin.ThriftModule,
+			},
+		},
+	}
+
+	procedures := make([]transport.Procedure, 0, 4)
+	procedures = append(procedures, thrift.BuildProcedures(service, opts...)...)
+	return procedures
+}
+
+type handler struct{ impl Interface }
+
+func (h handler) ListTaskLists(ctx context.Context, body wire.Value) (thrift.Response, error) {
+	var args admin.AdminService_ListTaskLists_Args
+	if err := args.FromWire(body); err != nil {
+		return thrift.Response{}, err
+	}
+
+	success, err := h.impl.ListTaskLists(ctx, args.Request)
+
+	hadError := err != nil
+	result, err := admin.AdminService_ListTaskLists_Helper.WrapResponse(success, err)
+
+	var response thrift.Response
+	if err == nil {
+		response.IsApplicationError = hadError
+		response.Body = result
+	}
+	return response, err
+}
+
+func (h handler) PauseTaskList(ctx context.Context, body wire.Value) (thrift.Response, error) {
+	var args admin.AdminService_PauseTaskList_Args
+	if err := args.FromWire(body); err != nil {
+		return thrift.Response{}, err
+	}
+
+	err := h.impl.PauseTaskList(ctx, args.Request)
+
+	hadError := err != nil
+	result, err := admin.AdminService_PauseTaskList_Helper.WrapResponse(err)
+
+	var response thrift.Response
+	if err == nil {
+		response.IsApplicationError = hadError
+		response.Body = result
+	}
+	return response, err
+}
+
+func (h handler) ResumeTaskList(ctx context.Context, body wire.Value) (thrift.Response, error) {
+	var args admin.AdminService_ResumeTaskList_Args
+	if err := args.FromWire(body); err != nil {
+		return thrift.Response{}, err
+	}
+
+	err := h.impl.ResumeTaskList(ctx, args.Request)
+
+	hadError := err != nil
+	result, err := admin.AdminService_ResumeTaskList_Helper.WrapResponse(err)
+
+	var response thrift.Response
+	if err == nil {
+		response.IsApplicationError = hadError
+		response.Body = result
+	}
+	return response, err
+}
+
+func (h handler) UpdateTaskListBuildIDs(ctx context.Context, body wire.Value) (thrift.Response, error) {
+	var args admin.AdminService_UpdateTaskListBuildIDs_Args
+	if err := args.FromWire(body); err != nil {
+		return thrift.Response{}, err
+	}
+
+	err := h.impl.UpdateTaskListBuildIDs(ctx, args.Request)
+
+	hadError := err != nil
+	result, err := admin.AdminService_UpdateTaskListBuildIDs_Helper.WrapResponse(err)
+
+	var response thrift.Response
+	if err == nil {
+		response.IsApplicationError = hadError
+		response.Body = result
+	}
+	return response, err
+}
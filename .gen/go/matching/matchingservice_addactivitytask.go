@@ -222,6 +222,8 @@ func init() {
 			return true
 		case *shared.ServiceBusyError:
 			return true
+		case *RemoteSyncMatchFailedError:
+			return true
 		default:
 			return false
 		}
@@ -248,6 +250,11 @@ func init() {
 				return nil, errors.New("WrapResponse received non-nil error type with nil value for MatchingService_AddActivityTask_Result.ServiceBusyError")
 			}
 			return &MatchingService_AddActivityTask_Result{ServiceBusyError: e}, nil
+		case *RemoteSyncMatchFailedError:
+			if e == nil {
+				return nil, errors.New("WrapResponse received non-nil error type with nil value for MatchingService_AddActivityTask_Result.RemoteSyncMatchFailedError")
+			}
+			return &MatchingService_AddActivityTask_Result{RemoteSyncMatchFailedError: e}, nil
 		}
 
 		return nil, err
@@ -265,6 +272,10 @@ func init() {
 			err = result.ServiceBusyError
 			return
 		}
+		if result.RemoteSyncMatchFailedError != nil {
+			err = result.RemoteSyncMatchFailedError
+			return
+		}
 		return
 	}
 
@@ -274,9 +285,10 @@ func init() {
 //
 // The result of a AddActivityTask execution is sent and received over the wire as this struct.
 type MatchingService_AddActivityTask_Result struct {
-	BadRequestError      *shared.BadRequestError      `json:"badRequestError,omitempty"`
-	InternalServiceError *shared.InternalServiceError `json:"internalServiceError,omitempty"`
-	ServiceBusyError     *shared.ServiceBusyError     `json:"serviceBusyError,omitempty"`
+	BadRequestError            *shared.BadRequestError      `json:"badRequestError,omitempty"`
+	InternalServiceError       *shared.InternalServiceError `json:"internalServiceError,omitempty"`
+	ServiceBusyError           *shared.ServiceBusyError     `json:"serviceBusyError,omitempty"`
+	RemoteSyncMatchFailedError *RemoteSyncMatchFailedError  `json:"remoteSyncMatchFailedError,omitempty"`
 }
 
 // ToWire translates a MatchingService_AddActivityTask_Result struct into a Thrift-level intermediate
@@ -296,7 +308,7 @@ type MatchingService_AddActivityTask_Result struct {
 //   }
 func (v *MatchingService_AddActivityTask_Result) ToWire() (wire.Value, error) {
 	var (
-		fields [3]wire.Field
+		fields [4]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -326,6 +338,14 @@ func (v *MatchingService_AddActivityTask_Result) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 3, Value: w}
 		i++
 	}
+	if v.RemoteSyncMatchFailedError != nil {
+		w, err = v.RemoteSyncMatchFailedError.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 4, Value: w}
+		i++
+	}
 
 	if i > 1 {
 		return wire.Value{}, fmt.Errorf("MatchingService_AddActivityTask_Result should have at most one field: got %v fields", i)
@@ -352,6 +372,12 @@ func _ServiceBusyError_Read(w wire.Value) (*shared.ServiceBusyError, error) {
 	return &v, err
 }
 
+func _RemoteSyncMatchFailedError_Read(w wire.Value) (*RemoteSyncMatchFailedError, error) {
+	var v RemoteSyncMatchFailedError
+	err := v.FromWire(w)
+	return &v, err
+}
+
 // FromWire deserializes a MatchingService_AddActivityTask_Result struct from its Thrift-level
 // representation. The Thrift-level representation may be obtained
 // from a ThriftRW protocol implementation.
@@ -397,6 +423,14 @@ func (v *MatchingService_AddActivityTask_Result) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 4:
+			if field.Value.Type() == wire.TStruct {
+				v.RemoteSyncMatchFailedError, err = _RemoteSyncMatchFailedError_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -411,6 +445,9 @@ func (v *MatchingService_AddActivityTask_Result) FromWire(w wire.Value) error {
 	if v.ServiceBusyError != nil {
 		count++
 	}
+	if v.RemoteSyncMatchFailedError != nil {
+		count++
+	}
 	if count > 1 {
 		return fmt.Errorf("MatchingService_AddActivityTask_Result should have at most one field: got %v fields", count)
 	}
@@ -425,7 +462,7 @@ func (v *MatchingService_AddActivityTask_Result) String() string {
 		return "<nil>"
 	}
 
-	var fields [3]string
+	var fields [4]string
 	i := 0
 	if v.BadRequestError != nil {
 		fields[i] = fmt.Sprintf("BadRequestError: %v", v.BadRequestError)
@@ -439,6 +476,10 @@ func (v *MatchingService_AddActivityTask_Result) String() string {
 		fields[i] = fmt.Sprintf("ServiceBusyError: %v", v.ServiceBusyError)
 		i++
 	}
+	if v.RemoteSyncMatchFailedError != nil {
+		fields[i] = fmt.Sprintf("RemoteSyncMatchFailedError: %v", v.RemoteSyncMatchFailedError)
+		i++
+	}
 
 	return fmt.Sprintf("MatchingService_AddActivityTask_Result{%v}", strings.Join(fields[:i], ", "))
 }
@@ -457,6 +498,9 @@ func (v *MatchingService_AddActivityTask_Result) Equals(rhs *MatchingService_Add
 	if !((v.ServiceBusyError == nil && rhs.ServiceBusyError == nil) || (v.ServiceBusyError != nil && rhs.ServiceBusyError != nil && v.ServiceBusyError.Equals(rhs.ServiceBusyError))) {
 		return false
 	}
+	if !((v.RemoteSyncMatchFailedError == nil && rhs.RemoteSyncMatchFailedError == nil) || (v.RemoteSyncMatchFailedError != nil && rhs.RemoteSyncMatchFailedError != nil && v.RemoteSyncMatchFailedError.Equals(rhs.RemoteSyncMatchFailedError))) {
+		return false
+	}
 
 	return true
 }
@@ -222,6 +222,8 @@ func init() {
 			return true
 		case *shared.ServiceBusyError:
 			return true
+		case *RemoteSyncMatchFailedError:
+			return true
 		default:
 			return false
 		}
@@ -248,6 +250,11 @@ func init() {
 				return nil, errors.New("WrapResponse received non-nil error type with nil value for MatchingService_AddDecisionTask_Result.ServiceBusyError")
 			}
 			return &MatchingService_AddDecisionTask_Result{ServiceBusyError: e}, nil
+		case *RemoteSyncMatchFailedError:
+			if e == nil {
+				return nil, errors.New("WrapResponse received non-nil error type with nil value for MatchingService_AddDecisionTask_Result.RemoteSyncMatchFailedError")
+			}
+			return &MatchingService_AddDecisionTask_Result{RemoteSyncMatchFailedError: e}, nil
 		}
 
 		return nil, err
@@ -265,6 +272,10 @@ func init() {
 			err = result.ServiceBusyError
 			return
 		}
+		if result.RemoteSyncMatchFailedError != nil {
+			err = result.RemoteSyncMatchFailedError
+			return
+		}
 		return
 	}
 
@@ -274,9 +285,10 @@ func init() {
 //
 // The result of a AddDecisionTask execution is sent and received over the wire as this struct.
 type MatchingService_AddDecisionTask_Result struct {
-	BadRequestError      *shared.BadRequestError      `json:"badRequestError,omitempty"`
-	InternalServiceError *shared.InternalServiceError `json:"internalServiceError,omitempty"`
-	ServiceBusyError     *shared.ServiceBusyError     `json:"serviceBusyError,omitempty"`
+	BadRequestError            *shared.BadRequestError      `json:"badRequestError,omitempty"`
+	InternalServiceError       *shared.InternalServiceError `json:"internalServiceError,omitempty"`
+	ServiceBusyError           *shared.ServiceBusyError     `json:"serviceBusyError,omitempty"`
+	RemoteSyncMatchFailedError *RemoteSyncMatchFailedError  `json:"remoteSyncMatchFailedError,omitempty"`
 }
 
 // ToWire translates a MatchingService_AddDecisionTask_Result struct into a Thrift-level intermediate
@@ -296,7 +308,7 @@ type MatchingService_AddDecisionTask_Result struct {
 //   }
 func (v *MatchingService_AddDecisionTask_Result) ToWire() (wire.Value, error) {
 	var (
-		fields [3]wire.Field
+		fields [4]wire.Field
 		i      int = 0
 		w      wire.Value
 		err    error
@@ -326,6 +338,14 @@ func (v *MatchingService_AddDecisionTask_Result) ToWire() (wire.Value, error) {
 		fields[i] = wire.Field{ID: 3, Value: w}
 		i++
 	}
+	if v.RemoteSyncMatchFailedError != nil {
+		w, err = v.RemoteSyncMatchFailedError.ToWire()
+		if err != nil {
+			return w, err
+		}
+		fields[i] = wire.Field{ID: 4, Value: w}
+		i++
+	}
 
 	if i > 1 {
 		return wire.Value{}, fmt.Errorf("MatchingService_AddDecisionTask_Result should have at most one field: got %v fields", i)
@@ -379,6 +399,14 @@ func (v *MatchingService_AddDecisionTask_Result) FromWire(w wire.Value) error {
 					return err
 				}
 
+			}
+		case 4:
+			if field.Value.Type() == wire.TStruct {
+				v.RemoteSyncMatchFailedError, err = _RemoteSyncMatchFailedError_Read(field.Value)
+				if err != nil {
+					return err
+				}
+
 			}
 		}
 	}
@@ -393,6 +421,9 @@ func (v *MatchingService_AddDecisionTask_Result) FromWire(w wire.Value) error {
 	if v.ServiceBusyError != nil {
 		count++
 	}
+	if v.RemoteSyncMatchFailedError != nil {
+		count++
+	}
 	if count > 1 {
 		return fmt.Errorf("MatchingService_AddDecisionTask_Result should have at most one field: got %v fields", count)
 	}
@@ -407,7 +438,7 @@ func (v *MatchingService_AddDecisionTask_Result) String() string {
 		return "<nil>"
 	}
 
-	var fields [3]string
+	var fields [4]string
 	i := 0
 	if v.BadRequestError != nil {
 		fields[i] = fmt.Sprintf("BadRequestError: %v", v.BadRequestError)
@@ -421,6 +452,10 @@ func (v *MatchingService_AddDecisionTask_Result) String() string {
 		fields[i] = fmt.Sprintf("ServiceBusyError: %v", v.ServiceBusyError)
 		i++
 	}
+	if v.RemoteSyncMatchFailedError != nil {
+		fields[i] = fmt.Sprintf("RemoteSyncMatchFailedError: %v", v.RemoteSyncMatchFailedError)
+		i++
+	}
 
 	return fmt.Sprintf("MatchingService_AddDecisionTask_Result{%v}", strings.Join(fields[:i], ", "))
 }
@@ -439,6 +474,9 @@ func (v *MatchingService_AddDecisionTask_Result) Equals(rhs *MatchingService_Add
 	if !((v.ServiceBusyError == nil && rhs.ServiceBusyError == nil) || (v.ServiceBusyError != nil && rhs.ServiceBusyError != nil && v.ServiceBusyError.Equals(rhs.ServiceBusyError))) {
 		return false
 	}
+	if !((v.RemoteSyncMatchFailedError == nil && rhs.RemoteSyncMatchFailedError == nil) || (v.RemoteSyncMatchFailedError != nil && rhs.RemoteSyncMatchFailedError != nil && v.RemoteSyncMatchFailedError.Equals(rhs.RemoteSyncMatchFailedError))) {
+		return false
+	}
 
 	return true
 }
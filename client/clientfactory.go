@@ -26,6 +26,7 @@ import (
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/membership"
 	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/service/dynamicconfig"
 )
 
 // Factory can be used to create RPC clients for cadence services
@@ -39,16 +40,19 @@ type rpcClientFactory struct {
 	monitor               membership.Monitor
 	metricsClient         metrics.Client
 	numberOfHistoryShards int
+	dc                    *dynamicconfig.Collection
 }
 
 // NewRPCClientFactory creates an instance of client factory that knows how to dispatch RPC calls.
 func NewRPCClientFactory(df common.RPCFactory,
-	monitor membership.Monitor, metricsClient metrics.Client, numberOfHistoryShards int) Factory {
+	monitor membership.Monitor, metricsClient metrics.Client, numberOfHistoryShards int,
+	dc *dynamicconfig.Collection) Factory {
 	return &rpcClientFactory{
 		df:                    df,
 		monitor:               monitor,
 		metricsClient:         metricsClient,
 		numberOfHistoryShards: numberOfHistoryShards,
+		dc:                    dc,
 	}
 }
 
@@ -64,7 +68,7 @@ func (cf *rpcClientFactory) NewHistoryClient() (history.Client, error) {
 }
 
 func (cf *rpcClientFactory) NewMatchingClient() (matching.Client, error) {
-	client, err := matching.NewClient(cf.df, cf.monitor)
+	client, err := matching.NewClient(cf.df, cf.monitor, cf.dc)
 	if err != nil {
 		return nil, err
 	}
@@ -30,6 +30,7 @@ import (
 	workflow "github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/membership"
+	"github.com/uber/cadence/common/service/dynamicconfig"
 	"go.uber.org/yarpc"
 )
 
@@ -40,19 +41,22 @@ type clientImpl struct {
 	thriftCacheLock sync.RWMutex
 	thriftCache     map[string]matchingserviceclient.Interface
 	rpcFactory      common.RPCFactory
+	loadBalancer    *loadBalancer
 }
 
-// NewClient creates a new history service TChannel client
-func NewClient(d common.RPCFactory, monitor membership.Monitor) (Client, error) {
+// NewClient creates a new matching service TChannel client, the add and poll requests of a task list are spread
+// across the partitions of the task list by updating the task list of the request to the chosen partition
+func NewClient(d common.RPCFactory, monitor membership.Monitor, dc *dynamicconfig.Collection) (Client, error) {
 	sResolver, err := monitor.GetResolver(common.MatchingServiceName)
 	if err != nil {
 		return nil, err
 	}
 
 	client := &clientImpl{
-		rpcFactory:  d,
-		resolver:    sResolver,
-		thriftCache: make(map[string]matchingserviceclient.Interface),
+		rpcFactory:   d,
+		resolver:     sResolver,
+		thriftCache:  make(map[string]matchingserviceclient.Interface),
+		loadBalancer: newLoadBalancer(dc),
 	}
 	return client, nil
 }
@@ -62,6 +66,7 @@ func (c *clientImpl) AddActivityTask(
 	addRequest *m.AddActivityTaskRequest,
 	opts ...yarpc.CallOption) error {
 	opts = common.AggregateYarpcOptions(ctx, opts...)
+	addRequest.TaskList = c.loadBalancer.pickWritePartition(addRequest.TaskList, addRequest.ForwardedFrom)
 	client, err := c.getHostForRequest(addRequest.TaskList.GetName())
 	if err != nil {
 		return err
@@ -76,6 +81,10 @@ func (c *clientImpl) AddDecisionTask(
 	addRequest *m.AddDecisionTaskRequest,
 	opts ...yarpc.CallOption) error {
 	opts = common.AggregateYarpcOptions(ctx, opts...)
+	// decision tasks of workflows pinned to a build ID are routed by the root partition of the task list
+	if addRequest.GetBuildID() == "" {
+		addRequest.TaskList = c.loadBalancer.pickWritePartition(addRequest.TaskList, addRequest.ForwardedFrom)
+	}
 	client, err := c.getHostForRequest(addRequest.TaskList.GetName())
 	if err != nil {
 		return err
@@ -90,6 +99,8 @@ func (c *clientImpl) PollForActivityTask(
 	pollRequest *m.PollForActivityTaskRequest,
 	opts ...yarpc.CallOption) (*workflow.PollForActivityTaskResponse, error) {
 	opts = common.AggregateYarpcOptions(ctx, opts...)
+	pollRequest.PollRequest.TaskList = c.loadBalancer.pickReadPartition(
+		pollRequest.PollRequest.TaskList, pollRequest.ForwardedFrom)
 	client, err := c.getHostForRequest(pollRequest.PollRequest.TaskList.GetName())
 	if err != nil {
 		return nil, err
@@ -104,6 +115,11 @@ func (c *clientImpl) PollForDecisionTask(
 	pollRequest *m.PollForDecisionTaskRequest,
 	opts ...yarpc.CallOption) (*m.PollForDecisionTaskResponse, error) {
 	opts = common.AggregateYarpcOptions(ctx, opts...)
+	// workers with a build ID are routed by the root partition of the task list
+	if pollRequest.PollRequest.GetBuildID() == "" {
+		pollRequest.PollRequest.TaskList = c.loadBalancer.pickReadPartition(
+			pollRequest.PollRequest.TaskList, pollRequest.ForwardedFrom)
+	}
 	client, err := c.getHostForRequest(pollRequest.PollRequest.TaskList.GetName())
 	if err != nil {
 		return nil, err
@@ -157,6 +173,75 @@ func (c *clientImpl) DescribeTaskList(ctx context.Context, request *m.DescribeTa
 	return client.DescribeTaskList(ctx, request, opts...)
 }
 
+func (c *clientImpl) UpdateTaskListBuildIDs(ctx context.Context, request *m.UpdateTaskListBuildIDsRequest, opts ...yarpc.CallOption) error {
+	opts = common.AggregateYarpcOptions(ctx, opts...)
+	client, err := c.getHostForRequest(request.UpdateRequest.TaskList.GetName())
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.UpdateTaskListBuildIDs(ctx, request, opts...)
+}
+
+// PauseTaskList pauses every partition of the task list
+func (c *clientImpl) PauseTaskList(ctx context.Context, request *m.PauseTaskListRequest, opts ...yarpc.CallOption) error {
+	opts = common.AggregateYarpcOptions(ctx, opts...)
+	return c.forEachPartition(ctx, request.PauseRequest.TaskList, func(ctx context.Context,
+		client matchingserviceclient.Interface, taskList *workflow.TaskList) error {
+		pauseRequest := *request.PauseRequest
+		pauseRequest.TaskList = taskList
+		return client.PauseTaskList(ctx, &m.PauseTaskListRequest{
+			DomainUUID:   request.DomainUUID,
+			PauseRequest: &pauseRequest,
+		}, opts...)
+	})
+}
+
+// ResumeTaskList resumes every partition of the task list
+func (c *clientImpl) ResumeTaskList(ctx context.Context, request *m.ResumeTaskListRequest, opts ...yarpc.CallOption) error {
+	opts = common.AggregateYarpcOptions(ctx, opts...)
+	return c.forEachPartition(ctx, request.ResumeRequest.TaskList, func(ctx context.Context,
+		client matchingserviceclient.Interface, taskList *workflow.TaskList) error {
+		resumeRequest := *request.ResumeRequest
+		resumeRequest.TaskList = taskList
+		return client.ResumeTaskList(ctx, &m.ResumeTaskListRequest{
+			DomainUUID:    request.DomainUUID,
+			ResumeRequest: &resumeRequest,
+		}, opts...)
+	})
+}
+
+// ListTaskLists reads the task lists of a domain from persistence, so any host can serve it
+func (c *clientImpl) ListTaskLists(ctx context.Context, request *m.ListTaskListsRequest, opts ...yarpc.CallOption) (*workflow.ListTaskListsResponse, error) {
+	opts = common.AggregateYarpcOptions(ctx, opts...)
+	client, err := c.getHostForRequest(request.GetDomainUUID())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.ListTaskLists(ctx, request, opts...)
+}
+
+// forEachPartition calls op with the host of every partition of the task list, it stops at the first error
+func (c *clientImpl) forEachPartition(ctx context.Context, taskList *workflow.TaskList,
+	op func(ctx context.Context, client matchingserviceclient.Interface, taskList *workflow.TaskList) error) error {
+	for _, partition := range c.loadBalancer.allPartitions(taskList) {
+		client, err := c.getHostForRequest(partition.GetName())
+		if err != nil {
+			return err
+		}
+		callCtx, cancel := c.createContext(ctx)
+		err = op(callCtx, client, partition)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *clientImpl) getHostForRequest(key string) (matchingserviceclient.Interface, error) {
 	host, err := c.resolver.Lookup(key)
 	if err != nil {
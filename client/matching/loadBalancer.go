@@ -0,0 +1,100 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"math/rand"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/service/dynamicconfig"
+)
+
+type (
+	// loadBalancer spreads the add and poll requests of a task list across its partitions
+	loadBalancer struct {
+		nWritePartitions dynamicconfig.IntPropertyFn
+		nReadPartitions  dynamicconfig.IntPropertyFn
+	}
+)
+
+func newLoadBalancer(dc *dynamicconfig.Collection) *loadBalancer {
+	return &loadBalancer{
+		nWritePartitions: dc.GetIntProperty(dynamicconfig.MatchingNumTaskListWritePartitions, 1),
+		nReadPartitions:  dc.GetIntProperty(dynamicconfig.MatchingNumTaskListReadPartitions, 1),
+	}
+}
+
+// pickWritePartition returns the task list partition an add request is sent to
+func (lb *loadBalancer) pickWritePartition(taskList *workflow.TaskList, forwardedFrom *string) *workflow.TaskList {
+	return lb.pickPartition(taskList, forwardedFrom, lb.nWritePartitions)
+}
+
+// pickReadPartition returns the task list partition a poll request is sent to
+func (lb *loadBalancer) pickReadPartition(taskList *workflow.TaskList, forwardedFrom *string) *workflow.TaskList {
+	return lb.pickPartition(taskList, forwardedFrom, lb.nReadPartitions)
+}
+
+// allPartitions returns every partition of a task list which tasks are added to or polled from, the root partition
+// first
+func (lb *loadBalancer) allPartitions(taskList *workflow.TaskList) []*workflow.TaskList {
+	partitions := []*workflow.TaskList{taskList}
+	name := taskList.GetName()
+	if taskList.GetKind() == workflow.TaskListKindSticky {
+		return partitions
+	}
+	if _, partition := common.ParseTaskListPartitionName(name); partition != 0 {
+		return partitions
+	}
+	n := lb.nWritePartitions(dynamicconfig.TaskListFilter(name))
+	if nRead := lb.nReadPartitions(dynamicconfig.TaskListFilter(name)); nRead > n {
+		n = nRead
+	}
+	for i := 1; i < n; i++ {
+		partitions = append(partitions, &workflow.TaskList{
+			Name: common.StringPtr(common.TaskListPartitionName(name, i)),
+			Kind: taskList.Kind,
+		})
+	}
+	return partitions
+}
+
+func (lb *loadBalancer) pickPartition(taskList *workflow.TaskList, forwardedFrom *string,
+	nPartitions dynamicconfig.IntPropertyFn) *workflow.TaskList {
+	// requests forwarded by a partition, sticky task lists and requests which already target a partition are
+	// routed as they are
+	if taskList == nil || forwardedFrom != nil && *forwardedFrom != "" ||
+		taskList.GetKind() == workflow.TaskListKindSticky {
+		return taskList
+	}
+	name := taskList.GetName()
+	if _, partition := common.ParseTaskListPartitionName(name); partition != 0 {
+		return taskList
+	}
+	n := nPartitions(dynamicconfig.TaskListFilter(name))
+	if n <= 1 {
+		return taskList
+	}
+	return &workflow.TaskList{
+		Name: common.StringPtr(common.TaskListPartitionName(name, rand.Intn(n))),
+		Kind: taskList.Kind,
+	}
+}
@@ -179,3 +179,71 @@ func (c *metricClient) DescribeTaskList(
 
 	return resp, err
 }
+
+func (c *metricClient) UpdateTaskListBuildIDs(
+	ctx context.Context,
+	request *m.UpdateTaskListBuildIDsRequest,
+	opts ...yarpc.CallOption) error {
+	c.metricsClient.IncCounter(metrics.MatchingClientUpdateTaskListBuildIDsScope, metrics.CadenceRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.MatchingClientUpdateTaskListBuildIDsScope, metrics.CadenceLatency)
+	err := c.client.UpdateTaskListBuildIDs(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.MatchingClientUpdateTaskListBuildIDsScope, metrics.CadenceFailures)
+	}
+
+	return err
+}
+
+func (c *metricClient) PauseTaskList(
+	ctx context.Context,
+	request *m.PauseTaskListRequest,
+	opts ...yarpc.CallOption) error {
+	c.metricsClient.IncCounter(metrics.MatchingClientPauseTaskListScope, metrics.CadenceRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.MatchingClientPauseTaskListScope, metrics.CadenceLatency)
+	err := c.client.PauseTaskList(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.MatchingClientPauseTaskListScope, metrics.CadenceFailures)
+	}
+
+	return err
+}
+
+func (c *metricClient) ResumeTaskList(
+	ctx context.Context,
+	request *m.ResumeTaskListRequest,
+	opts ...yarpc.CallOption) error {
+	c.metricsClient.IncCounter(metrics.MatchingClientResumeTaskListScope, metrics.CadenceRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.MatchingClientResumeTaskListScope, metrics.CadenceLatency)
+	err := c.client.ResumeTaskList(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.MatchingClientResumeTaskListScope, metrics.CadenceFailures)
+	}
+
+	return err
+}
+
+func (c *metricClient) ListTaskLists(
+	ctx context.Context,
+	request *m.ListTaskListsRequest,
+	opts ...yarpc.CallOption) (*workflow.ListTaskListsResponse, error) {
+	c.metricsClient.IncCounter(metrics.MatchingClientListTaskListsScope, metrics.CadenceRequests)
+
+	sw := c.metricsClient.StartTimer(metrics.MatchingClientListTaskListsScope, metrics.CadenceLatency)
+	resp, err := c.client.ListTaskLists(ctx, request, opts...)
+	sw.Stop()
+
+	if err != nil {
+		c.metricsClient.IncCounter(metrics.MatchingClientListTaskListsScope, metrics.CadenceFailures)
+	}
+
+	return resp, err
+}
@@ -25,6 +25,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/service/config"
 	"github.com/uber/cadence/tools/cassandra"
 
@@ -55,13 +56,32 @@ func startHandler(c *cli.Context) {
 	config.Load(env, configDir, zone, &cfg)
 	log.Printf("config=\n%v\n", cfg.String())
 
-	cassCfg := cfg.Cassandra
-	dir, err := os.Getwd()
-	if err != nil {
-		log.Fatal("Unable to get current directory")
+	// the schema version check only applies to cassandra
+	if cfg.SQL == nil && !cfg.InMemory {
+		cassCfg := cfg.Cassandra
+		dir, err := os.Getwd()
+		if err != nil {
+			log.Fatal("Unable to get current directory")
+		}
+		if err := cassandra.VerifyCompatibleVersion(cassCfg, dir); err != nil {
+			log.Fatal("Incompatible versions", err)
+		}
+	}
+
+	// all the services started by this process share the same in-memory store
+	var store *persistence.InMemoryStore
+	if cfg.InMemory {
+		store = persistence.NewInMemoryStore()
 	}
-	if err := cassandra.VerifyCompatibleVersion(cassCfg, dir); err != nil {
-		log.Fatal("Incompatible versions", err)
+
+	// the database file of the BoltDB visibility store is locked by the process, so it is also shared
+	var boltVisibilityStore *persistence.BoltVisibilityStore
+	if cfg.BoltVisibility != nil {
+		var err error
+		boltVisibilityStore, err = persistence.NewBoltVisibilityStore(cfg.BoltVisibility.Path)
+		if err != nil {
+			log.Fatal("Unable to open the visibility store", err)
+		}
 	}
 
 	services := getServices(c)
@@ -75,7 +95,7 @@ LoadServiceLoop:
 				log.Fatalf("`%v` service missing config", svc)
 			}
 		}
-		server := newServer(svc, &cfg)
+		server := newServer(svc, &cfg, store, boltVisibilityStore)
 		server.Start()
 	}
 
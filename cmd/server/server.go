@@ -25,7 +25,10 @@ import (
 	"time"
 
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/archiver"
+	"github.com/uber/cadence/common/authorization"
 	"github.com/uber/cadence/common/cluster"
+	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/service"
 	"github.com/uber/cadence/common/service/config"
 	"github.com/uber/cadence/common/service/dynamicconfig"
@@ -43,6 +46,9 @@ type (
 		cfg    *config.Config
 		doneC  chan struct{}
 		daemon common.Daemon
+		store  *persistence.InMemoryStore
+		// boltVisibilityStore is only set when the BoltDB visibility store is used
+		boltVisibilityStore *persistence.BoltVisibilityStore
 	}
 )
 
@@ -54,12 +60,16 @@ const (
 )
 
 // newServer returns a new instance of a daemon
-// that represents a cadence service
-func newServer(service string, cfg *config.Config) common.Daemon {
+// that represents a cadence service, store is
+// only set when the in-memory persistence is used
+func newServer(service string, cfg *config.Config, store *persistence.InMemoryStore,
+	boltVisibilityStore *persistence.BoltVisibilityStore) common.Daemon {
 	return &server{
-		cfg:   cfg,
-		name:  service,
-		doneC: make(chan struct{}),
+		cfg:                 cfg,
+		name:                service,
+		doneC:               make(chan struct{}),
+		store:               store,
+		boltVisibilityStore: boltVisibilityStore,
 	}
 }
 
@@ -99,6 +109,18 @@ func (s *server) startService() common.Daemon {
 	params.Name = "cadence-" + s.name
 	params.Logger = s.cfg.Log.NewBarkLogger()
 	params.CassandraConfig = s.cfg.Cassandra
+	params.SQLConfig = s.cfg.SQL
+	params.InMemoryStore = s.store
+	params.BoltVisibilityStore = s.boltVisibilityStore
+	if s.cfg.Archival != nil {
+		params.Archiver, err = archiver.NewArchiver(s.cfg.Archival)
+		if err != nil {
+			log.Fatalf("error creating archiver: %v", err)
+		}
+	}
+
+	params.Authorizer = authorization.NewAuthorizer(s.cfg.Authorization)
+	params.IdentityProvider = authorization.NewIdentityProvider(s.cfg.Authorization)
 
 	params.RingpopFactory, err = s.cfg.Ringpop.NewFactory()
 	if err != nil {
@@ -119,7 +141,14 @@ func (s *server) startService() common.Daemon {
 	)
 	// TODO: We need to switch Cadence to use zap logger, until then just pass zap.NewNop
 	params.MessagingClient = s.cfg.Kafka.NewKafkaClient(zap.NewNop(), params.Logger, params.MetricScope)
-	params.DynamicConfig = dynamicconfig.NewNopClient()
+	if s.cfg.DynamicConfigClient != nil {
+		params.DynamicConfig, err = dynamicconfig.NewFileBasedClient(s.cfg.DynamicConfigClient, params.Logger, s.doneC)
+		if err != nil {
+			log.Fatalf("error creating dynamic config client: %v", err)
+		}
+	} else {
+		params.DynamicConfig = dynamicconfig.NewNopClient()
+	}
 
 	var daemon common.Daemon
 
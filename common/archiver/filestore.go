@@ -0,0 +1,122 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package archiver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+)
+
+const (
+	filestoreBlobExtension = ".history"
+	filestoreDirMode       = 0700
+)
+
+type (
+	// filestoreArchiver stores the blobs as files of a local directory, each bucket being a subdirectory
+	filestoreArchiver struct {
+		storeDirectory string
+	}
+)
+
+// NewFilestoreArchiver returns an Archiver storing the blobs in the given directory of the local file system, the
+// directory is created if it does not exist
+func NewFilestoreArchiver(storeDirectory string) (Archiver, error) {
+	if err := os.MkdirAll(storeDirectory, filestoreDirMode); err != nil {
+		return nil, err
+	}
+	return &filestoreArchiver{storeDirectory: storeDirectory}, nil
+}
+
+func (a *filestoreArchiver) PutHistoryBlob(request *PutHistoryBlobRequest) error {
+	data, err := json.Marshal(request.Blob)
+	if err != nil {
+		return newFilestoreError("PutHistoryBlob", err)
+	}
+
+	dir := a.getExecutionDirectory(request.Bucket, request.DomainID, request.WorkflowID, request.RunID)
+	if err := os.MkdirAll(dir, filestoreDirMode); err != nil {
+		return newFilestoreError("PutHistoryBlob", err)
+	}
+
+	// The blob is written to a temporary file first, so readers never see a partially written blob
+	file, err := ioutil.TempFile(dir, "tmp")
+	if err != nil {
+		return newFilestoreError("PutHistoryBlob", err)
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), filepath.Join(dir, getFilestoreBlobName(request.Page)))
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return newFilestoreError("PutHistoryBlob", err)
+	}
+	return nil
+}
+
+func (a *filestoreArchiver) GetHistoryBlob(request *GetHistoryBlobRequest) (*HistoryBlob, error) {
+	dir := a.getExecutionDirectory(request.Bucket, request.DomainID, request.WorkflowID, request.RunID)
+	data, err := ioutil.ReadFile(filepath.Join(dir, getFilestoreBlobName(request.Page)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, &workflow.EntityNotExistsError{
+				Message: fmt.Sprintf("Archived history not found.  WorkflowId: %v, RunId: %v, Page: %v",
+					request.WorkflowID, request.RunID, request.Page),
+			}
+		}
+		return nil, newFilestoreError("GetHistoryBlob", err)
+	}
+
+	blob := &HistoryBlob{}
+	if err := json.Unmarshal(data, blob); err != nil {
+		return nil, newFilestoreError("GetHistoryBlob", err)
+	}
+	return blob, nil
+}
+
+// getExecutionDirectory returns the directory of the blobs of an execution.  Bucket names are validated by the
+// frontend and the domain and run IDs are UUIDs, while workflow IDs are arbitrary strings so they are hashed.
+func (a *filestoreArchiver) getExecutionDirectory(bucket, domainID, workflowID, runID string) string {
+	workflowIDHash := sha256.Sum256([]byte(workflowID))
+	return filepath.Join(a.storeDirectory, bucket, domainID, hex.EncodeToString(workflowIDHash[:]), runID)
+}
+
+func getFilestoreBlobName(page int) string {
+	return strconv.Itoa(page) + filestoreBlobExtension
+}
+
+func newFilestoreError(operation string, err error) error {
+	return &workflow.InternalServiceError{
+		Message: fmt.Sprintf("%v operation failed. Error: %v", operation, err),
+	}
+}
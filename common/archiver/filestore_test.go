@@ -0,0 +1,114 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package archiver
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/persistence"
+)
+
+type (
+	filestoreSuite struct {
+		suite.Suite
+		*require.Assertions
+		storeDirectory string
+		archiver       Archiver
+	}
+)
+
+func TestFilestoreSuite(t *testing.T) {
+	suite.Run(t, new(filestoreSuite))
+}
+
+func (s *filestoreSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	var err error
+	s.storeDirectory, err = ioutil.TempDir("", "cadence-archival")
+	s.Nil(err)
+	s.archiver, err = NewFilestoreArchiver(s.storeDirectory)
+	s.Nil(err)
+}
+
+func (s *filestoreSuite) TearDownTest() {
+	os.RemoveAll(s.storeDirectory)
+}
+
+func (s *filestoreSuite) TestPutAndGetHistoryBlob() {
+	request := &GetHistoryBlobRequest{
+		Bucket:     "test-bucket",
+		DomainID:   "3bd0d4a4-9c1e-4f8b-a8b1-2d5d2f1e4c6a",
+		WorkflowID: "../workflow/id",
+		RunID:      "7a6c2b8e-3b35-4f5e-9d89-6f1f0e8f4c2d",
+		Page:       0,
+	}
+	_, err := s.archiver.GetHistoryBlob(request)
+	s.IsType(&workflow.EntityNotExistsError{}, err)
+
+	blob := &HistoryBlob{
+		Events: persistence.NewSerializedHistoryEventBatch([]byte("first"), common.EncodingTypeThriftRWGzip, 1),
+	}
+	s.Nil(s.archiver.PutHistoryBlob(&PutHistoryBlobRequest{
+		Bucket:     request.Bucket,
+		DomainID:   request.DomainID,
+		WorkflowID: request.WorkflowID,
+		RunID:      request.RunID,
+		Page:       request.Page,
+		Blob:       blob,
+	}))
+	result, err := s.archiver.GetHistoryBlob(request)
+	s.Nil(err)
+	s.Equal(blob, result)
+
+	// a page is replaced when it is uploaded again
+	blob = &HistoryBlob{
+		Events: persistence.NewSerializedHistoryEventBatch([]byte("second"), common.EncodingTypeThriftRWGzip, 1),
+		IsLast: true,
+	}
+	s.Nil(s.archiver.PutHistoryBlob(&PutHistoryBlobRequest{
+		Bucket:     request.Bucket,
+		DomainID:   request.DomainID,
+		WorkflowID: request.WorkflowID,
+		RunID:      request.RunID,
+		Page:       request.Page,
+		Blob:       blob,
+	}))
+	result, err = s.archiver.GetHistoryBlob(request)
+	s.Nil(err)
+	s.Equal(blob, result)
+
+	// the pages of other executions, pages and buckets are distinct
+	for _, other := range []*GetHistoryBlobRequest{
+		{Bucket: "other-bucket", DomainID: request.DomainID, WorkflowID: request.WorkflowID, RunID: request.RunID},
+		{Bucket: request.Bucket, DomainID: request.DomainID, WorkflowID: "other", RunID: request.RunID},
+		{Bucket: request.Bucket, DomainID: request.DomainID, WorkflowID: request.WorkflowID, RunID: request.RunID,
+			Page: 1},
+	} {
+		_, err = s.archiver.GetHistoryBlob(other)
+		s.IsType(&workflow.EntityNotExistsError{}, err)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package archiver
+
+import (
+	"github.com/uber/cadence/common/persistence"
+)
+
+type (
+	// Archiver stores the histories of the closed workflow executions in a blob store, so they outlive the
+	// retention of their domain.  A history is stored as a sequence of pages, numbered from 0, each page being a
+	// blob.  The blobs are grouped in buckets, every domain with archival enabled has its own bucket.
+	Archiver interface {
+		// PutHistoryBlob uploads a page of the history of an execution, replacing the page if it already exists
+		PutHistoryBlob(request *PutHistoryBlobRequest) error
+		// GetHistoryBlob downloads a page of the history of an execution, an EntityNotExistsError is returned
+		// when the page does not exist
+		GetHistoryBlob(request *GetHistoryBlobRequest) (*HistoryBlob, error)
+	}
+
+	// HistoryBlob is a page of the history of an execution
+	HistoryBlob struct {
+		// Events are the serialized events of the page, the encoding type of the events usually compresses them
+		Events *persistence.SerializedHistoryEventBatch
+		// IsLast is true on the last page of the history
+		IsLast bool
+	}
+
+	// PutHistoryBlobRequest is used to upload a page of the history of an execution
+	PutHistoryBlobRequest struct {
+		Bucket     string
+		DomainID   string
+		WorkflowID string
+		RunID      string
+		Page       int
+		Blob       *HistoryBlob
+	}
+
+	// GetHistoryBlobRequest is used to download a page of the history of an execution
+	GetHistoryBlobRequest struct {
+		Bucket     string
+		DomainID   string
+		WorkflowID string
+		RunID      string
+		Page       int
+	}
+)
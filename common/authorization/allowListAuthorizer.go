@@ -0,0 +1,82 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common/service/config"
+)
+
+// AnyDomain is the domain name of the allow lists which apply to the domains without allow lists of their own
+const AnyDomain = "*"
+
+type (
+	// allowListAuthorizer allows a call on a domain if its caller is listed with the required permission in the
+	// allow lists of the domain, it keeps the highest permission of every identity of a domain
+	allowListAuthorizer struct {
+		domains map[string]map[string]Permission
+	}
+)
+
+// NewAllowListAuthorizer returns an Authorizer checking the callers against the per domain allow lists of the
+// config. A caller with the admin permission on a domain also has the write permission, which implies the read
+// permission. The calls on a domain without allow lists are allowed.
+func NewAllowListAuthorizer(cfg *config.Authorization) Authorizer {
+	domains := make(map[string]map[string]Permission, len(cfg.Domains))
+	for domain, allowList := range cfg.Domains {
+		identities := make(map[string]Permission)
+		grant := func(ids []string, permission Permission) {
+			for _, id := range ids {
+				if id == "" {
+					// the unauthenticated callers are never allowed
+					continue
+				}
+				if current, ok := identities[id]; !ok || current < permission {
+					identities[id] = permission
+				}
+			}
+		}
+		grant(allowList.Read, PermissionRead)
+		grant(allowList.Write, PermissionWrite)
+		grant(allowList.Admin, PermissionAdmin)
+		domains[domain] = identities
+	}
+	return &allowListAuthorizer{domains: domains}
+}
+
+func (a *allowListAuthorizer) Authorize(ctx context.Context, attributes *Attributes) error {
+	identities, ok := a.domains[attributes.DomainName]
+	if !ok {
+		if identities, ok = a.domains[AnyDomain]; !ok {
+			return nil
+		}
+	}
+	if permission, ok := identities[attributes.Identity]; ok && permission >= attributes.Permission {
+		return nil
+	}
+	return &workflow.AccessDeniedError{
+		Message: fmt.Sprintf("Caller %q does not have the %v permission on domain %q required by %v.",
+			attributes.Identity, attributes.Permission, attributes.DomainName, attributes.APIName),
+	}
+}
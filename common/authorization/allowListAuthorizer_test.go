@@ -0,0 +1,107 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common/service/config"
+)
+
+type (
+	allowListAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+		authorizer Authorizer
+	}
+)
+
+func TestAllowListAuthorizerSuite(t *testing.T) {
+	suite.Run(t, new(allowListAuthorizerSuite))
+}
+
+func (s *allowListAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.authorizer = NewAuthorizer(&config.Authorization{
+		Domains: map[string]config.DomainAllowList{
+			"orders": {
+				Read:  []string{"reader"},
+				Write: []string{"writer"},
+				Admin: []string{"admin"},
+			},
+			AnyDomain: {
+				Admin: []string{"operator"},
+			},
+		},
+	})
+}
+
+func (s *allowListAuthorizerSuite) authorize(identity, domain string, permission Permission) error {
+	return s.authorizer.Authorize(context.Background(), &Attributes{
+		APIName:    "TestAPI",
+		DomainName: domain,
+		Identity:   identity,
+		Permission: permission,
+	})
+}
+
+func (s *allowListAuthorizerSuite) TestPermissionsOfListedDomain() {
+	s.NoError(s.authorize("reader", "orders", PermissionRead))
+	s.IsType(&workflow.AccessDeniedError{}, s.authorize("reader", "orders", PermissionWrite))
+	s.IsType(&workflow.AccessDeniedError{}, s.authorize("reader", "orders", PermissionAdmin))
+
+	s.NoError(s.authorize("writer", "orders", PermissionRead))
+	s.NoError(s.authorize("writer", "orders", PermissionWrite))
+	s.IsType(&workflow.AccessDeniedError{}, s.authorize("writer", "orders", PermissionAdmin))
+
+	s.NoError(s.authorize("admin", "orders", PermissionRead))
+	s.NoError(s.authorize("admin", "orders", PermissionWrite))
+	s.NoError(s.authorize("admin", "orders", PermissionAdmin))
+
+	// the allow lists of a listed domain replace the allow lists of AnyDomain
+	s.IsType(&workflow.AccessDeniedError{}, s.authorize("operator", "orders", PermissionRead))
+	s.IsType(&workflow.AccessDeniedError{}, s.authorize("", "orders", PermissionRead))
+}
+
+func (s *allowListAuthorizerSuite) TestPermissionsOfOtherDomains() {
+	s.NoError(s.authorize("operator", "payments", PermissionAdmin))
+	s.IsType(&workflow.AccessDeniedError{}, s.authorize("admin", "payments", PermissionRead))
+}
+
+func (s *allowListAuthorizerSuite) TestNoAllowLists() {
+	authorizer := NewAuthorizer(&config.Authorization{
+		Domains: map[string]config.DomainAllowList{"orders": {Read: []string{"reader"}}},
+	})
+	s.NoError(authorizer.Authorize(context.Background(), &Attributes{
+		DomainName: "payments",
+		Permission: PermissionAdmin,
+	}))
+
+	s.NoError(NewAuthorizer(nil).Authorize(context.Background(), &Attributes{
+		DomainName: "orders",
+		Permission: PermissionAdmin,
+	}))
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+
+	"github.com/uber/cadence/common/service/config"
+)
+
+type (
+	// nopAuthorizer allows every call
+	nopAuthorizer struct{}
+)
+
+// NewAuthorizer builds the authorizer specified in the authorization config, every call is allowed when the
+// config is nil
+func NewAuthorizer(cfg *config.Authorization) Authorizer {
+	if cfg == nil {
+		return NewNopAuthorizer()
+	}
+	return NewAllowListAuthorizer(cfg)
+}
+
+// NewNopAuthorizer returns an Authorizer which allows every call
+func NewNopAuthorizer() Authorizer {
+	return &nopAuthorizer{}
+}
+
+func (a *nopAuthorizer) Authorize(ctx context.Context, attributes *Attributes) error {
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/service/config"
+	"go.uber.org/yarpc"
+)
+
+type (
+	// nopIdentityProvider authenticates no caller
+	nopIdentityProvider struct{}
+
+	// tokenIdentityProvider authenticates the callers with the tokens signed by the shared signing key, which
+	// are sent in the authorization token header of the calls
+	tokenIdentityProvider struct {
+		signingKey []byte
+		timeSource common.TimeSource
+	}
+)
+
+var errInvalidToken = &workflow.AccessDeniedError{Message: "Invalid authorization token."}
+var errExpiredToken = &workflow.AccessDeniedError{Message: "Authorization token is expired."}
+
+// NewIdentityProvider builds the identity provider specified in the authorization config, no caller is
+// authenticated when the config is nil or has no signing key
+func NewIdentityProvider(cfg *config.Authorization) IdentityProvider {
+	if cfg == nil || cfg.SigningKey == "" {
+		return NewNopIdentityProvider()
+	}
+	return NewTokenIdentityProvider([]byte(cfg.SigningKey), common.NewRealTimeSource())
+}
+
+// NewNopIdentityProvider returns an IdentityProvider which authenticates no caller
+func NewNopIdentityProvider() IdentityProvider {
+	return &nopIdentityProvider{}
+}
+
+// NewTokenIdentityProvider returns an IdentityProvider verifying the tokens created by NewToken with the same
+// signing key
+func NewTokenIdentityProvider(signingKey []byte, timeSource common.TimeSource) IdentityProvider {
+	return &tokenIdentityProvider{
+		signingKey: signingKey,
+		timeSource: timeSource,
+	}
+}
+
+// NewToken creates a token authenticating the identity until its expiry, the token is sent in the
+// authorization token header of the calls
+func NewToken(signingKey []byte, identity string, expiry time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(identity)) + "." + strconv.FormatInt(expiry.Unix(), 10)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(sign(signingKey, payload))
+}
+
+func (p *nopIdentityProvider) GetIdentity(ctx context.Context) (string, error) {
+	return "", nil
+}
+
+func (p *tokenIdentityProvider) GetIdentity(ctx context.Context) (string, error) {
+	call := yarpc.CallFromContext(ctx)
+	if call == nil {
+		return "", nil
+	}
+	token := call.Header(common.AuthorizationTokenHeaderName)
+	if token == "" {
+		return "", nil
+	}
+	return p.verify(token)
+}
+
+func (p *tokenIdentityProvider) verify(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, sign(p.signingKey, parts[0]+"."+parts[1])) {
+		return "", errInvalidToken
+	}
+	identity, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || len(identity) == 0 {
+		return "", errInvalidToken
+	}
+	expiry, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", errInvalidToken
+	}
+	if !p.timeSource.Now().Before(time.Unix(expiry, 0)) {
+		return "", errExpiredToken
+	}
+	return string(identity), nil
+}
+
+func sign(signingKey []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
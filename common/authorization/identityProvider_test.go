@@ -0,0 +1,136 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/yarpc/api/encoding"
+	"go.uber.org/yarpc/api/transport"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/service/config"
+)
+
+type (
+	identityProviderSuite struct {
+		suite.Suite
+		*require.Assertions
+		timeSource *mockTimeSource
+		provider   IdentityProvider
+	}
+
+	mockTimeSource struct {
+		currTime time.Time
+	}
+)
+
+var testSigningKey = []byte("test-signing-key")
+
+func TestIdentityProviderSuite(t *testing.T) {
+	suite.Run(t, new(identityProviderSuite))
+}
+
+func (s *identityProviderSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.timeSource = &mockTimeSource{currTime: time.Unix(1550000000, 0)}
+	s.provider = NewTokenIdentityProvider(testSigningKey, s.timeSource)
+}
+
+func (ts *mockTimeSource) Now() time.Time {
+	return ts.currTime
+}
+
+func (s *identityProviderSuite) callContext(headers map[string]string) context.Context {
+	ctx, call := encoding.NewInboundCall(context.Background())
+	s.NoError(call.ReadFromRequest(&transport.Request{Headers: transport.HeadersFromMap(headers)}))
+	return ctx
+}
+
+func (s *identityProviderSuite) getIdentity(token string) (string, error) {
+	return s.provider.GetIdentity(s.callContext(map[string]string{common.AuthorizationTokenHeaderName: token}))
+}
+
+func (s *identityProviderSuite) TestValidToken() {
+	identity, err := s.getIdentity(NewToken(testSigningKey, "worker", s.timeSource.currTime.Add(time.Hour)))
+	s.NoError(err)
+	s.Equal("worker", identity)
+}
+
+func (s *identityProviderSuite) TestNoToken() {
+	identity, err := s.provider.GetIdentity(s.callContext(nil))
+	s.NoError(err)
+	s.Empty(identity)
+
+	identity, err = s.provider.GetIdentity(context.Background())
+	s.NoError(err)
+	s.Empty(identity)
+}
+
+func (s *identityProviderSuite) TestExpiredToken() {
+	_, err := s.getIdentity(NewToken(testSigningKey, "worker", s.timeSource.currTime))
+	s.Equal(errExpiredToken, err)
+}
+
+func (s *identityProviderSuite) TestInvalidToken() {
+	_, err := s.getIdentity(NewToken([]byte("other-key"), "worker", s.timeSource.currTime.Add(time.Hour)))
+	s.IsType(&workflow.AccessDeniedError{}, err)
+
+	// the identity cannot be changed without the signing key
+	token := NewToken(testSigningKey, "worker", s.timeSource.currTime.Add(time.Hour))
+	forged := NewToken(testSigningKey, "admin", s.timeSource.currTime.Add(time.Hour))
+	_, err = s.getIdentity(forged[:len(forged)-43] + token[len(token)-43:])
+	s.Equal(errInvalidToken, err)
+
+	_, err = s.getIdentity(NewToken(testSigningKey, "", s.timeSource.currTime.Add(time.Hour)))
+	s.Equal(errInvalidToken, err)
+
+	_, err = s.getIdentity("worker")
+	s.Equal(errInvalidToken, err)
+}
+
+func (s *identityProviderSuite) TestCallerIdentityIsNotTrusted() {
+	// neither the name of the calling service nor any header but the signed token authenticates the caller
+	ctx := s.callContext(map[string]string{"cadence-caller-identity": "admin"})
+	identity, err := s.provider.GetIdentity(ctx)
+	s.NoError(err)
+	s.Empty(identity)
+}
+
+func (s *identityProviderSuite) TestNewIdentityProvider() {
+	identity, err := NewIdentityProvider(nil).GetIdentity(s.callContext(map[string]string{
+		common.AuthorizationTokenHeaderName: NewToken(testSigningKey, "worker", time.Now().Add(time.Hour)),
+	}))
+	s.NoError(err)
+	s.Empty(identity)
+
+	identity, err = NewIdentityProvider(&config.Authorization{SigningKey: string(testSigningKey)}).GetIdentity(
+		s.callContext(map[string]string{
+			common.AuthorizationTokenHeaderName: NewToken(testSigningKey, "worker", time.Now().Add(time.Hour)),
+		}))
+	s.NoError(err)
+	s.Equal("worker", identity)
+}
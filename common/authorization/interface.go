@@ -0,0 +1,81 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+)
+
+// Permission is the access to a domain which is required to call an API
+type Permission int
+
+const (
+	// PermissionRead is required by the APIs which only read the state of a domain
+	PermissionRead Permission = iota
+	// PermissionWrite is required by the APIs which change the workflows of a domain
+	PermissionWrite
+	// PermissionAdmin is required by the APIs which change a domain or the administration of its task lists
+	PermissionAdmin
+)
+
+type (
+	// Attributes describes an API call which is authorized
+	Attributes struct {
+		// APIName is the name of the frontend API which is called
+		APIName string
+		// DomainName is the domain the API is called on
+		DomainName string
+		// Identity is the identity of the caller verified by the IdentityProvider, it is empty when the caller
+		// is not authenticated
+		Identity string
+		// Permission is the access to the domain which is required to call the API
+		Permission Permission
+	}
+
+	// Authorizer decides if an API call received by the frontend is allowed, it is called before the call is
+	// handled
+	Authorizer interface {
+		// Authorize returns nil if the call is allowed and an AccessDeniedError if it is denied, the call is
+		// failed with any other error returned
+		Authorize(ctx context.Context, attributes *Attributes) error
+	}
+
+	// IdentityProvider authenticates the caller of an API, the identity it returns is the only identity which
+	// is authorized
+	IdentityProvider interface {
+		// GetIdentity returns the verified identity of the caller, or an empty identity when the call carries no
+		// credentials. An AccessDeniedError is returned when the credentials of the call cannot be verified.
+		GetIdentity(ctx context.Context) (string, error)
+	}
+)
+
+// String returns the name of the permission
+func (p Permission) String() string {
+	switch p {
+	case PermissionRead:
+		return "read"
+	case PermissionWrite:
+		return "write"
+	case PermissionAdmin:
+		return "admin"
+	}
+	return "unknown"
+}
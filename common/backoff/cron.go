@@ -0,0 +1,52 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backoff
+
+import (
+	"math"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// NoBackoff is used to represent backoff when no cron backoff is needed
+const NoBackoff = time.Duration(-1)
+
+// ValidateSchedule validates a cron schedule spec
+func ValidateSchedule(cronSchedule string) error {
+	_, err := cron.ParseStandard(cronSchedule)
+	return err
+}
+
+// GetBackoffForNextSchedule calculates the backoff time for the next run given a cronSchedule and current time.
+// It returns NoBackoff if the cron schedule is invalid.
+func GetBackoffForNextSchedule(cronSchedule string, nowTime time.Time) time.Duration {
+	schedule, err := cron.ParseStandard(cronSchedule)
+	if err != nil {
+		return NoBackoff
+	}
+
+	nowTime = nowTime.In(time.UTC)
+	nextScheduleTime := schedule.Next(nowTime)
+	// round up to seconds as backoff is recorded in seconds on history events, this also guarantees a backoff of at
+	// least one second
+	return time.Duration(math.Ceil(nextScheduleTime.Sub(nowTime).Seconds())) * time.Second
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	CronSuite struct {
+		*require.Assertions
+		suite.Suite
+	}
+)
+
+func TestCronSuite(t *testing.T) {
+	suite.Run(t, new(CronSuite))
+}
+
+func (s *CronSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *CronSuite) TestValidateSchedule() {
+	s.NoError(ValidateSchedule("*/5 * * * *"))
+	s.NoError(ValidateSchedule("0 10 * * MON-FRI"))
+	s.Error(ValidateSchedule("invalid-cron-spec"))
+	s.Error(ValidateSchedule("* * * * * * *"))
+}
+
+func (s *CronSuite) TestGetBackoffForNextSchedule() {
+	now, _ := time.Parse(time.RFC3339, "2018-12-17T08:00:00+00:00")
+	s.Equal(time.Hour, GetBackoffForNextSchedule("0 9 * * *", now))
+	s.Equal(5*time.Minute, GetBackoffForNextSchedule("*/5 * * * *", now))
+	s.Equal(2*time.Minute, GetBackoffForNextSchedule("*/5 * * * *", now.Add(3*time.Minute)))
+	s.Equal(24*time.Hour, GetBackoffForNextSchedule("0 8 * * *", now))
+	s.Equal(NoBackoff, GetBackoffForNextSchedule("invalid-cron-spec", now))
+}
+
+func (s *CronSuite) TestGetBackoffForNextSchedule_RoundUpToSeconds() {
+	now, _ := time.Parse(time.RFC3339Nano, "2018-12-17T08:00:10.300000000+00:00")
+	s.Equal(50*time.Second, GetBackoffForNextSchedule("* * * * *", now))
+
+	now, _ = time.Parse(time.RFC3339Nano, "2018-12-17T08:00:59.800000000+00:00")
+	s.Equal(time.Second, GetBackoffForNextSchedule("* * * * *", now))
+}
@@ -66,6 +66,11 @@ type Options struct {
 	// RemovedFunc is an optional function called when an element
 	// is scheduled for deletion
 	RemovedFunc RemovedFunc
+
+	// MaxSizeFn is an optional function returning the max size of the cache,
+	// it overrides the max size given at creation and is called on every insertion
+	// so the cache follows the changes of its value
+	MaxSizeFn func() int
 }
 
 // RemovedFunc is a type for notifying applications when an item is
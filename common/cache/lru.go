@@ -35,13 +35,14 @@ var (
 // lru is a concurrent fixed size cache that evicts elements in lru order
 type (
 	lru struct {
-		mut      sync.Mutex
-		byAccess *list.List
-		byKey    map[interface{}]*list.Element
-		maxSize  int
-		ttl      time.Duration
-		pin      bool
-		rmFunc   RemovedFunc
+		mut       sync.Mutex
+		byAccess  *list.List
+		byKey     map[interface{}]*list.Element
+		maxSize   int
+		maxSizeFn func() int
+		ttl       time.Duration
+		pin       bool
+		rmFunc    RemovedFunc
 	}
 
 	iteratorImpl struct {
@@ -132,12 +133,13 @@ func New(maxSize int, opts *Options) Cache {
 	}
 
 	return &lru{
-		byAccess: list.New(),
-		byKey:    make(map[interface{}]*list.Element, opts.InitialCapacity),
-		ttl:      opts.TTL,
-		maxSize:  maxSize,
-		pin:      opts.Pin,
-		rmFunc:   opts.RemovedFunc,
+		byAccess:  list.New(),
+		byKey:     make(map[interface{}]*list.Element, opts.InitialCapacity),
+		ttl:       opts.TTL,
+		maxSize:   maxSize,
+		maxSizeFn: opts.MaxSizeFn,
+		pin:       opts.Pin,
+		rmFunc:    opts.RemovedFunc,
 	}
 }
 
@@ -277,7 +279,8 @@ func (c *lru) putInternal(key interface{}, value interface{}, allowUpdate bool)
 	}
 
 	c.byKey[key] = c.byAccess.PushFront(entry)
-	if len(c.byKey) == c.maxSize {
+	// the max size can shrink between insertions, so keep evicting until the cache fits
+	for maxSize := c.getMaxSize(); len(c.byKey) > 0 && len(c.byKey) >= maxSize; {
 		oldest := c.byAccess.Back().Value.(*entryImpl)
 
 		if oldest.refCount > 0 {
@@ -293,6 +296,13 @@ func (c *lru) putInternal(key interface{}, value interface{}, allowUpdate bool)
 	return nil, nil
 }
 
+func (c *lru) getMaxSize() int {
+	if c.maxSizeFn != nil {
+		return c.maxSizeFn()
+	}
+	return c.maxSize
+}
+
 func (c *lru) deleteInternal(element *list.Element) {
 	entry := c.byAccess.Remove(element).(*entryImpl)
 	if c.rmFunc != nil {
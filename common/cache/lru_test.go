@@ -99,6 +99,28 @@ func TestLRUWithTTL(t *testing.T) {
 	assert.Equal(t, 0, cache.Size())
 }
 
+func TestLRUWithMaxSizeFn(t *testing.T) {
+	maxSize := 5
+	cache := New(0, &Options{
+		MaxSizeFn: func() int { return maxSize },
+	})
+	for _, key := range []string{"A", "B", "C", "D"} {
+		cache.Put(key, key)
+	}
+	assert.Equal(t, 4, cache.Size())
+
+	maxSize = 3
+	cache.Put("E", "E")
+	assert.Equal(t, 2, cache.Size())
+	assert.Equal(t, "D", cache.Get("D"))
+	assert.Equal(t, "E", cache.Get("E"))
+
+	maxSize = 5
+	cache.Put("F", "F")
+	cache.Put("G", "G")
+	assert.Equal(t, 4, cache.Size())
+}
+
 func TestLRUCacheConcurrentAccess(t *testing.T) {
 	cache := NewLRU(5)
 	values := map[string]string{
@@ -21,6 +21,7 @@
 package client
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -74,6 +75,21 @@ func (feature *FeatureImpl) SupportStickyQuery() bool {
 	return feature.featureVersion.major > 0
 }
 
+// LibraryVersion returns the library version of the client in the MAJOR.MINOR.PATCH format,
+// a version which can not be parsed is returned as 0.0.0
+func (feature *FeatureImpl) LibraryVersion() string {
+	return feature.libVersion.String()
+}
+
+// ClientImpl returns the name of the client implementation
+func (feature *FeatureImpl) ClientImpl() string {
+	return feature.lang
+}
+
+func (v version) String() string {
+	return fmt.Sprintf("%v.%v.%v", v.major, v.minor, v.patch)
+}
+
 func parseVersion(versionStr string) version {
 	var major int64
 	var minor int64
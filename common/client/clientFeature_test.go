@@ -55,3 +55,13 @@ func (s *FeatureSuite) TestSupportStickyQuery() {
 	feature = NewFeatureImpl(libVersion, featureVersion, lang)
 	s.False(feature.SupportStickyQuery(), "Should not support sticky query")
 }
+
+func (s *FeatureSuite) TestLibraryVersion() {
+	feature := NewFeatureImpl("0.5.1", "1.0.0", "go")
+	s.Equal("0.5.1", feature.LibraryVersion())
+	s.Equal("go", feature.ClientImpl())
+
+	feature = NewFeatureImpl("0.5.x", "1.0.0", "")
+	s.Equal("0.0.0", feature.LibraryVersion())
+	s.Equal("", feature.ClientImpl())
+}
@@ -46,6 +46,7 @@ const (
 	TagConsumerName         = "consumer-name"
 	TagPartition            = "partition"
 	TagOffset               = "offset"
+	TagSourceCluster        = "source-cluster"
 
 	// workflow logging tag values
 	// TagWorkflowComponent Values
@@ -55,6 +56,8 @@ const (
 	TagValueTransferQueueComponent            = "transfer-queue-processor"
 	TagValueTimerQueueComponent               = "timer-queue-processor"
 	TagValueReplicatorQueueComponent          = "replicator-queue-processor"
+	TagValueHistoryReplicatorComponent        = "history-replicator"
+	TagValueWorkflowResetorComponent          = "workflow-resetor"
 	TagValueShardController                   = "shard-controller"
 	TagValueMatchingEngineComponent           = "matching-engine"
 	TagValueReplicatorComponent               = "replicator"
@@ -67,6 +67,8 @@ const (
 	OperationTagName = "operation"
 	// ShardTagName is temporary until we can get all metric data removed for the service
 	ShardTagName = "shard"
+	// DomainTagName is the tag of the metrics emitted per domain
+	DomainTagName = "domain"
 )
 
 // This package should hold all the metrics and tags for cadence
@@ -128,6 +130,8 @@ const (
 	PersistenceDeleteWorkflowExecutionScope
 	// PersistenceGetCurrentExecutionScope tracks GetCurrentExecution calls made by service to persistence layer
 	PersistenceGetCurrentExecutionScope
+	// PersistenceListConcreteExecutionsScope tracks ListConcreteExecutions calls made by service to persistence layer
+	PersistenceListConcreteExecutionsScope
 	// PersistenceGetTransferTasksScope tracks GetTransferTasks calls made by service to persistence layer
 	PersistenceGetTransferTasksScope
 	// PersistenceGetReplicationTasksScope tracks GetReplicationTasks calls made by service to persistence layer
@@ -150,6 +154,8 @@ const (
 	PersistenceLeaseTaskListScope
 	// PersistenceUpdateTaskListScope tracks PersistenceUpdateTaskListScope calls made by service to persistence layer
 	PersistenceUpdateTaskListScope
+	// PersistenceListTaskListScope tracks ListTaskList calls made by service to persistence layer
+	PersistenceListTaskListScope
 	// PersistenceAppendHistoryEventsScope tracks AppendHistoryEvents calls made by service to persistence layer
 	PersistenceAppendHistoryEventsScope
 	// PersistenceGetWorkflowExecutionHistoryScope tracks GetWorkflowExecutionHistory calls made by service to persistence layer
@@ -182,8 +188,18 @@ const (
 	PersistenceListOpenWorkflowExecutionsByWorkflowIDScope
 	// PersistenceListClosedWorkflowExecutionsByWorkflowIDScope tracks ListClosedWorkflowExecutionsByWorkflowID calls made by service to persistence layer
 	PersistenceListClosedWorkflowExecutionsByWorkflowIDScope
+	// PersistenceUpsertWorkflowExecutionScope tracks UpsertWorkflowExecution calls made by service to persistence layer
+	PersistenceUpsertWorkflowExecutionScope
+	// PersistenceListOpenWorkflowExecutionsBySearchAttributesScope tracks ListOpenWorkflowExecutionsBySearchAttributes calls made by service to persistence layer
+	PersistenceListOpenWorkflowExecutionsBySearchAttributesScope
+	// PersistenceListClosedWorkflowExecutionsBySearchAttributesScope tracks ListClosedWorkflowExecutionsBySearchAttributes calls made by service to persistence layer
+	PersistenceListClosedWorkflowExecutionsBySearchAttributesScope
 	// PersistenceListClosedWorkflowExecutionsByStatusScope tracks ListClosedWorkflowExecutionsByStatus calls made by service to persistence layer
 	PersistenceListClosedWorkflowExecutionsByStatusScope
+	// PersistenceListWorkflowExecutionsScope tracks ListWorkflowExecutions calls made by service to persistence layer
+	PersistenceListWorkflowExecutionsScope
+	// PersistenceCountWorkflowExecutionsScope tracks CountWorkflowExecutions calls made by service to persistence layer
+	PersistenceCountWorkflowExecutionsScope
 	// PersistenceGetClosedWorkflowExecutionScope tracks GetClosedWorkflowExecution calls made by service to persistence layer
 	PersistenceGetClosedWorkflowExecutionScope
 	// HistoryClientStartWorkflowExecutionScope tracks RPC calls to history service
@@ -214,14 +230,20 @@ const (
 	HistoryClientRequestCancelWorkflowExecutionScope
 	// HistoryClientSignalWorkflowExecutionScope tracks RPC calls to history service
 	HistoryClientSignalWorkflowExecutionScope
+	// HistoryClientSignalWithStartWorkflowExecutionScope tracks RPC calls to history service
+	HistoryClientSignalWithStartWorkflowExecutionScope
 	// HistoryClientRemoveSignalMutableStateScope tracks RPC calls to history service
 	HistoryClientRemoveSignalMutableStateScope
 	// HistoryClientTerminateWorkflowExecutionScope tracks RPC calls to history service
 	HistoryClientTerminateWorkflowExecutionScope
+	// HistoryClientResetWorkflowExecutionScope tracks RPC calls to history service
+	HistoryClientResetWorkflowExecutionScope
 	// HistoryClientScheduleDecisionTaskScope tracks RPC calls to history service
 	HistoryClientScheduleDecisionTaskScope
 	// HistoryClientRecordChildExecutionCompletedScope tracks RPC calls to history service
 	HistoryClientRecordChildExecutionCompletedScope
+	// HistoryClientReplicateEventsScope tracks RPC calls to history service
+	HistoryClientReplicateEventsScope
 	// MatchingClientPollForDecisionTaskScope tracks RPC calls to matching service
 	MatchingClientPollForDecisionTaskScope
 	// MatchingClientPollForActivityTaskScope tracks RPC calls to matching service
@@ -238,6 +260,14 @@ const (
 	MatchingClientCancelOutstandingPollScope
 	// MatchingClientDescribeTaskListScope tracks RPC calls to matching service
 	MatchingClientDescribeTaskListScope
+	// MatchingClientUpdateTaskListBuildIDsScope tracks RPC calls to matching service
+	MatchingClientUpdateTaskListBuildIDsScope
+	// MatchingClientPauseTaskListScope tracks RPC calls to matching service
+	MatchingClientPauseTaskListScope
+	// MatchingClientResumeTaskListScope tracks RPC calls to matching service
+	MatchingClientResumeTaskListScope
+	// MatchingClientListTaskListsScope tracks RPC calls to matching service
+	MatchingClientListTaskListsScope
 
 	NumCommonScopes
 )
@@ -276,14 +306,22 @@ const (
 	FrontendGetWorkflowExecutionHistoryScope
 	// FrontendSignalWorkflowExecutionScope is the metric scope for frontend.SignalWorkflowExecution
 	FrontendSignalWorkflowExecutionScope
+	// FrontendSignalWithStartWorkflowExecutionScope is the metric scope for frontend.SignalWithStartWorkflowExecution
+	FrontendSignalWithStartWorkflowExecutionScope
 	// FrontendTerminateWorkflowExecutionScope is the metric scope for frontend.TerminateWorkflowExecution
 	FrontendTerminateWorkflowExecutionScope
+	// FrontendResetWorkflowExecutionScope is the metric scope for frontend.ResetWorkflowExecution
+	FrontendResetWorkflowExecutionScope
 	// FrontendRequestCancelWorkflowExecutionScope is the metric scope for frontend.RequestCancelWorkflowExecution
 	FrontendRequestCancelWorkflowExecutionScope
 	// FrontendListOpenWorkflowExecutionsScope is the metric scope for frontend.ListOpenWorkflowExecutions
 	FrontendListOpenWorkflowExecutionsScope
 	// FrontendListClosedWorkflowExecutionsScope is the metric scope for frontend.ListClosedWorkflowExecutions
 	FrontendListClosedWorkflowExecutionsScope
+	// FrontendListWorkflowExecutionsScope is the metric scope for frontend.ListWorkflowExecutions
+	FrontendListWorkflowExecutionsScope
+	// FrontendCountWorkflowExecutionsScope is the metric scope for frontend.CountWorkflowExecutions
+	FrontendCountWorkflowExecutionsScope
 	// FrontendRegisterDomainScope is the metric scope for frontend.RegisterDomain
 	FrontendRegisterDomainScope
 	// FrontendDescribeDomainScope is the metric scope for frontend.DescribeDomain
@@ -298,6 +336,14 @@ const (
 	FrontendDescribeWorkflowExecutionScope
 	// FrontendDescribeTaskListScope is the metric scope for frontend.DescribeTaskList
 	FrontendDescribeTaskListScope
+	// FrontendUpdateTaskListBuildIDsScope is the metric scope for frontend.UpdateTaskListBuildIDs
+	FrontendUpdateTaskListBuildIDsScope
+	// FrontendPauseTaskListScope is the metric scope for frontend.PauseTaskList
+	FrontendPauseTaskListScope
+	// FrontendResumeTaskListScope is the metric scope for frontend.ResumeTaskList
+	FrontendResumeTaskListScope
+	// FrontendListTaskListsScope is the metric scope for frontend.ListTaskLists
+	FrontendListTaskListsScope
 
 	NumFrontendScopes
 )
@@ -330,16 +376,22 @@ const (
 	HistoryRecordActivityTaskStartedScope
 	// HistorySignalWorkflowExecutionScope tracks SignalWorkflowExecution API calls received by service
 	HistorySignalWorkflowExecutionScope
+	// HistorySignalWithStartWorkflowExecutionScope tracks SignalWithStartWorkflowExecution API calls received by service
+	HistorySignalWithStartWorkflowExecutionScope
 	// HistoryRemoveSignalMutableStateScope tracks RemoveSignalMutableState API calls received by service
 	HistoryRemoveSignalMutableStateScope
 	// HistoryTerminateWorkflowExecutionScope tracks TerminateWorkflowExecution API calls received by service
 	HistoryTerminateWorkflowExecutionScope
+	// HistoryResetWorkflowExecutionScope tracks ResetWorkflowExecution API calls received by service
+	HistoryResetWorkflowExecutionScope
 	// HistoryScheduleDecisionTaskScope tracks ScheduleDecisionTask API calls received by service
 	HistoryScheduleDecisionTaskScope
 	// HistoryRecordChildExecutionCompletedScope tracks CompleteChildExecution API calls received by service
 	HistoryRecordChildExecutionCompletedScope
 	// HistoryRequestCancelWorkflowExecutionScope tracks RequestCancelWorkflowExecution API calls received by service
 	HistoryRequestCancelWorkflowExecutionScope
+	// HistoryReplicateEventsScope tracks ReplicateEvents API calls received by service
+	HistoryReplicateEventsScope
 	// HistoryShardControllerScope is the scope used by shard controller
 	HistoryShardControllerScope
 	// TransferQueueProcessorScope is the scope used by all metric emitted by transfer queue processor
@@ -356,6 +408,8 @@ const (
 	TransferTaskSignalExecutionScope
 	// TransferTaskStartChildExecutionScope is the scope used for start child execution task processing by transfer queue processor
 	TransferTaskStartChildExecutionScope
+	// TransferTaskUpsertWorkflowSearchAttributesScope is the scope used for upsert search attributes processing by transfer queue processor
+	TransferTaskUpsertWorkflowSearchAttributesScope
 	// TimerQueueProcessorScope is the scope used by all metric emitted by timer queue processor
 	TimerQueueProcessorScope
 	// TimerTaskActivityTimeoutScope is the scope used by metric emitted by timer queue processor for processing activity timeouts
@@ -368,6 +422,8 @@ const (
 	TimerTaskWorkflowTimeoutScope
 	// TimerTaskDeleteHistoryEvent is the scope used by metric emitted by timer queue processor for processing history event cleanup
 	TimerTaskDeleteHistoryEvent
+	// TimerTaskWorkflowBackoffTimerScope is the scope used by metric emitted by timer queue processor for processing workflow backoff timers
+	TimerTaskWorkflowBackoffTimerScope
 	// HistoryEventNotificationScope is the scope used by shard history event nitification
 	HistoryEventNotificationScope
 	// ReplicatorQueueProcessorScope is the scope used by all metric emitted by replicator queue processor
@@ -398,6 +454,14 @@ const (
 	MatchingCancelOutstandingPollScope
 	// MatchingDescribeTaskListScope tracks DescribeTaskList API calls received by service
 	MatchingDescribeTaskListScope
+	// MatchingUpdateTaskListBuildIDsScope tracks UpdateTaskListBuildIDs API calls received by service
+	MatchingUpdateTaskListBuildIDsScope
+	// MatchingPauseTaskListScope tracks PauseTaskList API calls received by service
+	MatchingPauseTaskListScope
+	// MatchingResumeTaskListScope tracks ResumeTaskList API calls received by service
+	MatchingResumeTaskListScope
+	// MatchingListTaskListsScope tracks ListTaskLists API calls received by service
+	MatchingListTaskListsScope
 
 	NumMatchingScopes
 )
@@ -414,70 +478,84 @@ const (
 var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 	// common scope Names
 	Common: {
-		PersistenceCreateShardScope:                              {operation: "CreateShard", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
-		PersistenceGetShardScope:                                 {operation: "GetShard", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
-		PersistenceUpdateShardScope:                              {operation: "UpdateShard", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
-		PersistenceCreateWorkflowExecutionScope:                  {operation: "CreateWorkflowExecution"},
-		PersistenceGetWorkflowExecutionScope:                     {operation: "GetWorkflowExecution"},
-		PersistenceUpdateWorkflowExecutionScope:                  {operation: "UpdateWorkflowExecution"},
-		PersistenceDeleteWorkflowExecutionScope:                  {operation: "DeleteWorkflowExecution"},
-		PersistenceGetCurrentExecutionScope:                      {operation: "GetCurrentExecution"},
-		PersistenceGetTransferTasksScope:                         {operation: "GetTransferTasks"},
-		PersistenceGetReplicationTasksScope:                      {operation: "GetReplicationTasks"},
-		PersistenceCompleteTransferTaskScope:                     {operation: "CompleteTransferTask"},
-		PersistenceCompleteReplicationTaskScope:                  {operation: "CompleteReplicationTask"},
-		PersistenceGetTimerIndexTasksScope:                       {operation: "GetTimerIndexTasks"},
-		PersistenceCompleteTimerTaskScope:                        {operation: "CompleteTimerTask"},
-		PersistenceCreateTaskScope:                               {operation: "CreateTask", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
-		PersistenceGetTasksScope:                                 {operation: "GetTasks", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
-		PersistenceCompleteTaskScope:                             {operation: "CompleteTask", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
-		PersistenceLeaseTaskListScope:                            {operation: "LeaseTaskList", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
-		PersistenceUpdateTaskListScope:                           {operation: "UpdateTaskList", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
-		PersistenceAppendHistoryEventsScope:                      {operation: "AppendHistoryEvents", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
-		PersistenceGetWorkflowExecutionHistoryScope:              {operation: "GetWorkflowExecutionHistory", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
-		PersistenceDeleteWorkflowExecutionHistoryScope:           {operation: "DeleteWorkflowExecutionHistory", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
-		PersistenceCreateDomainScope:                             {operation: "CreateDomain", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
-		PersistenceGetDomainScope:                                {operation: "GetDomain", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
-		PersistenceUpdateDomainScope:                             {operation: "UpdateDomain", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
-		PersistenceDeleteDomainScope:                             {operation: "DeleteDomain", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
-		PersistenceDeleteDomainByNameScope:                       {operation: "DeleteDomainByName", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
-		PersistenceRecordWorkflowExecutionStartedScope:           {operation: "RecordWorkflowExecutionStarted"},
-		PersistenceRecordWorkflowExecutionClosedScope:            {operation: "RecordWorkflowExecutionClosed"},
-		PersistenceListOpenWorkflowExecutionsScope:               {operation: "ListOpenWorkflowExecutions"},
-		PersistenceListClosedWorkflowExecutionsScope:             {operation: "ListClosedWorkflowExecutions"},
-		PersistenceListOpenWorkflowExecutionsByTypeScope:         {operation: "ListOpenWorkflowExecutionsByType"},
-		PersistenceListClosedWorkflowExecutionsByTypeScope:       {operation: "ListClosedWorkflowExecutionsByType"},
-		PersistenceListOpenWorkflowExecutionsByWorkflowIDScope:   {operation: "ListOpenWorkflowExecutionsByWorkflowID"},
-		PersistenceListClosedWorkflowExecutionsByWorkflowIDScope: {operation: "ListClosedWorkflowExecutionsByWorkflowID"},
-		PersistenceListClosedWorkflowExecutionsByStatusScope:     {operation: "ListClosedWorkflowExecutionsByStatus"},
-		PersistenceGetClosedWorkflowExecutionScope:               {operation: "GetClosedWorkflowExecution"},
-
-		HistoryClientStartWorkflowExecutionScope:         {operation: "HistoryClientStartWorkflowExecution"},
-		HistoryClientRecordActivityTaskHeartbeatScope:    {operation: "HistoryClientRecordActivityTaskHeartbeat"},
-		HistoryClientRespondDecisionTaskCompletedScope:   {operation: "HistoryClientRespondDecisionTaskCompleted"},
-		HistoryClientRespondDecisionTaskFailedScope:      {operation: "HistoryClientRespondDecisionTaskFailed"},
-		HistoryClientRespondActivityTaskCompletedScope:   {operation: "HistoryClientRespondActivityTaskCompleted"},
-		HistoryClientRespondActivityTaskFailedScope:      {operation: "HistoryClientRespondActivityTaskFailed"},
-		HistoryClientRespondActivityTaskCanceledScope:    {operation: "HistoryClientRespondActivityTaskCanceled"},
-		HistoryClientGetMutableStateScope:                {operation: "HistoryClientGetMutableState"},
-		HistoryClientResetStickyTaskListScope:            {operation: "HistoryClientResetStickyTaskListScope"},
-		HistoryClientDescribeWorkflowExecutionScope:      {operation: "HistoryClientDescribeWorkflowExecution"},
-		HistoryClientRecordDecisionTaskStartedScope:      {operation: "HistoryClientRecordDecisionTaskStarted"},
-		HistoryClientRecordActivityTaskStartedScope:      {operation: "HistoryClientRecordActivityTaskStarted"},
-		HistoryClientRequestCancelWorkflowExecutionScope: {operation: "HistoryClientRequestCancelWorkflowExecution"},
-		HistoryClientSignalWorkflowExecutionScope:        {operation: "HistoryClientSignalWorkflowExecution"},
-		HistoryClientRemoveSignalMutableStateScope:       {operation: "HistoryClientRemoveSignalMutableStateScope"},
-		HistoryClientTerminateWorkflowExecutionScope:     {operation: "HistoryClientTerminateWorkflowExecution"},
-		HistoryClientScheduleDecisionTaskScope:           {operation: "HistoryClientScheduleDecisionTask"},
-		HistoryClientRecordChildExecutionCompletedScope:  {operation: "HistoryClientRecordChildExecutionCompleted"},
-		MatchingClientPollForDecisionTaskScope:           {operation: "MatchingClientPollForDecisionTask"},
-		MatchingClientPollForActivityTaskScope:           {operation: "MatchingClientPollForActivityTask"},
-		MatchingClientAddActivityTaskScope:               {operation: "MatchingClientAddActivityTask"},
-		MatchingClientAddDecisionTaskScope:               {operation: "MatchingClientAddDecisionTask"},
-		MatchingClientQueryWorkflowScope:                 {operation: "MatchingClientQueryWorkflow"},
-		MatchingClientRespondQueryTaskCompletedScope:     {operation: "MatchingClientRespondQueryTaskCompleted"},
-		MatchingClientCancelOutstandingPollScope:         {operation: "MatchingClientCancelOutstandingPoll"},
-		MatchingClientDescribeTaskListScope:              {operation: "MatchingClientDescribeTaskList"},
+		PersistenceCreateShardScope:                                    {operation: "CreateShard", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceGetShardScope:                                       {operation: "GetShard", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceUpdateShardScope:                                    {operation: "UpdateShard", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceCreateWorkflowExecutionScope:                        {operation: "CreateWorkflowExecution"},
+		PersistenceGetWorkflowExecutionScope:                           {operation: "GetWorkflowExecution"},
+		PersistenceUpdateWorkflowExecutionScope:                        {operation: "UpdateWorkflowExecution"},
+		PersistenceDeleteWorkflowExecutionScope:                        {operation: "DeleteWorkflowExecution"},
+		PersistenceGetCurrentExecutionScope:                            {operation: "GetCurrentExecution"},
+		PersistenceListConcreteExecutionsScope:                         {operation: "ListConcreteExecutions"},
+		PersistenceGetTransferTasksScope:                               {operation: "GetTransferTasks"},
+		PersistenceGetReplicationTasksScope:                            {operation: "GetReplicationTasks"},
+		PersistenceCompleteTransferTaskScope:                           {operation: "CompleteTransferTask"},
+		PersistenceCompleteReplicationTaskScope:                        {operation: "CompleteReplicationTask"},
+		PersistenceGetTimerIndexTasksScope:                             {operation: "GetTimerIndexTasks"},
+		PersistenceCompleteTimerTaskScope:                              {operation: "CompleteTimerTask"},
+		PersistenceCreateTaskScope:                                     {operation: "CreateTask", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceGetTasksScope:                                       {operation: "GetTasks", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceCompleteTaskScope:                                   {operation: "CompleteTask", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceLeaseTaskListScope:                                  {operation: "LeaseTaskList", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceUpdateTaskListScope:                                 {operation: "UpdateTaskList", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceListTaskListScope:                                   {operation: "ListTaskList", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceAppendHistoryEventsScope:                            {operation: "AppendHistoryEvents", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceGetWorkflowExecutionHistoryScope:                    {operation: "GetWorkflowExecutionHistory", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceDeleteWorkflowExecutionHistoryScope:                 {operation: "DeleteWorkflowExecutionHistory", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceCreateDomainScope:                                   {operation: "CreateDomain", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceGetDomainScope:                                      {operation: "GetDomain", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceUpdateDomainScope:                                   {operation: "UpdateDomain", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceDeleteDomainScope:                                   {operation: "DeleteDomain", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceDeleteDomainByNameScope:                             {operation: "DeleteDomainByName", tags: map[string]string{ShardTagName: NoneShardsTagValue}},
+		PersistenceRecordWorkflowExecutionStartedScope:                 {operation: "RecordWorkflowExecutionStarted"},
+		PersistenceRecordWorkflowExecutionClosedScope:                  {operation: "RecordWorkflowExecutionClosed"},
+		PersistenceListOpenWorkflowExecutionsScope:                     {operation: "ListOpenWorkflowExecutions"},
+		PersistenceListClosedWorkflowExecutionsScope:                   {operation: "ListClosedWorkflowExecutions"},
+		PersistenceListOpenWorkflowExecutionsByTypeScope:               {operation: "ListOpenWorkflowExecutionsByType"},
+		PersistenceListClosedWorkflowExecutionsByTypeScope:             {operation: "ListClosedWorkflowExecutionsByType"},
+		PersistenceListOpenWorkflowExecutionsByWorkflowIDScope:         {operation: "ListOpenWorkflowExecutionsByWorkflowID"},
+		PersistenceListClosedWorkflowExecutionsByWorkflowIDScope:       {operation: "ListClosedWorkflowExecutionsByWorkflowID"},
+		PersistenceUpsertWorkflowExecutionScope:                        {operation: "UpsertWorkflowExecution"},
+		PersistenceListOpenWorkflowExecutionsBySearchAttributesScope:   {operation: "ListOpenWorkflowExecutionsBySearchAttributes"},
+		PersistenceListClosedWorkflowExecutionsBySearchAttributesScope: {operation: "ListClosedWorkflowExecutionsBySearchAttributes"},
+		PersistenceListClosedWorkflowExecutionsByStatusScope:           {operation: "ListClosedWorkflowExecutionsByStatus"},
+		PersistenceListWorkflowExecutionsScope:                         {operation: "ListWorkflowExecutions"},
+		PersistenceCountWorkflowExecutionsScope:                        {operation: "CountWorkflowExecutions"},
+		PersistenceGetClosedWorkflowExecutionScope:                     {operation: "GetClosedWorkflowExecution"},
+
+		HistoryClientStartWorkflowExecutionScope:           {operation: "HistoryClientStartWorkflowExecution"},
+		HistoryClientRecordActivityTaskHeartbeatScope:      {operation: "HistoryClientRecordActivityTaskHeartbeat"},
+		HistoryClientRespondDecisionTaskCompletedScope:     {operation: "HistoryClientRespondDecisionTaskCompleted"},
+		HistoryClientRespondDecisionTaskFailedScope:        {operation: "HistoryClientRespondDecisionTaskFailed"},
+		HistoryClientRespondActivityTaskCompletedScope:     {operation: "HistoryClientRespondActivityTaskCompleted"},
+		HistoryClientRespondActivityTaskFailedScope:        {operation: "HistoryClientRespondActivityTaskFailed"},
+		HistoryClientRespondActivityTaskCanceledScope:      {operation: "HistoryClientRespondActivityTaskCanceled"},
+		HistoryClientGetMutableStateScope:                  {operation: "HistoryClientGetMutableState"},
+		HistoryClientResetStickyTaskListScope:              {operation: "HistoryClientResetStickyTaskListScope"},
+		HistoryClientDescribeWorkflowExecutionScope:        {operation: "HistoryClientDescribeWorkflowExecution"},
+		HistoryClientRecordDecisionTaskStartedScope:        {operation: "HistoryClientRecordDecisionTaskStarted"},
+		HistoryClientRecordActivityTaskStartedScope:        {operation: "HistoryClientRecordActivityTaskStarted"},
+		HistoryClientRequestCancelWorkflowExecutionScope:   {operation: "HistoryClientRequestCancelWorkflowExecution"},
+		HistoryClientSignalWorkflowExecutionScope:          {operation: "HistoryClientSignalWorkflowExecution"},
+		HistoryClientSignalWithStartWorkflowExecutionScope: {operation: "HistoryClientSignalWithStartWorkflowExecution"},
+		HistoryClientRemoveSignalMutableStateScope:         {operation: "HistoryClientRemoveSignalMutableStateScope"},
+		HistoryClientTerminateWorkflowExecutionScope:       {operation: "HistoryClientTerminateWorkflowExecution"},
+		HistoryClientResetWorkflowExecutionScope:           {operation: "HistoryClientResetWorkflowExecution"},
+		HistoryClientScheduleDecisionTaskScope:             {operation: "HistoryClientScheduleDecisionTask"},
+		HistoryClientRecordChildExecutionCompletedScope:    {operation: "HistoryClientRecordChildExecutionCompleted"},
+		HistoryClientReplicateEventsScope:                  {operation: "HistoryClientReplicateEvents"},
+		MatchingClientPollForDecisionTaskScope:             {operation: "MatchingClientPollForDecisionTask"},
+		MatchingClientPollForActivityTaskScope:             {operation: "MatchingClientPollForActivityTask"},
+		MatchingClientAddActivityTaskScope:                 {operation: "MatchingClientAddActivityTask"},
+		MatchingClientAddDecisionTaskScope:                 {operation: "MatchingClientAddDecisionTask"},
+		MatchingClientQueryWorkflowScope:                   {operation: "MatchingClientQueryWorkflow"},
+		MatchingClientRespondQueryTaskCompletedScope:       {operation: "MatchingClientRespondQueryTaskCompleted"},
+		MatchingClientCancelOutstandingPollScope:           {operation: "MatchingClientCancelOutstandingPoll"},
+		MatchingClientDescribeTaskListScope:                {operation: "MatchingClientDescribeTaskList"},
+		MatchingClientUpdateTaskListBuildIDsScope:          {operation: "MatchingClientUpdateTaskListBuildIDs"},
+		MatchingClientPauseTaskListScope:                   {operation: "MatchingClientPauseTaskList"},
+		MatchingClientResumeTaskListScope:                  {operation: "MatchingClientResumeTaskList"},
+		MatchingClientListTaskListsScope:                   {operation: "MatchingClientListTaskLists"},
 	},
 	// Frontend Scope Names
 	Frontend: {
@@ -497,10 +575,14 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		FrontendRespondActivityTaskCanceledByIDScope:  {operation: "RespondActivityTaskCanceledByID"},
 		FrontendGetWorkflowExecutionHistoryScope:      {operation: "GetWorkflowExecutionHistory"},
 		FrontendSignalWorkflowExecutionScope:          {operation: "SignalWorkflowExecution"},
+		FrontendSignalWithStartWorkflowExecutionScope: {operation: "SignalWithStartWorkflowExecution"},
 		FrontendTerminateWorkflowExecutionScope:       {operation: "TerminateWorkflowExecution"},
+		FrontendResetWorkflowExecutionScope:           {operation: "ResetWorkflowExecution"},
 		FrontendRequestCancelWorkflowExecutionScope:   {operation: "RequestCancelWorkflowExecution"},
 		FrontendListOpenWorkflowExecutionsScope:       {operation: "ListOpenWorkflowExecutions"},
 		FrontendListClosedWorkflowExecutionsScope:     {operation: "ListClosedWorkflowExecutions"},
+		FrontendListWorkflowExecutionsScope:           {operation: "ListWorkflowExecutions"},
+		FrontendCountWorkflowExecutionsScope:          {operation: "CountWorkflowExecutions"},
 		FrontendRegisterDomainScope:                   {operation: "RegisterDomain"},
 		FrontendDescribeDomainScope:                   {operation: "DescribeDomain"},
 		FrontendUpdateDomainScope:                     {operation: "UpdateDomain"},
@@ -508,44 +590,53 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		FrontendQueryWorkflowScope:                    {operation: "QueryWorkflow"},
 		FrontendDescribeWorkflowExecutionScope:        {operation: "DescribeWorkflowExecution"},
 		FrontendDescribeTaskListScope:                 {operation: "DescribeTaskList"},
+		FrontendUpdateTaskListBuildIDsScope:           {operation: "UpdateTaskListBuildIDs"},
+		FrontendPauseTaskListScope:                    {operation: "PauseTaskList"},
+		FrontendResumeTaskListScope:                   {operation: "ResumeTaskList"},
+		FrontendListTaskListsScope:                    {operation: "ListTaskLists"},
 	},
 	// History Scope Names
 	History: {
-		HistoryStartWorkflowExecutionScope:         {operation: "StartWorkflowExecution"},
-		HistoryRecordActivityTaskHeartbeatScope:    {operation: "RecordActivityTaskHeartbeat"},
-		HistoryRespondDecisionTaskCompletedScope:   {operation: "RespondDecisionTaskCompleted"},
-		HistoryRespondDecisionTaskFailedScope:      {operation: "RespondDecisionTaskFailed"},
-		HistoryRespondActivityTaskCompletedScope:   {operation: "RespondActivityTaskCompleted"},
-		HistoryRespondActivityTaskFailedScope:      {operation: "RespondActivityTaskFailed"},
-		HistoryRespondActivityTaskCanceledScope:    {operation: "RespondActivityTaskCanceled"},
-		HistoryGetMutableStateScope:                {operation: "GetMutableState"},
-		HistoryResetStickyTaskListScope:            {operation: "ResetStickyTaskListScope"},
-		HistoryDescribeWorkflowExecutionScope:      {operation: "DescribeWorkflowExecution"},
-		HistoryRecordDecisionTaskStartedScope:      {operation: "RecordDecisionTaskStarted"},
-		HistoryRecordActivityTaskStartedScope:      {operation: "RecordActivityTaskStarted"},
-		HistorySignalWorkflowExecutionScope:        {operation: "SignalWorkflowExecution"},
-		HistoryRemoveSignalMutableStateScope:       {operation: "RemoveSignalMutableState"},
-		HistoryTerminateWorkflowExecutionScope:     {operation: "TerminateWorkflowExecution"},
-		HistoryScheduleDecisionTaskScope:           {operation: "ScheduleDecisionTask"},
-		HistoryRecordChildExecutionCompletedScope:  {operation: "RecordChildExecutionCompleted"},
-		HistoryRequestCancelWorkflowExecutionScope: {operation: "RequestCancelWorkflowExecution"},
-		HistoryShardControllerScope:                {operation: "ShardController"},
-		TransferQueueProcessorScope:                {operation: "TransferQueueProcessor"},
-		TransferTaskActivityScope:                  {operation: "TransferTaskActivity"},
-		TransferTaskDecisionScope:                  {operation: "TransferTaskDecision"},
-		TransferTaskCloseExecutionScope:            {operation: "TransferTaskCloseExecution"},
-		TransferTaskCancelExecutionScope:           {operation: "TransferTaskCancelExecution"},
-		TransferTaskSignalExecutionScope:           {operation: "TransferTaskSignalExecution"},
-		TransferTaskStartChildExecutionScope:       {operation: "TransferTaskStartChildExecution"},
-		TimerQueueProcessorScope:                   {operation: "TimerQueueProcessor"},
-		TimerTaskActivityTimeoutScope:              {operation: "TimerTaskActivityTimeout"},
-		TimerTaskDecisionTimeoutScope:              {operation: "TimerTaskDecisionTimeout"},
-		TimerTaskUserTimerScope:                    {operation: "TimerTaskUserTimer"},
-		TimerTaskWorkflowTimeoutScope:              {operation: "TimerTaskWorkflowTimeout"},
-		TimerTaskDeleteHistoryEvent:                {operation: "TimerTaskDeleteHistoryEvent"},
-		HistoryEventNotificationScope:              {operation: "HistoryEventNotification"},
-		ReplicatorQueueProcessorScope:              {operation: "ReplicatorQueueProcessor"},
-		ReplicatorTaskHistoryScope:                 {operation: "ReplicatorTaskHistory"},
+		HistoryStartWorkflowExecutionScope:              {operation: "StartWorkflowExecution"},
+		HistoryRecordActivityTaskHeartbeatScope:         {operation: "RecordActivityTaskHeartbeat"},
+		HistoryRespondDecisionTaskCompletedScope:        {operation: "RespondDecisionTaskCompleted"},
+		HistoryRespondDecisionTaskFailedScope:           {operation: "RespondDecisionTaskFailed"},
+		HistoryRespondActivityTaskCompletedScope:        {operation: "RespondActivityTaskCompleted"},
+		HistoryRespondActivityTaskFailedScope:           {operation: "RespondActivityTaskFailed"},
+		HistoryRespondActivityTaskCanceledScope:         {operation: "RespondActivityTaskCanceled"},
+		HistoryGetMutableStateScope:                     {operation: "GetMutableState"},
+		HistoryResetStickyTaskListScope:                 {operation: "ResetStickyTaskListScope"},
+		HistoryDescribeWorkflowExecutionScope:           {operation: "DescribeWorkflowExecution"},
+		HistoryRecordDecisionTaskStartedScope:           {operation: "RecordDecisionTaskStarted"},
+		HistoryRecordActivityTaskStartedScope:           {operation: "RecordActivityTaskStarted"},
+		HistorySignalWorkflowExecutionScope:             {operation: "SignalWorkflowExecution"},
+		HistorySignalWithStartWorkflowExecutionScope:    {operation: "SignalWithStartWorkflowExecution"},
+		HistoryRemoveSignalMutableStateScope:            {operation: "RemoveSignalMutableState"},
+		HistoryTerminateWorkflowExecutionScope:          {operation: "TerminateWorkflowExecution"},
+		HistoryResetWorkflowExecutionScope:              {operation: "ResetWorkflowExecution"},
+		HistoryScheduleDecisionTaskScope:                {operation: "ScheduleDecisionTask"},
+		HistoryRecordChildExecutionCompletedScope:       {operation: "RecordChildExecutionCompleted"},
+		HistoryRequestCancelWorkflowExecutionScope:      {operation: "RequestCancelWorkflowExecution"},
+		HistoryReplicateEventsScope:                     {operation: "ReplicateEvents"},
+		HistoryShardControllerScope:                     {operation: "ShardController"},
+		TransferQueueProcessorScope:                     {operation: "TransferQueueProcessor"},
+		TransferTaskActivityScope:                       {operation: "TransferTaskActivity"},
+		TransferTaskDecisionScope:                       {operation: "TransferTaskDecision"},
+		TransferTaskCloseExecutionScope:                 {operation: "TransferTaskCloseExecution"},
+		TransferTaskCancelExecutionScope:                {operation: "TransferTaskCancelExecution"},
+		TransferTaskSignalExecutionScope:                {operation: "TransferTaskSignalExecution"},
+		TransferTaskStartChildExecutionScope:            {operation: "TransferTaskStartChildExecution"},
+		TransferTaskUpsertWorkflowSearchAttributesScope: {operation: "TransferTaskUpsertWorkflowSearchAttributes"},
+		TimerQueueProcessorScope:                        {operation: "TimerQueueProcessor"},
+		TimerTaskActivityTimeoutScope:                   {operation: "TimerTaskActivityTimeout"},
+		TimerTaskDecisionTimeoutScope:                   {operation: "TimerTaskDecisionTimeout"},
+		TimerTaskUserTimerScope:                         {operation: "TimerTaskUserTimer"},
+		TimerTaskWorkflowTimeoutScope:                   {operation: "TimerTaskWorkflowTimeout"},
+		TimerTaskDeleteHistoryEvent:                     {operation: "TimerTaskDeleteHistoryEvent"},
+		TimerTaskWorkflowBackoffTimerScope:              {operation: "TimerTaskWorkflowBackoffTimer"},
+		HistoryEventNotificationScope:                   {operation: "HistoryEventNotification"},
+		ReplicatorQueueProcessorScope:                   {operation: "ReplicatorQueueProcessor"},
+		ReplicatorTaskHistoryScope:                      {operation: "ReplicatorTaskHistory"},
 	},
 	// Matching Scope Names
 	Matching: {
@@ -558,6 +649,10 @@ var ScopeDefs = map[ServiceIdx]map[int]scopeDefinition{
 		MatchingRespondQueryTaskCompletedScope: {operation: "RespondQueryTaskCompleted"},
 		MatchingCancelOutstandingPollScope:     {operation: "CancelOutstandingPoll"},
 		MatchingDescribeTaskListScope:          {operation: "DescribeTaskList"},
+		MatchingUpdateTaskListBuildIDsScope:    {operation: "UpdateTaskListBuildIDs"},
+		MatchingPauseTaskListScope:             {operation: "PauseTaskList"},
+		MatchingResumeTaskListScope:            {operation: "ResumeTaskList"},
+		MatchingListTaskListsScope:             {operation: "ListTaskLists"},
 	},
 	// Worker Scope Names
 	Worker: {
@@ -577,6 +672,7 @@ const (
 	CadenceErrDomainAlreadyExistsCounter
 	CadenceErrCancellationAlreadyRequestedCounter
 	CadenceErrQueryFailedCounter
+	CadenceErrAccessDeniedCounter
 	PersistenceRequests
 	PersistenceFailures
 	PersistenceLatency
@@ -607,6 +703,7 @@ const (
 	DecisionTypeCancelActivityCounter
 	DecisionTypeCancelTimerCounter
 	DecisionTypeRecordMarkerCounter
+	DecisionTypeUpsertWorkflowSearchAttributesCounter
 	DecisionTypeCancelExternalWorkflowCounter
 	DecisionTypeChildWorkflowCounter
 	DecisionTypeContinueAsNewCounter
@@ -621,6 +718,10 @@ const (
 	ScheduleToStartTimeoutCounter
 	StartToCloseTimeoutCounter
 	ScheduleToCloseTimeoutCounter
+	ActivityRetryCounter
+	ChildPolicyTerminateCounter
+	ChildPolicyRequestCancelCounter
+	ChildPolicyAbandonCounter
 	NewActiveTimerCounter
 	NewStandbyTimerCounter
 	NewTimerNotifyCounter
@@ -653,6 +754,10 @@ const (
 	RespondQueryTaskFailedCounter
 	SyncThrottleCounter
 	BufferThrottleCounter
+	ForwardTaskCounter
+	ForwardPollCounter
+	RedirectBuildIDTaskCounter
+	ExpiredTasksCounter
 )
 
 // Worker metrics enum
@@ -675,6 +780,7 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		CadenceErrDomainAlreadyExistsCounter:          {metricName: "cadence.errors.domain-already-exists", metricType: Counter},
 		CadenceErrCancellationAlreadyRequestedCounter: {metricName: "cadence.errors.cancellation-already-requested", metricType: Counter},
 		CadenceErrQueryFailedCounter:                  {metricName: "cadence.errors.query-failed", metricType: Counter},
+		CadenceErrAccessDeniedCounter:                 {metricName: "cadence.errors.access-denied", metricType: Counter},
 		PersistenceRequests:                           {metricName: "persistence.requests", metricType: Counter},
 		PersistenceFailures:                           {metricName: "persistence.errors", metricType: Counter},
 		PersistenceLatency:                            {metricName: "persistence.latency", metricType: Timer},
@@ -688,51 +794,56 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 	},
 	Frontend: {},
 	History: {
-		TaskRequests:                                 {metricName: "task.requests", metricType: Counter},
-		TaskFailures:                                 {metricName: "task.errors", metricType: Counter},
-		TaskLatency:                                  {metricName: "task.latency", metricType: Counter},
-		AckLevelUpdateCounter:                        {metricName: "ack-level-update", metricType: Counter},
-		AckLevelUpdateFailedCounter:                  {metricName: "ack-level-update-failed", metricType: Counter},
-		DecisionTypeScheduleActivityCounter:          {metricName: "schedule-activity-decision", metricType: Counter},
-		DecisionTypeCompleteWorkflowCounter:          {metricName: "complete-workflow-decision", metricType: Counter},
-		DecisionTypeFailWorkflowCounter:              {metricName: "fail-workflow-decision", metricType: Counter},
-		DecisionTypeCancelWorkflowCounter:            {metricName: "cancel-workflow-decision", metricType: Counter},
-		DecisionTypeStartTimerCounter:                {metricName: "start-timer-decision", metricType: Counter},
-		DecisionTypeCancelActivityCounter:            {metricName: "cancel-activity-decision", metricType: Counter},
-		DecisionTypeCancelTimerCounter:               {metricName: "cancel-timer-decision", metricType: Counter},
-		DecisionTypeRecordMarkerCounter:              {metricName: "record-marker-decision", metricType: Counter},
-		DecisionTypeCancelExternalWorkflowCounter:    {metricName: "cancel-external-workflow-decision", metricType: Counter},
-		DecisionTypeContinueAsNewCounter:             {metricName: "continue-as-new-decision", metricType: Counter},
-		DecisionTypeChildWorkflowCounter:             {metricName: "child-workflow-decision", metricType: Counter},
-		MultipleCompletionDecisionsCounter:           {metricName: "multiple-completion-decisions", metricType: Counter},
-		FailedDecisionsCounter:                       {metricName: "failed-decisions", metricType: Counter},
-		StaleMutableStateCounter:                     {metricName: "stale-mutable-state", metricType: Counter},
-		ConcurrencyUpdateFailureCounter:              {metricName: "concurrency-update-failure", metricType: Counter},
-		CadenceErrShardOwnershipLostCounter:          {metricName: "cadence.errors.shard-ownership-lost", metricType: Counter},
-		CadenceErrEventAlreadyStartedCounter:         {metricName: "cadence.errors.event-already-started", metricType: Counter},
-		HeartbeatTimeoutCounter:                      {metricName: "heartbeat-tiemout", metricType: Counter},
-		ScheduleToStartTimeoutCounter:                {metricName: "schedule-to-start-timeout", metricType: Counter},
-		StartToCloseTimeoutCounter:                   {metricName: "start-to-close-timeout", metricType: Counter},
-		ScheduleToCloseTimeoutCounter:                {metricName: "schedule-to-close-timeout", metricType: Counter},
-		NewActiveTimerCounter:                        {metricName: "new-active-timer", metricType: Counter},
-		NewStandbyTimerCounter:                       {metricName: "new-standby-timer", metricType: Counter},
-		NewTimerNotifyCounter:                        {metricName: "new-timer-notifications", metricType: Counter},
-		AcquireShardsCounter:                         {metricName: "acquire-shards-count", metricType: Counter},
-		AcquireShardsLatency:                         {metricName: "acquire-shards-latency", metricType: Timer},
-		ShardClosedCounter:                           {metricName: "shard-closed-count", metricType: Counter},
-		ShardItemCreatedCounter:                      {metricName: "sharditem-created-count", metricType: Counter},
-		ShardItemRemovedCounter:                      {metricName: "sharditem-removed-count", metricType: Counter},
-		MembershipChangedCounter:                     {metricName: "membership-changed-count", metricType: Counter},
-		NumShardsGauge:                               {metricName: "numshards-gauge", metricType: Gauge},
-		GetEngineForShardErrorCounter:                {metricName: "get-engine-for-shard-errors", metricType: Counter},
-		GetEngineForShardLatency:                     {metricName: "get-engine-for-shard-latency", metricType: Timer},
-		RemoveEngineForShardLatency:                  {metricName: "remove-engine-for-shard-latency", metricType: Timer},
-		CompleteDecisionWithStickyEnabledCounter:     {metricName: "complete-decision-sticky-enabled-count", metricType: Counter},
-		CompleteDecisionWithStickyDisabledCounter:    {metricName: "complete-decision-sticky-disabled-count", metricType: Counter},
-		HistoryEventNotificationQueueingLatency:      {metricName: "history-event-notification-queueing-latency", metricType: Timer},
-		HistoryEventNotificationFanoutLatency:        {metricName: "history-event-notification-fanout-latency", metricType: Timer},
-		HistoryEventNotificationInFlightMessageGauge: {metricName: "history-event-notification-inflight-message-gauge", metricType: Gauge},
-		HistoryEventNotificationFailDeliveryCount:    {metricName: "history-event-notification-fail-delivery-count", metricType: Counter},
+		TaskRequests:                                      {metricName: "task.requests", metricType: Counter},
+		TaskFailures:                                      {metricName: "task.errors", metricType: Counter},
+		TaskLatency:                                       {metricName: "task.latency", metricType: Counter},
+		AckLevelUpdateCounter:                             {metricName: "ack-level-update", metricType: Counter},
+		AckLevelUpdateFailedCounter:                       {metricName: "ack-level-update-failed", metricType: Counter},
+		DecisionTypeScheduleActivityCounter:               {metricName: "schedule-activity-decision", metricType: Counter},
+		DecisionTypeCompleteWorkflowCounter:               {metricName: "complete-workflow-decision", metricType: Counter},
+		DecisionTypeFailWorkflowCounter:                   {metricName: "fail-workflow-decision", metricType: Counter},
+		DecisionTypeCancelWorkflowCounter:                 {metricName: "cancel-workflow-decision", metricType: Counter},
+		DecisionTypeStartTimerCounter:                     {metricName: "start-timer-decision", metricType: Counter},
+		DecisionTypeCancelActivityCounter:                 {metricName: "cancel-activity-decision", metricType: Counter},
+		DecisionTypeCancelTimerCounter:                    {metricName: "cancel-timer-decision", metricType: Counter},
+		DecisionTypeRecordMarkerCounter:                   {metricName: "record-marker-decision", metricType: Counter},
+		DecisionTypeUpsertWorkflowSearchAttributesCounter: {metricName: "upsert-workflow-search-attributes-decision", metricType: Counter},
+		DecisionTypeCancelExternalWorkflowCounter:         {metricName: "cancel-external-workflow-decision", metricType: Counter},
+		DecisionTypeContinueAsNewCounter:                  {metricName: "continue-as-new-decision", metricType: Counter},
+		DecisionTypeChildWorkflowCounter:                  {metricName: "child-workflow-decision", metricType: Counter},
+		MultipleCompletionDecisionsCounter:                {metricName: "multiple-completion-decisions", metricType: Counter},
+		FailedDecisionsCounter:                            {metricName: "failed-decisions", metricType: Counter},
+		StaleMutableStateCounter:                          {metricName: "stale-mutable-state", metricType: Counter},
+		ConcurrencyUpdateFailureCounter:                   {metricName: "concurrency-update-failure", metricType: Counter},
+		CadenceErrShardOwnershipLostCounter:               {metricName: "cadence.errors.shard-ownership-lost", metricType: Counter},
+		CadenceErrEventAlreadyStartedCounter:              {metricName: "cadence.errors.event-already-started", metricType: Counter},
+		HeartbeatTimeoutCounter:                           {metricName: "heartbeat-tiemout", metricType: Counter},
+		ScheduleToStartTimeoutCounter:                     {metricName: "schedule-to-start-timeout", metricType: Counter},
+		StartToCloseTimeoutCounter:                        {metricName: "start-to-close-timeout", metricType: Counter},
+		ScheduleToCloseTimeoutCounter:                     {metricName: "schedule-to-close-timeout", metricType: Counter},
+		ActivityRetryCounter:                              {metricName: "activity-retry", metricType: Counter},
+		ChildPolicyTerminateCounter:                       {metricName: "child-policy-terminate", metricType: Counter},
+		ChildPolicyRequestCancelCounter:                   {metricName: "child-policy-request-cancel", metricType: Counter},
+		ChildPolicyAbandonCounter:                         {metricName: "child-policy-abandon", metricType: Counter},
+		NewActiveTimerCounter:                             {metricName: "new-active-timer", metricType: Counter},
+		NewStandbyTimerCounter:                            {metricName: "new-standby-timer", metricType: Counter},
+		NewTimerNotifyCounter:                             {metricName: "new-timer-notifications", metricType: Counter},
+		AcquireShardsCounter:                              {metricName: "acquire-shards-count", metricType: Counter},
+		AcquireShardsLatency:                              {metricName: "acquire-shards-latency", metricType: Timer},
+		ShardClosedCounter:                                {metricName: "shard-closed-count", metricType: Counter},
+		ShardItemCreatedCounter:                           {metricName: "sharditem-created-count", metricType: Counter},
+		ShardItemRemovedCounter:                           {metricName: "sharditem-removed-count", metricType: Counter},
+		MembershipChangedCounter:                          {metricName: "membership-changed-count", metricType: Counter},
+		NumShardsGauge:                                    {metricName: "numshards-gauge", metricType: Gauge},
+		GetEngineForShardErrorCounter:                     {metricName: "get-engine-for-shard-errors", metricType: Counter},
+		GetEngineForShardLatency:                          {metricName: "get-engine-for-shard-latency", metricType: Timer},
+		RemoveEngineForShardLatency:                       {metricName: "remove-engine-for-shard-latency", metricType: Timer},
+		CompleteDecisionWithStickyEnabledCounter:          {metricName: "complete-decision-sticky-enabled-count", metricType: Counter},
+		CompleteDecisionWithStickyDisabledCounter:         {metricName: "complete-decision-sticky-disabled-count", metricType: Counter},
+		HistoryEventNotificationQueueingLatency:           {metricName: "history-event-notification-queueing-latency", metricType: Timer},
+		HistoryEventNotificationFanoutLatency:             {metricName: "history-event-notification-fanout-latency", metricType: Timer},
+		HistoryEventNotificationInFlightMessageGauge:      {metricName: "history-event-notification-inflight-message-gauge", metricType: Gauge},
+		HistoryEventNotificationFailDeliveryCount:         {metricName: "history-event-notification-fail-delivery-count", metricType: Counter},
 	},
 	Matching: {
 		PollSuccessCounter:            {metricName: "poll.success"},
@@ -744,6 +855,10 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		RespondQueryTaskFailedCounter: {metricName: "respond-query-failed"},
 		SyncThrottleCounter:           {metricName: "sync.throttle.count"},
 		BufferThrottleCounter:         {metricName: "buffer.throttle.count"},
+		ForwardTaskCounter:            {metricName: "forward.task.count"},
+		ForwardPollCounter:            {metricName: "forward.poll.count"},
+		RedirectBuildIDTaskCounter:    {metricName: "redirect.buildid.task.count"},
+		ExpiredTasksCounter:           {metricName: "expired.tasks.count"},
 	},
 	Worker: {
 		ReplicatorMessages: {metricName: "replicator.messages"},
@@ -253,6 +253,29 @@ func (_m *HistoryClient) SignalWorkflowExecution(ctx context.Context, signalRequ
 	return r0
 }
 
+// SignalWithStartWorkflowExecution provides a mock function with given fields: ctx, signalWithStartRequest
+func (_m *HistoryClient) SignalWithStartWorkflowExecution(ctx context.Context, signalWithStartRequest *history.SignalWithStartWorkflowExecutionRequest, opts ...yarpc.CallOption) (*shared.StartWorkflowExecutionResponse, error) {
+	ret := _m.Called(ctx, signalWithStartRequest)
+
+	var r0 *shared.StartWorkflowExecutionResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *history.SignalWithStartWorkflowExecutionRequest) *shared.StartWorkflowExecutionResponse); ok {
+		r0 = rf(ctx, signalWithStartRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shared.StartWorkflowExecutionResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *history.SignalWithStartWorkflowExecutionRequest) error); ok {
+		r1 = rf(ctx, signalWithStartRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveSignalMutableState provides a mock function with given fields: ctx, removeRequest
 func (_m *HistoryClient) RemoveSignalMutableState(ctx context.Context, removeRequest *history.RemoveSignalMutableStateRequest, opts ...yarpc.CallOption) error {
 	ret := _m.Called(ctx, removeRequest)
@@ -318,6 +341,29 @@ func (_m *HistoryClient) TerminateWorkflowExecution(ctx context.Context, termina
 	return r0
 }
 
+// ResetWorkflowExecution provides a mock function with given fields: ctx, resetRequest
+func (_m *HistoryClient) ResetWorkflowExecution(ctx context.Context, resetRequest *history.ResetWorkflowExecutionRequest, opts ...yarpc.CallOption) (*shared.ResetWorkflowExecutionResponse, error) {
+	ret := _m.Called(ctx, resetRequest)
+
+	var r0 *shared.ResetWorkflowExecutionResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *history.ResetWorkflowExecutionRequest) *shared.ResetWorkflowExecutionResponse); ok {
+		r0 = rf(ctx, resetRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shared.ResetWorkflowExecutionResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *history.ResetWorkflowExecutionRequest) error); ok {
+		r1 = rf(ctx, resetRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ScheduleDecisionTask provides a mock function with given fields: ctx, request
 func (_m *HistoryClient) ScheduleDecisionTask(ctx context.Context, request *history.ScheduleDecisionTaskRequest, opts ...yarpc.CallOption) error {
 	ret := _m.Called(ctx, request)
@@ -345,3 +391,17 @@ func (_m *HistoryClient) RecordChildExecutionCompleted(ctx context.Context, requ
 
 	return r0
 }
+
+// ReplicateEvents provides a mock function with given fields: ctx, request
+func (_m *HistoryClient) ReplicateEvents(ctx context.Context, request *history.ReplicateEventsRequest, opts ...yarpc.CallOption) error {
+	ret := _m.Called(ctx, request)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *history.ReplicateEventsRequest) error); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
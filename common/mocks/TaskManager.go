@@ -116,6 +116,29 @@ func (_m *TaskManager) CreateTasks(request *persistence.CreateTasksRequest) (*pe
 	return r0, r1
 }
 
+// ListTaskList provides a mock function with given fields: request
+func (_m *TaskManager) ListTaskList(request *persistence.ListTaskListRequest) (*persistence.ListTaskListResponse, error) {
+	ret := _m.Called(request)
+
+	var r0 *persistence.ListTaskListResponse
+	if rf, ok := ret.Get(0).(func(*persistence.ListTaskListRequest) *persistence.ListTaskListResponse); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.ListTaskListResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*persistence.ListTaskListRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTasks provides a mock function with given fields: request
 func (_m *TaskManager) GetTasks(request *persistence.GetTasksRequest) (*persistence.GetTasksResponse, error) {
 	ret := _m.Called(request)
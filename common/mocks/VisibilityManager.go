@@ -33,6 +33,29 @@ func (_m *VisibilityManager) Close() {
 	_m.Called()
 }
 
+// CountWorkflowExecutions provides a mock function with given fields: request
+func (_m *VisibilityManager) CountWorkflowExecutions(request *persistence.CountWorkflowExecutionsRequest) (*persistence.CountWorkflowExecutionsResponse, error) {
+	ret := _m.Called(request)
+
+	var r0 *persistence.CountWorkflowExecutionsResponse
+	if rf, ok := ret.Get(0).(func(*persistence.CountWorkflowExecutionsRequest) *persistence.CountWorkflowExecutionsResponse); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.CountWorkflowExecutionsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*persistence.CountWorkflowExecutionsRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetClosedWorkflowExecution provides a mock function with given fields: request
 func (_m *VisibilityManager) GetClosedWorkflowExecution(request *persistence.GetClosedWorkflowExecutionRequest) (*persistence.GetClosedWorkflowExecutionResponse, error) {
 	ret := _m.Called(request)
@@ -79,6 +102,29 @@ func (_m *VisibilityManager) ListClosedWorkflowExecutions(request *persistence.L
 	return r0, r1
 }
 
+// ListClosedWorkflowExecutionsBySearchAttributes provides a mock function with given fields: request
+func (_m *VisibilityManager) ListClosedWorkflowExecutionsBySearchAttributes(request *persistence.ListWorkflowExecutionsBySearchAttributesRequest) (*persistence.ListWorkflowExecutionsResponse, error) {
+	ret := _m.Called(request)
+
+	var r0 *persistence.ListWorkflowExecutionsResponse
+	if rf, ok := ret.Get(0).(func(*persistence.ListWorkflowExecutionsBySearchAttributesRequest) *persistence.ListWorkflowExecutionsResponse); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.ListWorkflowExecutionsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*persistence.ListWorkflowExecutionsBySearchAttributesRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListClosedWorkflowExecutionsByStatus provides a mock function with given fields: request
 func (_m *VisibilityManager) ListClosedWorkflowExecutionsByStatus(request *persistence.ListClosedWorkflowExecutionsByStatusRequest) (*persistence.ListWorkflowExecutionsResponse, error) {
 	ret := _m.Called(request)
@@ -171,6 +217,29 @@ func (_m *VisibilityManager) ListOpenWorkflowExecutions(request *persistence.Lis
 	return r0, r1
 }
 
+// ListOpenWorkflowExecutionsBySearchAttributes provides a mock function with given fields: request
+func (_m *VisibilityManager) ListOpenWorkflowExecutionsBySearchAttributes(request *persistence.ListWorkflowExecutionsBySearchAttributesRequest) (*persistence.ListWorkflowExecutionsResponse, error) {
+	ret := _m.Called(request)
+
+	var r0 *persistence.ListWorkflowExecutionsResponse
+	if rf, ok := ret.Get(0).(func(*persistence.ListWorkflowExecutionsBySearchAttributesRequest) *persistence.ListWorkflowExecutionsResponse); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.ListWorkflowExecutionsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*persistence.ListWorkflowExecutionsBySearchAttributesRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListOpenWorkflowExecutionsByType provides a mock function with given fields: request
 func (_m *VisibilityManager) ListOpenWorkflowExecutionsByType(request *persistence.ListWorkflowExecutionsByTypeRequest) (*persistence.ListWorkflowExecutionsResponse, error) {
 	ret := _m.Called(request)
@@ -217,6 +286,29 @@ func (_m *VisibilityManager) ListOpenWorkflowExecutionsByWorkflowID(request *per
 	return r0, r1
 }
 
+// ListWorkflowExecutions provides a mock function with given fields: request
+func (_m *VisibilityManager) ListWorkflowExecutions(request *persistence.ListWorkflowExecutionsByQueryRequest) (*persistence.ListWorkflowExecutionsResponse, error) {
+	ret := _m.Called(request)
+
+	var r0 *persistence.ListWorkflowExecutionsResponse
+	if rf, ok := ret.Get(0).(func(*persistence.ListWorkflowExecutionsByQueryRequest) *persistence.ListWorkflowExecutionsResponse); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*persistence.ListWorkflowExecutionsResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*persistence.ListWorkflowExecutionsByQueryRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RecordWorkflowExecutionClosed provides a mock function with given fields: request
 func (_m *VisibilityManager) RecordWorkflowExecutionClosed(request *persistence.RecordWorkflowExecutionClosedRequest) error {
 	ret := _m.Called(request)
@@ -244,3 +336,17 @@ func (_m *VisibilityManager) RecordWorkflowExecutionStarted(request *persistence
 
 	return r0
 }
+
+// UpsertWorkflowExecution provides a mock function with given fields: request
+func (_m *VisibilityManager) UpsertWorkflowExecution(request *persistence.UpsertWorkflowExecutionRequest) error {
+	ret := _m.Called(request)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*persistence.UpsertWorkflowExecutionRequest) error); ok {
+		r0 = rf(request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,851 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/uber-common/bark"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/visibilityquery"
+)
+
+const (
+	// boltVisibilityOpenTimeout is how long opening the store waits for the lock on the file, which is held by
+	// the process using it
+	boltVisibilityOpenTimeout = 10 * time.Second
+
+	// boltVisibilityMaxExpiredRecordsPerWrite bounds the number of records past their retention deleted by each
+	// write of a closed record
+	boltVisibilityMaxExpiredRecordsPerWrite = 10
+
+	boltVisibilityOpenState   = byte(0)
+	boltVisibilityClosedState = byte(1)
+)
+
+// The records are keyed by domain ID and run ID.  Every index key starts with the domain ID, followed by the indexed
+// terms and the run ID, while the value of an index entry is the state of the execution followed by its run ID.
+// The strings of the keys are terminated by a zero byte and integers are big endian with the sign bit flipped, so
+// the keys sort like the values they are built from.  The expiry index is the only one not scoped by domain, its
+// keys start with the expiry time and its values are the keys of the expiring records.
+var (
+	boltVisibilityRecordsBucket           = []byte("records")
+	boltVisibilityStartTimeIndexBucket    = []byte("start_time_index")
+	boltVisibilityCloseTimeIndexBucket    = []byte("close_time_index")
+	boltVisibilityWorkflowIDIndexBucket   = []byte("workflow_id_index")
+	boltVisibilityWorkflowTypeIndexBucket = []byte("workflow_type_index")
+	boltVisibilityExpiryIndexBucket       = []byte("expiry_index")
+
+	boltVisibilityBuckets = [][]byte{
+		boltVisibilityRecordsBucket,
+		boltVisibilityStartTimeIndexBucket,
+		boltVisibilityCloseTimeIndexBucket,
+		boltVisibilityWorkflowIDIndexBucket,
+		boltVisibilityWorkflowTypeIndexBucket,
+		boltVisibilityExpiryIndexBucket,
+	}
+)
+
+type (
+	// BoltVisibilityStore is an embedded BoltDB database holding the visibility records of the workflow executions
+	// along with indexes on their fields.  The file is locked by the process which opens it, so all the services
+	// of a process share the same store and it cannot be shared by several hosts.
+	BoltVisibilityStore struct {
+		db *bolt.DB
+	}
+
+	boltVisibilityPersistence struct {
+		store  *BoltVisibilityStore
+		logger bark.Logger
+	}
+
+	// boltVisibilityRecord is the JSON encoded value of the records bucket
+	boltVisibilityRecord struct {
+		WorkflowID       string
+		RunID            string
+		WorkflowTypeName string
+		StartTime        int64
+		Closed           bool
+		CloseTime        int64
+		CloseStatus      int32
+		HistoryLength    int64
+		// ExpireTime is only set on the closed records of the domains with a retention
+		ExpireTime       int64
+		SearchAttributes map[string][]byte
+	}
+
+	// boltVisibilityIndexEntry is the entry of an index pointing to a record
+	boltVisibilityIndexEntry struct {
+		bucket []byte
+		key    []byte
+		value  []byte
+	}
+
+	// boltVisibilityIndexScan is the range of an index holding all the records which can match a query, upper is
+	// excluded and nil when the range is not bounded
+	boltVisibilityIndexScan struct {
+		bucket []byte
+		lower  []byte
+		upper  []byte
+	}
+
+	// boltVisibilityPageToken is the position of the last execution returned in a page
+	boltVisibilityPageToken struct {
+		StartTime int64
+		RunID     string
+	}
+
+	// boltVisibilityQueryPageToken is the number of executions returned in the previous pages of a query
+	boltVisibilityQueryPageToken struct {
+		Offset int
+	}
+)
+
+// NewBoltVisibilityStore opens the BoltDB visibility store at the given path, the file is created if it does not
+// exist yet
+func NewBoltVisibilityStore(path string) (*BoltVisibilityStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: boltVisibilityOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the visibility store %v: %v", path, err)
+	}
+
+	store := &BoltVisibilityStore{db: db}
+	if err := store.db.Update(createBoltVisibilityBuckets); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// Clear deletes all the records of the store along with their indexes
+func (s *BoltVisibilityStore) Clear() error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range boltVisibilityBuckets {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+		return createBoltVisibilityBuckets(tx)
+	})
+}
+
+// Close closes the database file
+func (s *BoltVisibilityStore) Close() {
+	s.db.Close()
+}
+
+func createBoltVisibilityBuckets(tx *bolt.Tx) error {
+	for _, name := range boltVisibilityBuckets {
+		if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewBoltVisibilityPersistence is used to create an instance of VisibilityManager implementation
+func NewBoltVisibilityPersistence(store *BoltVisibilityStore, logger bark.Logger) (VisibilityManager, error) {
+	return &boltVisibilityPersistence{store: store, logger: logger}, nil
+}
+
+// Close is a no-op, the database is owned by the BoltVisibilityStore
+func (v *boltVisibilityPersistence) Close() {
+}
+
+func (v *boltVisibilityPersistence) RecordWorkflowExecutionStarted(
+	request *RecordWorkflowExecutionStartedRequest) error {
+	err := v.store.db.Update(func(tx *bolt.Tx) error {
+		existing, err := getBoltVisibilityRecord(tx, request.DomainUUID, request.Execution.GetRunId())
+		// The closed record wins if the execution has already been recorded as closed
+		if err != nil || existing != nil {
+			return err
+		}
+
+		return putBoltVisibilityRecord(tx, request.DomainUUID, nil, &boltVisibilityRecord{
+			WorkflowID:       request.Execution.GetWorkflowId(),
+			RunID:            request.Execution.GetRunId(),
+			WorkflowTypeName: request.WorkflowTypeName,
+			StartTime:        request.StartTimestamp,
+			SearchAttributes: copySearchAttributes(request.SearchAttributes),
+		})
+	})
+	if err != nil {
+		return newBoltVisibilityError("RecordWorkflowExecutionStarted", err)
+	}
+	return nil
+}
+
+func (v *boltVisibilityPersistence) RecordWorkflowExecutionClosed(
+	request *RecordWorkflowExecutionClosedRequest) error {
+	record := &boltVisibilityRecord{
+		WorkflowID:       request.Execution.GetWorkflowId(),
+		RunID:            request.Execution.GetRunId(),
+		WorkflowTypeName: request.WorkflowTypeName,
+		StartTime:        request.StartTimestamp,
+		Closed:           true,
+		CloseTime:        request.CloseTimestamp,
+		CloseStatus:      int32(request.Status),
+		HistoryLength:    request.HistoryLength,
+		SearchAttributes: copySearchAttributes(request.SearchAttributes),
+	}
+	if request.RetentionSeconds > 0 {
+		record.ExpireTime = request.CloseTimestamp + request.RetentionSeconds*int64(time.Second)
+	}
+
+	err := v.store.db.Update(func(tx *bolt.Tx) error {
+		existing, err := getBoltVisibilityRecord(tx, request.DomainUUID, record.RunID)
+		if err != nil {
+			return err
+		}
+		if err := putBoltVisibilityRecord(tx, request.DomainUUID, existing, record); err != nil {
+			return err
+		}
+		return deleteExpiredBoltVisibilityRecords(tx, time.Now().UnixNano())
+	})
+	if err != nil {
+		return newBoltVisibilityError("RecordWorkflowExecutionClosed", err)
+	}
+	return nil
+}
+
+func (v *boltVisibilityPersistence) UpsertWorkflowExecution(request *UpsertWorkflowExecutionRequest) error {
+	err := v.store.db.Update(func(tx *bolt.Tx) error {
+		existing, err := getBoltVisibilityRecord(tx, request.DomainUUID, request.Execution.GetRunId())
+		if err != nil {
+			return err
+		}
+
+		if existing == nil {
+			// The started record has not been written yet
+			return putBoltVisibilityRecord(tx, request.DomainUUID, nil, &boltVisibilityRecord{
+				WorkflowID:       request.Execution.GetWorkflowId(),
+				RunID:            request.Execution.GetRunId(),
+				WorkflowTypeName: request.WorkflowTypeName,
+				StartTime:        request.StartTimestamp,
+				SearchAttributes: copySearchAttributes(request.SearchAttributes),
+			})
+		}
+
+		// The closed record already has the final search attributes
+		if existing.Closed {
+			return nil
+		}
+		record := *existing
+		record.SearchAttributes = copySearchAttributes(request.SearchAttributes)
+		return putBoltVisibilityRecord(tx, request.DomainUUID, existing, &record)
+	})
+	if err != nil {
+		return newBoltVisibilityError("UpsertWorkflowExecution", err)
+	}
+	return nil
+}
+
+func (v *boltVisibilityPersistence) ListOpenWorkflowExecutions(
+	request *ListWorkflowExecutionsRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListOpenWorkflowExecutions", request, boltVisibilityOpenState,
+		boltVisibilityStartTimeIndexBucket, newBoltKey(request.DomainUUID), nil)
+}
+
+func (v *boltVisibilityPersistence) ListClosedWorkflowExecutions(
+	request *ListWorkflowExecutionsRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListClosedWorkflowExecutions", request, boltVisibilityClosedState,
+		boltVisibilityStartTimeIndexBucket, newBoltKey(request.DomainUUID), nil)
+}
+
+func (v *boltVisibilityPersistence) ListOpenWorkflowExecutionsByType(
+	request *ListWorkflowExecutionsByTypeRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListOpenWorkflowExecutionsByType", &request.ListWorkflowExecutionsRequest,
+		boltVisibilityOpenState, boltVisibilityWorkflowTypeIndexBucket,
+		newBoltKey(request.DomainUUID, request.WorkflowTypeName), nil)
+}
+
+func (v *boltVisibilityPersistence) ListClosedWorkflowExecutionsByType(
+	request *ListWorkflowExecutionsByTypeRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListClosedWorkflowExecutionsByType", &request.ListWorkflowExecutionsRequest,
+		boltVisibilityClosedState, boltVisibilityWorkflowTypeIndexBucket,
+		newBoltKey(request.DomainUUID, request.WorkflowTypeName), nil)
+}
+
+func (v *boltVisibilityPersistence) ListOpenWorkflowExecutionsByWorkflowID(
+	request *ListWorkflowExecutionsByWorkflowIDRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListOpenWorkflowExecutionsByWorkflowID", &request.ListWorkflowExecutionsRequest,
+		boltVisibilityOpenState, boltVisibilityWorkflowIDIndexBucket,
+		newBoltKey(request.DomainUUID, request.WorkflowID), nil)
+}
+
+func (v *boltVisibilityPersistence) ListClosedWorkflowExecutionsByWorkflowID(
+	request *ListWorkflowExecutionsByWorkflowIDRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListClosedWorkflowExecutionsByWorkflowID",
+		&request.ListWorkflowExecutionsRequest, boltVisibilityClosedState, boltVisibilityWorkflowIDIndexBucket,
+		newBoltKey(request.DomainUUID, request.WorkflowID), nil)
+}
+
+func (v *boltVisibilityPersistence) ListOpenWorkflowExecutionsBySearchAttributes(
+	request *ListWorkflowExecutionsBySearchAttributesRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListOpenWorkflowExecutionsBySearchAttributes",
+		&request.ListWorkflowExecutionsRequest, boltVisibilityOpenState, boltVisibilityStartTimeIndexBucket,
+		newBoltKey(request.DomainUUID), func(record *boltVisibilityRecord) bool {
+			return hasSearchAttributes(record.SearchAttributes, request.SearchAttributes)
+		})
+}
+
+func (v *boltVisibilityPersistence) ListClosedWorkflowExecutionsBySearchAttributes(
+	request *ListWorkflowExecutionsBySearchAttributesRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListClosedWorkflowExecutionsBySearchAttributes",
+		&request.ListWorkflowExecutionsRequest, boltVisibilityClosedState, boltVisibilityStartTimeIndexBucket,
+		newBoltKey(request.DomainUUID), func(record *boltVisibilityRecord) bool {
+			return hasSearchAttributes(record.SearchAttributes, request.SearchAttributes)
+		})
+}
+
+func (v *boltVisibilityPersistence) ListClosedWorkflowExecutionsByStatus(
+	request *ListClosedWorkflowExecutionsByStatusRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListClosedWorkflowExecutionsByStatus", &request.ListWorkflowExecutionsRequest,
+		boltVisibilityClosedState, boltVisibilityStartTimeIndexBucket, newBoltKey(request.DomainUUID),
+		func(record *boltVisibilityRecord) bool {
+			return record.CloseStatus == int32(request.Status)
+		})
+}
+
+func (v *boltVisibilityPersistence) ListWorkflowExecutions(
+	request *ListWorkflowExecutionsByQueryRequest) (*ListWorkflowExecutionsResponse, error) {
+	token := &boltVisibilityQueryPageToken{}
+	if len(request.NextPageToken) > 0 {
+		if err := json.Unmarshal(request.NextPageToken, token); err != nil {
+			return nil, &workflow.BadRequestError{
+				Message: fmt.Sprintf("ListWorkflowExecutions operation failed.  Invalid next page token. Error: %v",
+					err),
+			}
+		}
+	}
+
+	var records []*boltVisibilityRecord
+	err := v.store.db.View(func(tx *bolt.Tx) error {
+		var err error
+		records, err = queryBoltVisibilityRecords(tx, request.DomainUUID, request.Query)
+		return err
+	})
+	if err != nil {
+		return nil, newBoltVisibilityError("ListWorkflowExecutions", err)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return request.Query.Less(records[i].toQueryRecord(), records[j].toQueryRecord())
+	})
+	if token.Offset >= len(records) {
+		records = nil
+	} else {
+		records = records[token.Offset:]
+	}
+	if request.PageSize > 0 && len(records) > request.PageSize {
+		records = records[:request.PageSize]
+	}
+
+	response := &ListWorkflowExecutionsResponse{}
+	for _, record := range records {
+		response.Executions = append(response.Executions, record.toWorkflowExecutionInfo())
+	}
+
+	if request.PageSize > 0 && len(records) == request.PageSize {
+		nextPageToken, err := json.Marshal(&boltVisibilityQueryPageToken{Offset: token.Offset + len(records)})
+		if err != nil {
+			return nil, newBoltVisibilityError("ListWorkflowExecutions", err)
+		}
+		response.NextPageToken = nextPageToken
+	}
+
+	return response, nil
+}
+
+func (v *boltVisibilityPersistence) CountWorkflowExecutions(
+	request *CountWorkflowExecutionsRequest) (*CountWorkflowExecutionsResponse, error) {
+	var records []*boltVisibilityRecord
+	err := v.store.db.View(func(tx *bolt.Tx) error {
+		var err error
+		records, err = queryBoltVisibilityRecords(tx, request.DomainUUID, request.Query)
+		return err
+	})
+	if err != nil {
+		return nil, newBoltVisibilityError("CountWorkflowExecutions", err)
+	}
+
+	return &CountWorkflowExecutionsResponse{Count: int64(len(records))}, nil
+}
+
+func (v *boltVisibilityPersistence) GetClosedWorkflowExecution(
+	request *GetClosedWorkflowExecutionRequest) (*GetClosedWorkflowExecutionResponse, error) {
+	execution := request.Execution
+
+	var record *boltVisibilityRecord
+	err := v.store.db.View(func(tx *bolt.Tx) error {
+		var err error
+		record, err = getBoltVisibilityRecord(tx, request.DomainUUID, execution.GetRunId())
+		return err
+	})
+	if err != nil {
+		return nil, newBoltVisibilityError("GetClosedWorkflowExecution", err)
+	}
+
+	if record == nil || !record.Closed || record.WorkflowID != execution.GetWorkflowId() {
+		return nil, &workflow.EntityNotExistsError{
+			Message: fmt.Sprintf("Workflow execution not found.  WorkflowId: %v, RunId: %v",
+				execution.GetWorkflowId(), execution.GetRunId()),
+		}
+	}
+
+	return &GetClosedWorkflowExecutionResponse{
+		Execution: record.toWorkflowExecutionInfo(),
+	}, nil
+}
+
+// listWorkflowExecutions returns a page of executions ordered by start time, newest first.  The executions are read
+// from an index keyed by the prefix followed by the start time, filter is optional and further restricts the
+// executions to the ones it accepts.
+func (v *boltVisibilityPersistence) listWorkflowExecutions(operation string,
+	request *ListWorkflowExecutionsRequest, state byte, bucket []byte, prefix []byte,
+	filter func(record *boltVisibilityRecord) bool) (*ListWorkflowExecutionsResponse, error) {
+	lower := appendBoltInt64(copyBoltKey(prefix), request.EarliestStartTime)
+	// Run IDs never contain the 0xff byte
+	upper := append(appendBoltInt64(copyBoltKey(prefix), request.LatestStartTime), 0xff)
+	if len(request.NextPageToken) > 0 {
+		token := &boltVisibilityPageToken{}
+		if err := json.Unmarshal(request.NextPageToken, token); err != nil {
+			return nil, &workflow.BadRequestError{
+				Message: fmt.Sprintf("%v operation failed.  Invalid next page token. Error: %v", operation, err),
+			}
+		}
+		upper = append(appendBoltInt64(copyBoltKey(prefix), token.StartTime), token.RunID...)
+	}
+
+	var records []*boltVisibilityRecord
+	err := v.store.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+		// Seek positions the cursor on the first key after the range, if any
+		k, value := c.Seek(upper)
+		if k == nil {
+			k, value = c.Last()
+		} else {
+			k, value = c.Prev()
+		}
+
+		for ; k != nil && bytes.Compare(k, lower) >= 0; k, value = c.Prev() {
+			if request.PageSize > 0 && len(records) == request.PageSize {
+				return nil
+			}
+			if value[0] != state {
+				continue
+			}
+			record, err := getBoltVisibilityRecord(tx, request.DomainUUID, string(value[1:]))
+			if err != nil {
+				return err
+			}
+			if record != nil && (filter == nil || filter(record)) {
+				records = append(records, record)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, newBoltVisibilityError(operation, err)
+	}
+
+	response := &ListWorkflowExecutionsResponse{}
+	for _, record := range records {
+		response.Executions = append(response.Executions, record.toWorkflowExecutionInfo())
+	}
+
+	if request.PageSize > 0 && len(records) == request.PageSize {
+		lastRecord := records[len(records)-1]
+		nextPageToken, err := json.Marshal(&boltVisibilityPageToken{
+			StartTime: lastRecord.StartTime,
+			RunID:     lastRecord.RunID,
+		})
+		if err != nil {
+			return nil, newBoltVisibilityError(operation, err)
+		}
+		response.NextPageToken = nextPageToken
+	}
+
+	return response, nil
+}
+
+// queryBoltVisibilityRecords returns the open and closed executions of the domain which match the query.  The
+// candidates are read from the index which narrows them down the most, see getBoltVisibilityIndexScan.
+func queryBoltVisibilityRecords(tx *bolt.Tx, domainID string,
+	query *visibilityquery.Query) ([]*boltVisibilityRecord, error) {
+	scan := getBoltVisibilityIndexScan(domainID, query.Filter)
+
+	var records []*boltVisibilityRecord
+	c := tx.Bucket(scan.bucket).Cursor()
+	for k, value := c.Seek(scan.lower); k != nil; k, value = c.Next() {
+		if scan.upper != nil && bytes.Compare(k, scan.upper) >= 0 {
+			break
+		}
+		record, err := getBoltVisibilityRecord(tx, domainID, string(value[1:]))
+		if err != nil {
+			return nil, err
+		}
+		if record != nil && query.Matches(record.toQueryRecord()) {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// getBoltVisibilityIndexScan returns the index range holding the candidates of a filter.  Only the conditions which
+// all the matching records must satisfy, the ones joined by the top level ANDs, can narrow down the range.  They are
+// used in order of selectivity: workflow ID, workflow ID prefix, workflow type, start time range and close time
+// range.  The close time index holds the closed executions only, it is also used when the filter requires a close
+// field to be set.  The start time index of the whole domain is scanned if nothing else applies.
+func getBoltVisibilityIndexScan(domainID string, filter visibilityquery.Expression) *boltVisibilityIndexScan {
+	conditions := getConjunctions(filter, nil)
+
+	var workflowIDPrefix, workflowTypeScan *boltVisibilityIndexScan
+	requiresClosed := false
+	for _, condition := range conditions {
+		switch c := condition.(type) {
+		case *visibilityquery.ComparisonExpression:
+			if isBoltVisibilityCloseField(c.Field) {
+				// Like in SQL, a comparison on a field which is not set is false
+				requiresClosed = true
+			}
+			if c.Operator != visibilityquery.OperatorEqual {
+				continue
+			}
+			switch c.Field {
+			case visibilityquery.FieldWorkflowID:
+				return newBoltVisibilityPrefixScan(boltVisibilityWorkflowIDIndexBucket,
+					newBoltKey(domainID, c.Value.(string)))
+			case visibilityquery.FieldWorkflowType:
+				workflowTypeScan = newBoltVisibilityPrefixScan(boltVisibilityWorkflowTypeIndexBucket,
+					newBoltKey(domainID, c.Value.(string)))
+			}
+		case *visibilityquery.PrefixExpression:
+			// The prefix is not terminated, so longer workflow IDs match
+			workflowIDPrefix = newBoltVisibilityPrefixScan(boltVisibilityWorkflowIDIndexBucket,
+				append(newBoltKey(domainID), c.Prefix...))
+		case *visibilityquery.NullExpression:
+			if c.Not && isBoltVisibilityCloseField(c.Field) {
+				requiresClosed = true
+			}
+		}
+	}
+	switch {
+	case workflowIDPrefix != nil:
+		return workflowIDPrefix
+	case workflowTypeScan != nil:
+		return workflowTypeScan
+	}
+
+	if lower, upper, ok := getBoltVisibilityTimeRange(conditions, visibilityquery.FieldStartTime); ok {
+		return newBoltVisibilityTimeScan(boltVisibilityStartTimeIndexBucket, newBoltKey(domainID), lower, upper)
+	}
+	if lower, upper, ok := getBoltVisibilityTimeRange(conditions, visibilityquery.FieldCloseTime); ok {
+		return newBoltVisibilityTimeScan(boltVisibilityCloseTimeIndexBucket, newBoltKey(domainID), lower, upper)
+	}
+	if requiresClosed {
+		return newBoltVisibilityPrefixScan(boltVisibilityCloseTimeIndexBucket, newBoltKey(domainID))
+	}
+	return newBoltVisibilityPrefixScan(boltVisibilityStartTimeIndexBucket, newBoltKey(domainID))
+}
+
+// getConjunctions appends the operands of the top level ANDs of the expression to conditions
+func getConjunctions(expression visibilityquery.Expression,
+	conditions []visibilityquery.Expression) []visibilityquery.Expression {
+	switch e := expression.(type) {
+	case nil:
+		return conditions
+	case *visibilityquery.AndExpression:
+		return getConjunctions(e.Right, getConjunctions(e.Left, conditions))
+	default:
+		return append(conditions, expression)
+	}
+}
+
+// getBoltVisibilityTimeRange returns the inclusive range of a time field the conditions restrict it to, ok is false
+// if none of them does
+func getBoltVisibilityTimeRange(conditions []visibilityquery.Expression,
+	field visibilityquery.Field) (lower int64, upper int64, ok bool) {
+	lower, upper = math.MinInt64, math.MaxInt64
+	for _, condition := range conditions {
+		c, isComparison := condition.(*visibilityquery.ComparisonExpression)
+		if !isComparison || c.Field != field {
+			continue
+		}
+
+		value := c.Value.(int64)
+		conditionLower, conditionUpper := int64(math.MinInt64), int64(math.MaxInt64)
+		switch c.Operator {
+		case visibilityquery.OperatorEqual:
+			conditionLower, conditionUpper = value, value
+		case visibilityquery.OperatorGreaterThanOrEqual:
+			conditionLower = value
+		case visibilityquery.OperatorLessThanOrEqual:
+			conditionUpper = value
+		case visibilityquery.OperatorGreaterThan:
+			if value == math.MaxInt64 {
+				return 0, -1, true
+			}
+			conditionLower = value + 1
+		case visibilityquery.OperatorLessThan:
+			if value == math.MinInt64 {
+				return 0, -1, true
+			}
+			conditionUpper = value - 1
+		default:
+			continue
+		}
+		if conditionLower > lower {
+			lower = conditionLower
+		}
+		if conditionUpper < upper {
+			upper = conditionUpper
+		}
+		ok = true
+	}
+	return lower, upper, ok
+}
+
+func isBoltVisibilityCloseField(field visibilityquery.Field) bool {
+	return field == visibilityquery.FieldCloseStatus || field == visibilityquery.FieldCloseTime ||
+		field == visibilityquery.FieldHistoryLength
+}
+
+// newBoltVisibilityPrefixScan returns the scan of the index keys starting with the prefix
+func newBoltVisibilityPrefixScan(bucket []byte, prefix []byte) *boltVisibilityIndexScan {
+	upper := copyBoltKey(prefix)
+	for len(upper) > 0 && upper[len(upper)-1] == 0xff {
+		upper = upper[:len(upper)-1]
+	}
+	if len(upper) == 0 {
+		upper = nil
+	} else {
+		upper[len(upper)-1]++
+	}
+	return &boltVisibilityIndexScan{bucket: bucket, lower: prefix, upper: upper}
+}
+
+// newBoltVisibilityTimeScan returns the scan of the index keys made of the prefix followed by a time within the
+// inclusive range
+func newBoltVisibilityTimeScan(bucket []byte, prefix []byte, lower, upper int64) *boltVisibilityIndexScan {
+	if lower > upper {
+		return &boltVisibilityIndexScan{bucket: bucket, lower: prefix, upper: prefix}
+	}
+	return &boltVisibilityIndexScan{
+		bucket: bucket,
+		lower:  appendBoltInt64(copyBoltKey(prefix), lower),
+		// Run IDs never contain the 0xff byte
+		upper: append(appendBoltInt64(copyBoltKey(prefix), upper), 0xff),
+	}
+}
+
+func getBoltVisibilityRecord(tx *bolt.Tx, domainID, runID string) (*boltVisibilityRecord, error) {
+	data := tx.Bucket(boltVisibilityRecordsBucket).Get(newBoltVisibilityRecordKey(domainID, runID))
+	if data == nil {
+		return nil, nil
+	}
+
+	record := &boltVisibilityRecord{}
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// putBoltVisibilityRecord writes the record and its index entries, replacing the ones of the existing record if any
+func putBoltVisibilityRecord(tx *bolt.Tx, domainID string, existing, record *boltVisibilityRecord) error {
+	if existing != nil {
+		if err := deleteBoltVisibilityRecord(tx, domainID, existing); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := tx.Bucket(boltVisibilityRecordsBucket).Put(newBoltVisibilityRecordKey(domainID, record.RunID),
+		data); err != nil {
+		return err
+	}
+	for _, entry := range record.indexEntries(domainID) {
+		if err := tx.Bucket(entry.bucket).Put(entry.key, entry.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteBoltVisibilityRecord(tx *bolt.Tx, domainID string, record *boltVisibilityRecord) error {
+	for _, entry := range record.indexEntries(domainID) {
+		if err := tx.Bucket(entry.bucket).Delete(entry.key); err != nil {
+			return err
+		}
+	}
+	return tx.Bucket(boltVisibilityRecordsBucket).Delete(newBoltVisibilityRecordKey(domainID, record.RunID))
+}
+
+// deleteExpiredBoltVisibilityRecords deletes a batch of the records whose retention is over, the first ones to
+// expire first
+func deleteExpiredBoltVisibilityRecords(tx *bolt.Tx, now int64) error {
+	expiryIndex := tx.Bucket(boltVisibilityExpiryIndexBucket)
+	for i := 0; i < boltVisibilityMaxExpiredRecordsPerWrite; i++ {
+		k, value := expiryIndex.Cursor().First()
+		if k == nil || decodeBoltInt64(k) > now {
+			return nil
+		}
+
+		separator := bytes.IndexByte(value, 0)
+		domainID, runID := string(value[:separator]), string(value[separator+1:])
+		record, err := getBoltVisibilityRecord(tx, domainID, runID)
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			// The entry is not expected to outlive its record, drop it anyway
+			if err := expiryIndex.Delete(copyBoltKey(k)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := deleteBoltVisibilityRecord(tx, domainID, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexEntries returns the entries of all the indexes pointing to the record
+func (r *boltVisibilityRecord) indexEntries(domainID string) []*boltVisibilityIndexEntry {
+	state := boltVisibilityOpenState
+	if r.Closed {
+		state = boltVisibilityClosedState
+	}
+	value := append([]byte{state}, r.RunID...)
+
+	entries := []*boltVisibilityIndexEntry{
+		{
+			bucket: boltVisibilityStartTimeIndexBucket,
+			key:    append(appendBoltInt64(newBoltKey(domainID), r.StartTime), r.RunID...),
+			value:  value,
+		},
+		{
+			bucket: boltVisibilityWorkflowIDIndexBucket,
+			key:    append(appendBoltInt64(newBoltKey(domainID, r.WorkflowID), r.StartTime), r.RunID...),
+			value:  value,
+		},
+		{
+			bucket: boltVisibilityWorkflowTypeIndexBucket,
+			key:    append(appendBoltInt64(newBoltKey(domainID, r.WorkflowTypeName), r.StartTime), r.RunID...),
+			value:  value,
+		},
+	}
+	if r.Closed {
+		entries = append(entries, &boltVisibilityIndexEntry{
+			bucket: boltVisibilityCloseTimeIndexBucket,
+			key:    append(appendBoltInt64(newBoltKey(domainID), r.CloseTime), r.RunID...),
+			value:  value,
+		})
+	}
+	if r.ExpireTime > 0 {
+		entries = append(entries, &boltVisibilityIndexEntry{
+			bucket: boltVisibilityExpiryIndexBucket,
+			key:    append(appendBoltInt64(nil, r.ExpireTime), newBoltVisibilityRecordKey(domainID, r.RunID)...),
+			value:  newBoltVisibilityRecordKey(domainID, r.RunID),
+		})
+	}
+	return entries
+}
+
+func (r *boltVisibilityRecord) toWorkflowExecutionInfo() *workflow.WorkflowExecutionInfo {
+	info := &workflow.WorkflowExecutionInfo{
+		Execution: &workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr(r.WorkflowID),
+			RunId:      common.StringPtr(r.RunID),
+		},
+		Type:             &workflow.WorkflowType{Name: common.StringPtr(r.WorkflowTypeName)},
+		StartTime:        common.Int64Ptr(r.StartTime),
+		SearchAttributes: getSearchAttributes(r.SearchAttributes),
+	}
+	if r.Closed {
+		status := workflow.WorkflowExecutionCloseStatus(r.CloseStatus)
+		info.CloseTime = common.Int64Ptr(r.CloseTime)
+		info.CloseStatus = &status
+		info.HistoryLength = common.Int64Ptr(r.HistoryLength)
+	}
+	return info
+}
+
+func (r *boltVisibilityRecord) toQueryRecord() *visibilityquery.Record {
+	return &visibilityquery.Record{
+		WorkflowID:    r.WorkflowID,
+		RunID:         r.RunID,
+		WorkflowType:  r.WorkflowTypeName,
+		StartTime:     r.StartTime,
+		Closed:        r.Closed,
+		CloseTime:     r.CloseTime,
+		CloseStatus:   int64(r.CloseStatus),
+		HistoryLength: r.HistoryLength,
+	}
+}
+
+func newBoltVisibilityRecordKey(domainID, runID string) []byte {
+	return append(newBoltKey(domainID), runID...)
+}
+
+// newBoltKey returns a key made of the zero terminated strings
+func newBoltKey(values ...string) []byte {
+	var key []byte
+	for _, value := range values {
+		key = append(append(key, value...), 0)
+	}
+	return key
+}
+
+// copyBoltKey returns a copy of the key which can be appended to without modifying the key
+func copyBoltKey(key []byte) []byte {
+	return append([]byte(nil), key...)
+}
+
+func appendBoltInt64(key []byte, value int64) []byte {
+	var encoded [8]byte
+	binary.BigEndian.PutUint64(encoded[:], uint64(value)^(1<<63))
+	return append(key, encoded[:]...)
+}
+
+func decodeBoltInt64(key []byte) int64 {
+	return int64(binary.BigEndian.Uint64(key) ^ (1 << 63))
+}
+
+func newBoltVisibilityError(operation string, err error) error {
+	return &workflow.InternalServiceError{
+		Message: fmt.Sprintf("%v operation failed. Error: %v", operation, err),
+	}
+}
@@ -41,7 +41,9 @@ const (
 
 	templateDomainConfigType = `{` +
 		`retention: ?, ` +
-		`emit_metric: ?` +
+		`emit_metric: ?, ` +
+		`archival_bucket: ?, ` +
+		`archival_status: ?` +
 		`}`
 
 	templateDomainReplicationConfigType = `{` +
@@ -62,7 +64,7 @@ const (
 		`WHERE id = ?`
 
 	templateGetDomainByNameQuery = `SELECT domain.id, domain.name, domain.status, domain.description, ` +
-		`domain.owner_email, config.retention, config.emit_metric, ` +
+		`domain.owner_email, config.retention, config.emit_metric, config.archival_bucket, config.archival_status, ` +
 		`replication_config.active_cluster_name, replication_config.clusters, ` +
 		`is_global_domain, ` +
 		`config_version, ` +
@@ -146,6 +148,8 @@ func (m *cassandraMetadataPersistence) CreateDomain(request *CreateDomainRequest
 		request.Info.OwnerEmail,
 		request.Config.Retention,
 		request.Config.EmitMetric,
+		request.Config.ArchivalBucket,
+		request.Config.ArchivalStatus,
 		request.ReplicationConfig.ActiveClusterName,
 		serializeClusterConfigs(request.ReplicationConfig.Clusters),
 		request.IsGlobalDomain,
@@ -238,6 +242,8 @@ func (m *cassandraMetadataPersistence) GetDomain(request *GetDomainRequest) (*Ge
 		&info.OwnerEmail,
 		&config.Retention,
 		&config.EmitMetric,
+		&config.ArchivalBucket,
+		&config.ArchivalStatus,
 		&replicationConfig.ActiveClusterName,
 		&replicationClusters,
 		&isGlobalDomain,
@@ -280,6 +286,8 @@ func (m *cassandraMetadataPersistence) UpdateDomain(request *UpdateDomainRequest
 		request.Info.OwnerEmail,
 		request.Config.Retention,
 		request.Config.EmitMetric,
+		request.Config.ArchivalBucket,
+		request.Config.ArchivalStatus,
 		request.ReplicationConfig.ActiveClusterName,
 		serializeClusterConfigs(request.ReplicationConfig.Clusters),
 		request.ConfigVersion,
@@ -287,11 +287,15 @@ func (m *metadataPersistenceSuite) TestUpdateDomain() {
 
 	resp2, err2 := m.GetDomain(id, "")
 	m.Nil(err2)
+	m.Equal("", resp2.Config.ArchivalBucket)
+	m.Equal(gen.ArchivalStatusDisabled, resp2.Config.ArchivalStatus)
 	updatedStatus := DomainStatusDeprecated
 	updatedDescription := "description-updated"
 	updatedOwner := "owner-updated"
 	updatedRetention := int32(20)
 	updatedEmitMetric := false
+	updatedArchivalBucket := "update-domain-test-bucket"
+	updatedArchivalStatus := gen.ArchivalStatusEnabled
 
 	updateClusterActive := "other random active cluster name"
 	updateClusterStandby := "other random standby cluster name"
@@ -315,8 +319,10 @@ func (m *metadataPersistenceSuite) TestUpdateDomain() {
 			OwnerEmail:  updatedOwner,
 		},
 		&DomainConfig{
-			Retention:  updatedRetention,
-			EmitMetric: updatedEmitMetric,
+			Retention:      updatedRetention,
+			EmitMetric:     updatedEmitMetric,
+			ArchivalBucket: updatedArchivalBucket,
+			ArchivalStatus: updatedArchivalStatus,
 		},
 		&DomainReplicationConfig{
 			ActiveClusterName: updateClusterActive,
@@ -339,6 +345,8 @@ func (m *metadataPersistenceSuite) TestUpdateDomain() {
 	m.Equal(updatedOwner, resp4.Info.OwnerEmail)
 	m.Equal(updatedRetention, resp4.Config.Retention)
 	m.Equal(updatedEmitMetric, resp4.Config.EmitMetric)
+	m.Equal(updatedArchivalBucket, resp4.Config.ArchivalBucket)
+	m.Equal(updatedArchivalStatus, resp4.Config.ArchivalStatus)
 	m.Equal(updateClusterActive, resp4.ReplicationConfig.ActiveClusterName)
 	m.Equal(len(updateClusters), len(resp4.ReplicationConfig.Clusters))
 	for index := range clusters {
@@ -358,6 +366,8 @@ func (m *metadataPersistenceSuite) TestUpdateDomain() {
 	m.Equal(updatedOwner, resp5.Info.OwnerEmail)
 	m.Equal(updatedRetention, resp5.Config.Retention)
 	m.Equal(updatedEmitMetric, resp5.Config.EmitMetric)
+	m.Equal(updatedArchivalBucket, resp5.Config.ArchivalBucket)
+	m.Equal(updatedArchivalStatus, resp5.Config.ArchivalStatus)
 	m.Equal(updateClusterActive, resp5.ReplicationConfig.ActiveClusterName)
 	m.Equal(len(updateClusters), len(resp5.ReplicationConfig.Clusters))
 	for index := range clusters {
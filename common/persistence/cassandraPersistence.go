@@ -22,6 +22,7 @@ package persistence
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -96,6 +97,8 @@ const (
 const (
 	taskListTaskID = -12345
 	initialRangeID = 1 // Id of the first range of a new task list
+	// listTaskListMaxScannedRows bounds the task list rows of all the domains which one ListTaskList call reads
+	listTaskListMaxScannedRows = 1000
 )
 
 const (
@@ -146,7 +149,19 @@ const (
 		`sticky_schedule_to_start_timeout: ?,` +
 		`client_library_version: ?, ` +
 		`client_feature_version: ?, ` +
-		`client_impl: ?` +
+		`client_impl: ?, ` +
+		`attempt: ?, ` +
+		`has_retry_policy: ?, ` +
+		`init_interval: ?, ` +
+		`backoff_coefficient: ?, ` +
+		`max_interval: ?, ` +
+		`expiration_time: ?, ` +
+		`max_attempts: ?, ` +
+		`non_retriable_errors: ?, ` +
+		`cron_schedule: ?, ` +
+		`search_attributes: ?, ` +
+		`priority: ?, ` +
+		`build_id: ?` +
 		`}`
 
 	templateReplicationStateType = `{` +
@@ -212,7 +227,16 @@ const (
 		`cancel_requested: ?, ` +
 		`cancel_request_id: ?, ` +
 		`last_hb_updated_time: ?, ` +
-		`timer_task_status: ?` +
+		`timer_task_status: ?, ` +
+		`attempt: ?, ` +
+		`has_retry_policy: ?, ` +
+		`init_interval: ?, ` +
+		`backoff_coefficient: ?, ` +
+		`max_interval: ?, ` +
+		`expiration_time: ?, ` +
+		`max_attempts: ?, ` +
+		`non_retriable_errors: ?, ` +
+		`priority: ?` +
 		`}`
 
 	templateTimerInfoType = `{` +
@@ -253,14 +277,21 @@ const (
 		`name: ?, ` +
 		`type: ?, ` +
 		`ack_level: ?, ` +
-		`kind: ? ` +
+		`kind: ?, ` +
+		`compatible_build_ids: ?, ` +
+		`retired_build_ids: ?, ` +
+		`paused: ?, ` +
+		`last_updated: ?` +
 		`}`
 
 	templateTaskType = `{` +
 		`domain_id: ?, ` +
 		`workflow_id: ?, ` +
 		`run_id: ?, ` +
-		`schedule_id: ?` +
+		`schedule_id: ?, ` +
+		`priority: ?, ` +
+		`build_id: ?, ` +
+		`expiry: ?` +
 		`}`
 
 	templateCreateShardQuery = `INSERT INTO executions (` +
@@ -376,7 +407,7 @@ const (
 		`and task_id = ? ` +
 		`IF next_event_id = ?`
 
-	templateUpdateActivityInfoQuery = `UPDATE executions ` +
+	templateCreateActivityInfoQuery = `UPDATE executions ` +
 		`SET activity_map[ ? ] =` + templateActivityInfoType + ` ` +
 		`WHERE shard_id = ? ` +
 		`and type = ? ` +
@@ -384,10 +415,12 @@ const (
 		`and workflow_id = ? ` +
 		`and run_id = ? ` +
 		`and visibility_ts = ? ` +
-		`and task_id = ? ` +
+		`and task_id = ? `
+
+	templateUpdateActivityInfoQuery = templateCreateActivityInfoQuery +
 		`IF next_event_id = ?`
 
-	templateUpdateTimerInfoQuery = `UPDATE executions ` +
+	templateCreateTimerInfoQuery = `UPDATE executions ` +
 		`SET timer_map[ ? ] =` + templateTimerInfoType + ` ` +
 		`WHERE shard_id = ? ` +
 		`and type = ? ` +
@@ -395,7 +428,9 @@ const (
 		`and workflow_id = ? ` +
 		`and run_id = ? ` +
 		`and visibility_ts = ? ` +
-		`and task_id = ? ` +
+		`and task_id = ? `
+
+	templateUpdateTimerInfoQuery = templateCreateTimerInfoQuery +
 		`IF next_event_id = ?`
 
 	templateUpdateChildExecutionInfoQuery = `UPDATE executions ` +
@@ -442,6 +477,16 @@ const (
 		`and task_id = ? ` +
 		`IF next_event_id = ?`
 
+	templateCreateSignalRequestedQuery = `UPDATE executions ` +
+		`SET signal_requested = signal_requested + ? ` +
+		`WHERE shard_id = ? ` +
+		`and type = ? ` +
+		`and domain_id = ? ` +
+		`and workflow_id = ? ` +
+		`and run_id = ? ` +
+		`and visibility_ts = ? ` +
+		`and task_id = ? `
+
 	templateAppendBufferedEventsQuery = `UPDATE executions ` +
 		`SET buffered_events_list = buffered_events_list + ? ` +
 		`WHERE shard_id = ? ` +
@@ -543,6 +588,11 @@ const (
 		`and task_id = ? ` +
 		`IF next_event_id = ?`
 
+	templateListConcreteExecutionsQuery = `SELECT run_id, execution ` +
+		`FROM executions ` +
+		`WHERE shard_id = ? ` +
+		`and type = ?`
+
 	templateGetTransferTasksQuery = `SELECT transfer ` +
 		`FROM executions ` +
 		`WHERE shard_id = ? ` +
@@ -627,6 +677,17 @@ const (
 		`and type = ? ` +
 		`and task_id = ?`
 
+	// The task list rows of every domain are in the tasks table and partitioned by task list, so listing the task
+	// lists of a domain is a scan of the whole table which is filtered on the domain by the client.  Its cost grows
+	// with the number of task lists and tasks of the cluster, so it should only be used by admin tooling.
+	templateListTaskListQuery = `SELECT ` +
+		`range_id, ` +
+		`task_list ` +
+		`FROM tasks ` +
+		`WHERE type = ? ` +
+		`and task_id = ? ` +
+		`ALLOW FILTERING`
+
 	templateInsertTaskListQuery = `INSERT INTO tasks (` +
 		`domain_id, ` +
 		`task_list_name, ` +
@@ -1062,6 +1123,18 @@ func (d *cassandraPersistence) CreateWorkflowExecutionWithinBatch(request *Creat
 			"", // client_library_version
 			"", // client_feature_version
 			"", // client_impl
+			request.Attempt,
+			request.HasRetryPolicy,
+			request.InitialInterval,
+			request.BackoffCoefficient,
+			request.MaximumInterval,
+			request.ExpirationTime,
+			request.MaximumAttempts,
+			request.NonRetriableErrors,
+			request.CronSchedule,
+			request.SearchAttributes,
+			request.Priority,
+			request.BuildID,
 			request.NextEventID,
 			defaultVisibilityTimestamp,
 			rowTypeExecutionTaskID)
@@ -1111,6 +1184,18 @@ func (d *cassandraPersistence) CreateWorkflowExecutionWithinBatch(request *Creat
 			"", // client_library_version
 			"", // client_feature_version
 			"", // client_impl
+			request.Attempt,
+			request.HasRetryPolicy,
+			request.InitialInterval,
+			request.BackoffCoefficient,
+			request.MaximumInterval,
+			request.ExpirationTime,
+			request.MaximumAttempts,
+			request.NonRetriableErrors,
+			request.CronSchedule,
+			request.SearchAttributes,
+			request.Priority,
+			request.BuildID,
 			request.ReplicationState.CurrentVersion,
 			request.ReplicationState.StartVersion,
 			request.ReplicationState.LastWriteVersion,
@@ -1120,6 +1205,28 @@ func (d *cassandraPersistence) CreateWorkflowExecutionWithinBatch(request *Creat
 			defaultVisibilityTimestamp,
 			rowTypeExecutionTaskID)
 	}
+
+	// The new run does not exist before the batch is applied, so its maps are written without a condition
+	for _, a := range request.ActivityInfos {
+		batch.Query(templateCreateActivityInfoQuery, d.activityInfoQueryArgs(a, request.DomainID,
+			*request.Execution.WorkflowId, *request.Execution.RunId)...)
+	}
+	for _, t := range request.TimerInfos {
+		batch.Query(templateCreateTimerInfoQuery, d.timerInfoQueryArgs(t, request.DomainID,
+			*request.Execution.WorkflowId, *request.Execution.RunId)...)
+	}
+
+	if len(request.SignalRequestedIDs) > 0 {
+		batch.Query(templateCreateSignalRequestedQuery,
+			request.SignalRequestedIDs,
+			d.shardID,
+			rowTypeExecution,
+			request.DomainID,
+			*request.Execution.WorkflowId,
+			*request.Execution.RunId,
+			defaultVisibilityTimestamp,
+			rowTypeExecutionTaskID)
+	}
 }
 
 func (d *cassandraPersistence) GetWorkflowExecution(request *GetWorkflowExecutionRequest) (
@@ -1260,6 +1367,18 @@ func (d *cassandraPersistence) UpdateWorkflowExecution(request *UpdateWorkflowEx
 			executionInfo.ClientLibraryVersion,
 			executionInfo.ClientFeatureVersion,
 			executionInfo.ClientImpl,
+			executionInfo.Attempt,
+			executionInfo.HasRetryPolicy,
+			executionInfo.InitialInterval,
+			executionInfo.BackoffCoefficient,
+			executionInfo.MaximumInterval,
+			executionInfo.ExpirationTime,
+			executionInfo.MaximumAttempts,
+			executionInfo.NonRetriableErrors,
+			executionInfo.CronSchedule,
+			executionInfo.SearchAttributes,
+			executionInfo.Priority,
+			executionInfo.BuildID,
 			executionInfo.NextEventID,
 			d.shardID,
 			rowTypeExecution,
@@ -1310,6 +1429,18 @@ func (d *cassandraPersistence) UpdateWorkflowExecution(request *UpdateWorkflowEx
 			executionInfo.ClientLibraryVersion,
 			executionInfo.ClientFeatureVersion,
 			executionInfo.ClientImpl,
+			executionInfo.Attempt,
+			executionInfo.HasRetryPolicy,
+			executionInfo.InitialInterval,
+			executionInfo.BackoffCoefficient,
+			executionInfo.MaximumInterval,
+			executionInfo.ExpirationTime,
+			executionInfo.MaximumAttempts,
+			executionInfo.NonRetriableErrors,
+			executionInfo.CronSchedule,
+			executionInfo.SearchAttributes,
+			executionInfo.Priority,
+			executionInfo.BuildID,
 			replicationState.CurrentVersion,
 			replicationState.StartVersion,
 			replicationState.LastWriteVersion,
@@ -1540,6 +1671,48 @@ func (d *cassandraPersistence) GetCurrentExecution(request *GetCurrentExecutionR
 	}, nil
 }
 
+func (d *cassandraPersistence) ListConcreteExecutions(request *ListConcreteExecutionsRequest) (
+	*ListConcreteExecutionsResponse, error) {
+	query := d.session.Query(templateListConcreteExecutionsQuery,
+		d.shardID,
+		rowTypeExecution)
+
+	iter := query.PageSize(request.PageSize).PageState(request.NextPageToken).Iter()
+	if iter == nil {
+		return nil, &workflow.InternalServiceError{
+			Message: "ListConcreteExecutions operation failed.  Not able to create query iterator.",
+		}
+	}
+
+	response := &ListConcreteExecutionsResponse{}
+	result := make(map[string]interface{})
+	for iter.MapScan(result) {
+		// The current execution pointer of a workflow is an execution row keyed by the permanent run ID
+		if result["run_id"].(gocql.UUID).String() != permanentRunID {
+			response.ExecutionInfos = append(response.ExecutionInfos,
+				createWorkflowExecutionInfo(result["execution"].(map[string]interface{})))
+		}
+		// Reset result map to get it ready for next scan
+		result = make(map[string]interface{})
+	}
+	nextPageToken := iter.PageState()
+	response.NextPageToken = make([]byte, len(nextPageToken))
+	copy(response.NextPageToken, nextPageToken)
+
+	if err := iter.Close(); err != nil {
+		if isThrottlingError(err) {
+			return nil, &workflow.ServiceBusyError{
+				Message: fmt.Sprintf("ListConcreteExecutions operation failed. Error: %v", err),
+			}
+		}
+		return nil, &workflow.InternalServiceError{
+			Message: fmt.Sprintf("ListConcreteExecutions operation failed. Error: %v", err),
+		}
+	}
+
+	return response, nil
+}
+
 func (d *cassandraPersistence) GetTransferTasks(request *GetTransferTasksRequest) (*GetTransferTasksResponse, error) {
 
 	// Reading transfer tasks need to be quorum level consistent, otherwise we could loose task
@@ -1712,7 +1885,11 @@ func (d *cassandraPersistence) LeaseTaskList(request *LeaseTaskListRequest) (*Le
 		taskListTaskID,
 	)
 	var rangeID, ackLevel int64
+	var compatibleBuildIDs [][]string
+	var retiredBuildIDs []string
+	var paused bool
 	var tlDB map[string]interface{}
+	now := time.Now()
 	err := query.Scan(&rangeID, &tlDB)
 	if err != nil {
 		if err == gocql.ErrNotFound { // First time task list is used
@@ -1728,6 +1905,10 @@ func (d *cassandraPersistence) LeaseTaskList(request *LeaseTaskListRequest) (*Le
 				request.TaskType,
 				0,
 				request.TaskListKind,
+				nil,
+				nil,
+				false,
+				now,
 			)
 		} else if isThrottlingError(err) {
 			return nil, &workflow.ServiceBusyError{
@@ -1743,6 +1924,9 @@ func (d *cassandraPersistence) LeaseTaskList(request *LeaseTaskListRequest) (*Le
 	} else {
 		ackLevel = tlDB["ack_level"].(int64)
 		taskListKind := tlDB["kind"].(int)
+		compatibleBuildIDs, _ = tlDB["compatible_build_ids"].([][]string)
+		retiredBuildIDs, _ = tlDB["retired_build_ids"].([]string)
+		paused, _ = tlDB["paused"].(bool)
 		query = d.session.Query(templateUpdateTaskListQuery,
 			rangeID+1,
 			request.DomainID,
@@ -1750,6 +1934,10 @@ func (d *cassandraPersistence) LeaseTaskList(request *LeaseTaskListRequest) (*Le
 			request.TaskType,
 			ackLevel,
 			taskListKind,
+			compatibleBuildIDs,
+			retiredBuildIDs,
+			paused,
+			now,
 			request.DomainID,
 			&request.TaskList,
 			request.TaskType,
@@ -1776,7 +1964,8 @@ func (d *cassandraPersistence) LeaseTaskList(request *LeaseTaskListRequest) (*Le
 			Msg: fmt.Sprintf("LeaseTaskList failed to apply. db rangeID %v", previousRangeID),
 		}
 	}
-	tli := &TaskListInfo{DomainID: request.DomainID, Name: request.TaskList, TaskType: request.TaskType, RangeID: rangeID + 1, AckLevel: ackLevel, Kind: request.TaskListKind}
+	tli := &TaskListInfo{DomainID: request.DomainID, Name: request.TaskList, TaskType: request.TaskType, RangeID: rangeID + 1, AckLevel: ackLevel, Kind: request.TaskListKind,
+		CompatibleBuildIDs: compatibleBuildIDs, RetiredBuildIDs: retiredBuildIDs, Paused: paused, LastUpdated: now}
 	return &LeaseTaskListResponse{TaskListInfo: tli}, nil
 }
 
@@ -1797,6 +1986,10 @@ func (d *cassandraPersistence) UpdateTaskList(request *UpdateTaskListRequest) (*
 			tli.TaskType,
 			tli.AckLevel,
 			tli.Kind,
+			tli.CompatibleBuildIDs,
+			tli.RetiredBuildIDs,
+			tli.Paused,
+			time.Now(),
 			stickyTaskListTTL,
 		)
 		err := query.Exec()
@@ -1820,6 +2013,10 @@ func (d *cassandraPersistence) UpdateTaskList(request *UpdateTaskListRequest) (*
 		tli.TaskType,
 		tli.AckLevel,
 		tli.Kind,
+		tli.CompatibleBuildIDs,
+		tli.RetiredBuildIDs,
+		tli.Paused,
+		time.Now(),
 		tli.DomainID,
 		&tli.Name,
 		tli.TaskType,
@@ -1865,9 +2062,11 @@ func (d *cassandraPersistence) CreateTasks(request *CreateTasksRequest) (*Create
 	taskListKind := request.TaskListInfo.Kind
 	ackLevel := request.TaskListInfo.AckLevel
 
+	now := time.Now()
 	for _, task := range request.Tasks {
 		scheduleID := task.Data.ScheduleID
-		if task.Data.ScheduleToStartTimeout == 0 {
+		ttl := getTaskTTL(task.Data, now)
+		if ttl == 0 {
 			batch.Query(templateCreateTaskQuery,
 				domainID,
 				taskList,
@@ -1877,7 +2076,10 @@ func (d *cassandraPersistence) CreateTasks(request *CreateTasksRequest) (*Create
 				domainID,
 				task.Execution.GetWorkflowId(),
 				task.Execution.GetRunId(),
-				scheduleID)
+				scheduleID,
+				task.Data.Priority,
+				task.Data.BuildID,
+				task.Data.Expiry)
 		} else {
 			batch.Query(templateCreateTaskWithTTLQuery,
 				domainID,
@@ -1889,7 +2091,10 @@ func (d *cassandraPersistence) CreateTasks(request *CreateTasksRequest) (*Create
 				task.Execution.GetWorkflowId(),
 				task.Execution.GetRunId(),
 				scheduleID,
-				task.Data.ScheduleToStartTimeout)
+				task.Data.Priority,
+				task.Data.BuildID,
+				task.Data.Expiry,
+				ttl)
 		}
 	}
 
@@ -1901,6 +2106,10 @@ func (d *cassandraPersistence) CreateTasks(request *CreateTasksRequest) (*Create
 		taskListType,
 		ackLevel,
 		taskListKind,
+		request.TaskListInfo.CompatibleBuildIDs,
+		request.TaskListInfo.RetiredBuildIDs,
+		request.TaskListInfo.Paused,
+		time.Now(),
 		domainID,
 		taskList,
 		taskListType,
@@ -2006,6 +2215,67 @@ func (d *cassandraPersistence) CompleteTask(request *CompleteTaskRequest) error
 	return nil
 }
 
+// From TaskManager interface.  The tasks table is partitioned by task list, so there is no way to only read the task
+// lists of a domain: the task lists of every domain are scanned and the ones of other domains are skipped.  Pages are
+// read until the response holds PageSize task lists of the domain, the scan is over or listTaskListMaxScannedRows
+// rows were read.  The cost of one call is bounded, but a page can be short or even empty before the last one and
+// listing the task lists of a small domain pages through the task lists of the whole cluster.
+func (d *cassandraPersistence) ListTaskList(request *ListTaskListRequest) (*ListTaskListResponse, error) {
+	response := &ListTaskListResponse{}
+	pageToken := request.NextPageToken
+	scannedRows := 0
+	for {
+		// Never read more rows than the space left in the response so that no task list is skipped over by the
+		// page token of the next request
+		pageSize := request.PageSize - len(response.Items)
+		if pageSize > listTaskListMaxScannedRows-scannedRows {
+			pageSize = listTaskListMaxScannedRows - scannedRows
+		}
+		query := d.session.Query(templateListTaskListQuery,
+			rowTypeTaskList,
+			taskListTaskID)
+		iter := query.PageSize(pageSize).PageState(pageToken).Iter()
+		if iter == nil {
+			return nil, &workflow.InternalServiceError{
+				Message: "ListTaskList operation failed.  Not able to create query iterator.",
+			}
+		}
+
+		result := make(map[string]interface{})
+		for iter.MapScan(result) {
+			scannedRows++
+			info := createTaskListInfo(result["task_list"].(map[string]interface{}))
+			if info.DomainID == request.DomainID {
+				info.RangeID = result["range_id"].(int64)
+				response.Items = append(response.Items, info)
+			}
+			// Reset result map to get it ready for next scan
+			result = make(map[string]interface{})
+		}
+		nextPageToken := iter.PageState()
+		pageToken = make([]byte, len(nextPageToken))
+		copy(pageToken, nextPageToken)
+
+		if err := iter.Close(); err != nil {
+			if isThrottlingError(err) {
+				return nil, &workflow.ServiceBusyError{
+					Message: fmt.Sprintf("ListTaskList operation failed. Error: %v", err),
+				}
+			}
+			return nil, &workflow.InternalServiceError{
+				Message: fmt.Sprintf("ListTaskList operation failed. Error: %v", err),
+			}
+		}
+
+		if len(pageToken) == 0 || len(response.Items) >= request.PageSize || scannedRows >= listTaskListMaxScannedRows {
+			break
+		}
+	}
+
+	response.NextPageToken = pageToken
+	return response, nil
+}
+
 func (d *cassandraPersistence) GetTimerIndexTasks(request *GetTimerIndexTasksRequest) (*GetTimerIndexTasksResponse,
 	error) {
 	// Reading timer tasks need to be quorum level consistent, otherwise we could loose task
@@ -2101,7 +2371,7 @@ func (d *cassandraPersistence) createTransferTasks(batch *gocql.Batch, transferT
 			targetWorkflowID = task.(*StartChildExecutionTask).TargetWorkflowID
 			scheduleID = task.(*StartChildExecutionTask).InitiatedID
 
-		case TransferTaskTypeCloseExecution:
+		case TransferTaskTypeCloseExecution, TransferTaskTypeUpsertWorkflowSearchAttributes:
 			// No explicit property needs to be set
 
 		default:
@@ -2193,6 +2463,8 @@ func (d *cassandraPersistence) createTimerTasks(batch *gocql.Batch, timerTasks [
 			timeoutType = t.TimeoutType
 		case *UserTimerTask:
 			eventID = t.EventID
+		case *WorkflowBackoffTimerTask:
+			timeoutType = t.TimeoutType
 		}
 
 		ts := common.UnixNanoToCQLTimestamp(GetVisibilityTSFrom(task).UnixNano())
@@ -2234,32 +2506,7 @@ func (d *cassandraPersistence) updateActivityInfos(batch *gocql.Batch, activityI
 
 	for _, a := range activityInfos {
 		batch.Query(templateUpdateActivityInfoQuery,
-			a.ScheduleID,
-			a.ScheduleID,
-			a.ScheduledEvent,
-			a.ScheduledTime,
-			a.StartedID,
-			a.StartedEvent,
-			a.StartedTime,
-			a.ActivityID,
-			a.RequestID,
-			a.Details,
-			a.ScheduleToStartTimeout,
-			a.ScheduleToCloseTimeout,
-			a.StartToCloseTimeout,
-			a.HeartbeatTimeout,
-			a.CancelRequested,
-			a.CancelRequestID,
-			a.LastHeartBeatUpdatedTime,
-			a.TimerTaskStatus,
-			d.shardID,
-			rowTypeExecution,
-			domainID,
-			workflowID,
-			runID,
-			defaultVisibilityTimestamp,
-			rowTypeExecutionTaskID,
-			condition)
+			append(d.activityInfoQueryArgs(a, domainID, workflowID, runID), condition)...)
 	}
 
 	if deleteInfo != nil {
@@ -2281,19 +2528,7 @@ func (d *cassandraPersistence) updateTimerInfos(batch *gocql.Batch, timerInfos [
 
 	for _, a := range timerInfos {
 		batch.Query(templateUpdateTimerInfoQuery,
-			a.TimerID,
-			a.TimerID,
-			a.StartedID,
-			a.ExpiryTime,
-			a.TaskID,
-			d.shardID,
-			rowTypeExecution,
-			domainID,
-			workflowID,
-			runID,
-			defaultVisibilityTimestamp,
-			rowTypeExecutionTaskID,
-			condition)
+			append(d.timerInfoQueryArgs(a, domainID, workflowID, runID), condition)...)
 	}
 
 	for _, t := range deleteInfos {
@@ -2310,6 +2545,65 @@ func (d *cassandraPersistence) updateTimerInfos(batch *gocql.Batch, timerInfos [
 	}
 }
 
+// activityInfoQueryArgs returns the values bound to templateCreateActivityInfoQuery
+func (d *cassandraPersistence) activityInfoQueryArgs(a *ActivityInfo, domainID, workflowID,
+	runID string) []interface{} {
+	return []interface{}{
+		a.ScheduleID,
+		a.ScheduleID,
+		a.ScheduledEvent,
+		a.ScheduledTime,
+		a.StartedID,
+		a.StartedEvent,
+		a.StartedTime,
+		a.ActivityID,
+		a.RequestID,
+		a.Details,
+		a.ScheduleToStartTimeout,
+		a.ScheduleToCloseTimeout,
+		a.StartToCloseTimeout,
+		a.HeartbeatTimeout,
+		a.CancelRequested,
+		a.CancelRequestID,
+		a.LastHeartBeatUpdatedTime,
+		a.TimerTaskStatus,
+		a.Attempt,
+		a.HasRetryPolicy,
+		a.InitialInterval,
+		a.BackoffCoefficient,
+		a.MaximumInterval,
+		a.ExpirationTime,
+		a.MaximumAttempts,
+		a.NonRetriableErrors,
+		a.Priority,
+		d.shardID,
+		rowTypeExecution,
+		domainID,
+		workflowID,
+		runID,
+		defaultVisibilityTimestamp,
+		rowTypeExecutionTaskID,
+	}
+}
+
+// timerInfoQueryArgs returns the values bound to templateCreateTimerInfoQuery
+func (d *cassandraPersistence) timerInfoQueryArgs(t *TimerInfo, domainID, workflowID, runID string) []interface{} {
+	return []interface{}{
+		t.TimerID,
+		t.TimerID,
+		t.StartedID,
+		t.ExpiryTime,
+		t.TaskID,
+		d.shardID,
+		rowTypeExecution,
+		domainID,
+		workflowID,
+		runID,
+		defaultVisibilityTimestamp,
+		rowTypeExecutionTaskID,
+	}
+}
+
 func (d *cassandraPersistence) updateChildExecutionInfos(batch *gocql.Batch, childExecutionInfos []*ChildExecutionInfo,
 	deleteInfo *int64, domainID, workflowID, runID string, condition int64, rangeID int64) {
 
@@ -2591,6 +2885,30 @@ func createWorkflowExecutionInfo(result map[string]interface{}) *WorkflowExecuti
 			info.ClientFeatureVersion = v.(string)
 		case "client_impl":
 			info.ClientImpl = v.(string)
+		case "attempt":
+			info.Attempt = int32(v.(int))
+		case "has_retry_policy":
+			info.HasRetryPolicy = v.(bool)
+		case "init_interval":
+			info.InitialInterval = int32(v.(int))
+		case "backoff_coefficient":
+			info.BackoffCoefficient = v.(float64)
+		case "max_interval":
+			info.MaximumInterval = int32(v.(int))
+		case "expiration_time":
+			info.ExpirationTime = v.(time.Time)
+		case "max_attempts":
+			info.MaximumAttempts = int32(v.(int))
+		case "non_retriable_errors":
+			info.NonRetriableErrors = v.([]string)
+		case "cron_schedule":
+			info.CronSchedule = v.(string)
+		case "search_attributes":
+			info.SearchAttributes = v.(map[string][]byte)
+		case "priority":
+			info.Priority = int32(v.(int))
+		case "build_id":
+			info.BuildID = v.(string)
 		}
 	}
 
@@ -2730,6 +3048,24 @@ func createActivityInfo(result map[string]interface{}) *ActivityInfo {
 			info.LastHeartBeatUpdatedTime = v.(time.Time)
 		case "timer_task_status":
 			info.TimerTaskStatus = int32(v.(int))
+		case "attempt":
+			info.Attempt = int32(v.(int))
+		case "has_retry_policy":
+			info.HasRetryPolicy = v.(bool)
+		case "init_interval":
+			info.InitialInterval = int32(v.(int))
+		case "backoff_coefficient":
+			info.BackoffCoefficient = v.(float64)
+		case "max_interval":
+			info.MaximumInterval = int32(v.(int))
+		case "expiration_time":
+			info.ExpirationTime = v.(time.Time)
+		case "max_attempts":
+			info.MaximumAttempts = int32(v.(int))
+		case "non_retriable_errors":
+			info.NonRetriableErrors = v.([]string)
+		case "priority":
+			info.Priority = int32(v.(int))
 		}
 	}
 
@@ -2808,16 +3144,21 @@ func createSignalInfo(result map[string]interface{}) *SignalInfo {
 }
 
 func createSerializedHistoryEventBatch(result map[string]interface{}) *SerializedHistoryEventBatch {
-	// TODO: default to JSON, update this when we support different encoding types.
-	eventBatch := &SerializedHistoryEventBatch{EncodingType: common.EncodingTypeJSON}
+	eventBatch := &SerializedHistoryEventBatch{}
 	for k, v := range result {
 		switch k {
+		case "encoding_type":
+			eventBatch.EncodingType = common.EncodingType(v.(string))
 		case "version":
 			eventBatch.Version = v.(int)
 		case "data":
 			eventBatch.Data = v.([]byte)
 		}
 	}
+	// batches buffered before the encoding type was recorded are json encoded
+	if eventBatch.EncodingType == "" {
+		eventBatch.EncodingType = common.EncodingTypeJSON
+	}
 
 	return eventBatch
 }
@@ -2834,6 +3175,53 @@ func createTaskInfo(result map[string]interface{}) *TaskInfo {
 			info.RunID = v.(gocql.UUID).String()
 		case "schedule_id":
 			info.ScheduleID = v.(int64)
+		case "priority":
+			info.Priority = int32(v.(int))
+		case "build_id":
+			info.BuildID = v.(string)
+		case "expiry":
+			info.Expiry = v.(time.Time)
+		}
+	}
+
+	return info
+}
+
+// getTaskTTL returns the TTL in seconds of a task row, the row expires together with the task so expired tasks are
+// purged even if they are never read. Returns 0 if the task never expires.
+func getTaskTTL(task *TaskInfo, now time.Time) int64 {
+	if task.Expiry.IsZero() {
+		return int64(task.ScheduleToStartTimeout)
+	}
+	ttl := int64(math.Ceil(task.Expiry.Sub(now).Seconds()))
+	if ttl < 1 {
+		ttl = 1 // the task already expired, it is skipped when read until the row is purged
+	}
+	return ttl
+}
+
+func createTaskListInfo(result map[string]interface{}) *TaskListInfo {
+	info := &TaskListInfo{}
+	for k, v := range result {
+		switch k {
+		case "domain_id":
+			info.DomainID = v.(gocql.UUID).String()
+		case "name":
+			info.Name = v.(string)
+		case "type":
+			info.TaskType = v.(int)
+		case "ack_level":
+			info.AckLevel = v.(int64)
+		case "kind":
+			info.Kind = v.(int)
+		case "compatible_build_ids":
+			info.CompatibleBuildIDs = v.([][]string)
+		case "retired_build_ids":
+			info.RetiredBuildIDs = v.([]string)
+		case "paused":
+			info.Paused = v.(bool)
+		case "last_updated":
+			info.LastUpdated = v.(time.Time)
 		}
 	}
 
@@ -2926,6 +3314,9 @@ func GetVisibilityTSFrom(task Task) time.Time {
 
 	case TaskTypeDeleteHistoryEvent:
 		return task.(*DeleteHistoryEventTask).VisibilityTimestamp
+
+	case TaskTypeWorkflowBackoffTimer:
+		return task.(*WorkflowBackoffTimerTask).VisibilityTimestamp
 	}
 	return time.Time{}
 }
@@ -2947,5 +3338,8 @@ func SetVisibilityTSFrom(task Task, t time.Time) {
 
 	case TaskTypeDeleteHistoryEvent:
 		task.(*DeleteHistoryEventTask).VisibilityTimestamp = t
+
+	case TaskTypeWorkflowBackoffTimer:
+		task.(*WorkflowBackoffTimerTask).VisibilityTimestamp = t
 	}
 }
@@ -21,6 +21,7 @@
 package persistence
 
 import (
+	"fmt"
 	"os"
 	"testing"
 	"time"
@@ -50,6 +51,22 @@ func TestCassandraPersistenceSuite(t *testing.T) {
 	suite.Run(t, s)
 }
 
+func TestGetTaskTTL(t *testing.T) {
+	now := time.Now()
+	// without an expiry the schedule-to-start timeout is the TTL, a task without timeout never expires
+	require.Equal(t, int64(0), getTaskTTL(&TaskInfo{}, now))
+	require.Equal(t, int64(10), getTaskTTL(&TaskInfo{ScheduleToStartTimeout: 10}, now))
+
+	// the expiry takes precedence and partial seconds are rounded up so the row outlives the task
+	require.Equal(t, int64(5), getTaskTTL(&TaskInfo{ScheduleToStartTimeout: 10, Expiry: now.Add(5 * time.Second)}, now))
+	require.Equal(t, int64(2), getTaskTTL(&TaskInfo{Expiry: now.Add(1500 * time.Millisecond)}, now))
+	require.Equal(t, int64(1), getTaskTTL(&TaskInfo{Expiry: now.Add(time.Millisecond)}, now))
+
+	// an expired task gets the smallest TTL instead of one that never expires
+	require.Equal(t, int64(1), getTaskTTL(&TaskInfo{Expiry: now}, now))
+	require.Equal(t, int64(1), getTaskTTL(&TaskInfo{ScheduleToStartTimeout: 10, Expiry: now.Add(-time.Minute)}, now))
+}
+
 func (s *cassandraPersistenceSuite) SetupSuite() {
 	if testing.Verbose() {
 		log.SetOutput(os.Stdout)
@@ -385,6 +402,54 @@ func (s *cassandraPersistenceSuite) TestGetCurrentWorkflow() {
 	s.Empty(task1, "Expected empty task identifier.")
 }
 
+func (s *cassandraPersistenceSuite) TestListConcreteExecutions() {
+	domainID := "8f9d0b3e-6c1a-4d2b-9e7f-3a5c1b2d4e6f"
+	runIDs := map[string]bool{
+		"0f5e8b1c-2d3a-4b6c-8d7e-9f0a1b2c3d4e": false,
+		"1a6f9c2d-3e4b-4c7d-9e8f-0a1b2c3d4e5f": false,
+		"2b7a0d3e-4f5c-4d8e-8f9a-1b2c3d4e5f6a": false,
+	}
+	i := 0
+	for runID := range runIDs {
+		workflowExecution := gen.WorkflowExecution{
+			WorkflowId: common.StringPtr(fmt.Sprintf("list-concrete-executions-test-%v", i)),
+			RunId:      common.StringPtr(runID),
+		}
+		_, err := s.CreateWorkflowExecution(domainID, workflowExecution, "queue1", "wType", 20, 13, nil, 3, 0, 2, nil)
+		s.Nil(err)
+		i++
+	}
+
+	// Scan the whole shard, which holds the executions of the other tests as well
+	var nextPageToken []byte
+	for {
+		response, err := s.WorkflowMgr.ListConcreteExecutions(&ListConcreteExecutionsRequest{
+			PageSize:      2,
+			NextPageToken: nextPageToken,
+		})
+		s.Nil(err)
+		s.True(len(response.ExecutionInfos) <= 2)
+		for _, info := range response.ExecutionInfos {
+			if info.DomainID != domainID {
+				continue
+			}
+			found, ok := runIDs[info.RunID]
+			s.True(ok, info.RunID)
+			s.False(found, "execution %v listed twice", info.RunID)
+			runIDs[info.RunID] = true
+			s.Equal("wType", info.WorkflowTypeName)
+		}
+		if len(response.NextPageToken) == 0 {
+			break
+		}
+		nextPageToken = response.NextPageToken
+	}
+
+	for runID, found := range runIDs {
+		s.True(found, runID)
+	}
+}
+
 func (s *cassandraPersistenceSuite) TestTransferTasks() {
 	domainID := "1eda632b-dde5-4cb2-94fd-5a6f04e6dfcd"
 	workflowExecution := gen.WorkflowExecution{
@@ -821,6 +886,48 @@ func (s *cassandraPersistenceSuite) TestLeaseAndUpdateTaskList() {
 	s.Error(err)
 }
 
+func (s *cassandraPersistenceSuite) TestListTaskList() {
+	domainID := uuid.New()
+	taskLists := map[string]int{"list-a": TaskListTypeDecision, "list-b": TaskListTypeActivity, "list-c": TaskListTypeDecision}
+	for name, taskType := range taskLists {
+		_, err := s.TaskMgr.LeaseTaskList(&LeaseTaskListRequest{
+			DomainID: domainID,
+			TaskList: name,
+			TaskType: taskType,
+		})
+		s.NoError(err)
+	}
+	_, err := s.TaskMgr.LeaseTaskList(&LeaseTaskListRequest{
+		DomainID: uuid.New(),
+		TaskList: "list-other-domain",
+		TaskType: TaskListTypeDecision,
+	})
+	s.NoError(err)
+
+	listed := make(map[string]int)
+	var nextPageToken []byte
+	for {
+		response, err := s.TaskMgr.ListTaskList(&ListTaskListRequest{
+			DomainID:      domainID,
+			PageSize:      2,
+			NextPageToken: nextPageToken,
+		})
+		s.NoError(err)
+		s.True(len(response.Items) <= 2)
+		for _, tli := range response.Items {
+			s.Equal(domainID, tli.DomainID)
+			s.EqualValues(1, tli.RangeID)
+			s.False(tli.LastUpdated.IsZero())
+			listed[tli.Name] = tli.TaskType
+		}
+		nextPageToken = response.NextPageToken
+		if len(nextPageToken) == 0 {
+			break
+		}
+	}
+	s.Equal(taskLists, listed)
+}
+
 func (s *cassandraPersistenceSuite) TestLeaseAndUpdateTaskList_Sticky() {
 	domainID := uuid.New()
 	taskList := "aaaaaaa"
@@ -1215,6 +1322,78 @@ func (s *cassandraPersistenceSuite) TestWorkflowMutableState_SignalRequested() {
 	s.Equal(0, len(state.SignalRequestedIDs))
 }
 
+func (s *cassandraPersistenceSuite) TestWorkflowMutableState_BufferedEvents() {
+	domainID := uuid.New()
+	workflowExecution := gen.WorkflowExecution{
+		WorkflowId: common.StringPtr("test-workflow-mutable-buffered-events-test"),
+		RunId:      common.StringPtr(uuid.New()),
+	}
+
+	task0, err0 := s.CreateWorkflowExecution(domainID, workflowExecution, "taskList", "wType", 20, 13, nil, 3, 0, 2, nil)
+	s.Nil(err0, "No error expected.")
+	s.NotEmpty(task0, "Expected non empty task identifier.")
+
+	state0, err1 := s.GetWorkflowExecutionInfo(domainID, workflowExecution)
+	s.Nil(err1, "No error expected.")
+	info0 := state0.ExecutionInfo
+	s.NotNil(info0, "Valid Workflow info expected.")
+
+	serializer, err2 := NewHistorySerializerFactory().Get(common.EncodingTypeThriftRWGzip)
+	s.Nil(err2, "No error expected.")
+	signalEvent := &gen.HistoryEvent{
+		EventId:   common.Int64Ptr(-123), // the event ID of a buffered event is assigned when it is flushed
+		Timestamp: common.Int64Ptr(time.Now().UnixNano()),
+		EventType: common.EventTypePtr(gen.EventTypeWorkflowExecutionSignaled),
+		WorkflowExecutionSignaledEventAttributes: &gen.WorkflowExecutionSignaledEventAttributes{
+			SignalName: common.StringPtr("signal"),
+			Input:      []byte("input"),
+			Identity:   common.StringPtr("identity"),
+		},
+	}
+	bufferedEvents, err2 := serializer.Serialize(NewHistoryEventBatch(GetDefaultHistoryVersion(),
+		[]*gen.HistoryEvent{signalEvent}))
+	s.Nil(err2, "No error expected.")
+
+	updatedInfo := copyWorkflowExecutionInfo(info0)
+	err3 := s.WorkflowMgr.UpdateWorkflowExecution(&UpdateWorkflowExecutionRequest{
+		ExecutionInfo:     updatedInfo,
+		Condition:         int64(3),
+		RangeID:           s.ShardInfo.RangeID,
+		NewBufferedEvents: bufferedEvents,
+	})
+	s.Nil(err3, "No error expected.")
+
+	// the buffered batch is read back with the encoding it was written with
+	state1, err4 := s.GetWorkflowExecutionInfo(domainID, workflowExecution)
+	s.Nil(err4, "No error expected.")
+	s.Equal(1, len(state1.BufferedEvents))
+	s.Equal(common.EncodingTypeThriftRWGzip, state1.BufferedEvents[0].EncodingType)
+	deserializer, err4 := NewHistorySerializerFactory().Get(state1.BufferedEvents[0].EncodingType)
+	s.Nil(err4, "No error expected.")
+	batch, err4 := deserializer.Deserialize(state1.BufferedEvents[0])
+	s.Nil(err4, "No error expected.")
+	s.Equal(1, len(batch.Events))
+	s.Equal(gen.EventTypeWorkflowExecutionSignaled, batch.Events[0].GetEventType())
+	s.Equal("signal", batch.Events[0].WorkflowExecutionSignaledEventAttributes.GetSignalName())
+	s.Equal([]byte("input"), batch.Events[0].WorkflowExecutionSignaledEventAttributes.Input)
+
+	// flush the buffered events
+	updatedInfo = copyWorkflowExecutionInfo(state1.ExecutionInfo)
+	updatedInfo.NextEventID = int64(4)
+	err5 := s.WorkflowMgr.UpdateWorkflowExecution(&UpdateWorkflowExecutionRequest{
+		ExecutionInfo:       updatedInfo,
+		Condition:           int64(3),
+		RangeID:             s.ShardInfo.RangeID,
+		ClearBufferedEvents: true,
+	})
+	s.Nil(err5, "No error expected.")
+
+	state2, err6 := s.GetWorkflowExecutionInfo(domainID, workflowExecution)
+	s.Nil(err6, "No error expected.")
+	s.Equal(0, len(state2.BufferedEvents))
+	s.Equal(int64(4), state2.ExecutionInfo.NextEventID)
+}
+
 func (s *cassandraPersistenceSuite) TestWorkflowMutableStateInfo() {
 	domainID := "9ed8818b-3090-4160-9f21-c6b70e64d2dd"
 	workflowExecution := gen.WorkflowExecution{
@@ -1291,6 +1470,98 @@ func (s *cassandraPersistenceSuite) TestContinueAsNew() {
 	s.Equal(*newWorkflowExecution.RunId, newRunID)
 }
 
+func (s *cassandraPersistenceSuite) TestContinueAsNewWithMutableState() {
+	domainID := "c1c0bb55-04e6-4a9c-89d0-1be7b96459f8"
+	workflowExecution := gen.WorkflowExecution{
+		WorkflowId: common.StringPtr("continue-as-new-mutable-state-test"),
+		RunId:      common.StringPtr("2b4ba3f2-6f1a-4a3e-a0ea-bfae5a7fd16f"),
+	}
+
+	_, err0 := s.CreateWorkflowExecution(domainID, workflowExecution, "queue1", "wType", 20, 13, nil, 3, 0, 2, nil)
+	s.Nil(err0, "No error expected.")
+
+	state0, err1 := s.GetWorkflowExecutionInfo(domainID, workflowExecution)
+	s.Nil(err1, "No error expected.")
+	info0 := state0.ExecutionInfo
+	continueAsNewInfo := copyWorkflowExecutionInfo(info0)
+	continueAsNewInfo.State = WorkflowStateCompleted
+	continueAsNewInfo.NextEventID = int64(5)
+	continueAsNewInfo.LastProcessedEvent = int64(2)
+
+	newWorkflowExecution := gen.WorkflowExecution{
+		WorkflowId: common.StringPtr("continue-as-new-mutable-state-test"),
+		RunId:      common.StringPtr("a1a6f4fe-4a2a-4b4c-9a5e-2f1b0c2a8e33"),
+	}
+	expiryTime := time.Now().Add(time.Minute).UTC()
+	signalRequestedID := uuid.New()
+	err2 := s.WorkflowMgr.UpdateWorkflowExecution(&UpdateWorkflowExecutionRequest{
+		ExecutionInfo: continueAsNewInfo,
+		Condition:     info0.NextEventID,
+		RangeID:       s.ShardInfo.RangeID,
+		ContinueAsNew: &CreateWorkflowExecutionRequest{
+			RequestID:            uuid.New(),
+			DomainID:             domainID,
+			Execution:            newWorkflowExecution,
+			TaskList:             continueAsNewInfo.TaskList,
+			WorkflowTypeName:     continueAsNewInfo.WorkflowTypeName,
+			WorkflowTimeout:      continueAsNewInfo.WorkflowTimeout,
+			DecisionTimeoutValue: continueAsNewInfo.DecisionTimeoutValue,
+			NextEventID:          int64(7),
+			LastProcessedEvent:   common.EmptyEventID,
+			RangeID:              s.ShardInfo.RangeID,
+			TransferTasks: []Task{
+				&DecisionTask{TaskID: s.GetNextSequenceNumber(), DomainID: domainID, TaskList: "queue1", ScheduleID: 6},
+				&ActivityTask{TaskID: s.GetNextSequenceNumber(), DomainID: domainID, TaskList: "queue1", ScheduleID: 4},
+			},
+			DecisionScheduleID:          int64(6),
+			DecisionStartedID:           common.EmptyEventID,
+			DecisionStartToCloseTimeout: 1,
+			ContinueAsNew:               true,
+			PreviousRunID:               continueAsNewInfo.RunID,
+			ActivityInfos: []*ActivityInfo{{
+				ScheduleID:             4,
+				ScheduledEvent:         []byte("scheduled_event_4"),
+				ScheduledTime:          time.Now().UTC(),
+				StartedID:              common.EmptyEventID,
+				ActivityID:             "activity4",
+				ScheduleToStartTimeout: 1,
+				ScheduleToCloseTimeout: 2,
+				StartToCloseTimeout:    3,
+				HeartbeatTimeout:       4,
+			}},
+			TimerInfos: []*TimerInfo{{
+				TimerID:    "timer5",
+				StartedID:  5,
+				ExpiryTime: expiryTime,
+				TaskID:     2,
+			}},
+			SignalRequestedIDs: []string{signalRequestedID},
+		},
+	})
+	s.Nil(err2, "No error expected.")
+
+	newExecutionState, err3 := s.GetWorkflowExecutionInfo(domainID, newWorkflowExecution)
+	s.Nil(err3)
+	s.Equal(int64(7), newExecutionState.ExecutionInfo.NextEventID)
+	s.Equal(1, len(newExecutionState.ActivitInfos))
+	ai, ok := newExecutionState.ActivitInfos[4]
+	s.True(ok)
+	s.Equal("activity4", ai.ActivityID)
+	s.Equal(common.EmptyEventID, ai.StartedID)
+	s.Equal(1, len(newExecutionState.TimerInfos))
+	ti, ok := newExecutionState.TimerInfos["timer5"]
+	s.True(ok)
+	s.Equal(int64(5), ti.StartedID)
+	s.Equal(expiryTime.Unix(), ti.ExpiryTime.Unix())
+	s.Equal(1, len(newExecutionState.SignalRequestedIDs))
+	_, ok = newExecutionState.SignalRequestedIDs[signalRequestedID]
+	s.True(ok)
+
+	newRunID, err4 := s.GetCurrentWorkflowRunID(domainID, *workflowExecution.WorkflowId)
+	s.Nil(err4)
+	s.Equal(*newWorkflowExecution.RunId, newRunID)
+}
+
 func (s *cassandraPersistenceSuite) TestReplicationTransferTaskTasks() {
 	domainID := "2466d7de-6602-4ad8-b939-fb8f8c36c711"
 	workflowExecution := gen.WorkflowExecution{
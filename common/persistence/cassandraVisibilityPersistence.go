@@ -22,6 +22,7 @@ package persistence
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/gocql/gocql"
@@ -40,8 +41,8 @@ const (
 
 const (
 	templateCreateWorkflowExecutionStarted = `INSERT INTO open_executions (` +
-		`domain_id, domain_partition, workflow_id, run_id, start_time, workflow_type_name) ` +
-		`VALUES (?, ?, ?, ?, ?, ?) using TTL ?`
+		`domain_id, domain_partition, workflow_id, run_id, start_time, workflow_type_name, search_attributes) ` +
+		`VALUES (?, ?, ?, ?, ?, ?, ?) using TTL ?`
 
 	templateDeleteWorkflowExecutionStarted = `DELETE FROM open_executions ` +
 		`WHERE domain_id = ? ` +
@@ -50,24 +51,26 @@ const (
 		`AND run_id = ?`
 
 	templateCreateWorkflowExecutionClosed = `INSERT INTO closed_executions (` +
-		`domain_id, domain_partition, workflow_id, run_id, start_time, close_time, workflow_type_name, status, history_length) ` +
-		`VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) using TTL ?`
+		`domain_id, domain_partition, workflow_id, run_id, start_time, close_time, workflow_type_name, status, history_length, ` +
+		`search_attributes) ` +
+		`VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) using TTL ?`
 
-	templateGetOpenWorkflowExecutions = `SELECT workflow_id, run_id, start_time, workflow_type_name ` +
+	templateGetOpenWorkflowExecutions = `SELECT workflow_id, run_id, start_time, workflow_type_name, search_attributes ` +
 		`FROM open_executions ` +
 		`WHERE domain_id = ? ` +
 		`AND domain_partition IN (?) ` +
 		`AND start_time >= ? ` +
 		`AND start_time <= ? `
 
-	templateGetClosedWorkflowExecutions = `SELECT workflow_id, run_id, start_time, close_time, workflow_type_name, status, history_length ` +
+	templateGetClosedWorkflowExecutions = `SELECT workflow_id, run_id, start_time, close_time, workflow_type_name, status, history_length, ` +
+		`search_attributes ` +
 		`FROM closed_executions ` +
 		`WHERE domain_id = ? ` +
 		`AND domain_partition IN (?) ` +
 		`AND start_time >= ? ` +
 		`AND start_time <= ? `
 
-	templateGetOpenWorkflowExecutionsByType = `SELECT workflow_id, run_id, start_time, workflow_type_name ` +
+	templateGetOpenWorkflowExecutionsByType = `SELECT workflow_id, run_id, start_time, workflow_type_name, search_attributes ` +
 		`FROM open_executions ` +
 		`WHERE domain_id = ? ` +
 		`AND domain_partition = ? ` +
@@ -75,7 +78,8 @@ const (
 		`AND start_time <= ? ` +
 		`AND workflow_type_name = ? `
 
-	templateGetClosedWorkflowExecutionsByType = `SELECT workflow_id, run_id, start_time, close_time, workflow_type_name, status, history_length ` +
+	templateGetClosedWorkflowExecutionsByType = `SELECT workflow_id, run_id, start_time, close_time, workflow_type_name, status, history_length, ` +
+		`search_attributes ` +
 		`FROM closed_executions ` +
 		`WHERE domain_id = ? ` +
 		`AND domain_partition = ? ` +
@@ -83,7 +87,7 @@ const (
 		`AND start_time <= ? ` +
 		`AND workflow_type_name = ? `
 
-	templateGetOpenWorkflowExecutionsByID = `SELECT workflow_id, run_id, start_time, workflow_type_name ` +
+	templateGetOpenWorkflowExecutionsByID = `SELECT workflow_id, run_id, start_time, workflow_type_name, search_attributes ` +
 		`FROM open_executions ` +
 		`WHERE domain_id = ? ` +
 		`AND domain_partition = ? ` +
@@ -91,7 +95,8 @@ const (
 		`AND start_time <= ? ` +
 		`AND workflow_id = ? `
 
-	templateGetClosedWorkflowExecutionsByID = `SELECT workflow_id, run_id, start_time, close_time, workflow_type_name, status, history_length ` +
+	templateGetClosedWorkflowExecutionsByID = `SELECT workflow_id, run_id, start_time, close_time, workflow_type_name, status, history_length, ` +
+		`search_attributes ` +
 		`FROM closed_executions ` +
 		`WHERE domain_id = ? ` +
 		`AND domain_partition = ? ` +
@@ -99,7 +104,8 @@ const (
 		`AND start_time <= ? ` +
 		`AND workflow_id = ? `
 
-	templateGetClosedWorkflowExecutionsByStatus = `SELECT workflow_id, run_id, start_time, close_time, workflow_type_name, status, history_length ` +
+	templateGetClosedWorkflowExecutionsByStatus = `SELECT workflow_id, run_id, start_time, close_time, workflow_type_name, status, history_length, ` +
+		`search_attributes ` +
 		`FROM closed_executions ` +
 		`WHERE domain_id = ? ` +
 		`AND domain_partition = ? ` +
@@ -107,12 +113,31 @@ const (
 		`AND start_time <= ? ` +
 		`AND status = ? `
 
-	templateGetClosedWorkflowExecution = `SELECT workflow_id, run_id, start_time, close_time, workflow_type_name, status, history_length ` +
+	templateGetClosedWorkflowExecution = `SELECT workflow_id, run_id, start_time, close_time, workflow_type_name, status, history_length, ` +
+		`search_attributes ` +
 		`FROM closed_executions ` +
 		`WHERE domain_id = ? ` +
 		`AND domain_partition = ? ` +
 		`AND workflow_id = ? ` +
 		`AND run_id = ? ALLOW FILTERING `
+
+	templateGetOpenWorkflowExecutionsBySearchAttributes = `SELECT workflow_id, run_id, start_time, workflow_type_name, ` +
+		`search_attributes ` +
+		`FROM open_executions ` +
+		`WHERE domain_id = ? ` +
+		`AND domain_partition = ? ` +
+		`AND start_time >= ? ` +
+		`AND start_time <= ? `
+
+	templateGetClosedWorkflowExecutionsBySearchAttributes = `SELECT workflow_id, run_id, start_time, close_time, ` +
+		`workflow_type_name, status, history_length, search_attributes ` +
+		`FROM closed_executions ` +
+		`WHERE domain_id = ? ` +
+		`AND domain_partition = ? ` +
+		`AND start_time >= ? ` +
+		`AND start_time <= ? `
+
+	templateSearchAttributeFilter = `AND search_attributes[?] = ? `
 )
 
 type (
@@ -158,6 +183,7 @@ func (v *cassandraVisibilityPersistence) RecordWorkflowExecutionStarted(
 		*request.Execution.RunId,
 		common.UnixNanoToCQLTimestamp(request.StartTimestamp),
 		request.WorkflowTypeName,
+		request.SearchAttributes,
 		ttl,
 	)
 	query = query.WithTimestamp(common.UnixNanoToCQLTimestamp(request.StartTimestamp))
@@ -206,6 +232,7 @@ func (v *cassandraVisibilityPersistence) RecordWorkflowExecutionClosed(
 		request.WorkflowTypeName,
 		request.Status,
 		request.HistoryLength,
+		request.SearchAttributes,
 		retention,
 	)
 
@@ -224,6 +251,37 @@ func (v *cassandraVisibilityPersistence) RecordWorkflowExecutionClosed(
 	return nil
 }
 
+func (v *cassandraVisibilityPersistence) UpsertWorkflowExecution(
+	request *UpsertWorkflowExecutionRequest) error {
+	ttl := request.WorkflowTimeout + openExecutionTTLBuffer
+	query := v.session.Query(templateCreateWorkflowExecutionStarted,
+		request.DomainUUID,
+		domainPartition,
+		*request.Execution.WorkflowId,
+		*request.Execution.RunId,
+		common.UnixNanoToCQLTimestamp(request.StartTimestamp),
+		request.WorkflowTypeName,
+		request.SearchAttributes,
+		ttl,
+	)
+	// The update replaces the row written when the execution started, while the removal of the open row
+	// written when the execution closes still wins, as the execution can only be updated before closing
+	query = query.WithTimestamp(common.UnixNanoToCQLTimestamp(request.UpdateTimestamp))
+	err := query.Exec()
+	if err != nil {
+		if isThrottlingError(err) {
+			return &workflow.ServiceBusyError{
+				Message: fmt.Sprintf("UpsertWorkflowExecution operation failed. Error: %v", err),
+			}
+		}
+		return &workflow.InternalServiceError{
+			Message: fmt.Sprintf("UpsertWorkflowExecution operation failed. Error: %v", err),
+		}
+	}
+
+	return nil
+}
+
 func (v *cassandraVisibilityPersistence) ListOpenWorkflowExecutions(
 	request *ListWorkflowExecutionsRequest) (*ListWorkflowExecutionsResponse, error) {
 	query := v.session.Query(templateGetOpenWorkflowExecutions,
@@ -468,6 +526,107 @@ func (v *cassandraVisibilityPersistence) ListClosedWorkflowExecutionsByWorkflowI
 	return response, nil
 }
 
+func (v *cassandraVisibilityPersistence) ListOpenWorkflowExecutionsBySearchAttributes(
+	request *ListWorkflowExecutionsBySearchAttributesRequest) (*ListWorkflowExecutionsResponse, error) {
+	template, args := v.getSearchAttributesQuery(templateGetOpenWorkflowExecutionsBySearchAttributes, request)
+	query := v.session.Query(template, args...).Consistency(v.lowConslevel)
+	iter := query.PageSize(request.PageSize).PageState(request.NextPageToken).Iter()
+	if iter == nil {
+		// TODO: should return a bad request error if the token is invalid
+		return nil, &workflow.InternalServiceError{
+			Message: "ListOpenWorkflowExecutionsBySearchAttributes operation failed.  Not able to create query iterator.",
+		}
+	}
+
+	response := &ListWorkflowExecutionsResponse{}
+	response.Executions = make([]*workflow.WorkflowExecutionInfo, 0)
+	wfexecution, has := readOpenWorkflowExecutionRecord(iter)
+	for has {
+		response.Executions = append(response.Executions, wfexecution)
+		wfexecution, has = readOpenWorkflowExecutionRecord(iter)
+	}
+
+	nextPageToken := iter.PageState()
+	response.NextPageToken = make([]byte, len(nextPageToken))
+	copy(response.NextPageToken, nextPageToken)
+	if err := iter.Close(); err != nil {
+		if isThrottlingError(err) {
+			return nil, &workflow.ServiceBusyError{
+				Message: fmt.Sprintf("ListOpenWorkflowExecutionsBySearchAttributes operation failed. Error: %v", err),
+			}
+		}
+		return nil, &workflow.InternalServiceError{
+			Message: fmt.Sprintf("ListOpenWorkflowExecutionsBySearchAttributes operation failed. Error: %v", err),
+		}
+	}
+
+	return response, nil
+}
+
+func (v *cassandraVisibilityPersistence) ListClosedWorkflowExecutionsBySearchAttributes(
+	request *ListWorkflowExecutionsBySearchAttributesRequest) (*ListWorkflowExecutionsResponse, error) {
+	template, args := v.getSearchAttributesQuery(templateGetClosedWorkflowExecutionsBySearchAttributes, request)
+	query := v.session.Query(template, args...).Consistency(v.lowConslevel)
+	iter := query.PageSize(request.PageSize).PageState(request.NextPageToken).Iter()
+	if iter == nil {
+		// TODO: should return a bad request error if the token is invalid
+		return nil, &workflow.InternalServiceError{
+			Message: "ListClosedWorkflowExecutionsBySearchAttributes operation failed.  Not able to create query iterator.",
+		}
+	}
+
+	response := &ListWorkflowExecutionsResponse{}
+	response.Executions = make([]*workflow.WorkflowExecutionInfo, 0)
+	wfexecution, has := readClosedWorkflowExecutionRecord(iter)
+	for has {
+		response.Executions = append(response.Executions, wfexecution)
+		wfexecution, has = readClosedWorkflowExecutionRecord(iter)
+	}
+
+	nextPageToken := iter.PageState()
+	response.NextPageToken = make([]byte, len(nextPageToken))
+	copy(response.NextPageToken, nextPageToken)
+	if err := iter.Close(); err != nil {
+		if isThrottlingError(err) {
+			return nil, &workflow.ServiceBusyError{
+				Message: fmt.Sprintf("ListClosedWorkflowExecutionsBySearchAttributes operation failed. Error: %v", err),
+			}
+		}
+		return nil, &workflow.InternalServiceError{
+			Message: fmt.Sprintf("ListClosedWorkflowExecutionsBySearchAttributes operation failed. Error: %v", err),
+		}
+	}
+
+	return response, nil
+}
+
+// getSearchAttributesQuery appends one condition on the indexed search_attributes entries per attribute to the
+// template. Only a single condition can be served by the entries index, further ones are filtered by cassandra
+// within the domain partition.
+func (v *cassandraVisibilityPersistence) getSearchAttributesQuery(template string,
+	request *ListWorkflowExecutionsBySearchAttributesRequest) (string, []interface{}) {
+	args := []interface{}{
+		request.DomainUUID,
+		domainPartition,
+		common.UnixNanoToCQLTimestamp(request.EarliestStartTime),
+		common.UnixNanoToCQLTimestamp(request.LatestStartTime),
+	}
+
+	keys := make([]string, 0, len(request.SearchAttributes))
+	for key := range request.SearchAttributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		template += templateSearchAttributeFilter
+		args = append(args, key, request.SearchAttributes[key])
+	}
+	if len(keys) > 1 {
+		template += `ALLOW FILTERING `
+	}
+	return template, args
+}
+
 func (v *cassandraVisibilityPersistence) ListClosedWorkflowExecutionsByStatus(
 	request *ListClosedWorkflowExecutionsByStatusRequest) (*ListWorkflowExecutionsResponse, error) {
 	query := v.session.Query(templateGetClosedWorkflowExecutionsByStatus,
@@ -509,6 +668,18 @@ func (v *cassandraVisibilityPersistence) ListClosedWorkflowExecutionsByStatus(
 	return response, nil
 }
 
+// ListWorkflowExecutions is not supported, the visibility tables can only be filtered on a single indexed column
+func (v *cassandraVisibilityPersistence) ListWorkflowExecutions(
+	request *ListWorkflowExecutionsByQueryRequest) (*ListWorkflowExecutionsResponse, error) {
+	return nil, newVisibilityQueryNotSupportedError("ListWorkflowExecutions")
+}
+
+// CountWorkflowExecutions is not supported, the visibility tables can only be filtered on a single indexed column
+func (v *cassandraVisibilityPersistence) CountWorkflowExecutions(
+	request *CountWorkflowExecutionsRequest) (*CountWorkflowExecutionsResponse, error) {
+	return nil, newVisibilityQueryNotSupportedError("CountWorkflowExecutions")
+}
+
 func (v *cassandraVisibilityPersistence) GetClosedWorkflowExecution(
 	request *GetClosedWorkflowExecutionRequest) (*GetClosedWorkflowExecutionResponse, error) {
 	execution := request.Execution
@@ -554,7 +725,8 @@ func readOpenWorkflowExecutionRecord(iter *gocql.Iter) (*workflow.WorkflowExecut
 	var runID gocql.UUID
 	var typeName string
 	var startTime time.Time
-	if iter.Scan(&workflowID, &runID, &startTime, &typeName) {
+	var searchAttributes map[string][]byte
+	if iter.Scan(&workflowID, &runID, &startTime, &typeName, &searchAttributes) {
 		execution := &workflow.WorkflowExecution{}
 		execution.WorkflowId = common.StringPtr(workflowID)
 		execution.RunId = common.StringPtr(runID.String())
@@ -566,6 +738,7 @@ func readOpenWorkflowExecutionRecord(iter *gocql.Iter) (*workflow.WorkflowExecut
 		record.Execution = execution
 		record.StartTime = common.Int64Ptr(startTime.UnixNano())
 		record.Type = wfType
+		record.SearchAttributes = getSearchAttributes(searchAttributes)
 		return record, true
 	}
 	return nil, false
@@ -579,7 +752,8 @@ func readClosedWorkflowExecutionRecord(iter *gocql.Iter) (*workflow.WorkflowExec
 	var closeTime time.Time
 	var status workflow.WorkflowExecutionCloseStatus
 	var historyLength int64
-	if iter.Scan(&workflowID, &runID, &startTime, &closeTime, &typeName, &status, &historyLength) {
+	var searchAttributes map[string][]byte
+	if iter.Scan(&workflowID, &runID, &startTime, &closeTime, &typeName, &status, &historyLength, &searchAttributes) {
 		execution := &workflow.WorkflowExecution{}
 		execution.WorkflowId = common.StringPtr(workflowID)
 		execution.RunId = common.StringPtr(runID.String())
@@ -594,7 +768,22 @@ func readClosedWorkflowExecutionRecord(iter *gocql.Iter) (*workflow.WorkflowExec
 		record.Type = wfType
 		record.CloseStatus = &status
 		record.HistoryLength = common.Int64Ptr(historyLength)
+		record.SearchAttributes = getSearchAttributes(searchAttributes)
 		return record, true
 	}
 	return nil, false
 }
+
+// getSearchAttributes returns the search attributes of a visibility record, nil if the execution has none
+func getSearchAttributes(indexedFields map[string][]byte) *workflow.SearchAttributes {
+	if len(indexedFields) == 0 {
+		return nil
+	}
+	return &workflow.SearchAttributes{IndexedFields: indexedFields}
+}
+
+func newVisibilityQueryNotSupportedError(operation string) error {
+	return &workflow.BadRequestError{
+		Message: fmt.Sprintf("%v operation is not supported by the Cassandra visibility store.", operation),
+	}
+}
@@ -32,6 +32,7 @@ import (
 
 	gen "github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/visibilityquery"
 )
 
 type (
@@ -446,3 +447,206 @@ func (s *visibilityPersistenceSuite) TestGetClosedExecution() {
 	s.Equal(workflowExecution.WorkflowId, resp.Execution.Execution.WorkflowId)
 	s.Equal(int64(3), *resp.Execution.HistoryLength)
 }
+
+func (s *visibilityPersistenceSuite) TestFilteringBySearchAttributes() {
+	testDomainUUID := uuid.New()
+	startTime := time.Now().UnixNano()
+
+	// Create 2 executions with different search attributes
+	workflowExecution1 := gen.WorkflowExecution{
+		WorkflowId: common.StringPtr("visibility-filtering-test1"),
+		RunId:      common.StringPtr("2d9b5b02-3f2b-4c51-8c1a-5d2c1a3e0b61"),
+	}
+	err0 := s.VisibilityMgr.RecordWorkflowExecutionStarted(&RecordWorkflowExecutionStartedRequest{
+		DomainUUID:       testDomainUUID,
+		Execution:        workflowExecution1,
+		WorkflowTypeName: "visibility-workflow",
+		StartTimestamp:   startTime,
+		SearchAttributes: map[string][]byte{"CustomKeywordField": []byte(`"keyword1"`)},
+	})
+	s.Nil(err0)
+
+	workflowExecution2 := gen.WorkflowExecution{
+		WorkflowId: common.StringPtr("visibility-filtering-test2"),
+		RunId:      common.StringPtr("7f3e6a7c-62c5-4c9c-9a4e-0e6f8d2b5c12"),
+	}
+	err1 := s.VisibilityMgr.RecordWorkflowExecutionStarted(&RecordWorkflowExecutionStartedRequest{
+		DomainUUID:       testDomainUUID,
+		Execution:        workflowExecution2,
+		WorkflowTypeName: "visibility-workflow",
+		StartTimestamp:   startTime,
+		SearchAttributes: map[string][]byte{"CustomKeywordField": []byte(`"keyword2"`)},
+	})
+	s.Nil(err1)
+
+	listRequest := ListWorkflowExecutionsRequest{
+		DomainUUID:        testDomainUUID,
+		PageSize:          2,
+		EarliestStartTime: startTime,
+		LatestStartTime:   startTime,
+	}
+
+	// List open with filtering
+	resp, err2 := s.VisibilityMgr.ListOpenWorkflowExecutionsBySearchAttributes(
+		&ListWorkflowExecutionsBySearchAttributesRequest{
+			ListWorkflowExecutionsRequest: listRequest,
+			SearchAttributes:              map[string][]byte{"CustomKeywordField": []byte(`"keyword2"`)},
+		})
+	s.Nil(err2)
+	s.Equal(1, len(resp.Executions))
+	s.Equal(workflowExecution2.WorkflowId, resp.Executions[0].Execution.WorkflowId)
+	s.Equal([]byte(`"keyword2"`), resp.Executions[0].SearchAttributes.IndexedFields["CustomKeywordField"])
+
+	// Upsert the search attributes of the first execution
+	err3 := s.VisibilityMgr.UpsertWorkflowExecution(&UpsertWorkflowExecutionRequest{
+		DomainUUID:       testDomainUUID,
+		Execution:        workflowExecution1,
+		WorkflowTypeName: "visibility-workflow",
+		StartTimestamp:   startTime,
+		UpdateTimestamp:  startTime + 1,
+		SearchAttributes: map[string][]byte{
+			"CustomKeywordField": []byte(`"keyword2"`),
+			"CustomIntField":     []byte(`1`),
+		},
+	})
+	s.Nil(err3)
+
+	resp, err4 := s.VisibilityMgr.ListOpenWorkflowExecutionsBySearchAttributes(
+		&ListWorkflowExecutionsBySearchAttributesRequest{
+			ListWorkflowExecutionsRequest: listRequest,
+			SearchAttributes: map[string][]byte{
+				"CustomKeywordField": []byte(`"keyword2"`),
+				"CustomIntField":     []byte(`1`),
+			},
+		})
+	s.Nil(err4)
+	s.Equal(1, len(resp.Executions))
+	s.Equal(workflowExecution1.WorkflowId, resp.Executions[0].Execution.WorkflowId)
+
+	// Close the first execution
+	err5 := s.VisibilityMgr.RecordWorkflowExecutionClosed(&RecordWorkflowExecutionClosedRequest{
+		DomainUUID:       testDomainUUID,
+		Execution:        workflowExecution1,
+		WorkflowTypeName: "visibility-workflow",
+		StartTimestamp:   startTime,
+		CloseTimestamp:   time.Now().UnixNano(),
+		SearchAttributes: map[string][]byte{
+			"CustomKeywordField": []byte(`"keyword2"`),
+			"CustomIntField":     []byte(`1`),
+		},
+	})
+	s.Nil(err5)
+
+	resp, err6 := s.VisibilityMgr.ListClosedWorkflowExecutionsBySearchAttributes(
+		&ListWorkflowExecutionsBySearchAttributesRequest{
+			ListWorkflowExecutionsRequest: listRequest,
+			SearchAttributes:              map[string][]byte{"CustomIntField": []byte(`1`)},
+		})
+	s.Nil(err6)
+	s.Equal(1, len(resp.Executions))
+	s.Equal(workflowExecution1.WorkflowId, resp.Executions[0].Execution.WorkflowId)
+}
+
+func (s *visibilityPersistenceSuite) TestListAndCountWorkflowExecutionsByQuery() {
+	testDomainUUID := uuid.New()
+	startTime := time.Now().UnixNano()
+
+	countQuery, err0 := visibilityquery.Parse(`WorkflowType = 'visibility-workflow' AND CloseTime IS NOT NULL`)
+	s.Nil(err0)
+	if !s.InMemory && s.SQLDriverName == "" {
+		// The Cassandra visibility store does not support queries
+		_, err := s.VisibilityMgr.CountWorkflowExecutions(&CountWorkflowExecutionsRequest{
+			DomainUUID: testDomainUUID,
+			Query:      countQuery,
+		})
+		s.IsType(&gen.BadRequestError{}, err)
+		return
+	}
+
+	// Create 3 executions, one open and two closed
+	workflowExecution1 := gen.WorkflowExecution{
+		WorkflowId: common.StringPtr("visibility-query-test1"),
+		RunId:      common.StringPtr("1b6f4d5e-0d7c-4a1a-9f3e-2c5d8e7f6a01"),
+	}
+	workflowExecution2 := gen.WorkflowExecution{
+		WorkflowId: common.StringPtr("visibility-query-test2"),
+		RunId:      common.StringPtr("2b6f4d5e-0d7c-4a1a-9f3e-2c5d8e7f6a02"),
+	}
+	workflowExecution3 := gen.WorkflowExecution{
+		WorkflowId: common.StringPtr("visibility-query-test3"),
+		RunId:      common.StringPtr("3b6f4d5e-0d7c-4a1a-9f3e-2c5d8e7f6a03"),
+	}
+	for _, execution := range []gen.WorkflowExecution{workflowExecution1, workflowExecution2, workflowExecution3} {
+		err := s.VisibilityMgr.RecordWorkflowExecutionStarted(&RecordWorkflowExecutionStartedRequest{
+			DomainUUID:       testDomainUUID,
+			Execution:        execution,
+			WorkflowTypeName: "visibility-workflow",
+			StartTimestamp:   startTime,
+		})
+		s.Nil(err)
+	}
+
+	err1 := s.VisibilityMgr.RecordWorkflowExecutionClosed(&RecordWorkflowExecutionClosedRequest{
+		DomainUUID:       testDomainUUID,
+		Execution:        workflowExecution2,
+		WorkflowTypeName: "visibility-workflow",
+		StartTimestamp:   startTime,
+		CloseTimestamp:   time.Now().UnixNano(),
+		Status:           gen.WorkflowExecutionCloseStatusFailed,
+		HistoryLength:    2,
+	})
+	s.Nil(err1)
+
+	err2 := s.VisibilityMgr.RecordWorkflowExecutionClosed(&RecordWorkflowExecutionClosedRequest{
+		DomainUUID:       testDomainUUID,
+		Execution:        workflowExecution3,
+		WorkflowTypeName: "other-workflow",
+		StartTimestamp:   startTime,
+		CloseTimestamp:   time.Now().UnixNano(),
+		Status:           gen.WorkflowExecutionCloseStatusCompleted,
+		HistoryLength:    5,
+	})
+	s.Nil(err2)
+
+	countResp, err3 := s.VisibilityMgr.CountWorkflowExecutions(&CountWorkflowExecutionsRequest{
+		DomainUUID: testDomainUUID,
+		Query:      countQuery,
+	})
+	s.Nil(err3)
+	s.Equal(int64(1), countResp.Count)
+
+	prefixQuery, err := visibilityquery.Parse(`WorkflowID LIKE 'visibility-query-test%' AND HistoryLength < 5`)
+	s.Nil(err)
+	countResp, err = s.VisibilityMgr.CountWorkflowExecutions(&CountWorkflowExecutionsRequest{
+		DomainUUID: testDomainUUID,
+		Query:      prefixQuery,
+	})
+	s.Nil(err)
+	s.Equal(int64(1), countResp.Count)
+
+	listQuery, err4 := visibilityquery.Parse(
+		`WorkflowType = 'visibility-workflow' OR HistoryLength > 3 ORDER BY HistoryLength DESC`)
+	s.Nil(err4)
+	resp, err5 := s.VisibilityMgr.ListWorkflowExecutions(&ListWorkflowExecutionsByQueryRequest{
+		DomainUUID: testDomainUUID,
+		Query:      listQuery,
+		PageSize:   2,
+	})
+	s.Nil(err5)
+	s.Equal(2, len(resp.Executions))
+	s.Equal(workflowExecution3.WorkflowId, resp.Executions[0].Execution.WorkflowId)
+	s.Equal(workflowExecution2.WorkflowId, resp.Executions[1].Execution.WorkflowId)
+	s.NotEmpty(resp.NextPageToken)
+
+	resp, err6 := s.VisibilityMgr.ListWorkflowExecutions(&ListWorkflowExecutionsByQueryRequest{
+		DomainUUID:    testDomainUUID,
+		Query:         listQuery,
+		PageSize:      2,
+		NextPageToken: resp.NextPageToken,
+	})
+	s.Nil(err6)
+	s.Equal(1, len(resp.Executions))
+	s.Equal(workflowExecution1.WorkflowId, resp.Executions[0].Execution.WorkflowId)
+	s.Nil(resp.Executions[0].CloseTime)
+	s.Empty(resp.NextPageToken)
+}
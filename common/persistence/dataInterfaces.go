@@ -73,6 +73,7 @@ const (
 	TransferTaskTypeCancelExecution
 	TransferTaskTypeStartChildExecution
 	TransferTaskTypeSignalExecution
+	TransferTaskTypeUpsertWorkflowSearchAttributes
 )
 
 // Types of replication tasks
@@ -88,6 +89,13 @@ const (
 	TaskTypeUserTimer
 	TaskTypeWorkflowTimeout
 	TaskTypeDeleteHistoryEvent
+	TaskTypeWorkflowBackoffTimer
+)
+
+// Types of workflow backoff timeout
+const (
+	WorkflowBackoffTimeoutTypeRetry = iota
+	WorkflowBackoffTimeoutTypeCron
 )
 
 type (
@@ -171,6 +179,23 @@ type (
 		ClientLibraryVersion         string
 		ClientFeatureVersion         string
 		ClientImpl                   string
+		// for retry
+		Attempt            int32
+		HasRetryPolicy     bool
+		InitialInterval    int32
+		BackoffCoefficient float64
+		MaximumInterval    int32
+		ExpirationTime     time.Time
+		MaximumAttempts    int32
+		NonRetriableErrors []string
+		// for cron
+		CronSchedule string
+		// for visibility
+		SearchAttributes map[string][]byte
+		// for task dispatch
+		Priority int32
+		// build ID of the worker which completed the first decision
+		BuildID string
 	}
 
 	// ReplicationState represents mutable state information for global domains.
@@ -232,6 +257,12 @@ type (
 		RangeID  int64
 		AckLevel int64
 		Kind     int
+		// sets of compatible build IDs, the last set holds the default build IDs
+		CompatibleBuildIDs [][]string
+		RetiredBuildIDs    []string
+		// tasks of a paused task list are not dispatched to pollers
+		Paused      bool
+		LastUpdated time.Time
 	}
 
 	// TaskInfo describes either activity or decision task
@@ -242,6 +273,10 @@ type (
 		TaskID                 int64
 		ScheduleID             int64
 		ScheduleToStartTimeout int32
+		Priority               int32
+		BuildID                string
+		// Expiry is when the schedule-to-start timeout of the task fires, zero if the task never expires
+		Expiry time.Time
 	}
 
 	// Task is the generic interface for workflow tasks
@@ -272,6 +307,12 @@ type (
 		TaskID int64
 	}
 
+	// UpsertWorkflowSearchAttributesTask identifies a transfer task for updating the search attributes
+	// of a running execution in the visibility store
+	UpsertWorkflowSearchAttributesTask struct {
+		TaskID int64
+	}
+
 	// DeleteHistoryEventTask identifies a timer task for deletion of history events of completed execution.
 	DeleteHistoryEventTask struct {
 		VisibilityTimestamp time.Time
@@ -329,6 +370,13 @@ type (
 		EventID             int64
 	}
 
+	// WorkflowBackoffTimerTask identifies a timer task for scheduling the first decision of a delayed workflow run.
+	WorkflowBackoffTimerTask struct {
+		VisibilityTimestamp time.Time
+		TaskID              int64
+		TimeoutType         int // retry or cron
+	}
+
 	// UserTimerTask identifies a timeout task.
 	UserTimerTask struct {
 		VisibilityTimestamp time.Time
@@ -383,6 +431,17 @@ type (
 		CancelRequestID          int64
 		LastHeartBeatUpdatedTime time.Time
 		TimerTaskStatus          int32
+		// For retry
+		Attempt            int32
+		HasRetryPolicy     bool
+		InitialInterval    int32
+		BackoffCoefficient float64
+		MaximumInterval    int32
+		ExpirationTime     time.Time
+		MaximumAttempts    int32
+		NonRetriableErrors []string
+		// for task dispatch
+		Priority int32
 	}
 
 	// TimerInfo details - metadata about user timer info.
@@ -463,6 +522,27 @@ type (
 		ContinueAsNew               bool
 		PreviousRunID               string
 		ReplicationState            *ReplicationState
+		// for retry
+		Attempt            int32
+		HasRetryPolicy     bool
+		InitialInterval    int32
+		BackoffCoefficient float64
+		MaximumInterval    int32
+		ExpirationTime     time.Time
+		MaximumAttempts    int32
+		NonRetriableErrors []string
+		// for cron
+		CronSchedule string
+		// for visibility
+		SearchAttributes map[string][]byte
+		// for task dispatch
+		Priority int32
+		BuildID  string
+		// for signal deduplication
+		SignalRequestedIDs []string
+		// for reset, the pending activities and timers of the new run
+		ActivityInfos []*ActivityInfo
+		TimerInfos    []*TimerInfo
 	}
 
 	// CreateWorkflowExecutionResponse is the response to CreateWorkflowExecutionRequest
@@ -526,6 +606,20 @@ type (
 		ClearBufferedEvents       bool
 	}
 
+	// ListConcreteExecutionsRequest is used to scan the workflow executions of a shard, the current execution
+	// pointers of the workflows are skipped
+	ListConcreteExecutionsRequest struct {
+		PageSize      int
+		NextPageToken []byte
+	}
+
+	// ListConcreteExecutionsResponse is the response to ListConcreteExecutionsRequest
+	ListConcreteExecutionsResponse struct {
+		ExecutionInfos []*WorkflowExecutionInfo
+		// NextPageToken is empty once the whole shard has been scanned
+		NextPageToken []byte
+	}
+
 	// DeleteWorkflowExecutionRequest is used to delete a workflow execution
 	DeleteWorkflowExecutionRequest struct {
 		DomainID   string
@@ -628,6 +722,21 @@ type (
 		Tasks []*TaskInfo
 	}
 
+	// ListTaskListRequest is used to page through the task lists of a domain
+	ListTaskListRequest struct {
+		DomainID      string
+		PageSize      int
+		NextPageToken []byte
+	}
+
+	// ListTaskListResponse is the response to ListTaskListRequest
+	ListTaskListResponse struct {
+		// Items can hold less than PageSize task lists, or none, even if NextPageToken is not empty
+		Items []*TaskListInfo
+		// NextPageToken is empty once all the task lists have been listed
+		NextPageToken []byte
+	}
+
 	// CompleteTaskRequest is used to complete a task
 	CompleteTaskRequest struct {
 		TaskList *TaskListInfo
@@ -715,6 +824,9 @@ type (
 		// NOTE: this retention is in days, not in seconds
 		Retention  int32
 		EmitMetric bool
+		// ArchivalBucket is the bucket of the archiver holding the histories of the domain, it cannot change once set
+		ArchivalBucket string
+		ArchivalStatus workflow.ArchivalStatus
 	}
 
 	// DomainReplicationConfig describes the cross DC domain replication configuration
@@ -801,6 +913,7 @@ type (
 		UpdateWorkflowExecution(request *UpdateWorkflowExecutionRequest) error
 		DeleteWorkflowExecution(request *DeleteWorkflowExecutionRequest) error
 		GetCurrentExecution(request *GetCurrentExecutionRequest) (*GetCurrentExecutionResponse, error)
+		ListConcreteExecutions(request *ListConcreteExecutionsRequest) (*ListConcreteExecutionsResponse, error)
 		GetTransferTasks(request *GetTransferTasksRequest) (*GetTransferTasksResponse, error)
 		CompleteTransferTask(request *CompleteTransferTaskRequest) error
 
@@ -827,6 +940,7 @@ type (
 		CreateTasks(request *CreateTasksRequest) (*CreateTasksResponse, error)
 		GetTasks(request *GetTasksRequest) (*GetTasksResponse, error)
 		CompleteTask(request *CompleteTaskRequest) error
+		ListTaskList(request *ListTaskListRequest) (*ListTaskListResponse, error)
 	}
 
 	// HistoryManager is used to manage Workflow Execution HistoryEventBatch
@@ -980,6 +1094,31 @@ func (a *ActivityTimeoutTask) SetVisibilityTimestamp(t time.Time) {
 	a.VisibilityTimestamp = t
 }
 
+// GetType returns the type of the backoff timer task
+func (r *WorkflowBackoffTimerTask) GetType() int {
+	return TaskTypeWorkflowBackoffTimer
+}
+
+// GetTaskID returns the sequence ID.
+func (r *WorkflowBackoffTimerTask) GetTaskID() int64 {
+	return r.TaskID
+}
+
+// SetTaskID sets the sequence ID.
+func (r *WorkflowBackoffTimerTask) SetTaskID(id int64) {
+	r.TaskID = id
+}
+
+// GetVisibilityTimestamp gets the visibility time stamp
+func (r *WorkflowBackoffTimerTask) GetVisibilityTimestamp() time.Time {
+	return r.VisibilityTimestamp
+}
+
+// SetVisibilityTimestamp gets the visibility time stamp
+func (r *WorkflowBackoffTimerTask) SetVisibilityTimestamp(t time.Time) {
+	r.VisibilityTimestamp = t
+}
+
 // GetType returns the type of the timer task
 func (u *UserTimerTask) GetType() int {
 	return TaskTypeUserTimer
@@ -1075,6 +1214,21 @@ func (u *StartChildExecutionTask) SetTaskID(id int64) {
 	u.TaskID = id
 }
 
+// GetType returns the type of the upsert search attributes transfer task
+func (u *UpsertWorkflowSearchAttributesTask) GetType() int {
+	return TransferTaskTypeUpsertWorkflowSearchAttributes
+}
+
+// GetTaskID returns the sequence ID of the upsert search attributes transfer task
+func (u *UpsertWorkflowSearchAttributesTask) GetTaskID() int64 {
+	return u.TaskID
+}
+
+// SetTaskID sets the sequence ID of the upsert search attributes transfer task
+func (u *UpsertWorkflowSearchAttributesTask) SetTaskID(id int64) {
+	u.TaskID = id
+}
+
 // GetType returns the type of the activity task
 func (a *HistoryReplicationTask) GetType() int {
 	return ReplicationTaskTypeHistory
@@ -21,11 +21,18 @@
 package persistence
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"sync/atomic"
+
+	"github.com/golang/snappy"
 	workflow "github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/common"
-	"sync/atomic"
+	"go.uber.org/thriftrw/protocol"
+	"go.uber.org/thriftrw/wire"
 )
 
 type (
@@ -73,8 +80,17 @@ type (
 
 	jsonHistorySerializer struct{}
 
+	// thriftRWHistorySerializer encodes events as a thriftrw binary shared.History
+	// and optionally compresses the result, the compression being part of the encoding type
+	thriftRWHistorySerializer struct {
+		encodingType common.EncodingType
+	}
+
 	serializerFactoryImpl struct {
-		jsonSerializer HistorySerializer
+		jsonSerializer           HistorySerializer
+		thriftRWSerializer       HistorySerializer
+		thriftRWSnappySerializer HistorySerializer
+		thriftRWGzipSerializer   HistorySerializer
 	}
 )
 
@@ -120,11 +136,109 @@ func (j *jsonHistorySerializer) Deserialize(batch *SerializedHistoryEventBatch)
 	return &HistoryEventBatch{Version: batch.Version, Events: events}, nil
 }
 
+// NewThriftRWHistorySerializer returns a thriftrw binary HistorySerializer, compressing
+// the serialized events as required by the given thriftrw encoding type
+func NewThriftRWHistorySerializer(encodingType common.EncodingType) (HistorySerializer, error) {
+	switch encodingType {
+	case common.EncodingTypeThriftRW, common.EncodingTypeThriftRWSnappy, common.EncodingTypeThriftRWGzip:
+		return &thriftRWHistorySerializer{encodingType: encodingType}, nil
+	default:
+		return nil, NewUnknownEncodingTypeError(encodingType)
+	}
+}
+
+func (t *thriftRWHistorySerializer) Serialize(batch *HistoryEventBatch) (*SerializedHistoryEventBatch, error) {
+
+	if batch.Version > GetMaxSupportedHistoryVersion() {
+		err := NewHistoryVersionCompatibilityError(batch.Version, GetMaxSupportedHistoryVersion())
+		return nil, &HistorySerializationError{msg: err.Error()}
+	}
+
+	history := &workflow.History{Events: batch.Events}
+	value, err := history.ToWire()
+	if err != nil {
+		return nil, &HistorySerializationError{msg: err.Error()}
+	}
+
+	var buf bytes.Buffer
+	if err := protocol.Binary.Encode(value, &buf); err != nil {
+		return nil, &HistorySerializationError{msg: err.Error()}
+	}
+
+	data, err := t.compress(buf.Bytes())
+	if err != nil {
+		return nil, &HistorySerializationError{msg: err.Error()}
+	}
+	return NewSerializedHistoryEventBatch(data, t.encodingType, batch.Version), nil
+}
+
+func (t *thriftRWHistorySerializer) Deserialize(batch *SerializedHistoryEventBatch) (*HistoryEventBatch, error) {
+
+	if batch.Version > GetMaxSupportedHistoryVersion() {
+		err := NewHistoryVersionCompatibilityError(batch.Version, GetMaxSupportedHistoryVersion())
+		return nil, &HistoryDeserializationError{msg: err.Error()}
+	}
+
+	data, err := t.decompress(batch.Data)
+	if err != nil {
+		return nil, &HistoryDeserializationError{msg: err.Error()}
+	}
+
+	value, err := protocol.Binary.Decode(bytes.NewReader(data), wire.TStruct)
+	if err != nil {
+		return nil, &HistoryDeserializationError{msg: err.Error()}
+	}
+
+	var history workflow.History
+	if err := history.FromWire(value); err != nil {
+		return nil, &HistoryDeserializationError{msg: err.Error()}
+	}
+	return &HistoryEventBatch{Version: batch.Version, Events: history.Events}, nil
+}
+
+func (t *thriftRWHistorySerializer) compress(data []byte) ([]byte, error) {
+	switch t.encodingType {
+	case common.EncodingTypeThriftRWSnappy:
+		return snappy.Encode(nil, data), nil
+	case common.EncodingTypeThriftRWGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return data, nil
+	}
+}
+
+func (t *thriftRWHistorySerializer) decompress(data []byte) ([]byte, error) {
+	switch t.encodingType {
+	case common.EncodingTypeThriftRWSnappy:
+		return snappy.Decode(nil, data)
+	case common.EncodingTypeThriftRWGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return ioutil.ReadAll(r)
+	default:
+		return data, nil
+	}
+}
+
 // NewHistorySerializerFactory creates and returns an instance
 // of HistorySerializerFactory
 func NewHistorySerializerFactory() HistorySerializerFactory {
 	return &serializerFactoryImpl{
-		jsonSerializer: NewJSONHistorySerializer(),
+		jsonSerializer:           NewJSONHistorySerializer(),
+		thriftRWSerializer:       &thriftRWHistorySerializer{encodingType: common.EncodingTypeThriftRW},
+		thriftRWSnappySerializer: &thriftRWHistorySerializer{encodingType: common.EncodingTypeThriftRWSnappy},
+		thriftRWGzipSerializer:   &thriftRWHistorySerializer{encodingType: common.EncodingTypeThriftRWGzip},
 	}
 }
 
@@ -133,6 +247,12 @@ func (f *serializerFactoryImpl) Get(encodingType common.EncodingType) (HistorySe
 	switch encodingType {
 	case common.EncodingTypeJSON:
 		return f.jsonSerializer, nil
+	case common.EncodingTypeThriftRW:
+		return f.thriftRWSerializer, nil
+	case common.EncodingTypeThriftRWSnappy:
+		return f.thriftRWSnappySerializer, nil
+	case common.EncodingTypeThriftRWGzip:
+		return f.thriftRWGzipSerializer, nil
 	default:
 		return nil, NewUnknownEncodingTypeError(encodingType)
 	}
@@ -134,3 +134,56 @@ func (s *historySerializerSuite) TestSerializerFactory() {
 	succ := common.AwaitWaitGroup(&doneWG, 10*time.Second)
 	s.True(succ, "test timed out")
 }
+
+func (s *historySerializerSuite) TestThriftRWSerializer() {
+	factory := NewHistorySerializerFactory()
+
+	event1 := &workflow.HistoryEvent{
+		EventId:   common.Int64Ptr(999),
+		Timestamp: common.Int64Ptr(time.Now().UnixNano()),
+		EventType: common.EventTypePtr(workflow.EventTypeActivityTaskCompleted),
+		ActivityTaskCompletedEventAttributes: &workflow.ActivityTaskCompletedEventAttributes{
+			Result:           []byte("result-1-event-1"),
+			ScheduledEventId: common.Int64Ptr(4),
+			StartedEventId:   common.Int64Ptr(5),
+			Identity:         common.StringPtr("event-1"),
+		},
+	}
+
+	encodingTypes := []common.EncodingType{
+		common.EncodingTypeThriftRW,
+		common.EncodingTypeThriftRWSnappy,
+		common.EncodingTypeThriftRWGzip,
+	}
+	for _, encodingType := range encodingTypes {
+		serializer, err := factory.Get(encodingType)
+		s.Nil(err)
+		_, ok := serializer.(*thriftRWHistorySerializer)
+		s.True(ok)
+
+		eventBatch := NewHistoryEventBatch(GetMaxSupportedHistoryVersion()+1, []*workflow.HistoryEvent{event1})
+		_, err = serializer.Serialize(eventBatch)
+		s.NotNil(err)
+		_, ok = err.(*HistorySerializationError)
+		s.True(ok)
+
+		eventBatch.Version = 1
+		sh, err := serializer.Serialize(eventBatch)
+		s.Nil(err)
+		s.Equal(1, sh.Version)
+		s.Equal(encodingType, sh.EncodingType)
+
+		deserializer, err := factory.Get(sh.EncodingType)
+		s.Nil(err)
+		dh, err := deserializer.Deserialize(sh)
+		s.Nil(err)
+		s.Equal(1, dh.Version)
+		s.Equal(1, len(dh.Events))
+		s.Equal(event1, dh.Events[0])
+	}
+
+	_, err := NewThriftRWHistorySerializer(common.EncodingTypeJSON)
+	s.NotNil(err)
+	_, ok := err.(*UnknownEncodingTypeError)
+	s.True(ok)
+}
@@ -0,0 +1,633 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pborman/uuid"
+	"github.com/uber-common/bark"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+)
+
+type (
+	inMemoryExecutionPersistence struct {
+		store   *InMemoryStore
+		shardID int
+		logger  bark.Logger
+	}
+
+	// inMemoryTasks are the queue entries written together with a workflow execution
+	inMemoryTasks struct {
+		transferTasks    []*TransferTaskInfo
+		replicationTasks []*ReplicationTaskInfo
+		timerTasks       []*TimerTaskInfo
+	}
+
+	// inMemoryListExecutionsPageToken is the key of the last execution returned in a page
+	inMemoryListExecutionsPageToken struct {
+		DomainID   string
+		WorkflowID string
+		RunID      string
+	}
+)
+
+// newInMemoryWorkflowExecutionPersistence is used to create an instance of ExecutionManager implementation for a shard
+func newInMemoryWorkflowExecutionPersistence(shardID int, store *InMemoryStore, logger bark.Logger) ExecutionManager {
+	return &inMemoryExecutionPersistence{store: store, shardID: shardID, logger: logger}
+}
+
+// Close is a no-op, the data is owned by the InMemoryStore
+func (d *inMemoryExecutionPersistence) Close() {
+}
+
+func (d *inMemoryExecutionPersistence) CreateWorkflowExecution(request *CreateWorkflowExecutionRequest) (
+	*CreateWorkflowExecutionResponse, error) {
+	tasks, err := newInMemoryTasks(request.TransferTasks, request.ReplicationTasks, request.TimerTasks,
+		request.DomainID, request.Execution.GetWorkflowId(), request.Execution.GetRunId())
+	if err != nil {
+		return nil, err
+	}
+
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	if err := d.store.assertShardOwnership(d.shardID, request.RangeID, "create workflow execution"); err != nil {
+		return nil, err
+	}
+
+	executions := d.store.shardExecutions(d.shardID)
+	if err := executions.validateCreateWorkflowExecution(request); err != nil {
+		return nil, err
+	}
+
+	executions.createWorkflowExecution(request, time.Now())
+	executions.addTasks(tasks)
+
+	return &CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil
+}
+
+func (d *inMemoryExecutionPersistence) GetWorkflowExecution(request *GetWorkflowExecutionRequest) (
+	*GetWorkflowExecutionResponse, error) {
+	key := inMemoryExecutionKey{
+		domainID:   request.DomainID,
+		workflowID: request.Execution.GetWorkflowId(),
+		runID:      request.Execution.GetRunId(),
+	}
+
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	execution, ok := d.store.shardExecutions(d.shardID).executions[key]
+	if !ok {
+		return nil, &workflow.EntityNotExistsError{
+			Message: fmt.Sprintf("Workflow execution not found.  WorkflowId: %v, RunId: %v",
+				key.workflowID, key.runID),
+		}
+	}
+
+	return &GetWorkflowExecutionResponse{State: execution.mutableState()}, nil
+}
+
+func (d *inMemoryExecutionPersistence) UpdateWorkflowExecution(request *UpdateWorkflowExecutionRequest) error {
+	now := time.Now()
+	executionInfo := *request.ExecutionInfo
+	executionInfo.LastUpdatedTimestamp = now
+	key := inMemoryExecutionKey{
+		domainID:   executionInfo.DomainID,
+		workflowID: executionInfo.WorkflowID,
+		runID:      executionInfo.RunID,
+	}
+
+	tasks, err := newInMemoryTasks(request.TransferTasks, request.ReplicationTasks, request.TimerTasks,
+		key.domainID, key.workflowID, key.runID)
+	if err != nil {
+		return err
+	}
+	var newRunTasks *inMemoryTasks
+	if request.ContinueAsNew != nil {
+		startReq := request.ContinueAsNew
+		newRunTasks, err = newInMemoryTasks(startReq.TransferTasks, nil, startReq.TimerTasks, startReq.DomainID,
+			startReq.Execution.GetWorkflowId(), startReq.Execution.GetRunId())
+		if err != nil {
+			return err
+		}
+	}
+
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	// Every condition is verified before anything is written, so a failed update leaves no partial state behind
+	if err := d.store.assertShardOwnership(d.shardID, request.RangeID, "update workflow execution"); err != nil {
+		return err
+	}
+
+	executions := d.store.shardExecutions(d.shardID)
+	execution, ok := executions.executions[key]
+	if !ok {
+		return &ConditionFailedError{
+			Msg: fmt.Sprintf("Failed to update workflow execution.  WorkflowId: %v, RunId: %v not found",
+				key.workflowID, key.runID),
+		}
+	}
+	if execution.executionInfo.NextEventID != request.Condition {
+		return &ConditionFailedError{
+			Msg: fmt.Sprintf("Failed to update workflow execution.  Request Condition: %v, Actual Value: %v",
+				request.Condition, execution.executionInfo.NextEventID),
+		}
+	}
+	if request.ContinueAsNew != nil {
+		if err := executions.validateCreateWorkflowExecution(request.ContinueAsNew); err != nil {
+			return err
+		}
+	}
+
+	execution.executionInfo = &executionInfo
+	if request.ReplicationState != nil {
+		// Updates will be called with null ReplicationState while the feature is disabled
+		execution.replicationState = copyInMemoryReplicationState(request.ReplicationState)
+	}
+	execution.update(request)
+
+	executions.addTasks(tasks)
+	if request.DeleteTimerTask != nil {
+		delete(executions.timerTasks, newInMemoryTimerTaskKey(GetVisibilityTSFrom(request.DeleteTimerTask),
+			request.DeleteTimerTask.GetTaskID()))
+	}
+
+	if request.ContinueAsNew != nil {
+		executions.createWorkflowExecution(request.ContinueAsNew, now)
+		executions.addTasks(newRunTasks)
+	} else if request.FinishExecution {
+		// The current execution is kept and only records that the run is closed, the same as the SQL persistence
+		executions.currentExecutions[inMemoryCurrentExecutionKey{
+			domainID:   key.domainID,
+			workflowID: key.workflowID,
+		}] = &GetCurrentExecutionResponse{
+			RunID:          key.runID,
+			StartRequestID: executionInfo.CreateRequestID,
+			State:          executionInfo.State,
+			CloseStatus:    executionInfo.CloseStatus,
+		}
+	}
+
+	return nil
+}
+
+func (d *inMemoryExecutionPersistence) DeleteWorkflowExecution(request *DeleteWorkflowExecutionRequest) error {
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	delete(d.store.shardExecutions(d.shardID).executions, inMemoryExecutionKey{
+		domainID:   request.DomainID,
+		workflowID: request.WorkflowID,
+		runID:      request.RunID,
+	})
+	return nil
+}
+
+func (d *inMemoryExecutionPersistence) GetCurrentExecution(request *GetCurrentExecutionRequest) (
+	*GetCurrentExecutionResponse, error) {
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	current, ok := d.store.shardExecutions(d.shardID).currentExecutions[inMemoryCurrentExecutionKey{
+		domainID:   request.DomainID,
+		workflowID: request.WorkflowID,
+	}]
+	if !ok {
+		return nil, &workflow.EntityNotExistsError{
+			Message: fmt.Sprintf("Workflow execution not found.  WorkflowId: %v", request.WorkflowID),
+		}
+	}
+
+	response := *current
+	return &response, nil
+}
+
+func (d *inMemoryExecutionPersistence) ListConcreteExecutions(request *ListConcreteExecutionsRequest) (
+	*ListConcreteExecutionsResponse, error) {
+	token := &inMemoryListExecutionsPageToken{}
+	if len(request.NextPageToken) > 0 {
+		if err := json.Unmarshal(request.NextPageToken, token); err != nil {
+			return nil, &workflow.BadRequestError{
+				Message: fmt.Sprintf("ListConcreteExecutions operation failed.  Invalid next page token. Error: %v",
+					err),
+			}
+		}
+	}
+	lastKey := inMemoryExecutionKey{domainID: token.DomainID, workflowID: token.WorkflowID, runID: token.RunID}
+
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	var keys []inMemoryExecutionKey
+	executions := d.store.shardExecutions(d.shardID).executions
+	for key := range executions {
+		if lastKey.less(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].less(keys[j])
+	})
+	if request.PageSize > 0 && len(keys) > request.PageSize {
+		keys = keys[:request.PageSize]
+	}
+
+	response := &ListConcreteExecutionsResponse{}
+	for _, key := range keys {
+		info := *executions[key].executionInfo
+		response.ExecutionInfos = append(response.ExecutionInfos, &info)
+	}
+
+	if request.PageSize > 0 && len(keys) == request.PageSize {
+		lastKey := keys[len(keys)-1]
+		nextPageToken, err := json.Marshal(&inMemoryListExecutionsPageToken{
+			DomainID:   lastKey.domainID,
+			WorkflowID: lastKey.workflowID,
+			RunID:      lastKey.runID,
+		})
+		if err != nil {
+			return nil, &workflow.InternalServiceError{
+				Message: fmt.Sprintf("ListConcreteExecutions operation failed. Error: %v", err),
+			}
+		}
+		response.NextPageToken = nextPageToken
+	}
+
+	return response, nil
+}
+
+func (d *inMemoryExecutionPersistence) GetTransferTasks(request *GetTransferTasksRequest) (*GetTransferTasksResponse,
+	error) {
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	response := &GetTransferTasksResponse{}
+	for taskID, task := range d.store.shardExecutions(d.shardID).transferTasks {
+		if taskID > request.ReadLevel && taskID <= request.MaxReadLevel {
+			info := *task
+			response.Tasks = append(response.Tasks, &info)
+		}
+	}
+	sort.Slice(response.Tasks, func(i, j int) bool {
+		return response.Tasks[i].TaskID < response.Tasks[j].TaskID
+	})
+	if request.BatchSize > 0 && len(response.Tasks) > request.BatchSize {
+		response.Tasks = response.Tasks[:request.BatchSize]
+	}
+
+	return response, nil
+}
+
+func (d *inMemoryExecutionPersistence) CompleteTransferTask(request *CompleteTransferTaskRequest) error {
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	delete(d.store.shardExecutions(d.shardID).transferTasks, request.TaskID)
+	return nil
+}
+
+func (d *inMemoryExecutionPersistence) GetReplicationTasks(request *GetReplicationTasksRequest) (
+	*GetReplicationTasksResponse, error) {
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	response := &GetReplicationTasksResponse{}
+	for taskID, task := range d.store.shardExecutions(d.shardID).replicationTasks {
+		if taskID > request.ReadLevel && taskID <= request.MaxReadLevel {
+			info := *task
+			response.Tasks = append(response.Tasks, &info)
+		}
+	}
+	sort.Slice(response.Tasks, func(i, j int) bool {
+		return response.Tasks[i].TaskID < response.Tasks[j].TaskID
+	})
+	if request.BatchSize > 0 && len(response.Tasks) > request.BatchSize {
+		response.Tasks = response.Tasks[:request.BatchSize]
+	}
+
+	return response, nil
+}
+
+func (d *inMemoryExecutionPersistence) CompleteReplicationTask(request *CompleteReplicationTaskRequest) error {
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	delete(d.store.shardExecutions(d.shardID).replicationTasks, request.TaskID)
+	return nil
+}
+
+func (d *inMemoryExecutionPersistence) GetTimerIndexTasks(request *GetTimerIndexTasksRequest) (
+	*GetTimerIndexTasksResponse, error) {
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	response := &GetTimerIndexTasksResponse{}
+	for _, timer := range d.store.shardExecutions(d.shardID).timerTasks {
+		if !timer.VisibilityTimestamp.Before(request.MinTimestamp) &&
+			timer.VisibilityTimestamp.Before(request.MaxTimestamp) {
+			info := *timer
+			response.Timers = append(response.Timers, &info)
+		}
+	}
+	sort.Slice(response.Timers, func(i, j int) bool {
+		ti, tj := response.Timers[i], response.Timers[j]
+		if !ti.VisibilityTimestamp.Equal(tj.VisibilityTimestamp) {
+			return ti.VisibilityTimestamp.Before(tj.VisibilityTimestamp)
+		}
+		return ti.TaskID < tj.TaskID
+	})
+	if request.BatchSize > 0 && len(response.Timers) > request.BatchSize {
+		response.Timers = response.Timers[:request.BatchSize]
+	}
+
+	// A full page means there could be more timers within the range, the token only signals that to the caller
+	if request.BatchSize > 0 && len(response.Timers) == request.BatchSize {
+		lastTimer := response.Timers[len(response.Timers)-1]
+		response.NextPageToken = newInMemoryPageToken(lastTimer.VisibilityTimestamp.UnixNano())
+	}
+
+	return response, nil
+}
+
+func (d *inMemoryExecutionPersistence) CompleteTimerTask(request *CompleteTimerTaskRequest) error {
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	delete(d.store.shardExecutions(d.shardID).timerTasks, newInMemoryTimerTaskKey(request.VisibilityTimestamp,
+		request.TaskID))
+	return nil
+}
+
+// validateCreateWorkflowExecution verifies the current execution allows the request to create a new run
+func (e *inMemoryShardExecutions) validateCreateWorkflowExecution(request *CreateWorkflowExecutionRequest) error {
+	workflowID := request.Execution.GetWorkflowId()
+	current, ok := e.currentExecutions[inMemoryCurrentExecutionKey{
+		domainID:   request.DomainID,
+		workflowID: workflowID,
+	}]
+	switch {
+	case !ok && request.ContinueAsNew:
+		return &ConditionFailedError{
+			Msg: fmt.Sprintf("Failed to continue workflow execution as new.  WorkflowId: %v, PreviousRunId: %v, "+
+				"current execution not found", workflowID, request.PreviousRunID),
+		}
+	case ok && (!request.ContinueAsNew || current.RunID != request.PreviousRunID):
+		return &WorkflowExecutionAlreadyStartedError{
+			Msg: fmt.Sprintf("Workflow execution already running. WorkflowId: %v, RunId: %v, rangeID: %v",
+				workflowID, current.RunID, request.RangeID),
+			StartRequestID: current.StartRequestID,
+			RunID:          current.RunID,
+			State:          current.State,
+			CloseStatus:    current.CloseStatus,
+		}
+	}
+	return nil
+}
+
+// createWorkflowExecution writes the new run and points the current execution to it, the request must have been
+// validated by validateCreateWorkflowExecution
+func (e *inMemoryShardExecutions) createWorkflowExecution(request *CreateWorkflowExecutionRequest, now time.Time) {
+	domainID := request.DomainID
+	workflowID := request.Execution.GetWorkflowId()
+	runID := request.Execution.GetRunId()
+
+	info := &WorkflowExecutionInfo{
+		DomainID:             domainID,
+		WorkflowID:           workflowID,
+		RunID:                runID,
+		InitiatedID:          emptyInitiatedID,
+		TaskList:             request.TaskList,
+		WorkflowTypeName:     request.WorkflowTypeName,
+		WorkflowTimeout:      request.WorkflowTimeout,
+		DecisionTimeoutValue: request.DecisionTimeoutValue,
+		ExecutionContext:     request.ExecutionContext,
+		State:                WorkflowStateCreated,
+		CloseStatus:          WorkflowCloseStatusNone,
+		LastFirstEventID:     common.FirstEventID,
+		NextEventID:          request.NextEventID,
+		LastProcessedEvent:   request.LastProcessedEvent,
+		StartTimestamp:       now,
+		LastUpdatedTimestamp: now,
+		CreateRequestID:      request.RequestID,
+		DecisionScheduleID:   request.DecisionScheduleID,
+		DecisionStartedID:    request.DecisionStartedID,
+		DecisionTimeout:      request.DecisionStartToCloseTimeout,
+		Attempt:              request.Attempt,
+		HasRetryPolicy:       request.HasRetryPolicy,
+		InitialInterval:      request.InitialInterval,
+		BackoffCoefficient:   request.BackoffCoefficient,
+		MaximumInterval:      request.MaximumInterval,
+		ExpirationTime:       request.ExpirationTime,
+		MaximumAttempts:      request.MaximumAttempts,
+		NonRetriableErrors:   request.NonRetriableErrors,
+		CronSchedule:         request.CronSchedule,
+		SearchAttributes:     request.SearchAttributes,
+		Priority:             request.Priority,
+		BuildID:              request.BuildID,
+	}
+
+	state := WorkflowStateRunning
+	if request.ParentExecution != nil {
+		info.ParentDomainID = request.ParentDomainID
+		info.ParentWorkflowID = request.ParentExecution.GetWorkflowId()
+		info.ParentRunID = request.ParentExecution.GetRunId()
+		info.InitiatedID = request.InitiatedID
+		state = WorkflowStateCreated
+	}
+
+	e.currentExecutions[inMemoryCurrentExecutionKey{domainID: domainID, workflowID: workflowID}] =
+		&GetCurrentExecutionResponse{
+			RunID:          runID,
+			StartRequestID: request.RequestID,
+			State:          state,
+			CloseStatus:    WorkflowCloseStatusNone,
+		}
+
+	activityInfos := make(map[int64]*ActivityInfo, len(request.ActivityInfos))
+	for _, v := range request.ActivityInfos {
+		info := *v
+		activityInfos[info.ScheduleID] = &info
+	}
+	timerInfos := make(map[string]*TimerInfo, len(request.TimerInfos))
+	for _, v := range request.TimerInfos {
+		info := *v
+		timerInfos[info.TimerID] = &info
+	}
+	signalRequestedIDs := make(map[string]struct{}, len(request.SignalRequestedIDs))
+	for _, signalID := range request.SignalRequestedIDs {
+		signalRequestedIDs[signalID] = struct{}{}
+	}
+
+	e.executions[inMemoryExecutionKey{domainID: domainID, workflowID: workflowID, runID: runID}] =
+		&inMemoryExecution{
+			executionInfo:       info,
+			replicationState:    copyInMemoryReplicationState(request.ReplicationState),
+			activityInfos:       activityInfos,
+			timerInfos:          timerInfos,
+			childExecutionInfos: make(map[int64]*ChildExecutionInfo),
+			requestCancelInfos:  make(map[int64]*RequestCancelInfo),
+			signalInfos:         make(map[int64]*SignalInfo),
+			signalRequestedIDs:  signalRequestedIDs,
+		}
+}
+
+func (e *inMemoryShardExecutions) addTasks(tasks *inMemoryTasks) {
+	for _, task := range tasks.transferTasks {
+		e.transferTasks[task.TaskID] = task
+	}
+	for _, task := range tasks.replicationTasks {
+		e.replicationTasks[task.TaskID] = task
+	}
+	for _, task := range tasks.timerTasks {
+		e.timerTasks[newInMemoryTimerTaskKey(task.VisibilityTimestamp, task.TaskID)] = task
+	}
+}
+
+func newInMemoryTasks(transferTasks, replicationTasks, timerTasks []Task, domainID, workflowID,
+	runID string) (*inMemoryTasks, error) {
+	tasks := &inMemoryTasks{}
+	for _, task := range transferTasks {
+		info, err := newTransferTaskInfo(task, domainID, workflowID, runID)
+		if err != nil {
+			return nil, err
+		}
+		tasks.transferTasks = append(tasks.transferTasks, info)
+	}
+	for _, task := range replicationTasks {
+		info, err := newReplicationTaskInfo(task, domainID, workflowID, runID)
+		if err != nil {
+			return nil, err
+		}
+		tasks.replicationTasks = append(tasks.replicationTasks, info)
+	}
+	for _, task := range timerTasks {
+		tasks.timerTasks = append(tasks.timerTasks, newTimerTaskInfo(task, domainID, workflowID, runID))
+	}
+	return tasks, nil
+}
+
+func (e *inMemoryExecution) mutableState() *WorkflowMutableState {
+	executionInfo := *e.executionInfo
+	state := &WorkflowMutableState{
+		ActivitInfos:        make(map[int64]*ActivityInfo, len(e.activityInfos)),
+		TimerInfos:          make(map[string]*TimerInfo, len(e.timerInfos)),
+		ChildExecutionInfos: make(map[int64]*ChildExecutionInfo, len(e.childExecutionInfos)),
+		RequestCancelInfos:  make(map[int64]*RequestCancelInfo, len(e.requestCancelInfos)),
+		SignalInfos:         make(map[int64]*SignalInfo, len(e.signalInfos)),
+		SignalRequestedIDs:  make(map[string]struct{}, len(e.signalRequestedIDs)),
+		ExecutionInfo:       &executionInfo,
+		ReplicationState:    copyInMemoryReplicationState(e.replicationState),
+	}
+
+	for k, v := range e.activityInfos {
+		info := *v
+		state.ActivitInfos[k] = &info
+	}
+	for k, v := range e.timerInfos {
+		info := *v
+		state.TimerInfos[k] = &info
+	}
+	for k, v := range e.childExecutionInfos {
+		info := *v
+		state.ChildExecutionInfos[k] = &info
+	}
+	for k, v := range e.requestCancelInfos {
+		info := *v
+		state.RequestCancelInfos[k] = &info
+	}
+	for k, v := range e.signalInfos {
+		info := *v
+		state.SignalInfos[k] = &info
+	}
+	for k := range e.signalRequestedIDs {
+		state.SignalRequestedIDs[k] = struct{}{}
+	}
+	for _, v := range e.bufferedEvents {
+		eventBatch := *v
+		state.BufferedEvents = append(state.BufferedEvents, &eventBatch)
+	}
+
+	return state
+}
+
+func (e *inMemoryExecution) update(request *UpdateWorkflowExecutionRequest) {
+	for _, v := range request.UpsertActivityInfos {
+		info := *v
+		e.activityInfos[info.ScheduleID] = &info
+	}
+	if request.DeleteActivityInfo != nil {
+		delete(e.activityInfos, *request.DeleteActivityInfo)
+	}
+
+	for _, v := range request.UpserTimerInfos {
+		info := *v
+		e.timerInfos[info.TimerID] = &info
+	}
+	for _, timerID := range request.DeleteTimerInfos {
+		delete(e.timerInfos, timerID)
+	}
+
+	for _, v := range request.UpsertChildExecutionInfos {
+		info := *v
+		e.childExecutionInfos[info.InitiatedID] = &info
+	}
+	if request.DeleteChildExecutionInfo != nil {
+		delete(e.childExecutionInfos, *request.DeleteChildExecutionInfo)
+	}
+
+	for _, v := range request.UpsertRequestCancelInfos {
+		info := *v
+		e.requestCancelInfos[info.InitiatedID] = &info
+	}
+	if request.DeleteRequestCancelInfo != nil {
+		delete(e.requestCancelInfos, *request.DeleteRequestCancelInfo)
+	}
+
+	for _, v := range request.UpsertSignalInfos {
+		info := *v
+		e.signalInfos[info.InitiatedID] = &info
+	}
+	if request.DeleteSignalInfo != nil {
+		delete(e.signalInfos, *request.DeleteSignalInfo)
+	}
+
+	for _, signalID := range request.UpsertSignalRequestedIDs {
+		e.signalRequestedIDs[signalID] = struct{}{}
+	}
+	if request.DeleteSignalRequestedID != "" {
+		delete(e.signalRequestedIDs, request.DeleteSignalRequestedID)
+	}
+
+	if request.ClearBufferedEvents {
+		e.bufferedEvents = nil
+	} else if request.NewBufferedEvents != nil {
+		eventBatch := *request.NewBufferedEvents
+		e.bufferedEvents = append(e.bufferedEvents, &eventBatch)
+	}
+}
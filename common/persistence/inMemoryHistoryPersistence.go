@@ -0,0 +1,146 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/uber-common/bark"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+)
+
+type (
+	inMemoryHistoryPersistence struct {
+		store  *InMemoryStore
+		logger bark.Logger
+	}
+)
+
+// NewInMemoryHistoryPersistence is used to create an instance of HistoryManager implementation
+func NewInMemoryHistoryPersistence(store *InMemoryStore, logger bark.Logger) (HistoryManager, error) {
+	return &inMemoryHistoryPersistence{store: store, logger: logger}, nil
+}
+
+// Close is a no-op, the data is owned by the InMemoryStore
+func (h *inMemoryHistoryPersistence) Close() {
+}
+
+func (h *inMemoryHistoryPersistence) AppendHistoryEvents(request *AppendHistoryEventsRequest) error {
+	key := inMemoryExecutionKey{
+		domainID:   request.DomainID,
+		workflowID: request.Execution.GetWorkflowId(),
+		runID:      request.Execution.GetRunId(),
+	}
+
+	h.store.Lock()
+	defer h.store.Unlock()
+
+	batches := h.store.history[key]
+	batch, ok := batches[request.FirstEventID]
+	if request.Overwrite {
+		if !ok || batch.rangeID > request.RangeID || batch.transactionID >= request.TransactionID {
+			return &ConditionFailedError{
+				Msg: "Failed to append history events.",
+			}
+		}
+	} else if ok {
+		return &ConditionFailedError{
+			Msg: "Failed to append history events.",
+		}
+	}
+
+	if batches == nil {
+		batches = make(map[int64]*inMemoryHistoryBatch)
+		h.store.history[key] = batches
+	}
+	batches[request.FirstEventID] = &inMemoryHistoryBatch{
+		rangeID:       request.RangeID,
+		transactionID: request.TransactionID,
+		events:        *request.Events,
+	}
+	return nil
+}
+
+func (h *inMemoryHistoryPersistence) GetWorkflowExecutionHistory(request *GetWorkflowExecutionHistoryRequest) (
+	*GetWorkflowExecutionHistoryResponse, error) {
+	execution := request.Execution
+
+	// the page token is the first event id of the first batch of the next page
+	firstEventID := request.FirstEventID
+	if len(request.NextPageToken) == inMemoryPageTokenSize {
+		firstEventID = int64(binary.BigEndian.Uint64(request.NextPageToken))
+	} else if len(request.NextPageToken) != 0 {
+		return nil, &workflow.BadRequestError{
+			Message: "GetWorkflowExecutionHistory operation failed.  Invalid next page token.",
+		}
+	}
+
+	h.store.Lock()
+	defer h.store.Unlock()
+
+	var firstEventIDs []int64
+	batches := h.store.history[inMemoryExecutionKey{
+		domainID:   request.DomainID,
+		workflowID: execution.GetWorkflowId(),
+		runID:      execution.GetRunId(),
+	}]
+	for id := range batches {
+		if id >= firstEventID && id < request.NextEventID {
+			firstEventIDs = append(firstEventIDs, id)
+		}
+	}
+	sort.Slice(firstEventIDs, func(i, j int) bool { return firstEventIDs[i] < firstEventIDs[j] })
+
+	if len(firstEventIDs) == 0 && len(request.NextPageToken) == 0 {
+		return nil, &workflow.EntityNotExistsError{
+			Message: fmt.Sprintf("Workflow execution history not found.  WorkflowId: %v, RunId: %v",
+				execution.GetWorkflowId(), execution.GetRunId()),
+		}
+	}
+
+	// the same as cassandra, the last page has an empty but non nil token
+	response := &GetWorkflowExecutionHistoryResponse{NextPageToken: []byte{}}
+	if request.PageSize > 0 && len(firstEventIDs) >= request.PageSize {
+		firstEventIDs = firstEventIDs[:request.PageSize]
+		response.NextPageToken = newInMemoryPageToken(firstEventIDs[len(firstEventIDs)-1] + 1)
+	}
+	for _, id := range firstEventIDs {
+		response.Events = append(response.Events, batches[id].events)
+	}
+
+	return response, nil
+}
+
+func (h *inMemoryHistoryPersistence) DeleteWorkflowExecutionHistory(
+	request *DeleteWorkflowExecutionHistoryRequest) error {
+	h.store.Lock()
+	defer h.store.Unlock()
+
+	delete(h.store.history, inMemoryExecutionKey{
+		domainID:   request.DomainID,
+		workflowID: request.Execution.GetWorkflowId(),
+		runID:      request.Execution.GetRunId(),
+	})
+	return nil
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"fmt"
+
+	"github.com/uber-common/bark"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+)
+
+type (
+	inMemoryMetadataPersistence struct {
+		store              *InMemoryStore
+		currentClusterName string
+		logger             bark.Logger
+	}
+)
+
+// NewInMemoryMetadataPersistence is used to create an instance of MetadataManager implementation
+func NewInMemoryMetadataPersistence(store *InMemoryStore, currentClusterName string,
+	logger bark.Logger) (MetadataManager, error) {
+	return &inMemoryMetadataPersistence{
+		store:              store,
+		currentClusterName: currentClusterName,
+		logger:             logger,
+	}, nil
+}
+
+// Close is a no-op, the data is owned by the InMemoryStore
+func (m *inMemoryMetadataPersistence) Close() {
+}
+
+func (m *inMemoryMetadataPersistence) CreateDomain(request *CreateDomainRequest) (*CreateDomainResponse, error) {
+	m.store.Lock()
+	defer m.store.Unlock()
+
+	existingID, ok := m.store.domainNames[request.Info.Name]
+	if !ok {
+		if _, ok = m.store.domains[request.Info.ID]; ok {
+			existingID = request.Info.ID
+		}
+	}
+	if ok {
+		return nil, &workflow.DomainAlreadyExistsError{
+			Message: fmt.Sprintf("Domain already exists.  DomainId: %v", existingID),
+		}
+	}
+
+	domain := &inMemoryDomain{
+		isGlobalDomain:  request.IsGlobalDomain,
+		configVersion:   request.ConfigVersion,
+		failoverVersion: request.FailoverVersion,
+		dbVersion:       0,
+	}
+	domain.set(request.Info, request.Config, request.ReplicationConfig)
+	m.store.domains[request.Info.ID] = domain
+	m.store.domainNames[request.Info.Name] = request.Info.ID
+
+	return &CreateDomainResponse{ID: request.Info.ID}, nil
+}
+
+func (m *inMemoryMetadataPersistence) GetDomain(request *GetDomainRequest) (*GetDomainResponse, error) {
+	if len(request.ID) > 0 && len(request.Name) > 0 {
+		return nil, &workflow.BadRequestError{
+			Message: "GetDomain operation failed.  Both ID and Name specified in request.",
+		}
+	} else if len(request.ID) == 0 && len(request.Name) == 0 {
+		return nil, &workflow.BadRequestError{
+			Message: "GetDomain operation failed.  Both ID and Name are empty.",
+		}
+	}
+
+	m.store.Lock()
+	defer m.store.Unlock()
+
+	id := request.ID
+	identity := request.ID
+	if len(request.ID) == 0 {
+		id = m.store.domainNames[request.Name]
+		identity = request.Name
+	}
+	domain, ok := m.store.domains[id]
+	if !ok {
+		return nil, &workflow.EntityNotExistsError{
+			Message: fmt.Sprintf("Domain %s does not exist.", identity),
+		}
+	}
+
+	info := *domain.info
+	config := *domain.config
+	response := &GetDomainResponse{
+		Info:              &info,
+		Config:            &config,
+		ReplicationConfig: copyInMemoryDomainReplicationConfig(domain.replicationConfig),
+		IsGlobalDomain:    domain.isGlobalDomain,
+		ConfigVersion:     domain.configVersion,
+		FailoverVersion:   domain.failoverVersion,
+		DBVersion:         domain.dbVersion,
+	}
+	response.ReplicationConfig.ActiveClusterName = GetOrUseDefaultActiveCluster(m.currentClusterName,
+		response.ReplicationConfig.ActiveClusterName)
+	response.ReplicationConfig.Clusters = GetOrUseDefaultClusters(m.currentClusterName,
+		response.ReplicationConfig.Clusters)
+
+	return response, nil
+}
+
+func (m *inMemoryMetadataPersistence) UpdateDomain(request *UpdateDomainRequest) error {
+	m.store.Lock()
+	defer m.store.Unlock()
+
+	// Same as the cassandra implementation, the update is silently skipped if db_version has moved on
+	domain, ok := m.store.domains[m.store.domainNames[request.Info.Name]]
+	if !ok || domain.dbVersion != request.DBVersion {
+		return nil
+	}
+
+	domain.set(request.Info, request.Config, request.ReplicationConfig)
+	domain.configVersion = request.ConfigVersion
+	domain.failoverVersion = request.FailoverVersion
+	domain.dbVersion = request.DBVersion + 1
+	return nil
+}
+
+func (m *inMemoryMetadataPersistence) DeleteDomain(request *DeleteDomainRequest) error {
+	m.store.Lock()
+	defer m.store.Unlock()
+
+	if domain, ok := m.store.domains[request.ID]; ok {
+		delete(m.store.domainNames, domain.info.Name)
+		delete(m.store.domains, request.ID)
+	}
+	return nil
+}
+
+func (m *inMemoryMetadataPersistence) DeleteDomainByName(request *DeleteDomainByNameRequest) error {
+	m.store.Lock()
+	defer m.store.Unlock()
+
+	if id, ok := m.store.domainNames[request.Name]; ok {
+		delete(m.store.domainNames, request.Name)
+		delete(m.store.domains, id)
+	}
+	return nil
+}
+
+func (d *inMemoryDomain) set(info *DomainInfo, config *DomainConfig, replicationConfig *DomainReplicationConfig) {
+	copiedInfo := *info
+	d.info = &copiedInfo
+	d.config = &DomainConfig{}
+	if config != nil {
+		*d.config = *config
+	}
+	d.replicationConfig = copyInMemoryDomainReplicationConfig(replicationConfig)
+	if d.replicationConfig == nil {
+		d.replicationConfig = &DomainReplicationConfig{}
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+const (
+	// inMemoryPageTokenSize is the size of the page tokens holding a single int64 position
+	inMemoryPageTokenSize = 8
+)
+
+type (
+	// InMemoryStore holds the data of the in-memory persistence.  All the managers created on top of the same store
+	// share the data, the same way managers created on top of the same cassandra keyspace do.  Nothing is ever
+	// written to disk, the data is lost once the process exits.
+	InMemoryStore struct {
+		sync.Mutex
+		shards      map[int]*ShardInfo
+		executions  map[int]*inMemoryShardExecutions
+		taskLists   map[inMemoryTaskListKey]*inMemoryTaskList
+		history     map[inMemoryExecutionKey]map[int64]*inMemoryHistoryBatch
+		domains     map[string]*inMemoryDomain
+		domainNames map[string]string
+		visibility  map[inMemoryVisibilityKey]*inMemoryVisibilityRecord
+	}
+
+	inMemoryExecutionKey struct {
+		domainID   string
+		workflowID string
+		runID      string
+	}
+
+	inMemoryCurrentExecutionKey struct {
+		domainID   string
+		workflowID string
+	}
+
+	inMemoryTimerTaskKey struct {
+		visibilityTimestamp int64
+		taskID              int64
+	}
+
+	// inMemoryShardExecutions is the execution state owned by a single history shard
+	inMemoryShardExecutions struct {
+		currentExecutions map[inMemoryCurrentExecutionKey]*GetCurrentExecutionResponse
+		executions        map[inMemoryExecutionKey]*inMemoryExecution
+		transferTasks     map[int64]*TransferTaskInfo
+		replicationTasks  map[int64]*ReplicationTaskInfo
+		timerTasks        map[inMemoryTimerTaskKey]*TimerTaskInfo
+	}
+
+	inMemoryExecution struct {
+		executionInfo       *WorkflowExecutionInfo
+		replicationState    *ReplicationState
+		activityInfos       map[int64]*ActivityInfo
+		timerInfos          map[string]*TimerInfo
+		childExecutionInfos map[int64]*ChildExecutionInfo
+		requestCancelInfos  map[int64]*RequestCancelInfo
+		signalInfos         map[int64]*SignalInfo
+		signalRequestedIDs  map[string]struct{}
+		bufferedEvents      []*SerializedHistoryEventBatch
+	}
+
+	inMemoryTaskListKey struct {
+		domainID string
+		name     string
+		taskType int
+	}
+
+	inMemoryTaskList struct {
+		info  *TaskListInfo
+		tasks map[int64]*TaskInfo
+	}
+
+	inMemoryHistoryBatch struct {
+		rangeID       int64
+		transactionID int64
+		events        SerializedHistoryEventBatch
+	}
+
+	inMemoryDomain struct {
+		info              *DomainInfo
+		config            *DomainConfig
+		replicationConfig *DomainReplicationConfig
+		isGlobalDomain    bool
+		configVersion     int64
+		failoverVersion   int64
+		dbVersion         int64
+	}
+
+	inMemoryVisibilityKey struct {
+		domainID string
+		runID    string
+	}
+
+	inMemoryVisibilityRecord struct {
+		workflowID       string
+		runID            string
+		workflowTypeName string
+		startTime        int64
+		closed           bool
+		closeTime        int64
+		closeStatus      int32
+		historyLength    int64
+		searchAttributes map[string][]byte
+	}
+)
+
+// NewInMemoryStore creates an empty store for the in-memory persistence managers
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{
+		shards:      make(map[int]*ShardInfo),
+		executions:  make(map[int]*inMemoryShardExecutions),
+		taskLists:   make(map[inMemoryTaskListKey]*inMemoryTaskList),
+		history:     make(map[inMemoryExecutionKey]map[int64]*inMemoryHistoryBatch),
+		domains:     make(map[string]*inMemoryDomain),
+		domainNames: make(map[string]string),
+		visibility:  make(map[inMemoryVisibilityKey]*inMemoryVisibilityRecord),
+	}
+}
+
+// shardExecutions returns the execution state of the shard, the caller must hold the store lock
+func (s *InMemoryStore) shardExecutions(shardID int) *inMemoryShardExecutions {
+	executions, ok := s.executions[shardID]
+	if !ok {
+		executions = &inMemoryShardExecutions{
+			currentExecutions: make(map[inMemoryCurrentExecutionKey]*GetCurrentExecutionResponse),
+			executions:        make(map[inMemoryExecutionKey]*inMemoryExecution),
+			transferTasks:     make(map[int64]*TransferTaskInfo),
+			replicationTasks:  make(map[int64]*ReplicationTaskInfo),
+			timerTasks:        make(map[inMemoryTimerTaskKey]*TimerTaskInfo),
+		}
+		s.executions[shardID] = executions
+	}
+	return executions
+}
+
+func newInMemoryPageToken(position int64) []byte {
+	token := make([]byte, inMemoryPageTokenSize)
+	binary.BigEndian.PutUint64(token, uint64(position))
+	return token
+}
+
+// less orders the execution keys by domain, workflow and run, the order the SQL persistence scans executions in
+func (k inMemoryExecutionKey) less(other inMemoryExecutionKey) bool {
+	if k.domainID != other.domainID {
+		return k.domainID < other.domainID
+	}
+	if k.workflowID != other.workflowID {
+		return k.workflowID < other.workflowID
+	}
+	return k.runID < other.runID
+}
+
+func newInMemoryTimerTaskKey(visibilityTimestamp time.Time, taskID int64) inMemoryTimerTaskKey {
+	return inMemoryTimerTaskKey{visibilityTimestamp: visibilityTimestamp.UnixNano(), taskID: taskID}
+}
+
+// The store never hands out the objects it holds, every read and write goes through a copy so callers are free to
+// mutate what they pass in or get back, the same way they would with any of the database backed managers.
+
+func copyInMemoryShardInfo(info *ShardInfo) *ShardInfo {
+	copied := *info
+	if info.ClusterTransferAckLevel != nil {
+		copied.ClusterTransferAckLevel = make(map[string]int64, len(info.ClusterTransferAckLevel))
+		for k, v := range info.ClusterTransferAckLevel {
+			copied.ClusterTransferAckLevel[k] = v
+		}
+	}
+	if info.ClusterTimerAckLevel != nil {
+		copied.ClusterTimerAckLevel = make(map[string]time.Time, len(info.ClusterTimerAckLevel))
+		for k, v := range info.ClusterTimerAckLevel {
+			copied.ClusterTimerAckLevel[k] = v
+		}
+	}
+	return &copied
+}
+
+func copyInMemoryReplicationState(state *ReplicationState) *ReplicationState {
+	if state == nil {
+		return nil
+	}
+	copied := *state
+	if state.LastReplicationInfo != nil {
+		copied.LastReplicationInfo = make(map[string]*ReplicationInfo, len(state.LastReplicationInfo))
+		for k, v := range state.LastReplicationInfo {
+			info := *v
+			copied.LastReplicationInfo[k] = &info
+		}
+	}
+	return &copied
+}
+
+func copyInMemoryDomainReplicationConfig(config *DomainReplicationConfig) *DomainReplicationConfig {
+	if config == nil {
+		return nil
+	}
+	copied := *config
+	if config.Clusters != nil {
+		copied.Clusters = make([]*ClusterReplicationConfig, 0, len(config.Clusters))
+		for _, c := range config.Clusters {
+			cluster := *c
+			copied.Clusters = append(copied.Clusters, &cluster)
+		}
+	}
+	return &copied
+}
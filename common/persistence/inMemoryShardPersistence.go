@@ -0,0 +1,133 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/uber-common/bark"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+)
+
+type (
+	inMemoryShardPersistence struct {
+		store              *InMemoryStore
+		currentClusterName string
+		logger             bark.Logger
+	}
+)
+
+// NewInMemoryShardPersistence is used to create an instance of ShardManager implementation
+func NewInMemoryShardPersistence(store *InMemoryStore, currentClusterName string,
+	logger bark.Logger) (ShardManager, error) {
+	return &inMemoryShardPersistence{store: store, currentClusterName: currentClusterName, logger: logger}, nil
+}
+
+// Close is a no-op, the data is owned by the InMemoryStore
+func (d *inMemoryShardPersistence) Close() {
+}
+
+func (d *inMemoryShardPersistence) CreateShard(request *CreateShardRequest) error {
+	shardInfo := copyInMemoryShardInfo(request.ShardInfo)
+	shardInfo.UpdatedAt = time.Now()
+
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	if existing, ok := d.store.shards[shardInfo.ShardID]; ok {
+		return &ShardAlreadyExistError{
+			Msg: fmt.Sprintf("Shard already exists in shards table.  ShardId: %v, RangeId: %v",
+				shardInfo.ShardID, existing.RangeID),
+		}
+	}
+
+	d.store.shards[shardInfo.ShardID] = shardInfo
+	return nil
+}
+
+func (d *inMemoryShardPersistence) GetShard(request *GetShardRequest) (*GetShardResponse, error) {
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	shardInfo, ok := d.store.shards[request.ShardID]
+	if !ok {
+		return nil, &workflow.EntityNotExistsError{
+			Message: fmt.Sprintf("Shard not found.  ShardId: %v", request.ShardID),
+		}
+	}
+
+	info := copyInMemoryShardInfo(shardInfo)
+	if info.ClusterTransferAckLevel == nil {
+		info.ClusterTransferAckLevel = map[string]int64{
+			d.currentClusterName: info.TransferAckLevel,
+		}
+	}
+	if info.ClusterTimerAckLevel == nil {
+		info.ClusterTimerAckLevel = map[string]time.Time{
+			d.currentClusterName: info.TimerAckLevel,
+		}
+	}
+
+	return &GetShardResponse{ShardInfo: info}, nil
+}
+
+func (d *inMemoryShardPersistence) UpdateShard(request *UpdateShardRequest) error {
+	shardInfo := copyInMemoryShardInfo(request.ShardInfo)
+	shardInfo.UpdatedAt = time.Now()
+
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	existing, ok := d.store.shards[shardInfo.ShardID]
+	if !ok || existing.RangeID != request.PreviousRangeID {
+		var rangeID int64
+		if ok {
+			rangeID = existing.RangeID
+		}
+		return &ShardOwnershipLostError{
+			ShardID: shardInfo.ShardID,
+			Msg: fmt.Sprintf("Failed to update shard.  previous_range_id: %v, range_id: %v",
+				request.PreviousRangeID, rangeID),
+		}
+	}
+
+	d.store.shards[shardInfo.ShardID] = shardInfo
+	return nil
+}
+
+// assertShardOwnership verifies the range of the shard is still owned, the caller must hold the store lock
+func (s *InMemoryStore) assertShardOwnership(shardID int, rangeID int64, operation string) error {
+	var actualRangeID int64
+	shardInfo, ok := s.shards[shardID]
+	if ok {
+		actualRangeID = shardInfo.RangeID
+	}
+	if !ok || actualRangeID != rangeID {
+		return &ShardOwnershipLostError{
+			ShardID: shardID,
+			Msg: fmt.Sprintf("Failed to %v.  Request RangeID: %v, Actual RangeID: %v",
+				operation, rangeID, actualRangeID),
+		}
+	}
+	return nil
+}
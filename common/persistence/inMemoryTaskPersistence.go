@@ -0,0 +1,252 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/uber-common/bark"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+)
+
+type (
+	inMemoryTaskPersistence struct {
+		store  *InMemoryStore
+		logger bark.Logger
+	}
+
+	inMemoryListTaskListsPageToken struct {
+		Name     string
+		TaskType int
+	}
+)
+
+// NewInMemoryTaskPersistence is used to create an instance of TaskManager implementation
+func NewInMemoryTaskPersistence(store *InMemoryStore, logger bark.Logger) (TaskManager, error) {
+	return &inMemoryTaskPersistence{store: store, logger: logger}, nil
+}
+
+// Close is a no-op, the data is owned by the InMemoryStore
+func (d *inMemoryTaskPersistence) Close() {
+}
+
+func (d *inMemoryTaskPersistence) LeaseTaskList(request *LeaseTaskListRequest) (*LeaseTaskListResponse, error) {
+	if len(request.TaskList) == 0 {
+		return nil, &workflow.InternalServiceError{
+			Message: fmt.Sprintf("LeaseTaskList requires non empty task list"),
+		}
+	}
+
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	key := inMemoryTaskListKey{domainID: request.DomainID, name: request.TaskList, taskType: request.TaskType}
+	taskList, ok := d.store.taskLists[key]
+	if !ok {
+		// First time task list is used
+		taskList = &inMemoryTaskList{
+			info: &TaskListInfo{
+				DomainID: request.DomainID,
+				Name:     request.TaskList,
+				TaskType: request.TaskType,
+				RangeID:  initialRangeID,
+				AckLevel: 0,
+			},
+			tasks: make(map[int64]*TaskInfo),
+		}
+		d.store.taskLists[key] = taskList
+	} else {
+		taskList.info.RangeID++
+	}
+	taskList.info.Kind = request.TaskListKind
+	taskList.info.LastUpdated = time.Now()
+
+	tli := *taskList.info
+	return &LeaseTaskListResponse{TaskListInfo: &tli}, nil
+}
+
+func (d *inMemoryTaskPersistence) UpdateTaskList(request *UpdateTaskListRequest) (*UpdateTaskListResponse, error) {
+	tli := *request.TaskListInfo
+	tli.LastUpdated = time.Now()
+
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	key := inMemoryTaskListKey{domainID: tli.DomainID, name: tli.Name, taskType: tli.TaskType}
+	taskList, ok := d.store.taskLists[key]
+	if tli.Kind == TaskListKindSticky {
+		// sticky task lists are written unconditionally, the same way they are upserted with a TTL in cassandra
+		if !ok {
+			taskList = &inMemoryTaskList{tasks: make(map[int64]*TaskInfo)}
+			d.store.taskLists[key] = taskList
+		}
+		taskList.info = &tli
+		return &UpdateTaskListResponse{}, nil
+	}
+
+	if !ok || taskList.info.RangeID != tli.RangeID {
+		var rangeID int64
+		if ok {
+			rangeID = taskList.info.RangeID
+		}
+		return nil, &ConditionFailedError{
+			Msg: fmt.Sprintf("Failed to update task list. name: %v, type: %v, rangeID: %v, db rangeID: %v",
+				tli.Name, tli.TaskType, tli.RangeID, rangeID),
+		}
+	}
+
+	taskList.info = &tli
+	return &UpdateTaskListResponse{}, nil
+}
+
+func (d *inMemoryTaskPersistence) CreateTasks(request *CreateTasksRequest) (*CreateTasksResponse, error) {
+	tli := request.TaskListInfo
+
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	// Tasks are only written if range_id didn't change
+	taskList, ok := d.store.taskLists[inMemoryTaskListKey{domainID: tli.DomainID, name: tli.Name,
+		taskType: tli.TaskType}]
+	if !ok || taskList.info.RangeID != tli.RangeID {
+		var rangeID int64
+		if ok {
+			rangeID = taskList.info.RangeID
+		}
+		return nil, &ConditionFailedError{
+			Msg: fmt.Sprintf("Failed to create task. TaskList: %v, taskListType: %v, rangeID: %v, db rangeID: %v",
+				tli.Name, tli.TaskType, tli.RangeID, rangeID),
+		}
+	}
+
+	for _, task := range request.Tasks {
+		taskList.tasks[task.TaskID] = &TaskInfo{
+			DomainID:               tli.DomainID,
+			WorkflowID:             task.Execution.GetWorkflowId(),
+			RunID:                  task.Execution.GetRunId(),
+			TaskID:                 task.TaskID,
+			ScheduleID:             task.Data.ScheduleID,
+			ScheduleToStartTimeout: task.Data.ScheduleToStartTimeout,
+			Priority:               task.Data.Priority,
+			BuildID:                task.Data.BuildID,
+			Expiry:                 task.Data.Expiry,
+		}
+	}
+	taskList.info.LastUpdated = time.Now()
+
+	return &CreateTasksResponse{}, nil
+}
+
+func (d *inMemoryTaskPersistence) GetTasks(request *GetTasksRequest) (*GetTasksResponse, error) {
+	response := &GetTasksResponse{}
+	if request.ReadLevel > request.MaxReadLevel {
+		return response, nil
+	}
+
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	taskList, ok := d.store.taskLists[inMemoryTaskListKey{domainID: request.DomainID, name: request.TaskList,
+		taskType: request.TaskType}]
+	if !ok {
+		return response, nil
+	}
+
+	for taskID, task := range taskList.tasks {
+		if taskID > request.ReadLevel && taskID <= request.MaxReadLevel {
+			info := *task
+			response.Tasks = append(response.Tasks, &info)
+		}
+	}
+	sort.Slice(response.Tasks, func(i, j int) bool {
+		return response.Tasks[i].TaskID < response.Tasks[j].TaskID
+	})
+	if request.BatchSize > 0 && len(response.Tasks) > request.BatchSize {
+		response.Tasks = response.Tasks[:request.BatchSize]
+	}
+
+	return response, nil
+}
+
+func (d *inMemoryTaskPersistence) CompleteTask(request *CompleteTaskRequest) error {
+	tli := request.TaskList
+
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	if taskList, ok := d.store.taskLists[inMemoryTaskListKey{domainID: tli.DomainID, name: tli.Name,
+		taskType: tli.TaskType}]; ok {
+		delete(taskList.tasks, request.TaskID)
+	}
+	return nil
+}
+
+func (d *inMemoryTaskPersistence) ListTaskList(request *ListTaskListRequest) (*ListTaskListResponse, error) {
+	token := &inMemoryListTaskListsPageToken{}
+	if len(request.NextPageToken) > 0 {
+		if err := json.Unmarshal(request.NextPageToken, token); err != nil {
+			return nil, &workflow.BadRequestError{
+				Message: fmt.Sprintf("ListTaskList operation failed.  Invalid next page token. Error: %v", err),
+			}
+		}
+	}
+	less := func(name string, taskType int, otherName string, otherTaskType int) bool {
+		return name < otherName || name == otherName && taskType < otherTaskType
+	}
+
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	response := &ListTaskListResponse{}
+	for key, taskList := range d.store.taskLists {
+		if key.domainID == request.DomainID && less(token.Name, token.TaskType, key.name, key.taskType) {
+			info := *taskList.info
+			response.Items = append(response.Items, &info)
+		}
+	}
+	sort.Slice(response.Items, func(i, j int) bool {
+		return less(response.Items[i].Name, response.Items[i].TaskType, response.Items[j].Name,
+			response.Items[j].TaskType)
+	})
+	if request.PageSize > 0 && len(response.Items) > request.PageSize {
+		response.Items = response.Items[:request.PageSize]
+	}
+
+	if request.PageSize > 0 && len(response.Items) == request.PageSize {
+		lastItem := response.Items[len(response.Items)-1]
+		nextPageToken, err := json.Marshal(&inMemoryListTaskListsPageToken{
+			Name:     lastItem.Name,
+			TaskType: lastItem.TaskType,
+		})
+		if err != nil {
+			return nil, &workflow.InternalServiceError{
+				Message: fmt.Sprintf("ListTaskList operation failed. Error: %v", err),
+			}
+		}
+		response.NextPageToken = nextPageToken
+	}
+
+	return response, nil
+}
@@ -0,0 +1,403 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/uber-common/bark"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/visibilityquery"
+)
+
+type (
+	inMemoryVisibilityPersistence struct {
+		store  *InMemoryStore
+		logger bark.Logger
+	}
+
+	// inMemoryVisibilityPageToken is the position of the last execution returned in a page
+	inMemoryVisibilityPageToken struct {
+		StartTime int64
+		RunID     string
+	}
+
+	// inMemoryVisibilityQueryPageToken is the number of executions returned in the previous pages of a query
+	inMemoryVisibilityQueryPageToken struct {
+		Offset int
+	}
+)
+
+// NewInMemoryVisibilityPersistence is used to create an instance of VisibilityManager implementation
+func NewInMemoryVisibilityPersistence(store *InMemoryStore, logger bark.Logger) (VisibilityManager, error) {
+	return &inMemoryVisibilityPersistence{store: store, logger: logger}, nil
+}
+
+// Close is a no-op, the data is owned by the InMemoryStore
+func (v *inMemoryVisibilityPersistence) Close() {
+}
+
+func (v *inMemoryVisibilityPersistence) RecordWorkflowExecutionStarted(
+	request *RecordWorkflowExecutionStartedRequest) error {
+	key := inMemoryVisibilityKey{domainID: request.DomainUUID, runID: request.Execution.GetRunId()}
+
+	v.store.Lock()
+	defer v.store.Unlock()
+
+	// The closed record wins if the execution has already been recorded as closed
+	if _, ok := v.store.visibility[key]; ok {
+		return nil
+	}
+
+	v.store.visibility[key] = &inMemoryVisibilityRecord{
+		workflowID:       request.Execution.GetWorkflowId(),
+		runID:            request.Execution.GetRunId(),
+		workflowTypeName: request.WorkflowTypeName,
+		startTime:        request.StartTimestamp,
+		searchAttributes: copySearchAttributes(request.SearchAttributes),
+	}
+	return nil
+}
+
+func (v *inMemoryVisibilityPersistence) RecordWorkflowExecutionClosed(
+	request *RecordWorkflowExecutionClosedRequest) error {
+	key := inMemoryVisibilityKey{domainID: request.DomainUUID, runID: request.Execution.GetRunId()}
+
+	v.store.Lock()
+	defer v.store.Unlock()
+
+	// The closed record is kept regardless of the domain retention
+	v.store.visibility[key] = &inMemoryVisibilityRecord{
+		workflowID:       request.Execution.GetWorkflowId(),
+		runID:            request.Execution.GetRunId(),
+		workflowTypeName: request.WorkflowTypeName,
+		startTime:        request.StartTimestamp,
+		closed:           true,
+		closeTime:        request.CloseTimestamp,
+		closeStatus:      int32(request.Status),
+		historyLength:    request.HistoryLength,
+		searchAttributes: copySearchAttributes(request.SearchAttributes),
+	}
+	return nil
+}
+
+func (v *inMemoryVisibilityPersistence) UpsertWorkflowExecution(request *UpsertWorkflowExecutionRequest) error {
+	key := inMemoryVisibilityKey{domainID: request.DomainUUID, runID: request.Execution.GetRunId()}
+
+	v.store.Lock()
+	defer v.store.Unlock()
+
+	record, ok := v.store.visibility[key]
+	if !ok {
+		// The started record has not been written yet
+		v.store.visibility[key] = &inMemoryVisibilityRecord{
+			workflowID:       request.Execution.GetWorkflowId(),
+			runID:            request.Execution.GetRunId(),
+			workflowTypeName: request.WorkflowTypeName,
+			startTime:        request.StartTimestamp,
+			searchAttributes: copySearchAttributes(request.SearchAttributes),
+		}
+		return nil
+	}
+
+	// The closed record already has the final search attributes
+	if !record.closed {
+		record.searchAttributes = copySearchAttributes(request.SearchAttributes)
+	}
+	return nil
+}
+
+func (v *inMemoryVisibilityPersistence) ListOpenWorkflowExecutions(
+	request *ListWorkflowExecutionsRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListOpenWorkflowExecutions", request, false, nil)
+}
+
+func (v *inMemoryVisibilityPersistence) ListClosedWorkflowExecutions(
+	request *ListWorkflowExecutionsRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListClosedWorkflowExecutions", request, true, nil)
+}
+
+func (v *inMemoryVisibilityPersistence) ListOpenWorkflowExecutionsByType(
+	request *ListWorkflowExecutionsByTypeRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListOpenWorkflowExecutionsByType", &request.ListWorkflowExecutionsRequest, false,
+		func(record *inMemoryVisibilityRecord) bool {
+			return record.workflowTypeName == request.WorkflowTypeName
+		})
+}
+
+func (v *inMemoryVisibilityPersistence) ListClosedWorkflowExecutionsByType(
+	request *ListWorkflowExecutionsByTypeRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListClosedWorkflowExecutionsByType", &request.ListWorkflowExecutionsRequest, true,
+		func(record *inMemoryVisibilityRecord) bool {
+			return record.workflowTypeName == request.WorkflowTypeName
+		})
+}
+
+func (v *inMemoryVisibilityPersistence) ListOpenWorkflowExecutionsByWorkflowID(
+	request *ListWorkflowExecutionsByWorkflowIDRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListOpenWorkflowExecutionsByWorkflowID", &request.ListWorkflowExecutionsRequest,
+		false, func(record *inMemoryVisibilityRecord) bool {
+			return record.workflowID == request.WorkflowID
+		})
+}
+
+func (v *inMemoryVisibilityPersistence) ListClosedWorkflowExecutionsByWorkflowID(
+	request *ListWorkflowExecutionsByWorkflowIDRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListClosedWorkflowExecutionsByWorkflowID",
+		&request.ListWorkflowExecutionsRequest, true, func(record *inMemoryVisibilityRecord) bool {
+			return record.workflowID == request.WorkflowID
+		})
+}
+
+func (v *inMemoryVisibilityPersistence) ListOpenWorkflowExecutionsBySearchAttributes(
+	request *ListWorkflowExecutionsBySearchAttributesRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListOpenWorkflowExecutionsBySearchAttributes",
+		&request.ListWorkflowExecutionsRequest, false, func(record *inMemoryVisibilityRecord) bool {
+			return hasSearchAttributes(record.searchAttributes, request.SearchAttributes)
+		})
+}
+
+func (v *inMemoryVisibilityPersistence) ListClosedWorkflowExecutionsBySearchAttributes(
+	request *ListWorkflowExecutionsBySearchAttributesRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListClosedWorkflowExecutionsBySearchAttributes",
+		&request.ListWorkflowExecutionsRequest, true, func(record *inMemoryVisibilityRecord) bool {
+			return hasSearchAttributes(record.searchAttributes, request.SearchAttributes)
+		})
+}
+
+func (v *inMemoryVisibilityPersistence) ListClosedWorkflowExecutionsByStatus(
+	request *ListClosedWorkflowExecutionsByStatusRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListClosedWorkflowExecutionsByStatus", &request.ListWorkflowExecutionsRequest,
+		true, func(record *inMemoryVisibilityRecord) bool {
+			return record.closeStatus == int32(request.Status)
+		})
+}
+
+func (v *inMemoryVisibilityPersistence) ListWorkflowExecutions(
+	request *ListWorkflowExecutionsByQueryRequest) (*ListWorkflowExecutionsResponse, error) {
+	token := &inMemoryVisibilityQueryPageToken{}
+	if len(request.NextPageToken) > 0 {
+		if err := json.Unmarshal(request.NextPageToken, token); err != nil {
+			return nil, &workflow.BadRequestError{
+				Message: fmt.Sprintf("ListWorkflowExecutions operation failed.  Invalid next page token. Error: %v",
+					err),
+			}
+		}
+	}
+
+	v.store.Lock()
+	defer v.store.Unlock()
+
+	records := v.queryWorkflowExecutions(request.DomainUUID, request.Query)
+	sort.Slice(records, func(i, j int) bool {
+		return request.Query.Less(records[i].toQueryRecord(), records[j].toQueryRecord())
+	})
+	if token.Offset >= len(records) {
+		records = nil
+	} else {
+		records = records[token.Offset:]
+	}
+	if request.PageSize > 0 && len(records) > request.PageSize {
+		records = records[:request.PageSize]
+	}
+
+	response := &ListWorkflowExecutionsResponse{}
+	for _, record := range records {
+		response.Executions = append(response.Executions, record.toWorkflowExecutionInfo())
+	}
+
+	if request.PageSize > 0 && len(records) == request.PageSize {
+		nextPageToken, err := json.Marshal(&inMemoryVisibilityQueryPageToken{Offset: token.Offset + len(records)})
+		if err != nil {
+			return nil, &workflow.InternalServiceError{
+				Message: fmt.Sprintf("ListWorkflowExecutions operation failed. Error: %v", err),
+			}
+		}
+		response.NextPageToken = nextPageToken
+	}
+
+	return response, nil
+}
+
+func (v *inMemoryVisibilityPersistence) CountWorkflowExecutions(
+	request *CountWorkflowExecutionsRequest) (*CountWorkflowExecutionsResponse, error) {
+	v.store.Lock()
+	defer v.store.Unlock()
+
+	records := v.queryWorkflowExecutions(request.DomainUUID, request.Query)
+	return &CountWorkflowExecutionsResponse{Count: int64(len(records))}, nil
+}
+
+// queryWorkflowExecutions returns the open and closed executions of the domain which match the query, the store
+// lock must be held
+func (v *inMemoryVisibilityPersistence) queryWorkflowExecutions(domainID string,
+	query *visibilityquery.Query) []*inMemoryVisibilityRecord {
+	var records []*inMemoryVisibilityRecord
+	for key, record := range v.store.visibility {
+		if key.domainID == domainID && query.Matches(record.toQueryRecord()) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+func (v *inMemoryVisibilityPersistence) GetClosedWorkflowExecution(
+	request *GetClosedWorkflowExecutionRequest) (*GetClosedWorkflowExecutionResponse, error) {
+	execution := request.Execution
+
+	v.store.Lock()
+	defer v.store.Unlock()
+
+	record, ok := v.store.visibility[inMemoryVisibilityKey{domainID: request.DomainUUID, runID: execution.GetRunId()}]
+	if !ok || !record.closed || record.workflowID != execution.GetWorkflowId() {
+		return nil, &workflow.EntityNotExistsError{
+			Message: fmt.Sprintf("Workflow execution not found.  WorkflowId: %v, RunId: %v",
+				execution.GetWorkflowId(), execution.GetRunId()),
+		}
+	}
+
+	return &GetClosedWorkflowExecutionResponse{
+		Execution: record.toWorkflowExecutionInfo(),
+	}, nil
+}
+
+// listWorkflowExecutions returns a page of executions ordered by start time, newest first.  filter is optional and
+// further restricts the executions to the ones it accepts.
+func (v *inMemoryVisibilityPersistence) listWorkflowExecutions(operation string,
+	request *ListWorkflowExecutionsRequest, closed bool,
+	filter func(record *inMemoryVisibilityRecord) bool) (*ListWorkflowExecutionsResponse, error) {
+	token := &inMemoryVisibilityPageToken{StartTime: request.LatestStartTime + 1}
+	if len(request.NextPageToken) > 0 {
+		if err := json.Unmarshal(request.NextPageToken, token); err != nil {
+			return nil, &workflow.BadRequestError{
+				Message: fmt.Sprintf("%v operation failed.  Invalid next page token. Error: %v", operation, err),
+			}
+		}
+	}
+
+	v.store.Lock()
+	defer v.store.Unlock()
+
+	var records []*inMemoryVisibilityRecord
+	for key, record := range v.store.visibility {
+		if key.domainID != request.DomainUUID || record.closed != closed ||
+			record.startTime < request.EarliestStartTime || record.startTime > request.LatestStartTime {
+			continue
+		}
+		if record.startTime > token.StartTime || (record.startTime == token.StartTime && record.runID <= token.RunID) {
+			continue
+		}
+		if filter != nil && !filter(record) {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].startTime != records[j].startTime {
+			return records[i].startTime > records[j].startTime
+		}
+		return records[i].runID < records[j].runID
+	})
+	if request.PageSize > 0 && len(records) > request.PageSize {
+		records = records[:request.PageSize]
+	}
+
+	response := &ListWorkflowExecutionsResponse{}
+	for _, record := range records {
+		response.Executions = append(response.Executions, record.toWorkflowExecutionInfo())
+	}
+
+	if request.PageSize > 0 && len(records) == request.PageSize {
+		lastRecord := records[len(records)-1]
+		nextPageToken, err := json.Marshal(&inMemoryVisibilityPageToken{
+			StartTime: lastRecord.startTime,
+			RunID:     lastRecord.runID,
+		})
+		if err != nil {
+			return nil, &workflow.InternalServiceError{
+				Message: fmt.Sprintf("%v operation failed. Error: %v", operation, err),
+			}
+		}
+		response.NextPageToken = nextPageToken
+	}
+
+	return response, nil
+}
+
+func (r *inMemoryVisibilityRecord) toWorkflowExecutionInfo() *workflow.WorkflowExecutionInfo {
+	info := &workflow.WorkflowExecutionInfo{
+		Execution: &workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr(r.workflowID),
+			RunId:      common.StringPtr(r.runID),
+		},
+		Type:             &workflow.WorkflowType{Name: common.StringPtr(r.workflowTypeName)},
+		StartTime:        common.Int64Ptr(r.startTime),
+		SearchAttributes: getSearchAttributes(copySearchAttributes(r.searchAttributes)),
+	}
+	if r.closed {
+		status := workflow.WorkflowExecutionCloseStatus(r.closeStatus)
+		info.CloseTime = common.Int64Ptr(r.closeTime)
+		info.CloseStatus = &status
+		info.HistoryLength = common.Int64Ptr(r.historyLength)
+	}
+	return info
+}
+
+func (r *inMemoryVisibilityRecord) toQueryRecord() *visibilityquery.Record {
+	return &visibilityquery.Record{
+		WorkflowID:    r.workflowID,
+		RunID:         r.runID,
+		WorkflowType:  r.workflowTypeName,
+		StartTime:     r.startTime,
+		Closed:        r.closed,
+		CloseTime:     r.closeTime,
+		CloseStatus:   int64(r.closeStatus),
+		HistoryLength: r.historyLength,
+	}
+}
+
+// hasSearchAttributes returns true if the record attributes contain all the given search attributes
+func hasSearchAttributes(recordAttributes, searchAttributes map[string][]byte) bool {
+	for name, value := range searchAttributes {
+		recordValue, ok := recordAttributes[name]
+		if !ok || !bytes.Equal(recordValue, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func copySearchAttributes(searchAttributes map[string][]byte) map[string][]byte {
+	if len(searchAttributes) == 0 {
+		return nil
+	}
+	result := make(map[string][]byte, len(searchAttributes))
+	for name, value := range searchAttributes {
+		result[name] = value
+	}
+	return result
+}
@@ -21,8 +21,13 @@
 package persistence
 
 import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
 	"math"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -45,6 +50,10 @@ const (
 	testPassword             = ""
 	testDatacenter           = ""
 	testSchemaDir            = "../.."
+	testSQLHost              = "127.0.0.1"
+	testMySQLUser            = "root"
+	testPostgresUser         = "postgres"
+	testSQLPassword          = ""
 )
 
 type (
@@ -67,6 +76,11 @@ type (
 		// when crtoss DC is public, remove EnableGlobalDomain
 		EnableGlobalDomain bool
 		IsMasterCluster    bool
+		// SQLDriverName selects the SQL persistence for the test, cassandra is used when it is empty
+		SQLDriverName string
+		SQLPort       int
+		// InMemory selects the in-memory persistence for the test, it takes precedence over SQLDriverName
+		InMemory bool
 	}
 
 	// TestBase wraps the base setup needed to create workflows over persistence layer.
@@ -83,7 +97,17 @@ type (
 		ClusterMetadata      cluster.Metadata
 		readLevel            int64
 		replicationReadLevel int64
+		// SQLDriverName is set by the suites running against the SQL persistence
+		SQLDriverName string
+		// InMemory is set by the suites running against the in-memory persistence
+		InMemory bool
+		// BoltVisibility is set by the suites running against the BoltDB visibility persistence
+		BoltVisibility bool
+		// boltVisibilityDir holds the BoltDB visibility store file of the test
+		boltVisibilityDir   string
+		boltVisibilityStore *BoltVisibilityStore
 		CassandraTestCluster
+		SQLTestCluster
 	}
 
 	// CassandraTestCluster allows executing cassandra operations in testing.
@@ -94,6 +118,15 @@ type (
 		session  *gocql.Session
 	}
 
+	// SQLTestCluster allows executing SQL operations in testing.
+	SQLTestCluster struct {
+		driverName   string
+		port         int
+		user         string
+		databaseName string
+		db           *sql.DB
+	}
+
 	testExecutionMgrFactory struct {
 		options   TestBaseOptions
 		cassandra CassandraTestCluster
@@ -118,6 +151,18 @@ func (s *TestBase) SetupWorkflowStoreWithOptions(options TestBaseOptions) {
 		options.IsMasterCluster,
 	)
 
+	if options.InMemory {
+		s.setupInMemoryWorkflowStore(log)
+		s.createTestShard(log)
+		return
+	}
+
+	if options.SQLDriverName != "" {
+		s.setupSQLWorkflowStore(options, log)
+		s.createTestShard(log)
+		return
+	}
+
 	// Setup Workflow keyspace and deploy schema for tests
 	s.CassandraTestCluster.setupTestCluster(options)
 	shardID := 0
@@ -162,6 +207,89 @@ func (s *TestBase) SetupWorkflowStoreWithOptions(options TestBaseOptions) {
 		log.Fatal(err)
 	}
 
+	s.createTestShard(log)
+}
+
+func (s *TestBase) setupSQLWorkflowStore(options TestBaseOptions, log bark.Logger) {
+	// Setup a database with both the cadence and visibility schema for tests
+	s.SQLTestCluster.setupTestCluster(options)
+	driverName := s.SQLTestCluster.driverName
+	port := s.SQLTestCluster.port
+	user := s.SQLTestCluster.user
+	databaseName := s.SQLTestCluster.databaseName
+	var err error
+	s.ShardMgr, err = NewSQLShardPersistence(driverName, testSQLHost, port, user, testSQLPassword, databaseName, 0,
+		s.ClusterMetadata.GetCurrentClusterName(), log)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.ExecutionMgrFactory, err = NewSQLPersistenceClientFactory(driverName, testSQLHost, port, user, testSQLPassword,
+		databaseName, 0, log, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Create an ExecutionManager for the shard for use in unit tests
+	s.WorkflowMgr, err = s.ExecutionMgrFactory.CreateExecutionManager(0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.TaskMgr, err = NewSQLTaskPersistence(driverName, testSQLHost, port, user, testSQLPassword, databaseName, 0, log)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.HistoryMgr, err = NewSQLHistoryPersistence(driverName, testSQLHost, port, user, testSQLPassword, databaseName, 0,
+		log)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.MetadataManager, err = NewSQLMetadataPersistence(driverName, testSQLHost, port, user, testSQLPassword,
+		databaseName, 0, s.ClusterMetadata.GetCurrentClusterName(), log)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.VisibilityMgr, err = NewSQLVisibilityPersistence(driverName, testSQLHost, port, user, testSQLPassword,
+		databaseName, 0, log)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (s *TestBase) setupInMemoryWorkflowStore(log bark.Logger) {
+	store := NewInMemoryStore()
+	var err error
+	s.ShardMgr, err = NewInMemoryShardPersistence(store, s.ClusterMetadata.GetCurrentClusterName(), log)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.ExecutionMgrFactory, err = NewInMemoryPersistenceClientFactory(store, log, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Create an ExecutionManager for the shard for use in unit tests
+	s.WorkflowMgr, err = s.ExecutionMgrFactory.CreateExecutionManager(0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.TaskMgr, err = NewInMemoryTaskPersistence(store, log)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.HistoryMgr, err = NewInMemoryHistoryPersistence(store, log)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.MetadataManager, err = NewInMemoryMetadataPersistence(store, s.ClusterMetadata.GetCurrentClusterName(), log)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.VisibilityMgr, err = NewInMemoryVisibilityPersistence(store, log)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (s *TestBase) createTestShard(log bark.Logger) {
+	shardID := 0
 	s.TaskIDGenerator = &testTransferTaskIDGenerator{}
 
 	// Create a shard for test
@@ -908,11 +1036,46 @@ func (s *TestBase) SetupWorkflowStore() {
 		ClusterPassword:    testPassword,
 		DropKeySpace:       true,
 		EnableGlobalDomain: false,
+		SQLDriverName:      s.SQLDriverName,
+		InMemory:           s.InMemory,
 	})
+	if s.BoltVisibility {
+		s.setupBoltVisibilityStore()
+	}
+}
+
+// setupBoltVisibilityStore replaces the visibility persistence with the BoltDB one, on a file in a temporary directory
+func (s *TestBase) setupBoltVisibilityStore() {
+	var err error
+	s.boltVisibilityDir, err = ioutil.TempDir("", "cadence-visibility")
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.boltVisibilityStore, err = NewBoltVisibilityStore(filepath.Join(s.boltVisibilityDir, "visibility.db"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.VisibilityMgr.Close()
+	s.VisibilityMgr, err = NewBoltVisibilityPersistence(s.boltVisibilityStore, bark.NewLoggerFromLogrus(log.New()))
+	if err != nil {
+		log.Fatal(err)
+	}
 }
 
 // TearDownWorkflowStore to cleanup
 func (s *TestBase) TearDownWorkflowStore() {
+	if s.BoltVisibility {
+		s.boltVisibilityStore.Close()
+		os.RemoveAll(s.boltVisibilityDir)
+	}
+	if s.InMemory {
+		// nothing to clean up, the store is dropped with the managers
+		return
+	}
+	if s.SQLDriverName != "" {
+		s.SQLTestCluster.tearDownTestCluster()
+		return
+	}
 	s.CassandraTestCluster.tearDownTestCluster()
 }
 
@@ -1052,6 +1215,77 @@ func (s *CassandraTestCluster) loadVisibilitySchema(fileNames []string, schemaDi
 	}
 }
 
+func (s *SQLTestCluster) setupTestCluster(options TestBaseOptions) {
+	s.driverName = options.SQLDriverName
+	s.port = options.SQLPort
+	s.user = testMySQLUser
+	adminDatabaseName := ""
+	if s.driverName == SQLDriverPostgres {
+		s.user = testPostgresUser
+		adminDatabaseName = "postgres"
+	}
+	s.databaseName = options.KeySpace
+	if s.databaseName == "" {
+		s.databaseName = generateRandomKeyspace(10)
+	}
+
+	dataSourceName, err := buildSQLDataSourceName(s.driverName, testSQLHost, s.port, s.user, testSQLPassword,
+		adminDatabaseName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.db, err = sql.Open(s.driverName, dataSourceName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := s.db.Exec(fmt.Sprintf("CREATE DATABASE %v", s.databaseName)); err != nil {
+		log.Fatal(err)
+	}
+
+	schemaDir := options.SchemaDir
+	if schemaDir == "" {
+		schemaDir = "."
+	}
+	schemaDir = fmt.Sprintf("%v/schema/%v", schemaDir, s.driverName)
+	s.loadSchema(schemaDir + "/cadence/schema.sql")
+	s.loadSchema(schemaDir + "/visibility/schema.sql")
+}
+
+func (s *SQLTestCluster) tearDownTestCluster() {
+	if _, err := s.db.Exec(fmt.Sprintf("DROP DATABASE %v", s.databaseName)); err != nil {
+		log.Fatal(err)
+	}
+	s.db.Close()
+}
+
+// loadSchema runs the statements of the schema file one by one, since not every driver accepts multiple statements
+func (s *SQLTestCluster) loadSchema(fileName string) {
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dataSourceName, err := buildSQLDataSourceName(s.driverName, testSQLHost, s.port, s.user, testSQLPassword,
+		s.databaseName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	db, err := sql.Open(s.driverName, dataSourceName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, stmt := range strings.Split(string(content), ";") {
+		if strings.TrimSpace(stmt) == "" {
+			continue
+		}
+		if _, err := db.Exec(stmt); err != nil {
+			log.Fatalf("Failed to load schema %v: %v", fileName, err)
+		}
+	}
+}
+
 func validateTimeRange(t time.Time, expectedDuration time.Duration) bool {
 	currentTime := time.Now()
 	diff := time.Duration(currentTime.UnixNano() - t.UnixNano())
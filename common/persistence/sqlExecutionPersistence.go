@@ -0,0 +1,1077 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pborman/uuid"
+	"github.com/uber-common/bark"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+)
+
+const (
+	sqlCreateCurrentExecutionQuery = `INSERT INTO current_executions (` +
+		`shard_id, domain_id, workflow_id, run_id, create_request_id, state, close_status) ` +
+		`VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	sqlGetCurrentExecutionQuery = `SELECT run_id, create_request_id, state, close_status ` +
+		`FROM current_executions ` +
+		`WHERE shard_id = ? AND domain_id = ? AND workflow_id = ?`
+
+	sqlLockCurrentExecutionQuery = sqlGetCurrentExecutionQuery + ` FOR UPDATE`
+
+	sqlUpdateCurrentExecutionQuery = `UPDATE current_executions ` +
+		`SET run_id = ?, create_request_id = ?, state = ?, close_status = ? ` +
+		`WHERE shard_id = ? AND domain_id = ? AND workflow_id = ?`
+
+	sqlCreateExecutionQuery = `INSERT INTO executions (` +
+		`shard_id, domain_id, workflow_id, run_id, next_event_id, data, replication_data, data_encoding) ` +
+		`VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	sqlGetExecutionQuery = `SELECT data, replication_data, data_encoding ` +
+		`FROM executions ` +
+		`WHERE shard_id = ? AND domain_id = ? AND workflow_id = ? AND run_id = ?`
+
+	sqlLockExecutionQuery = `SELECT next_event_id ` +
+		`FROM executions ` +
+		`WHERE shard_id = ? AND domain_id = ? AND workflow_id = ? AND run_id = ? FOR UPDATE`
+
+	sqlUpdateExecutionQuery = `UPDATE executions ` +
+		`SET next_event_id = ?, data = ?, data_encoding = ? ` +
+		`WHERE shard_id = ? AND domain_id = ? AND workflow_id = ? AND run_id = ?`
+
+	sqlUpdateExecutionWithReplicationQuery = `UPDATE executions ` +
+		`SET next_event_id = ?, data = ?, replication_data = ?, data_encoding = ? ` +
+		`WHERE shard_id = ? AND domain_id = ? AND workflow_id = ? AND run_id = ?`
+
+	// Queries on the tables holding the mutable state of an execution, the table is filled in using fmt.Sprintf
+	sqlDeleteExecutionRowsQuery = `DELETE FROM %v ` +
+		`WHERE shard_id = ? AND domain_id = ? AND workflow_id = ? AND run_id = ?`
+
+	sqlGetMapRowsQuery = `SELECT data, data_encoding ` +
+		`FROM %v ` +
+		`WHERE shard_id = ? AND domain_id = ? AND workflow_id = ? AND run_id = ?`
+
+	sqlInsertMapRowQuery = `INSERT INTO %v (` +
+		`shard_id, domain_id, workflow_id, run_id, %v, data, data_encoding) ` +
+		`VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	sqlDeleteMapRowQuery = `DELETE FROM %v ` +
+		`WHERE shard_id = ? AND domain_id = ? AND workflow_id = ? AND run_id = ? AND %v = ?`
+
+	sqlInsertSignalRequestedQuery = `INSERT INTO signals_requested_sets (` +
+		`shard_id, domain_id, workflow_id, run_id, signal_id) ` +
+		`VALUES (?, ?, ?, ?, ?)`
+
+	sqlDeleteSignalRequestedQuery = `DELETE FROM signals_requested_sets ` +
+		`WHERE shard_id = ? AND domain_id = ? AND workflow_id = ? AND run_id = ? AND signal_id = ?`
+
+	sqlGetSignalsRequestedQuery = `SELECT signal_id ` +
+		`FROM signals_requested_sets ` +
+		`WHERE shard_id = ? AND domain_id = ? AND workflow_id = ? AND run_id = ?`
+
+	sqlInsertBufferedEventsQuery = `INSERT INTO buffered_events (` +
+		`shard_id, domain_id, workflow_id, run_id, data, data_encoding, data_version) ` +
+		`VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	sqlGetBufferedEventsQuery = `SELECT data, data_encoding, data_version ` +
+		`FROM buffered_events ` +
+		`WHERE shard_id = ? AND domain_id = ? AND workflow_id = ? AND run_id = ? ` +
+		`ORDER BY id`
+
+	sqlListExecutionsQuery = `SELECT data, data_encoding ` +
+		`FROM executions ` +
+		`WHERE shard_id = ? AND (domain_id, workflow_id, run_id) > (?, ?, ?) ` +
+		`ORDER BY domain_id, workflow_id, run_id LIMIT ?`
+
+	sqlCreateTransferTaskQuery = `INSERT INTO transfer_tasks (shard_id, task_id, data, data_encoding) ` +
+		`VALUES (?, ?, ?, ?)`
+
+	sqlGetTransferTasksQuery = `SELECT data, data_encoding ` +
+		`FROM transfer_tasks ` +
+		`WHERE shard_id = ? AND task_id > ? AND task_id <= ? ` +
+		`ORDER BY task_id LIMIT ?`
+
+	sqlCompleteTransferTaskQuery = `DELETE FROM transfer_tasks WHERE shard_id = ? AND task_id = ?`
+
+	sqlCreateReplicationTaskQuery = `INSERT INTO replication_tasks (shard_id, task_id, data, data_encoding) ` +
+		`VALUES (?, ?, ?, ?)`
+
+	sqlGetReplicationTasksQuery = `SELECT data, data_encoding ` +
+		`FROM replication_tasks ` +
+		`WHERE shard_id = ? AND task_id > ? AND task_id <= ? ` +
+		`ORDER BY task_id LIMIT ?`
+
+	sqlCompleteReplicationTaskQuery = `DELETE FROM replication_tasks WHERE shard_id = ? AND task_id = ?`
+
+	sqlCreateTimerTaskQuery = `INSERT INTO timer_tasks (shard_id, visibility_timestamp, task_id, data, data_encoding) ` +
+		`VALUES (?, ?, ?, ?, ?)`
+
+	sqlGetTimerTasksQuery = `SELECT data, data_encoding ` +
+		`FROM timer_tasks ` +
+		`WHERE shard_id = ? AND visibility_timestamp >= ? AND visibility_timestamp < ? ` +
+		`ORDER BY visibility_timestamp, task_id LIMIT ?`
+
+	sqlCompleteTimerTaskQuery = `DELETE FROM timer_tasks WHERE shard_id = ? AND visibility_timestamp = ? AND task_id = ?`
+)
+
+// Tables holding the mutable state of a workflow execution, along with the column keying each row
+const (
+	sqlActivityInfoMapsTable       = "activity_info_maps"
+	sqlTimerInfoMapsTable          = "timer_info_maps"
+	sqlChildExecutionInfoMapsTable = "child_execution_info_maps"
+	sqlRequestCancelInfoMapsTable  = "request_cancel_info_maps"
+	sqlSignalInfoMapsTable         = "signal_info_maps"
+	sqlSignalsRequestedSetsTable   = "signals_requested_sets"
+	sqlBufferedEventsTable         = "buffered_events"
+
+	sqlScheduleIDColumn   = "schedule_id"
+	sqlTimerIDColumn      = "timer_id"
+	sqlInitiatedIDColumn  = "initiated_id"
+	sqlExecutionsTable    = "executions"
+	sqlTimerPageTokenSize = 8
+)
+
+type (
+	sqlExecutionPersistence struct {
+		*sqlDB
+		shardID int
+		logger  bark.Logger
+	}
+
+	// sqlListExecutionsPageToken is the key of the last execution returned in a page
+	sqlListExecutionsPageToken struct {
+		DomainID   string
+		WorkflowID string
+		RunID      string
+	}
+)
+
+var sqlExecutionTables = []string{
+	sqlExecutionsTable,
+	sqlActivityInfoMapsTable,
+	sqlTimerInfoMapsTable,
+	sqlChildExecutionInfoMapsTable,
+	sqlRequestCancelInfoMapsTable,
+	sqlSignalInfoMapsTable,
+	sqlSignalsRequestedSetsTable,
+	sqlBufferedEventsTable,
+}
+
+// newSQLWorkflowExecutionPersistence is used to create an instance of ExecutionManager implementation for a shard
+func newSQLWorkflowExecutionPersistence(shardID int, db *sqlDB, logger bark.Logger) ExecutionManager {
+	return &sqlExecutionPersistence{sqlDB: db, shardID: shardID, logger: logger}
+}
+
+// Close is a no-op, the connection pool is shared by all shards and owned by the sqlPersistenceClientFactory
+func (d *sqlExecutionPersistence) Close() {
+}
+
+func (d *sqlExecutionPersistence) CreateWorkflowExecution(request *CreateWorkflowExecutionRequest) (
+	*CreateWorkflowExecutionResponse, error) {
+	domainID := request.DomainID
+	workflowID := request.Execution.GetWorkflowId()
+	runID := request.Execution.GetRunId()
+
+	err := d.txExecute("CreateWorkflowExecution", func(tx *sql.Tx) error {
+		if err := d.assertShardOwnership(tx, request.RangeID, "create workflow execution"); err != nil {
+			return err
+		}
+
+		if err := d.createWorkflowExecutionWithinTx(tx, request, time.Now()); err != nil {
+			return err
+		}
+
+		return d.createTasks(tx, request.TransferTasks, request.ReplicationTasks, request.TimerTasks, nil,
+			domainID, workflowID, runID)
+	})
+	if err != nil {
+		if alreadyStarted, ok := err.(*WorkflowExecutionAlreadyStartedError); ok && alreadyStarted.RunID == "" {
+			return nil, d.getConcurrentlyStartedError(request)
+		}
+		return nil, err
+	}
+
+	return &CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil
+}
+
+// getConcurrentlyStartedError returns the WorkflowExecutionAlreadyStartedError for the current execution which a
+// concurrent create inserted.  It is read outside of the create transaction, which the unique key violation aborts.
+func (d *sqlExecutionPersistence) getConcurrentlyStartedError(request *CreateWorkflowExecutionRequest) error {
+	workflowID := request.Execution.GetWorkflowId()
+	var currentRunID, startRequestID string
+	var currentState, currentCloseStatus int
+	err := d.queryRow(d.db, sqlGetCurrentExecutionQuery, d.shardID, request.DomainID, workflowID).Scan(
+		&currentRunID, &startRequestID, &currentState, &currentCloseStatus)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// the concurrent create did not commit either
+			return &ConditionFailedError{
+				Msg: fmt.Sprintf("Failed to create workflow execution.  WorkflowId: %v, concurrent create failed",
+					workflowID),
+			}
+		}
+		return convertSQLError("CreateWorkflowExecution", err)
+	}
+
+	return &WorkflowExecutionAlreadyStartedError{
+		Msg: fmt.Sprintf("Workflow execution already running. WorkflowId: %v, RunId: %v, rangeID: %v",
+			workflowID, currentRunID, request.RangeID),
+		StartRequestID: startRequestID,
+		RunID:          currentRunID,
+		State:          currentState,
+		CloseStatus:    currentCloseStatus,
+	}
+}
+
+func (d *sqlExecutionPersistence) createWorkflowExecutionWithinTx(tx *sql.Tx, request *CreateWorkflowExecutionRequest,
+	now time.Time) error {
+	domainID := request.DomainID
+	workflowID := request.Execution.GetWorkflowId()
+	runID := request.Execution.GetRunId()
+
+	info := &WorkflowExecutionInfo{
+		DomainID:             domainID,
+		WorkflowID:           workflowID,
+		RunID:                runID,
+		InitiatedID:          emptyInitiatedID,
+		TaskList:             request.TaskList,
+		WorkflowTypeName:     request.WorkflowTypeName,
+		WorkflowTimeout:      request.WorkflowTimeout,
+		DecisionTimeoutValue: request.DecisionTimeoutValue,
+		ExecutionContext:     request.ExecutionContext,
+		State:                WorkflowStateCreated,
+		CloseStatus:          WorkflowCloseStatusNone,
+		LastFirstEventID:     common.FirstEventID,
+		NextEventID:          request.NextEventID,
+		LastProcessedEvent:   request.LastProcessedEvent,
+		StartTimestamp:       now,
+		LastUpdatedTimestamp: now,
+		CreateRequestID:      request.RequestID,
+		DecisionScheduleID:   request.DecisionScheduleID,
+		DecisionStartedID:    request.DecisionStartedID,
+		DecisionTimeout:      request.DecisionStartToCloseTimeout,
+		Attempt:              request.Attempt,
+		HasRetryPolicy:       request.HasRetryPolicy,
+		InitialInterval:      request.InitialInterval,
+		BackoffCoefficient:   request.BackoffCoefficient,
+		MaximumInterval:      request.MaximumInterval,
+		ExpirationTime:       request.ExpirationTime,
+		MaximumAttempts:      request.MaximumAttempts,
+		NonRetriableErrors:   request.NonRetriableErrors,
+		CronSchedule:         request.CronSchedule,
+		SearchAttributes:     request.SearchAttributes,
+		Priority:             request.Priority,
+		BuildID:              request.BuildID,
+	}
+
+	state := WorkflowStateRunning
+	if request.ParentExecution != nil {
+		info.ParentDomainID = request.ParentDomainID
+		info.ParentWorkflowID = request.ParentExecution.GetWorkflowId()
+		info.ParentRunID = request.ParentExecution.GetRunId()
+		info.InitiatedID = request.InitiatedID
+		state = WorkflowStateCreated
+	}
+
+	var currentRunID, startRequestID string
+	var currentState, currentCloseStatus int
+	err := d.queryRow(tx, sqlLockCurrentExecutionQuery, d.shardID, domainID, workflowID).Scan(
+		&currentRunID, &startRequestID, &currentState, &currentCloseStatus)
+	switch {
+	case err == sql.ErrNoRows:
+		if request.ContinueAsNew {
+			return &ConditionFailedError{
+				Msg: fmt.Sprintf("Failed to continue workflow execution as new.  WorkflowId: %v, PreviousRunId: %v, "+
+					"current execution not found", workflowID, request.PreviousRunID),
+			}
+		}
+		_, err = d.exec(tx, sqlCreateCurrentExecutionQuery, d.shardID, domainID, workflowID, runID, request.RequestID,
+			state, WorkflowCloseStatusNone)
+		if isSQLDuplicateKeyError(err) {
+			// the current execution of the concurrent create is filled in once this transaction is rolled back
+			return &WorkflowExecutionAlreadyStartedError{
+				Msg: fmt.Sprintf("Workflow execution already running. WorkflowId: %v", workflowID),
+			}
+		}
+	case err != nil:
+		return err
+	case !request.ContinueAsNew || currentRunID != request.PreviousRunID:
+		return &WorkflowExecutionAlreadyStartedError{
+			Msg: fmt.Sprintf("Workflow execution already running. WorkflowId: %v, RunId: %v, rangeID: %v",
+				workflowID, currentRunID, request.RangeID),
+			StartRequestID: startRequestID,
+			RunID:          currentRunID,
+			State:          currentState,
+			CloseStatus:    currentCloseStatus,
+		}
+	default:
+		_, err = d.exec(tx, sqlUpdateCurrentExecutionQuery, runID, request.RequestID, state, WorkflowCloseStatusNone,
+			d.shardID, domainID, workflowID)
+	}
+	if err != nil {
+		return err
+	}
+
+	data, err := sqlEncode(info)
+	if err != nil {
+		return err
+	}
+	var replicationData []byte
+	if request.ReplicationState != nil {
+		if replicationData, err = sqlEncode(request.ReplicationState); err != nil {
+			return err
+		}
+	}
+
+	if _, err = d.exec(tx, sqlCreateExecutionQuery, d.shardID, domainID, workflowID, runID, request.NextEventID, data,
+		replicationData, sqlDataEncoding); err != nil {
+		return err
+	}
+
+	for _, a := range request.ActivityInfos {
+		if err := d.upsertMapRow(tx, sqlActivityInfoMapsTable, sqlScheduleIDColumn, a.ScheduleID, a,
+			domainID, workflowID, runID); err != nil {
+			return err
+		}
+	}
+	for _, t := range request.TimerInfos {
+		if err := d.upsertMapRow(tx, sqlTimerInfoMapsTable, sqlTimerIDColumn, t.TimerID, t,
+			domainID, workflowID, runID); err != nil {
+			return err
+		}
+	}
+	for _, signalID := range request.SignalRequestedIDs {
+		if _, err := d.exec(tx, sqlInsertSignalRequestedQuery, d.shardID, domainID, workflowID, runID,
+			signalID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *sqlExecutionPersistence) GetWorkflowExecution(request *GetWorkflowExecutionRequest) (
+	*GetWorkflowExecutionResponse, error) {
+	domainID := request.DomainID
+	workflowID := request.Execution.GetWorkflowId()
+	runID := request.Execution.GetRunId()
+
+	var data, replicationData []byte
+	var encoding string
+	err := d.queryRow(d.db, sqlGetExecutionQuery, d.shardID, domainID, workflowID, runID).Scan(
+		&data, &replicationData, &encoding)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &workflow.EntityNotExistsError{
+				Message: fmt.Sprintf("Workflow execution not found.  WorkflowId: %v, RunId: %v",
+					workflowID, runID),
+			}
+		}
+		return nil, convertSQLError("GetWorkflowExecution", err)
+	}
+
+	state := &WorkflowMutableState{
+		ActivitInfos:        make(map[int64]*ActivityInfo),
+		TimerInfos:          make(map[string]*TimerInfo),
+		ChildExecutionInfos: make(map[int64]*ChildExecutionInfo),
+		RequestCancelInfos:  make(map[int64]*RequestCancelInfo),
+		SignalInfos:         make(map[int64]*SignalInfo),
+		SignalRequestedIDs:  make(map[string]struct{}),
+		ExecutionInfo:       &WorkflowExecutionInfo{},
+	}
+	if err := sqlDecode(data, encoding, state.ExecutionInfo); err != nil {
+		return nil, convertSQLError("GetWorkflowExecution", err)
+	}
+	if replicationData != nil {
+		state.ReplicationState = &ReplicationState{}
+		if err := sqlDecode(replicationData, encoding, state.ReplicationState); err != nil {
+			return nil, convertSQLError("GetWorkflowExecution", err)
+		}
+	}
+
+	if err := d.getMutableStateMaps(state, domainID, workflowID, runID); err != nil {
+		return nil, convertSQLError("GetWorkflowExecution", err)
+	}
+
+	return &GetWorkflowExecutionResponse{State: state}, nil
+}
+
+func (d *sqlExecutionPersistence) getMutableStateMaps(state *WorkflowMutableState, domainID, workflowID,
+	runID string) error {
+	err := d.getMapRows(sqlActivityInfoMapsTable, domainID, workflowID, runID, func(data []byte, encoding string) error {
+		info := &ActivityInfo{}
+		if err := sqlDecode(data, encoding, info); err != nil {
+			return err
+		}
+		state.ActivitInfos[info.ScheduleID] = info
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = d.getMapRows(sqlTimerInfoMapsTable, domainID, workflowID, runID, func(data []byte, encoding string) error {
+		info := &TimerInfo{}
+		if err := sqlDecode(data, encoding, info); err != nil {
+			return err
+		}
+		state.TimerInfos[info.TimerID] = info
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = d.getMapRows(sqlChildExecutionInfoMapsTable, domainID, workflowID, runID,
+		func(data []byte, encoding string) error {
+			info := &ChildExecutionInfo{}
+			if err := sqlDecode(data, encoding, info); err != nil {
+				return err
+			}
+			state.ChildExecutionInfos[info.InitiatedID] = info
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	err = d.getMapRows(sqlRequestCancelInfoMapsTable, domainID, workflowID, runID,
+		func(data []byte, encoding string) error {
+			info := &RequestCancelInfo{}
+			if err := sqlDecode(data, encoding, info); err != nil {
+				return err
+			}
+			state.RequestCancelInfos[info.InitiatedID] = info
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	err = d.getMapRows(sqlSignalInfoMapsTable, domainID, workflowID, runID, func(data []byte, encoding string) error {
+		info := &SignalInfo{}
+		if err := sqlDecode(data, encoding, info); err != nil {
+			return err
+		}
+		state.SignalInfos[info.InitiatedID] = info
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	rows, err := d.query(d.db, sqlGetSignalsRequestedQuery, d.shardID, domainID, workflowID, runID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var signalID string
+		if err := rows.Scan(&signalID); err != nil {
+			return err
+		}
+		state.SignalRequestedIDs[signalID] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = d.query(d.db, sqlGetBufferedEventsQuery, d.shardID, domainID, workflowID, runID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		eventBatch := &SerializedHistoryEventBatch{}
+		if err := rows.Scan(&eventBatch.Data, &eventBatch.EncodingType, &eventBatch.Version); err != nil {
+			return err
+		}
+		state.BufferedEvents = append(state.BufferedEvents, eventBatch)
+	}
+	return rows.Err()
+}
+
+func (d *sqlExecutionPersistence) getMapRows(table, domainID, workflowID, runID string,
+	fn func(data []byte, encoding string) error) error {
+	rows, err := d.query(d.db, fmt.Sprintf(sqlGetMapRowsQuery, table), d.shardID, domainID, workflowID, runID)
+	if err != nil {
+		return err
+	}
+	return decodeSQLRows(rows, fn)
+}
+
+func (d *sqlExecutionPersistence) UpdateWorkflowExecution(request *UpdateWorkflowExecutionRequest) error {
+	now := time.Now()
+	executionInfo := *request.ExecutionInfo
+	executionInfo.LastUpdatedTimestamp = now
+	domainID := executionInfo.DomainID
+	workflowID := executionInfo.WorkflowID
+	runID := executionInfo.RunID
+
+	return d.txExecute("UpdateWorkflowExecution", func(tx *sql.Tx) error {
+		if err := d.assertShardOwnership(tx, request.RangeID, "update workflow execution"); err != nil {
+			return err
+		}
+
+		var nextEventID int64
+		err := d.queryRow(tx, sqlLockExecutionQuery, d.shardID, domainID, workflowID, runID).Scan(&nextEventID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return &ConditionFailedError{
+					Msg: fmt.Sprintf("Failed to update workflow execution.  WorkflowId: %v, RunId: %v not found",
+						workflowID, runID),
+				}
+			}
+			return err
+		}
+		if nextEventID != request.Condition {
+			return &ConditionFailedError{
+				Msg: fmt.Sprintf("Failed to update workflow execution.  Request Condition: %v, Actual Value: %v",
+					request.Condition, nextEventID),
+			}
+		}
+
+		data, err := sqlEncode(&executionInfo)
+		if err != nil {
+			return err
+		}
+		if request.ReplicationState == nil {
+			// Updates will be called with null ReplicationState while the feature is disabled
+			_, err = d.exec(tx, sqlUpdateExecutionQuery, executionInfo.NextEventID, data, sqlDataEncoding,
+				d.shardID, domainID, workflowID, runID)
+		} else {
+			var replicationData []byte
+			if replicationData, err = sqlEncode(request.ReplicationState); err != nil {
+				return err
+			}
+			_, err = d.exec(tx, sqlUpdateExecutionWithReplicationQuery, executionInfo.NextEventID, data,
+				replicationData, sqlDataEncoding, d.shardID, domainID, workflowID, runID)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := d.createTasks(tx, request.TransferTasks, request.ReplicationTasks, request.TimerTasks,
+			request.DeleteTimerTask, domainID, workflowID, runID); err != nil {
+			return err
+		}
+
+		if err := d.updateMutableStateMaps(tx, request, domainID, workflowID, runID); err != nil {
+			return err
+		}
+
+		if request.ContinueAsNew != nil {
+			startReq := request.ContinueAsNew
+			if err := d.createWorkflowExecutionWithinTx(tx, startReq, now); err != nil {
+				return err
+			}
+			return d.createTasks(tx, startReq.TransferTasks, nil, startReq.TimerTasks, nil, startReq.DomainID,
+				startReq.Execution.GetWorkflowId(), startReq.Execution.GetRunId())
+		} else if request.FinishExecution {
+			// There is no TTL in SQL, the current execution row is kept and only records that the run is closed
+			_, err = d.exec(tx, sqlUpdateCurrentExecutionQuery, runID, executionInfo.CreateRequestID,
+				executionInfo.State, executionInfo.CloseStatus, d.shardID, domainID, workflowID)
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (d *sqlExecutionPersistence) updateMutableStateMaps(tx *sql.Tx, request *UpdateWorkflowExecutionRequest,
+	domainID, workflowID, runID string) error {
+	for _, a := range request.UpsertActivityInfos {
+		if err := d.upsertMapRow(tx, sqlActivityInfoMapsTable, sqlScheduleIDColumn, a.ScheduleID, a,
+			domainID, workflowID, runID); err != nil {
+			return err
+		}
+	}
+	if request.DeleteActivityInfo != nil {
+		if err := d.deleteMapRow(tx, sqlActivityInfoMapsTable, sqlScheduleIDColumn, *request.DeleteActivityInfo,
+			domainID, workflowID, runID); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range request.UpserTimerInfos {
+		if err := d.upsertMapRow(tx, sqlTimerInfoMapsTable, sqlTimerIDColumn, t.TimerID, t,
+			domainID, workflowID, runID); err != nil {
+			return err
+		}
+	}
+	for _, timerID := range request.DeleteTimerInfos {
+		if err := d.deleteMapRow(tx, sqlTimerInfoMapsTable, sqlTimerIDColumn, timerID,
+			domainID, workflowID, runID); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range request.UpsertChildExecutionInfos {
+		if err := d.upsertMapRow(tx, sqlChildExecutionInfoMapsTable, sqlInitiatedIDColumn, c.InitiatedID, c,
+			domainID, workflowID, runID); err != nil {
+			return err
+		}
+	}
+	if request.DeleteChildExecutionInfo != nil {
+		if err := d.deleteMapRow(tx, sqlChildExecutionInfoMapsTable, sqlInitiatedIDColumn,
+			*request.DeleteChildExecutionInfo, domainID, workflowID, runID); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range request.UpsertRequestCancelInfos {
+		if err := d.upsertMapRow(tx, sqlRequestCancelInfoMapsTable, sqlInitiatedIDColumn, r.InitiatedID, r,
+			domainID, workflowID, runID); err != nil {
+			return err
+		}
+	}
+	if request.DeleteRequestCancelInfo != nil {
+		if err := d.deleteMapRow(tx, sqlRequestCancelInfoMapsTable, sqlInitiatedIDColumn,
+			*request.DeleteRequestCancelInfo, domainID, workflowID, runID); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range request.UpsertSignalInfos {
+		if err := d.upsertMapRow(tx, sqlSignalInfoMapsTable, sqlInitiatedIDColumn, s.InitiatedID, s,
+			domainID, workflowID, runID); err != nil {
+			return err
+		}
+	}
+	if request.DeleteSignalInfo != nil {
+		if err := d.deleteMapRow(tx, sqlSignalInfoMapsTable, sqlInitiatedIDColumn, *request.DeleteSignalInfo,
+			domainID, workflowID, runID); err != nil {
+			return err
+		}
+	}
+
+	for _, signalID := range request.UpsertSignalRequestedIDs {
+		if _, err := d.exec(tx, sqlDeleteSignalRequestedQuery, d.shardID, domainID, workflowID, runID,
+			signalID); err != nil {
+			return err
+		}
+		if _, err := d.exec(tx, sqlInsertSignalRequestedQuery, d.shardID, domainID, workflowID, runID,
+			signalID); err != nil {
+			return err
+		}
+	}
+	if request.DeleteSignalRequestedID != "" {
+		if _, err := d.exec(tx, sqlDeleteSignalRequestedQuery, d.shardID, domainID, workflowID, runID,
+			request.DeleteSignalRequestedID); err != nil {
+			return err
+		}
+	}
+
+	if request.ClearBufferedEvents {
+		_, err := d.exec(tx, fmt.Sprintf(sqlDeleteExecutionRowsQuery, sqlBufferedEventsTable), d.shardID, domainID,
+			workflowID, runID)
+		return err
+	} else if request.NewBufferedEvents != nil {
+		_, err := d.exec(tx, sqlInsertBufferedEventsQuery, d.shardID, domainID, workflowID, runID,
+			request.NewBufferedEvents.Data, request.NewBufferedEvents.EncodingType, request.NewBufferedEvents.Version)
+		return err
+	}
+
+	return nil
+}
+
+func (d *sqlExecutionPersistence) upsertMapRow(tx *sql.Tx, table, keyColumn string, key interface{},
+	value interface{}, domainID, workflowID, runID string) error {
+	data, err := sqlEncode(value)
+	if err != nil {
+		return err
+	}
+
+	if err := d.deleteMapRow(tx, table, keyColumn, key, domainID, workflowID, runID); err != nil {
+		return err
+	}
+
+	_, err = d.exec(tx, fmt.Sprintf(sqlInsertMapRowQuery, table, keyColumn), d.shardID, domainID, workflowID, runID,
+		key, data, sqlDataEncoding)
+	return err
+}
+
+func (d *sqlExecutionPersistence) deleteMapRow(tx *sql.Tx, table, keyColumn string, key interface{},
+	domainID, workflowID, runID string) error {
+	_, err := d.exec(tx, fmt.Sprintf(sqlDeleteMapRowQuery, table, keyColumn), d.shardID, domainID, workflowID, runID,
+		key)
+	return err
+}
+
+func (d *sqlExecutionPersistence) DeleteWorkflowExecution(request *DeleteWorkflowExecutionRequest) error {
+	return d.txExecute("DeleteWorkflowExecution", func(tx *sql.Tx) error {
+		for _, table := range sqlExecutionTables {
+			if _, err := d.exec(tx, fmt.Sprintf(sqlDeleteExecutionRowsQuery, table), d.shardID, request.DomainID,
+				request.WorkflowID, request.RunID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (d *sqlExecutionPersistence) GetCurrentExecution(request *GetCurrentExecutionRequest) (
+	*GetCurrentExecutionResponse, error) {
+	response := &GetCurrentExecutionResponse{}
+	err := d.queryRow(d.db, sqlGetCurrentExecutionQuery, d.shardID, request.DomainID, request.WorkflowID).Scan(
+		&response.RunID, &response.StartRequestID, &response.State, &response.CloseStatus)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &workflow.EntityNotExistsError{
+				Message: fmt.Sprintf("Workflow execution not found.  WorkflowId: %v", request.WorkflowID),
+			}
+		}
+		return nil, convertSQLError("GetCurrentExecution", err)
+	}
+
+	return response, nil
+}
+
+func (d *sqlExecutionPersistence) ListConcreteExecutions(request *ListConcreteExecutionsRequest) (
+	*ListConcreteExecutionsResponse, error) {
+	token := &sqlListExecutionsPageToken{}
+	if len(request.NextPageToken) > 0 {
+		if err := json.Unmarshal(request.NextPageToken, token); err != nil {
+			return nil, &workflow.BadRequestError{
+				Message: fmt.Sprintf("ListConcreteExecutions operation failed.  Invalid next page token. Error: %v",
+					err),
+			}
+		}
+	}
+
+	rows, err := d.query(d.db, sqlListExecutionsQuery, d.shardID, token.DomainID, token.WorkflowID, token.RunID,
+		request.PageSize)
+	if err != nil {
+		return nil, convertSQLError("ListConcreteExecutions", err)
+	}
+
+	response := &ListConcreteExecutionsResponse{}
+	err = decodeSQLRows(rows, func(data []byte, encoding string) error {
+		info := &WorkflowExecutionInfo{}
+		if err := sqlDecode(data, encoding, info); err != nil {
+			return err
+		}
+		response.ExecutionInfos = append(response.ExecutionInfos, info)
+		return nil
+	})
+	if err != nil {
+		return nil, convertSQLError("ListConcreteExecutions", err)
+	}
+
+	if len(response.ExecutionInfos) == request.PageSize {
+		lastInfo := response.ExecutionInfos[len(response.ExecutionInfos)-1]
+		nextPageToken, err := json.Marshal(&sqlListExecutionsPageToken{
+			DomainID:   lastInfo.DomainID,
+			WorkflowID: lastInfo.WorkflowID,
+			RunID:      lastInfo.RunID,
+		})
+		if err != nil {
+			return nil, convertSQLError("ListConcreteExecutions", err)
+		}
+		response.NextPageToken = nextPageToken
+	}
+
+	return response, nil
+}
+
+func (d *sqlExecutionPersistence) GetTransferTasks(request *GetTransferTasksRequest) (*GetTransferTasksResponse,
+	error) {
+	rows, err := d.query(d.db, sqlGetTransferTasksQuery, d.shardID, request.ReadLevel, request.MaxReadLevel,
+		request.BatchSize)
+	if err != nil {
+		return nil, convertSQLError("GetTransferTasks", err)
+	}
+
+	response := &GetTransferTasksResponse{}
+	err = decodeSQLRows(rows, func(data []byte, encoding string) error {
+		task := &TransferTaskInfo{}
+		if err := sqlDecode(data, encoding, task); err != nil {
+			return err
+		}
+		response.Tasks = append(response.Tasks, task)
+		return nil
+	})
+	if err != nil {
+		return nil, convertSQLError("GetTransferTasks", err)
+	}
+
+	return response, nil
+}
+
+func (d *sqlExecutionPersistence) CompleteTransferTask(request *CompleteTransferTaskRequest) error {
+	if _, err := d.exec(d.db, sqlCompleteTransferTaskQuery, d.shardID, request.TaskID); err != nil {
+		return convertSQLError("CompleteTransferTask", err)
+	}
+	return nil
+}
+
+func (d *sqlExecutionPersistence) GetReplicationTasks(request *GetReplicationTasksRequest) (
+	*GetReplicationTasksResponse, error) {
+	rows, err := d.query(d.db, sqlGetReplicationTasksQuery, d.shardID, request.ReadLevel, request.MaxReadLevel,
+		request.BatchSize)
+	if err != nil {
+		return nil, convertSQLError("GetReplicationTasks", err)
+	}
+
+	response := &GetReplicationTasksResponse{}
+	err = decodeSQLRows(rows, func(data []byte, encoding string) error {
+		task := &ReplicationTaskInfo{}
+		if err := sqlDecode(data, encoding, task); err != nil {
+			return err
+		}
+		response.Tasks = append(response.Tasks, task)
+		return nil
+	})
+	if err != nil {
+		return nil, convertSQLError("GetReplicationTasks", err)
+	}
+
+	return response, nil
+}
+
+func (d *sqlExecutionPersistence) CompleteReplicationTask(request *CompleteReplicationTaskRequest) error {
+	if _, err := d.exec(d.db, sqlCompleteReplicationTaskQuery, d.shardID, request.TaskID); err != nil {
+		return convertSQLError("CompleteReplicationTask", err)
+	}
+	return nil
+}
+
+func (d *sqlExecutionPersistence) GetTimerIndexTasks(request *GetTimerIndexTasksRequest) (
+	*GetTimerIndexTasksResponse, error) {
+	rows, err := d.query(d.db, sqlGetTimerTasksQuery, d.shardID, request.MinTimestamp.UnixNano(),
+		request.MaxTimestamp.UnixNano(), request.BatchSize)
+	if err != nil {
+		return nil, convertSQLError("GetTimerTasks", err)
+	}
+
+	response := &GetTimerIndexTasksResponse{}
+	err = decodeSQLRows(rows, func(data []byte, encoding string) error {
+		timer := &TimerTaskInfo{}
+		if err := sqlDecode(data, encoding, timer); err != nil {
+			return err
+		}
+		response.Timers = append(response.Timers, timer)
+		return nil
+	})
+	if err != nil {
+		return nil, convertSQLError("GetTimerTasks", err)
+	}
+
+	// A full page means there could be more timers within the range, the token only signals that to the caller
+	if request.BatchSize > 0 && len(response.Timers) == request.BatchSize {
+		lastTimer := response.Timers[len(response.Timers)-1]
+		response.NextPageToken = make([]byte, sqlTimerPageTokenSize)
+		binary.BigEndian.PutUint64(response.NextPageToken, uint64(lastTimer.VisibilityTimestamp.UnixNano()))
+	}
+
+	return response, nil
+}
+
+func (d *sqlExecutionPersistence) CompleteTimerTask(request *CompleteTimerTaskRequest) error {
+	if _, err := d.exec(d.db, sqlCompleteTimerTaskQuery, d.shardID, request.VisibilityTimestamp.UnixNano(),
+		request.TaskID); err != nil {
+		return convertSQLError("CompleteTimerTask", err)
+	}
+	return nil
+}
+
+// assertShardOwnership locks the shard row for the rest of the transaction and verifies the range is still owned
+func (d *sqlExecutionPersistence) assertShardOwnership(tx *sql.Tx, rangeID int64, operation string) error {
+	actualRangeID, err := d.lockShard(tx, d.shardID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == sql.ErrNoRows || actualRangeID != rangeID {
+		return &ShardOwnershipLostError{
+			ShardID: d.shardID,
+			Msg: fmt.Sprintf("Failed to %v.  Request RangeID: %v, Actual RangeID: %v",
+				operation, rangeID, actualRangeID),
+		}
+	}
+	return nil
+}
+
+func (d *sqlExecutionPersistence) createTasks(tx *sql.Tx, transferTasks, replicationTasks, timerTasks []Task,
+	deleteTimerTask Task, domainID, workflowID, runID string) error {
+	for _, task := range transferTasks {
+		info, err := newTransferTaskInfo(task, domainID, workflowID, runID)
+		if err != nil {
+			return err
+		}
+		data, err := sqlEncode(info)
+		if err != nil {
+			return err
+		}
+		if _, err := d.exec(tx, sqlCreateTransferTaskQuery, d.shardID, info.TaskID, data,
+			sqlDataEncoding); err != nil {
+			return err
+		}
+	}
+
+	for _, task := range replicationTasks {
+		info, err := newReplicationTaskInfo(task, domainID, workflowID, runID)
+		if err != nil {
+			return err
+		}
+		data, err := sqlEncode(info)
+		if err != nil {
+			return err
+		}
+		if _, err := d.exec(tx, sqlCreateReplicationTaskQuery, d.shardID, info.TaskID, data,
+			sqlDataEncoding); err != nil {
+			return err
+		}
+	}
+
+	for _, task := range timerTasks {
+		info := newTimerTaskInfo(task, domainID, workflowID, runID)
+		data, err := sqlEncode(info)
+		if err != nil {
+			return err
+		}
+		if _, err := d.exec(tx, sqlCreateTimerTaskQuery, d.shardID, info.VisibilityTimestamp.UnixNano(),
+			info.TaskID, data, sqlDataEncoding); err != nil {
+			return err
+		}
+	}
+
+	if deleteTimerTask != nil {
+		if _, err := d.exec(tx, sqlCompleteTimerTaskQuery, d.shardID,
+			GetVisibilityTSFrom(deleteTimerTask).UnixNano(), deleteTimerTask.GetTaskID()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newTransferTaskInfo converts a transfer task into the TransferTaskInfo stored in the transfer queue
+func newTransferTaskInfo(task Task, domainID, workflowID, runID string) (*TransferTaskInfo, error) {
+	info := &TransferTaskInfo{
+		DomainID:         domainID,
+		WorkflowID:       workflowID,
+		RunID:            runID,
+		TaskID:           task.GetTaskID(),
+		TargetDomainID:   domainID,
+		TargetWorkflowID: transferTaskTransferTargetWorkflowID,
+		TaskType:         task.GetType(),
+	}
+
+	switch t := task.(type) {
+	case *ActivityTask:
+		info.TargetDomainID = t.DomainID
+		info.TaskList = t.TaskList
+		info.ScheduleID = t.ScheduleID
+
+	case *DecisionTask:
+		info.TargetDomainID = t.DomainID
+		info.TaskList = t.TaskList
+		info.ScheduleID = t.ScheduleID
+
+	case *CancelExecutionTask:
+		info.TargetDomainID = t.TargetDomainID
+		info.TargetWorkflowID = t.TargetWorkflowID
+		info.TargetRunID = t.TargetRunID
+		info.TargetChildWorkflowOnly = t.TargetChildWorkflowOnly
+		info.ScheduleID = t.InitiatedID
+
+	case *SignalExecutionTask:
+		info.TargetDomainID = t.TargetDomainID
+		info.TargetWorkflowID = t.TargetWorkflowID
+		info.TargetRunID = t.TargetRunID
+		info.TargetChildWorkflowOnly = t.TargetChildWorkflowOnly
+		info.ScheduleID = t.InitiatedID
+
+	case *StartChildExecutionTask:
+		info.TargetDomainID = t.TargetDomainID
+		info.TargetWorkflowID = t.TargetWorkflowID
+		info.ScheduleID = t.InitiatedID
+
+	case *CloseExecutionTask, *UpsertWorkflowSearchAttributesTask:
+		// No explicit property needs to be set
+
+	default:
+		return nil, fmt.Errorf("unknown transfer task type: %v", task.GetType())
+	}
+
+	return info, nil
+}
+
+// newReplicationTaskInfo converts a replication task into the ReplicationTaskInfo stored in the replication queue
+func newReplicationTaskInfo(task Task, domainID, workflowID, runID string) (*ReplicationTaskInfo, error) {
+	info := &ReplicationTaskInfo{
+		DomainID:     domainID,
+		WorkflowID:   workflowID,
+		RunID:        runID,
+		TaskID:       task.GetTaskID(),
+		TaskType:     task.GetType(),
+		FirstEventID: common.EmptyEventID,
+		NextEventID:  common.EmptyEventID,
+	}
+
+	switch t := task.(type) {
+	case *HistoryReplicationTask:
+		info.FirstEventID = t.FirstEventID
+		info.NextEventID = t.NextEventID
+		info.Version = t.Version
+		info.LastReplicationInfo = t.LastReplicationInfo
+
+	default:
+		return nil, fmt.Errorf("unknown replication task type: %v", task.GetType())
+	}
+
+	return info, nil
+}
+
+// newTimerTaskInfo converts a timer task into the TimerTaskInfo stored in the timer queue
+func newTimerTaskInfo(task Task, domainID, workflowID, runID string) *TimerTaskInfo {
+	info := &TimerTaskInfo{
+		DomainID:            domainID,
+		WorkflowID:          workflowID,
+		RunID:               runID,
+		VisibilityTimestamp: GetVisibilityTSFrom(task),
+		TaskID:              task.GetTaskID(),
+		TaskType:            task.GetType(),
+	}
+
+	switch t := task.(type) {
+	case *DecisionTimeoutTask:
+		info.EventID = t.EventID
+		info.TimeoutType = t.TimeoutType
+		info.ScheduleAttempt = t.ScheduleAttempt
+	case *ActivityTimeoutTask:
+		info.EventID = t.EventID
+		info.TimeoutType = t.TimeoutType
+	case *UserTimerTask:
+		info.EventID = t.EventID
+	case *WorkflowBackoffTimerTask:
+		info.TimeoutType = t.TimeoutType
+	}
+
+	return info
+}
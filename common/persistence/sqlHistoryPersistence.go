@@ -0,0 +1,176 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/uber-common/bark"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+)
+
+const (
+	sqlAppendHistoryEventsQuery = `INSERT INTO events (` +
+		`domain_id, workflow_id, run_id, first_event_id, range_id, tx_id, data, data_encoding, data_version) ` +
+		`VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	sqlLockHistoryEventsQuery = `SELECT range_id, tx_id ` +
+		`FROM events ` +
+		`WHERE domain_id = ? AND workflow_id = ? AND run_id = ? AND first_event_id = ? FOR UPDATE`
+
+	sqlOverwriteHistoryEventsQuery = `UPDATE events ` +
+		`SET range_id = ?, tx_id = ?, data = ?, data_encoding = ?, data_version = ? ` +
+		`WHERE domain_id = ? AND workflow_id = ? AND run_id = ? AND first_event_id = ?`
+
+	sqlGetWorkflowExecutionHistoryQuery = `SELECT first_event_id, data, data_encoding, data_version ` +
+		`FROM events ` +
+		`WHERE domain_id = ? AND workflow_id = ? AND run_id = ? AND first_event_id >= ? AND first_event_id < ? ` +
+		`ORDER BY first_event_id LIMIT ?`
+
+	sqlDeleteWorkflowExecutionHistoryQuery = `DELETE FROM events ` +
+		`WHERE domain_id = ? AND workflow_id = ? AND run_id = ?`
+
+	sqlHistoryPageTokenSize = 8
+)
+
+type (
+	sqlHistoryPersistence struct {
+		*sqlDB
+		logger bark.Logger
+	}
+)
+
+// NewSQLHistoryPersistence is used to create an instance of HistoryManager implementation
+func NewSQLHistoryPersistence(driverName, host string, port int, user, password, databaseName string, maxConns int,
+	logger bark.Logger) (HistoryManager, error) {
+	db, err := newSQLDB(driverName, host, port, user, password, databaseName, maxConns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlHistoryPersistence{sqlDB: db, logger: logger}, nil
+}
+
+func (h *sqlHistoryPersistence) AppendHistoryEvents(request *AppendHistoryEventsRequest) error {
+	domainID := request.DomainID
+	workflowID := request.Execution.GetWorkflowId()
+	runID := request.Execution.GetRunId()
+
+	return h.txExecute("AppendHistoryEvents", func(tx *sql.Tx) error {
+		var rangeID, txID int64
+		err := h.queryRow(tx, sqlLockHistoryEventsQuery, domainID, workflowID, runID, request.FirstEventID).Scan(
+			&rangeID, &txID)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		if request.Overwrite {
+			if err == sql.ErrNoRows || rangeID > request.RangeID || txID >= request.TransactionID {
+				return &ConditionFailedError{
+					Msg: "Failed to append history events.",
+				}
+			}
+			_, err = h.exec(tx, sqlOverwriteHistoryEventsQuery, request.RangeID, request.TransactionID,
+				request.Events.Data, request.Events.EncodingType, request.Events.Version, domainID, workflowID, runID,
+				request.FirstEventID)
+			return err
+		}
+
+		if err == nil {
+			return &ConditionFailedError{
+				Msg: "Failed to append history events.",
+			}
+		}
+		_, err = h.exec(tx, sqlAppendHistoryEventsQuery, domainID, workflowID, runID, request.FirstEventID,
+			request.RangeID, request.TransactionID, request.Events.Data, request.Events.EncodingType,
+			request.Events.Version)
+		return err
+	})
+}
+
+func (h *sqlHistoryPersistence) GetWorkflowExecutionHistory(request *GetWorkflowExecutionHistoryRequest) (
+	*GetWorkflowExecutionHistoryResponse, error) {
+	execution := request.Execution
+
+	// the page token is the first_event_id of the first batch of the next page
+	firstEventID := request.FirstEventID
+	if len(request.NextPageToken) == sqlHistoryPageTokenSize {
+		firstEventID = int64(binary.BigEndian.Uint64(request.NextPageToken))
+	} else if len(request.NextPageToken) != 0 {
+		return nil, &workflow.BadRequestError{
+			Message: "GetWorkflowExecutionHistory operation failed.  Invalid next page token.",
+		}
+	}
+	pageSize := request.PageSize
+	if pageSize <= 0 {
+		pageSize = math.MaxInt32
+	}
+
+	rows, err := h.query(h.db, sqlGetWorkflowExecutionHistoryQuery, request.DomainID, execution.GetWorkflowId(),
+		execution.GetRunId(), firstEventID, request.NextEventID, pageSize)
+	if err != nil {
+		return nil, convertSQLError("GetWorkflowExecutionHistory", err)
+	}
+	defer rows.Close()
+
+	var lastFirstEventID int64
+	// the same as cassandra, the last page has an empty but non nil token
+	response := &GetWorkflowExecutionHistoryResponse{NextPageToken: []byte{}}
+	for rows.Next() {
+		var history SerializedHistoryEventBatch
+		if err := rows.Scan(&lastFirstEventID, &history.Data, &history.EncodingType, &history.Version); err != nil {
+			return nil, convertSQLError("GetWorkflowExecutionHistory", err)
+		}
+		response.Events = append(response.Events, history)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, convertSQLError("GetWorkflowExecutionHistory", err)
+	}
+
+	if len(response.Events) == 0 && len(request.NextPageToken) == 0 {
+		return nil, &workflow.EntityNotExistsError{
+			Message: fmt.Sprintf("Workflow execution history not found.  WorkflowId: %v, RunId: %v",
+				execution.GetWorkflowId(), execution.GetRunId()),
+		}
+	}
+
+	if len(response.Events) == pageSize {
+		response.NextPageToken = make([]byte, sqlHistoryPageTokenSize)
+		binary.BigEndian.PutUint64(response.NextPageToken, uint64(lastFirstEventID+1))
+	}
+
+	return response, nil
+}
+
+func (h *sqlHistoryPersistence) DeleteWorkflowExecutionHistory(
+	request *DeleteWorkflowExecutionHistoryRequest) error {
+	execution := request.Execution
+	if _, err := h.exec(h.db, sqlDeleteWorkflowExecutionHistoryQuery, request.DomainID, execution.GetWorkflowId(),
+		execution.GetRunId()); err != nil {
+		return convertSQLError("DeleteWorkflowExecutionHistory", err)
+	}
+
+	return nil
+}
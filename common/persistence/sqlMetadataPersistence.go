@@ -0,0 +1,209 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/uber-common/bark"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+)
+
+const (
+	sqlCreateDomainQuery = `INSERT INTO domains (` +
+		`id, name, data, data_encoding, is_global_domain, config_version, failover_version, db_version) ` +
+		`VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	sqlGetExistingDomainQuery = `SELECT id FROM domains WHERE id = ? OR name = ?`
+
+	sqlDomainColumns = `SELECT data, data_encoding, is_global_domain, config_version, failover_version, db_version ` +
+		`FROM domains `
+
+	sqlGetDomainByIDQuery = sqlDomainColumns + `WHERE id = ?`
+
+	sqlGetDomainByNameQuery = sqlDomainColumns + `WHERE name = ?`
+
+	sqlUpdateDomainQuery = `UPDATE domains ` +
+		`SET data = ?, data_encoding = ?, config_version = ?, failover_version = ?, db_version = ? ` +
+		`WHERE name = ? AND db_version = ?`
+
+	sqlDeleteDomainQuery = `DELETE FROM domains WHERE id = ?`
+
+	sqlDeleteDomainByNameQuery = `DELETE FROM domains WHERE name = ?`
+)
+
+type (
+	sqlMetadataPersistence struct {
+		*sqlDB
+		currentClusterName string
+		logger             bark.Logger
+	}
+
+	// sqlDomainData is the blob stored in the data column of the domains table
+	sqlDomainData struct {
+		Info              *DomainInfo
+		Config            *DomainConfig
+		ReplicationConfig *DomainReplicationConfig
+	}
+)
+
+// NewSQLMetadataPersistence is used to create an instance of MetadataManager implementation
+func NewSQLMetadataPersistence(driverName, host string, port int, user, password, databaseName string,
+	maxConns int, currentClusterName string, logger bark.Logger) (MetadataManager, error) {
+	db, err := newSQLDB(driverName, host, port, user, password, databaseName, maxConns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlMetadataPersistence{
+		sqlDB:              db,
+		currentClusterName: currentClusterName,
+		logger:             logger,
+	}, nil
+}
+
+func (m *sqlMetadataPersistence) CreateDomain(request *CreateDomainRequest) (*CreateDomainResponse, error) {
+	data, err := sqlEncode(&sqlDomainData{
+		Info:              request.Info,
+		Config:            request.Config,
+		ReplicationConfig: request.ReplicationConfig,
+	})
+	if err != nil {
+		return nil, convertSQLError("CreateDomain", err)
+	}
+
+	err = m.txExecute("CreateDomain", func(tx *sql.Tx) error {
+		var existingID string
+		err := m.queryRow(tx, sqlGetExistingDomainQuery, request.Info.ID, request.Info.Name).Scan(&existingID)
+		if err == nil {
+			return &workflow.DomainAlreadyExistsError{
+				Message: fmt.Sprintf("Domain already exists.  DomainId: %v", existingID),
+			}
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		_, err = m.exec(tx, sqlCreateDomainQuery, request.Info.ID, request.Info.Name, data, sqlDataEncoding,
+			request.IsGlobalDomain, request.ConfigVersion, request.FailoverVersion, 0)
+		if isSQLDuplicateKeyError(err) {
+			return &workflow.DomainAlreadyExistsError{
+				Message: fmt.Sprintf("Domain already exists.  DomainId: %v, DomainName: %v", request.Info.ID,
+					request.Info.Name),
+			}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateDomainResponse{ID: request.Info.ID}, nil
+}
+
+func (m *sqlMetadataPersistence) GetDomain(request *GetDomainRequest) (*GetDomainResponse, error) {
+	if len(request.ID) > 0 && len(request.Name) > 0 {
+		return nil, &workflow.BadRequestError{
+			Message: "GetDomain operation failed.  Both ID and Name specified in request.",
+		}
+	} else if len(request.ID) == 0 && len(request.Name) == 0 {
+		return nil, &workflow.BadRequestError{
+			Message: "GetDomain operation failed.  Both ID and Name are empty.",
+		}
+	}
+
+	var row *sql.Row
+	identity := request.Name
+	if len(request.ID) > 0 {
+		row = m.queryRow(m.db, sqlGetDomainByIDQuery, request.ID)
+		identity = request.ID
+	} else {
+		row = m.queryRow(m.db, sqlGetDomainByNameQuery, request.Name)
+	}
+
+	var data []byte
+	var encoding string
+	response := &GetDomainResponse{}
+	err := row.Scan(&data, &encoding, &response.IsGlobalDomain, &response.ConfigVersion, &response.FailoverVersion,
+		&response.DBVersion)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &workflow.EntityNotExistsError{
+				Message: fmt.Sprintf("Domain %s does not exist.", identity),
+			}
+		}
+		return nil, convertSQLError("GetDomain", err)
+	}
+
+	domain := &sqlDomainData{}
+	if err := sqlDecode(data, encoding, domain); err != nil {
+		return nil, convertSQLError("GetDomain", err)
+	}
+	response.Info = domain.Info
+	response.Config = domain.Config
+	response.ReplicationConfig = domain.ReplicationConfig
+	if response.ReplicationConfig == nil {
+		response.ReplicationConfig = &DomainReplicationConfig{}
+	}
+	response.ReplicationConfig.ActiveClusterName = GetOrUseDefaultActiveCluster(m.currentClusterName,
+		response.ReplicationConfig.ActiveClusterName)
+	response.ReplicationConfig.Clusters = GetOrUseDefaultClusters(m.currentClusterName,
+		response.ReplicationConfig.Clusters)
+
+	return response, nil
+}
+
+func (m *sqlMetadataPersistence) UpdateDomain(request *UpdateDomainRequest) error {
+	data, err := sqlEncode(&sqlDomainData{
+		Info:              request.Info,
+		Config:            request.Config,
+		ReplicationConfig: request.ReplicationConfig,
+	})
+	if err != nil {
+		return convertSQLError("UpdateDomain", err)
+	}
+
+	// Same as the cassandra implementation, the update is silently skipped if db_version has moved on
+	if _, err := m.exec(m.db, sqlUpdateDomainQuery, data, sqlDataEncoding, request.ConfigVersion,
+		request.FailoverVersion, request.DBVersion+1, request.Info.Name, request.DBVersion); err != nil {
+		return convertSQLError("UpdateDomain", err)
+	}
+
+	return nil
+}
+
+func (m *sqlMetadataPersistence) DeleteDomain(request *DeleteDomainRequest) error {
+	if _, err := m.exec(m.db, sqlDeleteDomainQuery, request.ID); err != nil {
+		return convertSQLError("DeleteDomain", err)
+	}
+
+	return nil
+}
+
+func (m *sqlMetadataPersistence) DeleteDomainByName(request *DeleteDomainByNameRequest) error {
+	if _, err := m.exec(m.db, sqlDeleteDomainByNameQuery, request.Name); err != nil {
+		return convertSQLError("DeleteDomainByName", err)
+	}
+
+	return nil
+}
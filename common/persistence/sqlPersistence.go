@@ -0,0 +1,245 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+
+	// database/sql drivers for the supported SQL databases
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+)
+
+const (
+	// SQLDriverMySQL is the database/sql driver name used for MySQL
+	SQLDriverMySQL = "mysql"
+	// SQLDriverPostgres is the database/sql driver name used for Postgres
+	SQLDriverPostgres = "postgres"
+
+	defaultMySQLPort    = 3306
+	defaultPostgresPort = 5432
+
+	// error number and code of a unique key violation in MySQL and Postgres
+	mysqlDuplicateEntryErrorNumber   = 1062
+	postgresUniqueViolationErrorCode = "23505"
+
+	// all the blobs written by the SQL persistence are json encoded
+	sqlDataEncoding = common.EncodingTypeJSON
+)
+
+type (
+	// sqlDB wraps a database/sql connection pool and hides the differences between the supported drivers
+	sqlDB struct {
+		db         *sql.DB
+		driverName string
+	}
+
+	// sqlExecutor is implemented by both *sql.DB and *sql.Tx
+	sqlExecutor interface {
+		Exec(query string, args ...interface{}) (sql.Result, error)
+		Query(query string, args ...interface{}) (*sql.Rows, error)
+		QueryRow(query string, args ...interface{}) *sql.Row
+	}
+)
+
+func newSQLDB(driverName, host string, port int, user, password, databaseName string, maxConns int) (*sqlDB, error) {
+	dataSourceName, err := buildSQLDataSourceName(driverName, host, port, user, password, databaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	if maxConns > 0 {
+		db.SetMaxOpenConns(maxConns)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &sqlDB{db: db, driverName: driverName}, nil
+}
+
+func buildSQLDataSourceName(driverName, host string, port int, user, password, databaseName string) (string,
+	error) {
+	switch driverName {
+	case SQLDriverMySQL:
+		if port == 0 {
+			port = defaultMySQLPort
+		}
+		return fmt.Sprintf("%s:%s@tcp(%s)/%s", user, password, net.JoinHostPort(host, strconv.Itoa(port)),
+			databaseName), nil
+	case SQLDriverPostgres:
+		if port == 0 {
+			port = defaultPostgresPort
+		}
+		dataSourceName := url.URL{
+			Scheme:   SQLDriverPostgres,
+			User:     url.UserPassword(user, password),
+			Host:     net.JoinHostPort(host, strconv.Itoa(port)),
+			Path:     "/" + databaseName,
+			RawQuery: "sslmode=disable",
+		}
+		return dataSourceName.String(), nil
+	default:
+		return "", fmt.Errorf("unsupported sql driver: %v", driverName)
+	}
+}
+
+// Close releases the resources held by this object
+func (d *sqlDB) Close() {
+	if d.db != nil {
+		d.db.Close()
+	}
+}
+
+// rebind converts the '?' placeholders used by all the queries into the positional placeholders expected by postgres
+func (d *sqlDB) rebind(query string) string {
+	if d.driverName != SQLDriverPostgres {
+		return query
+	}
+
+	var buffer bytes.Buffer
+	position := 0
+	for _, c := range query {
+		if c == '?' {
+			position++
+			buffer.WriteString("$" + strconv.Itoa(position))
+		} else {
+			buffer.WriteRune(c)
+		}
+	}
+	return buffer.String()
+}
+
+func (d *sqlDB) exec(e sqlExecutor, query string, args ...interface{}) (sql.Result, error) {
+	return e.Exec(d.rebind(query), args...)
+}
+
+func (d *sqlDB) query(e sqlExecutor, query string, args ...interface{}) (*sql.Rows, error) {
+	return e.Query(d.rebind(query), args...)
+}
+
+func (d *sqlDB) queryRow(e sqlExecutor, query string, args ...interface{}) *sql.Row {
+	return e.QueryRow(d.rebind(query), args...)
+}
+
+// txExecute runs fn within a transaction which is only committed if fn succeeds.  Conditional writes are implemented
+// by locking the row holding the condition with 'SELECT ... FOR UPDATE' as the first statement of the transaction.
+func (d *sqlDB) txExecute(operation string, fn func(tx *sql.Tx) error) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return &workflow.InternalServiceError{
+			Message: fmt.Sprintf("%v operation failed. Failed to start transaction. Error: %v", operation, err),
+		}
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return convertSQLError(operation, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return &workflow.InternalServiceError{
+			Message: fmt.Sprintf("%v operation failed. Failed to commit transaction. Error: %v", operation, err),
+		}
+	}
+
+	return nil
+}
+
+// convertSQLError passes through the errors which are part of the persistence contract and converts everything else
+// into an InternalServiceError
+func convertSQLError(operation string, err error) error {
+	switch err.(type) {
+	case *ConditionFailedError,
+		*ShardAlreadyExistError,
+		*ShardOwnershipLostError,
+		*WorkflowExecutionAlreadyStartedError,
+		*workflow.EntityNotExistsError,
+		*workflow.DomainAlreadyExistsError,
+		*workflow.BadRequestError,
+		*workflow.InternalServiceError:
+		return err
+	}
+
+	if isSQLDuplicateKeyError(err) {
+		// the row did not exist when the transaction checked for it, but a concurrent transaction inserted it since
+		return &ConditionFailedError{
+			Msg: fmt.Sprintf("%v operation failed. Error: %v", operation, err),
+		}
+	}
+
+	return &workflow.InternalServiceError{
+		Message: fmt.Sprintf("%v operation failed. Error: %v", operation, err),
+	}
+}
+
+// isSQLDuplicateKeyError returns true if err is a unique key violation.  Locking a row which does not exist yet does
+// not block a concurrent transaction, so this is how a create loses the race against a concurrent create of the row.
+func isSQLDuplicateKeyError(err error) bool {
+	switch e := err.(type) {
+	case *mysql.MySQLError:
+		return e.Number == mysqlDuplicateEntryErrorNumber
+	case *pq.Error:
+		return e.Code == postgresUniqueViolationErrorCode
+	}
+	return false
+}
+
+func sqlEncode(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func sqlDecode(data []byte, encoding string, value interface{}) error {
+	if common.EncodingType(encoding) != sqlDataEncoding {
+		return fmt.Errorf("unsupported data encoding: %v", encoding)
+	}
+	return json.Unmarshal(data, value)
+}
+
+// decodeSQLRows calls fn with the data and data_encoding columns of every row and closes the rows when done
+func decodeSQLRows(rows *sql.Rows, fn func(data []byte, encoding string) error) error {
+	defer rows.Close()
+	for rows.Next() {
+		var data []byte
+		var encoding string
+		if err := rows.Scan(&data, &encoding); err != nil {
+			return err
+		}
+		if err := fn(data, encoding); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"github.com/uber-common/bark"
+	"github.com/uber/cadence/common/metrics"
+)
+
+type (
+	sqlPersistenceClientFactory struct {
+		db            *sqlDB
+		metricsClient metrics.Client
+		logger        bark.Logger
+	}
+)
+
+// NewSQLPersistenceClientFactory is used to create an instance of ExecutionManagerFactory implementation
+func NewSQLPersistenceClientFactory(driverName, host string, port int, user, password, databaseName string,
+	maxConns int, logger bark.Logger, metricsClient metrics.Client) (ExecutionManagerFactory, error) {
+	db, err := newSQLDB(driverName, host, port, user, password, databaseName, maxConns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlPersistenceClientFactory{db: db, logger: logger, metricsClient: metricsClient}, nil
+}
+
+// CreateExecutionManager implements ExecutionManagerFactory interface
+func (f *sqlPersistenceClientFactory) CreateExecutionManager(shardID int) (ExecutionManager, error) {
+	mgr := newSQLWorkflowExecutionPersistence(shardID, f.db, f.logger)
+
+	if f.metricsClient == nil {
+		return mgr, nil
+	}
+
+	return NewWorkflowExecutionPersistenceClient(mgr, f.metricsClient), nil
+}
+
+// Close releases the underlying resources held by this object
+func (f *sqlPersistenceClientFactory) Close() {
+	f.db.Close()
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+)
+
+// sqlDriver selects the database used by the SQL persistence tests, e.g. go test -args -sqlDriver=mysql
+var sqlDriver = flag.String("sqlDriver", "", "SQL driver to run the persistence tests against, mysql or postgres")
+
+func TestSQLShardPersistenceSuite(t *testing.T) {
+	skipWithoutSQLDriver(t)
+	s := new(shardPersistenceSuite)
+	s.SQLDriverName = *sqlDriver
+	suite.Run(t, s)
+}
+
+func TestSQLPersistenceSuite(t *testing.T) {
+	skipWithoutSQLDriver(t)
+	s := new(cassandraPersistenceSuite)
+	s.SQLDriverName = *sqlDriver
+	suite.Run(t, s)
+}
+
+func TestSQLHistoryPersistenceSuite(t *testing.T) {
+	skipWithoutSQLDriver(t)
+	s := new(historyPersistenceSuite)
+	s.SQLDriverName = *sqlDriver
+	suite.Run(t, s)
+}
+
+func TestSQLMetadataPersistenceSuite(t *testing.T) {
+	skipWithoutSQLDriver(t)
+	s := new(metadataPersistenceSuite)
+	s.SQLDriverName = *sqlDriver
+	suite.Run(t, s)
+}
+
+func TestSQLVisibilityPersistenceSuite(t *testing.T) {
+	skipWithoutSQLDriver(t)
+	s := new(visibilityPersistenceSuite)
+	s.SQLDriverName = *sqlDriver
+	suite.Run(t, s)
+}
+
+func TestBuildSQLDataSourceName(t *testing.T) {
+	dataSourceName, err := buildSQLDataSourceName(SQLDriverMySQL, "127.0.0.1", 0, "root", "pass", "cadence")
+	require.NoError(t, err)
+	require.Equal(t, "root:pass@tcp(127.0.0.1:3306)/cadence", dataSourceName)
+
+	dataSourceName, err = buildSQLDataSourceName(SQLDriverPostgres, "127.0.0.1", 5433, "postgres", "", "cadence")
+	require.NoError(t, err)
+	require.Equal(t, "postgres://postgres:@127.0.0.1:5433/cadence?sslmode=disable", dataSourceName)
+
+	_, err = buildSQLDataSourceName("sqlite3", "", 0, "", "", "")
+	require.Error(t, err)
+}
+
+func TestSQLRebind(t *testing.T) {
+	query := `SELECT a FROM t WHERE b = ? AND c = ?`
+	mysql := &sqlDB{driverName: SQLDriverMySQL}
+	require.Equal(t, query, mysql.rebind(query))
+	postgres := &sqlDB{driverName: SQLDriverPostgres}
+	require.Equal(t, `SELECT a FROM t WHERE b = $1 AND c = $2`, postgres.rebind(query))
+}
+
+func TestConvertSQLDuplicateKeyError(t *testing.T) {
+	// a create which loses the race against a concurrent create fails the same way as a failed condition
+	mysqlErr := &mysql.MySQLError{Number: mysqlDuplicateEntryErrorNumber, Message: "Duplicate entry"}
+	require.True(t, isSQLDuplicateKeyError(mysqlErr))
+	require.IsType(t, &ConditionFailedError{}, convertSQLError("CreateTasks", mysqlErr))
+	postgresErr := &pq.Error{Code: postgresUniqueViolationErrorCode, Message: "duplicate key value"}
+	require.True(t, isSQLDuplicateKeyError(postgresErr))
+	require.IsType(t, &ConditionFailedError{}, convertSQLError("CreateTasks", postgresErr))
+
+	for _, err := range []error{
+		&mysql.MySQLError{Number: 1213, Message: "Deadlock found"},
+		&pq.Error{Code: "40001", Message: "could not serialize access"},
+		errors.New("connection refused"),
+		nil,
+	} {
+		require.False(t, isSQLDuplicateKeyError(err))
+	}
+	require.IsType(t, &workflow.InternalServiceError{}, convertSQLError("CreateTasks", errors.New("connection refused")))
+}
+
+func skipWithoutSQLDriver(t *testing.T) {
+	if *sqlDriver == "" {
+		t.Skip("sqlDriver flag is not set, skipping SQL persistence tests")
+	}
+}
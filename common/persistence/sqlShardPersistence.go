@@ -0,0 +1,160 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/uber-common/bark"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+)
+
+const (
+	sqlCreateShardQuery = `INSERT INTO shards (shard_id, range_id, data, data_encoding) VALUES (?, ?, ?, ?)`
+
+	sqlGetShardQuery = `SELECT range_id, data, data_encoding FROM shards WHERE shard_id = ?`
+
+	sqlLockShardQuery = `SELECT range_id FROM shards WHERE shard_id = ? FOR UPDATE`
+
+	sqlUpdateShardQuery = `UPDATE shards SET range_id = ?, data = ?, data_encoding = ? WHERE shard_id = ?`
+)
+
+type (
+	sqlShardPersistence struct {
+		*sqlDB
+		currentClusterName string
+		logger             bark.Logger
+	}
+)
+
+// NewSQLShardPersistence is used to create an instance of ShardManager implementation
+func NewSQLShardPersistence(driverName, host string, port int, user, password, databaseName string, maxConns int,
+	currentClusterName string, logger bark.Logger) (ShardManager, error) {
+	db, err := newSQLDB(driverName, host, port, user, password, databaseName, maxConns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlShardPersistence{sqlDB: db, currentClusterName: currentClusterName, logger: logger}, nil
+}
+
+func (d *sqlShardPersistence) CreateShard(request *CreateShardRequest) error {
+	shardInfo := *request.ShardInfo
+	shardInfo.UpdatedAt = time.Now()
+	data, err := sqlEncode(&shardInfo)
+	if err != nil {
+		return convertSQLError("CreateShard", err)
+	}
+
+	return d.txExecute("CreateShard", func(tx *sql.Tx) error {
+		rangeID, err := d.lockShard(tx, shardInfo.ShardID)
+		if err == nil {
+			return &ShardAlreadyExistError{
+				Msg: fmt.Sprintf("Shard already exists in shards table.  ShardId: %v, RangeId: %v",
+					shardInfo.ShardID, rangeID),
+			}
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		_, err = d.exec(tx, sqlCreateShardQuery, shardInfo.ShardID, shardInfo.RangeID, data, sqlDataEncoding)
+		if isSQLDuplicateKeyError(err) {
+			return &ShardAlreadyExistError{
+				Msg: fmt.Sprintf("Shard already exists in shards table.  ShardId: %v", shardInfo.ShardID),
+			}
+		}
+		return err
+	})
+}
+
+func (d *sqlShardPersistence) GetShard(request *GetShardRequest) (*GetShardResponse, error) {
+	shardID := request.ShardID
+	var rangeID int64
+	var data []byte
+	var encoding string
+	err := d.queryRow(d.db, sqlGetShardQuery, shardID).Scan(&rangeID, &data, &encoding)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &workflow.EntityNotExistsError{
+				Message: fmt.Sprintf("Shard not found.  ShardId: %v", shardID),
+			}
+		}
+		return nil, convertSQLError("GetShard", err)
+	}
+
+	info := &ShardInfo{}
+	if err := sqlDecode(data, encoding, info); err != nil {
+		return nil, convertSQLError("GetShard", err)
+	}
+	info.ShardID = shardID
+	info.RangeID = rangeID
+
+	if info.ClusterTransferAckLevel == nil {
+		info.ClusterTransferAckLevel = map[string]int64{
+			d.currentClusterName: info.TransferAckLevel,
+		}
+	}
+	if info.ClusterTimerAckLevel == nil {
+		info.ClusterTimerAckLevel = map[string]time.Time{
+			d.currentClusterName: info.TimerAckLevel,
+		}
+	}
+
+	return &GetShardResponse{ShardInfo: info}, nil
+}
+
+func (d *sqlShardPersistence) UpdateShard(request *UpdateShardRequest) error {
+	shardInfo := *request.ShardInfo
+	shardInfo.UpdatedAt = time.Now()
+	data, err := sqlEncode(&shardInfo)
+	if err != nil {
+		return convertSQLError("UpdateShard", err)
+	}
+
+	return d.txExecute("UpdateShard", func(tx *sql.Tx) error {
+		rangeID, err := d.lockShard(tx, shardInfo.ShardID)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if err == sql.ErrNoRows || rangeID != request.PreviousRangeID {
+			return &ShardOwnershipLostError{
+				ShardID: shardInfo.ShardID,
+				Msg: fmt.Sprintf("Failed to update shard.  previous_range_id: %v, range_id: %v",
+					request.PreviousRangeID, rangeID),
+			}
+		}
+
+		_, err = d.exec(tx, sqlUpdateShardQuery, shardInfo.RangeID, data, sqlDataEncoding, shardInfo.ShardID)
+		return err
+	})
+}
+
+// lockShard reads the range_id of the shard and holds a lock on the shard row until the transaction ends.  Every
+// write to the executions of a shard goes through this lock, which fences writes from stale shard owners.
+func (d *sqlDB) lockShard(tx *sql.Tx, shardID int) (int64, error) {
+	var rangeID int64
+	err := d.queryRow(tx, sqlLockShardQuery, shardID).Scan(&rangeID)
+	return rangeID, err
+}
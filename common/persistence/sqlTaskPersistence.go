@@ -0,0 +1,334 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/uber-common/bark"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+)
+
+const (
+	sqlCreateTaskListQuery = `INSERT INTO task_lists ` +
+		`(domain_id, name, task_type, range_id, ack_level, kind, build_ids, paused, last_updated) ` +
+		`VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	sqlLockTaskListQuery = `SELECT range_id, ack_level, build_ids, paused ` +
+		`FROM task_lists ` +
+		`WHERE domain_id = ? AND name = ? AND task_type = ? FOR UPDATE`
+
+	sqlUpdateTaskListQuery = `UPDATE task_lists ` +
+		`SET range_id = ?, ack_level = ?, kind = ?, build_ids = ?, paused = ?, last_updated = ? ` +
+		`WHERE domain_id = ? AND name = ? AND task_type = ?`
+
+	sqlListTaskListsQuery = `SELECT name, task_type, range_id, ack_level, kind, build_ids, paused, last_updated ` +
+		`FROM task_lists ` +
+		`WHERE domain_id = ? AND (name, task_type) > (?, ?) ` +
+		`ORDER BY name, task_type LIMIT ?`
+
+	sqlCreateTaskQuery = `INSERT INTO tasks (domain_id, task_list_name, task_type, task_id, data, data_encoding) ` +
+		`VALUES (?, ?, ?, ?, ?, ?)`
+
+	sqlGetTasksQuery = `SELECT task_id, data, data_encoding ` +
+		`FROM tasks ` +
+		`WHERE domain_id = ? AND task_list_name = ? AND task_type = ? AND task_id > ? AND task_id <= ? ` +
+		`ORDER BY task_id LIMIT ?`
+
+	sqlCompleteTaskQuery = `DELETE FROM tasks ` +
+		`WHERE domain_id = ? AND task_list_name = ? AND task_type = ? AND task_id = ?`
+)
+
+type (
+	sqlTaskPersistence struct {
+		*sqlDB
+		logger bark.Logger
+	}
+
+	// sqlTaskListBuildIDs is stored JSON encoded in the build_ids column of task_lists
+	sqlTaskListBuildIDs struct {
+		Compatible [][]string
+		Retired    []string
+	}
+
+	sqlListTaskListsPageToken struct {
+		Name     string
+		TaskType int
+	}
+)
+
+// NewSQLTaskPersistence is used to create an instance of TaskManager implementation
+func NewSQLTaskPersistence(driverName, host string, port int, user, password, databaseName string, maxConns int,
+	logger bark.Logger) (TaskManager, error) {
+	db, err := newSQLDB(driverName, host, port, user, password, databaseName, maxConns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlTaskPersistence{sqlDB: db, logger: logger}, nil
+}
+
+func (d *sqlTaskPersistence) LeaseTaskList(request *LeaseTaskListRequest) (*LeaseTaskListResponse, error) {
+	if len(request.TaskList) == 0 {
+		return nil, &workflow.InternalServiceError{
+			Message: fmt.Sprintf("LeaseTaskList requires non empty task list"),
+		}
+	}
+
+	var rangeID, ackLevel int64
+	var buildIDs []byte
+	var paused bool
+	now := time.Now()
+	err := d.txExecute("LeaseTaskList", func(tx *sql.Tx) error {
+		err := d.queryRow(tx, sqlLockTaskListQuery, request.DomainID, request.TaskList, request.TaskType).Scan(
+			&rangeID, &ackLevel, &buildIDs, &paused)
+		switch {
+		case err == sql.ErrNoRows:
+			// First time task list is used
+			rangeID = initialRangeID
+			ackLevel = 0
+			_, err = d.exec(tx, sqlCreateTaskListQuery, request.DomainID, request.TaskList, request.TaskType, rangeID,
+				ackLevel, request.TaskListKind, nil, false, now.UnixNano())
+		case err == nil:
+			rangeID++
+			_, err = d.exec(tx, sqlUpdateTaskListQuery, rangeID, ackLevel, request.TaskListKind, buildIDs, paused,
+				now.UnixNano(), request.DomainID, request.TaskList, request.TaskType)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tli := &TaskListInfo{DomainID: request.DomainID, Name: request.TaskList, TaskType: request.TaskType, RangeID: rangeID, AckLevel: ackLevel, Kind: request.TaskListKind,
+		Paused: paused, LastUpdated: now}
+	if err := decodeSQLTaskListBuildIDs(tli, buildIDs); err != nil {
+		return nil, convertSQLError("LeaseTaskList", err)
+	}
+	return &LeaseTaskListResponse{TaskListInfo: tli}, nil
+}
+
+func (d *sqlTaskPersistence) UpdateTaskList(request *UpdateTaskListRequest) (*UpdateTaskListResponse, error) {
+	tli := request.TaskListInfo
+	var buildIDs []byte
+	if len(tli.CompatibleBuildIDs) > 0 || len(tli.RetiredBuildIDs) > 0 {
+		var err error
+		if buildIDs, err = sqlEncode(&sqlTaskListBuildIDs{
+			Compatible: tli.CompatibleBuildIDs,
+			Retired:    tli.RetiredBuildIDs,
+		}); err != nil {
+			return nil, convertSQLError("UpdateTaskList", err)
+		}
+	}
+
+	err := d.txExecute("UpdateTaskList", func(tx *sql.Tx) error {
+		var rangeID, ackLevel int64
+		var currentBuildIDs []byte
+		var currentPaused bool
+		err := d.queryRow(tx, sqlLockTaskListQuery, tli.DomainID, tli.Name, tli.TaskType).Scan(&rangeID, &ackLevel,
+			&currentBuildIDs, &currentPaused)
+		if err == sql.ErrNoRows && tli.Kind == TaskListKindSticky {
+			// sticky task lists are written unconditionally, the same way they are upserted with a TTL in cassandra
+			_, err = d.exec(tx, sqlCreateTaskListQuery, tli.DomainID, tli.Name, tli.TaskType, tli.RangeID, tli.AckLevel,
+				tli.Kind, buildIDs, tli.Paused, time.Now().UnixNano())
+			return err
+		}
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if tli.Kind != TaskListKindSticky && (err == sql.ErrNoRows || rangeID != tli.RangeID) {
+			return &ConditionFailedError{
+				Msg: fmt.Sprintf("Failed to update task list. name: %v, type: %v, rangeID: %v, db rangeID: %v",
+					tli.Name, tli.TaskType, tli.RangeID, rangeID),
+			}
+		}
+
+		_, err = d.exec(tx, sqlUpdateTaskListQuery, tli.RangeID, tli.AckLevel, tli.Kind, buildIDs, tli.Paused,
+			time.Now().UnixNano(), tli.DomainID, tli.Name, tli.TaskType)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdateTaskListResponse{}, nil
+}
+
+func (d *sqlTaskPersistence) CreateTasks(request *CreateTasksRequest) (*CreateTasksResponse, error) {
+	tli := request.TaskListInfo
+
+	err := d.txExecute("CreateTasks", func(tx *sql.Tx) error {
+		// The task list row is locked to ensure that range_id didn't change
+		var rangeID, ackLevel int64
+		var buildIDs []byte
+		var paused bool
+		err := d.queryRow(tx, sqlLockTaskListQuery, tli.DomainID, tli.Name, tli.TaskType).Scan(&rangeID, &ackLevel,
+			&buildIDs, &paused)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if err == sql.ErrNoRows || rangeID != tli.RangeID {
+			return &ConditionFailedError{
+				Msg: fmt.Sprintf("Failed to create task. TaskList: %v, taskListType: %v, rangeID: %v, db rangeID: %v",
+					tli.Name, tli.TaskType, tli.RangeID, rangeID),
+			}
+		}
+
+		for _, task := range request.Tasks {
+			info := &TaskInfo{
+				DomainID:               tli.DomainID,
+				WorkflowID:             task.Execution.GetWorkflowId(),
+				RunID:                  task.Execution.GetRunId(),
+				TaskID:                 task.TaskID,
+				ScheduleID:             task.Data.ScheduleID,
+				ScheduleToStartTimeout: task.Data.ScheduleToStartTimeout,
+				Priority:               task.Data.Priority,
+				BuildID:                task.Data.BuildID,
+				Expiry:                 task.Data.Expiry,
+			}
+			data, err := sqlEncode(info)
+			if err != nil {
+				return err
+			}
+			if _, err := d.exec(tx, sqlCreateTaskQuery, tli.DomainID, tli.Name, tli.TaskType, task.TaskID, data,
+				sqlDataEncoding); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateTasksResponse{}, nil
+}
+
+func (d *sqlTaskPersistence) GetTasks(request *GetTasksRequest) (*GetTasksResponse, error) {
+	if request.ReadLevel > request.MaxReadLevel {
+		return &GetTasksResponse{}, nil
+	}
+
+	rows, err := d.query(d.db, sqlGetTasksQuery, request.DomainID, request.TaskList, request.TaskType,
+		request.ReadLevel, request.MaxReadLevel, request.BatchSize)
+	if err != nil {
+		return nil, convertSQLError("GetTasks", err)
+	}
+	defer rows.Close()
+
+	response := &GetTasksResponse{}
+	for rows.Next() {
+		var taskID int64
+		var data []byte
+		var encoding string
+		if err := rows.Scan(&taskID, &data, &encoding); err != nil {
+			return nil, convertSQLError("GetTasks", err)
+		}
+		t := &TaskInfo{}
+		if err := sqlDecode(data, encoding, t); err != nil {
+			return nil, convertSQLError("GetTasks", err)
+		}
+		t.TaskID = taskID
+		response.Tasks = append(response.Tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, convertSQLError("GetTasks", err)
+	}
+
+	return response, nil
+}
+
+func (d *sqlTaskPersistence) CompleteTask(request *CompleteTaskRequest) error {
+	tli := request.TaskList
+	if _, err := d.exec(d.db, sqlCompleteTaskQuery, tli.DomainID, tli.Name, tli.TaskType,
+		request.TaskID); err != nil {
+		return convertSQLError("CompleteTask", err)
+	}
+	return nil
+}
+
+func (d *sqlTaskPersistence) ListTaskList(request *ListTaskListRequest) (*ListTaskListResponse, error) {
+	token := &sqlListTaskListsPageToken{}
+	if len(request.NextPageToken) > 0 {
+		if err := json.Unmarshal(request.NextPageToken, token); err != nil {
+			return nil, &workflow.BadRequestError{
+				Message: fmt.Sprintf("ListTaskList operation failed.  Invalid next page token. Error: %v", err),
+			}
+		}
+	}
+
+	rows, err := d.query(d.db, sqlListTaskListsQuery, request.DomainID, token.Name, token.TaskType,
+		request.PageSize)
+	if err != nil {
+		return nil, convertSQLError("ListTaskList", err)
+	}
+	defer rows.Close()
+
+	response := &ListTaskListResponse{}
+	for rows.Next() {
+		tli := &TaskListInfo{DomainID: request.DomainID}
+		var buildIDs []byte
+		var lastUpdated int64
+		if err := rows.Scan(&tli.Name, &tli.TaskType, &tli.RangeID, &tli.AckLevel, &tli.Kind, &buildIDs, &tli.Paused,
+			&lastUpdated); err != nil {
+			return nil, convertSQLError("ListTaskList", err)
+		}
+		if err := decodeSQLTaskListBuildIDs(tli, buildIDs); err != nil {
+			return nil, convertSQLError("ListTaskList", err)
+		}
+		tli.LastUpdated = time.Unix(0, lastUpdated)
+		response.Items = append(response.Items, tli)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, convertSQLError("ListTaskList", err)
+	}
+
+	if len(response.Items) == request.PageSize {
+		lastItem := response.Items[len(response.Items)-1]
+		nextPageToken, err := json.Marshal(&sqlListTaskListsPageToken{
+			Name:     lastItem.Name,
+			TaskType: lastItem.TaskType,
+		})
+		if err != nil {
+			return nil, convertSQLError("ListTaskList", err)
+		}
+		response.NextPageToken = nextPageToken
+	}
+
+	return response, nil
+}
+
+func decodeSQLTaskListBuildIDs(tli *TaskListInfo, buildIDs []byte) error {
+	if len(buildIDs) == 0 {
+		return nil
+	}
+	var b sqlTaskListBuildIDs
+	if err := sqlDecode(buildIDs, string(sqlDataEncoding), &b); err != nil {
+		return err
+	}
+	tli.CompatibleBuildIDs = b.Compatible
+	tli.RetiredBuildIDs = b.Retired
+	return nil
+}
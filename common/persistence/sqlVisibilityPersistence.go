@@ -0,0 +1,532 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persistence
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/uber-common/bark"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/visibilityquery"
+)
+
+const (
+	sqlCreateWorkflowExecutionStartedQuery = `INSERT INTO executions_visibility (` +
+		`domain_id, workflow_id, run_id, start_time, workflow_type_name, search_attributes) ` +
+		`VALUES (?, ?, ?, ?, ?, ?)`
+
+	sqlCreateWorkflowExecutionClosedQuery = `INSERT INTO executions_visibility (` +
+		`domain_id, workflow_id, run_id, start_time, close_time, workflow_type_name, close_status, history_length, ` +
+		`search_attributes) ` +
+		`VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	sqlLockWorkflowExecutionVisibilityQuery = `SELECT close_time FROM executions_visibility ` +
+		`WHERE domain_id = ? AND run_id = ? FOR UPDATE`
+
+	sqlUpdateWorkflowExecutionSearchAttributesQuery = `UPDATE executions_visibility SET search_attributes = ? ` +
+		`WHERE domain_id = ? AND run_id = ?`
+
+	sqlDeleteWorkflowExecutionVisibilityQuery = `DELETE FROM executions_visibility ` +
+		`WHERE domain_id = ? AND run_id = ?`
+
+	sqlCreateSearchAttributeQuery = `INSERT INTO executions_visibility_search_attributes (` +
+		`domain_id, run_id, name, value) ` +
+		`VALUES (?, ?, ?, ?)`
+
+	sqlDeleteSearchAttributesQuery = `DELETE FROM executions_visibility_search_attributes ` +
+		`WHERE domain_id = ? AND run_id = ?`
+
+	sqlVisibilityColumns = `SELECT workflow_id, run_id, start_time, close_time, workflow_type_name, close_status, ` +
+		`history_length, search_attributes ` +
+		`FROM executions_visibility `
+
+	sqlGetClosedWorkflowExecutionQuery = sqlVisibilityColumns +
+		`WHERE domain_id = ? AND workflow_id = ? AND run_id = ? AND close_time IS NOT NULL`
+
+	// The list query is assembled from the time range filter, an optional filter on the open/closed state of the
+	// execution, an optional filter on a column and the page token condition
+	sqlListWorkflowExecutionsQuery = sqlVisibilityColumns +
+		`WHERE domain_id = ? AND start_time >= ? AND start_time <= ? %v%v` +
+		`AND (start_time < ? OR (start_time = ? AND run_id > ?)) ` +
+		`ORDER BY start_time DESC, run_id LIMIT ?`
+
+	// The query and count queries are assembled from the filter and the sort keys of a visibility query
+	sqlListWorkflowExecutionsByQueryQuery = sqlVisibilityColumns +
+		`WHERE domain_id = ? %vORDER BY %v LIMIT ? OFFSET ?`
+
+	sqlCountWorkflowExecutionsQuery = `SELECT COUNT(*) FROM executions_visibility WHERE domain_id = ? %v`
+
+	sqlOpenFilter   = `AND close_time IS NULL `
+	sqlClosedFilter = `AND close_time IS NOT NULL `
+
+	sqlSearchAttributeFilter = `AND EXISTS (SELECT 1 FROM executions_visibility_search_attributes a ` +
+		`WHERE a.domain_id = executions_visibility.domain_id AND a.run_id = executions_visibility.run_id ` +
+		`AND a.name = ? AND a.value = ?) `
+)
+
+type (
+	sqlVisibilityPersistence struct {
+		*sqlDB
+		logger bark.Logger
+	}
+
+	// sqlVisibilityPageToken is the position of the last execution returned in a page
+	sqlVisibilityPageToken struct {
+		StartTime int64
+		RunID     string
+	}
+
+	// sqlVisibilityQueryPageToken is the number of executions returned in the previous pages of a query, queries
+	// can sort on columns which do not identify a position
+	sqlVisibilityQueryPageToken struct {
+		Offset int
+	}
+)
+
+// sqlVisibilityQueryColumns maps the fields of visibility queries to their column
+var sqlVisibilityQueryColumns = map[visibilityquery.Field]string{
+	visibilityquery.FieldWorkflowID:    "workflow_id",
+	visibilityquery.FieldWorkflowType:  "workflow_type_name",
+	visibilityquery.FieldCloseStatus:   "close_status",
+	visibilityquery.FieldStartTime:     "start_time",
+	visibilityquery.FieldCloseTime:     "close_time",
+	visibilityquery.FieldHistoryLength: "history_length",
+}
+
+// sqlLikePatternEscaper escapes the wildcards of LIKE patterns
+var sqlLikePatternEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// NewSQLVisibilityPersistence is used to create an instance of VisibilityManager implementation
+func NewSQLVisibilityPersistence(driverName, host string, port int, user, password, databaseName string,
+	maxConns int, logger bark.Logger) (VisibilityManager, error) {
+	db, err := newSQLDB(driverName, host, port, user, password, databaseName, maxConns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlVisibilityPersistence{sqlDB: db, logger: logger}, nil
+}
+
+func (v *sqlVisibilityPersistence) RecordWorkflowExecutionStarted(
+	request *RecordWorkflowExecutionStartedRequest) error {
+	return v.txExecute("RecordWorkflowExecutionStarted", func(tx *sql.Tx) error {
+		// The closed record wins if the execution has already been recorded as closed
+		var closeTime sql.NullInt64
+		err := v.queryRow(tx, sqlLockWorkflowExecutionVisibilityQuery, request.DomainUUID,
+			request.Execution.GetRunId()).Scan(&closeTime)
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		return v.createWorkflowExecutionStarted(tx, request.DomainUUID, request.Execution, request.StartTimestamp,
+			request.WorkflowTypeName, request.SearchAttributes)
+	})
+}
+
+func (v *sqlVisibilityPersistence) RecordWorkflowExecutionClosed(
+	request *RecordWorkflowExecutionClosedRequest) error {
+	// There is no TTL in SQL, the closed record is kept regardless of the domain retention
+	return v.txExecute("RecordWorkflowExecutionClosed", func(tx *sql.Tx) error {
+		if _, err := v.exec(tx, sqlDeleteWorkflowExecutionVisibilityQuery, request.DomainUUID,
+			request.Execution.GetRunId()); err != nil {
+			return err
+		}
+
+		searchAttributes, err := sqlEncode(request.SearchAttributes)
+		if err != nil {
+			return err
+		}
+		if _, err := v.exec(tx, sqlCreateWorkflowExecutionClosedQuery, request.DomainUUID,
+			request.Execution.GetWorkflowId(), request.Execution.GetRunId(), request.StartTimestamp,
+			request.CloseTimestamp, request.WorkflowTypeName, int32(request.Status), request.HistoryLength,
+			searchAttributes); err != nil {
+			return err
+		}
+		return v.replaceSearchAttributes(tx, request.DomainUUID, request.Execution.GetRunId(),
+			request.SearchAttributes)
+	})
+}
+
+func (v *sqlVisibilityPersistence) UpsertWorkflowExecution(request *UpsertWorkflowExecutionRequest) error {
+	return v.txExecute("UpsertWorkflowExecution", func(tx *sql.Tx) error {
+		var closeTime sql.NullInt64
+		err := v.queryRow(tx, sqlLockWorkflowExecutionVisibilityQuery, request.DomainUUID,
+			request.Execution.GetRunId()).Scan(&closeTime)
+		switch {
+		case err == sql.ErrNoRows:
+			// The started record has not been written yet
+			return v.createWorkflowExecutionStarted(tx, request.DomainUUID, request.Execution,
+				request.StartTimestamp, request.WorkflowTypeName, request.SearchAttributes)
+		case err != nil:
+			return err
+		case closeTime.Valid:
+			// The closed record already has the final search attributes
+			return nil
+		}
+
+		searchAttributes, err := sqlEncode(request.SearchAttributes)
+		if err != nil {
+			return err
+		}
+		if _, err := v.exec(tx, sqlUpdateWorkflowExecutionSearchAttributesQuery, searchAttributes,
+			request.DomainUUID, request.Execution.GetRunId()); err != nil {
+			return err
+		}
+		return v.replaceSearchAttributes(tx, request.DomainUUID, request.Execution.GetRunId(),
+			request.SearchAttributes)
+	})
+}
+
+func (v *sqlVisibilityPersistence) createWorkflowExecutionStarted(tx *sql.Tx, domainID string,
+	execution workflow.WorkflowExecution, startTimestamp int64, workflowTypeName string,
+	searchAttributes map[string][]byte) error {
+	data, err := sqlEncode(searchAttributes)
+	if err != nil {
+		return err
+	}
+	if _, err := v.exec(tx, sqlCreateWorkflowExecutionStartedQuery, domainID, execution.GetWorkflowId(),
+		execution.GetRunId(), startTimestamp, workflowTypeName, data); err != nil {
+		return err
+	}
+	return v.replaceSearchAttributes(tx, domainID, execution.GetRunId(), searchAttributes)
+}
+
+// replaceSearchAttributes replaces the rows of the search attributes table which are used to filter on the
+// search attributes of an execution
+func (v *sqlVisibilityPersistence) replaceSearchAttributes(tx *sql.Tx, domainID, runID string,
+	searchAttributes map[string][]byte) error {
+	if _, err := v.exec(tx, sqlDeleteSearchAttributesQuery, domainID, runID); err != nil {
+		return err
+	}
+	for name, value := range searchAttributes {
+		if _, err := v.exec(tx, sqlCreateSearchAttributeQuery, domainID, runID, name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *sqlVisibilityPersistence) ListOpenWorkflowExecutions(
+	request *ListWorkflowExecutionsRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListOpenWorkflowExecutions", request, sqlOpenFilter, "", nil)
+}
+
+func (v *sqlVisibilityPersistence) ListClosedWorkflowExecutions(
+	request *ListWorkflowExecutionsRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListClosedWorkflowExecutions", request, sqlClosedFilter, "", nil)
+}
+
+func (v *sqlVisibilityPersistence) ListOpenWorkflowExecutionsByType(
+	request *ListWorkflowExecutionsByTypeRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListOpenWorkflowExecutionsByType", &request.ListWorkflowExecutionsRequest,
+		sqlOpenFilter, "workflow_type_name", request.WorkflowTypeName)
+}
+
+func (v *sqlVisibilityPersistence) ListClosedWorkflowExecutionsByType(
+	request *ListWorkflowExecutionsByTypeRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListClosedWorkflowExecutionsByType", &request.ListWorkflowExecutionsRequest,
+		sqlClosedFilter, "workflow_type_name", request.WorkflowTypeName)
+}
+
+func (v *sqlVisibilityPersistence) ListOpenWorkflowExecutionsByWorkflowID(
+	request *ListWorkflowExecutionsByWorkflowIDRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListOpenWorkflowExecutionsByWorkflowID", &request.ListWorkflowExecutionsRequest,
+		sqlOpenFilter, "workflow_id", request.WorkflowID)
+}
+
+func (v *sqlVisibilityPersistence) ListClosedWorkflowExecutionsByWorkflowID(
+	request *ListWorkflowExecutionsByWorkflowIDRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListClosedWorkflowExecutionsByWorkflowID",
+		&request.ListWorkflowExecutionsRequest, sqlClosedFilter, "workflow_id", request.WorkflowID)
+}
+
+func (v *sqlVisibilityPersistence) ListOpenWorkflowExecutionsBySearchAttributes(
+	request *ListWorkflowExecutionsBySearchAttributesRequest) (*ListWorkflowExecutionsResponse, error) {
+	filter, filterArgs := getSQLSearchAttributesFilter(request.SearchAttributes)
+	return v.listWorkflowExecutionsWithFilter("ListOpenWorkflowExecutionsBySearchAttributes",
+		&request.ListWorkflowExecutionsRequest, sqlOpenFilter, filter, filterArgs)
+}
+
+func (v *sqlVisibilityPersistence) ListClosedWorkflowExecutionsBySearchAttributes(
+	request *ListWorkflowExecutionsBySearchAttributesRequest) (*ListWorkflowExecutionsResponse, error) {
+	filter, filterArgs := getSQLSearchAttributesFilter(request.SearchAttributes)
+	return v.listWorkflowExecutionsWithFilter("ListClosedWorkflowExecutionsBySearchAttributes",
+		&request.ListWorkflowExecutionsRequest, sqlClosedFilter, filter, filterArgs)
+}
+
+func (v *sqlVisibilityPersistence) ListClosedWorkflowExecutionsByStatus(
+	request *ListClosedWorkflowExecutionsByStatusRequest) (*ListWorkflowExecutionsResponse, error) {
+	return v.listWorkflowExecutions("ListClosedWorkflowExecutionsByStatus", &request.ListWorkflowExecutionsRequest,
+		sqlClosedFilter, "close_status", int32(request.Status))
+}
+
+func (v *sqlVisibilityPersistence) ListWorkflowExecutions(
+	request *ListWorkflowExecutionsByQueryRequest) (*ListWorkflowExecutionsResponse, error) {
+	token := &sqlVisibilityQueryPageToken{}
+	if len(request.NextPageToken) > 0 {
+		if err := json.Unmarshal(request.NextPageToken, token); err != nil {
+			return nil, &workflow.BadRequestError{
+				Message: fmt.Sprintf("ListWorkflowExecutions operation failed.  Invalid next page token. Error: %v",
+					err),
+			}
+		}
+	}
+
+	filter, args := getSQLQueryFilter(request.Query.Filter)
+	args = append([]interface{}{request.DomainUUID}, args...)
+	args = append(args, request.PageSize, token.Offset)
+
+	rows, err := v.query(v.db, fmt.Sprintf(sqlListWorkflowExecutionsByQueryQuery, filter,
+		getSQLQueryOrderBy(request.Query.OrderBy)), args...)
+	if err != nil {
+		return nil, convertSQLError("ListWorkflowExecutions", err)
+	}
+
+	executions, err := readSQLVisibilityRecords(rows)
+	if err != nil {
+		return nil, convertSQLError("ListWorkflowExecutions", err)
+	}
+
+	response := &ListWorkflowExecutionsResponse{Executions: executions}
+	if request.PageSize > 0 && len(executions) == request.PageSize {
+		nextPageToken, err := json.Marshal(&sqlVisibilityQueryPageToken{Offset: token.Offset + len(executions)})
+		if err != nil {
+			return nil, convertSQLError("ListWorkflowExecutions", err)
+		}
+		response.NextPageToken = nextPageToken
+	}
+
+	return response, nil
+}
+
+func (v *sqlVisibilityPersistence) CountWorkflowExecutions(
+	request *CountWorkflowExecutionsRequest) (*CountWorkflowExecutionsResponse, error) {
+	filter, args := getSQLQueryFilter(request.Query.Filter)
+	args = append([]interface{}{request.DomainUUID}, args...)
+
+	var count int64
+	if err := v.queryRow(v.db, fmt.Sprintf(sqlCountWorkflowExecutionsQuery, filter), args...).Scan(
+		&count); err != nil {
+		return nil, convertSQLError("CountWorkflowExecutions", err)
+	}
+
+	return &CountWorkflowExecutionsResponse{Count: count}, nil
+}
+
+func (v *sqlVisibilityPersistence) GetClosedWorkflowExecution(
+	request *GetClosedWorkflowExecutionRequest) (*GetClosedWorkflowExecutionResponse, error) {
+	execution := request.Execution
+	rows, err := v.query(v.db, sqlGetClosedWorkflowExecutionQuery, request.DomainUUID, execution.GetWorkflowId(),
+		execution.GetRunId())
+	if err != nil {
+		return nil, convertSQLError("GetClosedWorkflowExecution", err)
+	}
+
+	executions, err := readSQLVisibilityRecords(rows)
+	if err != nil {
+		return nil, convertSQLError("GetClosedWorkflowExecution", err)
+	}
+	if len(executions) == 0 {
+		return nil, &workflow.EntityNotExistsError{
+			Message: fmt.Sprintf("Workflow execution not found.  WorkflowId: %v, RunId: %v",
+				execution.GetWorkflowId(), execution.GetRunId()),
+		}
+	}
+
+	return &GetClosedWorkflowExecutionResponse{
+		Execution: executions[0],
+	}, nil
+}
+
+// listWorkflowExecutions returns a page of executions ordered by start time, newest first.  filterColumn is optional
+// and further restricts the executions to the ones having filterValue in that column.
+func (v *sqlVisibilityPersistence) listWorkflowExecutions(operation string, request *ListWorkflowExecutionsRequest,
+	stateFilter string, filterColumn string, filterValue interface{}) (*ListWorkflowExecutionsResponse, error) {
+	var filter string
+	var filterArgs []interface{}
+	if filterColumn != "" {
+		filter = fmt.Sprintf("AND %v = ? ", filterColumn)
+		filterArgs = append(filterArgs, filterValue)
+	}
+	return v.listWorkflowExecutionsWithFilter(operation, request, stateFilter, filter, filterArgs)
+}
+
+// listWorkflowExecutionsWithFilter returns a page of executions ordered by start time, newest first, which match
+// the filter conditions
+func (v *sqlVisibilityPersistence) listWorkflowExecutionsWithFilter(operation string,
+	request *ListWorkflowExecutionsRequest, stateFilter string, filter string,
+	filterArgs []interface{}) (*ListWorkflowExecutionsResponse, error) {
+	token := &sqlVisibilityPageToken{StartTime: request.LatestStartTime + 1}
+	if len(request.NextPageToken) > 0 {
+		if err := json.Unmarshal(request.NextPageToken, token); err != nil {
+			return nil, &workflow.BadRequestError{
+				Message: fmt.Sprintf("%v operation failed.  Invalid next page token. Error: %v", operation, err),
+			}
+		}
+	}
+
+	args := []interface{}{request.DomainUUID, request.EarliestStartTime, request.LatestStartTime}
+	args = append(args, filterArgs...)
+	args = append(args, token.StartTime, token.StartTime, token.RunID, request.PageSize)
+
+	rows, err := v.query(v.db, fmt.Sprintf(sqlListWorkflowExecutionsQuery, stateFilter, filter), args...)
+	if err != nil {
+		return nil, convertSQLError(operation, err)
+	}
+
+	executions, err := readSQLVisibilityRecords(rows)
+	if err != nil {
+		return nil, convertSQLError(operation, err)
+	}
+
+	response := &ListWorkflowExecutionsResponse{Executions: executions}
+	if request.PageSize > 0 && len(executions) == request.PageSize {
+		lastExecution := executions[len(executions)-1]
+		nextPageToken, err := json.Marshal(&sqlVisibilityPageToken{
+			StartTime: lastExecution.GetStartTime(),
+			RunID:     lastExecution.Execution.GetRunId(),
+		})
+		if err != nil {
+			return nil, convertSQLError(operation, err)
+		}
+		response.NextPageToken = nextPageToken
+	}
+
+	return response, nil
+}
+
+// getSQLSearchAttributesFilter returns one condition on the search attributes table per attribute
+func getSQLSearchAttributesFilter(searchAttributes map[string][]byte) (string, []interface{}) {
+	names := make([]string, 0, len(searchAttributes))
+	for name := range searchAttributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var filter string
+	var filterArgs []interface{}
+	for _, name := range names {
+		filter += sqlSearchAttributeFilter
+		filterArgs = append(filterArgs, name, searchAttributes[name])
+	}
+	return filter, filterArgs
+}
+
+// getSQLQueryFilter returns the condition of a visibility query filter and its arguments
+func getSQLQueryFilter(filter visibilityquery.Expression) (string, []interface{}) {
+	if filter == nil {
+		return "", nil
+	}
+	condition, args := getSQLQueryCondition(filter)
+	return "AND " + condition + " ", args
+}
+
+func getSQLQueryCondition(expression visibilityquery.Expression) (string, []interface{}) {
+	switch e := expression.(type) {
+	case *visibilityquery.AndExpression:
+		left, leftArgs := getSQLQueryCondition(e.Left)
+		right, rightArgs := getSQLQueryCondition(e.Right)
+		return fmt.Sprintf("(%v AND %v)", left, right), append(leftArgs, rightArgs...)
+	case *visibilityquery.OrExpression:
+		left, leftArgs := getSQLQueryCondition(e.Left)
+		right, rightArgs := getSQLQueryCondition(e.Right)
+		return fmt.Sprintf("(%v OR %v)", left, right), append(leftArgs, rightArgs...)
+	case *visibilityquery.ComparisonExpression:
+		return fmt.Sprintf("%v %v ?", sqlVisibilityQueryColumns[e.Field], e.Operator), []interface{}{e.Value}
+	case *visibilityquery.PrefixExpression:
+		// The prefix is matched literally, backslash is the default escape character of both MySQL and Postgres
+		return fmt.Sprintf("%v LIKE ?", sqlVisibilityQueryColumns[e.Field]),
+			[]interface{}{sqlLikePatternEscaper.Replace(e.Prefix) + "%"}
+	case *visibilityquery.NullExpression:
+		if e.Not {
+			return fmt.Sprintf("%v IS NOT NULL", sqlVisibilityQueryColumns[e.Field]), nil
+		}
+		return fmt.Sprintf("%v IS NULL", sqlVisibilityQueryColumns[e.Field]), nil
+	default:
+		// The parser only creates the expressions above
+		panic(fmt.Sprintf("unknown visibility query expression %T", expression))
+	}
+}
+
+// getSQLQueryOrderBy returns the sort keys of a visibility query.  The close columns are not set on open
+// executions, which sort before any value like in the other visibility stores whatever the database.
+func getSQLQueryOrderBy(orderBy []visibilityquery.OrderByField) string {
+	var keys []string
+	for _, field := range orderBy {
+		key := sqlVisibilityQueryColumns[field.Field]
+		if field.Field == visibilityquery.FieldCloseTime || field.Field == visibilityquery.FieldHistoryLength {
+			key = fmt.Sprintf("COALESCE(%v, -1)", key)
+		}
+		if field.Desc {
+			key += " DESC"
+		}
+		keys = append(keys, key)
+	}
+	return strings.Join(append(keys, "run_id"), ", ")
+}
+
+func readSQLVisibilityRecords(rows *sql.Rows) ([]*workflow.WorkflowExecutionInfo, error) {
+	defer rows.Close()
+
+	var records []*workflow.WorkflowExecutionInfo
+	for rows.Next() {
+		var workflowID, runID, typeName string
+		var startTime int64
+		var closeTime, closeStatus, historyLength sql.NullInt64
+		var searchAttributesData []byte
+		if err := rows.Scan(&workflowID, &runID, &startTime, &closeTime, &typeName, &closeStatus,
+			&historyLength, &searchAttributesData); err != nil {
+			return nil, err
+		}
+
+		var searchAttributes map[string][]byte
+		if len(searchAttributesData) > 0 {
+			if err := json.Unmarshal(searchAttributesData, &searchAttributes); err != nil {
+				return nil, err
+			}
+		}
+
+		record := &workflow.WorkflowExecutionInfo{
+			Execution: &workflow.WorkflowExecution{
+				WorkflowId: common.StringPtr(workflowID),
+				RunId:      common.StringPtr(runID),
+			},
+			Type:             &workflow.WorkflowType{Name: common.StringPtr(typeName)},
+			StartTime:        common.Int64Ptr(startTime),
+			SearchAttributes: getSearchAttributes(searchAttributes),
+		}
+		if closeTime.Valid {
+			status := workflow.WorkflowExecutionCloseStatus(closeStatus.Int64)
+			record.CloseTime = common.Int64Ptr(closeTime.Int64)
+			record.CloseStatus = &status
+			record.HistoryLength = common.Int64Ptr(historyLength.Int64)
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
@@ -20,7 +20,10 @@
 
 package persistence
 
-import s "github.com/uber/cadence/.gen/go/shared"
+import (
+	s "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common/visibilityquery"
+)
 
 // Interfaces for the Visibility Store.
 // This is a secondary store that is eventually consistent with the main
@@ -37,6 +40,7 @@ type (
 		WorkflowTypeName string
 		StartTimestamp   int64
 		WorkflowTimeout  int64
+		SearchAttributes map[string][]byte
 	}
 
 	// RecordWorkflowExecutionClosedRequest is used to add a record of a newly
@@ -50,6 +54,19 @@ type (
 		Status           s.WorkflowExecutionCloseStatus
 		HistoryLength    int64
 		RetentionSeconds int64
+		SearchAttributes map[string][]byte
+	}
+
+	// UpsertWorkflowExecutionRequest is used to update the search attributes of an
+	// open execution, it is ignored once the execution has been recorded as closed
+	UpsertWorkflowExecutionRequest struct {
+		DomainUUID       string
+		Execution        s.WorkflowExecution
+		WorkflowTypeName string
+		StartTimestamp   int64
+		WorkflowTimeout  int64
+		UpdateTimestamp  int64
+		SearchAttributes map[string][]byte
 	}
 
 	// ListWorkflowExecutionsRequest is used to list executions in a domain
@@ -86,6 +103,13 @@ type (
 		WorkflowID string
 	}
 
+	// ListWorkflowExecutionsBySearchAttributesRequest is used to list executions that
+	// have all the given search attributes, compared on their encoded values
+	ListWorkflowExecutionsBySearchAttributesRequest struct {
+		ListWorkflowExecutionsRequest
+		SearchAttributes map[string][]byte
+	}
+
 	// ListClosedWorkflowExecutionsByStatusRequest is used to list executions that
 	// have specific close status
 	ListClosedWorkflowExecutionsByStatusRequest struct {
@@ -93,6 +117,26 @@ type (
 		Status s.WorkflowExecutionCloseStatus
 	}
 
+	// ListWorkflowExecutionsByQueryRequest is used to list the open and closed executions that
+	// match a query, sorted as the query requires
+	ListWorkflowExecutionsByQueryRequest struct {
+		DomainUUID    string
+		Query         *visibilityquery.Query
+		PageSize      int
+		NextPageToken []byte
+	}
+
+	// CountWorkflowExecutionsRequest is used to count the open and closed executions that match a query
+	CountWorkflowExecutionsRequest struct {
+		DomainUUID string
+		Query      *visibilityquery.Query
+	}
+
+	// CountWorkflowExecutionsResponse is the response to CountWorkflowExecutionsRequest
+	CountWorkflowExecutionsResponse struct {
+		Count int64
+	}
+
 	// GetClosedWorkflowExecutionRequest is used retrieve the record for a specific execution
 	GetClosedWorkflowExecutionRequest struct {
 		DomainUUID string
@@ -109,13 +153,18 @@ type (
 		Closeable
 		RecordWorkflowExecutionStarted(request *RecordWorkflowExecutionStartedRequest) error
 		RecordWorkflowExecutionClosed(request *RecordWorkflowExecutionClosedRequest) error
+		UpsertWorkflowExecution(request *UpsertWorkflowExecutionRequest) error
 		ListOpenWorkflowExecutions(request *ListWorkflowExecutionsRequest) (*ListWorkflowExecutionsResponse, error)
 		ListClosedWorkflowExecutions(request *ListWorkflowExecutionsRequest) (*ListWorkflowExecutionsResponse, error)
 		ListOpenWorkflowExecutionsByType(request *ListWorkflowExecutionsByTypeRequest) (*ListWorkflowExecutionsResponse, error)
 		ListClosedWorkflowExecutionsByType(request *ListWorkflowExecutionsByTypeRequest) (*ListWorkflowExecutionsResponse, error)
 		ListOpenWorkflowExecutionsByWorkflowID(request *ListWorkflowExecutionsByWorkflowIDRequest) (*ListWorkflowExecutionsResponse, error)
 		ListClosedWorkflowExecutionsByWorkflowID(request *ListWorkflowExecutionsByWorkflowIDRequest) (*ListWorkflowExecutionsResponse, error)
+		ListOpenWorkflowExecutionsBySearchAttributes(request *ListWorkflowExecutionsBySearchAttributesRequest) (*ListWorkflowExecutionsResponse, error)
+		ListClosedWorkflowExecutionsBySearchAttributes(request *ListWorkflowExecutionsBySearchAttributesRequest) (*ListWorkflowExecutionsResponse, error)
 		ListClosedWorkflowExecutionsByStatus(request *ListClosedWorkflowExecutionsByStatusRequest) (*ListWorkflowExecutionsResponse, error)
+		ListWorkflowExecutions(request *ListWorkflowExecutionsByQueryRequest) (*ListWorkflowExecutionsResponse, error)
+		CountWorkflowExecutions(request *CountWorkflowExecutionsRequest) (*CountWorkflowExecutionsResponse, error)
 		GetClosedWorkflowExecution(request *GetClosedWorkflowExecutionRequest) (*GetClosedWorkflowExecutionResponse, error)
 	}
 )
@@ -21,6 +21,9 @@
 package common
 
 import (
+	"net"
+
+	"github.com/uber/tchannel-go"
 	"go.uber.org/yarpc"
 	"golang.org/x/net/context"
 )
@@ -43,6 +46,16 @@ const (
 	// ClientImplHeaderName refers to the name of the
 	// header that contains the client implementation
 	ClientImplHeaderName = "cadence-client-name"
+
+	// CallerIPHeaderName refers to the name of the
+	// header that contains the IP address of the client
+	// which called the frontend
+	CallerIPHeaderName = "cadence-caller-ip"
+
+	// AuthorizationTokenHeaderName refers to the name of the
+	// header that contains the signed token which authenticates
+	// the caller to the frontend
+	AuthorizationTokenHeaderName = "cadence-authorization"
 )
 
 type (
@@ -66,3 +79,18 @@ func AggregateYarpcOptions(ctx context.Context, opts ...yarpc.CallOption) []yarp
 	result = append(result, opts...)
 	return result
 }
+
+// GetCallerIP returns the IP address of the peer which made the inbound tchannel call of the context,
+// returns an empty string if there is no inbound tchannel call
+func GetCallerIP(ctx context.Context) string {
+	call := tchannel.CurrentCall(ctx)
+	if call == nil {
+		return ""
+	}
+	hostPort := call.RemotePeer().HostPort
+	host, _, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return hostPort
+	}
+	return host
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package searchattribute
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common/service/dynamicconfig"
+)
+
+type (
+	// Validator checks search attributes against the whitelist of the cluster
+	// and converts their values to a canonical encoding, so that equal values
+	// are persisted and filtered on as equal bytes
+	Validator struct {
+		validSearchAttributes dynamicconfig.MapPropertyFn
+	}
+)
+
+// defaultValidSearchAttributes is the whitelist used when the cluster does not configure one
+var defaultValidSearchAttributes = map[string]interface{}{
+	"CustomKeywordField":  "KEYWORD",
+	"CustomIntField":      "INT",
+	"CustomDoubleField":   "DOUBLE",
+	"CustomBoolField":     "BOOL",
+	"CustomDatetimeField": "DATETIME",
+}
+
+// GetDefaultValidSearchAttributes returns the default whitelist of search attribute names and value types
+func GetDefaultValidSearchAttributes() map[string]interface{} {
+	validSearchAttributes := make(map[string]interface{}, len(defaultValidSearchAttributes))
+	for key, valueType := range defaultValidSearchAttributes {
+		validSearchAttributes[key] = valueType
+	}
+	return validSearchAttributes
+}
+
+// NewValidator creates a new search attributes validator using the given whitelist
+func NewValidator(validSearchAttributes dynamicconfig.MapPropertyFn) *Validator {
+	return &Validator{
+		validSearchAttributes: validSearchAttributes,
+	}
+}
+
+// ValidateSearchAttributes returns a BadRequestError if any of the attributes is not whitelisted or its
+// value cannot be decoded as the whitelisted type, otherwise it returns the attributes in canonical encoding
+func (v *Validator) ValidateSearchAttributes(attributes *workflow.SearchAttributes) (*workflow.SearchAttributes, error) {
+	if attributes == nil || len(attributes.IndexedFields) == 0 {
+		return attributes, nil
+	}
+
+	validSearchAttributes := v.validSearchAttributes()
+	indexedFields := make(map[string][]byte, len(attributes.IndexedFields))
+	for key, value := range attributes.IndexedFields {
+		validType, ok := validSearchAttributes[key]
+		if !ok {
+			return nil, &workflow.BadRequestError{
+				Message: fmt.Sprintf("Search attribute %v is not whitelisted.", key),
+			}
+		}
+		valueType, err := ParseValueType(validType)
+		if err != nil {
+			return nil, &workflow.BadRequestError{
+				Message: fmt.Sprintf("Search attribute %v has invalid type in whitelist: %v.", key, err),
+			}
+		}
+		canonicalValue, err := Normalize(value, valueType)
+		if err != nil {
+			return nil, &workflow.BadRequestError{
+				Message: fmt.Sprintf("Search attribute %v is not a valid %v value: %v.", key, valueType, err),
+			}
+		}
+		indexedFields[key] = canonicalValue
+	}
+	return &workflow.SearchAttributes{IndexedFields: indexedFields}, nil
+}
+
+// ParseValueType converts a whitelist entry, either the name or the numeric
+// value of an IndexedValueType, to the IndexedValueType
+func ParseValueType(validType interface{}) (workflow.IndexedValueType, error) {
+	var valueType workflow.IndexedValueType
+	switch t := validType.(type) {
+	case workflow.IndexedValueType:
+		valueType = t
+	case string:
+		if err := valueType.UnmarshalText([]byte(strings.ToUpper(t))); err != nil {
+			return valueType, err
+		}
+		return valueType, nil
+	case int:
+		valueType = workflow.IndexedValueType(t)
+	case float64:
+		valueType = workflow.IndexedValueType(t)
+	default:
+		return valueType, fmt.Errorf("unknown value type %v", validType)
+	}
+
+	switch valueType {
+	case workflow.IndexedValueTypeKeyword,
+		workflow.IndexedValueTypeInt,
+		workflow.IndexedValueTypeDouble,
+		workflow.IndexedValueTypeBool,
+		workflow.IndexedValueTypeDatetime:
+		return valueType, nil
+	default:
+		return valueType, fmt.Errorf("unknown value type %v", validType)
+	}
+}
+
+// Normalize decodes the JSON encoded value as the given type and returns its canonical encoding
+func Normalize(value []byte, valueType workflow.IndexedValueType) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+
+	var decoded interface{}
+	switch valueType {
+	case workflow.IndexedValueTypeKeyword:
+		var keyword string
+		if err := decoder.Decode(&keyword); err != nil {
+			return nil, err
+		}
+		decoded = keyword
+	case workflow.IndexedValueTypeInt:
+		var number json.Number
+		if err := decoder.Decode(&number); err != nil {
+			return nil, err
+		}
+		intValue, err := number.Int64()
+		if err != nil {
+			return nil, err
+		}
+		decoded = intValue
+	case workflow.IndexedValueTypeDouble:
+		var number json.Number
+		if err := decoder.Decode(&number); err != nil {
+			return nil, err
+		}
+		doubleValue, err := number.Float64()
+		if err != nil {
+			return nil, err
+		}
+		decoded = doubleValue
+	case workflow.IndexedValueTypeBool:
+		var boolValue bool
+		if err := decoder.Decode(&boolValue); err != nil {
+			return nil, err
+		}
+		decoded = boolValue
+	case workflow.IndexedValueTypeDatetime:
+		var datetime string
+		if err := decoder.Decode(&datetime); err != nil {
+			return nil, err
+		}
+		t, err := time.Parse(time.RFC3339Nano, datetime)
+		if err != nil {
+			return nil, err
+		}
+		decoded = t.UTC().Format(time.RFC3339Nano)
+	default:
+		return nil, fmt.Errorf("unknown value type %v", valueType)
+	}
+
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after value")
+	}
+	return json.Marshal(decoded)
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package searchattribute
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common/service/dynamicconfig"
+)
+
+type (
+	validatorSuite struct {
+		suite.Suite
+		*require.Assertions
+		validator *Validator
+	}
+)
+
+func TestValidatorSuite(t *testing.T) {
+	suite.Run(t, new(validatorSuite))
+}
+
+func (s *validatorSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.validator = NewValidator(dynamicconfig.NewNopCollection().GetMapProperty(
+		dynamicconfig.ValidSearchAttributes, GetDefaultValidSearchAttributes(),
+	))
+}
+
+func (s *validatorSuite) TestValidateSearchAttributes() {
+	attributes, err := s.validator.ValidateSearchAttributes(nil)
+	s.Nil(err)
+	s.Nil(attributes)
+
+	attributes, err = s.validator.ValidateSearchAttributes(&workflow.SearchAttributes{
+		IndexedFields: map[string][]byte{
+			"CustomKeywordField":  []byte(`"order-1"`),
+			"CustomIntField":      []byte(` 10 `),
+			"CustomDoubleField":   []byte(`1.50`),
+			"CustomBoolField":     []byte(`true`),
+			"CustomDatetimeField": []byte(`"2018-10-01T10:00:00+02:00"`),
+		},
+	})
+	s.Nil(err)
+	s.Equal(map[string][]byte{
+		"CustomKeywordField":  []byte(`"order-1"`),
+		"CustomIntField":      []byte(`10`),
+		"CustomDoubleField":   []byte(`1.5`),
+		"CustomBoolField":     []byte(`true`),
+		"CustomDatetimeField": []byte(`"2018-10-01T08:00:00Z"`),
+	}, attributes.IndexedFields)
+}
+
+func (s *validatorSuite) TestValidateSearchAttributes_NotWhitelisted() {
+	_, err := s.validator.ValidateSearchAttributes(&workflow.SearchAttributes{
+		IndexedFields: map[string][]byte{"CustomerID": []byte(`"customer-1"`)},
+	})
+	s.IsType(&workflow.BadRequestError{}, err)
+}
+
+func (s *validatorSuite) TestValidateSearchAttributes_InvalidValue() {
+	invalidValues := map[string][]byte{
+		"CustomKeywordField":  []byte(`10`),
+		"CustomIntField":      []byte(`1.5`),
+		"CustomDoubleField":   []byte(`true`),
+		"CustomBoolField":     []byte(`1`),
+		"CustomDatetimeField": []byte(`"yesterday"`),
+	}
+	for key, value := range invalidValues {
+		_, err := s.validator.ValidateSearchAttributes(&workflow.SearchAttributes{
+			IndexedFields: map[string][]byte{key: value},
+		})
+		s.IsType(&workflow.BadRequestError{}, err, key)
+	}
+}
+
+func (s *validatorSuite) TestParseValueType() {
+	valueType, err := ParseValueType("keyword")
+	s.Nil(err)
+	s.Equal(workflow.IndexedValueTypeKeyword, valueType)
+
+	valueType, err = ParseValueType(int(workflow.IndexedValueTypeDatetime))
+	s.Nil(err)
+	s.Equal(workflow.IndexedValueTypeDatetime, valueType)
+
+	_, err = ParseValueType("text")
+	s.NotNil(err)
+	_, err = ParseValueType(100)
+	s.NotNil(err)
+}
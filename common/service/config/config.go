@@ -26,6 +26,7 @@ import (
 
 	"github.com/uber-go/tally/m3"
 	"github.com/uber/cadence/common/messaging"
+	"github.com/uber/cadence/common/service/dynamicconfig"
 	"github.com/uber/ringpop-go/discovery"
 )
 
@@ -36,6 +37,20 @@ type (
 		Ringpop Ringpop `yaml:"ringpop"`
 		// Cassandra is the configuration for connecting to cassandra
 		Cassandra Cassandra `yaml:"cassandra"`
+		// SQL is the configuration for connecting to a SQL database, cassandra is used when it is not specified
+		SQL *SQL `yaml:"sql"`
+		// InMemory keeps all the data in the memory of the process instead of cassandra or a SQL database, it is
+		// meant for development and the data is lost when the process exits
+		InMemory bool `yaml:"inMemory"`
+		// BoltVisibility is the configuration of the embedded BoltDB visibility store, it replaces the visibility
+		// store of the persistence when specified
+		BoltVisibility *BoltVisibility `yaml:"boltVisibility"`
+		// Archival is the configuration of the blob store keeping the histories of the domains with archival
+		// enabled, archival cannot be enabled on the domains when it is not specified
+		Archival *Archival `yaml:"archival"`
+		// Authorization is the configuration of the authorizer of the frontend APIs, every call is allowed when
+		// it is not specified
+		Authorization *Authorization `yaml:"authorization"`
 		// Log is the logging config
 		Log Logger `yaml:"log"`
 		// ClustersInfo is the config containing all valid clusters and active acluster
@@ -44,6 +59,9 @@ type (
 		Services map[string]Service `yaml:"services"`
 		// Kafka is the config for connecting to kafka
 		Kafka messaging.KafkaConfig `yaml:"kafka"`
+		// DynamicConfigClient is the config of the file holding the dynamic config values, the default values
+		// are used when it is not specified
+		DynamicConfigClient *dynamicconfig.FileBasedClientConfig `yaml:"dynamicConfigClient"`
 	}
 
 	// Service contains the service specific config items
@@ -93,7 +111,7 @@ type (
 	// Cassandra contains configuration to connect to Cassandra cluster
 	Cassandra struct {
 		// Hosts is a csv of cassandra endpoints
-		Hosts string `yaml:"hosts" validate:"nonzero"`
+		Hosts string `yaml:"hosts"`
 		// Port is the cassandra port used for connection by gocql client
 		Port int `yaml:"port"`
 		// User is the cassandra user used for authentication by gocql client
@@ -101,9 +119,9 @@ type (
 		// Password is the cassandra password used for authentication by gocql client
 		Password string `yaml:"password"`
 		// Keyspace is the cassandra keyspace
-		Keyspace string `yaml:"keyspace" validate:"nonzero"`
+		Keyspace string `yaml:"keyspace"`
 		// VisibilityKeyspace is the cassandra keyspace for visibility store
-		VisibilityKeyspace string `yaml:"visibilityKeyspace" validate:"nonzero"`
+		VisibilityKeyspace string `yaml:"visibilityKeyspace"`
 		// Consistency is the default cassandra consistency level
 		Consistency string `yaml:"consistency"`
 		// Datacenter is the data center filter arg for cassandra
@@ -112,6 +130,67 @@ type (
 		NumHistoryShards int `yaml:"numHistoryShards" validate:"nonzero"`
 	}
 
+	// SQL contains configuration to connect to a SQL database
+	SQL struct {
+		// DriverName is the name of the database/sql driver, either mysql or postgres
+		DriverName string `yaml:"driverName"`
+		// Host is the host of the SQL server
+		Host string `yaml:"host"`
+		// Port is the port of the SQL server, the default port of the driver is used when not specified
+		Port int `yaml:"port"`
+		// User is the user used for authentication
+		User string `yaml:"user"`
+		// Password is the password used for authentication
+		Password string `yaml:"password"`
+		// DatabaseName is the database holding the cadence tables
+		DatabaseName string `yaml:"databaseName"`
+		// VisibilityDatabaseName is the database holding the visibility tables
+		VisibilityDatabaseName string `yaml:"visibilityDatabaseName"`
+		// MaxConns is the max number of connections of each connection pool
+		MaxConns int `yaml:"maxConns"`
+	}
+
+	// BoltVisibility contains the configuration of the embedded BoltDB visibility store.  The database file is
+	// local to the process, so it only fits the clusters running all their frontend and history services in a
+	// single process.
+	BoltVisibility struct {
+		// Path is the path of the database file, it is created if it does not exist
+		Path string `yaml:"path" validate:"nonzero"`
+	}
+
+	// Archival contains the configuration of the archiver, one of the blob stores has to be specified
+	Archival struct {
+		// Filestore stores the histories in a directory of the local file system
+		Filestore *FilestoreArchiver `yaml:"filestore"`
+	}
+
+	// FilestoreArchiver contains the configuration of the archiver storing the histories in files
+	FilestoreArchiver struct {
+		// StoreDirectory is the directory holding the buckets, it is created if it does not exist
+		StoreDirectory string `yaml:"storeDirectory" validate:"nonzero"`
+	}
+
+	// Authorization contains the allow lists of the callers which are authorized to call the frontend APIs
+	Authorization struct {
+		// SigningKey is the shared secret which signs the tokens authenticating the callers, the callers are
+		// not authenticated when it is empty and only the domains without allow lists can be called
+		SigningKey string `yaml:"signingKey"`
+		// Domains maps a domain name to its allow lists, the allow lists of the "*" entry apply to the domains
+		// which are not listed, the calls on a domain are allowed when it has no allow lists
+		Domains map[string]DomainAllowList `yaml:"domains"`
+	}
+
+	// DomainAllowList lists the identities of the callers authorized to call the frontend APIs of a domain
+	DomainAllowList struct {
+		// Read lists the callers allowed to call the APIs which only read the domain
+		Read []string `yaml:"read"`
+		// Write lists the callers allowed to call the APIs which read or change the workflows of the domain
+		Write []string `yaml:"write"`
+		// Admin lists the callers allowed to call every API of the domain, including the APIs which change the
+		// domain or administer its task lists
+		Admin []string `yaml:"admin"`
+	}
+
 	// Replicator describes the configuration of replicator
 	Replicator struct {
 	}
@@ -58,6 +58,12 @@ type DurationPropertyFn func(opts ...FilterOption) time.Duration
 // BoolPropertyFn is a wrapper to get bool property from dynamic config
 type BoolPropertyFn func(opts ...FilterOption) bool
 
+// StringPropertyFn is a wrapper to get string property from dynamic config
+type StringPropertyFn func(opts ...FilterOption) string
+
+// MapPropertyFn is a wrapper to get map property from dynamic config
+type MapPropertyFn func(opts ...FilterOption) map[string]interface{}
+
 // GetProperty gets a eface property and returns defaultValue if property is not found
 func (c *Collection) GetProperty(key Key, defaultValue interface{}) PropertyFn {
 	return func() interface{} {
@@ -121,3 +127,35 @@ func (c *Collection) GetBoolProperty(key Key, defaultValue bool) BoolPropertyFn
 		return val
 	}
 }
+
+// GetStringProperty gets property and asserts that it's a string
+func (c *Collection) GetStringProperty(key Key, defaultValue string) StringPropertyFn {
+	return func(opts ...FilterOption) string {
+		val, err := c.client.GetStringValue(key, getFilterMap(opts...), defaultValue)
+		if err != nil {
+			c.logNoValue(key, err)
+		}
+		return val
+	}
+}
+
+// GetMapProperty gets property and asserts that it's a map
+func (c *Collection) GetMapProperty(key Key, defaultValue map[string]interface{}) MapPropertyFn {
+	return func(opts ...FilterOption) map[string]interface{} {
+		val, err := c.client.GetMapValue(key, getFilterMap(opts...), defaultValue)
+		if err != nil {
+			c.logNoValue(key, err)
+		}
+		return val
+	}
+}
+
+// GetIntPropertyFn returns value as IntPropertyFn
+func GetIntPropertyFn(value int) IntPropertyFn {
+	return func(opts ...FilterOption) int { return value }
+}
+
+// GetDurationPropertyFn returns value as DurationPropertyFn
+func GetDurationPropertyFn(value time.Duration) DurationPropertyFn {
+	return func(opts ...FilterOption) time.Duration { return value }
+}
@@ -118,3 +118,9 @@ func (s *configSuite) TestGetDurationProperty() {
 	interval := s.cln.GetDurationProperty(key, time.Second)
 	s.Equal(time.Second, interval())
 }
+
+func (s *configSuite) TestGetStringProperty() {
+	key := HistoryEventEncodingType
+	encoding := s.cln.GetStringProperty(key, "json")
+	s.Equal("json", encoding())
+}
@@ -34,6 +34,8 @@ const (
 	_matchingRoot               = "matching."
 	_matchingDomainTaskListRoot = _matchingRoot + "domain." + "taskList."
 	_historyRoot                = "history."
+	_frontendRoot               = "frontend."
+	_systemRoot                 = "system."
 )
 
 var keys = []string{
@@ -45,8 +47,113 @@ var keys = []string{
 	_matchingDomainTaskListRoot + "updateAckInterval",
 	_matchingDomainTaskListRoot + "idleTasklistCheckInterval",
 	_historyRoot + "longPollExpirationInterval",
+	_historyRoot + "eventEncodingType",
+	_systemRoot + "validSearchAttributes",
+	_frontendRoot + "domainRPS",
+	_frontendRoot + "rps",
+	_frontendRoot + "visibilityMaxPageSize",
+	_frontendRoot + "historyMaxPageSize",
+	_frontendRoot + "historyMgrNumConns",
+	_historyRoot + "historyCacheInitialSize",
+	_historyRoot + "historyCacheMaxSize",
+	_historyRoot + "historyCacheTTL",
+	_historyRoot + "acquireShardInterval",
+	_historyRoot + "defaultScheduleToStartActivityTimeout",
+	_historyRoot + "defaultScheduleToCloseActivityTimeout",
+	_historyRoot + "defaultStartToCloseActivityTimeout",
+	_historyRoot + "timerTaskBatchSize",
+	_historyRoot + "timerTaskWorkerCount",
+	_historyRoot + "timerProcessorUpdateFailureRetryCount",
+	_historyRoot + "timerProcessorGetFailureRetryCount",
+	_historyRoot + "timerProcessorUpdateAckInterval",
+	_historyRoot + "timerProcessorForceUpdateInterval",
+	_historyRoot + "transferTaskBatchSize",
+	_historyRoot + "transferProcessorMaxPollRPS",
+	_historyRoot + "transferTaskWorkerCount",
+	_historyRoot + "transferTaskMaxRetryCount",
+	_historyRoot + "transferProcessorMaxPollInterval",
+	_historyRoot + "transferProcessorUpdateAckInterval",
+	_historyRoot + "transferProcessorForceUpdateInterval",
+	_historyRoot + "replicatorTaskBatchSize",
+	_historyRoot + "replicatorTaskWorkerCount",
+	_historyRoot + "replicatorTaskMaxRetryCount",
+	_historyRoot + "replicatorProcessorMaxPollRPS",
+	_historyRoot + "replicatorProcessorMaxPollInterval",
+	_historyRoot + "replicatorProcessorUpdateAckInterval",
+	_historyRoot + "replicatorProcessorForceUpdateInterval",
+	_historyRoot + "executionMgrNumConns",
+	_historyRoot + "historyMgrNumConns",
+	_historyRoot + "archivalHistoryPageSize",
+	_historyRoot + "archivalTimeout",
+	_matchingDomainTaskListRoot + "numTaskListWritePartitions",
+	_matchingDomainTaskListRoot + "numTaskListReadPartitions",
+	_matchingDomainTaskListRoot + "priorityStarvationThreshold",
 }
 
+// keyTypes are the types of the values of the keys, a value of another type is rejected by the clients reading
+// the values from an untyped source such as a file.  Every key needs to have its type here.
+var keyTypes = map[Key]valueType{
+	MatchingMinTaskThrottlingBurstSize:     intType,
+	MatchingMaxTaskBatchSize:               intType,
+	MatchingLongPollExpirationInterval:     durationType,
+	MatchingEnableSyncMatch:                boolType,
+	MatchingUpdateAckInterval:              durationType,
+	MatchingIdleTasklistCheckInterval:      durationType,
+	HistoryLongPollExpirationInterval:      durationType,
+	HistoryEventEncodingType:               stringType,
+	ValidSearchAttributes:                  mapType,
+	FrontendDomainRPS:                      intType,
+	FrontendRPS:                            intType,
+	FrontendVisibilityMaxPageSize:          intType,
+	FrontendHistoryMaxPageSize:             intType,
+	FrontendHistoryMgrNumConns:             intType,
+	HistoryCacheInitialSize:                intType,
+	HistoryCacheMaxSize:                    intType,
+	HistoryCacheTTL:                        durationType,
+	AcquireShardInterval:                   durationType,
+	DefaultScheduleToStartActivityTimeout:  durationType,
+	DefaultScheduleToCloseActivityTimeout:  durationType,
+	DefaultStartToCloseActivityTimeout:     durationType,
+	TimerTaskBatchSize:                     intType,
+	TimerTaskWorkerCount:                   intType,
+	TimerProcessorUpdateFailureRetryCount:  intType,
+	TimerProcessorGetFailureRetryCount:     intType,
+	TimerProcessorUpdateAckInterval:        durationType,
+	TimerProcessorForceUpdateInterval:      durationType,
+	TransferTaskBatchSize:                  intType,
+	TransferProcessorMaxPollRPS:            intType,
+	TransferTaskWorkerCount:                intType,
+	TransferTaskMaxRetryCount:              intType,
+	TransferProcessorMaxPollInterval:       durationType,
+	TransferProcessorUpdateAckInterval:     durationType,
+	TransferProcessorForceUpdateInterval:   durationType,
+	ReplicatorTaskBatchSize:                intType,
+	ReplicatorTaskWorkerCount:              intType,
+	ReplicatorTaskMaxRetryCount:            intType,
+	ReplicatorProcessorMaxPollRPS:          intType,
+	ReplicatorProcessorMaxPollInterval:     durationType,
+	ReplicatorProcessorUpdateAckInterval:   durationType,
+	ReplicatorProcessorForceUpdateInterval: durationType,
+	ExecutionMgrNumConns:                   intType,
+	HistoryMgrNumConns:                     intType,
+	ArchivalHistoryPageSize:                intType,
+	ArchivalTimeout:                        durationType,
+	MatchingNumTaskListWritePartitions:     intType,
+	MatchingNumTaskListReadPartitions:      intType,
+	MatchingPriorityStarvationThreshold:    intType,
+}
+
+type valueType int
+
+const (
+	intType valueType = iota
+	floatType
+	boolType
+	stringType
+	mapType
+	durationType
+)
+
 const (
 	// The order of constants is important. It should match the order in the keys array above.
 	unknownKey Key = iota
@@ -66,13 +173,101 @@ const (
 	MatchingIdleTasklistCheckInterval
 	// HistoryLongPollExpirationInterval is the long poll expiration interval in the history service
 	HistoryLongPollExpirationInterval
+	// HistoryEventEncodingType is the encoding type used for newly written history events
+	HistoryEventEncodingType
+	// ValidSearchAttributes is the whitelist of search attribute names and their value types
+	ValidSearchAttributes
+	// FrontendDomainRPS is the rate limit of the requests of a domain in the frontend service
+	FrontendDomainRPS
+	// FrontendRPS is the rate limit of all the requests in the frontend service
+	FrontendRPS
+	// FrontendVisibilityMaxPageSize is the default page size of the visibility list APIs
+	FrontendVisibilityMaxPageSize
+	// FrontendHistoryMaxPageSize is the default page size of GetWorkflowExecutionHistory
+	FrontendHistoryMaxPageSize
+	// FrontendHistoryMgrNumConns is the number of connections of the history persistence in the frontend service
+	FrontendHistoryMgrNumConns
+
+	// History keys
+
+	// HistoryCacheInitialSize is the initial size of the history cache of a shard
+	HistoryCacheInitialSize
+	// HistoryCacheMaxSize is the max size of the history cache of a shard
+	HistoryCacheMaxSize
+	// HistoryCacheTTL is the TTL of the entries of the history cache
+	HistoryCacheTTL
+	// AcquireShardInterval is the interval of the shard controller to acquire the shards owned by the host
+	AcquireShardInterval
+	// DefaultScheduleToStartActivityTimeout is the default schedule to start timeout of activities
+	DefaultScheduleToStartActivityTimeout
+	// DefaultScheduleToCloseActivityTimeout is the default schedule to close timeout of activities
+	DefaultScheduleToCloseActivityTimeout
+	// DefaultStartToCloseActivityTimeout is the default start to close timeout of activities
+	DefaultStartToCloseActivityTimeout
+	// TimerTaskBatchSize is the batch size of the timer queue processor
+	TimerTaskBatchSize
+	// TimerTaskWorkerCount is the number of workers of the timer queue processor
+	TimerTaskWorkerCount
+	// TimerProcessorUpdateFailureRetryCount is the retry count of the timer processor for failed updates
+	TimerProcessorUpdateFailureRetryCount
+	// TimerProcessorGetFailureRetryCount is the retry count of the timer processor for failed reads
+	TimerProcessorGetFailureRetryCount
+	// TimerProcessorUpdateAckInterval is the interval of the timer processor to update the ack level
+	TimerProcessorUpdateAckInterval
+	// TimerProcessorForceUpdateInterval is the interval of the timer processor to force an ack level update
+	TimerProcessorForceUpdateInterval
+	// TransferTaskBatchSize is the batch size of the transfer queue processor
+	TransferTaskBatchSize
+	// TransferProcessorMaxPollRPS is the max poll rate of the transfer queue processor
+	TransferProcessorMaxPollRPS
+	// TransferTaskWorkerCount is the number of workers of the transfer queue processor
+	TransferTaskWorkerCount
+	// TransferTaskMaxRetryCount is the max retry count of a transfer task
+	TransferTaskMaxRetryCount
+	// TransferProcessorMaxPollInterval is the max poll interval of the transfer queue processor
+	TransferProcessorMaxPollInterval
+	// TransferProcessorUpdateAckInterval is the interval of the transfer processor to update the ack level
+	TransferProcessorUpdateAckInterval
+	// TransferProcessorForceUpdateInterval is the interval of the transfer processor to force an ack level update
+	TransferProcessorForceUpdateInterval
+	// ReplicatorTaskBatchSize is the batch size of the replicator queue processor
+	ReplicatorTaskBatchSize
+	// ReplicatorTaskWorkerCount is the number of workers of the replicator queue processor
+	ReplicatorTaskWorkerCount
+	// ReplicatorTaskMaxRetryCount is the max retry count of a replicator task
+	ReplicatorTaskMaxRetryCount
+	// ReplicatorProcessorMaxPollRPS is the max poll rate of the replicator queue processor
+	ReplicatorProcessorMaxPollRPS
+	// ReplicatorProcessorMaxPollInterval is the max poll interval of the replicator queue processor
+	ReplicatorProcessorMaxPollInterval
+	// ReplicatorProcessorUpdateAckInterval is the interval of the replicator processor to update the ack level
+	ReplicatorProcessorUpdateAckInterval
+	// ReplicatorProcessorForceUpdateInterval is the interval of the replicator processor to force an ack level update
+	ReplicatorProcessorForceUpdateInterval
+	// ExecutionMgrNumConns is the number of connections of the execution persistence in the history service
+	ExecutionMgrNumConns
+	// HistoryMgrNumConns is the number of connections of the history persistence in the history service
+	HistoryMgrNumConns
+	// ArchivalHistoryPageSize is the page size used to read the histories to archive
+	ArchivalHistoryPageSize
+	// ArchivalTimeout is how long the timer worker waits on the archival of a history before retrying it later
+	ArchivalTimeout
+	// MatchingNumTaskListWritePartitions is the number of partitions tasks are added to, the partition keys only
+	// support the task list name constraint
+	MatchingNumTaskListWritePartitions
+	// MatchingNumTaskListReadPartitions is the number of partitions polled by the pollers,
+	// it should only be lowered after the write partitions have been drained
+	MatchingNumTaskListReadPartitions
+	// MatchingPriorityStarvationThreshold is the number of consecutive tasks dispatched ahead of the oldest buffered
+	// task before the oldest task is dispatched regardless of its priority
+	MatchingPriorityStarvationThreshold
 )
 
 // Filter represents a filter on the dynamic config key
 type Filter int
 
 func (f Filter) String() string {
-	if f <= unknownFilter || f > TaskListName {
+	if f <= unknownFilter || int(f) >= len(filters) {
 		return filters[unknownFilter]
 	}
 	return filters[f]
@@ -82,6 +277,7 @@ var filters = []string{
 	"unknownFilter",
 	"domainName",
 	"taskListName",
+	"shardID",
 }
 
 const (
@@ -90,6 +286,8 @@ const (
 	DomainName
 	// TaskListName is the tasklist name
 	TaskListName
+	// ShardID is the id of a history shard
+	ShardID
 )
 
 // FilterOption is used to provide filters for dynamic config keys
@@ -108,3 +306,10 @@ func DomainFilter(name string) FilterOption {
 		filterMap[DomainName] = name
 	}
 }
+
+// ShardIDFilter filters by history shard id
+func ShardIDFilter(shardID int) FilterOption {
+	return func(filterMap map[Filter]interface{}) {
+		filterMap[ShardID] = shardID
+	}
+}
@@ -0,0 +1,357 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/uber-common/bark"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	defaultPollInterval = time.Minute
+	minPollInterval     = 5 * time.Second
+)
+
+var errKeyNotFound = errors.New("unable to find key")
+
+type (
+	// FileBasedClientConfig is the config of the dynamic config client reading the values from a YAML file
+	FileBasedClientConfig struct {
+		// Filepath is the path of the YAML file holding the values of the keys
+		Filepath string `yaml:"filepath" validate:"nonzero"`
+		// PollInterval is the interval at which the file is checked for changes, one minute by default
+		PollInterval time.Duration `yaml:"pollInterval"`
+	}
+
+	// fileBasedClient is a dynamic config client reading the values from a YAML file which maps each key to a
+	// list of values, each value with optional constraints on the filters:
+	//
+	//   matching.domain.taskList.enableSyncMatch:
+	//   - value: false
+	//     constraints:
+	//       domainName: "samples-domain"
+	//       taskListName: "samples-tasklist"
+	//   - value: true
+	//
+	// The value with the most constraints matching the filters of a lookup is returned.  The file is polled for
+	// changes, a file which fails to load is logged and the values of the last good file are kept.
+	fileBasedClient struct {
+		values      atomic.Value // map[Key][]*constrainedValue
+		lastModTime time.Time
+		config      *FileBasedClientConfig
+		logger      bark.Logger
+		doneCh      <-chan struct{}
+	}
+
+	constrainedValue struct {
+		value       interface{}
+		constraints map[Filter]interface{}
+	}
+
+	fileValue struct {
+		Value       interface{}            `yaml:"value"`
+		Constraints map[string]interface{} `yaml:"constraints"`
+	}
+)
+
+// NewFileBasedClient creates a dynamic config client reading the values from the file of the config, the file is
+// polled for changes until doneCh is closed
+func NewFileBasedClient(config *FileBasedClientConfig, logger bark.Logger, doneCh <-chan struct{}) (Client, error) {
+	if config.PollInterval == 0 {
+		config.PollInterval = defaultPollInterval
+	}
+	if config.PollInterval < minPollInterval {
+		return nil, fmt.Errorf("poll interval of the dynamic config file should be at least %v", minPollInterval)
+	}
+
+	client := &fileBasedClient{
+		config: config,
+		logger: logger,
+		doneCh: doneCh,
+	}
+	if err := client.update(); err != nil {
+		return nil, fmt.Errorf("unable to load the dynamic config file %v: %v", config.Filepath, err)
+	}
+	go client.pollFile()
+	return client, nil
+}
+
+func (fc *fileBasedClient) GetValue(name Key, defaultValue interface{}) (interface{}, error) {
+	return fc.GetValueWithFilters(name, nil, defaultValue)
+}
+
+func (fc *fileBasedClient) GetValueWithFilters(
+	name Key, filters map[Filter]interface{}, defaultValue interface{},
+) (interface{}, error) {
+	values := fc.values.Load().(map[Key][]*constrainedValue)
+
+	var match *constrainedValue
+	for _, value := range values[name] {
+		if !matchFilters(value.constraints, filters) {
+			continue
+		}
+		if match == nil || len(value.constraints) > len(match.constraints) {
+			match = value
+		}
+	}
+	if match == nil {
+		return defaultValue, errKeyNotFound
+	}
+	return match.value, nil
+}
+
+func (fc *fileBasedClient) GetIntValue(name Key, filters map[Filter]interface{}, defaultValue int) (int, error) {
+	val, err := fc.GetValueWithFilters(name, filters, defaultValue)
+	if err != nil {
+		return defaultValue, err
+	}
+	if intVal, ok := val.(int); ok {
+		return intVal, nil
+	}
+	return defaultValue, errors.New("value type is not int")
+}
+
+func (fc *fileBasedClient) GetFloatValue(name Key, filters map[Filter]interface{}, defaultValue float64) (float64, error) {
+	val, err := fc.GetValueWithFilters(name, filters, defaultValue)
+	if err != nil {
+		return defaultValue, err
+	}
+	if floatVal, ok := val.(float64); ok {
+		return floatVal, nil
+	}
+	return defaultValue, errors.New("value type is not float64")
+}
+
+func (fc *fileBasedClient) GetBoolValue(name Key, filters map[Filter]interface{}, defaultValue bool) (bool, error) {
+	val, err := fc.GetValueWithFilters(name, filters, defaultValue)
+	if err != nil {
+		return defaultValue, err
+	}
+	if boolVal, ok := val.(bool); ok {
+		return boolVal, nil
+	}
+	return defaultValue, errors.New("value type is not bool")
+}
+
+func (fc *fileBasedClient) GetStringValue(name Key, filters map[Filter]interface{}, defaultValue string) (string, error) {
+	val, err := fc.GetValueWithFilters(name, filters, defaultValue)
+	if err != nil {
+		return defaultValue, err
+	}
+	if stringVal, ok := val.(string); ok {
+		return stringVal, nil
+	}
+	return defaultValue, errors.New("value type is not string")
+}
+
+func (fc *fileBasedClient) GetMapValue(
+	name Key, filters map[Filter]interface{}, defaultValue map[string]interface{},
+) (map[string]interface{}, error) {
+	val, err := fc.GetValueWithFilters(name, filters, defaultValue)
+	if err != nil {
+		return defaultValue, err
+	}
+	if mapVal, ok := val.(map[string]interface{}); ok {
+		return mapVal, nil
+	}
+	return defaultValue, errors.New("value type is not map")
+}
+
+func (fc *fileBasedClient) GetDurationValue(
+	name Key, filters map[Filter]interface{}, defaultValue time.Duration,
+) (time.Duration, error) {
+	val, err := fc.GetValueWithFilters(name, filters, defaultValue)
+	if err != nil {
+		return defaultValue, err
+	}
+	if durationVal, ok := val.(time.Duration); ok {
+		return durationVal, nil
+	}
+	return defaultValue, errors.New("value type is not duration")
+}
+
+func (fc *fileBasedClient) pollFile() {
+	ticker := time.NewTicker(fc.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := fc.update(); err != nil {
+				fc.logger.Errorf("Failed to update dynamic config from %v, keeping the last values. Error: %v",
+					fc.config.Filepath, err)
+			}
+		case <-fc.doneCh:
+			return
+		}
+	}
+}
+
+// update reloads the file when it changed since the last load, the values are swapped at once so a lookup never
+// sees a partially loaded file
+func (fc *fileBasedClient) update() error {
+	info, err := os.Stat(fc.config.Filepath)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(fc.lastModTime) {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(fc.config.Filepath)
+	if err != nil {
+		return err
+	}
+	values, err := parseFileValues(data)
+	if err != nil {
+		return err
+	}
+
+	fc.values.Store(values)
+	fc.lastModTime = info.ModTime()
+	fc.logger.Infof("Loaded dynamic config from %v", fc.config.Filepath)
+	return nil
+}
+
+func parseFileValues(data []byte) (map[Key][]*constrainedValue, error) {
+	var fileValues map[string][]*fileValue
+	if err := yaml.Unmarshal(data, &fileValues); err != nil {
+		return nil, err
+	}
+
+	values := make(map[Key][]*constrainedValue, len(fileValues))
+	for keyName, keyValues := range fileValues {
+		key, ok := keyByName(keyName)
+		if !ok {
+			return nil, fmt.Errorf("unknown key %v", keyName)
+		}
+		for _, fileValue := range keyValues {
+			value, err := convertValue(keyTypes[key], fileValue.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of key %v: %v", keyName, err)
+			}
+			constraints := make(map[Filter]interface{}, len(fileValue.Constraints))
+			for filterName, filterValue := range fileValue.Constraints {
+				filter, ok := filterByName(filterName)
+				if !ok {
+					return nil, fmt.Errorf("unknown constraint %v of key %v", filterName, keyName)
+				}
+				switch filterValue.(type) {
+				case map[interface{}]interface{}, []interface{}:
+					return nil, fmt.Errorf("constraint %v of key %v is not a scalar", filterName, keyName)
+				}
+				constraints[filter] = filterValue
+			}
+			values[key] = append(values[key], &constrainedValue{value: value, constraints: constraints})
+		}
+	}
+	return values, nil
+}
+
+// convertValue converts a value decoded from the file to the type of its key
+func convertValue(t valueType, value interface{}) (interface{}, error) {
+	switch t {
+	case intType:
+		if intVal, ok := value.(int); ok {
+			return intVal, nil
+		}
+	case floatType:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		}
+	case boolType:
+		if boolVal, ok := value.(bool); ok {
+			return boolVal, nil
+		}
+	case stringType:
+		if stringVal, ok := value.(string); ok {
+			return stringVal, nil
+		}
+	case mapType:
+		if mapVal, ok := value.(map[interface{}]interface{}); ok {
+			return convertMap(mapVal)
+		}
+	case durationType:
+		switch v := value.(type) {
+		case string:
+			return time.ParseDuration(v)
+		case int:
+			// a bare number is a number of seconds
+			return time.Duration(v) * time.Second, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected value %v of type %T", value, value)
+}
+
+func convertMap(mapVal map[interface{}]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(mapVal))
+	for k, v := range mapVal {
+		key, ok := k.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected map key %v of type %T", k, k)
+		}
+		if nested, ok := v.(map[interface{}]interface{}); ok {
+			var err error
+			if v, err = convertMap(nested); err != nil {
+				return nil, err
+			}
+		}
+		result[key] = v
+	}
+	return result, nil
+}
+
+func matchFilters(constraints map[Filter]interface{}, filters map[Filter]interface{}) bool {
+	for filter, constraint := range constraints {
+		if value, ok := filters[filter]; !ok || value != constraint {
+			return false
+		}
+	}
+	return true
+}
+
+func keyByName(name string) (Key, bool) {
+	for i, keyName := range keys {
+		if Key(i) != unknownKey && keyName == name {
+			return Key(i), true
+		}
+	}
+	return unknownKey, false
+}
+
+func filterByName(name string) (Filter, bool) {
+	for i, filterName := range filters {
+		if Filter(i) != unknownFilter && filterName == name {
+			return Filter(i), true
+		}
+	}
+	return unknownFilter, false
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-common/bark"
+)
+
+const testConfigFile = `
+matching.domain.taskList.enableSyncMatch:
+- value: false
+  constraints:
+    domainName: "some-domain"
+- value: true
+  constraints:
+    domainName: "some-domain"
+    taskListName: "some-tasklist"
+- value: true
+matching.domain.taskList.longPollExpirationInterval:
+- value: 30s
+history.longPollExpirationInterval:
+- value: 10
+system.validSearchAttributes:
+- value:
+    CustomKeywordField: 1
+    CustomIntField: 2
+frontend.domainRPS:
+- value: 100
+  constraints:
+    domainName: "some-domain"
+history.transferTaskBatchSize:
+- value: 50
+  constraints:
+    shardID: 3
+`
+
+type fileBasedClientSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	dir      string
+	filepath string
+	doneCh   chan struct{}
+	client   *fileBasedClient
+}
+
+func TestFileBasedClientSuite(t *testing.T) {
+	s := new(fileBasedClientSuite)
+	suite.Run(t, s)
+}
+
+func (s *fileBasedClientSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	var err error
+	s.dir, err = ioutil.TempDir("", "fileBasedClientSuite")
+	s.NoError(err)
+	s.filepath = filepath.Join(s.dir, "dynamicconfig.yaml")
+	s.writeFile(testConfigFile, time.Now())
+
+	s.doneCh = make(chan struct{})
+	client, err := NewFileBasedClient(&FileBasedClientConfig{Filepath: s.filepath}, bark.NewLoggerFromLogrus(logrus.New()), s.doneCh)
+	s.NoError(err)
+	s.client = client.(*fileBasedClient)
+}
+
+func (s *fileBasedClientSuite) TearDownTest() {
+	close(s.doneCh)
+	os.RemoveAll(s.dir)
+}
+
+func (s *fileBasedClientSuite) writeFile(content string, modTime time.Time) {
+	s.NoError(ioutil.WriteFile(s.filepath, []byte(content), 0644))
+	s.NoError(os.Chtimes(s.filepath, modTime, modTime))
+}
+
+func (s *fileBasedClientSuite) TestGetValueWithConstraints() {
+	value, err := s.client.GetBoolValue(MatchingEnableSyncMatch, nil, false)
+	s.NoError(err)
+	s.True(value)
+
+	domainFilters := map[Filter]interface{}{DomainName: "some-domain"}
+	value, err = s.client.GetBoolValue(MatchingEnableSyncMatch, domainFilters, true)
+	s.NoError(err)
+	s.False(value)
+
+	taskListFilters := map[Filter]interface{}{DomainName: "some-domain", TaskListName: "some-tasklist"}
+	value, err = s.client.GetBoolValue(MatchingEnableSyncMatch, taskListFilters, false)
+	s.NoError(err)
+	s.True(value)
+
+	otherDomainFilters := map[Filter]interface{}{DomainName: "other-domain", TaskListName: "some-tasklist"}
+	value, err = s.client.GetBoolValue(MatchingEnableSyncMatch, otherDomainFilters, false)
+	s.NoError(err)
+	s.True(value)
+}
+
+func (s *fileBasedClientSuite) TestGetValueWithShardIDConstraint() {
+	batchSize, err := s.client.GetIntValue(TransferTaskBatchSize, map[Filter]interface{}{ShardID: 3}, 100)
+	s.NoError(err)
+	s.Equal(50, batchSize)
+
+	batchSize, err = s.client.GetIntValue(TransferTaskBatchSize, map[Filter]interface{}{ShardID: 4}, 100)
+	s.Error(err)
+	s.Equal(100, batchSize)
+}
+
+func (s *fileBasedClientSuite) TestGetValueDefault() {
+	rps, err := s.client.GetIntValue(FrontendDomainRPS, map[Filter]interface{}{DomainName: "other-domain"}, 1200)
+	s.Error(err)
+	s.Equal(1200, rps)
+
+	batchSize, err := s.client.GetIntValue(MatchingMaxTaskBatchSize, nil, 100)
+	s.Error(err)
+	s.Equal(100, batchSize)
+}
+
+func (s *fileBasedClientSuite) TestGetValueTypes() {
+	rps, err := s.client.GetIntValue(FrontendDomainRPS, map[Filter]interface{}{DomainName: "some-domain"}, 1200)
+	s.NoError(err)
+	s.Equal(100, rps)
+
+	interval, err := s.client.GetDurationValue(MatchingLongPollExpirationInterval, nil, time.Minute)
+	s.NoError(err)
+	s.Equal(30*time.Second, interval)
+
+	interval, err = s.client.GetDurationValue(HistoryLongPollExpirationInterval, nil, time.Minute)
+	s.NoError(err)
+	s.Equal(10*time.Second, interval)
+
+	attributes, err := s.client.GetMapValue(ValidSearchAttributes, nil, nil)
+	s.NoError(err)
+	s.Equal(map[string]interface{}{"CustomKeywordField": 1, "CustomIntField": 2}, attributes)
+
+	// a lookup with the wrong type returns the default value
+	encoding, err := s.client.GetStringValue(MatchingEnableSyncMatch, nil, "json")
+	s.Error(err)
+	s.Equal("json", encoding)
+}
+
+func (s *fileBasedClientSuite) TestUpdate() {
+	s.writeFile(`
+frontend.domainRPS:
+- value: 50
+`, time.Now().Add(time.Minute))
+	s.NoError(s.client.update())
+
+	rps, err := s.client.GetIntValue(FrontendDomainRPS, map[Filter]interface{}{DomainName: "some-domain"}, 1200)
+	s.NoError(err)
+	s.Equal(50, rps)
+	_, err = s.client.GetBoolValue(MatchingEnableSyncMatch, nil, false)
+	s.Error(err)
+}
+
+func (s *fileBasedClientSuite) TestUpdateFailureKeepsLastValues() {
+	invalidFiles := []string{
+		"frontend.domainRPS: [",
+		"unknown.key:\n- value: 1\n",
+		"frontend.domainRPS:\n- value: not-a-number\n",
+		"frontend.domainRPS:\n- value: 1\n  constraints:\n    unknownFilter: 1\n",
+		"matching.domain.taskList.longPollExpirationInterval:\n- value: not-a-duration\n",
+	}
+	for i, content := range invalidFiles {
+		s.writeFile(content, time.Now().Add(time.Duration(i+1)*time.Minute))
+		s.Error(s.client.update(), content)
+
+		rps, err := s.client.GetIntValue(FrontendDomainRPS, map[Filter]interface{}{DomainName: "some-domain"}, 1200)
+		s.NoError(err)
+		s.Equal(100, rps)
+	}
+}
+
+func (s *fileBasedClientSuite) TestNewFileBasedClientFailure() {
+	logger := bark.NewLoggerFromLogrus(logrus.New())
+	_, err := NewFileBasedClient(&FileBasedClientConfig{Filepath: filepath.Join(s.dir, "missing.yaml")}, logger, s.doneCh)
+	s.Error(err)
+
+	_, err = NewFileBasedClient(&FileBasedClientConfig{Filepath: s.filepath, PollInterval: time.Second}, logger, s.doneCh)
+	s.Error(err)
+}
+
+func (s *fileBasedClientSuite) TestKeyTypes() {
+	for i := range keys {
+		if Key(i) == unknownKey {
+			continue
+		}
+		_, ok := keyTypes[Key(i)]
+		s.True(ok, keys[i])
+	}
+}
@@ -27,11 +27,14 @@ import (
 
 	"github.com/uber/cadence/client"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/archiver"
+	"github.com/uber/cadence/common/authorization"
 	"github.com/uber/cadence/common/cluster"
 	"github.com/uber/cadence/common/logging"
 	"github.com/uber/cadence/common/membership"
 	"github.com/uber/cadence/common/messaging"
 	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/persistence"
 	"github.com/uber/cadence/common/service/config"
 	"github.com/uber/cadence/common/service/dynamicconfig"
 
@@ -59,10 +62,20 @@ type (
 		RPCFactory       common.RPCFactory
 		PProfInitializer common.PProfInitializer
 		CassandraConfig  config.Cassandra
+		SQLConfig        *config.SQL
+		InMemoryStore    *persistence.InMemoryStore
+		// BoltVisibilityStore takes precedence over the visibility store of the persistence when set
+		BoltVisibilityStore *persistence.BoltVisibilityStore
+		// Archiver is only set when archival is configured on the cluster
+		Archiver         archiver.Archiver
 		ClusterMetadata  cluster.Metadata
 		ReplicatorConfig config.Replicator
 		MessagingClient  messaging.Client
 		DynamicConfig    dynamicconfig.Client
+		// Authorizer authorizes the calls received by the frontend
+		Authorizer authorization.Authorizer
+		// IdentityProvider authenticates the callers of the frontend
+		IdentityProvider authorization.IdentityProvider
 	}
 
 	// RingpopFactory provides a bootstrapped ringpop
@@ -173,7 +186,7 @@ func (h *serviceImpl) Start() {
 	h.hostInfo = hostInfo
 
 	h.clientFactory = client.NewRPCClientFactory(h.rpcFactory, h.membershipMonitor, h.metricsClient,
-		h.numberOfHistoryShards)
+		h.numberOfHistoryShards, h.dynamicCollection)
 
 	// The service is now started up
 	h.logger.Info("service started")
@@ -0,0 +1,69 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ReservedTaskListPrefix is the prefix of the task list names reserved for the internal use of cadence,
+// user task lists are not allowed to start with it
+const ReservedTaskListPrefix = "/__cadence_sys/"
+
+// TaskListPartitionName returns the name of the given partition of a task list, the root partition
+// keeps the name of the task list
+func TaskListPartitionName(taskListName string, partition int) string {
+	if partition <= 0 {
+		return taskListName
+	}
+	return fmt.Sprintf("%v%v/%v", ReservedTaskListPrefix, taskListName, partition)
+}
+
+// ParseTaskListPartitionName returns the name of the task list and the partition of a task list partition name,
+// any name which is not a valid partition name is returned as the root partition of a task list
+func ParseTaskListPartitionName(name string) (string, int) {
+	if !strings.HasPrefix(name, ReservedTaskListPrefix) {
+		return name, 0
+	}
+	suffixOff := strings.LastIndex(name, "/")
+	if suffixOff <= len(ReservedTaskListPrefix) {
+		return name, 0
+	}
+	partition, err := strconv.Atoi(name[suffixOff+1:])
+	if err != nil || partition <= 0 {
+		return name, 0
+	}
+	return name[len(ReservedTaskListPrefix):suffixOff], partition
+}
+
+// TaskListBuildIDName returns the name of the task list which dispatches the decision tasks of the workflows pinned
+// to a build ID of a task list, build IDs can not contain a "/" so the name is never parsed as a partition name
+func TaskListBuildIDName(taskListName string, buildID string) string {
+	return fmt.Sprintf("%v%v/build:%v", ReservedTaskListPrefix, taskListName, buildID)
+}
+
+// TaskListPriorityName returns the name of the task list which persists the tasks of the given priority level of a
+// task list, the name is never parsed as a partition name
+func TaskListPriorityName(taskListName string, level int) string {
+	return fmt.Sprintf("%v%v/priority:%v", ReservedTaskListPrefix, taskListName, level)
+}
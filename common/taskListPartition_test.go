@@ -0,0 +1,81 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	taskListPartitionSuite struct {
+		*require.Assertions
+		suite.Suite
+	}
+)
+
+func TestTaskListPartitionSuite(t *testing.T) {
+	suite.Run(t, new(taskListPartitionSuite))
+}
+
+func (s *taskListPartitionSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *taskListPartitionSuite) TestPartitionName() {
+	s.Equal("tl", TaskListPartitionName("tl", 0))
+	s.Equal("/__cadence_sys/tl/1", TaskListPartitionName("tl", 1))
+	s.Equal("/__cadence_sys/a/b/12", TaskListPartitionName("a/b", 12))
+}
+
+func (s *taskListPartitionSuite) TestParsePartitionName() {
+	for _, name := range []string{"tl", "a/b", "/tl/3"} {
+		for _, partition := range []int{0, 1, 7} {
+			parsedName, parsedPartition := ParseTaskListPartitionName(TaskListPartitionName(name, partition))
+			s.Equal(name, parsedName)
+			s.Equal(partition, parsedPartition)
+		}
+	}
+
+	for _, name := range []string{"/__cadence_sys/", "/__cadence_sys/tl", "/__cadence_sys/tl/x", "/__cadence_sys/tl/-1", "/__cadence_sys//1"} {
+		parsedName, parsedPartition := ParseTaskListPartitionName(name)
+		s.Equal(name, parsedName)
+		s.Equal(0, parsedPartition)
+	}
+}
+
+func (s *taskListPartitionSuite) TestBuildIDName() {
+	name := TaskListBuildIDName("tl", "12")
+	s.Equal("/__cadence_sys/tl/build:12", name)
+	parsedName, parsedPartition := ParseTaskListPartitionName(name)
+	s.Equal(name, parsedName)
+	s.Equal(0, parsedPartition)
+}
+
+func (s *taskListPartitionSuite) TestPriorityName() {
+	name := TaskListPriorityName("tl", 2)
+	s.Equal("/__cadence_sys/tl/priority:2", name)
+	parsedName, parsedPartition := ParseTaskListPartitionName(name)
+	s.Equal(name, parsedName)
+	s.Equal(0, parsedPartition)
+}
@@ -0,0 +1,489 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package visibilityquery
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+)
+
+type (
+	tokenKind int
+
+	token struct {
+		kind tokenKind
+		text string
+		pos  int
+	}
+
+	parser struct {
+		tokens []token
+		next   int
+	}
+)
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+)
+
+// fields maps the lower case name of every field to the field
+var fields = map[string]Field{
+	strings.ToLower(string(FieldWorkflowID)):    FieldWorkflowID,
+	strings.ToLower(string(FieldWorkflowType)):  FieldWorkflowType,
+	strings.ToLower(string(FieldCloseStatus)):   FieldCloseStatus,
+	strings.ToLower(string(FieldStartTime)):     FieldStartTime,
+	strings.ToLower(string(FieldCloseTime)):     FieldCloseTime,
+	strings.ToLower(string(FieldHistoryLength)): FieldHistoryLength,
+}
+
+var operators = map[string]Operator{
+	"=":  OperatorEqual,
+	"!=": OperatorNotEqual,
+	"<>": OperatorNotEqual,
+	"<":  OperatorLessThan,
+	"<=": OperatorLessThanOrEqual,
+	">":  OperatorGreaterThan,
+	">=": OperatorGreaterThanOrEqual,
+}
+
+var defaultOrderBy = []OrderByField{{Field: FieldStartTime, Desc: true}}
+
+// Parse parses a query such as "WorkflowType = 'order' AND (CloseStatus = 'FAILED' OR CloseTime IS NULL)
+// ORDER BY StartTime DESC".  Filters are comparisons of a field with a string or integer value, BETWEEN,
+// IS [NOT] NULL and prefix matches on WorkflowID such as "WorkflowID LIKE 'order-%'", combined with AND, OR and
+// parentheses.  Times are either unix nanoseconds or RFC3339 strings.
+// A BadRequestError is returned if the query is not valid or uses a clause which is not supported.
+func Parse(query string) (*Query, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, newBadRequestError(err)
+	}
+
+	p := &parser{tokens: tokens}
+	result, err := p.parseQuery()
+	if err != nil {
+		return nil, newBadRequestError(err)
+	}
+	return result, nil
+}
+
+func newBadRequestError(err error) error {
+	return &workflow.BadRequestError{Message: fmt.Sprintf("Invalid query: %v", err)}
+}
+
+func tokenize(query string) ([]token, error) {
+	var tokens []token
+	runes := []rune(query)
+	for pos := 0; pos < len(runes); {
+		r := runes[pos]
+		switch {
+		case unicode.IsSpace(r):
+			pos++
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "(", pos: pos})
+			pos++
+
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")", pos: pos})
+			pos++
+
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: pos})
+			pos++
+
+		case r == '\'' || r == '"':
+			// Quotes are escaped by doubling them
+			var text []rune
+			start := pos
+			pos++
+			for {
+				if pos >= len(runes) {
+					return nil, fmt.Errorf("unterminated string at position %v", start)
+				}
+				if runes[pos] == r {
+					if pos+1 < len(runes) && runes[pos+1] == r {
+						text = append(text, r)
+						pos += 2
+						continue
+					}
+					pos++
+					break
+				}
+				text = append(text, runes[pos])
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokenString, text: string(text), pos: start})
+
+		case r == '-' || unicode.IsDigit(r):
+			start := pos
+			pos++
+			for pos < len(runes) && unicode.IsDigit(runes[pos]) {
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:pos]), pos: start})
+
+		case strings.ContainsRune("=!<>", r):
+			start := pos
+			pos++
+			if pos < len(runes) && strings.ContainsRune("=>", runes[pos]) {
+				pos++
+			}
+			text := string(runes[start:pos])
+			if _, ok := operators[text]; !ok {
+				return nil, fmt.Errorf("unknown operator %v at position %v", text, start)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: text, pos: start})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := pos
+			for pos < len(runes) && (unicode.IsLetter(runes[pos]) || unicode.IsDigit(runes[pos]) || runes[pos] == '_') {
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: string(runes[start:pos]), pos: start})
+
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %v", r, pos)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) consume() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+// isKeyword returns true if the next token is the given keyword, keywords are case insensitive
+func (p *parser) isKeyword(keyword string) bool {
+	t := p.peek()
+	return t.kind == tokenIdentifier && strings.EqualFold(t.text, keyword)
+}
+
+func (p *parser) expectKeyword(keyword string) error {
+	if !p.isKeyword(keyword) {
+		return unexpectedTokenError(p.peek(), keyword)
+	}
+	p.consume()
+	return nil
+}
+
+func (p *parser) parseQuery() (*Query, error) {
+	query := &Query{OrderBy: defaultOrderBy}
+	if p.peek().kind != tokenEOF && !p.isKeyword("ORDER") {
+		filter, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		query.Filter = filter
+	}
+
+	if p.isKeyword("ORDER") {
+		orderBy, err := p.parseOrderBy()
+		if err != nil {
+			return nil, err
+		}
+		query.OrderBy = orderBy
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, unexpectedTokenError(t, "end of query")
+	}
+	return query, nil
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("OR") {
+		p.consume()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &OrExpression{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("AND") {
+		p.consume()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		left = &AndExpression{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parsePrimary() (Expression, error) {
+	if p.peek().kind == tokenLeftParen {
+		p.consume()
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.consume(); t.kind != tokenRightParen {
+			return nil, unexpectedTokenError(t, ")")
+		}
+		return expression, nil
+	}
+	return p.parsePredicate()
+}
+
+func (p *parser) parsePredicate() (Expression, error) {
+	field, err := p.parseField()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case p.isKeyword("IS"):
+		p.consume()
+		not := false
+		if p.isKeyword("NOT") {
+			p.consume()
+			not = true
+		}
+		if err := p.expectKeyword("NULL"); err != nil {
+			return nil, err
+		}
+		if !isNullable(field) {
+			return nil, fmt.Errorf("IS NULL is not supported on %v, it is only supported on %v, %v and %v",
+				field, FieldCloseStatus, FieldCloseTime, FieldHistoryLength)
+		}
+		return &NullExpression{Field: field, Not: not}, nil
+
+	case p.isKeyword("BETWEEN"):
+		p.consume()
+		if !isOrdered(field) {
+			return nil, fmt.Errorf("BETWEEN is not supported on %v", field)
+		}
+		lower, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("AND"); err != nil {
+			return nil, err
+		}
+		upper, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+		return &AndExpression{
+			Left:  &ComparisonExpression{Field: field, Operator: OperatorGreaterThanOrEqual, Value: lower},
+			Right: &ComparisonExpression{Field: field, Operator: OperatorLessThanOrEqual, Value: upper},
+		}, nil
+
+	case p.isKeyword("LIKE"):
+		p.consume()
+		if field != FieldWorkflowID {
+			return nil, fmt.Errorf("LIKE is not supported on %v, it is only supported on %v", field, FieldWorkflowID)
+		}
+		prefix, err := p.parsePrefixPattern()
+		if err != nil {
+			return nil, err
+		}
+		return &PrefixExpression{Field: field, Prefix: prefix}, nil
+
+	case p.peek().kind == tokenOperator:
+		operator := operators[p.consume().text]
+		if operator != OperatorEqual && operator != OperatorNotEqual && !isOrdered(field) {
+			return nil, fmt.Errorf("operator %v is not supported on %v, only = and != are", operator, field)
+		}
+		value, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+		return &ComparisonExpression{Field: field, Operator: operator, Value: value}, nil
+
+	default:
+		return nil, unexpectedTokenError(p.peek(), "operator, BETWEEN, IS or LIKE")
+	}
+}
+
+func (p *parser) parseField() (Field, error) {
+	t := p.consume()
+	if t.kind != tokenIdentifier {
+		return "", unexpectedTokenError(t, "field")
+	}
+	field, ok := fields[strings.ToLower(t.text)]
+	if !ok {
+		return "", fmt.Errorf("unknown field %v at position %v, supported fields are %v, %v, %v, %v, %v and %v",
+			t.text, t.pos, FieldWorkflowID, FieldWorkflowType, FieldCloseStatus, FieldStartTime, FieldCloseTime,
+			FieldHistoryLength)
+	}
+	return field, nil
+}
+
+// parseValue returns the value converted to the type of the field
+func (p *parser) parseValue(field Field) (interface{}, error) {
+	t := p.consume()
+	if t.kind != tokenString && t.kind != tokenNumber {
+		return nil, unexpectedTokenError(t, "value")
+	}
+
+	var value interface{}
+	var err error
+	switch field {
+	case FieldWorkflowID, FieldWorkflowType:
+		if t.kind != tokenString {
+			err = errors.New("expected a string")
+		}
+		value = t.text
+	case FieldCloseStatus:
+		value, err = parseCloseStatus(t)
+	case FieldStartTime, FieldCloseTime:
+		value, err = parseTime(t)
+	case FieldHistoryLength:
+		if t.kind != tokenNumber {
+			err = errors.New("expected an integer")
+		} else {
+			value, err = strconv.ParseInt(t.text, 10, 64)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %v for %v at position %v: %v", t.text, field, t.pos, err)
+	}
+	return value, nil
+}
+
+// parsePrefixPattern returns the prefix of a LIKE pattern.  Only prefix patterns such as 'order-%' are supported,
+// the characters before the trailing % are matched literally.
+func (p *parser) parsePrefixPattern() (string, error) {
+	t := p.consume()
+	if t.kind != tokenString {
+		return "", unexpectedTokenError(t, "pattern")
+	}
+	prefix := strings.TrimSuffix(t.text, "%")
+	if prefix == t.text || strings.Contains(prefix, "%") {
+		return "", fmt.Errorf("invalid pattern %v at position %v: only prefix patterns ending with %% are supported",
+			t.text, t.pos)
+	}
+	return prefix, nil
+}
+
+func parseCloseStatus(t token) (int64, error) {
+	var status workflow.WorkflowExecutionCloseStatus
+	if t.kind == tokenNumber {
+		value, err := strconv.ParseInt(t.text, 10, 32)
+		if err != nil {
+			return 0, err
+		}
+		status = workflow.WorkflowExecutionCloseStatus(value)
+	} else if err := status.UnmarshalText([]byte(strings.ToUpper(t.text))); err != nil {
+		return 0, err
+	}
+
+	for _, validStatus := range workflow.WorkflowExecutionCloseStatus_Values() {
+		if status == validStatus {
+			return int64(status), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown close status %v", t.text)
+}
+
+func parseTime(t token) (int64, error) {
+	if t.kind == tokenNumber {
+		return strconv.ParseInt(t.text, 10, 64)
+	}
+	value, err := time.Parse(time.RFC3339Nano, t.text)
+	if err != nil {
+		return 0, err
+	}
+	return value.UnixNano(), nil
+}
+
+func (p *parser) parseOrderBy() ([]OrderByField, error) {
+	p.consume()
+	if err := p.expectKeyword("BY"); err != nil {
+		return nil, err
+	}
+
+	var orderBy []OrderByField
+	for {
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		if field == FieldCloseStatus {
+			return nil, fmt.Errorf("ORDER BY is not supported on %v", field)
+		}
+
+		desc := false
+		if p.isKeyword("DESC") {
+			p.consume()
+			desc = true
+		} else if p.isKeyword("ASC") {
+			p.consume()
+		}
+		orderBy = append(orderBy, OrderByField{Field: field, Desc: desc})
+
+		if p.peek().kind != tokenComma {
+			return orderBy, nil
+		}
+		p.consume()
+	}
+}
+
+func isNullable(field Field) bool {
+	return field == FieldCloseStatus || field == FieldCloseTime || field == FieldHistoryLength
+}
+
+// isOrdered returns true if range comparisons are supported on the field
+func isOrdered(field Field) bool {
+	return field == FieldStartTime || field == FieldCloseTime || field == FieldHistoryLength
+}
+
+func unexpectedTokenError(t token, expected string) error {
+	if t.kind == tokenEOF {
+		return fmt.Errorf("expected %v at end of query", expected)
+	}
+	return fmt.Errorf("expected %v at position %v, found %v", expected, t.pos, t.text)
+}
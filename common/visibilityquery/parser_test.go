@@ -0,0 +1,173 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package visibilityquery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	workflow "github.com/uber/cadence/.gen/go/shared"
+)
+
+type (
+	parserSuite struct {
+		suite.Suite
+		*require.Assertions
+	}
+)
+
+func TestParserSuite(t *testing.T) {
+	suite.Run(t, new(parserSuite))
+}
+
+func (s *parserSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *parserSuite) TestParseEmptyQuery() {
+	query, err := Parse("  ")
+	s.Nil(err)
+	s.Nil(query.Filter)
+	s.Equal([]OrderByField{{Field: FieldStartTime, Desc: true}}, query.OrderBy)
+}
+
+func (s *parserSuite) TestParseFilter() {
+	query, err := Parse(`workflowtype = 'order' and (CloseStatus = 'failed' OR closeTime IS NULL)`)
+	s.Nil(err)
+	s.Equal(&AndExpression{
+		Left: &ComparisonExpression{Field: FieldWorkflowType, Operator: OperatorEqual, Value: "order"},
+		Right: &OrExpression{
+			Left: &ComparisonExpression{Field: FieldCloseStatus, Operator: OperatorEqual,
+				Value: int64(workflow.WorkflowExecutionCloseStatusFailed)},
+			Right: &NullExpression{Field: FieldCloseTime},
+		},
+	}, query.Filter)
+
+	// AND binds tighter than OR
+	query, err = Parse(`WorkflowID = "a" OR WorkflowID = 'b''s' AND HistoryLength >= 10`)
+	s.Nil(err)
+	s.Equal(&OrExpression{
+		Left: &ComparisonExpression{Field: FieldWorkflowID, Operator: OperatorEqual, Value: "a"},
+		Right: &AndExpression{
+			Left:  &ComparisonExpression{Field: FieldWorkflowID, Operator: OperatorEqual, Value: "b's"},
+			Right: &ComparisonExpression{Field: FieldHistoryLength, Operator: OperatorGreaterThanOrEqual, Value: int64(10)},
+		},
+	}, query.Filter)
+}
+
+func (s *parserSuite) TestParsePrefix() {
+	query, err := Parse(`WorkflowID LIKE 'order_1%' AND WorkflowType = 'order'`)
+	s.Nil(err)
+	s.Equal(&AndExpression{
+		Left:  &PrefixExpression{Field: FieldWorkflowID, Prefix: "order_1"},
+		Right: &ComparisonExpression{Field: FieldWorkflowType, Operator: OperatorEqual, Value: "order"},
+	}, query.Filter)
+}
+
+func (s *parserSuite) TestParseTimes() {
+	startTime := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	query, err := Parse(`StartTime BETWEEN '2018-10-01T00:00:00Z' AND 1538352000000000001`)
+	s.Nil(err)
+	s.Equal(&AndExpression{
+		Left: &ComparisonExpression{Field: FieldStartTime, Operator: OperatorGreaterThanOrEqual,
+			Value: startTime.UnixNano()},
+		Right: &ComparisonExpression{Field: FieldStartTime, Operator: OperatorLessThanOrEqual,
+			Value: startTime.UnixNano() + 1},
+	}, query.Filter)
+}
+
+func (s *parserSuite) TestParseOrderBy() {
+	query, err := Parse(`CloseTime IS NOT NULL ORDER BY CloseTime DESC, workflowid`)
+	s.Nil(err)
+	s.Equal(&NullExpression{Field: FieldCloseTime, Not: true}, query.Filter)
+	s.Equal([]OrderByField{{Field: FieldCloseTime, Desc: true}, {Field: FieldWorkflowID}}, query.OrderBy)
+
+	query, err = Parse(`order by HistoryLength asc`)
+	s.Nil(err)
+	s.Nil(query.Filter)
+	s.Equal([]OrderByField{{Field: FieldHistoryLength}}, query.OrderBy)
+}
+
+func (s *parserSuite) TestParseInvalidQuery() {
+	for _, query := range []string{
+		`WorkflowType = 'order`,
+		`WorkflowType == 'order'`,
+		`WorkflowType = order`,
+		`WorkflowType > 'order'`,
+		`RunID = 'run'`,
+		`WorkflowID = 1`,
+		`CloseStatus = 'UNKNOWN'`,
+		`CloseStatus = 10`,
+		`StartTime > 'yesterday'`,
+		`HistoryLength = '10'`,
+		`WorkflowID IS NULL`,
+		`WorkflowType BETWEEN 'a' AND 'b'`,
+		`WorkflowType LIKE 'order%'`,
+		`WorkflowID LIKE 'order'`,
+		`WorkflowID LIKE '%order%'`,
+		`WorkflowID LIKE 10`,
+		`(WorkflowID = 'a'`,
+		`WorkflowID = 'a' AND`,
+		`WorkflowID = 'a' LIMIT 10`,
+		`ORDER BY CloseStatus`,
+		`ORDER StartTime`,
+	} {
+		_, err := Parse(query)
+		s.IsType(&workflow.BadRequestError{}, err, query)
+	}
+}
+
+func (s *parserSuite) TestMatch() {
+	openRecord := &Record{WorkflowID: "wid", RunID: "run1", WorkflowType: "order", StartTime: 10}
+	closedRecord := &Record{WorkflowID: "wid", RunID: "run2", WorkflowType: "order", StartTime: 5, Closed: true,
+		CloseTime: 20, CloseStatus: int64(workflow.WorkflowExecutionCloseStatusFailed), HistoryLength: 7}
+
+	query, err := Parse(`WorkflowType = 'order' AND CloseStatus != 'COMPLETED'`)
+	s.Nil(err)
+	s.False(query.Matches(openRecord))
+	s.True(query.Matches(closedRecord))
+
+	query, err = Parse(`CloseTime IS NULL OR HistoryLength < 5`)
+	s.Nil(err)
+	s.True(query.Matches(openRecord))
+	s.False(query.Matches(closedRecord))
+
+	query, err = Parse(`WorkflowID LIKE 'wi%' AND CloseTime IS NULL`)
+	s.Nil(err)
+	s.True(query.Matches(openRecord))
+	s.False(query.Matches(closedRecord))
+
+	query, err = Parse(`WorkflowID LIKE 'wid-%'`)
+	s.Nil(err)
+	s.False(query.Matches(openRecord))
+
+	query, err = Parse(`ORDER BY CloseTime DESC`)
+	s.Nil(err)
+	s.True(query.Matches(openRecord))
+	s.True(query.Less(closedRecord, openRecord))
+	s.False(query.Less(openRecord, closedRecord))
+
+	query, err = Parse(``)
+	s.Nil(err)
+	s.True(query.Less(openRecord, closedRecord))
+}
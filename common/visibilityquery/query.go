@@ -0,0 +1,251 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package visibilityquery
+
+import (
+	"strings"
+)
+
+type (
+	// Field is a column of the visibility records which queries can filter and sort on
+	Field string
+
+	// Operator is an operator comparing a field with a value
+	Operator string
+
+	// Query is a parsed and validated visibility query
+	Query struct {
+		// Filter is nil when the query matches every execution of the domain
+		Filter Expression
+		// OrderBy is never empty, queries without ORDER BY are sorted by StartTime DESC
+		OrderBy []OrderByField
+	}
+
+	// OrderByField is one of the sort keys of a query
+	OrderByField struct {
+		Field Field
+		Desc  bool
+	}
+
+	// Expression is a node of the filter of a query
+	Expression interface {
+		// Match returns true if the record satisfies the expression
+		Match(record *Record) bool
+	}
+
+	// AndExpression matches the records which match both sides
+	AndExpression struct {
+		Left  Expression
+		Right Expression
+	}
+
+	// OrExpression matches the records which match either side
+	OrExpression struct {
+		Left  Expression
+		Right Expression
+	}
+
+	// ComparisonExpression compares a field with a value.  The value is a string for WorkflowID and WorkflowType
+	// and an int64 for every other field: times are in unix nanoseconds and close statuses are
+	// WorkflowExecutionCloseStatus values.  Like in SQL, a comparison on a field which is not set is false.
+	ComparisonExpression struct {
+		Field    Field
+		Operator Operator
+		Value    interface{}
+	}
+
+	// PrefixExpression matches the records on which the string field starts with the prefix
+	PrefixExpression struct {
+		Field  Field
+		Prefix string
+	}
+
+	// NullExpression matches the records on which the field is not set, or set if Not is true.  Only the close
+	// fields are not set, on open executions.
+	NullExpression struct {
+		Field Field
+		Not   bool
+	}
+
+	// Record is the visibility record of an execution which expressions are matched against
+	Record struct {
+		WorkflowID    string
+		RunID         string
+		WorkflowType  string
+		StartTime     int64
+		Closed        bool
+		CloseTime     int64
+		CloseStatus   int64
+		HistoryLength int64
+	}
+)
+
+// Fields which queries can filter on
+const (
+	FieldWorkflowID    Field = "WorkflowID"
+	FieldWorkflowType  Field = "WorkflowType"
+	FieldCloseStatus   Field = "CloseStatus"
+	FieldStartTime     Field = "StartTime"
+	FieldCloseTime     Field = "CloseTime"
+	FieldHistoryLength Field = "HistoryLength"
+)
+
+// Operators which queries can compare fields with
+const (
+	OperatorEqual              Operator = "="
+	OperatorNotEqual           Operator = "!="
+	OperatorLessThan           Operator = "<"
+	OperatorLessThanOrEqual    Operator = "<="
+	OperatorGreaterThan        Operator = ">"
+	OperatorGreaterThanOrEqual Operator = ">="
+)
+
+// Match returns true if the record matches both sides
+func (e *AndExpression) Match(record *Record) bool {
+	return e.Left.Match(record) && e.Right.Match(record)
+}
+
+// Match returns true if the record matches either side
+func (e *OrExpression) Match(record *Record) bool {
+	return e.Left.Match(record) || e.Right.Match(record)
+}
+
+// Match returns true if the field of the record is set and compares with the value as the operator requires
+func (e *ComparisonExpression) Match(record *Record) bool {
+	value, ok := record.value(e.Field)
+	if !ok {
+		return false
+	}
+
+	var result int
+	switch v := value.(type) {
+	case string:
+		result = strings.Compare(v, e.Value.(string))
+	case int64:
+		result = compareInt64(v, e.Value.(int64))
+	}
+
+	switch e.Operator {
+	case OperatorEqual:
+		return result == 0
+	case OperatorNotEqual:
+		return result != 0
+	case OperatorLessThan:
+		return result < 0
+	case OperatorLessThanOrEqual:
+		return result <= 0
+	case OperatorGreaterThan:
+		return result > 0
+	case OperatorGreaterThanOrEqual:
+		return result >= 0
+	default:
+		return false
+	}
+}
+
+// Match returns true if the field of the record starts with the prefix
+func (e *PrefixExpression) Match(record *Record) bool {
+	value, ok := record.value(e.Field)
+	if !ok {
+		return false
+	}
+	stringValue, ok := value.(string)
+	return ok && strings.HasPrefix(stringValue, e.Prefix)
+}
+
+// Match returns true if the field of the record is not set, or set if Not is true
+func (e *NullExpression) Match(record *Record) bool {
+	_, ok := record.value(e.Field)
+	return ok == e.Not
+}
+
+// Matches returns true if the record matches the filter of the query
+func (q *Query) Matches(record *Record) bool {
+	return q.Filter == nil || q.Filter.Match(record)
+}
+
+// Less returns true if the first record sorts before the second one.  Records are sorted on the ORDER BY fields
+// and then on run ID, so the order is deterministic.  Fields which are not set sort before any value.
+func (q *Query) Less(first, second *Record) bool {
+	for _, orderBy := range q.OrderBy {
+		result := compareField(first, second, orderBy.Field)
+		if result == 0 {
+			continue
+		}
+		if orderBy.Desc {
+			return result > 0
+		}
+		return result < 0
+	}
+	return first.RunID < second.RunID
+}
+
+func (r *Record) value(field Field) (interface{}, bool) {
+	switch field {
+	case FieldWorkflowID:
+		return r.WorkflowID, true
+	case FieldWorkflowType:
+		return r.WorkflowType, true
+	case FieldStartTime:
+		return r.StartTime, true
+	case FieldCloseStatus:
+		return r.CloseStatus, r.Closed
+	case FieldCloseTime:
+		return r.CloseTime, r.Closed
+	case FieldHistoryLength:
+		return r.HistoryLength, r.Closed
+	default:
+		return nil, false
+	}
+}
+
+func compareField(first, second *Record, field Field) int {
+	firstValue, firstOk := first.value(field)
+	secondValue, secondOk := second.value(field)
+	switch {
+	case !firstOk && !secondOk:
+		return 0
+	case !firstOk:
+		return -1
+	case !secondOk:
+		return 1
+	}
+
+	switch v := firstValue.(type) {
+	case string:
+		return strings.Compare(v, secondValue.(string))
+	case int64:
+		return compareInt64(v, secondValue.(int64))
+	default:
+		return 0
+	}
+}
+
+func compareInt64(first, second int64) int {
+	switch {
+	case first < second:
+		return -1
+	case first > second:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -52,6 +52,7 @@ import (
 
 var (
 	integration = flag.Bool("integration", true, "run integration tests")
+	inMemory    = flag.Bool("inMemory", false, "run integration tests against the in-memory persistence")
 )
 
 const (
@@ -136,7 +137,9 @@ func (s *integrationSuite) TearDownTest() {
 func (s *integrationSuite) setupTest(enableGlobalDomain bool, isMasterCluster bool) {
 	// Have to define our overridden assertions in the test setup. If we did it earlier, s.T() will return nil
 	s.Assertions = require.New(s.T())
+	s.InMemory = *inMemory
 	options := persistence.TestBaseOptions{}
+	options.InMemory = s.InMemory
 	options.ClusterHost = "127.0.0.1"
 	options.DropKeySpace = true
 	options.SchemaDir = ".."
@@ -0,0 +1,80 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+include "shared.thrift"
+
+namespace java com.uber.cadence.admin
+
+/**
+* AdminService provides the operator APIs which manage how the tasks of a domain are dispatched.
+**/
+service AdminService {
+  /**
+  * UpdateTaskListBuildIDs adds a build ID to the decision task list, either as compatible with an existing build ID or
+  * as the new default build ID, or retires it. Workflows are pinned to the build ID of the worker which completed
+  * their first decision and their decision tasks are only dispatched to pollers with a compatible build ID.
+  **/
+  void UpdateTaskListBuildIDs(1: shared.UpdateTaskListBuildIDsRequest request)
+    throws (
+      1: shared.BadRequestError badRequestError,
+      2: shared.InternalServiceError internalServiceError,
+      3: shared.EntityNotExistsError entityNotExistError,
+      4: shared.ServiceBusyError serviceBusyError,
+      5: shared.AccessDeniedError accessDeniedError,
+    )
+
+  /**
+  * PauseTaskList stops dispatching the tasks of a task list to pollers, the tasks added while the task list is
+  * paused are persisted and dispatched once it is resumed.
+  **/
+  void PauseTaskList(1: shared.PauseTaskListRequest request)
+    throws (
+      1: shared.BadRequestError badRequestError,
+      2: shared.InternalServiceError internalServiceError,
+      3: shared.EntityNotExistsError entityNotExistError,
+      4: shared.ServiceBusyError serviceBusyError,
+      5: shared.AccessDeniedError accessDeniedError,
+    )
+
+  /**
+  * ResumeTaskList resumes dispatching the tasks of a paused task list.
+  **/
+  void ResumeTaskList(1: shared.ResumeTaskListRequest request)
+    throws (
+      1: shared.BadRequestError badRequestError,
+      2: shared.InternalServiceError internalServiceError,
+      3: shared.EntityNotExistsError entityNotExistError,
+      4: shared.ServiceBusyError serviceBusyError,
+      5: shared.AccessDeniedError accessDeniedError,
+    )
+
+  /**
+  * ListTaskLists returns a page of the task lists of a domain with their ack level and the time they were last
+  * updated, the task lists which are internally used by a task list, like its partitions, are not listed.
+  **/
+  shared.ListTaskListsResponse ListTaskLists(1: shared.ListTaskListsRequest request)
+    throws (
+      1: shared.BadRequestError badRequestError,
+      2: shared.InternalServiceError internalServiceError,
+      3: shared.EntityNotExistsError entityNotExistError,
+      4: shared.ServiceBusyError serviceBusyError,
+      5: shared.AccessDeniedError accessDeniedError,
+    )
+}
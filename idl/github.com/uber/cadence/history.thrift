@@ -41,6 +41,7 @@ struct StartWorkflowExecutionRequest {
   10: optional string domainUUID
   20: optional shared.StartWorkflowExecutionRequest startRequest
   30: optional ParentExecutionInfo parentExecutionInfo
+  40: optional i32 priority
 }
 
 struct GetMutableStateRequest {
@@ -115,6 +116,7 @@ struct RecordActivityTaskStartedRequest {
 struct RecordActivityTaskStartedResponse {
   10: optional shared.HistoryEvent startedEvent
   20: optional shared.HistoryEvent scheduledEvent
+  30: optional i64 (js.type = "Long") attempt
 }
 
 struct RecordDecisionTaskStartedRequest {
@@ -144,6 +146,11 @@ struct SignalWorkflowExecutionRequest {
   40: optional bool childWorkflowOnly
 }
 
+struct SignalWithStartWorkflowExecutionRequest {
+  10: optional string domainUUID
+  20: optional shared.SignalWithStartWorkflowExecutionRequest signalWithStartRequest
+}
+
 struct RemoveSignalMutableStateRequest {
   10: optional string domainUUID
   20: optional shared.WorkflowExecution workflowExecution
@@ -155,6 +162,11 @@ struct TerminateWorkflowExecutionRequest {
   20: optional shared.TerminateWorkflowExecutionRequest terminateRequest
 }
 
+struct ResetWorkflowExecutionRequest {
+  10: optional string domainUUID
+  20: optional shared.ResetWorkflowExecutionRequest resetRequest
+}
+
 struct RequestCancelWorkflowExecutionRequest {
   10: optional string domainUUID
   20: optional shared.RequestCancelWorkflowExecutionRequest cancelRequest
@@ -188,6 +200,16 @@ struct RecordChildExecutionCompletedRequest {
   50: optional shared.HistoryEvent completionEvent
 }
 
+struct ReplicateEventsRequest {
+  10: optional string sourceCluster
+  20: optional string domainUUID
+  30: optional shared.WorkflowExecution workflowExecution
+  40: optional i64 (js.type = "Long") firstEventId
+  50: optional i64 (js.type = "Long") nextEventId
+  60: optional i64 (js.type = "Long") version
+  70: optional shared.History history
+}
+
 /**
 * HistoryService provides API to start a new long running workflow instance, as well as query and update the history
 * of workflow instances already created.
@@ -364,6 +386,22 @@ service HistoryService {
       4: ShardOwnershipLostError shardOwnershipLostError,
     )
 
+  /**
+  * SignalWithStartWorkflowExecution is used to ensure sending a signal event to a workflow execution.
+  * If workflow is running, this results in WorkflowExecutionSignaled event recorded in the history
+  * and a decision task being created for the execution.
+  * If workflow is not running or not found, it will first try start workflow with given WorkflowIDResuePolicy,
+  * and record WorkflowExecutionStarted and WorkflowExecutionSignaled event in case of success.
+  * It will return `WorkflowExecutionAlreadyStartedError` if start workflow failed with given policy.
+  **/
+  shared.StartWorkflowExecutionResponse SignalWithStartWorkflowExecution(1: SignalWithStartWorkflowExecutionRequest signalWithStartRequest)
+    throws (
+      1: shared.BadRequestError badRequestError,
+      2: shared.InternalServiceError internalServiceError,
+      3: ShardOwnershipLostError shardOwnershipLostError,
+      4: shared.WorkflowExecutionAlreadyStartedError workflowAlreadyStartedError,
+    )
+
   /**
   * RemoveSignalMutableState is used to remove a signal request ID that was previously recorded.  This is currently
   * used to clean execution info when signal decision finished.
@@ -388,6 +426,19 @@ service HistoryService {
       4: ShardOwnershipLostError shardOwnershipLostError,
     )
 
+  /**
+  * ResetWorkflowExecution resets an existing workflow execution to the given DecisionTaskCompleted event (exclusive).
+  * It starts a new run from a copy of the history up to that point, re-applies the signals received after it and
+  * terminates the current run of the execution.
+  **/
+  shared.ResetWorkflowExecutionResponse ResetWorkflowExecution(1: ResetWorkflowExecutionRequest resetRequest)
+    throws (
+      1: shared.BadRequestError badRequestError,
+      2: shared.InternalServiceError internalServiceError,
+      3: shared.EntityNotExistsError entityNotExistError,
+      4: ShardOwnershipLostError shardOwnershipLostError,
+    )
+
   /**
   * RequestCancelWorkflowExecution is called by application worker when it wants to request cancellation of a workflow instance.
   * It will result in a new 'WorkflowExecutionCancelRequested' event being written to the workflow history and a new DecisionTask
@@ -439,4 +490,15 @@ service HistoryService {
       3: shared.EntityNotExistsError entityNotExistError,
       4: ShardOwnershipLostError shardOwnershipLostError,
     )
+
+  /**
+  * ReplicateEvents is called by processor to replicate history events for passive domains
+  **/
+  void ReplicateEvents(1: ReplicateEventsRequest replicateRequest)
+    throws (
+      1: shared.BadRequestError badRequestError,
+      2: shared.InternalServiceError internalServiceError,
+      3: shared.EntityNotExistsError entityNotExistError,
+      4: ShardOwnershipLostError shardOwnershipLostError,
+    )
 }
@@ -22,10 +22,17 @@ include "shared.thrift"
 
 namespace java com.uber.cadence.matching
 
+// RemoteSyncMatchFailedError is returned by the root partition of a task list when a task forwarded by one of its
+// partitions could not be matched to a poller, the task is then persisted by the partition
+exception RemoteSyncMatchFailedError {
+  1: required string message
+}
+
 struct PollForDecisionTaskRequest {
   10: optional string domainUUID
   15: optional string pollerID
   20: optional shared.PollForDecisionTaskRequest pollRequest
+  30: optional string forwardedFrom
 }
 
 struct PollForDecisionTaskResponse {
@@ -46,6 +53,7 @@ struct PollForActivityTaskRequest {
   10: optional string domainUUID
   15: optional string pollerID
   20: optional shared.PollForActivityTaskRequest pollRequest
+  30: optional string forwardedFrom
 }
 
 struct AddDecisionTaskRequest {
@@ -54,6 +62,9 @@ struct AddDecisionTaskRequest {
   30: optional shared.TaskList taskList
   40: optional i64 (js.type = "Long") scheduleId
   50: optional i32 scheduleToStartTimeoutSeconds
+  60: optional string forwardedFrom
+  70: optional i32 priority
+  80: optional string buildID
 }
 
 struct AddActivityTaskRequest {
@@ -63,6 +74,8 @@ struct AddActivityTaskRequest {
   40: optional shared.TaskList taskList
   50: optional i64 (js.type = "Long") scheduleId
   60: optional i32 scheduleToStartTimeoutSeconds
+  70: optional string forwardedFrom
+  80: optional i32 priority
 }
 
 struct QueryWorkflowRequest {
@@ -90,6 +103,26 @@ struct DescribeTaskListRequest {
   20: optional shared.DescribeTaskListRequest descRequest
 }
 
+struct UpdateTaskListBuildIDsRequest {
+  10: optional string domainUUID
+  20: optional shared.UpdateTaskListBuildIDsRequest updateRequest
+}
+
+struct PauseTaskListRequest {
+  10: optional string domainUUID
+  20: optional shared.PauseTaskListRequest pauseRequest
+}
+
+struct ResumeTaskListRequest {
+  10: optional string domainUUID
+  20: optional shared.ResumeTaskListRequest resumeRequest
+}
+
+struct ListTaskListsRequest {
+  10: optional string domainUUID
+  20: optional shared.ListTaskListsRequest listRequest
+}
+
 /**
 * MatchingService API is exposed to provide support for polling from long running applications.
 * Such applications are expected to have a worker which regularly polls for DecisionTask and ActivityTask.  For each
@@ -127,6 +160,7 @@ service MatchingService {
       1: shared.BadRequestError badRequestError,
       2: shared.InternalServiceError internalServiceError,
       3: shared.ServiceBusyError serviceBusyError,
+      4: RemoteSyncMatchFailedError remoteSyncMatchFailedError,
     )
 
   /**
@@ -138,6 +172,7 @@ service MatchingService {
       1: shared.BadRequestError badRequestError,
       2: shared.InternalServiceError internalServiceError,
       3: shared.ServiceBusyError serviceBusyError,
+      4: RemoteSyncMatchFailedError remoteSyncMatchFailedError,
     )
 
   /**
@@ -186,4 +221,45 @@ service MatchingService {
         2: shared.InternalServiceError internalServiceError,
         3: shared.EntityNotExistsError entityNotExistError,
       )
+
+  /**
+  * UpdateTaskListBuildIDs marks a build ID of a decision task list as compatible with other build IDs or as retired.
+  **/
+  void UpdateTaskListBuildIDs(1: UpdateTaskListBuildIDsRequest request)
+    throws (
+        1: shared.BadRequestError badRequestError,
+        2: shared.InternalServiceError internalServiceError,
+        3: shared.ServiceBusyError serviceBusyError,
+      )
+
+  /**
+  * PauseTaskList stops dispatching the tasks of a task list partition to pollers, new tasks are persisted.
+  **/
+  void PauseTaskList(1: PauseTaskListRequest request)
+    throws (
+        1: shared.BadRequestError badRequestError,
+        2: shared.InternalServiceError internalServiceError,
+        3: shared.ServiceBusyError serviceBusyError,
+      )
+
+  /**
+  * ResumeTaskList resumes dispatching the tasks of a paused task list partition.
+  **/
+  void ResumeTaskList(1: ResumeTaskListRequest request)
+    throws (
+        1: shared.BadRequestError badRequestError,
+        2: shared.InternalServiceError internalServiceError,
+        3: shared.ServiceBusyError serviceBusyError,
+      )
+
+  /**
+  * ListTaskLists returns a page of the task lists of a domain, the partitions, priority backlogs and build ID task
+  * lists which are internal to a task list are not listed.
+  **/
+  shared.ListTaskListsResponse ListTaskLists(1: ListTaskListsRequest request)
+    throws (
+        1: shared.BadRequestError badRequestError,
+        2: shared.InternalServiceError internalServiceError,
+        3: shared.ServiceBusyError serviceBusyError,
+      )
 }
@@ -61,6 +61,10 @@ exception DomainNotActiveError {
   4: required string activeCluster
 }
 
+exception AccessDeniedError {
+  1: required string message
+}
+
 
 enum WorkflowIdReusePolicy {
   /*
@@ -86,6 +90,11 @@ enum DomainStatus {
   DELETED,
 }
 
+enum ArchivalStatus {
+  DISABLED,
+  ENABLED,
+}
+
 enum TimeoutType {
   START_TO_CLOSE,
   SCHEDULE_TO_START,
@@ -110,6 +119,7 @@ enum DecisionType {
   ContinueAsNewWorkflowExecution,
   StartChildWorkflowExecution,
   SignalExternalWorkflowExecution,
+  UpsertWorkflowSearchAttributes,
 }
 
 enum EventType {
@@ -154,6 +164,7 @@ enum EventType {
   SignalExternalWorkflowExecutionInitiated,
   SignalExternalWorkflowExecutionFailed,
   ExternalWorkflowExecutionSignaled,
+  UpsertWorkflowSearchAttributes,
 }
 
 enum DecisionTaskFailedCause {
@@ -173,6 +184,8 @@ enum DecisionTaskFailedCause {
   WORKFLOW_WORKER_UNHANDLED_FAILURE,
   BAD_SIGNAL_WORKFLOW_EXECUTION_ATTRIBUTES,
   BAD_START_CHILD_EXECUTION_ATTRIBUTES,
+  RESET_WORKFLOW,
+  BAD_SEARCH_ATTRIBUTES,
 }
 
 enum CancelExternalWorkflowExecutionFailedCause {
@@ -223,6 +236,20 @@ enum TaskListKind {
   STICKY,
 }
 
+enum ContinueAsNewInitiator {
+  DECIDER,
+  RETRY_POLICY,
+  CRON_SCHEDULE,
+}
+
+enum IndexedValueType {
+  KEYWORD,
+  INT,
+  DOUBLE,
+  BOOL,
+  DATETIME,
+}
+
 struct WorkflowType {
   10: optional string name
 }
@@ -245,6 +272,12 @@ struct WorkflowExecution {
   20: optional string runId
 }
 
+// SearchAttributes holds the custom indexed attributes of a workflow execution,
+// keyed by attribute name with the JSON encoded value
+struct SearchAttributes {
+  10: optional map<string,binary> indexedFields
+}
+
 struct WorkflowExecutionInfo {
   10: optional WorkflowExecution execution
   20: optional WorkflowType type
@@ -252,6 +285,7 @@ struct WorkflowExecutionInfo {
   40: optional i64 (js.type = "Long") closeTime
   50: optional WorkflowExecutionCloseStatus closeStatus
   60: optional i64 (js.type = "Long") historyLength
+  70: optional SearchAttributes searchAttributes
 }
 
 struct WorkflowExecutionConfiguration {
@@ -266,6 +300,33 @@ struct TransientDecisionInfo {
   20: optional HistoryEvent startedEvent
 }
 
+// RetryPolicy defines how retry can be applied to an activity or a workflow execution
+struct RetryPolicy {
+  // Interval of the first retry. If coefficient is 1.0 then it is used for all retries.
+  10: optional i32 initialIntervalInSeconds
+
+  // Coefficient used to calculate the next retry interval.
+  // The next retry interval is previous interval multiplied by the coefficient.
+  // Must be 1 or larger.
+  20: optional double backoffCoefficient
+
+  // Maximum interval between retries. Exponential backoff leads to interval increase.
+  // This value is the cap of the increase. Default is 100x of initial interval.
+  30: optional i32 maximumIntervalInSeconds
+
+  // Maximum number of attempts. When exceeded the retries stop even if not expired yet.
+  // Zero means unlimited, in which case expirationIntervalInSeconds must be set.
+  40: optional i32 maximumAttempts
+
+  // Non-Retriable errors. Will stop retrying if error matches this list.
+  50: optional list<string> nonRetriableErrorReasons
+
+  // Expiration time for the whole retry process, measured from when the activity is scheduled
+  // or when the first run of the workflow execution is started.
+  // Zero means no expiration, in which case maximumAttempts must be set.
+  60: optional i32 expirationIntervalInSeconds
+}
+
 struct ScheduleActivityTaskDecisionAttributes {
   10: optional string activityId
   20: optional ActivityType activityType
@@ -276,6 +337,8 @@ struct ScheduleActivityTaskDecisionAttributes {
   50: optional i32 scheduleToStartTimeoutSeconds
   55: optional i32 startToCloseTimeoutSeconds
   60: optional i32 heartbeatTimeoutSeconds
+  70: optional RetryPolicy retryPolicy
+  80: optional i32 priority
 }
 
 struct RequestCancelActivityTaskDecisionAttributes {
@@ -332,6 +395,13 @@ struct ContinueAsNewWorkflowExecutionDecisionAttributes {
   30: optional binary input
   40: optional i32 executionStartToCloseTimeoutSeconds
   50: optional i32 taskStartToCloseTimeoutSeconds
+  60: optional i32 backoffStartIntervalInSeconds
+  70: optional RetryPolicy retryPolicy
+  80: optional ContinueAsNewInitiator initiator
+  90: optional string failureReason
+  100: optional binary failureDetails
+  110: optional string cronSchedule
+  120: optional SearchAttributes searchAttributes
 }
 
 struct StartChildWorkflowExecutionDecisionAttributes {
@@ -345,6 +415,13 @@ struct StartChildWorkflowExecutionDecisionAttributes {
   80: optional ChildPolicy childPolicy
   90: optional binary control
   100: optional WorkflowIdReusePolicy workflowIdReusePolicy
+  110: optional RetryPolicy retryPolicy
+  120: optional string cronSchedule
+  130: optional i32 priority
+}
+
+struct UpsertWorkflowSearchAttributesDecisionAttributes {
+  10: optional SearchAttributes searchAttributes
 }
 
 struct Decision {
@@ -361,6 +438,7 @@ struct Decision {
   90:  optional ContinueAsNewWorkflowExecutionDecisionAttributes continueAsNewWorkflowExecutionDecisionAttributes
   100: optional StartChildWorkflowExecutionDecisionAttributes startChildWorkflowExecutionDecisionAttributes
   110: optional SignalExternalWorkflowExecutionDecisionAttributes signalExternalWorkflowExecutionDecisionAttributes
+  120: optional UpsertWorkflowSearchAttributesDecisionAttributes upsertWorkflowSearchAttributesDecisionAttributes
 }
 
 struct WorkflowExecutionStartedEventAttributes {
@@ -370,6 +448,13 @@ struct WorkflowExecutionStartedEventAttributes {
   40: optional i32 executionStartToCloseTimeoutSeconds
   50: optional i32 taskStartToCloseTimeoutSeconds
   60: optional string identity
+  70: optional RetryPolicy retryPolicy
+  80: optional i32 attempt
+  90: optional i64 (js.type = "Long") expirationTimestamp
+  100: optional string cronSchedule
+  110: optional i32 firstDecisionTaskBackoffSeconds
+  120: optional SearchAttributes searchAttributes
+  130: optional i32 priority
 }
 
 struct WorkflowExecutionCompletedEventAttributes {
@@ -395,6 +480,10 @@ struct WorkflowExecutionContinuedAsNewEventAttributes {
   50: optional i32 executionStartToCloseTimeoutSeconds
   60: optional i32 taskStartToCloseTimeoutSeconds
   70: optional i64 (js.type = "Long") decisionTaskCompletedEventId
+  80: optional i32 backoffStartIntervalInSeconds
+  90: optional ContinueAsNewInitiator initiator
+  100: optional string failureReason
+  110: optional binary failureDetails
 }
 
 struct DecisionTaskScheduledEventAttributes {
@@ -407,6 +496,7 @@ struct DecisionTaskStartedEventAttributes {
   10: optional i64 (js.type = "Long") scheduledEventId
   20: optional string identity
   30: optional string requestId
+  40: optional string buildID
 }
 
 struct DecisionTaskCompletedEventAttributes {
@@ -441,12 +531,15 @@ struct ActivityTaskScheduledEventAttributes {
   55: optional i32 startToCloseTimeoutSeconds
   60: optional i32 heartbeatTimeoutSeconds
   90: optional i64 (js.type = "Long") decisionTaskCompletedEventId
+  100: optional RetryPolicy retryPolicy
+  110: optional i32 priority
 }
 
 struct ActivityTaskStartedEventAttributes {
   10: optional i64 (js.type = "Long") scheduledEventId
   20: optional string identity
   30: optional string requestId
+  40: optional i32 attempt
 }
 
 struct ActivityTaskCompletedEventAttributes {
@@ -606,6 +699,9 @@ struct StartChildWorkflowExecutionInitiatedEventAttributes {
   90:  optional binary control
   100: optional i64 (js.type = "Long") decisionTaskCompletedEventId
   110: optional WorkflowIdReusePolicy workflowIdReusePolicy
+  120: optional RetryPolicy retryPolicy
+  130: optional string cronSchedule
+  140: optional i32 priority
 }
 
 struct StartChildWorkflowExecutionFailedEventAttributes {
@@ -670,6 +766,11 @@ struct ChildWorkflowExecutionTerminatedEventAttributes {
   50: optional i64 (js.type = "Long") startedEventId
 }
 
+struct UpsertWorkflowSearchAttributesEventAttributes {
+  10: optional i64 (js.type = "Long") decisionTaskCompletedEventId
+  20: optional SearchAttributes searchAttributes
+}
+
 struct HistoryEvent {
   10:  optional i64 (js.type = "Long") eventId
   20:  optional i64 (js.type = "Long") timestamp
@@ -715,6 +816,7 @@ struct HistoryEvent {
   420: optional SignalExternalWorkflowExecutionInitiatedEventAttributes signalExternalWorkflowExecutionInitiatedEventAttributes
   430: optional SignalExternalWorkflowExecutionFailedEventAttributes signalExternalWorkflowExecutionFailedEventAttributes
   440: optional ExternalWorkflowExecutionSignaledEventAttributes externalWorkflowExecutionSignaledEventAttributes
+  450: optional UpsertWorkflowSearchAttributesEventAttributes upsertWorkflowSearchAttributesEventAttributes
 }
 
 struct History {
@@ -744,6 +846,8 @@ struct DomainInfo {
 struct DomainConfiguration {
   10: optional i32 workflowExecutionRetentionPeriodInDays
   20: optional bool emitMetric
+  30: optional ArchivalStatus archivalStatus
+  40: optional string archivalBucketName
 }
 
 struct UpdateDomainInfo {
@@ -768,6 +872,8 @@ struct RegisterDomainRequest {
   50: optional bool emitMetric
   60: optional list<ClusterReplicationConfiguration> clusters
   70: optional string activeClusterName
+  80: optional ArchivalStatus archivalStatus
+  90: optional string archivalBucketName
 }
 
 struct DescribeDomainRequest {
@@ -812,6 +918,9 @@ struct StartWorkflowExecutionRequest {
   80: optional string identity
   90: optional string requestId
   100: optional WorkflowIdReusePolicy workflowIdReusePolicy
+  110: optional RetryPolicy retryPolicy
+  120: optional string cronSchedule
+  130: optional SearchAttributes searchAttributes
 }
 
 struct StartWorkflowExecutionResponse {
@@ -822,6 +931,8 @@ struct PollForDecisionTaskRequest {
   10: optional string domain
   20: optional TaskList taskList
   30: optional string identity
+  // build ID of the worker code, decision tasks of workflows pinned to another build ID are not dispatched to it
+  40: optional string buildID
 }
 
 struct PollForDecisionTaskResponse {
@@ -862,6 +973,7 @@ struct PollForActivityTaskRequest {
   20: optional TaskList taskList
   30: optional string identity
   40: optional TaskListMetadata taskListMetadata
+  50: optional string buildID
 }
 
 struct PollForActivityTaskResponse {
@@ -875,6 +987,7 @@ struct PollForActivityTaskResponse {
   90:  optional i64 (js.type = "Long") startedTimestamp
   100: optional i32 startToCloseTimeoutSeconds
   110: optional i32 heartbeatTimeoutSeconds
+  120: optional i32 attempt
 }
 
 struct RecordActivityTaskHeartbeatRequest {
@@ -974,6 +1087,25 @@ struct SignalWorkflowExecutionRequest {
   70: optional binary control
 }
 
+struct SignalWithStartWorkflowExecutionRequest {
+  10: optional string domain
+  20: optional string workflowId
+  30: optional WorkflowType workflowType
+  40: optional TaskList taskList
+  50: optional binary input
+  60: optional i32 executionStartToCloseTimeoutSeconds
+  70: optional i32 taskStartToCloseTimeoutSeconds
+  80: optional string identity
+  90: optional string requestId
+  100: optional WorkflowIdReusePolicy workflowIdReusePolicy
+  110: optional string signalName
+  120: optional binary signalInput
+  130: optional binary control
+  140: optional RetryPolicy retryPolicy
+  150: optional string cronSchedule
+  160: optional SearchAttributes searchAttributes
+}
+
 struct TerminateWorkflowExecutionRequest {
   10: optional string domain
   20: optional WorkflowExecution workflowExecution
@@ -982,6 +1114,19 @@ struct TerminateWorkflowExecutionRequest {
   50: optional string identity
 }
 
+struct ResetWorkflowExecutionRequest {
+  10: optional string domain
+  20: optional WorkflowExecution workflowExecution
+  30: optional string reason
+  40: optional i64 (js.type = "Long") decisionFinishEventId
+  50: optional string requestId
+  60: optional string identity
+}
+
+struct ResetWorkflowExecutionResponse {
+  10: optional string runId
+}
+
 struct ListOpenWorkflowExecutionsRequest {
   10: optional string domain
   20: optional i32 maximumPageSize
@@ -989,6 +1134,7 @@ struct ListOpenWorkflowExecutionsRequest {
   40: optional StartTimeFilter StartTimeFilter
   50: optional WorkflowExecutionFilter executionFilter
   60: optional WorkflowTypeFilter typeFilter
+  70: optional SearchAttributes searchAttributesFilter
 }
 
 struct ListOpenWorkflowExecutionsResponse {
@@ -1004,6 +1150,7 @@ struct ListClosedWorkflowExecutionsRequest {
   50: optional WorkflowExecutionFilter executionFilter
   60: optional WorkflowTypeFilter typeFilter
   70: optional WorkflowExecutionCloseStatus statusFilter
+  80: optional SearchAttributes searchAttributesFilter
 }
 
 struct ListClosedWorkflowExecutionsResponse {
@@ -1011,6 +1158,27 @@ struct ListClosedWorkflowExecutionsResponse {
   20: optional binary nextPageToken
 }
 
+struct ListWorkflowExecutionsRequest {
+  10: optional string domain
+  20: optional i32 pageSize
+  30: optional binary nextPageToken
+  40: optional string query
+}
+
+struct ListWorkflowExecutionsResponse {
+  10: optional list<WorkflowExecutionInfo> executions
+  20: optional binary nextPageToken
+}
+
+struct CountWorkflowExecutionsRequest {
+  10: optional string domain
+  20: optional string query
+}
+
+struct CountWorkflowExecutionsResponse {
+  10: optional i64 count
+}
+
 struct QueryWorkflowRequest {
   10: optional string domain
   20: optional WorkflowExecution execution
@@ -1044,6 +1212,7 @@ struct PendingActivityInfo {
   30: optional PendingActivityState state
   40: optional binary heartbeatDetails
   50: optional i64 (js.type = "Long") lastHeartbeatTimestamp
+  60: optional i32 attempt
 }
 
 struct DescribeWorkflowExecutionResponse {
@@ -1060,6 +1229,54 @@ struct DescribeTaskListRequest {
 
 struct DescribeTaskListResponse {
   10: optional list<PollerInfo> pollers
+  20: optional TaskListStatus taskListStatus
+  30: optional TaskListBuildIDs buildIDs
+  // tasks of a paused task list are persisted but not dispatched to pollers until it is resumed
+  40: optional bool paused
+}
+
+struct CompatibleBuildIDs {
+  10: optional list<string> buildIDs
+}
+
+struct TaskListBuildIDs {
+  // sets of build IDs which can run each other's workflows, the last set holds the default build IDs of the task list
+  10: optional list<CompatibleBuildIDs> compatibleBuildIDs
+  20: optional list<string> retiredBuildIDs
+}
+
+struct UpdateTaskListBuildIDsRequest {
+  10: optional string domain
+  20: optional TaskList taskList
+  30: optional string buildID
+  // buildID is added to the set of compatibleBuildID, without it buildID starts a new default set
+  40: optional string compatibleBuildID
+  // retired build IDs are no longer polled for, their workflows are dispatched to the default build IDs
+  50: optional bool retire
+}
+
+struct PauseTaskListRequest {
+  10: optional string domain
+  20: optional TaskList taskList
+  30: optional TaskListType taskListType
+}
+
+struct ResumeTaskListRequest {
+  10: optional string domain
+  20: optional TaskList taskList
+  30: optional TaskListType taskListType
+}
+
+struct TaskListStatus {
+  10: optional i64 (js.type = "Long") backlogCountHint
+  20: optional i64 (js.type = "Long") readLevel
+  30: optional i64 (js.type = "Long") ackLevel
+  35: optional i64 (js.type = "Long") rangeID
+  // tasks dispatched per second over the last minute
+  40: optional double ratePerSecond
+  50: optional double maxRatePerSecond
+  // ratio of the tasks dispatched over the last minute which were sync matched
+  60: optional double syncMatchRatio
 }
 
 enum TaskListType {
@@ -1073,8 +1290,34 @@ enum TaskListType {
   Activity,
 }
 
+struct ListTaskListsRequest {
+  10: optional string domain
+  20: optional i32 maximumPageSize
+  30: optional binary nextPageToken
+}
+
+struct TaskListInfo {
+  10: optional string name
+  20: optional TaskListType taskListType
+  30: optional TaskListKind kind
+  40: optional i64 (js.type = "Long") ackLevel
+  // Unix Nano
+  50: optional i64 (js.type = "Long") lastUpdatedTime
+}
+
+struct ListTaskListsResponse {
+  10: optional list<TaskListInfo> taskLists
+  20: optional binary nextPageToken
+}
+
 struct PollerInfo {
   // Unix Nano
   10: optional i64 (js.type = "Long")  lastAccessTime
   20: optional string identity
+  // the dispatch rate the poller asked for
+  30: optional double ratePerSecond
+  40: optional string ipAddress
+  50: optional string clientLibraryVersion
+  60: optional string clientImpl
+  70: optional string buildID
 }
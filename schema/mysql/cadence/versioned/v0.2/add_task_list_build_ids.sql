@@ -0,0 +1 @@
+ALTER TABLE task_lists ADD build_ids BLOB NULL;
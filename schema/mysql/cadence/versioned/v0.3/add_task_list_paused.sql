@@ -0,0 +1 @@
+ALTER TABLE task_lists ADD paused BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,15 @@
+CREATE TABLE executions_visibility (
+  domain_id VARCHAR(64) NOT NULL,
+  run_id VARCHAR(64) NOT NULL,
+  workflow_id VARCHAR(255) NOT NULL,
+  workflow_type_name VARCHAR(255) NOT NULL,
+  start_time BIGINT NOT NULL,
+  close_time BIGINT NULL,
+  close_status INTEGER NULL,
+  history_length BIGINT NULL,
+  PRIMARY KEY (domain_id, run_id)
+);
+
+CREATE INDEX by_start_time ON executions_visibility (domain_id, start_time DESC, run_id);
+CREATE INDEX by_workflow_id ON executions_visibility (domain_id, workflow_id, start_time DESC, run_id);
+CREATE INDEX by_type_start_time ON executions_visibility (domain_id, workflow_type_name, start_time DESC, run_id);
@@ -0,0 +1 @@
+ALTER TABLE task_lists ADD last_updated BIGINT NOT NULL DEFAULT 0;
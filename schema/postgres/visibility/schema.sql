@@ -0,0 +1,26 @@
+CREATE TABLE executions_visibility (
+  domain_id VARCHAR(64) NOT NULL,
+  run_id VARCHAR(64) NOT NULL,
+  workflow_id VARCHAR(255) NOT NULL,
+  workflow_type_name VARCHAR(255) NOT NULL,
+  start_time BIGINT NOT NULL,
+  close_time BIGINT NULL,
+  close_status INTEGER NULL,
+  history_length BIGINT NULL,
+  search_attributes BYTEA NULL,
+  PRIMARY KEY (domain_id, run_id)
+);
+
+CREATE INDEX by_start_time ON executions_visibility (domain_id, start_time DESC, run_id);
+CREATE INDEX by_workflow_id ON executions_visibility (domain_id, workflow_id, start_time DESC, run_id);
+CREATE INDEX by_type_start_time ON executions_visibility (domain_id, workflow_type_name, start_time DESC, run_id);
+
+CREATE TABLE executions_visibility_search_attributes (
+  domain_id VARCHAR(64) NOT NULL,
+  run_id VARCHAR(64) NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  value BYTEA NOT NULL,
+  PRIMARY KEY (domain_id, run_id, name)
+);
+
+CREATE INDEX by_search_attribute ON executions_visibility_search_attributes (domain_id, name, value);
@@ -0,0 +1,11 @@
+ALTER TABLE executions_visibility ADD search_attributes BYTEA NULL;
+
+CREATE TABLE executions_visibility_search_attributes (
+  domain_id VARCHAR(64) NOT NULL,
+  run_id VARCHAR(64) NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  value BYTEA NOT NULL,
+  PRIMARY KEY (domain_id, run_id, name)
+);
+
+CREATE INDEX by_search_attribute ON executions_visibility_search_attributes (domain_id, name, value);
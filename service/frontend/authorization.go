@@ -0,0 +1,99 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package frontend
+
+import (
+	"context"
+
+	"github.com/uber/cadence/common/authorization"
+)
+
+// apiPermissions is the permission on its domain which is required to call each API, the APIs which are not
+// listed require the admin permission
+var apiPermissions = map[string]authorization.Permission{
+	"RegisterDomain":                   authorization.PermissionAdmin,
+	"DescribeDomain":                   authorization.PermissionRead,
+	"UpdateDomain":                     authorization.PermissionAdmin,
+	"DeprecateDomain":                  authorization.PermissionAdmin,
+	"PollForActivityTask":              authorization.PermissionWrite,
+	"PollForDecisionTask":              authorization.PermissionWrite,
+	"RecordActivityTaskHeartbeat":      authorization.PermissionWrite,
+	"RecordActivityTaskHeartbeatByID":  authorization.PermissionWrite,
+	"RespondActivityTaskCompleted":     authorization.PermissionWrite,
+	"RespondActivityTaskCompletedByID": authorization.PermissionWrite,
+	"RespondActivityTaskFailed":        authorization.PermissionWrite,
+	"RespondActivityTaskFailedByID":    authorization.PermissionWrite,
+	"RespondActivityTaskCanceled":      authorization.PermissionWrite,
+	"RespondActivityTaskCanceledByID":  authorization.PermissionWrite,
+	"RespondDecisionTaskCompleted":     authorization.PermissionWrite,
+	"RespondDecisionTaskFailed":        authorization.PermissionWrite,
+	"RespondQueryTaskCompleted":        authorization.PermissionWrite,
+	"StartWorkflowExecution":           authorization.PermissionWrite,
+	"GetWorkflowExecutionHistory":      authorization.PermissionRead,
+	"SignalWorkflowExecution":          authorization.PermissionWrite,
+	"SignalWithStartWorkflowExecution": authorization.PermissionWrite,
+	"TerminateWorkflowExecution":       authorization.PermissionWrite,
+	"ResetWorkflowExecution":           authorization.PermissionWrite,
+	"RequestCancelWorkflowExecution":   authorization.PermissionWrite,
+	"ListOpenWorkflowExecutions":       authorization.PermissionRead,
+	"ListClosedWorkflowExecutions":     authorization.PermissionRead,
+	"ListWorkflowExecutions":           authorization.PermissionRead,
+	"CountWorkflowExecutions":          authorization.PermissionRead,
+	"QueryWorkflow":                    authorization.PermissionRead,
+	"DescribeWorkflowExecution":        authorization.PermissionRead,
+	"DescribeTaskList":                 authorization.PermissionRead,
+	"UpdateTaskListBuildIDs":           authorization.PermissionAdmin,
+	"PauseTaskList":                    authorization.PermissionAdmin,
+	"ResumeTaskList":                   authorization.PermissionAdmin,
+	"ListTaskLists":                    authorization.PermissionRead,
+}
+
+// authorize asks the authorizer if the caller is allowed to call the API on the domain, the denials are counted
+// in the metrics of the API
+func (wh *WorkflowHandler) authorize(ctx context.Context, apiName string, domainName string, scope int) error {
+	permission, ok := apiPermissions[apiName]
+	if !ok {
+		permission = authorization.PermissionAdmin
+	}
+	identity, err := wh.identityProvider.GetIdentity(ctx)
+	if err != nil {
+		return wh.error(err, scope)
+	}
+	err = wh.authorizer.Authorize(ctx, &authorization.Attributes{
+		APIName:    apiName,
+		DomainName: domainName,
+		Identity:   identity,
+		Permission: permission,
+	})
+	if err != nil {
+		return wh.error(err, scope)
+	}
+	return nil
+}
+
+// authorizeByDomainID authorizes the APIs which receive the ID of their domain in a task token
+func (wh *WorkflowHandler) authorizeByDomainID(ctx context.Context, apiName string, domainID string, scope int) error {
+	domainEntry, err := wh.domainCache.GetDomainByID(domainID)
+	if err != nil {
+		return wh.error(err, scope)
+	}
+	return wh.authorize(ctx, apiName, domainEntry.GetInfo().Name, scope)
+}
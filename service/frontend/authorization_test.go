@@ -0,0 +1,138 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package frontend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+	"go.uber.org/yarpc/api/encoding"
+	"go.uber.org/yarpc/api/transport"
+
+	gen "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/authorization"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/service/config"
+	"github.com/uber/cadence/common/service/dynamicconfig"
+)
+
+type (
+	authorizationSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		scope   tally.TestScope
+		handler *WorkflowHandler
+	}
+)
+
+var testSigningKey = []byte("test-signing-key")
+
+func TestAuthorizationSuite(t *testing.T) {
+	s := new(authorizationSuite)
+	suite.Run(t, s)
+}
+
+func (s *authorizationSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.scope = tally.NewTestScope("test", nil)
+	metricsClient := metrics.NewClient(s.scope, metrics.Frontend)
+	cfg := &config.Authorization{
+		SigningKey: string(testSigningKey),
+		Domains: map[string]config.DomainAllowList{
+			"orders": {
+				Read:  []string{"reader"},
+				Write: []string{"writer"},
+			},
+		},
+	}
+	frontendConfig := NewConfig(dynamicconfig.NewNopCollection())
+	s.handler = &WorkflowHandler{
+		config:           frontendConfig,
+		metricsClient:    metricsClient,
+		rateLimiter:      newDomainRateLimiter(frontendConfig.RPS, frontendConfig.DomainRPS, metricsClient, common.NewRealTimeSource()),
+		authorizer:       authorization.NewAuthorizer(cfg),
+		identityProvider: authorization.NewIdentityProvider(cfg),
+	}
+}
+
+func (s *authorizationSuite) callContext(headers map[string]string) context.Context {
+	ctx, call := encoding.NewInboundCall(context.Background())
+	s.NoError(call.ReadFromRequest(&transport.Request{Headers: transport.HeadersFromMap(headers)}))
+	return ctx
+}
+
+func (s *authorizationSuite) tokenContext(identity string) context.Context {
+	return s.callContext(map[string]string{
+		common.AuthorizationTokenHeaderName: authorization.NewToken(testSigningKey, identity, time.Now().Add(time.Hour)),
+	})
+}
+
+func (s *authorizationSuite) terminate(ctx context.Context) error {
+	return s.handler.TerminateWorkflowExecution(ctx, &gen.TerminateWorkflowExecutionRequest{
+		Domain: common.StringPtr("orders"),
+	})
+}
+
+func (s *authorizationSuite) accessDeniedCount() int64 {
+	counter, ok := s.scope.Snapshot().Counters()["test.cadence.errors.access-denied+operation=TerminateWorkflowExecution"]
+	if !ok {
+		return 0
+	}
+	return counter.Value()
+}
+
+func (s *authorizationSuite) TestDeniedCall() {
+	err := s.terminate(s.tokenContext("reader"))
+	s.IsType(&gen.AccessDeniedError{}, err)
+	s.EqualValues(1, s.accessDeniedCount())
+
+	err = s.terminate(context.Background())
+	s.IsType(&gen.AccessDeniedError{}, err)
+	s.EqualValues(2, s.accessDeniedCount())
+}
+
+func (s *authorizationSuite) TestAllowedCall() {
+	// the call passes the authorization and fails the validation of the request which follows it
+	err := s.terminate(s.tokenContext("writer"))
+	s.Equal(errExecutionNotSet, err)
+	s.EqualValues(0, s.accessDeniedCount())
+}
+
+func (s *authorizationSuite) TestInvalidToken() {
+	ctx := s.callContext(map[string]string{
+		common.AuthorizationTokenHeaderName: authorization.NewToken([]byte("other-key"), "writer", time.Now().Add(time.Hour)),
+	})
+	s.IsType(&gen.AccessDeniedError{}, s.terminate(ctx))
+	s.EqualValues(1, s.accessDeniedCount())
+}
+
+func (s *authorizationSuite) TestUnauthenticatedIdentityIsNotTrusted() {
+	ctx := s.callContext(map[string]string{"cadence-caller-identity": "writer"})
+	s.IsType(&gen.AccessDeniedError{}, s.terminate(ctx))
+	s.EqualValues(1, s.accessDeniedCount())
+}
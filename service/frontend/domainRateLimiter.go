@@ -0,0 +1,168 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package frontend
+
+import (
+	"sync"
+	"time"
+
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/service/dynamicconfig"
+)
+
+const (
+	// domainRateLimiterIdleTimeout is the time after which the token bucket of a domain with no request is evicted
+	domainRateLimiterIdleTimeout = 10 * time.Minute
+)
+
+type (
+	// domainRateLimiter throttles the requests with a token bucket per domain, the rps of each domain coming from
+	// the dynamic config.  A global token bucket caps the requests of all the domains to protect the host.
+	domainRateLimiter struct {
+		sync.Mutex
+		globalRPS        dynamicconfig.IntPropertyFn
+		globalBucket     common.TokenBucket
+		globalBucketRPS  int
+		domainRPS        dynamicconfig.IntPropertyFn
+		metricsClient    metrics.Client
+		timeSource       common.TimeSource
+		domains          map[string]*domainRateLimiterEntry
+		nextEvictionTime time.Time
+	}
+
+	domainRateLimiterEntry struct {
+		rps            int
+		bucket         common.TokenBucket
+		lastAccessTime time.Time
+		// metricsClient is tagged with the domain, it is only created once a request of the domain is throttled
+		metricsClient metrics.Client
+	}
+)
+
+func newDomainRateLimiter(globalRPS dynamicconfig.IntPropertyFn, domainRPS dynamicconfig.IntPropertyFn,
+	metricsClient metrics.Client, timeSource common.TimeSource) *domainRateLimiter {
+	rps := globalRPS()
+	return &domainRateLimiter{
+		globalRPS:        globalRPS,
+		globalBucket:     common.NewTokenBucket(rps, timeSource),
+		globalBucketRPS:  rps,
+		domainRPS:        domainRPS,
+		metricsClient:    metricsClient,
+		timeSource:       timeSource,
+		domains:          make(map[string]*domainRateLimiterEntry),
+		nextEvictionTime: timeSource.Now().Add(domainRateLimiterIdleTimeout),
+	}
+}
+
+// Allow consumes a token of the domain and of the global token buckets, it returns false when the request needs
+// to be throttled.  A throttled request is counted as a ServiceBusyError of the domain.
+func (d *domainRateLimiter) Allow(domain string, scope int) bool {
+	globalBucket := d.getGlobalBucket()
+	entry := d.getEntry(domain)
+	if entry == nil {
+		ok, _ := globalBucket.TryConsume(1)
+		return ok
+	}
+
+	// the bucket of the domain goes first, so the requests of a noisy domain do not drain the global bucket
+	ok, _ := entry.bucket.TryConsume(1)
+	if ok {
+		ok, _ = globalBucket.TryConsume(1)
+	}
+	if !ok {
+		d.getDomainMetricsClient(domain, entry).IncCounter(scope, metrics.CadenceErrServiceBusyCounter)
+	}
+	return ok
+}
+
+// Consume counts the request of the domain in the rps, without throttling it
+func (d *domainRateLimiter) Consume(domain string) {
+	if entry := d.getEntry(domain); entry != nil {
+		entry.bucket.TryConsume(1)
+	}
+	d.getGlobalBucket().TryConsume(1)
+}
+
+func (d *domainRateLimiter) getGlobalBucket() common.TokenBucket {
+	rps := d.globalRPS()
+
+	d.Lock()
+	defer d.Unlock()
+
+	if rps != d.globalBucketRPS {
+		// like the buckets of the domains, the global bucket follows the updates of its rps in the dynamic config
+		d.globalBucket = common.NewTokenBucket(rps, d.timeSource)
+		d.globalBucketRPS = rps
+	}
+	return d.globalBucket
+}
+
+func (d *domainRateLimiter) getEntry(domain string) *domainRateLimiterEntry {
+	if len(domain) == 0 {
+		return nil
+	}
+
+	rps := d.domainRPS(dynamicconfig.DomainFilter(domain))
+	now := d.timeSource.Now()
+
+	d.Lock()
+	defer d.Unlock()
+
+	if !now.Before(d.nextEvictionTime) {
+		d.evictIdleDomainsLocked(now)
+	}
+
+	entry, ok := d.domains[domain]
+	if !ok || entry.rps != rps {
+		// the bucket is recreated whenever the rps of the domain gets updated in the dynamic config
+		newEntry := &domainRateLimiterEntry{
+			rps:    rps,
+			bucket: common.NewTokenBucket(rps, d.timeSource),
+		}
+		if ok {
+			newEntry.metricsClient = entry.metricsClient
+		}
+		entry = newEntry
+		d.domains[domain] = entry
+	}
+	entry.lastAccessTime = now
+	return entry
+}
+
+func (d *domainRateLimiter) getDomainMetricsClient(domain string, entry *domainRateLimiterEntry) metrics.Client {
+	d.Lock()
+	defer d.Unlock()
+
+	if entry.metricsClient == nil {
+		entry.metricsClient = d.metricsClient.Tagged(map[string]string{metrics.DomainTagName: domain})
+	}
+	return entry.metricsClient
+}
+
+func (d *domainRateLimiter) evictIdleDomainsLocked(now time.Time) {
+	for domain, entry := range d.domains {
+		if now.Sub(entry.lastAccessTime) >= domainRateLimiterIdleTimeout {
+			delete(d.domains, domain)
+		}
+	}
+	d.nextEvictionTime = now.Add(domainRateLimiterIdleTimeout)
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package frontend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/service/dynamicconfig"
+)
+
+type (
+	domainRateLimiterSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		timeSource *mockTimeSource
+		globalRPS  int
+		domainRPS  map[string]int
+		limiter    *domainRateLimiter
+	}
+
+	mockTimeSource struct {
+		currTime time.Time
+	}
+)
+
+func TestDomainRateLimiterSuite(t *testing.T) {
+	s := new(domainRateLimiterSuite)
+	suite.Run(t, s)
+}
+
+func (s *domainRateLimiterSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.timeSource = &mockTimeSource{currTime: time.Now()}
+	s.domainRPS = map[string]int{}
+	s.limiter = s.newLimiter(100)
+}
+
+func (ts *mockTimeSource) Now() time.Time {
+	return ts.currTime
+}
+
+func (s *domainRateLimiterSuite) newLimiter(globalRPS int) *domainRateLimiter {
+	s.globalRPS = globalRPS
+	domainRPS := func(opts ...dynamicconfig.FilterOption) int {
+		filters := make(map[dynamicconfig.Filter]interface{})
+		for _, opt := range opts {
+			opt(filters)
+		}
+		if rps, ok := s.domainRPS[filters[dynamicconfig.DomainName].(string)]; ok {
+			return rps
+		}
+		return 100
+	}
+	metricsClient := metrics.NewClient(tally.NoopScope, metrics.Frontend)
+	return newDomainRateLimiter(func(opts ...dynamicconfig.FilterOption) int {
+		return s.globalRPS
+	}, domainRPS, metricsClient, s.timeSource)
+}
+
+func (s *domainRateLimiterSuite) TestDomainThrottled() {
+	s.domainRPS["noisy-domain"] = 10
+	scope := metrics.FrontendStartWorkflowExecutionScope
+
+	s.True(s.limiter.Allow("noisy-domain", scope))
+	s.False(s.limiter.Allow("noisy-domain", scope))
+	s.True(s.limiter.Allow("quiet-domain", scope))
+
+	// the requests counted without throttling still drain the bucket of the domain
+	s.limiter.Consume("quiet-domain")
+	s.True(s.limiter.Allow("quiet-domain", scope))
+}
+
+func (s *domainRateLimiterSuite) TestGlobalThrottled() {
+	s.limiter = s.newLimiter(10)
+	scope := metrics.FrontendStartWorkflowExecutionScope
+
+	s.True(s.limiter.Allow("some-domain", scope))
+	s.False(s.limiter.Allow("other-domain", scope))
+	s.False(s.limiter.Allow("", scope))
+}
+
+func (s *domainRateLimiterSuite) TestDomainRPSUpdated() {
+	s.domainRPS["some-domain"] = 10
+	scope := metrics.FrontendStartWorkflowExecutionScope
+
+	s.True(s.limiter.Allow("some-domain", scope))
+	s.False(s.limiter.Allow("some-domain", scope))
+
+	s.domainRPS["some-domain"] = 20
+	s.True(s.limiter.Allow("some-domain", scope))
+	s.True(s.limiter.Allow("some-domain", scope))
+	s.False(s.limiter.Allow("some-domain", scope))
+}
+
+func (s *domainRateLimiterSuite) TestGlobalRPSUpdated() {
+	s.limiter = s.newLimiter(10)
+	scope := metrics.FrontendStartWorkflowExecutionScope
+
+	s.True(s.limiter.Allow("some-domain", scope))
+	s.False(s.limiter.Allow("other-domain", scope))
+
+	s.globalRPS = 20
+	s.True(s.limiter.Allow("other-domain", scope))
+	s.True(s.limiter.Allow("", scope))
+	s.False(s.limiter.Allow("", scope))
+}
+
+func (s *domainRateLimiterSuite) TestIdleDomainEvicted() {
+	scope := metrics.FrontendStartWorkflowExecutionScope
+
+	s.True(s.limiter.Allow("idle-domain", scope))
+	s.True(s.limiter.Allow("busy-domain", scope))
+	s.Equal(2, len(s.limiter.domains))
+
+	s.timeSource.currTime = s.timeSource.currTime.Add(domainRateLimiterIdleTimeout / 2)
+	s.True(s.limiter.Allow("busy-domain", scope))
+	s.timeSource.currTime = s.timeSource.currTime.Add(domainRateLimiterIdleTimeout / 2)
+	s.True(s.limiter.Allow("busy-domain", scope))
+
+	s.Equal(1, len(s.limiter.domains))
+	s.NotNil(s.limiter.domains["busy-domain"])
+}
@@ -80,6 +80,8 @@ func (s *domainReplicatorSuite) TestHandleTransmissionTask_RegisterDomainTask()
 	ownerEmail := "some random test owner"
 	retention := int32(10)
 	emitMetric := true
+	archivalStatus := shared.ArchivalStatusEnabled
+	archivalBucket := "some-random-archival-bucket"
 	clusterActive := "some random active cluster name"
 	clusterStandby := "some random standby cluster name"
 	configVersion := int64(0)
@@ -102,8 +104,10 @@ func (s *domainReplicatorSuite) TestHandleTransmissionTask_RegisterDomainTask()
 		OwnerEmail:  ownerEmail,
 	}
 	config := &persistence.DomainConfig{
-		Retention:  retention,
-		EmitMetric: emitMetric,
+		Retention:      retention,
+		EmitMetric:     emitMetric,
+		ArchivalBucket: archivalBucket,
+		ArchivalStatus: archivalStatus,
 	}
 	replicationConfig := &persistence.DomainReplicationConfig{
 		ActiveClusterName: clusterActive,
@@ -124,6 +128,8 @@ func (s *domainReplicatorSuite) TestHandleTransmissionTask_RegisterDomainTask()
 			Config: &shared.DomainConfiguration{
 				WorkflowExecutionRetentionPeriodInDays: common.Int32Ptr(retention),
 				EmitMetric:                             common.BoolPtr(emitMetric),
+				ArchivalStatus:                         archivalStatus.Ptr(),
+				ArchivalBucketName:                     common.StringPtr(archivalBucket),
 			},
 			ReplicationConfig: &shared.DomainReplicationConfiguration{
 				ActiveClusterName: common.StringPtr(clusterActive),
@@ -147,6 +153,8 @@ func (s *domainReplicatorSuite) TestHandleTransmissionTask_UpdateDomainTask() {
 	ownerEmail := "some random test owner"
 	retention := int32(10)
 	emitMetric := true
+	archivalStatus := shared.ArchivalStatusEnabled
+	archivalBucket := "some-random-archival-bucket"
 	clusterActive := "some random active cluster name"
 	clusterStandby := "some random standby cluster name"
 	configVersion := int64(0)
@@ -169,8 +177,10 @@ func (s *domainReplicatorSuite) TestHandleTransmissionTask_UpdateDomainTask() {
 		OwnerEmail:  ownerEmail,
 	}
 	config := &persistence.DomainConfig{
-		Retention:  retention,
-		EmitMetric: emitMetric,
+		Retention:      retention,
+		EmitMetric:     emitMetric,
+		ArchivalBucket: archivalBucket,
+		ArchivalStatus: archivalStatus,
 	}
 	replicationConfig := &persistence.DomainReplicationConfig{
 		ActiveClusterName: clusterActive,
@@ -191,6 +201,8 @@ func (s *domainReplicatorSuite) TestHandleTransmissionTask_UpdateDomainTask() {
 			Config: &shared.DomainConfiguration{
 				WorkflowExecutionRetentionPeriodInDays: common.Int32Ptr(retention),
 				EmitMetric:                             common.BoolPtr(emitMetric),
+				ArchivalStatus:                         archivalStatus.Ptr(),
+				ArchivalBucketName:                     common.StringPtr(archivalBucket),
 			},
 			ReplicationConfig: &shared.DomainReplicationConfiguration{
 				ActiveClusterName: common.StringPtr(clusterActive),
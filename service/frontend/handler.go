@@ -24,6 +24,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 
@@ -32,6 +34,7 @@ import (
 	"github.com/pborman/uuid"
 	"github.com/uber-common/bark"
 	"github.com/uber-go/tally"
+	"github.com/uber/cadence/.gen/go/admin/adminserviceserver"
 	"github.com/uber/cadence/.gen/go/cadence/workflowserviceserver"
 	"github.com/uber/cadence/.gen/go/health"
 	"github.com/uber/cadence/.gen/go/health/metaserver"
@@ -42,34 +45,45 @@ import (
 	"github.com/uber/cadence/client/history"
 	"github.com/uber/cadence/client/matching"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/archiver"
+	"github.com/uber/cadence/common/authorization"
 	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/client"
 	"github.com/uber/cadence/common/logging"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/searchattribute"
 	"github.com/uber/cadence/common/service"
+	"github.com/uber/cadence/common/service/dynamicconfig"
+	"github.com/uber/cadence/common/visibilityquery"
+	"go.uber.org/yarpc"
 	"go.uber.org/yarpc/yarpcerrors"
 )
 
 var _ workflowserviceserver.Interface = (*WorkflowHandler)(nil)
+var _ adminserviceserver.Interface = (*WorkflowHandler)(nil)
 
 type (
 	// WorkflowHandler - Thrift handler inteface for workflow service
 	WorkflowHandler struct {
-		domainCache        cache.DomainCache
-		metadataMgr        persistence.MetadataManager
-		historyMgr         persistence.HistoryManager
-		visibitiltyMgr     persistence.VisibilityManager
-		history            history.Client
-		matching           matching.Client
-		tokenSerializer    common.TaskTokenSerializer
-		hSerializerFactory persistence.HistorySerializerFactory
-		metricsClient      metrics.Client
-		startWG            sync.WaitGroup
-		rateLimiter        common.TokenBucket
-		config             *Config
-		domainReplicator   DomainReplicator
+		domainCache         cache.DomainCache
+		metadataMgr         persistence.MetadataManager
+		historyMgr          persistence.HistoryManager
+		visibitiltyMgr      persistence.VisibilityManager
+		history             history.Client
+		matching            matching.Client
+		tokenSerializer     common.TaskTokenSerializer
+		hSerializerFactory  persistence.HistorySerializerFactory
+		metricsClient       metrics.Client
+		startWG             sync.WaitGroup
+		rateLimiter         *domainRateLimiter
+		config              *Config
+		domainReplicator    DomainReplicator
+		searchAttrValidator *searchattribute.Validator
+		archiver            archiver.Archiver
+		authorizer          authorization.Authorizer
+		identityProvider    authorization.IdentityProvider
 		service.Service
 	}
 
@@ -80,6 +94,10 @@ type (
 		IsWorkflowRunning bool
 		PersistenceToken  []byte
 		TransientDecision *gen.TransientDecisionInfo
+		// IsArchivedHistory is true when the history is read from the archiver, ArchivedHistoryPage being the
+		// next page to read
+		IsArchivedHistory   bool
+		ArchivedHistoryPage int
 	}
 )
 
@@ -99,6 +117,9 @@ var (
 	errNextPageTokenRunIDMismatch = &gen.BadRequestError{Message: "RunID in the request does not match the NextPageToken."}
 	errQueryNotSet                = &gen.BadRequestError{Message: "WorkflowQuery is not set on request."}
 	errQueryTypeNotSet            = &gen.BadRequestError{Message: "QueryType is not set on request."}
+	errReservedTaskListPrefix     = &gen.BadRequestError{Message: "TaskList name uses the reserved prefix " + common.ReservedTaskListPrefix + "."}
+	errBuildIDNotSet              = &gen.BadRequestError{Message: "BuildID is not set on request."}
+	errInvalidBuildID             = &gen.BadRequestError{Message: "BuildID must not contain \"/\"."}
 
 	// err indicating that this cluster is not the master, so cannot do domain registration or update
 	errNotMasterCluster                = &gen.BadRequestError{Message: "Cluster is not master cluster, cannot do domain registration or domain update."}
@@ -107,6 +128,19 @@ var (
 	errActiveClusterNotInClusters      = &gen.BadRequestError{Message: "Active cluster is not contained in all clusters."}
 	errCannotDoDomainFailoverAndUpdate = &gen.BadRequestError{Message: "Cannot set active cluster to current cluster when other paramaters are set."}
 
+	// err indicating that the archival config of the domain cannot be applied
+	errArchivalNotConfigured       = &gen.BadRequestError{Message: "Archival is not configured on this cluster."}
+	errArchivalBucketNotSet        = &gen.BadRequestError{Message: "Archival bucket is required to enable archival."}
+	errInvalidArchivalBucketName   = &gen.BadRequestError{Message: "Invalid archival bucket name."}
+	errCannotChangeArchivalBucket  = &gen.BadRequestError{Message: "Cannot change the archival bucket of a domain once set."}
+	errArchivedHistoryNotAvailable = &gen.BadRequestError{Message: "Archived history is not available for the domain."}
+
+	// err indicating that the visibility store of this cluster cannot serve the query based visibility APIs
+	errVisibilityQueryNotSupported = &gen.BadRequestError{Message: "Query based visibility APIs are not supported by " +
+		"the Cassandra visibility store, use ListOpenWorkflowExecutions or ListClosedWorkflowExecutions instead."}
+
+	archivalBucketNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
 	frontendServiceRetryPolicy = common.CreateFrontendServiceRetryPolicy()
 )
 
@@ -114,18 +148,23 @@ var (
 func NewWorkflowHandler(
 	sVice service.Service, config *Config, metadataMgr persistence.MetadataManager,
 	historyMgr persistence.HistoryManager, visibilityMgr persistence.VisibilityManager,
-	kafkaProducer messaging.Producer) *WorkflowHandler {
+	kafkaProducer messaging.Producer, archiver archiver.Archiver, authorizer authorization.Authorizer,
+	identityProvider authorization.IdentityProvider) *WorkflowHandler {
 	handler := &WorkflowHandler{
-		Service:            sVice,
-		config:             config,
-		metadataMgr:        metadataMgr,
-		historyMgr:         historyMgr,
-		visibitiltyMgr:     visibilityMgr,
-		tokenSerializer:    common.NewJSONTaskTokenSerializer(),
-		hSerializerFactory: persistence.NewHistorySerializerFactory(),
-		domainCache:        cache.NewDomainCache(metadataMgr, sVice.GetClusterMetadata(), sVice.GetLogger()),
-		rateLimiter:        common.NewTokenBucket(config.RPS, common.NewRealTimeSource()),
-		domainReplicator:   NewDomainReplicator(kafkaProducer, sVice.GetLogger()),
+		Service:             sVice,
+		config:              config,
+		metadataMgr:         metadataMgr,
+		historyMgr:          historyMgr,
+		visibitiltyMgr:      visibilityMgr,
+		tokenSerializer:     common.NewJSONTaskTokenSerializer(),
+		hSerializerFactory:  persistence.NewHistorySerializerFactory(),
+		domainCache:         cache.NewDomainCache(metadataMgr, sVice.GetClusterMetadata(), sVice.GetLogger()),
+		rateLimiter:         newDomainRateLimiter(config.RPS, config.DomainRPS, sVice.GetMetricsClient(), common.NewRealTimeSource()),
+		domainReplicator:    NewDomainReplicator(kafkaProducer, sVice.GetLogger()),
+		searchAttrValidator: searchattribute.NewValidator(config.ValidSearchAttributes),
+		archiver:            archiver,
+		authorizer:          authorizer,
+		identityProvider:    identityProvider,
 	}
 	// prevent us from trying to serve requests before handler's Start() is complete
 	handler.startWG.Add(1)
@@ -135,6 +174,7 @@ func NewWorkflowHandler(
 // Start starts the handler
 func (wh *WorkflowHandler) Start() error {
 	wh.Service.GetDispatcher().Register(workflowserviceserver.New(wh))
+	wh.Service.GetDispatcher().Register(adminserviceserver.New(wh))
 	wh.Service.GetDispatcher().Register(metaserver.New(wh))
 	wh.Service.Start()
 	var err error
@@ -177,6 +217,15 @@ func (wh *WorkflowHandler) RegisterDomain(ctx context.Context, registerRequest *
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
+	if err := wh.authorize(ctx, "RegisterDomain", registerRequest.GetName(), scope); err != nil {
+		return err
+	}
+
+	// the domain does not exist yet, so only the global token bucket applies
+	if ok := wh.rateLimiter.Allow("", scope); !ok {
+		return wh.error(createServiceBusyError(), scope)
+	}
+
 	clusterMetadata := wh.GetClusterMetadata()
 	// TODO remove the IsGlobalDomainEnabled check once cross DC is public
 	if clusterMetadata.IsGlobalDomainEnabled() && !clusterMetadata.IsMasterCluster() {
@@ -223,6 +272,12 @@ func (wh *WorkflowHandler) RegisterDomain(ctx context.Context, registerRequest *
 		return wh.error(err, scope)
 	}
 
+	archivalStatus := registerRequest.GetArchivalStatus()
+	archivalBucket := registerRequest.GetArchivalBucketName()
+	if err := wh.validateArchivalConfig(archivalStatus, archivalBucket); err != nil {
+		return wh.error(err, scope)
+	}
+
 	domainRequest := &persistence.CreateDomainRequest{
 		Info: &persistence.DomainInfo{
 			ID:          uuid.New(),
@@ -232,8 +287,10 @@ func (wh *WorkflowHandler) RegisterDomain(ctx context.Context, registerRequest *
 			Description: registerRequest.GetDescription(),
 		},
 		Config: &persistence.DomainConfig{
-			Retention:  registerRequest.GetWorkflowExecutionRetentionPeriodInDays(),
-			EmitMetric: registerRequest.GetEmitMetric(),
+			Retention:      registerRequest.GetWorkflowExecutionRetentionPeriodInDays(),
+			EmitMetric:     registerRequest.GetEmitMetric(),
+			ArchivalBucket: archivalBucket,
+			ArchivalStatus: archivalStatus,
 		},
 		ReplicationConfig: &persistence.DomainReplicationConfig{
 			ActiveClusterName: activeClusterName,
@@ -271,6 +328,14 @@ func (wh *WorkflowHandler) DescribeDomain(ctx context.Context,
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
+	if err := wh.authorize(ctx, "DescribeDomain", describeRequest.GetName(), scope); err != nil {
+		return nil, err
+	}
+
+	if ok := wh.rateLimiter.Allow(describeRequest.GetName(), scope); !ok {
+		return nil, wh.error(createServiceBusyError(), scope)
+	}
+
 	if describeRequest.Name == nil {
 		return nil, wh.error(errDomainNotSet, scope)
 	}
@@ -301,6 +366,14 @@ func (wh *WorkflowHandler) UpdateDomain(ctx context.Context,
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
+	if err := wh.authorize(ctx, "UpdateDomain", updateRequest.GetName(), scope); err != nil {
+		return nil, err
+	}
+
+	if ok := wh.rateLimiter.Allow(updateRequest.GetName(), scope); !ok {
+		return nil, wh.error(createServiceBusyError(), scope)
+	}
+
 	clusterMetadata := wh.GetClusterMetadata()
 	// TODO remove the IsGlobalDomainEnabled check once cross DC is public
 	if !clusterMetadata.IsGlobalDomainEnabled() {
@@ -407,6 +480,25 @@ func (wh *WorkflowHandler) UpdateDomain(ctx context.Context,
 			configurationChanged = true
 			config.Retention = updatedConfig.GetWorkflowExecutionRetentionPeriodInDays()
 		}
+		if updatedConfig.ArchivalBucketName != nil || updatedConfig.ArchivalStatus != nil {
+			archivalStatus := config.ArchivalStatus
+			if updatedConfig.ArchivalStatus != nil {
+				archivalStatus = updatedConfig.GetArchivalStatus()
+			}
+			archivalBucket := config.ArchivalBucket
+			if updatedConfig.ArchivalBucketName != nil {
+				if len(archivalBucket) != 0 && archivalBucket != updatedConfig.GetArchivalBucketName() {
+					return nil, wh.error(errCannotChangeArchivalBucket, scope)
+				}
+				archivalBucket = updatedConfig.GetArchivalBucketName()
+			}
+			if err := wh.validateArchivalConfig(archivalStatus, archivalBucket); err != nil {
+				return nil, wh.error(err, scope)
+			}
+			configurationChanged = true
+			config.ArchivalStatus = archivalStatus
+			config.ArchivalBucket = archivalBucket
+		}
 	}
 	if updateRequest.ReplicationConfiguration != nil {
 		updateReplicationConfig := updateRequest.ReplicationConfiguration
@@ -475,6 +567,14 @@ func (wh *WorkflowHandler) DeprecateDomain(ctx context.Context, deprecateRequest
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
+	if err := wh.authorize(ctx, "DeprecateDomain", deprecateRequest.GetName(), scope); err != nil {
+		return err
+	}
+
+	if ok := wh.rateLimiter.Allow(deprecateRequest.GetName(), scope); !ok {
+		return wh.error(createServiceBusyError(), scope)
+	}
+
 	clusterMetadata := wh.GetClusterMetadata()
 	// TODO remove the IsGlobalDomainEnabled check once cross DC is public
 	if clusterMetadata.IsGlobalDomainEnabled() && !clusterMetadata.IsMasterCluster() {
@@ -518,7 +618,11 @@ func (wh *WorkflowHandler) PollForActivityTask(
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	if ok, _ := wh.rateLimiter.TryConsume(1); !ok {
+	if err := wh.authorize(ctx, "PollForActivityTask", pollRequest.GetDomain(), scope); err != nil {
+		return nil, err
+	}
+
+	if ok := wh.rateLimiter.Allow(pollRequest.GetDomain(), scope); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
@@ -531,12 +635,18 @@ func (wh *WorkflowHandler) PollForActivityTask(
 		return nil, err
 	}
 
+	if err := wh.validateBuildID(pollRequest.GetBuildID(), scope); err != nil {
+		return nil, err
+	}
+
 	domainID, err := wh.domainCache.GetDomainID(pollRequest.GetDomain())
 	if err != nil {
 		return nil, wh.error(err, scope)
 	}
 
 	pollerID := uuid.New()
+	// pass the IP address of the worker on to matching to record it in the poller history
+	callerIP := yarpc.WithHeader(common.CallerIPHeaderName, common.GetCallerIP(ctx))
 	var resp *gen.PollForActivityTaskResponse
 	op := func() error {
 		var err error
@@ -544,7 +654,7 @@ func (wh *WorkflowHandler) PollForActivityTask(
 			DomainUUID:  common.StringPtr(domainID),
 			PollerID:    common.StringPtr(pollerID),
 			PollRequest: pollRequest,
-		})
+		}, callerIP)
 		return err
 	}
 
@@ -570,7 +680,11 @@ func (wh *WorkflowHandler) PollForDecisionTask(
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	if ok, _ := wh.rateLimiter.TryConsume(1); !ok {
+	if err := wh.authorize(ctx, "PollForDecisionTask", pollRequest.GetDomain(), scope); err != nil {
+		return nil, err
+	}
+
+	if ok := wh.rateLimiter.Allow(pollRequest.GetDomain(), scope); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
@@ -583,6 +697,10 @@ func (wh *WorkflowHandler) PollForDecisionTask(
 		return nil, err
 	}
 
+	if err := wh.validateBuildID(pollRequest.GetBuildID(), scope); err != nil {
+		return nil, err
+	}
+
 	domainName := pollRequest.GetDomain()
 	domainID, err := wh.domainCache.GetDomainID(domainName)
 	if err != nil {
@@ -592,6 +710,8 @@ func (wh *WorkflowHandler) PollForDecisionTask(
 	wh.Service.GetLogger().Debugf("Poll for decision. DomainName: %v, DomainID: %v", domainName, domainID)
 
 	pollerID := uuid.New()
+	// pass the IP address of the worker on to matching to record it in the poller history
+	callerIP := yarpc.WithHeader(common.CallerIPHeaderName, common.GetCallerIP(ctx))
 	var matchingResp *m.PollForDecisionTaskResponse
 	op := func() error {
 		var err error
@@ -599,7 +719,7 @@ func (wh *WorkflowHandler) PollForDecisionTask(
 			DomainUUID:  common.StringPtr(domainID),
 			PollerID:    common.StringPtr(pollerID),
 			PollRequest: pollRequest,
-		})
+		}, callerIP)
 		return err
 	}
 
@@ -617,7 +737,7 @@ func (wh *WorkflowHandler) PollForDecisionTask(
 		return nil, nil
 	}
 
-	resp, err := wh.createPollForDecisionTaskResponse(ctx, domainID, matchingResp)
+	resp, err := wh.createPollForDecisionTaskResponse(ctx, domainName, domainID, matchingResp)
 	if err != nil {
 		return nil, wh.error(err, scope)
 	}
@@ -658,9 +778,6 @@ func (wh *WorkflowHandler) RecordActivityTaskHeartbeat(
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.rateLimiter.TryConsume(1)
-
 	wh.Service.GetLogger().Debug("Received RecordActivityTaskHeartbeat")
 	if heartbeatRequest.TaskToken == nil {
 		return nil, wh.error(errTaskTokenNotSet, scope)
@@ -672,6 +789,12 @@ func (wh *WorkflowHandler) RecordActivityTaskHeartbeat(
 	if taskToken.DomainID == "" {
 		return nil, wh.error(errDomainNotSet, scope)
 	}
+	if err := wh.authorizeByDomainID(ctx, "RecordActivityTaskHeartbeat", taskToken.DomainID, scope); err != nil {
+		return nil, err
+	}
+
+	// Count the request in the RPS, but we still accept it even if RPS is exceeded
+	wh.consumeByDomainID(taskToken.DomainID)
 
 	resp, err := wh.history.RecordActivityTaskHeartbeat(ctx, &h.RecordActivityTaskHeartbeatRequest{
 		DomainUUID:       common.StringPtr(taskToken.DomainID),
@@ -692,8 +815,12 @@ func (wh *WorkflowHandler) RecordActivityTaskHeartbeatByID(
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
+	if err := wh.authorize(ctx, "RecordActivityTaskHeartbeatByID", heartbeatRequest.GetDomain(), scope); err != nil {
+		return nil, err
+	}
+
 	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.rateLimiter.TryConsume(1)
+	wh.rateLimiter.Consume(heartbeatRequest.GetDomain())
 
 	wh.Service.GetLogger().Debug("Received RecordActivityTaskHeartbeatByID")
 	domainID, err := wh.domainCache.GetDomainID(heartbeatRequest.GetDomain())
@@ -751,9 +878,6 @@ func (wh *WorkflowHandler) RespondActivityTaskCompleted(
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.rateLimiter.TryConsume(1)
-
 	if completeRequest.TaskToken == nil {
 		return wh.error(errTaskTokenNotSet, scope)
 	}
@@ -764,6 +888,12 @@ func (wh *WorkflowHandler) RespondActivityTaskCompleted(
 	if taskToken.DomainID == "" {
 		return wh.error(errDomainNotSet, scope)
 	}
+	if err := wh.authorizeByDomainID(ctx, "RespondActivityTaskCompleted", taskToken.DomainID, scope); err != nil {
+		return err
+	}
+
+	// Count the request in the RPS, but we still accept it even if RPS is exceeded
+	wh.consumeByDomainID(taskToken.DomainID)
 
 	err = wh.history.RespondActivityTaskCompleted(ctx, &h.RespondActivityTaskCompletedRequest{
 		DomainUUID:      common.StringPtr(taskToken.DomainID),
@@ -784,8 +914,12 @@ func (wh *WorkflowHandler) RespondActivityTaskCompletedByID(
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
+	if err := wh.authorize(ctx, "RespondActivityTaskCompletedByID", completeRequest.GetDomain(), scope); err != nil {
+		return err
+	}
+
 	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.rateLimiter.TryConsume(1)
+	wh.rateLimiter.Consume(completeRequest.GetDomain())
 
 	domainID, err := wh.domainCache.GetDomainID(completeRequest.GetDomain())
 	if err != nil {
@@ -842,9 +976,6 @@ func (wh *WorkflowHandler) RespondActivityTaskFailed(
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.rateLimiter.TryConsume(1)
-
 	if failedRequest.TaskToken == nil {
 		return wh.error(errTaskTokenNotSet, scope)
 	}
@@ -855,6 +986,12 @@ func (wh *WorkflowHandler) RespondActivityTaskFailed(
 	if taskToken.DomainID == "" {
 		return wh.error(errDomainNotSet, scope)
 	}
+	if err := wh.authorizeByDomainID(ctx, "RespondActivityTaskFailed", taskToken.DomainID, scope); err != nil {
+		return err
+	}
+
+	// Count the request in the RPS, but we still accept it even if RPS is exceeded
+	wh.consumeByDomainID(taskToken.DomainID)
 
 	err = wh.history.RespondActivityTaskFailed(ctx, &h.RespondActivityTaskFailedRequest{
 		DomainUUID:    common.StringPtr(taskToken.DomainID),
@@ -875,8 +1012,12 @@ func (wh *WorkflowHandler) RespondActivityTaskFailedByID(
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
+	if err := wh.authorize(ctx, "RespondActivityTaskFailedByID", failedRequest.GetDomain(), scope); err != nil {
+		return err
+	}
+
 	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.rateLimiter.TryConsume(1)
+	wh.rateLimiter.Consume(failedRequest.GetDomain())
 
 	domainID, err := wh.domainCache.GetDomainID(failedRequest.GetDomain())
 	if err != nil {
@@ -934,9 +1075,6 @@ func (wh *WorkflowHandler) RespondActivityTaskCanceled(
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.rateLimiter.TryConsume(1)
-
 	if cancelRequest.TaskToken == nil {
 		return wh.error(errTaskTokenNotSet, scope)
 	}
@@ -947,6 +1085,12 @@ func (wh *WorkflowHandler) RespondActivityTaskCanceled(
 	if taskToken.DomainID == "" {
 		return wh.error(errDomainNotSet, scope)
 	}
+	if err := wh.authorizeByDomainID(ctx, "RespondActivityTaskCanceled", taskToken.DomainID, scope); err != nil {
+		return err
+	}
+
+	// Count the request in the RPS, but we still accept it even if RPS is exceeded
+	wh.consumeByDomainID(taskToken.DomainID)
 
 	err = wh.history.RespondActivityTaskCanceled(ctx, &h.RespondActivityTaskCanceledRequest{
 		DomainUUID:    common.StringPtr(taskToken.DomainID),
@@ -967,8 +1111,12 @@ func (wh *WorkflowHandler) RespondActivityTaskCanceledByID(
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
+	if err := wh.authorize(ctx, "RespondActivityTaskCanceledByID", cancelRequest.GetDomain(), scope); err != nil {
+		return err
+	}
+
 	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.rateLimiter.TryConsume(1)
+	wh.rateLimiter.Consume(cancelRequest.GetDomain())
 
 	domainID, err := wh.domainCache.GetDomainID(cancelRequest.GetDomain())
 	if err != nil {
@@ -1025,9 +1173,6 @@ func (wh *WorkflowHandler) RespondDecisionTaskCompleted(
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.rateLimiter.TryConsume(1)
-
 	if completeRequest.TaskToken == nil {
 		return wh.error(errTaskTokenNotSet, scope)
 	}
@@ -1038,6 +1183,12 @@ func (wh *WorkflowHandler) RespondDecisionTaskCompleted(
 	if taskToken.DomainID == "" {
 		return wh.error(errDomainNotSet, scope)
 	}
+	if err := wh.authorizeByDomainID(ctx, "RespondDecisionTaskCompleted", taskToken.DomainID, scope); err != nil {
+		return err
+	}
+
+	// Count the request in the RPS, but we still accept it even if RPS is exceeded
+	wh.consumeByDomainID(taskToken.DomainID)
 
 	err = wh.history.RespondDecisionTaskCompleted(ctx, &h.RespondDecisionTaskCompletedRequest{
 		DomainUUID:      common.StringPtr(taskToken.DomainID),
@@ -1058,9 +1209,6 @@ func (wh *WorkflowHandler) RespondDecisionTaskFailed(
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.rateLimiter.TryConsume(1)
-
 	if failedRequest.TaskToken == nil {
 		return wh.error(errTaskTokenNotSet, scope)
 	}
@@ -1071,6 +1219,12 @@ func (wh *WorkflowHandler) RespondDecisionTaskFailed(
 	if taskToken.DomainID == "" {
 		return wh.error(errDomainNotSet, scope)
 	}
+	if err := wh.authorizeByDomainID(ctx, "RespondDecisionTaskFailed", taskToken.DomainID, scope); err != nil {
+		return err
+	}
+
+	// Count the request in the RPS, but we still accept it even if RPS is exceeded
+	wh.consumeByDomainID(taskToken.DomainID)
 
 	err = wh.history.RespondDecisionTaskFailed(ctx, &h.RespondDecisionTaskFailedRequest{
 		DomainUUID:    common.StringPtr(taskToken.DomainID),
@@ -1091,9 +1245,6 @@ func (wh *WorkflowHandler) RespondQueryTaskCompleted(
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	// Count the request in the RPS, but we still accept it even if RPS is exceeded
-	wh.rateLimiter.TryConsume(1)
-
 	if completeRequest.TaskToken == nil {
 		return wh.error(errTaskTokenNotSet, scope)
 	}
@@ -1104,6 +1255,12 @@ func (wh *WorkflowHandler) RespondQueryTaskCompleted(
 	if queryTaskToken.DomainID == "" || queryTaskToken.TaskList == "" || queryTaskToken.TaskID == "" {
 		return wh.error(errInvalidTaskToken, scope)
 	}
+	if err := wh.authorizeByDomainID(ctx, "RespondQueryTaskCompleted", queryTaskToken.DomainID, scope); err != nil {
+		return err
+	}
+
+	// Count the request in the RPS, but we still accept it even if RPS is exceeded
+	wh.consumeByDomainID(queryTaskToken.DomainID)
 
 	matchingRequest := &m.RespondQueryTaskCompletedRequest{
 		DomainUUID:       common.StringPtr(queryTaskToken.DomainID),
@@ -1128,7 +1285,11 @@ func (wh *WorkflowHandler) StartWorkflowExecution(
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	if ok, _ := wh.rateLimiter.TryConsume(1); !ok {
+	if err := wh.authorize(ctx, "StartWorkflowExecution", startRequest.GetDomain(), scope); err != nil {
+		return nil, err
+	}
+
+	if ok := wh.rateLimiter.Allow(startRequest.GetDomain(), scope); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
@@ -1165,6 +1326,12 @@ func (wh *WorkflowHandler) StartWorkflowExecution(
 			Message: "A valid TaskStartToCloseTimeoutSeconds is not set on request."}, scope)
 	}
 
+	searchAttributes, err := wh.searchAttrValidator.ValidateSearchAttributes(startRequest.SearchAttributes)
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+	startRequest.SearchAttributes = searchAttributes
+
 	domainName := startRequest.GetDomain()
 	wh.Service.GetLogger().Debugf("Start workflow execution request domain: %v", domainName)
 	domainID, err := wh.domainCache.GetDomainID(domainName)
@@ -1193,7 +1360,11 @@ func (wh *WorkflowHandler) GetWorkflowExecutionHistory(
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	if ok, _ := wh.rateLimiter.TryConsume(1); !ok {
+	if err := wh.authorize(ctx, "GetWorkflowExecutionHistory", getRequest.GetDomain(), scope); err != nil {
+		return nil, err
+	}
+
+	if ok := wh.rateLimiter.Allow(getRequest.GetDomain(), scope); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
@@ -1206,7 +1377,7 @@ func (wh *WorkflowHandler) GetWorkflowExecutionHistory(
 	}
 
 	if getRequest.MaximumPageSize == nil || *getRequest.MaximumPageSize == 0 {
-		getRequest.MaximumPageSize = common.Int32Ptr(wh.config.DefaultHistoryMaxPageSize)
+		getRequest.MaximumPageSize = common.Int32Ptr(int32(wh.config.DefaultHistoryMaxPageSize(dynamicconfig.DomainFilter(getRequest.GetDomain()))))
 	}
 
 	domainID, err := wh.domainCache.GetDomainID(getRequest.GetDomain())
@@ -1256,6 +1427,18 @@ func (wh *WorkflowHandler) GetWorkflowExecutionHistory(
 
 		execution.RunId = common.StringPtr(token.RunID)
 
+		if token.IsArchivedHistory {
+			bucket, err := wh.getArchivalBucket(domainID)
+			if err != nil {
+				return nil, wh.error(err, scope)
+			}
+			response, err := wh.getArchivedHistory(domainID, bucket, *execution, token.ArchivedHistoryPage, isCloseEventOnly)
+			if err != nil {
+				return nil, wh.error(err, scope)
+			}
+			return response, nil
+		}
+
 		// we need to update the current next event ID and whether workflow is running
 		if len(token.PersistenceToken) == 0 && isLongPoll && token.IsWorkflowRunning {
 			if !isCloseEventOnly {
@@ -1276,6 +1459,16 @@ func (wh *WorkflowHandler) GetWorkflowExecutionHistory(
 		}
 		runID, lastFirstEventID, nextEventID, isWorkflowRunning, err = queryHistory(domainID, execution, queryNextEventID)
 		if err != nil {
+			// the history of a closed execution may already be deleted by the retention, but still be archived
+			if _, ok := err.(*gen.EntityNotExistsError); ok && execution.GetRunId() != "" {
+				if bucket, bucketErr := wh.getArchivalBucket(domainID); bucketErr == nil {
+					response, err := wh.getArchivedHistory(domainID, bucket, *execution, 0, isCloseEventOnly)
+					if err != nil {
+						return nil, wh.error(err, scope)
+					}
+					return response, nil
+				}
+			}
 			return nil, wh.error(err, scope)
 		}
 
@@ -1347,7 +1540,11 @@ func (wh *WorkflowHandler) SignalWorkflowExecution(ctx context.Context,
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	if ok, _ := wh.rateLimiter.TryConsume(1); !ok {
+	if err := wh.authorize(ctx, "SignalWorkflowExecution", signalRequest.GetDomain(), scope); err != nil {
+		return err
+	}
+
+	if ok := wh.rateLimiter.Allow(signalRequest.GetDomain(), scope); !ok {
 		return wh.error(createServiceBusyError(), scope)
 	}
 
@@ -1379,6 +1576,78 @@ func (wh *WorkflowHandler) SignalWorkflowExecution(ctx context.Context,
 	return nil
 }
 
+// SignalWithStartWorkflowExecution is used to ensure sending a signal event to a workflow execution.
+// If workflow is running, this results in WorkflowExecutionSignaled event recorded in the history
+// and a decision task being created for the execution.
+// If workflow is not running or not found, this results in WorkflowExecutionStarted and WorkflowExecutionSignaled
+// event recorded in history, and a decision task being created for the execution
+func (wh *WorkflowHandler) SignalWithStartWorkflowExecution(ctx context.Context,
+	signalWithStartRequest *gen.SignalWithStartWorkflowExecutionRequest) (*gen.StartWorkflowExecutionResponse, error) {
+
+	scope := metrics.FrontendSignalWithStartWorkflowExecutionScope
+	sw := wh.startRequestProfile(scope)
+	defer sw.Stop()
+
+	if err := wh.authorize(ctx, "SignalWithStartWorkflowExecution", signalWithStartRequest.GetDomain(), scope); err != nil {
+		return nil, err
+	}
+
+	if ok := wh.rateLimiter.Allow(signalWithStartRequest.GetDomain(), scope); !ok {
+		return nil, wh.error(createServiceBusyError(), scope)
+	}
+
+	if signalWithStartRequest.Domain == nil {
+		return nil, wh.error(errDomainNotSet, scope)
+	}
+
+	if signalWithStartRequest.GetWorkflowId() == "" {
+		return nil, wh.error(&gen.BadRequestError{Message: "WorkflowId is not set on request."}, scope)
+	}
+
+	if signalWithStartRequest.SignalName == nil || signalWithStartRequest.GetSignalName() == "" {
+		return nil, wh.error(&gen.BadRequestError{Message: "SignalName is not set on request."}, scope)
+	}
+
+	if signalWithStartRequest.WorkflowType == nil || signalWithStartRequest.WorkflowType.GetName() == "" {
+		return nil, wh.error(&gen.BadRequestError{Message: "WorkflowType is not set on request."}, scope)
+	}
+
+	if err := wh.validateTaskList(signalWithStartRequest.TaskList, scope); err != nil {
+		return nil, err
+	}
+
+	if signalWithStartRequest.GetExecutionStartToCloseTimeoutSeconds() <= 0 {
+		return nil, wh.error(&gen.BadRequestError{
+			Message: "A valid ExecutionStartToCloseTimeoutSeconds is not set on request."}, scope)
+	}
+
+	if signalWithStartRequest.GetTaskStartToCloseTimeoutSeconds() <= 0 {
+		return nil, wh.error(&gen.BadRequestError{
+			Message: "A valid TaskStartToCloseTimeoutSeconds is not set on request."}, scope)
+	}
+
+	searchAttributes, err := wh.searchAttrValidator.ValidateSearchAttributes(signalWithStartRequest.SearchAttributes)
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+	signalWithStartRequest.SearchAttributes = searchAttributes
+
+	domainID, err := wh.domainCache.GetDomainID(signalWithStartRequest.GetDomain())
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+
+	resp, err := wh.history.SignalWithStartWorkflowExecution(ctx, &h.SignalWithStartWorkflowExecutionRequest{
+		DomainUUID:             common.StringPtr(domainID),
+		SignalWithStartRequest: signalWithStartRequest,
+	})
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+
+	return resp, nil
+}
+
 // TerminateWorkflowExecution terminates an existing workflow execution by recording WorkflowExecutionTerminated event
 // in the history and immediately terminating the execution instance.
 func (wh *WorkflowHandler) TerminateWorkflowExecution(ctx context.Context,
@@ -1388,7 +1657,11 @@ func (wh *WorkflowHandler) TerminateWorkflowExecution(ctx context.Context,
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	if ok, _ := wh.rateLimiter.TryConsume(1); !ok {
+	if err := wh.authorize(ctx, "TerminateWorkflowExecution", terminateRequest.GetDomain(), scope); err != nil {
+		return err
+	}
+
+	if ok := wh.rateLimiter.Allow(terminateRequest.GetDomain(), scope); !ok {
 		return wh.error(createServiceBusyError(), scope)
 	}
 
@@ -1416,6 +1689,56 @@ func (wh *WorkflowHandler) TerminateWorkflowExecution(ctx context.Context,
 	return nil
 }
 
+// ResetWorkflowExecution resets an existing workflow execution to the given DecisionTaskCompleted event (exclusive).
+// It starts a new run from a copy of the history up to that point, re-applies the signals received after it and
+// terminates the current run of the execution.
+func (wh *WorkflowHandler) ResetWorkflowExecution(ctx context.Context,
+	resetRequest *gen.ResetWorkflowExecutionRequest) (*gen.ResetWorkflowExecutionResponse, error) {
+
+	scope := metrics.FrontendResetWorkflowExecutionScope
+	sw := wh.startRequestProfile(scope)
+	defer sw.Stop()
+
+	if err := wh.authorize(ctx, "ResetWorkflowExecution", resetRequest.GetDomain(), scope); err != nil {
+		return nil, err
+	}
+
+	if ok := wh.rateLimiter.Allow(resetRequest.GetDomain(), scope); !ok {
+		return nil, wh.error(createServiceBusyError(), scope)
+	}
+
+	if resetRequest.Domain == nil {
+		return nil, wh.error(errDomainNotSet, scope)
+	}
+
+	if err := wh.validateExecution(resetRequest.WorkflowExecution, scope); err != nil {
+		return nil, err
+	}
+
+	if resetRequest.GetDecisionFinishEventId() <= common.FirstEventID {
+		return nil, wh.error(&gen.BadRequestError{Message: "A valid DecisionFinishEventId is not set on request."}, scope)
+	}
+
+	if resetRequest.GetRequestId() == "" {
+		return nil, wh.error(&gen.BadRequestError{Message: "RequestId is not set on request."}, scope)
+	}
+
+	domainID, err := wh.domainCache.GetDomainID(resetRequest.GetDomain())
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+
+	resp, err := wh.history.ResetWorkflowExecution(ctx, &h.ResetWorkflowExecutionRequest{
+		DomainUUID:   common.StringPtr(domainID),
+		ResetRequest: resetRequest,
+	})
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+
+	return resp, nil
+}
+
 // RequestCancelWorkflowExecution - requests to cancel a workflow execution
 func (wh *WorkflowHandler) RequestCancelWorkflowExecution(
 	ctx context.Context,
@@ -1425,7 +1748,11 @@ func (wh *WorkflowHandler) RequestCancelWorkflowExecution(
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	if ok, _ := wh.rateLimiter.TryConsume(1); !ok {
+	if err := wh.authorize(ctx, "RequestCancelWorkflowExecution", cancelRequest.GetDomain(), scope); err != nil {
+		return err
+	}
+
+	if ok := wh.rateLimiter.Allow(cancelRequest.GetDomain(), scope); !ok {
 		return wh.error(createServiceBusyError(), scope)
 	}
 
@@ -1461,7 +1788,11 @@ func (wh *WorkflowHandler) ListOpenWorkflowExecutions(ctx context.Context,
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	if ok, _ := wh.rateLimiter.TryConsume(1); !ok {
+	if err := wh.authorize(ctx, "ListOpenWorkflowExecutions", listRequest.GetDomain(), scope); err != nil {
+		return nil, err
+	}
+
+	if ok := wh.rateLimiter.Allow(listRequest.GetDomain(), scope); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
@@ -1481,13 +1812,29 @@ func (wh *WorkflowHandler) ListOpenWorkflowExecutions(ctx context.Context,
 		return nil, wh.error(&gen.BadRequestError{Message: "LatestTime in StartTimeFilter is required"}, scope)
 	}
 
-	if listRequest.ExecutionFilter != nil && listRequest.TypeFilter != nil {
+	filterCount := 0
+	if listRequest.ExecutionFilter != nil {
+		filterCount++
+	}
+	if listRequest.TypeFilter != nil {
+		filterCount++
+	}
+	if listRequest.SearchAttributesFilter != nil {
+		filterCount++
+	}
+
+	if filterCount > 1 {
 		return nil, wh.error(&gen.BadRequestError{
-			Message: "Only one of ExecutionFilter or TypeFilter is allowed"}, scope)
+			Message: "Only one of ExecutionFilter, TypeFilter or SearchAttributesFilter is allowed"}, scope)
+	}
+
+	searchAttributesFilter, err := wh.validateSearchAttributesFilter(listRequest.SearchAttributesFilter, scope)
+	if err != nil {
+		return nil, err
 	}
 
 	if listRequest.MaximumPageSize == nil || *listRequest.MaximumPageSize == 0 {
-		listRequest.MaximumPageSize = common.Int32Ptr(wh.config.DefaultVisibilityMaxPageSize)
+		listRequest.MaximumPageSize = common.Int32Ptr(int32(wh.config.DefaultVisibilityMaxPageSize(dynamicconfig.DomainFilter(listRequest.GetDomain()))))
 	}
 
 	domainID, err := wh.domainCache.GetDomainID(listRequest.GetDomain())
@@ -1515,6 +1862,12 @@ func (wh *WorkflowHandler) ListOpenWorkflowExecutions(ctx context.Context,
 			ListWorkflowExecutionsRequest: baseReq,
 			WorkflowTypeName:              listRequest.TypeFilter.GetName(),
 		})
+	} else if searchAttributesFilter != nil {
+		persistenceResp, err = wh.visibitiltyMgr.ListOpenWorkflowExecutionsBySearchAttributes(
+			&persistence.ListWorkflowExecutionsBySearchAttributesRequest{
+				ListWorkflowExecutionsRequest: baseReq,
+				SearchAttributes:              searchAttributesFilter.GetIndexedFields(),
+			})
 	} else {
 		persistenceResp, err = wh.visibitiltyMgr.ListOpenWorkflowExecutions(&baseReq)
 	}
@@ -1537,7 +1890,11 @@ func (wh *WorkflowHandler) ListClosedWorkflowExecutions(ctx context.Context,
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	if ok, _ := wh.rateLimiter.TryConsume(1); !ok {
+	if err := wh.authorize(ctx, "ListClosedWorkflowExecutions", listRequest.GetDomain(), scope); err != nil {
+		return nil, err
+	}
+
+	if ok := wh.rateLimiter.Allow(listRequest.GetDomain(), scope); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
@@ -1567,14 +1924,22 @@ func (wh *WorkflowHandler) ListClosedWorkflowExecutions(ctx context.Context,
 	if listRequest.StatusFilter != nil {
 		filterCount++
 	}
+	if listRequest.SearchAttributesFilter != nil {
+		filterCount++
+	}
 
 	if filterCount > 1 {
 		return nil, wh.error(&gen.BadRequestError{
-			Message: "Only one of ExecutionFilter, TypeFilter or StatusFilter is allowed"}, scope)
+			Message: "Only one of ExecutionFilter, TypeFilter, StatusFilter or SearchAttributesFilter is allowed"}, scope)
+	}
+
+	searchAttributesFilter, err := wh.validateSearchAttributesFilter(listRequest.SearchAttributesFilter, scope)
+	if err != nil {
+		return nil, err
 	}
 
 	if listRequest.MaximumPageSize == nil || *listRequest.MaximumPageSize == 0 {
-		listRequest.MaximumPageSize = common.Int32Ptr(wh.config.DefaultVisibilityMaxPageSize)
+		listRequest.MaximumPageSize = common.Int32Ptr(int32(wh.config.DefaultVisibilityMaxPageSize(dynamicconfig.DomainFilter(listRequest.GetDomain()))))
 	}
 
 	domainID, err := wh.domainCache.GetDomainID(listRequest.GetDomain())
@@ -1605,8 +1970,14 @@ func (wh *WorkflowHandler) ListClosedWorkflowExecutions(ctx context.Context,
 	} else if listRequest.StatusFilter != nil {
 		persistenceResp, err = wh.visibitiltyMgr.ListClosedWorkflowExecutionsByStatus(&persistence.ListClosedWorkflowExecutionsByStatusRequest{
 			ListWorkflowExecutionsRequest: baseReq,
-			Status: listRequest.GetStatusFilter(),
+			Status:                        listRequest.GetStatusFilter(),
 		})
+	} else if searchAttributesFilter != nil {
+		persistenceResp, err = wh.visibitiltyMgr.ListClosedWorkflowExecutionsBySearchAttributes(
+			&persistence.ListWorkflowExecutionsBySearchAttributesRequest{
+				ListWorkflowExecutionsRequest: baseReq,
+				SearchAttributes:              searchAttributesFilter.GetIndexedFields(),
+			})
 	} else {
 		persistenceResp, err = wh.visibitiltyMgr.ListClosedWorkflowExecutions(&baseReq)
 	}
@@ -1621,20 +1992,130 @@ func (wh *WorkflowHandler) ListClosedWorkflowExecutions(ctx context.Context,
 	return resp, nil
 }
 
-// QueryWorkflow returns query result for a specified workflow execution
-func (wh *WorkflowHandler) QueryWorkflow(ctx context.Context,
-	queryRequest *gen.QueryWorkflowRequest) (*gen.QueryWorkflowResponse, error) {
+// ListWorkflowExecutions - retrieves info for the open and closed workflow executions in a domain which match a query.
+// Queries are only supported by the SQL, BoltDB and in-memory visibility stores, clusters which keep visibility
+// records in Cassandra reject the request with a BadRequestError.
+func (wh *WorkflowHandler) ListWorkflowExecutions(ctx context.Context,
+	listRequest *gen.ListWorkflowExecutionsRequest) (*gen.ListWorkflowExecutionsResponse, error) {
 
-	scope := metrics.FrontendQueryWorkflowScope
+	scope := metrics.FrontendListWorkflowExecutionsScope
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	if queryRequest.Domain == nil {
-		return nil, wh.error(errDomainNotSet, scope)
+	if err := wh.authorize(ctx, "ListWorkflowExecutions", listRequest.GetDomain(), scope); err != nil {
+		return nil, err
 	}
 
-	if queryRequest.Execution == nil {
-		return nil, wh.error(errExecutionNotSet, scope)
+	if ok := wh.rateLimiter.Allow(listRequest.GetDomain(), scope); !ok {
+		return nil, wh.error(createServiceBusyError(), scope)
+	}
+
+	if listRequest.Domain == nil {
+		return nil, wh.error(errDomainNotSet, scope)
+	}
+
+	if !wh.config.VisibilityQuerySupported {
+		return nil, wh.error(errVisibilityQueryNotSupported, scope)
+	}
+
+	if listRequest.GetPageSize() <= 0 {
+		listRequest.PageSize = common.Int32Ptr(int32(wh.config.DefaultVisibilityMaxPageSize(dynamicconfig.DomainFilter(listRequest.GetDomain()))))
+	}
+
+	query, err := visibilityquery.Parse(listRequest.GetQuery())
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+
+	domainID, err := wh.domainCache.GetDomainID(listRequest.GetDomain())
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+
+	persistenceResp, err := wh.visibitiltyMgr.ListWorkflowExecutions(&persistence.ListWorkflowExecutionsByQueryRequest{
+		DomainUUID:    domainID,
+		Query:         query,
+		PageSize:      int(listRequest.GetPageSize()),
+		NextPageToken: listRequest.NextPageToken,
+	})
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+
+	resp := &gen.ListWorkflowExecutionsResponse{}
+	resp.Executions = persistenceResp.Executions
+	resp.NextPageToken = persistenceResp.NextPageToken
+	return resp, nil
+}
+
+// CountWorkflowExecutions - counts the open and closed workflow executions in a domain which match a query.  Like
+// ListWorkflowExecutions, it is not supported by the Cassandra visibility store.
+func (wh *WorkflowHandler) CountWorkflowExecutions(ctx context.Context,
+	countRequest *gen.CountWorkflowExecutionsRequest) (*gen.CountWorkflowExecutionsResponse, error) {
+
+	scope := metrics.FrontendCountWorkflowExecutionsScope
+	sw := wh.startRequestProfile(scope)
+	defer sw.Stop()
+
+	if err := wh.authorize(ctx, "CountWorkflowExecutions", countRequest.GetDomain(), scope); err != nil {
+		return nil, err
+	}
+
+	if ok := wh.rateLimiter.Allow(countRequest.GetDomain(), scope); !ok {
+		return nil, wh.error(createServiceBusyError(), scope)
+	}
+
+	if countRequest.Domain == nil {
+		return nil, wh.error(errDomainNotSet, scope)
+	}
+
+	if !wh.config.VisibilityQuerySupported {
+		return nil, wh.error(errVisibilityQueryNotSupported, scope)
+	}
+
+	query, err := visibilityquery.Parse(countRequest.GetQuery())
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+
+	domainID, err := wh.domainCache.GetDomainID(countRequest.GetDomain())
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+
+	persistenceResp, err := wh.visibitiltyMgr.CountWorkflowExecutions(&persistence.CountWorkflowExecutionsRequest{
+		DomainUUID: domainID,
+		Query:      query,
+	})
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+
+	return &gen.CountWorkflowExecutionsResponse{Count: common.Int64Ptr(persistenceResp.Count)}, nil
+}
+
+// QueryWorkflow returns query result for a specified workflow execution
+func (wh *WorkflowHandler) QueryWorkflow(ctx context.Context,
+	queryRequest *gen.QueryWorkflowRequest) (*gen.QueryWorkflowResponse, error) {
+
+	scope := metrics.FrontendQueryWorkflowScope
+	sw := wh.startRequestProfile(scope)
+	defer sw.Stop()
+
+	if err := wh.authorize(ctx, "QueryWorkflow", queryRequest.GetDomain(), scope); err != nil {
+		return nil, err
+	}
+
+	if ok := wh.rateLimiter.Allow(queryRequest.GetDomain(), scope); !ok {
+		return nil, wh.error(createServiceBusyError(), scope)
+	}
+
+	if queryRequest.Domain == nil {
+		return nil, wh.error(errDomainNotSet, scope)
+	}
+
+	if queryRequest.Execution == nil {
+		return nil, wh.error(errExecutionNotSet, scope)
 	}
 
 	if queryRequest.Execution.WorkflowId == nil {
@@ -1730,7 +2211,11 @@ func (wh *WorkflowHandler) DescribeWorkflowExecution(ctx context.Context, reques
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	if ok, _ := wh.rateLimiter.TryConsume(1); !ok {
+	if err := wh.authorize(ctx, "DescribeWorkflowExecution", request.GetDomain(), scope); err != nil {
+		return nil, err
+	}
+
+	if ok := wh.rateLimiter.Allow(request.GetDomain(), scope); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
@@ -1766,7 +2251,11 @@ func (wh *WorkflowHandler) DescribeTaskList(ctx context.Context, request *gen.De
 	sw := wh.startRequestProfile(scope)
 	defer sw.Stop()
 
-	if ok, _ := wh.rateLimiter.TryConsume(1); !ok {
+	if err := wh.authorize(ctx, "DescribeTaskList", request.GetDomain(), scope); err != nil {
+		return nil, err
+	}
+
+	if ok := wh.rateLimiter.Allow(request.GetDomain(), scope); !ok {
 		return nil, wh.error(createServiceBusyError(), scope)
 	}
 
@@ -1804,6 +2293,196 @@ func (wh *WorkflowHandler) DescribeTaskList(ctx context.Context, request *gen.De
 	return response, nil
 }
 
+// UpdateTaskListBuildIDs adds a build ID to a decision task list, either as compatible with an existing build ID or as
+// the new default build ID, or retires it.
+func (wh *WorkflowHandler) UpdateTaskListBuildIDs(ctx context.Context, request *gen.UpdateTaskListBuildIDsRequest) error {
+
+	scope := metrics.FrontendUpdateTaskListBuildIDsScope
+	sw := wh.startRequestProfile(scope)
+	defer sw.Stop()
+
+	if err := wh.authorize(ctx, "UpdateTaskListBuildIDs", request.GetDomain(), scope); err != nil {
+		return err
+	}
+
+	if ok := wh.rateLimiter.Allow(request.GetDomain(), scope); !ok {
+		return wh.error(createServiceBusyError(), scope)
+	}
+
+	if request.Domain == nil {
+		return wh.error(errDomainNotSet, scope)
+	}
+	domainID, err := wh.domainCache.GetDomainID(request.GetDomain())
+	if err != nil {
+		return wh.error(err, scope)
+	}
+
+	if err := wh.validateTaskList(request.TaskList, scope); err != nil {
+		return err
+	}
+
+	if request.GetBuildID() == "" {
+		return wh.error(errBuildIDNotSet, scope)
+	}
+	if err := wh.validateBuildID(request.GetBuildID(), scope); err != nil {
+		return err
+	}
+	if err := wh.validateBuildID(request.GetCompatibleBuildID(), scope); err != nil {
+		return err
+	}
+
+	op := func() error {
+		return wh.matching.UpdateTaskListBuildIDs(ctx, &m.UpdateTaskListBuildIDsRequest{
+			DomainUUID:    common.StringPtr(domainID),
+			UpdateRequest: request,
+		})
+	}
+
+	err = backoff.Retry(op, frontendServiceRetryPolicy, common.IsServiceTransientError)
+	if err != nil {
+		return wh.error(err, scope)
+	}
+
+	return nil
+}
+
+// PauseTaskList stops dispatching the tasks of a task list to pollers, the tasks added while it is paused
+// are persisted and dispatched once it is resumed.
+func (wh *WorkflowHandler) PauseTaskList(ctx context.Context, request *gen.PauseTaskListRequest) error {
+
+	scope := metrics.FrontendPauseTaskListScope
+	sw := wh.startRequestProfile(scope)
+	defer sw.Stop()
+
+	if err := wh.authorize(ctx, "PauseTaskList", request.GetDomain(), scope); err != nil {
+		return err
+	}
+
+	if ok := wh.rateLimiter.Allow(request.GetDomain(), scope); !ok {
+		return wh.error(createServiceBusyError(), scope)
+	}
+
+	if request.Domain == nil {
+		return wh.error(errDomainNotSet, scope)
+	}
+	domainID, err := wh.domainCache.GetDomainID(request.GetDomain())
+	if err != nil {
+		return wh.error(err, scope)
+	}
+
+	if err := wh.validateTaskList(request.TaskList, scope); err != nil {
+		return err
+	}
+
+	if err := wh.validateTaskListType(request.TaskListType, scope); err != nil {
+		return err
+	}
+
+	op := func() error {
+		return wh.matching.PauseTaskList(ctx, &m.PauseTaskListRequest{
+			DomainUUID:   common.StringPtr(domainID),
+			PauseRequest: request,
+		})
+	}
+
+	err = backoff.Retry(op, frontendServiceRetryPolicy, common.IsServiceTransientError)
+	if err != nil {
+		return wh.error(err, scope)
+	}
+
+	return nil
+}
+
+// ResumeTaskList resumes dispatching the tasks of a paused task list.
+func (wh *WorkflowHandler) ResumeTaskList(ctx context.Context, request *gen.ResumeTaskListRequest) error {
+
+	scope := metrics.FrontendResumeTaskListScope
+	sw := wh.startRequestProfile(scope)
+	defer sw.Stop()
+
+	if err := wh.authorize(ctx, "ResumeTaskList", request.GetDomain(), scope); err != nil {
+		return err
+	}
+
+	if ok := wh.rateLimiter.Allow(request.GetDomain(), scope); !ok {
+		return wh.error(createServiceBusyError(), scope)
+	}
+
+	if request.Domain == nil {
+		return wh.error(errDomainNotSet, scope)
+	}
+	domainID, err := wh.domainCache.GetDomainID(request.GetDomain())
+	if err != nil {
+		return wh.error(err, scope)
+	}
+
+	if err := wh.validateTaskList(request.TaskList, scope); err != nil {
+		return err
+	}
+
+	if err := wh.validateTaskListType(request.TaskListType, scope); err != nil {
+		return err
+	}
+
+	op := func() error {
+		return wh.matching.ResumeTaskList(ctx, &m.ResumeTaskListRequest{
+			DomainUUID:    common.StringPtr(domainID),
+			ResumeRequest: request,
+		})
+	}
+
+	err = backoff.Retry(op, frontendServiceRetryPolicy, common.IsServiceTransientError)
+	if err != nil {
+		return wh.error(err, scope)
+	}
+
+	return nil
+}
+
+// ListTaskLists returns a page of the task lists of a domain, including the task lists which currently have no
+// pollers.  The Cassandra task store scans the task lists of every domain to serve it, so it is meant for admin
+// tooling rather than for frequent calls, and a page can be short before the last one.
+func (wh *WorkflowHandler) ListTaskLists(ctx context.Context,
+	request *gen.ListTaskListsRequest) (*gen.ListTaskListsResponse, error) {
+
+	scope := metrics.FrontendListTaskListsScope
+	sw := wh.startRequestProfile(scope)
+	defer sw.Stop()
+
+	if err := wh.authorize(ctx, "ListTaskLists", request.GetDomain(), scope); err != nil {
+		return nil, err
+	}
+
+	if ok := wh.rateLimiter.Allow(request.GetDomain(), scope); !ok {
+		return nil, wh.error(createServiceBusyError(), scope)
+	}
+
+	if request.Domain == nil {
+		return nil, wh.error(errDomainNotSet, scope)
+	}
+	domainID, err := wh.domainCache.GetDomainID(request.GetDomain())
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+
+	var response *gen.ListTaskListsResponse
+	op := func() error {
+		var err error
+		response, err = wh.matching.ListTaskLists(ctx, &m.ListTaskListsRequest{
+			DomainUUID:  common.StringPtr(domainID),
+			ListRequest: request,
+		})
+		return err
+	}
+
+	err = backoff.Retry(op, frontendServiceRetryPolicy, common.IsServiceTransientError)
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+
+	return response, nil
+}
+
 func (wh *WorkflowHandler) getHistory(domainID string, execution gen.WorkflowExecution,
 	firstEventID, nextEventID int64, pageSize int32, nextPageToken []byte,
 	transientDecision *gen.TransientDecisionInfo) (*gen.History, []byte, error) {
@@ -1858,6 +2537,18 @@ func (wh *WorkflowHandler) getLoggerForTask(taskToken []byte) bark.Logger {
 }
 
 // startRequestProfile initiates recording of request metrics
+// consumeByDomainID counts a request of the domain in the RPS, the requests carrying a task token only know the ID
+// of their domain
+func (wh *WorkflowHandler) consumeByDomainID(domainID string) {
+	domainEntry, err := wh.domainCache.GetDomainByID(domainID)
+	if err != nil {
+		// the request still counts against the global RPS, it fails later on with the same error
+		wh.rateLimiter.Consume("")
+		return
+	}
+	wh.rateLimiter.Consume(domainEntry.GetInfo().Name)
+}
+
 func (wh *WorkflowHandler) startRequestProfile(scope int) tally.Stopwatch {
 	wh.startWG.Wait()
 	sw := wh.metricsClient.StartTimer(scope, metrics.CadenceLatency)
@@ -1892,6 +2583,9 @@ func (wh *WorkflowHandler) error(err error, scope int) error {
 	case *gen.QueryFailedError:
 		wh.metricsClient.IncCounter(scope, metrics.CadenceErrQueryFailedCounter)
 		return err
+	case *gen.AccessDeniedError:
+		wh.metricsClient.IncCounter(scope, metrics.CadenceErrAccessDeniedCounter)
+		return err
 	default:
 		logging.LogUncategorizedError(wh.Service.GetLogger(), err)
 		wh.metricsClient.IncCounter(scope, metrics.CadenceFailures)
@@ -1906,10 +2600,21 @@ func (wh *WorkflowHandler) validateTaskListType(t *gen.TaskListType, scope int)
 	return nil
 }
 
+// validateBuildID rejects build IDs which would make the name of their task list a task list partition name
+func (wh *WorkflowHandler) validateBuildID(buildID string, scope int) error {
+	if strings.Contains(buildID, "/") {
+		return wh.error(errInvalidBuildID, scope)
+	}
+	return nil
+}
+
 func (wh *WorkflowHandler) validateTaskList(t *gen.TaskList, scope int) error {
 	if t == nil || t.Name == nil || *t.Name == "" {
 		return wh.error(errTaskListNotSet, scope)
 	}
+	if strings.HasPrefix(t.GetName(), common.ReservedTaskListPrefix) {
+		return wh.error(errReservedTaskListPrefix, scope)
+	}
 	return nil
 }
 
@@ -1926,6 +2631,23 @@ func (wh *WorkflowHandler) validateExecution(w *gen.WorkflowExecution, scope int
 	return nil
 }
 
+// validateSearchAttributesFilter returns the filter with its values in the encoding used by the visibility store
+func (wh *WorkflowHandler) validateSearchAttributesFilter(filter *gen.SearchAttributes,
+	scope int) (*gen.SearchAttributes, error) {
+	if filter == nil {
+		return nil, nil
+	}
+	if len(filter.GetIndexedFields()) == 0 {
+		return nil, wh.error(&gen.BadRequestError{Message: "IndexedFields in SearchAttributesFilter is required"}, scope)
+	}
+
+	filter, err := wh.searchAttrValidator.ValidateSearchAttributes(filter)
+	if err != nil {
+		return nil, wh.error(err, scope)
+	}
+	return filter, nil
+}
+
 func getDomainStatus(info *persistence.DomainInfo) *gen.DomainStatus {
 	switch info.Status {
 	case persistence.DomainStatusRegistered:
@@ -1956,6 +2678,8 @@ func createDomainResponse(info *persistence.DomainInfo, config *persistence.Doma
 	configResult := &gen.DomainConfiguration{
 		EmitMetric:                             common.BoolPtr(config.EmitMetric),
 		WorkflowExecutionRetentionPeriodInDays: common.Int32Ptr(config.Retention),
+		ArchivalStatus:                         config.ArchivalStatus.Ptr(),
+		ArchivalBucketName:                     common.StringPtr(config.ArchivalBucket),
 	}
 
 	clusters := []*gen.ClusterReplicationConfiguration{}
@@ -1973,7 +2697,7 @@ func createDomainResponse(info *persistence.DomainInfo, config *persistence.Doma
 	return infoResult, configResult, replicationConfigResult
 }
 
-func (wh *WorkflowHandler) createPollForDecisionTaskResponse(ctx context.Context, domainID string,
+func (wh *WorkflowHandler) createPollForDecisionTaskResponse(ctx context.Context, domainName, domainID string,
 	matchingResp *m.PollForDecisionTaskResponse) (*gen.PollForDecisionTaskResponse, error) {
 
 	if matchingResp.WorkflowExecution == nil {
@@ -2011,7 +2735,7 @@ func (wh *WorkflowHandler) createPollForDecisionTaskResponse(ctx context.Context
 			*matchingResp.WorkflowExecution,
 			firstEventID,
 			nextEventID,
-			wh.config.DefaultHistoryMaxPageSize,
+			int32(wh.config.DefaultHistoryMaxPageSize(dynamicconfig.DomainFilter(domainName))),
 			nil,
 			matchingResp.DecisionInfo)
 		if err != nil {
@@ -2048,6 +2772,76 @@ func (wh *WorkflowHandler) createPollForDecisionTaskResponse(ctx context.Context
 	return resp, nil
 }
 
+// getArchivalBucket returns the archival bucket of the domain, when the histories of the domain are archived
+func (wh *WorkflowHandler) getArchivalBucket(domainID string) (string, error) {
+	if wh.archiver == nil {
+		return "", errArchivedHistoryNotAvailable
+	}
+	domainEntry, err := wh.domainCache.GetDomainByID(domainID)
+	if err != nil {
+		return "", err
+	}
+	config := domainEntry.GetConfig()
+	if config.ArchivalStatus != gen.ArchivalStatusEnabled || len(config.ArchivalBucket) == 0 {
+		return "", errArchivedHistoryNotAvailable
+	}
+	return config.ArchivalBucket, nil
+}
+
+// getArchivedHistory returns a page of the archived history of the execution, each archived page being returned
+// as is.  Only the close event is returned when isCloseEventOnly is set, read from the last archived page.
+func (wh *WorkflowHandler) getArchivedHistory(domainID string, bucket string, execution gen.WorkflowExecution,
+	page int, isCloseEventOnly bool) (*gen.GetWorkflowExecutionHistoryResponse, error) {
+
+	var blob *archiver.HistoryBlob
+	for {
+		var err error
+		blob, err = wh.archiver.GetHistoryBlob(&archiver.GetHistoryBlobRequest{
+			Bucket:     bucket,
+			DomainID:   domainID,
+			WorkflowID: execution.GetWorkflowId(),
+			RunID:      execution.GetRunId(),
+			Page:       page,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !isCloseEventOnly || blob.IsLast {
+			break
+		}
+		page++
+	}
+
+	serializer, err := wh.hSerializerFactory.Get(blob.Events.EncodingType)
+	if err != nil {
+		return nil, err
+	}
+	batch, err := serializer.Deserialize(blob.Events)
+	if err != nil {
+		return nil, err
+	}
+	history := &gen.History{Events: batch.Events}
+
+	var token *getHistoryContinuationToken
+	if isCloseEventOnly {
+		if len(history.Events) != 0 {
+			history.Events = history.Events[len(history.Events)-1:]
+		}
+	} else if !blob.IsLast {
+		token = &getHistoryContinuationToken{
+			RunID:               execution.GetRunId(),
+			IsArchivedHistory:   true,
+			ArchivedHistoryPage: page + 1,
+		}
+	}
+
+	nextToken, err := serializeHistoryToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return createGetWorkflowExecutionHistoryResponse(history, nextToken), nil
+}
+
 func createGetWorkflowExecutionHistoryResponse(
 	history *gen.History, nextPageToken []byte) *gen.GetWorkflowExecutionHistoryResponse {
 	resp := &gen.GetWorkflowExecutionHistoryResponse{}
@@ -2085,3 +2879,19 @@ func (wh *WorkflowHandler) validateClusterName(clusterName string) error {
 	}
 	return nil
 }
+
+func (wh *WorkflowHandler) validateArchivalConfig(status gen.ArchivalStatus, bucket string) error {
+	if len(bucket) != 0 && !archivalBucketNameRegex.MatchString(bucket) {
+		return errInvalidArchivalBucketName
+	}
+	if status != gen.ArchivalStatusEnabled {
+		return nil
+	}
+	if wh.archiver == nil {
+		return errArchivalNotConfigured
+	}
+	if len(bucket) == 0 {
+		return errArchivalBucketNotSet
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package frontend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-common/bark"
+	"github.com/uber-go/tally"
+
+	gen "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/archiver"
+	"github.com/uber/cadence/common/authorization"
+	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/mocks"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/service"
+	"github.com/uber/cadence/common/service/dynamicconfig"
+)
+
+const (
+	testDomainName     = "test-domain"
+	testDomainID       = "6b8b4e3c-2f1a-4c5d-9e7f-0a1b2c3d4e5f"
+	testArchivalBucket = "test-bucket"
+)
+
+type (
+	workflowHandlerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		mockMetadataMgr     *mocks.MetadataManager
+		mockClusterMetadata *mocks.ClusterMetadata
+		mockHistoryClient   *mocks.HistoryClient
+		mockArchiver        *mocks.Archiver
+		handler             *WorkflowHandler
+	}
+)
+
+func TestWorkflowHandlerSuite(t *testing.T) {
+	s := new(workflowHandlerSuite)
+	suite.Run(t, s)
+}
+
+func (s *workflowHandlerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	logger := bark.NewLoggerFromLogrus(log.New())
+	metricsClient := metrics.NewClient(tally.NoopScope, metrics.Frontend)
+	s.mockMetadataMgr = &mocks.MetadataManager{}
+	s.mockClusterMetadata = &mocks.ClusterMetadata{}
+	s.mockHistoryClient = &mocks.HistoryClient{}
+	s.mockArchiver = &mocks.Archiver{}
+	frontendConfig := NewConfig(dynamicconfig.NewNopCollection())
+	s.handler = &WorkflowHandler{
+		Service:            service.NewTestService(s.mockClusterMetadata, nil, metricsClient, logger),
+		config:             frontendConfig,
+		metricsClient:      metricsClient,
+		domainCache:        cache.NewDomainCache(s.mockMetadataMgr, s.mockClusterMetadata, logger),
+		history:            s.mockHistoryClient,
+		hSerializerFactory: persistence.NewHistorySerializerFactory(),
+		rateLimiter:        newDomainRateLimiter(frontendConfig.RPS, frontendConfig.DomainRPS, metricsClient, common.NewRealTimeSource()),
+		archiver:           s.mockArchiver,
+		authorizer:         authorization.NewNopAuthorizer(),
+		identityProvider:   authorization.NewNopIdentityProvider(),
+	}
+}
+
+func (s *workflowHandlerSuite) TearDownTest() {
+	s.mockMetadataMgr.AssertExpectations(s.T())
+	s.mockHistoryClient.AssertExpectations(s.T())
+	s.mockArchiver.AssertExpectations(s.T())
+}
+
+func (s *workflowHandlerSuite) TestGetHistoryFallsBackToArchivedHistory() {
+	execution := &gen.WorkflowExecution{WorkflowId: common.StringPtr("workflow-id"), RunId: common.StringPtr(uuid.New())}
+	s.setupDomain(gen.ArchivalStatusEnabled)
+	s.mockHistoryClient.On("GetMutableState", mock.Anything, mock.Anything).Return(nil,
+		&gen.EntityNotExistsError{Message: "workflow execution not found"}).Once()
+	s.mockArchiver.On("GetHistoryBlob", s.getHistoryBlobRequest(execution, 0)).Return(
+		s.newHistoryBlob(false, 1, 2), nil).Once()
+	s.mockArchiver.On("GetHistoryBlob", s.getHistoryBlobRequest(execution, 1)).Return(
+		s.newHistoryBlob(true, 3), nil).Once()
+
+	// the history deleted by the retention is read page by page from the archiver
+	response, err := s.handler.GetWorkflowExecutionHistory(context.Background(), &gen.GetWorkflowExecutionHistoryRequest{
+		Domain:    common.StringPtr(testDomainName),
+		Execution: execution,
+	})
+	s.NoError(err)
+	s.Equal([]int64{1, 2}, getEventIDs(response.History))
+	s.NotEmpty(response.NextPageToken)
+
+	response, err = s.handler.GetWorkflowExecutionHistory(context.Background(), &gen.GetWorkflowExecutionHistoryRequest{
+		Domain:        common.StringPtr(testDomainName),
+		Execution:     execution,
+		NextPageToken: response.NextPageToken,
+	})
+	s.NoError(err)
+	s.Equal([]int64{3}, getEventIDs(response.History))
+	s.Empty(response.NextPageToken)
+}
+
+func (s *workflowHandlerSuite) TestGetHistoryFallsBackToArchivedCloseEvent() {
+	execution := &gen.WorkflowExecution{WorkflowId: common.StringPtr("workflow-id"), RunId: common.StringPtr(uuid.New())}
+	s.setupDomain(gen.ArchivalStatusEnabled)
+	s.mockHistoryClient.On("GetMutableState", mock.Anything, mock.Anything).Return(nil,
+		&gen.EntityNotExistsError{Message: "workflow execution not found"}).Once()
+	s.mockArchiver.On("GetHistoryBlob", s.getHistoryBlobRequest(execution, 0)).Return(
+		s.newHistoryBlob(false, 1, 2), nil).Once()
+	s.mockArchiver.On("GetHistoryBlob", s.getHistoryBlobRequest(execution, 1)).Return(
+		s.newHistoryBlob(true, 3, 4), nil).Once()
+
+	// only the close event is returned, read from the last archived page
+	response, err := s.handler.GetWorkflowExecutionHistory(context.Background(), &gen.GetWorkflowExecutionHistoryRequest{
+		Domain:                 common.StringPtr(testDomainName),
+		Execution:              execution,
+		HistoryEventFilterType: gen.HistoryEventFilterTypeCloseEvent.Ptr(),
+	})
+	s.NoError(err)
+	s.Equal([]int64{4}, getEventIDs(response.History))
+	s.Empty(response.NextPageToken)
+}
+
+func (s *workflowHandlerSuite) TestGetHistoryNotArchived() {
+	execution := &gen.WorkflowExecution{WorkflowId: common.StringPtr("workflow-id"), RunId: common.StringPtr(uuid.New())}
+	s.setupDomain(gen.ArchivalStatusDisabled)
+	notExistsErr := &gen.EntityNotExistsError{Message: "workflow execution not found"}
+	s.mockHistoryClient.On("GetMutableState", mock.Anything, mock.Anything).Return(nil, notExistsErr).Once()
+
+	// the error of the history service is returned when the domain has no archived history
+	_, err := s.handler.GetWorkflowExecutionHistory(context.Background(), &gen.GetWorkflowExecutionHistoryRequest{
+		Domain:    common.StringPtr(testDomainName),
+		Execution: execution,
+	})
+	s.Equal(notExistsErr, err)
+}
+
+func (s *workflowHandlerSuite) TestDomainAPIsThrottled() {
+	rps := func(opts ...dynamicconfig.FilterOption) int {
+		return 10
+	}
+	s.handler.rateLimiter = newDomainRateLimiter(rps, rps, s.handler.metricsClient,
+		&mockTimeSource{currTime: time.Now()})
+	s.setupDomain(gen.ArchivalStatusDisabled)
+
+	// the first request drains the token bucket of the domain and the global one
+	response, err := s.handler.DescribeDomain(context.Background(), &gen.DescribeDomainRequest{
+		Name: common.StringPtr(testDomainName),
+	})
+	s.NoError(err)
+	s.Equal(testDomainName, response.DomainInfo.GetName())
+
+	_, err = s.handler.DescribeDomain(context.Background(), &gen.DescribeDomainRequest{
+		Name: common.StringPtr(testDomainName),
+	})
+	s.IsType(&gen.ServiceBusyError{}, err)
+	_, err = s.handler.UpdateDomain(context.Background(), &gen.UpdateDomainRequest{
+		Name: common.StringPtr(testDomainName),
+	})
+	s.IsType(&gen.ServiceBusyError{}, err)
+	err = s.handler.DeprecateDomain(context.Background(), &gen.DeprecateDomainRequest{
+		Name: common.StringPtr(testDomainName),
+	})
+	s.IsType(&gen.ServiceBusyError{}, err)
+
+	// a new domain is throttled by the global token bucket
+	err = s.handler.RegisterDomain(context.Background(), &gen.RegisterDomainRequest{
+		Name: common.StringPtr("new-domain"),
+	})
+	s.IsType(&gen.ServiceBusyError{}, err)
+}
+
+func (s *workflowHandlerSuite) setupDomain(archivalStatus gen.ArchivalStatus) {
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info: &persistence.DomainInfo{ID: testDomainID, Name: testDomainName},
+		Config: &persistence.DomainConfig{
+			Retention:      1,
+			ArchivalBucket: testArchivalBucket,
+			ArchivalStatus: archivalStatus,
+		},
+		ReplicationConfig: &persistence.DomainReplicationConfig{},
+	}, nil)
+}
+
+func (s *workflowHandlerSuite) getHistoryBlobRequest(execution *gen.WorkflowExecution,
+	page int) *archiver.GetHistoryBlobRequest {
+	return &archiver.GetHistoryBlobRequest{
+		Bucket:     testArchivalBucket,
+		DomainID:   testDomainID,
+		WorkflowID: execution.GetWorkflowId(),
+		RunID:      execution.GetRunId(),
+		Page:       page,
+	}
+}
+
+func (s *workflowHandlerSuite) newHistoryBlob(isLast bool, eventIDs ...int64) *archiver.HistoryBlob {
+	var events []*gen.HistoryEvent
+	for _, eventID := range eventIDs {
+		events = append(events, &gen.HistoryEvent{
+			EventId:   common.Int64Ptr(eventID),
+			EventType: gen.EventTypeMarkerRecorded.Ptr(),
+		})
+	}
+	serializer, err := persistence.NewHistorySerializerFactory().Get(common.EncodingTypeThriftRWGzip)
+	s.NoError(err)
+	serializedEvents, err := serializer.Serialize(persistence.NewHistoryEventBatch(
+		persistence.GetDefaultHistoryVersion(), events))
+	s.NoError(err)
+	return &archiver.HistoryBlob{Events: serializedEvents, IsLast: isLast}
+}
+
+func getEventIDs(history *gen.History) []int64 {
+	var eventIDs []int64
+	for _, event := range history.Events {
+		eventIDs = append(eventIDs, event.GetEventId())
+	}
+	return eventIDs
+}
@@ -25,26 +25,42 @@ import (
 	"github.com/uber/cadence/common/messaging"
 	"github.com/uber/cadence/common/mocks"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/searchattribute"
 	"github.com/uber/cadence/common/service"
+	"github.com/uber/cadence/common/service/dynamicconfig"
 )
 
 // Config represents configuration for cadence-frontend service
 type Config struct {
-	DefaultVisibilityMaxPageSize int32
-	DefaultHistoryMaxPageSize    int32
-	RPS                          int
+	DefaultVisibilityMaxPageSize dynamicconfig.IntPropertyFn
+	DefaultHistoryMaxPageSize    dynamicconfig.IntPropertyFn
+	RPS                          dynamicconfig.IntPropertyFn
+	DomainRPS                    dynamicconfig.IntPropertyFn
 
 	// Persistence settings
-	HistoryMgrNumConns int
+	// Change of this config requires service restart
+	HistoryMgrNumConns dynamicconfig.IntPropertyFn
+
+	// ValidSearchAttributes is the whitelist of search attribute names and their value types
+	ValidSearchAttributes dynamicconfig.MapPropertyFn
+
+	// VisibilityQuerySupported is false when the visibility store cannot serve ListWorkflowExecutions and
+	// CountWorkflowExecutions.  It is set from the persistence config of the service.
+	VisibilityQuerySupported bool
 }
 
 // NewConfig returns new service config with default values
-func NewConfig() *Config {
+func NewConfig(dc *dynamicconfig.Collection) *Config {
 	return &Config{
-		DefaultVisibilityMaxPageSize: 1000,
-		DefaultHistoryMaxPageSize:    1000,
-		RPS:                1200, // This limit is based on experimental runs.
-		HistoryMgrNumConns: 10,
+		DefaultVisibilityMaxPageSize: dc.GetIntProperty(dynamicconfig.FrontendVisibilityMaxPageSize, 1000),
+		DefaultHistoryMaxPageSize:    dc.GetIntProperty(dynamicconfig.FrontendHistoryMaxPageSize, 1000),
+		RPS:                          dc.GetIntProperty(dynamicconfig.FrontendRPS, 1200), // This limit is based on experimental runs.
+		DomainRPS:                    dc.GetIntProperty(dynamicconfig.FrontendDomainRPS, 1200),
+		HistoryMgrNumConns:           dc.GetIntProperty(dynamicconfig.FrontendHistoryMgrNumConns, 10),
+		ValidSearchAttributes: dc.GetMapProperty(
+			dynamicconfig.ValidSearchAttributes, searchattribute.GetDefaultValidSearchAttributes(),
+		),
+		VisibilityQuerySupported: true,
 	}
 }
 
@@ -59,7 +75,7 @@ type Service struct {
 func NewService(params *service.BootstrapParams) common.Daemon {
 	return &Service{
 		params: params,
-		config: NewConfig(),
+		config: NewConfig(dynamicconfig.NewCollection(params.DynamicConfig, params.Logger)),
 		stopC:  make(chan struct{}),
 	}
 }
@@ -74,41 +90,93 @@ func (s *Service) Start() {
 
 	base := service.New(p)
 
-	metadata, err := persistence.NewCassandraMetadataPersistence(p.CassandraConfig.Hosts,
-		p.CassandraConfig.Port,
-		p.CassandraConfig.User,
-		p.CassandraConfig.Password,
-		p.CassandraConfig.Datacenter,
-		p.CassandraConfig.Keyspace,
-		p.ClusterMetadata.GetCurrentClusterName(),
-		p.Logger)
+	var metadata persistence.MetadataManager
+	var err error
+	if p.InMemoryStore != nil {
+		metadata, err = persistence.NewInMemoryMetadataPersistence(p.InMemoryStore, p.ClusterMetadata.GetCurrentClusterName(), p.Logger)
+	} else if p.SQLConfig != nil {
+		metadata, err = persistence.NewSQLMetadataPersistence(p.SQLConfig.DriverName,
+			p.SQLConfig.Host,
+			p.SQLConfig.Port,
+			p.SQLConfig.User,
+			p.SQLConfig.Password,
+			p.SQLConfig.DatabaseName,
+			p.SQLConfig.MaxConns,
+			p.ClusterMetadata.GetCurrentClusterName(),
+			p.Logger,
+		)
+	} else {
+		metadata, err = persistence.NewCassandraMetadataPersistence(p.CassandraConfig.Hosts,
+			p.CassandraConfig.Port,
+			p.CassandraConfig.User,
+			p.CassandraConfig.Password,
+			p.CassandraConfig.Datacenter,
+			p.CassandraConfig.Keyspace,
+			p.ClusterMetadata.GetCurrentClusterName(),
+			p.Logger)
+	}
 
 	if err != nil {
 		log.Fatalf("failed to create metadata manager: %v", err)
 	}
 	metadata = persistence.NewMetadataPersistenceClient(metadata, base.GetMetricsClient())
 
-	visibility, err := persistence.NewCassandraVisibilityPersistence(p.CassandraConfig.Hosts,
-		p.CassandraConfig.Port,
-		p.CassandraConfig.User,
-		p.CassandraConfig.Password,
-		p.CassandraConfig.Datacenter,
-		p.CassandraConfig.VisibilityKeyspace,
-		p.Logger)
+	var visibility persistence.VisibilityManager
+	if p.BoltVisibilityStore != nil {
+		visibility, err = persistence.NewBoltVisibilityPersistence(p.BoltVisibilityStore, p.Logger)
+	} else if p.InMemoryStore != nil {
+		visibility, err = persistence.NewInMemoryVisibilityPersistence(p.InMemoryStore, p.Logger)
+	} else if p.SQLConfig != nil {
+		visibility, err = persistence.NewSQLVisibilityPersistence(p.SQLConfig.DriverName,
+			p.SQLConfig.Host,
+			p.SQLConfig.Port,
+			p.SQLConfig.User,
+			p.SQLConfig.Password,
+			p.SQLConfig.VisibilityDatabaseName,
+			p.SQLConfig.MaxConns,
+			p.Logger,
+		)
+	} else {
+		// Cassandra visibility tables can only be filtered on a single indexed column, so they cannot serve
+		// arbitrary queries
+		s.config.VisibilityQuerySupported = false
+		visibility, err = persistence.NewCassandraVisibilityPersistence(p.CassandraConfig.Hosts,
+			p.CassandraConfig.Port,
+			p.CassandraConfig.User,
+			p.CassandraConfig.Password,
+			p.CassandraConfig.Datacenter,
+			p.CassandraConfig.VisibilityKeyspace,
+			p.Logger)
+	}
 
 	if err != nil {
 		log.Fatalf("failed to create visiblity manager: %v", err)
 	}
 	visibility = persistence.NewVisibilityPersistenceClient(visibility, base.GetMetricsClient())
 
-	history, err := persistence.NewCassandraHistoryPersistence(p.CassandraConfig.Hosts,
-		p.CassandraConfig.Port,
-		p.CassandraConfig.User,
-		p.CassandraConfig.Password,
-		p.CassandraConfig.Datacenter,
-		p.CassandraConfig.Keyspace,
-		s.config.HistoryMgrNumConns,
-		p.Logger)
+	var history persistence.HistoryManager
+	if p.InMemoryStore != nil {
+		history, err = persistence.NewInMemoryHistoryPersistence(p.InMemoryStore, p.Logger)
+	} else if p.SQLConfig != nil {
+		history, err = persistence.NewSQLHistoryPersistence(p.SQLConfig.DriverName,
+			p.SQLConfig.Host,
+			p.SQLConfig.Port,
+			p.SQLConfig.User,
+			p.SQLConfig.Password,
+			p.SQLConfig.DatabaseName,
+			p.SQLConfig.MaxConns,
+			p.Logger,
+		)
+	} else {
+		history, err = persistence.NewCassandraHistoryPersistence(p.CassandraConfig.Hosts,
+			p.CassandraConfig.Port,
+			p.CassandraConfig.User,
+			p.CassandraConfig.Password,
+			p.CassandraConfig.Datacenter,
+			p.CassandraConfig.Keyspace,
+			s.config.HistoryMgrNumConns(),
+			p.Logger)
+	}
 
 	if err != nil {
 		log.Fatalf("Creating Cassandra history manager persistence failed: %v", err)
@@ -127,7 +195,8 @@ func (s *Service) Start() {
 		kafkaProducer = &mocks.KafkaProducer{}
 	}
 
-	handler := NewWorkflowHandler(base, s.config, metadata, history, visibility, kafkaProducer)
+	handler := NewWorkflowHandler(base, s.config, metadata, history, visibility, kafkaProducer, p.Archiver, p.Authorizer,
+		p.IdentityProvider)
 	handler.Start()
 
 	log.Infof("%v started", common.FrontendServiceName)
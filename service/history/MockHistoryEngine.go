@@ -302,6 +302,29 @@ func (_m *MockHistoryEngine) SignalWorkflowExecution(request *gohistory.SignalWo
 	return r0
 }
 
+// SignalWithStartWorkflowExecution is mock implementation for SignalWithStartWorkflowExecution of HistoryEngine
+func (_m *MockHistoryEngine) SignalWithStartWorkflowExecution(request *gohistory.SignalWithStartWorkflowExecutionRequest) (*shared.StartWorkflowExecutionResponse, error) {
+	ret := _m.Called(request)
+
+	var r0 *shared.StartWorkflowExecutionResponse
+	if rf, ok := ret.Get(0).(func(*gohistory.SignalWithStartWorkflowExecutionRequest) *shared.StartWorkflowExecutionResponse); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shared.StartWorkflowExecutionResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*gohistory.SignalWithStartWorkflowExecutionRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveSignalMutableState is mock implementation for RemoveSignalMutableState of HistoryEngine
 func (_m *MockHistoryEngine) RemoveSignalMutableState(request *gohistory.RemoveSignalMutableStateRequest) error {
 	ret := _m.Called(request)
@@ -330,6 +353,29 @@ func (_m *MockHistoryEngine) TerminateWorkflowExecution(request *gohistory.Termi
 	return r0
 }
 
+// ResetWorkflowExecution is mock implementation for ResetWorkflowExecution of HistoryEngine
+func (_m *MockHistoryEngine) ResetWorkflowExecution(request *gohistory.ResetWorkflowExecutionRequest) (*shared.ResetWorkflowExecutionResponse, error) {
+	ret := _m.Called(request)
+
+	var r0 *shared.ResetWorkflowExecutionResponse
+	if rf, ok := ret.Get(0).(func(*gohistory.ResetWorkflowExecutionRequest) *shared.ResetWorkflowExecutionResponse); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shared.ResetWorkflowExecutionResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*gohistory.ResetWorkflowExecutionRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ScheduleDecisionTask is mock implementation for ScheduleDecisionTask of HistoryEngine
 func (_m *MockHistoryEngine) ScheduleDecisionTask(request *gohistory.ScheduleDecisionTaskRequest) error {
 	ret := _m.Called(request)
@@ -358,4 +404,18 @@ func (_m *MockHistoryEngine) RecordChildExecutionCompleted(request *gohistory.Re
 	return r0
 }
 
+// ReplicateEvents is mock implementation for ReplicateEvents of HistoryEngine
+func (_m *MockHistoryEngine) ReplicateEvents(request *gohistory.ReplicateEventsRequest) error {
+	ret := _m.Called(request)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*gohistory.ReplicateEventsRequest) error); ok {
+		r0 = rf(request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 var _ Engine = (*MockHistoryEngine)(nil)
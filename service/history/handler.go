@@ -33,6 +33,7 @@ import (
 	hc "github.com/uber/cadence/client/history"
 	"github.com/uber/cadence/client/matching"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/archiver"
 	"github.com/uber/cadence/common/membership"
 	"github.com/uber/cadence/common/messaging"
 	"github.com/uber/cadence/common/metrics"
@@ -59,6 +60,7 @@ type (
 		config                *Config
 		historyEventNotifier  historyEventNotifier
 		publisher             messaging.Producer
+		archiver              archiver.Archiver
 		service.Service
 	}
 )
@@ -75,7 +77,8 @@ var (
 // NewHandler creates a thrift handler for the history service
 func NewHandler(sVice service.Service, config *Config, shardManager persistence.ShardManager,
 	metadataMgr persistence.MetadataManager, visibilityMgr persistence.VisibilityManager,
-	historyMgr persistence.HistoryManager, executionMgrFactory persistence.ExecutionManagerFactory) *Handler {
+	historyMgr persistence.HistoryManager, executionMgrFactory persistence.ExecutionManagerFactory,
+	archiver archiver.Archiver) *Handler {
 	handler := &Handler{
 		Service:             sVice,
 		config:              config,
@@ -85,6 +88,7 @@ func NewHandler(sVice service.Service, config *Config, shardManager persistence.
 		visibilityMgr:       visibilityMgr,
 		executionMgrFactory: executionMgrFactory,
 		tokenSerializer:     common.NewJSONTaskTokenSerializer(),
+		archiver:            archiver,
 	}
 
 	// prevent us from trying to serve requests before shard controller is started and ready
@@ -149,7 +153,8 @@ func (h *Handler) Stop() {
 
 // CreateEngine is implementation for HistoryEngineFactory used for creating the engine instance for shard
 func (h *Handler) CreateEngine(context ShardContext) Engine {
-	return NewEngineWithShardContext(context, h.visibilityMgr, h.matchingServiceClient, h.historyServiceClient, h.historyEventNotifier, h.publisher)
+	return NewEngineWithShardContext(context, h.visibilityMgr, h.matchingServiceClient, h.historyServiceClient, h.historyEventNotifier, h.publisher,
+		h.archiver)
 }
 
 // Health is for health check
@@ -609,6 +614,39 @@ func (h *Handler) SignalWorkflowExecution(ctx context.Context,
 	return nil
 }
 
+// SignalWithStartWorkflowExecution is used to ensure sending a signal event to a workflow execution.
+// If workflow is running, this results in WorkflowExecutionSignaled event recorded in the history
+// and a decision task being created for the execution.
+// If workflow is not running or not found, this results in WorkflowExecutionStarted and WorkflowExecutionSignaled
+// event recorded in history, and a decision task being created for the execution
+func (h *Handler) SignalWithStartWorkflowExecution(ctx context.Context,
+	wrappedRequest *hist.SignalWithStartWorkflowExecutionRequest) (*gen.StartWorkflowExecutionResponse, error) {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistorySignalWithStartWorkflowExecutionScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistorySignalWithStartWorkflowExecutionScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if wrappedRequest.DomainUUID == nil {
+		return nil, errDomainNotSet
+	}
+
+	signalWithStartRequest := wrappedRequest.SignalWithStartRequest
+	engine, err1 := h.controller.GetEngine(signalWithStartRequest.GetWorkflowId())
+	if err1 != nil {
+		h.updateErrorMetric(metrics.HistorySignalWithStartWorkflowExecutionScope, err1)
+		return nil, err1
+	}
+
+	response, err2 := engine.SignalWithStartWorkflowExecution(wrappedRequest)
+	if err2 != nil {
+		h.updateErrorMetric(metrics.HistorySignalWithStartWorkflowExecutionScope, h.convertError(err2))
+		return nil, h.convertError(err2)
+	}
+
+	return response, nil
+}
+
 // RemoveSignalMutableState is used to remove a signal request ID that was previously recorded.  This is currently
 // used to clean execution info when signal decision finished.
 func (h *Handler) RemoveSignalMutableState(ctx context.Context,
@@ -666,6 +704,37 @@ func (h *Handler) TerminateWorkflowExecution(ctx context.Context,
 	return nil
 }
 
+// ResetWorkflowExecution resets an existing workflow execution to the given DecisionTaskCompleted event (exclusive).
+// It starts a new run from a copy of the history up to that point and terminates the current run.
+func (h *Handler) ResetWorkflowExecution(ctx context.Context,
+	wrappedRequest *hist.ResetWorkflowExecutionRequest) (*gen.ResetWorkflowExecutionResponse, error) {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryResetWorkflowExecutionScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryResetWorkflowExecutionScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if wrappedRequest.DomainUUID == nil {
+		return nil, errDomainNotSet
+	}
+
+	resetRequest := wrappedRequest.ResetRequest
+	workflowExecution := resetRequest.WorkflowExecution
+	engine, err1 := h.controller.GetEngine(*workflowExecution.WorkflowId)
+	if err1 != nil {
+		h.updateErrorMetric(metrics.HistoryResetWorkflowExecutionScope, err1)
+		return nil, err1
+	}
+
+	response, err2 := engine.ResetWorkflowExecution(wrappedRequest)
+	if err2 != nil {
+		h.updateErrorMetric(metrics.HistoryResetWorkflowExecutionScope, h.convertError(err2))
+		return nil, h.convertError(err2)
+	}
+
+	return response, nil
+}
+
 // ScheduleDecisionTask is used for creating a decision task for already started workflow execution.  This is mainly
 // used by transfer queue processor during the processing of StartChildWorkflowExecution task, where it first starts
 // child execution without creating the decision task and then calls this API after updating the mutable state of
@@ -767,6 +836,37 @@ func (h *Handler) ResetStickyTaskList(ctx context.Context, resetRequest *hist.Re
 	return resp, nil
 }
 
+// ReplicateEvents is called by processor to replicate history events for passive domains
+func (h *Handler) ReplicateEvents(ctx context.Context, replicateRequest *hist.ReplicateEventsRequest) error {
+	h.startWG.Wait()
+
+	h.metricsClient.IncCounter(metrics.HistoryReplicateEventsScope, metrics.CadenceRequests)
+	sw := h.metricsClient.StartTimer(metrics.HistoryReplicateEventsScope, metrics.CadenceLatency)
+	defer sw.Stop()
+
+	if replicateRequest.DomainUUID == nil {
+		return errDomainNotSet
+	}
+
+	if replicateRequest.WorkflowExecution == nil {
+		return errWorkflowExecutionNotSet
+	}
+
+	engine, err1 := h.controller.GetEngine(replicateRequest.WorkflowExecution.GetWorkflowId())
+	if err1 != nil {
+		h.updateErrorMetric(metrics.HistoryReplicateEventsScope, err1)
+		return err1
+	}
+
+	err2 := engine.ReplicateEvents(replicateRequest)
+	if err2 != nil {
+		h.updateErrorMetric(metrics.HistoryReplicateEventsScope, h.convertError(err2))
+		return h.convertError(err2)
+	}
+
+	return nil
+}
+
 // convertError is a helper method to convert ShardOwnershipLostError from persistence layer returned by various
 // HistoryEngine API calls to ShardOwnershipLost error return by HistoryService for client to be redirected to the
 // correct shard.
@@ -21,6 +21,8 @@
 package history
 
 import (
+	"time"
+
 	"github.com/uber-common/bark"
 	h "github.com/uber/cadence/.gen/go/history"
 	workflow "github.com/uber/cadence/.gen/go/shared"
@@ -33,38 +35,61 @@ const (
 	firstEventID    int64 = 1
 	emptyEventID    int64 = -23
 	bufferedEventID int64 = -123
+	// transientEventID is used as StartedID for activities with retry policy, as their started event is only
+	// written to history when the activity is closed
+	transientEventID int64 = -124
 )
 
 type (
 	historyBuilder struct {
-		serializer persistence.HistorySerializer
-		history    []*workflow.HistoryEvent
-		msBuilder  *mutableStateBuilder
-		logger     bark.Logger
+		serializerFactory persistence.HistorySerializerFactory
+		history           []*workflow.HistoryEvent
+		msBuilder         *mutableStateBuilder
+		logger            bark.Logger
 	}
 )
 
 func newHistoryBuilder(msBuilder *mutableStateBuilder, logger bark.Logger) *historyBuilder {
 	return &historyBuilder{
-		serializer: persistence.NewJSONHistorySerializer(),
-		history:    []*workflow.HistoryEvent{},
-		msBuilder:  msBuilder,
-		logger:     logger.WithField(logging.TagWorkflowComponent, logging.TagValueHistoryBuilderComponent),
+		serializerFactory: persistence.NewHistorySerializerFactory(),
+		history:           []*workflow.HistoryEvent{},
+		msBuilder:         msBuilder,
+		logger:            logger.WithField(logging.TagWorkflowComponent, logging.TagValueHistoryBuilderComponent),
 	}
 }
 
 func (b *historyBuilder) Serialize() (*persistence.SerializedHistoryEventBatch, error) {
 	eventBatch := persistence.NewHistoryEventBatch(persistence.GetDefaultHistoryVersion(), b.history)
-	history, err := b.serializer.Serialize(eventBatch)
+	history, err := b.getSerializer().Serialize(eventBatch)
 	if err != nil {
 		return nil, err
 	}
 	return history, nil
 }
 
-func (b *historyBuilder) AddWorkflowExecutionStartedEvent(
-	request *workflow.StartWorkflowExecutionRequest) *workflow.HistoryEvent {
-	event := b.newWorkflowExecutionStartedEvent(request)
+// getSerializer returns the serializer for the encoding type configured for new writes,
+// falling back to the default encoding type if the configured one is not supported
+func (b *historyBuilder) getSerializer() persistence.HistorySerializer {
+	encodingType := common.EncodingType(b.msBuilder.config.EventEncodingType())
+	serializer, err := b.serializerFactory.Get(encodingType)
+	if err != nil {
+		b.logger.WithField(logging.TagErr, err).Warnf(
+			"Unsupported history event encoding type %v, using %v.", encodingType, persistence.DefaultEncodingType)
+		serializer, _ = b.serializerFactory.Get(persistence.DefaultEncodingType)
+	}
+	return serializer
+}
+
+// getDeserializer returns the serializer matching the encoding type of a persisted event batch
+func (b *historyBuilder) getDeserializer(batch *persistence.SerializedHistoryEventBatch) (persistence.HistorySerializer, error) {
+	return b.serializerFactory.Get(batch.EncodingType)
+}
+
+func (b *historyBuilder) AddWorkflowExecutionStartedEvent(request *workflow.StartWorkflowExecutionRequest,
+	attempt int32, expirationTime time.Time, firstDecisionTaskBackoffSeconds int32,
+	priority int32) *workflow.HistoryEvent {
+	event := b.newWorkflowExecutionStartedEvent(request, attempt, expirationTime, firstDecisionTaskBackoffSeconds,
+		priority)
 
 	return b.addEventToHistory(event)
 }
@@ -77,8 +102,8 @@ func (b *historyBuilder) AddDecisionTaskScheduledEvent(taskList string,
 }
 
 func (b *historyBuilder) AddDecisionTaskStartedEvent(scheduleEventID int64, requestID string,
-	identity string) *workflow.HistoryEvent {
-	event := b.newDecisionTaskStartedEvent(scheduleEventID, requestID, identity)
+	identity string, buildID string) *workflow.HistoryEvent {
+	event := b.newDecisionTaskStartedEvent(scheduleEventID, requestID, identity, buildID)
 
 	return b.addEventToHistory(event)
 }
@@ -111,9 +136,9 @@ func (b *historyBuilder) AddActivityTaskScheduledEvent(decisionCompletedEventID
 	return b.addEventToHistory(event)
 }
 
-func (b *historyBuilder) AddActivityTaskStartedEvent(scheduleEventID int64, requestID string,
-	request *workflow.PollForActivityTaskRequest) *workflow.HistoryEvent {
-	event := b.newActivityTaskStartedEvent(scheduleEventID, requestID, request)
+func (b *historyBuilder) AddActivityTaskStartedEvent(scheduleEventID int64, attempt int32, requestID string,
+	identity string) *workflow.HistoryEvent {
+	event := b.newActivityTaskStartedEvent(scheduleEventID, attempt, requestID, identity)
 
 	return b.addEventToHistory(event)
 }
@@ -340,6 +365,13 @@ func (b *historyBuilder) AddMarkerRecordedEvent(decisionCompletedEventID int64,
 	return b.addEventToHistory(event)
 }
 
+func (b *historyBuilder) AddUpsertWorkflowSearchAttributesEvent(decisionCompletedEventID int64,
+	attributes *workflow.UpsertWorkflowSearchAttributesDecisionAttributes) *workflow.HistoryEvent {
+	event := b.newUpsertWorkflowSearchAttributesEvent(decisionCompletedEventID, attributes)
+
+	return b.addEventToHistory(event)
+}
+
 func (b *historyBuilder) AddWorkflowExecutionSignaledEvent(
 	request *workflow.SignalWorkflowExecutionRequest) *workflow.HistoryEvent {
 	event := b.newWorkflowExecutionSignaledEvent(request)
@@ -419,8 +451,9 @@ func (b *historyBuilder) addEventToHistory(event *workflow.HistoryEvent) *workfl
 	return event
 }
 
-func (b *historyBuilder) newWorkflowExecutionStartedEvent(
-	request *workflow.StartWorkflowExecutionRequest) *workflow.HistoryEvent {
+func (b *historyBuilder) newWorkflowExecutionStartedEvent(request *workflow.StartWorkflowExecutionRequest,
+	attempt int32, expirationTime time.Time, firstDecisionTaskBackoffSeconds int32,
+	priority int32) *workflow.HistoryEvent {
 	historyEvent := b.msBuilder.createNewHistoryEvent(workflow.EventTypeWorkflowExecutionStarted)
 	attributes := &workflow.WorkflowExecutionStartedEventAttributes{}
 	attributes.WorkflowType = request.WorkflowType
@@ -429,6 +462,15 @@ func (b *historyBuilder) newWorkflowExecutionStartedEvent(
 	attributes.ExecutionStartToCloseTimeoutSeconds = common.Int32Ptr(*request.ExecutionStartToCloseTimeoutSeconds)
 	attributes.TaskStartToCloseTimeoutSeconds = common.Int32Ptr(*request.TaskStartToCloseTimeoutSeconds)
 	attributes.Identity = common.StringPtr(common.StringDefault(request.Identity))
+	attributes.RetryPolicy = request.RetryPolicy
+	attributes.Attempt = common.Int32Ptr(attempt)
+	if !expirationTime.IsZero() {
+		attributes.ExpirationTimestamp = common.Int64Ptr(expirationTime.UnixNano())
+	}
+	attributes.CronSchedule = request.CronSchedule
+	attributes.SearchAttributes = request.SearchAttributes
+	attributes.FirstDecisionTaskBackoffSeconds = common.Int32Ptr(firstDecisionTaskBackoffSeconds)
+	attributes.Priority = common.Int32Ptr(priority)
 	historyEvent.WorkflowExecutionStartedEventAttributes = attributes
 
 	return historyEvent
@@ -442,10 +484,10 @@ func (b *historyBuilder) newDecisionTaskScheduledEvent(taskList string, startToC
 }
 
 func (b *historyBuilder) newDecisionTaskStartedEvent(scheduledEventID int64, requestID string,
-	identity string) *workflow.HistoryEvent {
+	identity string, buildID string) *workflow.HistoryEvent {
 	historyEvent := b.msBuilder.createNewHistoryEvent(workflow.EventTypeDecisionTaskStarted)
 
-	return setDecisionTaskStartedEventInfo(historyEvent, scheduledEventID, requestID, identity)
+	return setDecisionTaskStartedEventInfo(historyEvent, scheduledEventID, requestID, identity, buildID)
 }
 
 func (b *historyBuilder) newDecisionTaskCompletedEvent(scheduleEventID, startedEventID int64,
@@ -499,21 +541,18 @@ func (b *historyBuilder) newActivityTaskScheduledEvent(decisionTaskCompletedEven
 	attributes.StartToCloseTimeoutSeconds = common.Int32Ptr(common.Int32Default(scheduleAttributes.StartToCloseTimeoutSeconds))
 	attributes.HeartbeatTimeoutSeconds = common.Int32Ptr(common.Int32Default(scheduleAttributes.HeartbeatTimeoutSeconds))
 	attributes.DecisionTaskCompletedEventId = common.Int64Ptr(decisionTaskCompletedEventID)
+	attributes.RetryPolicy = scheduleAttributes.RetryPolicy
+	attributes.Priority = scheduleAttributes.Priority
 	historyEvent.ActivityTaskScheduledEventAttributes = attributes
 
 	return historyEvent
 }
 
-func (b *historyBuilder) newActivityTaskStartedEvent(scheduledEventID int64, requestID string,
-	request *workflow.PollForActivityTaskRequest) *workflow.HistoryEvent {
+func (b *historyBuilder) newActivityTaskStartedEvent(scheduledEventID int64, attempt int32, requestID string,
+	identity string) *workflow.HistoryEvent {
 	historyEvent := b.msBuilder.createNewHistoryEvent(workflow.EventTypeActivityTaskStarted)
-	attributes := &workflow.ActivityTaskStartedEventAttributes{}
-	attributes.ScheduledEventId = common.Int64Ptr(scheduledEventID)
-	attributes.Identity = common.StringPtr(common.StringDefault(request.Identity))
-	attributes.RequestId = common.StringPtr(requestID)
-	historyEvent.ActivityTaskStartedEventAttributes = attributes
 
-	return historyEvent
+	return setActivityTaskStartedEventInfo(historyEvent, scheduledEventID, attempt, requestID, identity)
 }
 
 func (b *historyBuilder) newActivityTaskCompletedEvent(scheduleEventID, startedEventID int64,
@@ -624,6 +663,17 @@ func (b *historyBuilder) newMarkerRecordedEventAttributes(decisionTaskCompletedE
 	return historyEvent
 }
 
+func (b *historyBuilder) newUpsertWorkflowSearchAttributesEvent(decisionTaskCompletedEventID int64,
+	request *workflow.UpsertWorkflowSearchAttributesDecisionAttributes) *workflow.HistoryEvent {
+	historyEvent := b.msBuilder.createNewHistoryEvent(workflow.EventTypeUpsertWorkflowSearchAttributes)
+	attributes := &workflow.UpsertWorkflowSearchAttributesEventAttributes{}
+	attributes.DecisionTaskCompletedEventId = common.Int64Ptr(decisionTaskCompletedEventID)
+	attributes.SearchAttributes = request.SearchAttributes
+	historyEvent.UpsertWorkflowSearchAttributesEventAttributes = attributes
+
+	return historyEvent
+}
+
 func (b *historyBuilder) newWorkflowExecutionCancelRequestedEvent(cause string,
 	request *h.RequestCancelWorkflowExecutionRequest) *workflow.HistoryEvent {
 	event := b.msBuilder.createNewHistoryEvent(workflow.EventTypeWorkflowExecutionCancelRequested)
@@ -765,6 +815,10 @@ func (b *historyBuilder) newWorkflowExecutionContinuedAsNewEvent(decisionTaskCom
 	attributes.ExecutionStartToCloseTimeoutSeconds = common.Int32Ptr(*request.ExecutionStartToCloseTimeoutSeconds)
 	attributes.TaskStartToCloseTimeoutSeconds = common.Int32Ptr(*request.TaskStartToCloseTimeoutSeconds)
 	attributes.DecisionTaskCompletedEventId = common.Int64Ptr(decisionTaskCompletedEventID)
+	attributes.BackoffStartIntervalInSeconds = common.Int32Ptr(request.GetBackoffStartIntervalInSeconds())
+	attributes.Initiator = request.Initiator
+	attributes.FailureReason = request.FailureReason
+	attributes.FailureDetails = request.FailureDetails
 	historyEvent.WorkflowExecutionContinuedAsNewEventAttributes = attributes
 
 	return historyEvent
@@ -785,6 +839,9 @@ func (b *historyBuilder) newStartChildWorkflowExecutionInitiatedEvent(decisionTa
 	attributes.Control = startAttributes.Control
 	attributes.DecisionTaskCompletedEventId = common.Int64Ptr(decisionTaskCompletedEventID)
 	attributes.WorkflowIdReusePolicy = startAttributes.WorkflowIdReusePolicy
+	attributes.RetryPolicy = startAttributes.RetryPolicy
+	attributes.CronSchedule = startAttributes.CronSchedule
+	attributes.Priority = startAttributes.Priority
 	historyEvent.StartChildWorkflowExecutionInitiatedEventAttributes = attributes
 
 	return historyEvent
@@ -908,10 +965,17 @@ func newDecisionTaskScheduledEventWithInfo(eventID, timestamp int64, taskList st
 }
 
 func newDecisionTaskStartedEventWithInfo(eventID, timestamp int64, scheduledEventID int64, requestID string,
-	identity string) *workflow.HistoryEvent {
+	identity string, buildID string) *workflow.HistoryEvent {
 	historyEvent := createNewHistoryEvent(eventID, workflow.EventTypeDecisionTaskStarted, timestamp)
 
-	return setDecisionTaskStartedEventInfo(historyEvent, scheduledEventID, requestID, identity)
+	return setDecisionTaskStartedEventInfo(historyEvent, scheduledEventID, requestID, identity, buildID)
+}
+
+func newActivityTaskStartedEventWithInfo(eventID, timestamp int64, scheduledEventID int64, attempt int32,
+	requestID string, identity string) *workflow.HistoryEvent {
+	historyEvent := createNewHistoryEvent(eventID, workflow.EventTypeActivityTaskStarted, timestamp)
+
+	return setActivityTaskStartedEventInfo(historyEvent, scheduledEventID, attempt, requestID, identity)
 }
 
 func createNewHistoryEvent(eventID int64, eventType workflow.EventType, timestamp int64) *workflow.HistoryEvent {
@@ -936,12 +1000,27 @@ func setDecisionTaskScheduledEventInfo(historyEvent *workflow.HistoryEvent, task
 }
 
 func setDecisionTaskStartedEventInfo(historyEvent *workflow.HistoryEvent, scheduledEventID int64, requestID string,
-	identity string) *workflow.HistoryEvent {
+	identity string, buildID string) *workflow.HistoryEvent {
 	attributes := &workflow.DecisionTaskStartedEventAttributes{}
 	attributes.ScheduledEventId = common.Int64Ptr(scheduledEventID)
 	attributes.Identity = common.StringPtr(identity)
 	attributes.RequestId = common.StringPtr(requestID)
+	if buildID != "" {
+		attributes.BuildID = common.StringPtr(buildID)
+	}
 	historyEvent.DecisionTaskStartedEventAttributes = attributes
 
 	return historyEvent
 }
+
+func setActivityTaskStartedEventInfo(historyEvent *workflow.HistoryEvent, scheduledEventID int64, attempt int32,
+	requestID string, identity string) *workflow.HistoryEvent {
+	attributes := &workflow.ActivityTaskStartedEventAttributes{}
+	attributes.ScheduledEventId = common.Int64Ptr(scheduledEventID)
+	attributes.Attempt = common.Int32Ptr(attempt)
+	attributes.Identity = common.StringPtr(identity)
+	attributes.RequestId = common.StringPtr(requestID)
+	historyEvent.ActivityTaskStartedEventAttributes = attributes
+
+	return historyEvent
+}
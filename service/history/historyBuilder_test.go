@@ -22,6 +22,7 @@ package history
 
 import (
 	"testing"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -647,7 +648,7 @@ func (s *historyBuilderSuite) addWorkflowExecutionStartedEvent(we workflow.Workf
 		ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(executionStartToCloseTimeout),
 		TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(taskStartToCloseTimeout),
 		Identity:                            common.StringPtr(identity),
-	})
+	}, 0, time.Now())
 
 	return e
 }
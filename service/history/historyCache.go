@@ -28,6 +28,7 @@ import (
 	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/logging"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/service/dynamicconfig"
 
 	"github.com/uber-common/bark"
 	"github.com/uber/cadence/common"
@@ -60,12 +61,16 @@ var (
 func newHistoryCache(shard ShardContext, logger bark.Logger) *historyCache {
 	opts := &cache.Options{}
 	config := shard.GetConfig()
-	opts.InitialCapacity = config.HistoryCacheInitialSize
-	opts.TTL = config.HistoryCacheTTL
+	shardFilter := dynamicconfig.ShardIDFilter(shard.GetShardID())
+	opts.InitialCapacity = config.HistoryCacheInitialSize(shardFilter)
+	opts.TTL = config.HistoryCacheTTL(shardFilter)
 	opts.Pin = true
+	opts.MaxSizeFn = func() int {
+		return config.HistoryCacheMaxSize(shardFilter)
+	}
 
 	return &historyCache{
-		Cache:            cache.New(config.HistoryCacheMaxSize, opts),
+		Cache:            cache.New(config.HistoryCacheMaxSize(shardFilter), opts),
 		shard:            shard,
 		executionManager: shard.GetExecutionManager(),
 		logger: logger.WithFields(bark.Fields{
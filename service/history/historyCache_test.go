@@ -77,7 +77,7 @@ func (s *historyCacheSuite) SetupTest() {
 }
 
 func (s *historyCacheSuite) TestHistoryCachePinning() {
-	s.mockShard.GetConfig().HistoryCacheMaxSize = 2
+	s.mockShard.GetConfig().HistoryCacheMaxSize = dynamicconfig.GetIntPropertyFn(2)
 	domain := "test_domain"
 	s.cache = newHistoryCache(s.mockShard, s.logger)
 	we := workflow.WorkflowExecution{
@@ -35,10 +35,13 @@ import (
 	hc "github.com/uber/cadence/client/history"
 	"github.com/uber/cadence/client/matching"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/archiver"
+	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/logging"
 	"github.com/uber/cadence/common/messaging"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/searchattribute"
 )
 
 const (
@@ -60,8 +63,13 @@ type (
 		tokenSerializer      common.TaskTokenSerializer
 		hSerializerFactory   persistence.HistorySerializerFactory
 		historyCache         *historyCache
+		replicator           *historyReplicator
+		resetor              *workflowResetor
+		searchAttrValidator  *searchattribute.Validator
 		metricsClient        metrics.Client
 		logger               bark.Logger
+		// archiver is nil when archival is not configured on the cluster
+		archiver archiver.Archiver
 	}
 
 	// shardContextWrapper wraps ShardContext to notify transferQueueProcessor on new tasks.
@@ -72,6 +80,12 @@ type (
 		replcatorProcessor   queueProcessor
 		historyEventNotifier historyEventNotifier
 	}
+
+	// updateWorkflowAction is the result of the action applied to mutable state by updateWorkflowExecutionWithAction
+	updateWorkflowAction struct {
+		timerTasks     []persistence.Task
+		createDecision bool
+	}
 )
 
 var _ Engine = (*historyEngineImpl)(nil)
@@ -107,7 +121,8 @@ var (
 
 // NewEngineWithShardContext creates an instance of history engine
 func NewEngineWithShardContext(shard ShardContext, visibilityMgr persistence.VisibilityManager,
-	matching matching.Client, historyClient hc.Client, historyEventNotifier historyEventNotifier, publisher messaging.Producer) Engine {
+	matching matching.Client, historyClient hc.Client, historyEventNotifier historyEventNotifier, publisher messaging.Producer,
+	archiver archiver.Archiver) Engine {
 	shardWrapper := &shardContextWrapper{
 		ShardContext:         shard,
 		historyEventNotifier: historyEventNotifier,
@@ -130,7 +145,11 @@ func NewEngineWithShardContext(shard ShardContext, visibilityMgr persistence.Vis
 		}),
 		metricsClient:        shard.GetMetricsClient(),
 		historyEventNotifier: historyEventNotifier,
+		searchAttrValidator:  searchattribute.NewValidator(shard.GetConfig().ValidSearchAttributes),
+		archiver:             archiver,
 	}
+	historyEngImpl.replicator = newHistoryReplicator(shard, historyEngImpl, historyCache, historyManager, logger)
+	historyEngImpl.resetor = newWorkflowResetor(shard, historyEngImpl, historyCache, historyManager, logger)
 	txProcessor := newTransferQueueProcessor(shard, historyEngImpl, visibilityMgr, matching, historyClient)
 	historyEngImpl.timerProcessor = newTimerQueueProcessor(shard, historyEngImpl, executionManager, logger)
 	historyEngImpl.txProcessor = txProcessor
@@ -176,6 +195,13 @@ func (e *historyEngineImpl) Stop() {
 // StartWorkflowExecution starts a workflow execution
 func (e *historyEngineImpl) StartWorkflowExecution(startRequest *h.StartWorkflowExecutionRequest) (
 	*workflow.StartWorkflowExecutionResponse, error) {
+	return e.startWorkflowHelper(startRequest, nil)
+}
+
+// startWorkflowHelper starts a workflow execution.  If a signal request is given, the signal is recorded right after
+// the started event so it is delivered to the first decision of the new run.
+func (e *historyEngineImpl) startWorkflowHelper(startRequest *h.StartWorkflowExecutionRequest,
+	signalRequest *workflow.SignalWorkflowExecutionRequest) (*workflow.StartWorkflowExecutionResponse, error) {
 	domainID, err := getDomainUUID(startRequest.DomainUUID)
 	if err != nil {
 		return nil, err
@@ -189,6 +215,12 @@ func (e *historyEngineImpl) StartWorkflowExecution(startRequest *h.StartWorkflow
 	if request.TaskStartToCloseTimeoutSeconds == nil || *request.TaskStartToCloseTimeoutSeconds <= 0 {
 		return nil, &workflow.BadRequestError{Message: "Missing or invalid TaskStartToCloseTimeoutSeconds."}
 	}
+	if err := validateRetryPolicy(request.RetryPolicy); err != nil {
+		return nil, err
+	}
+	if err := validateCronSchedule(request.GetCronSchedule()); err != nil {
+		return nil, err
+	}
 
 	execution := workflow.WorkflowExecution{
 		WorkflowId: request.WorkflowId,
@@ -208,17 +240,30 @@ func (e *historyEngineImpl) StartWorkflowExecution(startRequest *h.StartWorkflow
 	// Generate first decision task event.
 	taskList := *request.TaskList.Name
 	msBuilder := newMutableStateBuilder(e.shard.GetConfig(), e.logger)
-	startedEvent := msBuilder.AddWorkflowExecutionStartedEvent(domainID, execution, request)
+	startedEvent := msBuilder.AddWorkflowExecutionStartedEvent(domainID, execution, request,
+		startRequest.GetPriority(), e.shard.GetTimeSource().Now())
 	if startedEvent == nil {
 		return nil, &workflow.InternalServiceError{Message: "Failed to add workflow execution started event."}
 	}
+	var signalRequestedIDs []string
+	if signalRequest != nil {
+		if msBuilder.AddWorkflowExecutionSignaled(signalRequest) == nil {
+			return nil, &workflow.InternalServiceError{Message: "Failed to add workflow execution signaled event."}
+		}
+		// the request ID of the signal is recorded on the new run so a retry of the request does not signal it again
+		if requestID := signalRequest.GetRequestId(); requestID != "" {
+			signalRequestedIDs = []string{requestID}
+		}
+	}
 
 	var transferTasks []persistence.Task
 	decisionScheduleID := emptyEventID
 	decisionStartID := emptyEventID
 	decisionTimeout := int32(0)
-	if parentInfo == nil {
-		// DecisionTask is only created when it is not a Child Workflow Execution
+	firstDecisionTaskBackoffSeconds := startedEvent.WorkflowExecutionStartedEventAttributes.GetFirstDecisionTaskBackoffSeconds()
+	if parentInfo == nil && firstDecisionTaskBackoffSeconds == 0 {
+		// DecisionTask is only created when it is not a Child Workflow Execution and the first decision is not delayed
+		// by a backoff, otherwise it is scheduled by the backoff timer
 		di := msBuilder.AddDecisionTaskScheduledEvent()
 		if di == nil {
 			return nil, &workflow.InternalServiceError{Message: "Failed to add decision scheduled event."}
@@ -232,10 +277,8 @@ func (e *historyEngineImpl) StartWorkflowExecution(startRequest *h.StartWorkflow
 		decisionTimeout = di.DecisionTimeout
 	}
 
-	duration := time.Duration(*request.ExecutionStartToCloseTimeoutSeconds) * time.Second
-	timerTasks := []persistence.Task{&persistence.WorkflowTimeoutTask{
-		VisibilityTimestamp: e.shard.GetTimeSource().Now().Add(duration),
-	}}
+	timerTasks := e.getTimerBuilder(&execution).GetWorkflowStartTimerTasks(*request.ExecutionStartToCloseTimeoutSeconds,
+		firstDecisionTaskBackoffSeconds, persistence.WorkflowBackoffTimeoutTypeCron)
 	// Serialize the history
 	serializedHistory, serializedError := msBuilder.hBuilder.Serialize()
 	if serializedError != nil {
@@ -320,6 +363,18 @@ func (e *historyEngineImpl) StartWorkflowExecution(startRequest *h.StartWorkflow
 			ContinueAsNew:               !isBrandNew,
 			PreviousRunID:               prevRunID,
 			ReplicationState:            replicationState,
+			Attempt:                     msBuilder.executionInfo.Attempt,
+			HasRetryPolicy:              msBuilder.executionInfo.HasRetryPolicy,
+			InitialInterval:             msBuilder.executionInfo.InitialInterval,
+			BackoffCoefficient:          msBuilder.executionInfo.BackoffCoefficient,
+			MaximumInterval:             msBuilder.executionInfo.MaximumInterval,
+			ExpirationTime:              msBuilder.executionInfo.ExpirationTime,
+			MaximumAttempts:             msBuilder.executionInfo.MaximumAttempts,
+			NonRetriableErrors:          msBuilder.executionInfo.NonRetriableErrors,
+			CronSchedule:                msBuilder.executionInfo.CronSchedule,
+			SearchAttributes:            msBuilder.executionInfo.SearchAttributes,
+			Priority:                    msBuilder.executionInfo.Priority,
+			SignalRequestedIDs:          signalRequestedIDs,
 		})
 
 		if err != nil {
@@ -561,10 +616,11 @@ func (e *historyEngineImpl) DescribeWorkflowExecution(
 			ChildPolicy:                         common.ChildPolicyPtr(workflow.ChildPolicyTerminate),
 		},
 		WorkflowExecutionInfo: &workflow.WorkflowExecutionInfo{
-			Execution:     request.Request.Execution,
-			Type:          &workflow.WorkflowType{Name: common.StringPtr(msBuilder.executionInfo.WorkflowTypeName)},
-			StartTime:     common.Int64Ptr(msBuilder.executionInfo.StartTimestamp.UnixNano()),
-			HistoryLength: common.Int64Ptr(msBuilder.GetNextEventID() - common.FirstEventID),
+			Execution:        request.Request.Execution,
+			Type:             &workflow.WorkflowType{Name: common.StringPtr(msBuilder.executionInfo.WorkflowTypeName)},
+			StartTime:        common.Int64Ptr(msBuilder.executionInfo.StartTimestamp.UnixNano()),
+			HistoryLength:    common.Int64Ptr(msBuilder.GetNextEventID() - common.FirstEventID),
+			SearchAttributes: getSearchAttributes(msBuilder.executionInfo.SearchAttributes),
 		},
 	}
 	if msBuilder.executionInfo.State == persistence.WorkflowStateCompleted {
@@ -578,6 +634,7 @@ func (e *historyEngineImpl) DescribeWorkflowExecution(
 		for _, pi := range msBuilder.pendingActivityInfoIDs {
 			ai := &workflow.PendingActivityInfo{
 				ActivityID: common.StringPtr(pi.ActivityID),
+				Attempt:    common.Int32Ptr(pi.Attempt),
 			}
 			state := workflow.PendingActivityStateScheduled
 			if pi.CancelRequested {
@@ -741,6 +798,7 @@ func (e *historyEngineImpl) RecordActivityTaskStarted(
 				return nil, &workflow.InternalServiceError{Message: "Corrupted workflow execution state."}
 			}
 			response.ScheduledEvent = scheduledEvent
+			response.Attempt = common.Int64Ptr(int64(ai.Attempt))
 
 			if ai.StartedID != emptyEventID {
 				// If activity is started as part of the current request scope then return a positive response
@@ -922,7 +980,15 @@ Update_History_Loop:
 					failCause = workflow.DecisionTaskFailedCauseBadCompleteWorkflowExecutionAttributes
 					break Process_Decision_Loop
 				}
-				if e := msBuilder.AddCompletedWorkflowEvent(completedID, attributes); e == nil {
+
+				// A cron workflow never completes, its next run is started at the next time the schedule fires
+				if cronBackoff := msBuilder.getCronBackoffInterval(e.shard.GetTimeSource().Now()); cronBackoff != backoff.NoBackoff {
+					continueAsNewBuilder, continueAsNewTimerTasks, err = e.addContinueAsNewEventWithBackoff(msBuilder,
+						tBuilder, completedID, cronBackoff, workflow.ContinueAsNewInitiatorCronSchedule, nil, nil)
+					if err != nil {
+						return err
+					}
+				} else if e := msBuilder.AddCompletedWorkflowEvent(completedID, attributes); e == nil {
 					return &workflow.InternalServiceError{Message: "Unable to add complete workflow event."}
 				}
 				isComplete = true
@@ -948,7 +1014,17 @@ Update_History_Loop:
 					failCause = workflow.DecisionTaskFailedCauseBadFailWorkflowExecutionAttributes
 					break Process_Decision_Loop
 				}
-				if e := msBuilder.AddFailWorkflowEvent(completedID, attributes); e == nil {
+
+				// Instead of failing, start the next run if the workflow should be retried or it is a cron workflow
+				backoffInterval, initiator := msBuilder.getFailureBackoffInterval(attributes.GetReason(),
+					e.shard.GetTimeSource().Now())
+				if backoffInterval != backoff.NoBackoff {
+					continueAsNewBuilder, continueAsNewTimerTasks, err = e.addContinueAsNewEventWithBackoff(msBuilder,
+						tBuilder, completedID, backoffInterval, initiator, attributes.Reason, attributes.Details)
+					if err != nil {
+						return err
+					}
+				} else if e := msBuilder.AddFailWorkflowEvent(completedID, attributes); e == nil {
 					return &workflow.InternalServiceError{Message: "Unable to add fail workflow event."}
 				}
 				isComplete = true
@@ -1044,6 +1120,18 @@ Update_History_Loop:
 				}
 				msBuilder.AddRecordMarkerEvent(completedID, attributes)
 
+			case workflow.DecisionTypeUpsertWorkflowSearchAttributes:
+				e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
+					metrics.DecisionTypeUpsertWorkflowSearchAttributesCounter)
+				attributes := d.UpsertWorkflowSearchAttributesDecisionAttributes
+				if err = e.validateUpsertWorkflowSearchAttributes(attributes); err != nil {
+					failDecision = true
+					failCause = workflow.DecisionTaskFailedCauseBadSearchAttributes
+					break Process_Decision_Loop
+				}
+				msBuilder.AddUpsertWorkflowSearchAttributesEvent(completedID, attributes)
+				transferTasks = append(transferTasks, &persistence.UpsertWorkflowSearchAttributesTask{})
+
 			case workflow.DecisionTypeRequestCancelExternalWorkflowExecution:
 				e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
 					metrics.DecisionTypeCancelExternalWorkflowCounter)
@@ -1141,21 +1229,23 @@ Update_History_Loop:
 					failCause = workflow.DecisionTaskFailedCauseBadContinueAsNewAttributes
 					break Process_Decision_Loop
 				}
-				runID := uuid.New()
-				_, newStateBuilder, err := msBuilder.AddContinueAsNewEvent(completedID, domainID, runID, attributes)
+				if attributes.SearchAttributes != nil {
+					if attributes.SearchAttributes, err = e.searchAttrValidator.ValidateSearchAttributes(
+						attributes.SearchAttributes); err != nil {
+						failDecision = true
+						failCause = workflow.DecisionTaskFailedCauseBadSearchAttributes
+						break Process_Decision_Loop
+					}
+				}
+				newStateBuilder, newTimerTasks, err := e.addContinueAsNewEvent(msBuilder, tBuilder, completedID,
+					attributes)
 				if err != nil {
 					return nil
 				}
 
-				// add timer task to new workflow
-				duration := time.Duration(*attributes.ExecutionStartToCloseTimeoutSeconds) * time.Second
-				continueAsNewTimerTasks = []persistence.Task{&persistence.WorkflowTimeoutTask{
-					VisibilityTimestamp: e.shard.GetTimeSource().Now().Add(duration),
-				}}
-				msBuilder.continueAsNew.TimerTasks = continueAsNewTimerTasks
-
 				isComplete = true
 				continueAsNewBuilder = newStateBuilder
+				continueAsNewTimerTasks = newTimerTasks
 
 			case workflow.DecisionTypeStartChildWorkflowExecution:
 				e.metricsClient.IncCounter(metrics.HistoryRespondDecisionTaskCompletedScope,
@@ -1381,8 +1471,8 @@ func (e *historyEngineImpl) RespondActivityTaskFailed(req *h.RespondActivityTask
 		RunId:      common.StringPtr(token.RunID),
 	}
 
-	return e.updateWorkflowExecution(domainID, workflowExecution, false, true,
-		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) ([]persistence.Task, error) {
+	return e.updateWorkflowExecutionWithAction(domainID, workflowExecution, false,
+		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) (*updateWorkflowAction, error) {
 			if !msBuilder.isWorkflowExecutionRunning() {
 				return nil, ErrWorkflowCompleted
 			}
@@ -1407,13 +1497,21 @@ func (e *historyEngineImpl) RespondActivityTaskFailed(req *h.RespondActivityTask
 				return nil, ErrActivityTaskNotFound
 			}
 
+			if msBuilder.RetryActivity(ai, request.GetReason(), e.shard.GetTimeSource().Now()) {
+				// Activity is retried after backoff, no need to record the failure in history or schedule a decision
+				e.metricsClient.IncCounter(metrics.HistoryRespondActivityTaskFailedScope, metrics.ActivityRetryCounter)
+				return &updateWorkflowAction{
+					timerTasks: []persistence.Task{tBuilder.AddActivityRetryTimer(ai)},
+				}, nil
+			}
+
 			startedID := ai.StartedID
 			if msBuilder.AddActivityTaskFailedEvent(scheduleID, startedID, request) == nil {
 				// Unable to add ActivityTaskFailed event to history
 				return nil, &workflow.InternalServiceError{Message: "Unable to add ActivityTaskFailed event to history."}
 			}
 
-			return nil, nil
+			return &updateWorkflowAction{createDecision: true}, nil
 		})
 }
 
@@ -1555,8 +1653,8 @@ func (e *historyEngineImpl) RequestCancelWorkflowExecution(
 		RunId:      request.WorkflowExecution.RunId,
 	}
 
-	return e.updateWorkflowExecution(domainID, execution, false, true,
-		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) ([]persistence.Task, error) {
+	return e.updateWorkflowExecutionWithAction(domainID, execution, false,
+		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) (*updateWorkflowAction, error) {
 			if !msBuilder.isWorkflowExecutionRunning() {
 				return nil, ErrWorkflowCompleted
 			}
@@ -1576,7 +1674,7 @@ func (e *historyEngineImpl) RequestCancelWorkflowExecution(
 				if cancelRequest.RequestId != nil {
 					requestID := *cancelRequest.RequestId
 					if requestID != "" && cancelRequestID == requestID {
-						return nil, nil
+						return newScheduleDecisionAction(msBuilder), nil
 					}
 				}
 				// if we consider workflow cancellation idempotent, then this error is redundant
@@ -1588,7 +1686,7 @@ func (e *historyEngineImpl) RequestCancelWorkflowExecution(
 				return nil, &workflow.InternalServiceError{Message: "Unable to cancel workflow execution."}
 			}
 
-			return nil, nil
+			return newScheduleDecisionAction(msBuilder), nil
 		})
 }
 
@@ -1605,8 +1703,8 @@ func (e *historyEngineImpl) SignalWorkflowExecution(signalRequest *h.SignalWorkf
 		RunId:      request.WorkflowExecution.RunId,
 	}
 
-	return e.updateWorkflowExecution(domainID, execution, false, true,
-		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) ([]persistence.Task, error) {
+	return e.updateWorkflowExecutionWithAction(domainID, execution, false,
+		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) (*updateWorkflowAction, error) {
 			if !msBuilder.isWorkflowExecutionRunning() {
 				return nil, ErrWorkflowCompleted
 			}
@@ -1620,20 +1718,89 @@ func (e *historyEngineImpl) SignalWorkflowExecution(signalRequest *h.SignalWorkf
 				}
 			}
 
-			// deduplicate by request id for signal decision
-			if requestID := request.GetRequestId(); requestID != "" {
-				if msBuilder.isSignalRequested(requestID) {
-					return nil, nil
+			return addWorkflowExecutionSignaled(msBuilder, request)
+		})
+}
+
+// SignalWithStartWorkflowExecution signals the current run of the workflow execution if it is running, otherwise it
+// starts a new run using the workflow ID reuse policy of the request and records the signal as its first event.
+func (e *historyEngineImpl) SignalWithStartWorkflowExecution(
+	signalWithStartRequest *h.SignalWithStartWorkflowExecutionRequest) (*workflow.StartWorkflowExecutionResponse, error) {
+	domainID, err := getDomainUUID(signalWithStartRequest.DomainUUID)
+	if err != nil {
+		return nil, err
+	}
+	request := signalWithStartRequest.SignalWithStartRequest
+	signalRequest := &workflow.SignalWorkflowExecutionRequest{
+		Domain:            request.Domain,
+		WorkflowExecution: &workflow.WorkflowExecution{WorkflowId: request.WorkflowId},
+		SignalName:        request.SignalName,
+		Input:             request.SignalInput,
+		Identity:          request.Identity,
+		RequestId:         request.RequestId,
+		Control:           request.Control,
+	}
+	execution := workflow.WorkflowExecution{
+		WorkflowId: request.WorkflowId,
+	}
+
+	// Signal the current run first, a new run is only started if there is no running one.  Both a missing and a closed
+	// current run surface as EntityNotExistsError.
+	signalCurrentRun := func() (*workflow.StartWorkflowExecutionResponse, error) {
+		var runID string
+		err := e.updateWorkflowExecutionWithAction(domainID, execution, false,
+			func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) (*updateWorkflowAction, error) {
+				if !msBuilder.isWorkflowExecutionRunning() {
+					return nil, ErrWorkflowCompleted
 				}
-				msBuilder.addSignalRequested(requestID)
-			}
 
-			if msBuilder.AddWorkflowExecutionSignaled(request) == nil {
-				return nil, &workflow.InternalServiceError{Message: "Unable to signal workflow execution."}
-			}
+				runID = msBuilder.executionInfo.RunID
+				return addWorkflowExecutionSignaled(msBuilder, signalRequest)
+			})
+		if err != nil {
+			return nil, err
+		}
+		return &workflow.StartWorkflowExecutionResponse{RunId: common.StringPtr(runID)}, nil
+	}
+
+	response, err := signalCurrentRun()
+	if _, ok := err.(*workflow.EntityNotExistsError); !ok {
+		return response, err
+	}
+
+	startRequest := &h.StartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		StartRequest: &workflow.StartWorkflowExecutionRequest{
+			Domain:                              request.Domain,
+			WorkflowId:                          request.WorkflowId,
+			WorkflowType:                        request.WorkflowType,
+			TaskList:                            request.TaskList,
+			Input:                               request.Input,
+			ExecutionStartToCloseTimeoutSeconds: request.ExecutionStartToCloseTimeoutSeconds,
+			TaskStartToCloseTimeoutSeconds:      request.TaskStartToCloseTimeoutSeconds,
+			Identity:                            request.Identity,
+			RequestId:                           request.RequestId,
+			WorkflowIdReusePolicy:               request.WorkflowIdReusePolicy,
+			RetryPolicy:                         request.RetryPolicy,
+			CronSchedule:                        request.CronSchedule,
+			SearchAttributes:                    request.SearchAttributes,
+		},
+	}
 
-			return nil, nil
-		})
+	response, err = e.startWorkflowHelper(startRequest, signalRequest)
+	startErr, ok := err.(*workflow.WorkflowExecutionAlreadyStartedError)
+	if !ok {
+		return response, err
+	}
+
+	// A concurrent request started a run after the current run was found closed, the signal is delivered to that run
+	// instead.  The start error is returned if the current run is still closed, which means the workflow ID reuse
+	// policy rejected the new run.
+	response, err = signalCurrentRun()
+	if _, ok := err.(*workflow.EntityNotExistsError); ok {
+		return nil, startErr
+	}
+	return response, err
 }
 
 // RemoveSignalMutableState remove the signal request id in signal_requested for deduplicate
@@ -1684,6 +1851,13 @@ func (e *historyEngineImpl) TerminateWorkflowExecution(terminateRequest *h.Termi
 		})
 }
 
+// ResetWorkflowExecution starts a new run of the workflow from a previous decision of the given run and terminates
+// the given run if it is still running
+func (e *historyEngineImpl) ResetWorkflowExecution(resetRequest *h.ResetWorkflowExecutionRequest) (
+	*workflow.ResetWorkflowExecutionResponse, error) {
+	return e.resetor.ResetWorkflowExecution(resetRequest)
+}
+
 // ScheduleDecisionTask schedules a decision if no outstanding decision found
 func (e *historyEngineImpl) ScheduleDecisionTask(scheduleRequest *h.ScheduleDecisionTaskRequest) error {
 	domainID, err := getDomainUUID(scheduleRequest.DomainUUID)
@@ -1695,15 +1869,13 @@ func (e *historyEngineImpl) ScheduleDecisionTask(scheduleRequest *h.ScheduleDeci
 		RunId:      scheduleRequest.WorkflowExecution.RunId,
 	}
 
-	return e.updateWorkflowExecution(domainID, execution, false, true,
-		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) ([]persistence.Task, error) {
+	return e.updateWorkflowExecutionWithAction(domainID, execution, false,
+		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) (*updateWorkflowAction, error) {
 			if !msBuilder.isWorkflowExecutionRunning() {
 				return nil, ErrWorkflowCompleted
 			}
 
-			// Noop
-
-			return nil, nil
+			return newScheduleDecisionAction(msBuilder), nil
 		})
 }
 
@@ -1756,9 +1928,30 @@ func (e *historyEngineImpl) RecordChildExecutionCompleted(completionRequest *h.R
 		})
 }
 
+// ReplicateEvents applies history events received from a remote cluster to the local copy of the execution
+func (e *historyEngineImpl) ReplicateEvents(replicateRequest *h.ReplicateEventsRequest) error {
+	return e.replicator.ApplyEvents(replicateRequest)
+}
+
 func (e *historyEngineImpl) updateWorkflowExecution(domainID string, execution workflow.WorkflowExecution,
 	createDeletionTask, createDecisionTask bool,
 	action func(builder *mutableStateBuilder, tBuilder *timerBuilder) ([]persistence.Task, error)) error {
+	return e.updateWorkflowExecutionWithAction(domainID, execution, createDeletionTask,
+		func(msBuilder *mutableStateBuilder, tBuilder *timerBuilder) (*updateWorkflowAction, error) {
+			timerTasks, err := action(msBuilder, tBuilder)
+			if err != nil {
+				return nil, err
+			}
+
+			return &updateWorkflowAction{timerTasks: timerTasks, createDecision: createDecisionTask}, nil
+		})
+}
+
+// updateWorkflowExecutionWithAction is same as updateWorkflowExecution, except that action decides whether a new
+// decision needs to be scheduled.
+func (e *historyEngineImpl) updateWorkflowExecutionWithAction(domainID string, execution workflow.WorkflowExecution,
+	createDeletionTask bool,
+	action func(builder *mutableStateBuilder, tBuilder *timerBuilder) (*updateWorkflowAction, error)) error {
 
 	context, release, err0 := e.historyCache.getOrCreateWorkflowExecution(domainID, execution)
 	if err0 != nil {
@@ -1774,10 +1967,9 @@ Update_History_Loop:
 		}
 		tBuilder := e.getTimerBuilder(&context.workflowExecution)
 
-		var timerTasks []persistence.Task
-		var err error
 		// conduct caller action
-		if timerTasks, err = action(msBuilder, tBuilder); err != nil {
+		postActions, err := action(msBuilder, tBuilder)
+		if err != nil {
 			if err == ErrStaleState {
 				// Handler detected that cached workflow mutable could potentially be stale
 				// Reload workflow execution history
@@ -1789,6 +1981,7 @@ Update_History_Loop:
 			return err
 		}
 
+		timerTasks := postActions.timerTasks
 		var transferTasks []persistence.Task
 
 		if createDeletionTask {
@@ -1800,7 +1993,7 @@ Update_History_Loop:
 			timerTasks = append(timerTasks, timerT)
 		}
 
-		if createDecisionTask {
+		if postActions.createDecision {
 			// Create a transfer task to schedule a decision task
 			if !msBuilder.HasPendingDecisionTask() {
 				di := msBuilder.AddDecisionTaskScheduledEvent()
@@ -1838,6 +2031,30 @@ Update_History_Loop:
 	return ErrMaxAttemptsExceeded
 }
 
+// newScheduleDecisionAction schedules a decision if there is no pending one, unless the first decision of the run is
+// delayed by a retry or cron backoff.  In that case the first decision is scheduled by the backoff timer.
+func newScheduleDecisionAction(msBuilder *mutableStateBuilder) *updateWorkflowAction {
+	return &updateWorkflowAction{createDecision: !msBuilder.hasPendingFirstDecisionTaskBackoff()}
+}
+
+// addWorkflowExecutionSignaled records the signal unless a signal with the same request ID was already recorded
+func addWorkflowExecutionSignaled(msBuilder *mutableStateBuilder,
+	request *workflow.SignalWorkflowExecutionRequest) (*updateWorkflowAction, error) {
+	// deduplicate by request id for signal decision
+	if requestID := request.GetRequestId(); requestID != "" {
+		if msBuilder.isSignalRequested(requestID) {
+			return newScheduleDecisionAction(msBuilder), nil
+		}
+		msBuilder.addSignalRequested(requestID)
+	}
+
+	if msBuilder.AddWorkflowExecutionSignaled(request) == nil {
+		return nil, &workflow.InternalServiceError{Message: "Unable to signal workflow execution."}
+	}
+
+	return newScheduleDecisionAction(msBuilder), nil
+}
+
 func (e *historyEngineImpl) getDeleteWorkflowTasks(
 	domainID string,
 	tBuilder *timerBuilder,
@@ -1861,6 +2078,94 @@ func (e *historyEngineImpl) getDeleteWorkflowTasks(
 	return closeTask, cleanupTask, nil
 }
 
+// addContinueAsNewEvent closes the current run with a continued as new event and creates the next run along with the
+// timer tasks of the next run.
+func (e *historyEngineImpl) addContinueAsNewEvent(msBuilder *mutableStateBuilder, tBuilder *timerBuilder,
+	decisionCompletedEventID int64, attributes *workflow.ContinueAsNewWorkflowExecutionDecisionAttributes) (
+	*mutableStateBuilder, []persistence.Task, error) {
+	domainID := msBuilder.executionInfo.DomainID
+	_, newStateBuilder, err := msBuilder.AddContinueAsNewEvent(decisionCompletedEventID, domainID, uuid.New(),
+		attributes, e.shard.GetTimeSource().Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	backoffTimeoutType := persistence.WorkflowBackoffTimeoutTypeCron
+	if attributes.GetInitiator() == workflow.ContinueAsNewInitiatorRetryPolicy {
+		backoffTimeoutType = persistence.WorkflowBackoffTimeoutTypeRetry
+	}
+	timerTasks := tBuilder.GetWorkflowStartTimerTasks(attributes.GetExecutionStartToCloseTimeoutSeconds(),
+		attributes.GetBackoffStartIntervalInSeconds(), backoffTimeoutType)
+	msBuilder.continueAsNew.TimerTasks = timerTasks
+
+	return newStateBuilder, timerTasks, nil
+}
+
+// addContinueAsNewEventWithBackoff starts the next run of the workflow execution after the given backoff, which is
+// used to retry a failed run or to start the next run of a cron workflow.  The next run carries over the input and
+// the configuration of the current run.
+func (e *historyEngineImpl) addContinueAsNewEventWithBackoff(msBuilder *mutableStateBuilder, tBuilder *timerBuilder,
+	decisionCompletedEventID int64, backoffInterval time.Duration, initiator workflow.ContinueAsNewInitiator,
+	failureReason *string, failureDetails []byte) (*mutableStateBuilder, []persistence.Task, error) {
+	startedEvent, err := e.getWorkflowStartedEvent(msBuilder)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	startAttributes := startedEvent.WorkflowExecutionStartedEventAttributes
+	attributes := &workflow.ContinueAsNewWorkflowExecutionDecisionAttributes{
+		WorkflowType:                        startAttributes.WorkflowType,
+		TaskList:                            startAttributes.TaskList,
+		Input:                               startAttributes.Input,
+		ExecutionStartToCloseTimeoutSeconds: startAttributes.ExecutionStartToCloseTimeoutSeconds,
+		TaskStartToCloseTimeoutSeconds:      startAttributes.TaskStartToCloseTimeoutSeconds,
+		BackoffStartIntervalInSeconds:       common.Int32Ptr(int32(backoffInterval.Seconds())),
+		RetryPolicy:                         startAttributes.RetryPolicy,
+		Initiator:                           initiator.Ptr(),
+		FailureReason:                       failureReason,
+		FailureDetails:                      failureDetails,
+		CronSchedule:                        startAttributes.CronSchedule,
+	}
+
+	return e.addContinueAsNewEvent(msBuilder, tBuilder, decisionCompletedEventID, attributes)
+}
+
+// getWorkflowStartedEvent loads the started event of the current run from history
+func (e *historyEngineImpl) getWorkflowStartedEvent(msBuilder *mutableStateBuilder) (*workflow.HistoryEvent, error) {
+	response, err := e.historyMgr.GetWorkflowExecutionHistory(&persistence.GetWorkflowExecutionHistoryRequest{
+		DomainID: msBuilder.executionInfo.DomainID,
+		Execution: workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr(msBuilder.executionInfo.WorkflowID),
+			RunId:      common.StringPtr(msBuilder.executionInfo.RunID),
+		},
+		FirstEventID: firstEventID,
+		NextEventID:  firstEventID + 1,
+		PageSize:     1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, batch := range response.Events {
+		persistence.SetSerializedHistoryDefaults(&batch)
+		serializer, err := e.hSerializerFactory.Get(batch.EncodingType)
+		if err != nil {
+			return nil, err
+		}
+		history, err := serializer.Deserialize(&batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range history.Events {
+			if event.GetEventType() == workflow.EventTypeWorkflowExecutionStarted {
+				return event, nil
+			}
+		}
+	}
+
+	return nil, &workflow.InternalServiceError{Message: "Unable to load workflow execution started event."}
+}
+
 func (e *historyEngineImpl) createRecordDecisionTaskStartedResponse(domainID string, msBuilder *mutableStateBuilder,
 	di *decisionInfo, identity string) *h.RecordDecisionTaskStartedResponse {
 	response := &h.RecordDecisionTaskStartedResponse{}
@@ -1977,6 +2282,46 @@ func validateActivityScheduleAttributes(attributes *workflow.ScheduleActivityTas
 		return &workflow.BadRequestError{Message: "Ac valid HeartbeatTimeoutSeconds is not set on decision."}
 	}
 
+	return validateRetryPolicy(attributes.RetryPolicy)
+}
+
+func validateRetryPolicy(policy *workflow.RetryPolicy) error {
+	if policy == nil {
+		// nil policy is valid which means no retry
+		return nil
+	}
+	if policy.GetInitialIntervalInSeconds() <= 0 {
+		return &workflow.BadRequestError{Message: "InitialIntervalInSeconds must be greater than 0 on retry policy."}
+	}
+	if policy.GetBackoffCoefficient() < 1 {
+		return &workflow.BadRequestError{Message: "BackoffCoefficient cannot be less than 1 on retry policy."}
+	}
+	if policy.GetMaximumIntervalInSeconds() < 0 {
+		return &workflow.BadRequestError{Message: "MaximumIntervalInSeconds cannot be less than 0 on retry policy."}
+	}
+	if policy.GetMaximumIntervalInSeconds() > 0 && policy.GetMaximumIntervalInSeconds() < policy.GetInitialIntervalInSeconds() {
+		return &workflow.BadRequestError{Message: "MaximumIntervalInSeconds cannot be less than InitialIntervalInSeconds on retry policy."}
+	}
+	if policy.GetMaximumAttempts() < 0 {
+		return &workflow.BadRequestError{Message: "MaximumAttempts cannot be less than 0 on retry policy."}
+	}
+	if policy.GetExpirationIntervalInSeconds() < 0 {
+		return &workflow.BadRequestError{Message: "ExpirationIntervalInSeconds cannot be less than 0 on retry policy."}
+	}
+	if policy.GetMaximumAttempts() == 0 && policy.GetExpirationIntervalInSeconds() == 0 {
+		return &workflow.BadRequestError{Message: "MaximumAttempts and ExpirationIntervalInSeconds are both 0 on retry policy. At least one of them must be specified."}
+	}
+	return nil
+}
+
+func validateCronSchedule(cronSchedule string) error {
+	if cronSchedule == "" {
+		// empty cron schedule is valid which means the workflow is not a cron workflow
+		return nil
+	}
+	if err := backoff.ValidateSchedule(cronSchedule); err != nil {
+		return &workflow.BadRequestError{Message: fmt.Sprintf("Invalid CronSchedule: %v.", err)}
+	}
 	return nil
 }
 
@@ -2086,6 +2431,25 @@ func validateSignalExternalWorkflowExecutionAttributes(attributes *workflow.Sign
 	return nil
 }
 
+// validateUpsertWorkflowSearchAttributes validates the upserted search attributes and replaces their values with
+// the canonical encoding used by the visibility store
+func (e *historyEngineImpl) validateUpsertWorkflowSearchAttributes(
+	attributes *workflow.UpsertWorkflowSearchAttributesDecisionAttributes) error {
+	if attributes == nil {
+		return &workflow.BadRequestError{Message: "UpsertWorkflowSearchAttributesDecisionAttributes is not set on decision."}
+	}
+	if len(attributes.SearchAttributes.GetIndexedFields()) == 0 {
+		return &workflow.BadRequestError{Message: "SearchAttributes is not set on decision."}
+	}
+
+	searchAttributes, err := e.searchAttrValidator.ValidateSearchAttributes(attributes.SearchAttributes)
+	if err != nil {
+		return err
+	}
+	attributes.SearchAttributes = searchAttributes
+	return nil
+}
+
 func validateContinueAsNewWorkflowExecutionAttributes(executionInfo *persistence.WorkflowExecutionInfo,
 	attributes *workflow.ContinueAsNewWorkflowExecutionDecisionAttributes) error {
 	if attributes == nil {
@@ -2112,7 +2476,17 @@ func validateContinueAsNewWorkflowExecutionAttributes(executionInfo *persistence
 		attributes.TaskStartToCloseTimeoutSeconds = common.Int32Ptr(executionInfo.DecisionTimeoutValue)
 	}
 
-	return nil
+	if attributes.GetBackoffStartIntervalInSeconds() < 0 {
+		return &workflow.BadRequestError{Message: "BackoffStartIntervalInSeconds cannot be less than 0 on decision."}
+	}
+
+	// Retry and cron runs are only started by the server
+	attributes.Initiator = workflow.ContinueAsNewInitiatorDecider.Ptr()
+
+	if err := validateRetryPolicy(attributes.RetryPolicy); err != nil {
+		return err
+	}
+	return validateCronSchedule(attributes.GetCronSchedule())
 }
 
 func validateStartChildExecutionAttributes(parentInfo *persistence.WorkflowExecutionInfo,
@@ -2148,7 +2522,10 @@ func validateStartChildExecutionAttributes(parentInfo *persistence.WorkflowExecu
 		attributes.TaskStartToCloseTimeoutSeconds = common.Int32Ptr(parentInfo.DecisionTimeoutValue)
 	}
 
-	return nil
+	if err := validateRetryPolicy(attributes.RetryPolicy); err != nil {
+		return err
+	}
+	return validateCronSchedule(attributes.GetCronSchedule())
 }
 
 func getDomainUUID(domainUUID *string) (string, error) {
@@ -145,6 +145,7 @@ func (s *engine2Suite) SetupTest() {
 	s.mockClusterMetadata.On("GetCurrentClusterName").Return(cluster.TestCurrentClusterName)
 	h.txProcessor = newTransferQueueProcessor(mockShard, h, s.mockVisibilityMgr, s.mockMatchingClient, s.mockHistoryClient)
 	h.timerProcessor = newTimerQueueProcessor(mockShard, h, s.mockExecutionMgr, s.logger)
+	h.resetor = newWorkflowResetor(mockShard, h, historyCache, s.mockHistoryMgr, s.logger)
 	s.historyEngine = h
 }
 
@@ -979,3 +980,593 @@ func (s *engine2Suite) getBuilder(domainID string, we workflow.WorkflowExecution
 
 	return context.msBuilder
 }
+
+func (s *engine2Suite) TestSignalWithStartWorkflowExecution_JustSignal() {
+	domainID := "domainId"
+	workflowID := "wId"
+	runID := "rId"
+	identity := "testIdentity"
+	tl := "testTaskList"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(workflowID),
+		RunId:      common.StringPtr(runID),
+	}
+
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, false)
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	gceResponse := &persistence.GetCurrentExecutionResponse{RunID: runID}
+
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(gceResponse, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+
+	resp, err := s.historyEngine.SignalWithStartWorkflowExecution(&h.SignalWithStartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		SignalWithStartRequest: &workflow.SignalWithStartWorkflowExecutionRequest{
+			Domain:      common.StringPtr(domainID),
+			WorkflowId:  common.StringPtr(workflowID),
+			Identity:    common.StringPtr(identity),
+			SignalName:  common.StringPtr("my signal name"),
+			SignalInput: []byte("test input"),
+		},
+	})
+	s.Nil(err)
+	s.Equal(runID, resp.GetRunId())
+}
+
+func (s *engine2Suite) TestSignalWithStartWorkflowExecution_WorkflowNotExist() {
+	domainID := "domainId"
+	workflowID := "wId"
+	identity := "testIdentity"
+
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(nil, &workflow.EntityNotExistsError{}).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.MatchedBy(func(request *persistence.AppendHistoryEventsRequest) bool {
+		return request.FirstEventID == firstEventID
+	})).Return(nil).Once()
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.MatchedBy(func(request *persistence.CreateWorkflowExecutionRequest) bool {
+		// started, signaled and decision scheduled events
+		return !request.ContinueAsNew && request.NextEventID == firstEventID+3
+	})).Return(&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+
+	resp, err := s.historyEngine.SignalWithStartWorkflowExecution(&h.SignalWithStartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		SignalWithStartRequest: &workflow.SignalWithStartWorkflowExecutionRequest{
+			Domain:       common.StringPtr(domainID),
+			WorkflowId:   common.StringPtr(workflowID),
+			WorkflowType: &workflow.WorkflowType{Name: common.StringPtr("wType")},
+			TaskList:     &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr(identity),
+			SignalName:                          common.StringPtr("my signal name"),
+			SignalInput:                         []byte("test input"),
+			RequestId:                           common.StringPtr(uuid.New()),
+		},
+	})
+	s.Nil(err)
+	s.NotNil(resp.RunId)
+}
+
+func (s *engine2Suite) TestSignalWithStartWorkflowExecution_WorkflowNotRunning() {
+	domainID := "domainId"
+	workflowID := "wId"
+	runID := "rId"
+	identity := "testIdentity"
+	tl := "testTaskList"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(workflowID),
+		RunId:      common.StringPtr(runID),
+	}
+
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, false)
+	ms := createMutableState(msBuilder)
+	ms.ExecutionInfo.State = persistence.WorkflowStateCompleted
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	gceResponse := &persistence.GetCurrentExecutionResponse{RunID: runID}
+
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(gceResponse, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On(
+		"CreateWorkflowExecution",
+		mock.MatchedBy(func(request *persistence.CreateWorkflowExecutionRequest) bool { return request.ContinueAsNew == false }),
+	).Return(nil, &persistence.WorkflowExecutionAlreadyStartedError{
+		Msg:            "random message",
+		StartRequestID: "oldRequestID",
+		RunID:          runID,
+		State:          persistence.WorkflowStateCompleted,
+		CloseStatus:    persistence.WorkflowCloseStatusCompleted,
+	}).Once()
+	s.mockExecutionMgr.On(
+		"CreateWorkflowExecution",
+		mock.MatchedBy(func(request *persistence.CreateWorkflowExecutionRequest) bool {
+			return request.ContinueAsNew == true && request.PreviousRunID == runID
+		}),
+	).Return(&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+
+	resp, err := s.historyEngine.SignalWithStartWorkflowExecution(&h.SignalWithStartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		SignalWithStartRequest: &workflow.SignalWithStartWorkflowExecutionRequest{
+			Domain:       common.StringPtr(domainID),
+			WorkflowId:   common.StringPtr(workflowID),
+			WorkflowType: &workflow.WorkflowType{Name: common.StringPtr("wType")},
+			TaskList:     &workflow.TaskList{Name: common.StringPtr(tl)},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr(identity),
+			SignalName:                          common.StringPtr("my signal name"),
+			SignalInput:                         []byte("test input"),
+			RequestId:                           common.StringPtr("newRequestID"),
+			WorkflowIdReusePolicy:               workflow.WorkflowIdReusePolicyAllowDuplicate.Ptr(),
+		},
+	})
+	s.Nil(err)
+	s.NotNil(resp.RunId)
+	s.NotEqual(runID, resp.GetRunId())
+}
+
+func (s *engine2Suite) TestSignalWithStartWorkflowExecution_RecordSignalRequestID() {
+	domainID := "domainId"
+	workflowID := "wId"
+	requestID := uuid.New()
+
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(nil, &workflow.EntityNotExistsError{}).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.MatchedBy(func(request *persistence.CreateWorkflowExecutionRequest) bool {
+		return len(request.SignalRequestedIDs) == 1 && request.SignalRequestedIDs[0] == requestID
+	})).Return(&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+
+	resp, err := s.historyEngine.SignalWithStartWorkflowExecution(&h.SignalWithStartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		SignalWithStartRequest: &workflow.SignalWithStartWorkflowExecutionRequest{
+			Domain:       common.StringPtr(domainID),
+			WorkflowId:   common.StringPtr(workflowID),
+			WorkflowType: &workflow.WorkflowType{Name: common.StringPtr("wType")},
+			TaskList:     &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr("testIdentity"),
+			SignalName:                          common.StringPtr("my signal name"),
+			SignalInput:                         []byte("test input"),
+			RequestId:                           common.StringPtr(requestID),
+		},
+	})
+	s.Nil(err)
+	s.NotNil(resp.RunId)
+}
+
+func (s *engine2Suite) TestSignalWithStartWorkflowExecution_Retry() {
+	domainID := "domainId"
+	workflowID := "wId"
+	runID := "rId"
+	identity := "testIdentity"
+	tl := "testTaskList"
+	requestID := uuid.New()
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(workflowID),
+		RunId:      common.StringPtr(runID),
+	}
+
+	// the run started by the first attempt of the request has recorded its signal request ID
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, false)
+	ms := createMutableState(msBuilder)
+	ms.SignalRequestedIDs = map[string]struct{}{requestID: {}}
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	gceResponse := &persistence.GetCurrentExecutionResponse{RunID: runID}
+
+	// no signaled event is appended to the history
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(gceResponse, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.MatchedBy(func(request *persistence.UpdateWorkflowExecutionRequest) bool {
+		return request.ExecutionInfo.NextEventID == ms.ExecutionInfo.NextEventID
+	})).Return(nil).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+
+	resp, err := s.historyEngine.SignalWithStartWorkflowExecution(&h.SignalWithStartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		SignalWithStartRequest: &workflow.SignalWithStartWorkflowExecutionRequest{
+			Domain:       common.StringPtr(domainID),
+			WorkflowId:   common.StringPtr(workflowID),
+			WorkflowType: &workflow.WorkflowType{Name: common.StringPtr("wType")},
+			TaskList:     &workflow.TaskList{Name: common.StringPtr(tl)},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr(identity),
+			SignalName:                          common.StringPtr("my signal name"),
+			SignalInput:                         []byte("test input"),
+			RequestId:                           common.StringPtr(requestID),
+		},
+	})
+	s.Nil(err)
+	s.Equal(runID, resp.GetRunId())
+}
+
+func (s *engine2Suite) TestSignalWithStartWorkflowExecution_StartRace() {
+	domainID := "domainId"
+	workflowID := "wId"
+	runID := "rId"
+	identity := "testIdentity"
+	tl := "testTaskList"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(workflowID),
+		RunId:      common.StringPtr(runID),
+	}
+
+	// the run started by a concurrent request
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, false)
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	gceResponse := &persistence.GetCurrentExecutionResponse{RunID: runID}
+
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(nil, &workflow.EntityNotExistsError{}).Once()
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.Anything).Return(nil, &persistence.WorkflowExecutionAlreadyStartedError{
+		Msg:            "random message",
+		StartRequestID: "otherRequestID",
+		RunID:          runID,
+		State:          persistence.WorkflowStateRunning,
+		CloseStatus:    persistence.WorkflowCloseStatusNone,
+	}).Once()
+	s.mockHistoryMgr.On("DeleteWorkflowExecutionHistory", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(gceResponse, nil).Once()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Twice()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.MatchedBy(func(request *persistence.UpdateWorkflowExecutionRequest) bool {
+		// the signal is delivered to the run started by the concurrent request
+		return request.ExecutionInfo.RunID == runID && request.ExecutionInfo.NextEventID == ms.ExecutionInfo.NextEventID+1
+	})).Return(nil).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false)
+
+	resp, err := s.historyEngine.SignalWithStartWorkflowExecution(&h.SignalWithStartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		SignalWithStartRequest: &workflow.SignalWithStartWorkflowExecutionRequest{
+			Domain:       common.StringPtr(domainID),
+			WorkflowId:   common.StringPtr(workflowID),
+			WorkflowType: &workflow.WorkflowType{Name: common.StringPtr("wType")},
+			TaskList:     &workflow.TaskList{Name: common.StringPtr(tl)},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr(identity),
+			SignalName:                          common.StringPtr("my signal name"),
+			SignalInput:                         []byte("test input"),
+			RequestId:                           common.StringPtr("newRequestID"),
+		},
+	})
+	s.Nil(err)
+	s.Equal(runID, resp.GetRunId())
+}
+
+func (s *engine2Suite) TestSignalWithStartWorkflowExecution_RejectDuplicate() {
+	domainID := "domainId"
+	workflowID := "wId"
+	runID := "rId"
+	identity := "testIdentity"
+	tl := "testTaskList"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(workflowID),
+		RunId:      common.StringPtr(runID),
+	}
+
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, false)
+	ms := createMutableState(msBuilder)
+	ms.ExecutionInfo.State = persistence.WorkflowStateCompleted
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	gceResponse := &persistence.GetCurrentExecutionResponse{RunID: runID}
+
+	// the closed run is loaded once and found closed again after the new run is rejected
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(gceResponse, nil).Twice()
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.Anything).Return(nil, &persistence.WorkflowExecutionAlreadyStartedError{
+		Msg:            "random message",
+		StartRequestID: "oldRequestID",
+		RunID:          runID,
+		State:          persistence.WorkflowStateCompleted,
+		CloseStatus:    persistence.WorkflowCloseStatusCompleted,
+	}).Once()
+	s.mockHistoryMgr.On("DeleteWorkflowExecutionHistory", mock.Anything).Return(nil).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+
+	resp, err := s.historyEngine.SignalWithStartWorkflowExecution(&h.SignalWithStartWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		SignalWithStartRequest: &workflow.SignalWithStartWorkflowExecutionRequest{
+			Domain:       common.StringPtr(domainID),
+			WorkflowId:   common.StringPtr(workflowID),
+			WorkflowType: &workflow.WorkflowType{Name: common.StringPtr("wType")},
+			TaskList:     &workflow.TaskList{Name: common.StringPtr(tl)},
+			ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
+			TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(2),
+			Identity:                            common.StringPtr(identity),
+			SignalName:                          common.StringPtr("my signal name"),
+			SignalInput:                         []byte("test input"),
+			RequestId:                           common.StringPtr("newRequestID"),
+			WorkflowIdReusePolicy:               workflow.WorkflowIdReusePolicyRejectDuplicate.Ptr(),
+		},
+	})
+	s.IsType(&workflow.WorkflowExecutionAlreadyStartedError{}, err)
+	s.Nil(resp)
+}
+
+func (s *engine2Suite) TestResetWorkflowExecution_Running() {
+	domainID := "domainId"
+	workflowID := "wId"
+	runID := "rId"
+	identity := "testIdentity"
+	tl := "testTaskList"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(workflowID),
+		RunId:      common.StringPtr(runID),
+	}
+
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, true)
+	completedEvent := addDecisionTaskCompletedEvent(msBuilder, 2, 3, nil, identity)
+	addActivityTaskScheduledEvent(msBuilder, *completedEvent.EventId, "activity1", "activity_type1", tl, []byte("input1"),
+		100, 10, 5)
+	msBuilder.AddWorkflowExecutionSignaled(&workflow.SignalWorkflowExecutionRequest{
+		SignalName: common.StringPtr("signal"),
+		Input:      []byte("signal input"),
+		Identity:   common.StringPtr(identity),
+	})
+	addDecisionTaskScheduledEvent(msBuilder)
+	history, err := msBuilder.hBuilder.Serialize()
+	s.Nil(err)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	historyResponse := &persistence.GetWorkflowExecutionHistoryResponse{
+		Events: []persistence.SerializedHistoryEventBatch{*history},
+	}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("GetWorkflowExecutionHistory", mock.Anything).Return(historyResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Twice()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.MatchedBy(func(request *persistence.UpdateWorkflowExecutionRequest) bool {
+		// started, decision scheduled, decision started, decision failed, signaled and decision scheduled events
+		return request.ContinueAsNew != nil && request.ContinueAsNew.PreviousRunID == runID &&
+			request.ExecutionInfo.CloseStatus == persistence.WorkflowCloseStatusTerminated &&
+			request.ContinueAsNew.NextEventID == 7 && len(request.ContinueAsNew.ActivityInfos) == 0 &&
+			len(request.ContinueAsNew.TransferTasks) == 1
+	})).Return(nil).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
+		&persistence.GetDomainResponse{Config: &persistence.DomainConfig{Retention: 1}}, nil)
+
+	resp, err := s.historyEngine.ResetWorkflowExecution(&h.ResetWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		ResetRequest: &workflow.ResetWorkflowExecutionRequest{
+			Domain:                common.StringPtr(domainID),
+			WorkflowExecution:     &workflowExecution,
+			Reason:                common.StringPtr("bad deployment"),
+			DecisionFinishEventId: completedEvent.EventId,
+			RequestId:             common.StringPtr(uuid.New()),
+			Identity:              common.StringPtr(identity),
+		},
+	})
+	s.Nil(err)
+	s.NotNil(resp.RunId)
+	s.NotEqual(runID, resp.GetRunId())
+}
+
+func (s *engine2Suite) TestResetWorkflowExecution_PendingActivityAndTimer() {
+	domainID := "domainId"
+	workflowID := "wId"
+	runID := "rId"
+	identity := "testIdentity"
+	tl := "testTaskList"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(workflowID),
+		RunId:      common.StringPtr(runID),
+	}
+
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, true)
+	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, 2, 3, nil, identity)
+	addActivityTaskScheduledEvent(msBuilder, *decisionCompletedEvent.EventId, "activity1", "activity_type1", tl,
+		[]byte("input1"), 100, 10, 5)
+	addTimerStartedEvent(msBuilder, *decisionCompletedEvent.EventId, "timer1", 100)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	startedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	completedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID, *startedEvent.EventId, nil, identity)
+	history, err := msBuilder.hBuilder.Serialize()
+	s.Nil(err)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	historyResponse := &persistence.GetWorkflowExecutionHistoryResponse{
+		Events: []persistence.SerializedHistoryEventBatch{*history},
+	}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("GetWorkflowExecutionHistory", mock.Anything).Return(historyResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Twice()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.MatchedBy(func(request *persistence.UpdateWorkflowExecutionRequest) bool {
+		// the new run is created with its pending activity and timer, the tasks dispatching the decision and the
+		// activity, and the workflow timeout, activity timeout and user timer tasks
+		newRun := request.ContinueAsNew
+		return newRun != nil && newRun.PreviousRunID == runID && newRun.NextEventID == 11 &&
+			len(newRun.ActivityInfos) == 1 && newRun.ActivityInfos[0].ScheduleID == 5 &&
+			len(newRun.TimerInfos) == 1 && newRun.TimerInfos[0].TimerID == "timer1" &&
+			len(newRun.TransferTasks) == 2 && len(newRun.TimerTasks) == 3
+	})).Return(nil).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
+		&persistence.GetDomainResponse{Config: &persistence.DomainConfig{Retention: 1}}, nil)
+
+	resp, err := s.historyEngine.ResetWorkflowExecution(&h.ResetWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		ResetRequest: &workflow.ResetWorkflowExecutionRequest{
+			Domain:                common.StringPtr(domainID),
+			WorkflowExecution:     &workflowExecution,
+			Reason:                common.StringPtr("bad deployment"),
+			DecisionFinishEventId: completedEvent.EventId,
+			RequestId:             common.StringPtr(uuid.New()),
+			Identity:              common.StringPtr(identity),
+		},
+	})
+	s.Nil(err)
+	s.NotEqual(runID, resp.GetRunId())
+}
+
+func (s *engine2Suite) TestResetWorkflowExecution_Closed() {
+	domainID := "domainId"
+	workflowID := "wId"
+	runID := "rId"
+	identity := "testIdentity"
+	tl := "testTaskList"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(workflowID),
+		RunId:      common.StringPtr(runID),
+	}
+
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, true)
+	completedEvent := addDecisionTaskCompletedEvent(msBuilder, 2, 3, nil, identity)
+	addCompleteWorkflowEvent(msBuilder, *completedEvent.EventId, []byte("result"))
+	history, err := msBuilder.hBuilder.Serialize()
+	s.Nil(err)
+
+	ms := createMutableState(msBuilder)
+	ms.ExecutionInfo.State = persistence.WorkflowStateCompleted
+	ms.ExecutionInfo.CloseStatus = persistence.WorkflowCloseStatusCompleted
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	gceResponse := &persistence.GetCurrentExecutionResponse{RunID: runID, StartRequestID: "oldRequestID"}
+	historyResponse := &persistence.GetWorkflowExecutionHistoryResponse{
+		Events: []persistence.SerializedHistoryEventBatch{*history},
+	}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(gceResponse, nil).Once()
+	s.mockHistoryMgr.On("GetWorkflowExecutionHistory", mock.Anything).Return(historyResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.MatchedBy(func(request *persistence.CreateWorkflowExecutionRequest) bool {
+		// started, decision scheduled, decision started, decision failed and decision scheduled events
+		return request.ContinueAsNew && request.PreviousRunID == runID && request.NextEventID == 6 &&
+			len(request.TransferTasks) == 1 && len(request.TimerTasks) == 1
+	})).Return(&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Once()
+
+	resp, err := s.historyEngine.ResetWorkflowExecution(&h.ResetWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		ResetRequest: &workflow.ResetWorkflowExecutionRequest{
+			Domain:                common.StringPtr(domainID),
+			WorkflowExecution:     &workflowExecution,
+			Reason:                common.StringPtr("bad deployment"),
+			DecisionFinishEventId: completedEvent.EventId,
+			RequestId:             common.StringPtr(uuid.New()),
+			Identity:              common.StringPtr(identity),
+		},
+	})
+	s.Nil(err)
+	s.NotEqual(runID, resp.GetRunId())
+}
+
+func (s *engine2Suite) TestResetWorkflowExecution_RetryAfterFailure() {
+	domainID := "domainId"
+	workflowID := "wId"
+	runID := "rId"
+	identity := "testIdentity"
+	tl := "testTaskList"
+	requestID := uuid.New()
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(workflowID),
+		RunId:      common.StringPtr(runID),
+	}
+
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, true)
+	completedEvent := addDecisionTaskCompletedEvent(msBuilder, 2, 3, nil, identity)
+	addDecisionTaskScheduledEvent(msBuilder)
+	history, err := msBuilder.hBuilder.Serialize()
+	s.Nil(err)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	terminatedMs := createMutableState(msBuilder)
+	terminatedMs.ExecutionInfo.State = persistence.WorkflowStateCompleted
+	terminatedMs.ExecutionInfo.CloseStatus = persistence.WorkflowCloseStatusTerminated
+	terminatedResponse := &persistence.GetWorkflowExecutionResponse{State: terminatedMs}
+	historyResponse := &persistence.GetWorkflowExecutionHistoryResponse{
+		Events: []persistence.SerializedHistoryEventBatch{*history},
+	}
+
+	// the write terminating the base run and creating the new run times out, but makes it to persistence
+	var newRunID string
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("GetWorkflowExecutionHistory", mock.Anything).Return(historyResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Twice()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.MatchedBy(func(request *persistence.UpdateWorkflowExecutionRequest) bool {
+		if request.ContinueAsNew == nil || len(request.ContinueAsNew.TransferTasks) != 1 {
+			return false
+		}
+		newRunID = request.ContinueAsNew.Execution.GetRunId()
+		return true
+	})).Return(&persistence.TimeoutError{Msg: "random message"}).Once()
+	s.mockShardManager.On("UpdateShard", mock.Anything).Return(nil).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
+		&persistence.GetDomainResponse{Config: &persistence.DomainConfig{Retention: 1}}, nil)
+
+	request := &h.ResetWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		ResetRequest: &workflow.ResetWorkflowExecutionRequest{
+			Domain:                common.StringPtr(domainID),
+			WorkflowExecution:     &workflowExecution,
+			Reason:                common.StringPtr("bad deployment"),
+			DecisionFinishEventId: completedEvent.EventId,
+			RequestId:             common.StringPtr(requestID),
+			Identity:              common.StringPtr(identity),
+		},
+	}
+	_, err = s.historyEngine.ResetWorkflowExecution(request)
+	s.IsType(&persistence.TimeoutError{}, err)
+	s.NotEmpty(newRunID)
+
+	// the retry finds the complete new run created by the request and does not write anything
+	gceResponse := &persistence.GetCurrentExecutionResponse{RunID: newRunID, StartRequestID: requestID}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(terminatedResponse, nil).Once()
+	s.mockExecutionMgr.On("GetCurrentExecution", mock.Anything).Return(gceResponse, nil).Once()
+
+	resp, err := s.historyEngine.ResetWorkflowExecution(request)
+	s.Nil(err)
+	s.Equal(newRunID, resp.GetRunId())
+}
+
+func (s *engine2Suite) TestResetWorkflowExecution_NotDecisionCompletedEvent() {
+	domainID := "domainId"
+	workflowID := "wId"
+	runID := "rId"
+	identity := "testIdentity"
+	tl := "testTaskList"
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(workflowID),
+		RunId:      common.StringPtr(runID),
+	}
+
+	msBuilder := s.createExecutionStartedState(workflowExecution, tl, identity, true)
+	addDecisionTaskCompletedEvent(msBuilder, 2, 3, nil, identity)
+	history, err := msBuilder.hBuilder.Serialize()
+	s.Nil(err)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	historyResponse := &persistence.GetWorkflowExecutionHistoryResponse{
+		Events: []persistence.SerializedHistoryEventBatch{*history},
+	}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("GetWorkflowExecutionHistory", mock.Anything).Return(historyResponse, nil).Once()
+
+	_, err = s.historyEngine.ResetWorkflowExecution(&h.ResetWorkflowExecutionRequest{
+		DomainUUID: common.StringPtr(domainID),
+		ResetRequest: &workflow.ResetWorkflowExecutionRequest{
+			Domain:                common.StringPtr(domainID),
+			WorkflowExecution:     &workflowExecution,
+			Reason:                common.StringPtr("bad deployment"),
+			DecisionFinishEventId: common.Int64Ptr(3),
+			RequestId:             common.StringPtr(uuid.New()),
+			Identity:              common.StringPtr(identity),
+		},
+	})
+	s.IsType(&workflow.BadRequestError{}, err)
+}
@@ -64,10 +64,15 @@ type (
 		RecordActivityTaskHeartbeat(request *h.RecordActivityTaskHeartbeatRequest) (*workflow.RecordActivityTaskHeartbeatResponse, error)
 		RequestCancelWorkflowExecution(request *h.RequestCancelWorkflowExecutionRequest) error
 		SignalWorkflowExecution(request *h.SignalWorkflowExecutionRequest) error
+		SignalWithStartWorkflowExecution(request *h.SignalWithStartWorkflowExecutionRequest) (
+			*workflow.StartWorkflowExecutionResponse, error)
 		RemoveSignalMutableState(request *h.RemoveSignalMutableStateRequest) error
 		TerminateWorkflowExecution(request *h.TerminateWorkflowExecutionRequest) error
+		ResetWorkflowExecution(request *h.ResetWorkflowExecutionRequest) (*workflow.ResetWorkflowExecutionResponse,
+			error)
 		ScheduleDecisionTask(request *h.ScheduleDecisionTaskRequest) error
 		RecordChildExecutionCompleted(request *h.RecordChildExecutionCompletedRequest) error
+		ReplicateEvents(request *h.ReplicateEventsRequest) error
 	}
 
 	// EngineFactory is used to create an instance of sharded history engine
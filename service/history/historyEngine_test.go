@@ -36,6 +36,7 @@ import (
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/mocks"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/searchattribute"
 	"github.com/uber/cadence/common/service/dynamicconfig"
 
 	"github.com/pborman/uuid"
@@ -150,6 +151,7 @@ func (s *engineSuite) SetupTest() {
 		tokenSerializer:      common.NewJSONTaskTokenSerializer(),
 		hSerializerFactory:   persistence.NewHistorySerializerFactory(),
 		historyEventNotifier: historyEventNotifier,
+		searchAttrValidator:  searchattribute.NewValidator(s.config.ValidSearchAttributes),
 	}
 
 	// this is used by shard context, not relevent to this test, so we do not care how many times "GetCurrentClusterName" os called
@@ -1049,6 +1051,323 @@ func (s *engineSuite) TestRespondDecisionTaskCompletedFailWorkflowSuccess() {
 	s.False(executionBuilder.HasPendingDecisionTask())
 }
 
+func (s *engineSuite) TestRespondDecisionTaskCompletedFailWorkflowRetry() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr("rId"),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+	details := []byte("fail workflow details")
+	reason := "fail workflow reason"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	msBuilder.AddWorkflowExecutionStartedEvent(domainID, we, &workflow.StartWorkflowExecutionRequest{
+		WorkflowId:                          we.WorkflowId,
+		WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("wType")},
+		TaskList:                            &workflow.TaskList{Name: common.StringPtr(tl)},
+		Input:                               []byte("input"),
+		ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(100),
+		TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(200),
+		Identity:                            common.StringPtr(identity),
+		RetryPolicy: &workflow.RetryPolicy{
+			InitialIntervalInSeconds: common.Int32Ptr(10),
+			BackoffCoefficient:       common.Float64Ptr(2),
+			MaximumAttempts:          common.Int32Ptr(3),
+		},
+	}, 0, time.Now())
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	serializedHistory, err := msBuilder.hBuilder.Serialize()
+	s.Nil(err)
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeFailWorkflowExecution),
+		FailWorkflowExecutionDecisionAttributes: &workflow.FailWorkflowExecutionDecisionAttributes{
+			Reason:  &reason,
+			Details: details,
+		},
+	}}
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	historyResponse := &persistence.GetWorkflowExecutionHistoryResponse{
+		Events: []persistence.SerializedHistoryEventBatch{*serializedHistory},
+	}
+
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("GetWorkflowExecutionHistory", mock.Anything).Return(historyResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Twice()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(arguments mock.Arguments) {
+		updateRequest = arguments.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
+		&persistence.GetDomainResponse{Config: &persistence.DomainConfig{Retention: 1}}, nil)
+
+	now := time.Now()
+	err = s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  &identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+
+	// the failed run is continued as new instead of failing
+	s.Equal(persistence.WorkflowCloseStatusContinuedAsNew, updateRequest.ExecutionInfo.CloseStatus)
+	newRun := updateRequest.ContinueAsNew
+	s.NotNil(newRun)
+	s.Equal(int32(1), newRun.Attempt)
+	s.True(newRun.HasRetryPolicy)
+
+	// the first decision of the next run is scheduled by the backoff timer after the retry interval
+	s.Empty(newRun.TransferTasks)
+	s.Equal(2, len(newRun.TimerTasks))
+	backoffTimer, ok := newRun.TimerTasks[1].(*persistence.WorkflowBackoffTimerTask)
+	s.True(ok)
+	s.Equal(persistence.WorkflowBackoffTimeoutTypeRetry, backoffTimer.TimeoutType)
+	s.False(backoffTimer.VisibilityTimestamp.Before(now.Add(10 * time.Second)))
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedCompleteCronWorkflow() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr("rId"),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	msBuilder.AddWorkflowExecutionStartedEvent(domainID, we, &workflow.StartWorkflowExecutionRequest{
+		WorkflowId:                          we.WorkflowId,
+		WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("wType")},
+		TaskList:                            &workflow.TaskList{Name: common.StringPtr(tl)},
+		Input:                               []byte("input"),
+		ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(100),
+		TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(200),
+		Identity:                            common.StringPtr(identity),
+		CronSchedule:                        common.StringPtr("@every 30s"),
+	}, 0, time.Now())
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	serializedHistory, err := msBuilder.hBuilder.Serialize()
+	s.Nil(err)
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeCompleteWorkflowExecution),
+		CompleteWorkflowExecutionDecisionAttributes: &workflow.CompleteWorkflowExecutionDecisionAttributes{
+			Result: []byte("complete"),
+		},
+	}}
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	historyResponse := &persistence.GetWorkflowExecutionHistoryResponse{
+		Events: []persistence.SerializedHistoryEventBatch{*serializedHistory},
+	}
+
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("GetWorkflowExecutionHistory", mock.Anything).Return(historyResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Twice()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(arguments mock.Arguments) {
+		updateRequest = arguments.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
+		&persistence.GetDomainResponse{Config: &persistence.DomainConfig{Retention: 1}}, nil)
+
+	err = s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken: taskToken,
+			Decisions: decisions,
+			Identity:  &identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+
+	// a cron workflow never completes, its next run is started at the next time the schedule fires
+	s.Equal(persistence.WorkflowCloseStatusContinuedAsNew, updateRequest.ExecutionInfo.CloseStatus)
+	newRun := updateRequest.ContinueAsNew
+	s.NotNil(newRun)
+	s.Equal(int32(0), newRun.Attempt)
+	s.Equal("@every 30s", newRun.CronSchedule)
+	s.Empty(newRun.TransferTasks)
+	s.Equal(2, len(newRun.TimerTasks))
+	backoffTimer, ok := newRun.TimerTasks[1].(*persistence.WorkflowBackoffTimerTask)
+	s.True(ok)
+	s.Equal(persistence.WorkflowBackoffTimeoutTypeCron, backoffTimer.TimeoutType)
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedUpsertWorkflowSearchAttributes() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr("rId"),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 2,
+	})
+	identity := "testIdentity"
+	executionContext := []byte("context")
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	msBuilder.executionInfo.SearchAttributes = map[string][]byte{
+		"CustomIntField":     []byte("1"),
+		"CustomKeywordField": []byte(`"old"`),
+	}
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+	decisions := []*workflow.Decision{{
+		DecisionType: common.DecisionTypePtr(workflow.DecisionTypeUpsertWorkflowSearchAttributes),
+		UpsertWorkflowSearchAttributesDecisionAttributes: &workflow.UpsertWorkflowSearchAttributesDecisionAttributes{
+			SearchAttributes: &workflow.SearchAttributes{IndexedFields: map[string][]byte{
+				"CustomKeywordField": []byte(` "new" `),
+				"CustomDoubleField":  []byte("1.50"),
+			}},
+		},
+	}}
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(arguments mock.Arguments) {
+		updateRequest = arguments.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+
+	err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+			TaskToken:        taskToken,
+			Decisions:        decisions,
+			ExecutionContext: executionContext,
+			Identity:         &identity,
+		},
+	})
+	s.Nil(err, s.printHistory(msBuilder))
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int64(6), executionBuilder.executionInfo.NextEventID)
+	s.Equal(int64(3), executionBuilder.executionInfo.LastProcessedEvent)
+	s.Equal(persistence.WorkflowStateRunning, executionBuilder.executionInfo.State)
+	s.False(executionBuilder.HasPendingDecisionTask())
+
+	// upserted values are stored in canonical encoding and merged into the existing search attributes
+	expectedSearchAttributes := map[string][]byte{
+		"CustomIntField":     []byte("1"),
+		"CustomKeywordField": []byte(`"new"`),
+		"CustomDoubleField":  []byte("1.5"),
+	}
+	s.Equal(expectedSearchAttributes, executionBuilder.executionInfo.SearchAttributes)
+	s.Equal(expectedSearchAttributes, updateRequest.ExecutionInfo.SearchAttributes)
+	s.Equal(1, len(updateRequest.TransferTasks))
+	s.IsType(&persistence.UpsertWorkflowSearchAttributesTask{}, updateRequest.TransferTasks[0])
+}
+
+func (s *engineSuite) TestRespondDecisionTaskCompletedUpsertWorkflowSearchAttributesInvalid() {
+	domainID := "domainId"
+	tl := "testTaskList"
+	identity := "testIdentity"
+
+	invalidAttributes := []*workflow.UpsertWorkflowSearchAttributesDecisionAttributes{
+		nil,
+		{SearchAttributes: &workflow.SearchAttributes{}},
+		// not whitelisted
+		{SearchAttributes: &workflow.SearchAttributes{IndexedFields: map[string][]byte{
+			"UnknownField": []byte(`"value"`),
+		}}},
+		// not an int
+		{SearchAttributes: &workflow.SearchAttributes{IndexedFields: map[string][]byte{
+			"CustomIntField": []byte("1.5"),
+		}}},
+		// not json
+		{SearchAttributes: &workflow.SearchAttributes{IndexedFields: map[string][]byte{
+			"CustomKeywordField": []byte("value"),
+		}}},
+	}
+
+	for _, attributes := range invalidAttributes {
+		we := workflow.WorkflowExecution{
+			WorkflowId: common.StringPtr("wId"),
+			RunId:      common.StringPtr(uuid.New()),
+		}
+		taskToken, _ := json.Marshal(&common.TaskToken{
+			WorkflowID: *we.WorkflowId,
+			RunID:      *we.RunId,
+			ScheduleID: 2,
+		})
+
+		msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+		addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+		di := addDecisionTaskScheduledEvent(msBuilder)
+		addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+
+		decisions := []*workflow.Decision{{
+			DecisionType: common.DecisionTypePtr(workflow.DecisionTypeUpsertWorkflowSearchAttributes),
+			UpsertWorkflowSearchAttributesDecisionAttributes: attributes,
+		}}
+
+		// the execution is loaded again to fail the decision
+		for i := 0; i < 2; i++ {
+			ms := createMutableState(msBuilder)
+			gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+			s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+		}
+
+		var updateRequest *persistence.UpdateWorkflowExecutionRequest
+		s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+		s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(arguments mock.Arguments) {
+			updateRequest = arguments.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+		}).Once()
+		s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+
+		err := s.mockHistoryEngine.RespondDecisionTaskCompleted(context.Background(), &history.RespondDecisionTaskCompletedRequest{
+			DomainUUID: common.StringPtr(domainID),
+			CompleteRequest: &workflow.RespondDecisionTaskCompletedRequest{
+				TaskToken: taskToken,
+				Decisions: decisions,
+				Identity:  &identity,
+			},
+		})
+		s.IsType(&workflow.BadRequestError{}, err, attributes.String())
+
+		// the decision is failed and retried as a transient decision without upserting the search attributes
+		executionBuilder := s.getBuilder(domainID, we)
+		s.Equal(int64(5), executionBuilder.executionInfo.NextEventID, attributes.String())
+		s.True(executionBuilder.HasPendingDecisionTask(), attributes.String())
+		s.Equal(int64(1), executionBuilder.executionInfo.DecisionAttempt, attributes.String())
+		s.Empty(executionBuilder.executionInfo.SearchAttributes, attributes.String())
+		s.Empty(updateRequest.ExecutionInfo.SearchAttributes, attributes.String())
+		s.Equal(1, len(updateRequest.TransferTasks), attributes.String())
+		s.IsType(&persistence.DecisionTask{}, updateRequest.TransferTasks[0], attributes.String())
+	}
+}
+
 func (s *engineSuite) TestRespondDecisionTaskCompletedSignalExternalWorkflowSuccess() {
 	domainID := "domainId"
 	we := workflow.WorkflowExecution{
@@ -2198,6 +2517,148 @@ func (s *engineSuite) TestRespondActivityTaskFailedSuccess() {
 	s.Equal(emptyEventID, di.StartedID)
 }
 
+func (s *engineSuite) TestRespondActivityTaskFailedRetry() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr("rId"),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 5,
+	})
+	identity := "testIdentity"
+	activityID := "activity1_id"
+	activityType := "activity_type1"
+	activityInput := []byte("input1")
+	failReason := "failed"
+	failDetails := []byte("fail details.")
+	retryPolicy := &workflow.RetryPolicy{
+		InitialIntervalInSeconds: common.Int32Ptr(10),
+		BackoffCoefficient:       common.Float64Ptr(2),
+		MaximumAttempts:          common.Int32Ptr(3),
+	}
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
+		*decisionStartedEvent.EventId, nil, identity)
+	activityScheduledEvent, _ := addActivityTaskScheduledEventWithRetry(msBuilder, *decisionCompletedEvent.EventId,
+		activityID, activityType, tl, activityInput, 100, 10, 5, retryPolicy)
+	addActivityTaskStartedEvent(msBuilder, *activityScheduledEvent.EventId, tl, identity)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(arguments mock.Arguments) {
+		updateRequest = arguments.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+
+	now := time.Now()
+	err := s.mockHistoryEngine.RespondActivityTaskFailed(&history.RespondActivityTaskFailedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		FailedRequest: &workflow.RespondActivityTaskFailedRequest{
+			TaskToken: taskToken,
+			Reason:    &failReason,
+			Details:   failDetails,
+			Identity:  &identity,
+		},
+	})
+	s.Nil(err)
+
+	// The failed attempt is not recorded in history and no decision is scheduled
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int64(6), executionBuilder.executionInfo.NextEventID)
+	s.False(executionBuilder.HasPendingDecisionTask())
+	ai, ok := executionBuilder.GetActivityInfo(5)
+	s.True(ok)
+	s.Equal(int32(1), ai.Attempt)
+	s.Equal(emptyEventID, ai.StartedID)
+	s.Nil(ai.StartedEvent)
+	s.False(ai.ScheduledTime.Before(now.Add(10 * time.Second)))
+
+	// The next attempt is dispatched by a retry timer after the backoff
+	s.Empty(updateRequest.TransferTasks)
+	s.Equal(1, len(updateRequest.TimerTasks))
+	retryTimer, ok := updateRequest.TimerTasks[0].(*persistence.ActivityTimeoutTask)
+	s.True(ok)
+	s.Equal(int64(5), retryTimer.EventID)
+	s.Equal(ai.ScheduledTime, retryTimer.VisibilityTimestamp)
+	s.Equal(int32(TimerTaskStatusCreatedRetry), ai.TimerTaskStatus)
+}
+
+func (s *engineSuite) TestRespondActivityTaskFailedNonRetriable() {
+	domainID := "domainId"
+	we := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr("rId"),
+	}
+	tl := "testTaskList"
+	taskToken, _ := json.Marshal(&common.TaskToken{
+		WorkflowID: *we.WorkflowId,
+		RunID:      *we.RunId,
+		ScheduleID: 5,
+	})
+	identity := "testIdentity"
+	activityID := "activity1_id"
+	activityType := "activity_type1"
+	activityInput := []byte("input1")
+	failReason := "non-retriable"
+	failDetails := []byte("fail details.")
+	retryPolicy := &workflow.RetryPolicy{
+		InitialIntervalInSeconds: common.Int32Ptr(10),
+		BackoffCoefficient:       common.Float64Ptr(2),
+		MaximumAttempts:          common.Int32Ptr(3),
+		NonRetriableErrorReasons: []string{failReason},
+	}
+
+	msBuilder := newMutableStateBuilder(s.config, bark.NewLoggerFromLogrus(log.New()))
+	addWorkflowExecutionStartedEvent(msBuilder, we, "wType", tl, []byte("input"), 100, 200, identity)
+	di := addDecisionTaskScheduledEvent(msBuilder)
+	decisionStartedEvent := addDecisionTaskStartedEvent(msBuilder, di.ScheduleID, tl, identity)
+	decisionCompletedEvent := addDecisionTaskCompletedEvent(msBuilder, di.ScheduleID,
+		*decisionStartedEvent.EventId, nil, identity)
+	activityScheduledEvent, _ := addActivityTaskScheduledEventWithRetry(msBuilder, *decisionCompletedEvent.EventId,
+		activityID, activityType, tl, activityInput, 100, 10, 5, retryPolicy)
+	addActivityTaskStartedEvent(msBuilder, *activityScheduledEvent.EventId, tl, identity)
+
+	ms := createMutableState(msBuilder)
+	gwmsResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(gwmsResponse, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+
+	err := s.mockHistoryEngine.RespondActivityTaskFailed(&history.RespondActivityTaskFailedRequest{
+		DomainUUID: common.StringPtr(domainID),
+		FailedRequest: &workflow.RespondActivityTaskFailedRequest{
+			TaskToken: taskToken,
+			Reason:    &failReason,
+			Details:   failDetails,
+			Identity:  &identity,
+		},
+	})
+	s.Nil(err)
+
+	// The started event of the last attempt is written before the failed event
+	executionBuilder := s.getBuilder(domainID, we)
+	s.Equal(int64(9), executionBuilder.executionInfo.NextEventID)
+	_, ok := executionBuilder.GetActivityInfo(5)
+	s.False(ok)
+	s.True(executionBuilder.HasPendingDecisionTask())
+	di, ok = executionBuilder.GetPendingDecision(int64(8))
+	s.True(ok)
+	s.Equal(int64(8), di.ScheduleID)
+}
+
 func (s *engineSuite) TestRespondActivityTaskFailedByIDSuccess() {
 	domainID := "domainId"
 	we := workflow.WorkflowExecution{
@@ -3416,7 +3877,7 @@ func addWorkflowExecutionStartedEvent(builder *mutableStateBuilder, workflowExec
 		ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(executionStartToCloseTimeout),
 		TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(taskStartToCloseTimeout),
 		Identity:                            common.StringPtr(identity),
-	})
+	}, 0, time.Now())
 
 	return e
 }
@@ -3467,6 +3928,22 @@ func addActivityTaskScheduledEvent(builder *mutableStateBuilder, decisionComplet
 	})
 }
 
+func addActivityTaskScheduledEventWithRetry(builder *mutableStateBuilder, decisionCompletedID int64, activityID,
+	activityType, taskList string, input []byte, timeout, queueTimeout, heartbeatTimeout int32,
+	retryPolicy *workflow.RetryPolicy) (*workflow.HistoryEvent, *persistence.ActivityInfo) {
+	return builder.AddActivityTaskScheduledEvent(decisionCompletedID, &workflow.ScheduleActivityTaskDecisionAttributes{
+		ActivityId:                    common.StringPtr(activityID),
+		ActivityType:                  &workflow.ActivityType{Name: common.StringPtr(activityType)},
+		TaskList:                      &workflow.TaskList{Name: common.StringPtr(taskList)},
+		Input:                         input,
+		ScheduleToCloseTimeoutSeconds: common.Int32Ptr(timeout),
+		ScheduleToStartTimeoutSeconds: common.Int32Ptr(queueTimeout),
+		HeartbeatTimeoutSeconds:       common.Int32Ptr(heartbeatTimeout),
+		StartToCloseTimeoutSeconds:    common.Int32Ptr(1),
+		RetryPolicy:                   retryPolicy,
+	})
+}
+
 func addActivityTaskStartedEvent(builder *mutableStateBuilder, scheduleID int64,
 	taskList, identity string) *workflow.HistoryEvent {
 	ai, _ := builder.GetActivityInfo(scheduleID)
@@ -3562,6 +4039,16 @@ func addCompleteWorkflowEvent(builder *mutableStateBuilder, decisionCompletedEve
 	return e
 }
 
+func addUpsertWorkflowSearchAttributesEvent(builder *mutableStateBuilder, decisionCompletedEventID int64,
+	indexedFields map[string][]byte) *workflow.HistoryEvent {
+	e := builder.AddUpsertWorkflowSearchAttributesEvent(decisionCompletedEventID,
+		&workflow.UpsertWorkflowSearchAttributesDecisionAttributes{
+			SearchAttributes: &workflow.SearchAttributes{IndexedFields: indexedFields},
+		})
+
+	return e
+}
+
 func createMutableState(builder *mutableStateBuilder) *persistence.WorkflowMutableState {
 	builder.FlushBufferedEvents()
 	info := copyWorkflowExecutionInfo(builder.executionInfo)
@@ -3617,6 +4104,7 @@ func copyWorkflowExecutionInfo(sourceInfo *persistence.WorkflowExecutionInfo) *p
 		DecisionStartedID:    sourceInfo.DecisionStartedID,
 		DecisionRequestID:    sourceInfo.DecisionRequestID,
 		DecisionTimeout:      sourceInfo.DecisionTimeout,
+		SearchAttributes:     sourceInfo.SearchAttributes,
 	}
 }
 
@@ -3635,6 +4123,14 @@ func copyActivityInfo(sourceInfo *persistence.ActivityInfo) *persistence.Activit
 		HeartbeatTimeout:       sourceInfo.HeartbeatTimeout,
 		CancelRequested:        sourceInfo.CancelRequested,
 		CancelRequestID:        sourceInfo.CancelRequestID,
+		Attempt:                sourceInfo.Attempt,
+		HasRetryPolicy:         sourceInfo.HasRetryPolicy,
+		InitialInterval:        sourceInfo.InitialInterval,
+		BackoffCoefficient:     sourceInfo.BackoffCoefficient,
+		MaximumInterval:        sourceInfo.MaximumInterval,
+		ExpirationTime:         sourceInfo.ExpirationTime,
+		MaximumAttempts:        sourceInfo.MaximumAttempts,
+		NonRetriableErrors:     sourceInfo.NonRetriableErrors,
 	}
 }
 
@@ -0,0 +1,308 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"fmt"
+
+	"github.com/pborman/uuid"
+	"github.com/uber-common/bark"
+	h "github.com/uber/cadence/.gen/go/history"
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common/logging"
+	"github.com/uber/cadence/common/persistence"
+)
+
+type (
+	historyReplicator struct {
+		shard         ShardContext
+		historyEngine *historyEngineImpl
+		historyCache  *historyCache
+		historyMgr    persistence.HistoryManager
+		logger        bark.Logger
+	}
+)
+
+var (
+	// ErrRetryReplication is returned when replication events for a workflow execution are received out of order.
+	// Replicator should retry the task after a backoff so the missing events can be applied first.
+	ErrRetryReplication = &workflow.InternalServiceError{Message: "Missing replication events, retry later."}
+	// ErrMissingReplicationState is returned when replication events are received for an execution which does not
+	// belong to a global domain
+	ErrMissingReplicationState = &workflow.BadRequestError{Message: "Workflow execution is missing replication state."}
+	// ErrInvalidReplicationEvents is returned when the events of a replication task do not match its event ID range
+	ErrInvalidReplicationEvents = &workflow.BadRequestError{Message: "Replication events do not match the event range."}
+)
+
+func newHistoryReplicator(shard ShardContext, historyEngine *historyEngineImpl, historyCache *historyCache,
+	historyMgr persistence.HistoryManager, logger bark.Logger) *historyReplicator {
+	return &historyReplicator{
+		shard:         shard,
+		historyEngine: historyEngine,
+		historyCache:  historyCache,
+		historyMgr:    historyMgr,
+		logger: logger.WithFields(bark.Fields{
+			logging.TagWorkflowComponent: logging.TagValueHistoryReplicatorComponent,
+		}),
+	}
+}
+
+// ApplyEvents applies a batch of history events received from a remote cluster to the local copy of the execution.
+// Duplicate batches are dropped and ErrRetryReplication is returned if some events before this batch are missing.
+func (r *historyReplicator) ApplyEvents(request *h.ReplicateEventsRequest) error {
+	if request.History == nil || len(request.History.Events) == 0 {
+		r.logger.Warn("Dropping empty replication task.")
+		return nil
+	}
+
+	events := request.History.Events
+	if events[0].GetEventId() != request.GetFirstEventId() ||
+		events[len(events)-1].GetEventId() != request.GetNextEventId()-1 {
+		r.logger.Warnf("Replication events [%v, %v] do not match the event range [%v, %v).", events[0].GetEventId(),
+			events[len(events)-1].GetEventId(), request.GetFirstEventId(), request.GetNextEventId())
+		return ErrInvalidReplicationEvents
+	}
+
+	domainID, err := getDomainUUID(request.DomainUUID)
+	if err != nil {
+		return err
+	}
+
+	execution := *request.WorkflowExecution
+	logger := r.logger.WithFields(bark.Fields{
+		logging.TagDomainID:            domainID,
+		logging.TagWorkflowExecutionID: execution.GetWorkflowId(),
+		logging.TagWorkflowRunID:       execution.GetRunId(),
+		logging.TagSourceCluster:       request.GetSourceCluster(),
+	})
+
+	context, release, err := r.historyCache.getOrCreateWorkflowExecution(domainID, execution)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	for attempt := 0; attempt < conditionalRetryCount; attempt++ {
+		msBuilder, err := context.loadWorkflowExecution()
+		if err != nil {
+			if _, ok := err.(*workflow.EntityNotExistsError); !ok {
+				return err
+			}
+
+			if request.GetFirstEventId() != firstEventID {
+				// Events for the start of this execution are not applied yet
+				logger.Debugf("Execution not found for replication events [%v, %v).", request.GetFirstEventId(),
+					request.GetNextEventId())
+				return ErrRetryReplication
+			}
+
+			return r.replicateWorkflowStarted(context, domainID, execution, request, logger)
+		}
+
+		rState := msBuilder.replicationState
+		if rState == nil {
+			return ErrMissingReplicationState
+		}
+
+		if rState.LastWriteVersion > request.GetVersion() {
+			// Events were written by a more recent failover version, so this batch is stale
+			logger.Warnf("Dropping stale replication events. LastWriteVersion: %v, Version: %v.",
+				rState.LastWriteVersion, request.GetVersion())
+			return nil
+		}
+
+		if ri, ok := rState.LastReplicationInfo[request.GetSourceCluster()]; ok {
+			if ri.Version == request.GetVersion() && ri.LastEventID >= request.GetNextEventId()-1 {
+				logger.Debugf("Dropping duplicate replication events [%v, %v). LastEventID: %v.",
+					request.GetFirstEventId(), request.GetNextEventId(), ri.LastEventID)
+				return nil
+			}
+		}
+
+		nextEventID := msBuilder.GetNextEventID()
+		if request.GetFirstEventId() < nextEventID {
+			logger.Debugf("Dropping duplicate replication events [%v, %v). NextEventID: %v.",
+				request.GetFirstEventId(), request.GetNextEventId(), nextEventID)
+			return nil
+		}
+
+		if request.GetFirstEventId() > nextEventID {
+			logger.Debugf("Replication events [%v, %v) received out of order. NextEventID: %v.",
+				request.GetFirstEventId(), request.GetNextEventId(), nextEventID)
+			return ErrRetryReplication
+		}
+
+		err = r.replicateWorkflowUpdated(context, msBuilder, domainID, execution, request)
+		if err == ErrConflict {
+			continue
+		}
+
+		return err
+	}
+
+	return ErrMaxAttemptsExceeded
+}
+
+func (r *historyReplicator) replicateWorkflowStarted(context *workflowExecutionContext, domainID string,
+	execution workflow.WorkflowExecution, request *h.ReplicateEventsRequest, logger bark.Logger) error {
+	msBuilder := newMutableStateBuilder(r.shard.GetConfig(), logger)
+	sBuilder := newStateBuilder(msBuilder, logger)
+	requestID := uuid.New()
+	lastEvent, err := sBuilder.applyEvents(domainID, requestID, execution, request.History.Events)
+	if err != nil {
+		return err
+	}
+
+	lastEventID := lastEvent.GetEventId()
+	msBuilder.replicationState = &persistence.ReplicationState{
+		StartVersion: request.GetVersion(),
+	}
+	msBuilder.ApplyReplicationStateUpdatesFromRemote(request.GetSourceCluster(), request.GetVersion(), lastEventID)
+
+	serializedHistory, err := msBuilder.hBuilder.Serialize()
+	if err != nil {
+		logging.LogHistorySerializationErrorEvent(logger, err, "Unable to serialize replicated history events.")
+		return err
+	}
+
+	err = r.shard.AppendHistoryEvents(&persistence.AppendHistoryEventsRequest{
+		DomainID:  domainID,
+		Execution: execution,
+		// It is ok to use 0 for TransactionID because RunID is unique so there are
+		// no potential duplicates to override.
+		TransactionID: 0,
+		FirstEventID:  firstEventID,
+		Events:        serializedHistory,
+	})
+	if err != nil {
+		return err
+	}
+
+	deleteEvents := func() {
+		// Cleanup the history events we just created as the execution was never created
+		r.historyMgr.DeleteWorkflowExecutionHistory(&persistence.DeleteWorkflowExecutionHistoryRequest{
+			DomainID:  domainID,
+			Execution: execution,
+		})
+	}
+
+	executionInfo := msBuilder.executionInfo
+	createWorkflow := func(isBrandNew bool, prevRunID string) error {
+		_, err := r.shard.CreateWorkflowExecution(&persistence.CreateWorkflowExecutionRequest{
+			RequestID:                   requestID,
+			DomainID:                    domainID,
+			Execution:                   execution,
+			InitiatedID:                 emptyEventID,
+			TaskList:                    executionInfo.TaskList,
+			WorkflowTypeName:            executionInfo.WorkflowTypeName,
+			WorkflowTimeout:             executionInfo.WorkflowTimeout,
+			DecisionTimeoutValue:        executionInfo.DecisionTimeoutValue,
+			ExecutionContext:            nil,
+			NextEventID:                 msBuilder.GetNextEventID(),
+			LastProcessedEvent:          executionInfo.LastProcessedEvent,
+			DecisionScheduleID:          executionInfo.DecisionScheduleID,
+			DecisionStartedID:           executionInfo.DecisionStartedID,
+			DecisionStartToCloseTimeout: executionInfo.DecisionTimeout,
+			ContinueAsNew:               !isBrandNew,
+			PreviousRunID:               prevRunID,
+			ReplicationState:            msBuilder.replicationState,
+			Attempt:                     executionInfo.Attempt,
+			HasRetryPolicy:              executionInfo.HasRetryPolicy,
+			InitialInterval:             executionInfo.InitialInterval,
+			BackoffCoefficient:          executionInfo.BackoffCoefficient,
+			MaximumInterval:             executionInfo.MaximumInterval,
+			ExpirationTime:              executionInfo.ExpirationTime,
+			MaximumAttempts:             executionInfo.MaximumAttempts,
+			NonRetriableErrors:          executionInfo.NonRetriableErrors,
+			CronSchedule:                executionInfo.CronSchedule,
+			SearchAttributes:            executionInfo.SearchAttributes,
+			Priority:                    executionInfo.Priority,
+			BuildID:                     executionInfo.BuildID,
+		})
+		return err
+	}
+
+	err = createWorkflow(true, "")
+	if errExist, ok := err.(*persistence.WorkflowExecutionAlreadyStartedError); ok {
+		if errExist.RunID == execution.GetRunId() {
+			// Execution was created by a previous attempt of this task
+			return nil
+		}
+
+		if errExist.State != persistence.WorkflowStateCompleted {
+			deleteEvents()
+			return &workflow.InternalServiceError{
+				Message: fmt.Sprintf("Workflow execution is already running. WorkflowId: %v, RunId: %v.",
+					execution.GetWorkflowId(), errExist.RunID),
+			}
+		}
+
+		// Previous run is already closed, so replace it as the current run
+		err = createWorkflow(false, errExist.RunID)
+	}
+
+	if err != nil {
+		deleteEvents()
+		return err
+	}
+
+	context.clear()
+	return nil
+}
+
+func (r *historyReplicator) replicateWorkflowUpdated(context *workflowExecutionContext,
+	msBuilder *mutableStateBuilder, domainID string, execution workflow.WorkflowExecution,
+	request *h.ReplicateEventsRequest) error {
+	wasRunning := msBuilder.isWorkflowExecutionRunning()
+	sBuilder := newStateBuilder(msBuilder, context.logger)
+	lastEvent, err := sBuilder.applyEvents(domainID, uuid.New(), execution, request.History.Events)
+	if err != nil {
+		context.clear()
+		return err
+	}
+
+	// Standby cluster does not dispatch any tasks for the execution.  Only schedule cleanup of history once the
+	// execution is closed by the remote cluster.
+	var timerTasks []persistence.Task
+	if wasRunning && !msBuilder.isWorkflowExecutionRunning() {
+		tBuilder := r.historyEngine.getTimerBuilder(&execution)
+		_, cleanupTask, err := r.historyEngine.getDeleteWorkflowTasks(domainID, tBuilder)
+		if err != nil {
+			context.clear()
+			return err
+		}
+		timerTasks = append(timerTasks, cleanupTask)
+	}
+
+	transactionID, err := r.shard.GetNextTransferTaskID()
+	if err != nil {
+		context.clear()
+		return err
+	}
+
+	if err := context.replicateWorkflowExecution(request, nil, timerTasks, lastEvent.GetEventId(),
+		transactionID); err != nil {
+		return err
+	}
+
+	r.historyEngine.timerProcessor.NotifyNewTimers(timerTasks)
+	return nil
+}
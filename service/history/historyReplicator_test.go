@@ -0,0 +1,444 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pborman/uuid"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-common/bark"
+	"github.com/uber-go/tally"
+	h "github.com/uber/cadence/.gen/go/history"
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/cluster"
+	"github.com/uber/cadence/common/messaging"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/mocks"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/service"
+	"github.com/uber/cadence/common/service/dynamicconfig"
+)
+
+const (
+	testSourceCluster = "standby"
+)
+
+type (
+	historyReplicatorSuite struct {
+		suite.Suite
+		// override suite.Suite.Assertions with require.Assertions; this means that s.NotNil(nil) will stop the test,
+		// not merely log an error
+		*require.Assertions
+		replicator          *historyReplicator
+		mockMatchingClient  *mocks.MatchingClient
+		mockHistoryClient   *mocks.HistoryClient
+		mockMetadataMgr     *mocks.MetadataManager
+		mockVisibilityMgr   *mocks.VisibilityManager
+		mockExecutionMgr    *mocks.ExecutionManager
+		mockHistoryMgr      *mocks.HistoryManager
+		mockShardManager    *mocks.ShardManager
+		mockClusterMetadata *mocks.ClusterMetadata
+		mockProducer        *mocks.KafkaProducer
+		mockMessagingClient messaging.Client
+		mockService         service.Service
+		shardClosedCh       chan int
+		config              *Config
+		logger              bark.Logger
+	}
+)
+
+func TestHistoryReplicatorSuite(t *testing.T) {
+	s := new(historyReplicatorSuite)
+	suite.Run(t, s)
+}
+
+func (s *historyReplicatorSuite) SetupSuite() {
+	if testing.Verbose() {
+		log.SetOutput(os.Stdout)
+	}
+
+	l := log.New()
+	l.Level = log.DebugLevel
+	s.logger = bark.NewLoggerFromLogrus(l)
+	s.config = NewConfig(dynamicconfig.NewNopCollection(), 1)
+}
+
+func (s *historyReplicatorSuite) TearDownSuite() {
+}
+
+func (s *historyReplicatorSuite) SetupTest() {
+	// Have to define our overridden assertions in the test setup. If we did it earlier, s.T() will return nil
+	s.Assertions = require.New(s.T())
+
+	shardID := 0
+	s.mockMatchingClient = &mocks.MatchingClient{}
+	s.mockHistoryClient = &mocks.HistoryClient{}
+	s.mockMetadataMgr = &mocks.MetadataManager{}
+	s.mockVisibilityMgr = &mocks.VisibilityManager{}
+	s.mockExecutionMgr = &mocks.ExecutionManager{}
+	s.mockHistoryMgr = &mocks.HistoryManager{}
+	s.mockShardManager = &mocks.ShardManager{}
+	s.mockClusterMetadata = &mocks.ClusterMetadata{}
+	s.mockProducer = &mocks.KafkaProducer{}
+	s.shardClosedCh = make(chan int, 100)
+	metricsClient := metrics.NewClient(tally.NoopScope, metrics.History)
+	s.mockMessagingClient = mocks.NewMockMessagingClient(s.mockProducer, nil)
+	s.mockService = service.NewTestService(s.mockClusterMetadata, s.mockMessagingClient, metricsClient, s.logger)
+
+	domainCache := cache.NewDomainCache(s.mockMetadataMgr, s.mockClusterMetadata, s.logger)
+	mockShard := &shardContextImpl{
+		service:                   s.mockService,
+		shardInfo:                 &persistence.ShardInfo{ShardID: shardID, RangeID: 1, TransferAckLevel: 0},
+		transferSequenceNumber:    1,
+		executionManager:          s.mockExecutionMgr,
+		historyMgr:                s.mockHistoryMgr,
+		domainCache:               domainCache,
+		shardManager:              s.mockShardManager,
+		maxTransferSequenceNumber: 100000,
+		closeCh:                   s.shardClosedCh,
+		config:                    s.config,
+		logger:                    s.logger,
+		metricsClient:             metrics.NewClient(tally.NoopScope, metrics.History),
+	}
+
+	historyCache := newHistoryCache(mockShard, s.logger)
+	engine := &historyEngineImpl{
+		shard:              mockShard,
+		executionManager:   s.mockExecutionMgr,
+		historyMgr:         s.mockHistoryMgr,
+		historyCache:       historyCache,
+		logger:             s.logger,
+		metricsClient:      metrics.NewClient(tally.NoopScope, metrics.History),
+		tokenSerializer:    common.NewJSONTaskTokenSerializer(),
+		hSerializerFactory: persistence.NewHistorySerializerFactory(),
+	}
+
+	// this is used by shard context, not relevent to this test, so we do not care how many times "GetCurrentClusterName" os called
+	s.mockClusterMetadata.On("GetCurrentClusterName").Return(cluster.TestCurrentClusterName)
+	engine.txProcessor = newTransferQueueProcessor(mockShard, engine, s.mockVisibilityMgr, s.mockMatchingClient,
+		s.mockHistoryClient)
+	engine.timerProcessor = newTimerQueueProcessor(mockShard, engine, s.mockExecutionMgr, s.logger)
+	engine.replicator = newHistoryReplicator(mockShard, engine, historyCache, s.mockHistoryMgr, s.logger)
+	s.replicator = engine.replicator
+}
+
+func (s *historyReplicatorSuite) TearDownTest() {
+	s.mockMatchingClient.AssertExpectations(s.T())
+	s.mockExecutionMgr.AssertExpectations(s.T())
+	s.mockHistoryMgr.AssertExpectations(s.T())
+	s.mockShardManager.AssertExpectations(s.T())
+	s.mockVisibilityMgr.AssertExpectations(s.T())
+	s.mockProducer.AssertExpectations(s.T())
+}
+
+func (s *historyReplicatorSuite) TestApplyEventsEmptyHistory() {
+	err := s.replicator.ApplyEvents(&h.ReplicateEventsRequest{
+		SourceCluster:     common.StringPtr(testSourceCluster),
+		DomainUUID:        common.StringPtr("domainId"),
+		WorkflowExecution: s.getWorkflowExecution(),
+		FirstEventId:      common.Int64Ptr(1),
+		NextEventId:       common.Int64Ptr(1),
+		Version:           common.Int64Ptr(1),
+		History:           &workflow.History{},
+	})
+	s.Nil(err)
+}
+
+func (s *historyReplicatorSuite) TestApplyEventsInvalidEventRange() {
+	request := s.getReplicateRequest(5, 1)
+	request.FirstEventId = common.Int64Ptr(4)
+
+	err := s.replicator.ApplyEvents(request)
+	s.Equal(ErrInvalidReplicationEvents, err)
+
+	request = s.getReplicateRequest(5, 1)
+	request.NextEventId = common.Int64Ptr(7)
+
+	err = s.replicator.ApplyEvents(request)
+	s.Equal(ErrInvalidReplicationEvents, err)
+}
+
+func (s *historyReplicatorSuite) TestApplyEventsMissingExecution() {
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(nil, &workflow.EntityNotExistsError{}).Once()
+
+	err := s.replicator.ApplyEvents(s.getReplicateRequest(5, 1))
+	s.Equal(ErrRetryReplication, err)
+}
+
+func (s *historyReplicatorSuite) TestApplyEventsMissingReplicationState() {
+	state := s.getMutableState(5, nil)
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: state}, nil).Once()
+
+	err := s.replicator.ApplyEvents(s.getReplicateRequest(5, 1))
+	s.Equal(ErrMissingReplicationState, err)
+}
+
+func (s *historyReplicatorSuite) TestApplyEventsStaleVersion() {
+	state := s.getMutableState(5, &persistence.ReplicationState{
+		CurrentVersion:   2,
+		StartVersion:     1,
+		LastWriteVersion: 2,
+		LastWriteEventID: 4,
+	})
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: state}, nil).Once()
+
+	err := s.replicator.ApplyEvents(s.getReplicateRequest(5, 1))
+	s.Nil(err)
+}
+
+func (s *historyReplicatorSuite) TestApplyEventsDuplicate() {
+	state := s.getMutableState(5, &persistence.ReplicationState{
+		CurrentVersion:   1,
+		StartVersion:     1,
+		LastWriteVersion: 1,
+		LastWriteEventID: 4,
+		LastReplicationInfo: map[string]*persistence.ReplicationInfo{
+			testSourceCluster: {Version: 1, LastEventID: 4},
+		},
+	})
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: state}, nil).Once()
+
+	err := s.replicator.ApplyEvents(s.getReplicateRequest(3, 1))
+	s.Nil(err)
+}
+
+func (s *historyReplicatorSuite) TestApplyEventsOutOfOrder() {
+	state := s.getMutableState(5, &persistence.ReplicationState{
+		CurrentVersion:   1,
+		StartVersion:     1,
+		LastWriteVersion: 1,
+		LastWriteEventID: 4,
+	})
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: state}, nil).Once()
+
+	err := s.replicator.ApplyEvents(s.getReplicateRequest(7, 1))
+	s.Equal(ErrRetryReplication, err)
+}
+
+func (s *historyReplicatorSuite) TestApplyEventsWorkflowStarted() {
+	request := &h.ReplicateEventsRequest{
+		SourceCluster:     common.StringPtr(testSourceCluster),
+		DomainUUID:        common.StringPtr("domainId"),
+		WorkflowExecution: s.getWorkflowExecution(),
+		FirstEventId:      common.Int64Ptr(1),
+		NextEventId:       common.Int64Ptr(3),
+		Version:           common.Int64Ptr(1),
+		History: &workflow.History{
+			Events: []*workflow.HistoryEvent{
+				{
+					EventId:   common.Int64Ptr(1),
+					Timestamp: common.Int64Ptr(0),
+					EventType: common.EventTypePtr(workflow.EventTypeWorkflowExecutionStarted),
+					WorkflowExecutionStartedEventAttributes: &workflow.WorkflowExecutionStartedEventAttributes{
+						WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("wType")},
+						TaskList:                            &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+						ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(100),
+						TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(10),
+					},
+				},
+				{
+					EventId:   common.Int64Ptr(2),
+					Timestamp: common.Int64Ptr(0),
+					EventType: common.EventTypePtr(workflow.EventTypeDecisionTaskScheduled),
+					DecisionTaskScheduledEventAttributes: &workflow.DecisionTaskScheduledEventAttributes{
+						TaskList:                   &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+						StartToCloseTimeoutSeconds: common.Int32Ptr(10),
+					},
+				},
+			},
+		},
+	}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(nil, &workflow.EntityNotExistsError{}).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("CreateWorkflowExecution", mock.Anything).Return(
+		&persistence.CreateWorkflowExecutionResponse{TaskID: uuid.New()}, nil).Once()
+
+	err := s.replicator.ApplyEvents(request)
+	s.Nil(err)
+
+	createRequest := s.mockExecutionMgr.Calls[1].Arguments.Get(0).(*persistence.CreateWorkflowExecutionRequest)
+	s.Equal(int64(3), createRequest.NextEventID)
+	s.Equal(int64(2), createRequest.DecisionScheduleID)
+	s.Empty(createRequest.TransferTasks)
+	s.NotNil(createRequest.ReplicationState)
+	s.Equal(int64(1), createRequest.ReplicationState.LastWriteVersion)
+	s.Equal(int64(2), createRequest.ReplicationState.LastWriteEventID)
+	s.Equal(int64(2), createRequest.ReplicationState.LastReplicationInfo[testSourceCluster].LastEventID)
+}
+
+func (s *historyReplicatorSuite) TestApplyEventsWorkflowUpdated() {
+	state := s.getMutableState(5, &persistence.ReplicationState{
+		CurrentVersion:   1,
+		StartVersion:     1,
+		LastWriteVersion: 1,
+		LastWriteEventID: 4,
+		LastReplicationInfo: map[string]*persistence.ReplicationInfo{
+			testSourceCluster: {Version: 1, LastEventID: 4},
+		},
+	})
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: state}, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	err := s.replicator.ApplyEvents(s.getReplicateRequest(5, 1))
+	s.Nil(err)
+
+	appendRequest := s.mockHistoryMgr.Calls[0].Arguments.Get(0).(*persistence.AppendHistoryEventsRequest)
+	s.Equal(int64(5), appendRequest.FirstEventID)
+	updateRequest := s.mockExecutionMgr.Calls[1].Arguments.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	s.Equal(int64(5), updateRequest.Condition)
+	s.Equal(int64(6), updateRequest.ExecutionInfo.NextEventID)
+	s.Equal(persistence.WorkflowStateRunning, updateRequest.ExecutionInfo.State)
+	s.Empty(updateRequest.TransferTasks)
+	s.Empty(updateRequest.TimerTasks)
+	s.Empty(updateRequest.ReplicationTasks)
+	s.Equal(int64(5), updateRequest.ReplicationState.LastWriteEventID)
+	s.Equal(int64(5), updateRequest.ReplicationState.LastReplicationInfo[testSourceCluster].LastEventID)
+}
+
+func (s *historyReplicatorSuite) TestApplyEventsWorkflowUpdatedClosed() {
+	state := s.getMutableState(5, &persistence.ReplicationState{
+		CurrentVersion:   1,
+		StartVersion:     1,
+		LastWriteVersion: 1,
+		LastWriteEventID: 4,
+	})
+	request := s.getReplicateRequest(5, 1)
+	request.History.Events[0] = &workflow.HistoryEvent{
+		EventId:   common.Int64Ptr(5),
+		Timestamp: common.Int64Ptr(0),
+		EventType: common.EventTypePtr(workflow.EventTypeWorkflowExecutionCompleted),
+		WorkflowExecutionCompletedEventAttributes: &workflow.WorkflowExecutionCompletedEventAttributes{
+			DecisionTaskCompletedEventId: common.Int64Ptr(4),
+		},
+	}
+
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: state}, nil).Once()
+	s.mockMetadataMgr.On("GetDomain", mock.Anything).Return(
+		&persistence.GetDomainResponse{Config: &persistence.DomainConfig{Retention: 1}}, nil)
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Once()
+
+	err := s.replicator.ApplyEvents(request)
+	s.Nil(err)
+
+	updateRequest := s.mockExecutionMgr.Calls[1].Arguments.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	s.Equal(persistence.WorkflowStateCompleted, updateRequest.ExecutionInfo.State)
+	s.Equal(persistence.WorkflowCloseStatusCompleted, updateRequest.ExecutionInfo.CloseStatus)
+	s.True(updateRequest.FinishExecution)
+	s.Empty(updateRequest.TransferTasks)
+	s.Equal(1, len(updateRequest.TimerTasks))
+	s.Equal(persistence.TaskTypeDeleteHistoryEvent, updateRequest.TimerTasks[0].GetType())
+}
+
+func (s *historyReplicatorSuite) TestApplyEventsWorkflowUpdatedConflict() {
+	state := s.getMutableState(5, &persistence.ReplicationState{
+		CurrentVersion:   1,
+		StartVersion:     1,
+		LastWriteVersion: 1,
+		LastWriteEventID: 4,
+	})
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: state}, nil).Once()
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(
+		&persistence.ConditionFailedError{}).Once()
+
+	// Events were applied by a concurrent update, so the batch is dropped as a duplicate after reloading
+	appliedState := s.getMutableState(6, &persistence.ReplicationState{
+		CurrentVersion:   1,
+		StartVersion:     1,
+		LastWriteVersion: 1,
+		LastWriteEventID: 5,
+	})
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(
+		&persistence.GetWorkflowExecutionResponse{State: appliedState}, nil).Once()
+
+	err := s.replicator.ApplyEvents(s.getReplicateRequest(5, 1))
+	s.Nil(err)
+	s.Equal(3, len(s.mockExecutionMgr.Calls))
+}
+
+func (s *historyReplicatorSuite) getWorkflowExecution() *workflow.WorkflowExecution {
+	return &workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr("rId"),
+	}
+}
+
+func (s *historyReplicatorSuite) getReplicateRequest(firstEventID, version int64) *h.ReplicateEventsRequest {
+	return &h.ReplicateEventsRequest{
+		SourceCluster:     common.StringPtr(testSourceCluster),
+		DomainUUID:        common.StringPtr("domainId"),
+		WorkflowExecution: s.getWorkflowExecution(),
+		FirstEventId:      common.Int64Ptr(firstEventID),
+		NextEventId:       common.Int64Ptr(firstEventID + 1),
+		Version:           common.Int64Ptr(version),
+		History: &workflow.History{
+			Events: []*workflow.HistoryEvent{
+				{
+					EventId:   common.Int64Ptr(firstEventID),
+					Timestamp: common.Int64Ptr(0),
+					EventType: common.EventTypePtr(workflow.EventTypeWorkflowExecutionSignaled),
+					WorkflowExecutionSignaledEventAttributes: &workflow.WorkflowExecutionSignaledEventAttributes{
+						SignalName: common.StringPtr("signal"),
+					},
+				},
+			},
+		},
+	}
+}
+
+func (s *historyReplicatorSuite) getMutableState(nextEventID int64,
+	replicationState *persistence.ReplicationState) *persistence.WorkflowMutableState {
+	return &persistence.WorkflowMutableState{
+		ExecutionInfo: &persistence.WorkflowExecutionInfo{
+			DomainID:    "domainId",
+			WorkflowID:  "wId",
+			RunID:       "rId",
+			TaskList:    "testTaskList",
+			State:       persistence.WorkflowStateRunning,
+			NextEventID: nextEventID,
+		},
+		ActivitInfos:        make(map[int64]*persistence.ActivityInfo),
+		TimerInfos:          make(map[string]*persistence.TimerInfo),
+		ChildExecutionInfos: make(map[int64]*persistence.ChildExecutionInfo),
+		RequestCancelInfos:  make(map[int64]*persistence.RequestCancelInfo),
+		SignalInfos:         make(map[int64]*persistence.SignalInfo),
+		SignalRequestedIDs:  make(map[string]struct{}),
+		ReplicationState:    replicationState,
+	}
+}
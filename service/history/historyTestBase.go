@@ -103,6 +103,11 @@ func newTestShardContext(shardInfo *persistence.ShardInfo, transferSequenceNumbe
 	}
 }
 
+// GetShardID test implementation
+func (s *TestShardContext) GetShardID() int {
+	return s.shardInfo.ShardID
+}
+
 // GetService test implementation
 func (s *TestShardContext) GetService() service.Service {
 	return s.service
@@ -262,8 +267,11 @@ func (s *TestShardContext) GetTimeSource() common.TimeSource {
 	return common.NewRealTimeSource()
 }
 
-// SetupWorkflowStoreWithOptions to setup workflow test base
+// SetupWorkflowStoreWithOptions to setup workflow test base, the history tests always run against the in-memory
+// persistence
 func (s *TestBase) SetupWorkflowStoreWithOptions(options persistence.TestBaseOptions) {
+	s.TestBase.InMemory = true
+	options.InMemory = true
 	s.TestBase.SetupWorkflowStoreWithOptions(options)
 	log := bark.NewLoggerFromLogrus(log.New())
 	config := NewConfig(dynamicconfig.NewNopCollection(), 1)
@@ -273,8 +281,9 @@ func (s *TestBase) SetupWorkflowStoreWithOptions(options persistence.TestBaseOpt
 	s.TestBase.TaskIDGenerator = s.ShardContext
 }
 
-// SetupWorkflowStore to setup workflow test base
+// SetupWorkflowStore to setup workflow test base, the history tests always run against the in-memory persistence
 func (s *TestBase) SetupWorkflowStore() {
+	s.TestBase.InMemory = true
 	s.TestBase.SetupWorkflowStore()
 	log := bark.NewLoggerFromLogrus(log.New())
 	config := NewConfig(dynamicconfig.NewNopCollection(), 1)
@@ -28,6 +28,7 @@ import (
 	h "github.com/uber/cadence/.gen/go/history"
 	workflow "github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/logging"
 	"github.com/uber/cadence/common/persistence"
 
@@ -175,8 +176,12 @@ func (e *mutableStateBuilder) FlushBufferedEvents() error {
 	// no decision in-flight, flush all buffered events to committed bucket
 	if !e.HasInFlightDecisionTask() {
 		flush := func(bufferedEventBatch *persistence.SerializedHistoryEventBatch) error {
-			// TODO: get serializer based on eventBatch's EncodingType when we support multiple encoding
-			eventBatch, err := e.hBuilder.serializer.Deserialize(bufferedEventBatch)
+			serializer, err := e.hBuilder.getDeserializer(bufferedEventBatch)
+			if err != nil {
+				logging.LogHistoryDeserializationErrorEvent(e.logger, err, "Unable to serialize execution history for update.")
+				return err
+			}
+			eventBatch, err := serializer.Deserialize(bufferedEventBatch)
 			if err != nil {
 				logging.LogHistoryDeserializationErrorEvent(e.logger, err, "Unable to serialize execution history for update.")
 				return err
@@ -219,7 +224,7 @@ func (e *mutableStateBuilder) FlushBufferedEvents() error {
 	if e.HasInFlightDecisionTask() && len(newBufferedEvents) > 0 {
 		// decision in-flight, and some new events needs to be buffered
 		bufferedBatch := persistence.NewHistoryEventBatch(persistence.GetDefaultHistoryVersion(), newBufferedEvents)
-		serializedEvents, err := e.hBuilder.serializer.Serialize(bufferedBatch)
+		serializedEvents, err := e.hBuilder.getSerializer().Serialize(bufferedBatch)
 		if err != nil {
 			logging.LogHistorySerializationErrorEvent(e.logger, err, "Unable to serialize execution history for update.")
 			return err
@@ -237,6 +242,20 @@ func (e *mutableStateBuilder) ApplyReplicationStateUpdates(failoverVersion int64
 	e.replicationState.LastWriteEventID = e.GetNextEventID() - 1
 }
 
+func (e *mutableStateBuilder) ApplyReplicationStateUpdatesFromRemote(sourceCluster string, version,
+	lastEventID int64) {
+	e.replicationState.CurrentVersion = version
+	e.replicationState.LastWriteVersion = version
+	e.replicationState.LastWriteEventID = lastEventID
+	if e.replicationState.LastReplicationInfo == nil {
+		e.replicationState.LastReplicationInfo = make(map[string]*persistence.ReplicationInfo)
+	}
+	e.replicationState.LastReplicationInfo[sourceCluster] = &persistence.ReplicationInfo{
+		Version:     version,
+		LastEventID: lastEventID,
+	}
+}
+
 func (e *mutableStateBuilder) CloseUpdateSession(createReplicationTask bool) (*mutableStateSessionUpdates, error) {
 	if err := e.FlushBufferedEvents(); err != nil {
 		return nil, err
@@ -436,6 +455,7 @@ func (e *mutableStateBuilder) shouldBufferEvent(eventType workflow.EventType) bo
 		workflow.EventTypeCancelTimerFailed,
 		workflow.EventTypeRequestCancelExternalWorkflowExecutionInitiated,
 		workflow.EventTypeMarkerRecorded,
+		workflow.EventTypeUpsertWorkflowSearchAttributes,
 		workflow.EventTypeStartChildWorkflowExecutionInitiated,
 		workflow.EventTypeSignalExternalWorkflowExecutionInitiated:
 		// do not buffer event if event is directly generated from a corresponding decision
@@ -730,6 +750,16 @@ func (e *mutableStateBuilder) HasPendingDecisionTask() bool {
 	return e.executionInfo.DecisionScheduleID != emptyEventID
 }
 
+// hasPendingFirstDecisionTaskBackoff returns true if the first decision of the run is delayed by a retry or cron
+// backoff and the backoff timer has not scheduled it yet.
+func (e *mutableStateBuilder) hasPendingFirstDecisionTaskBackoff() bool {
+	if e.HasPendingDecisionTask() || e.executionInfo.LastProcessedEvent != emptyEventID {
+		return false
+	}
+
+	return e.executionInfo.CronSchedule != "" || (e.executionInfo.HasRetryPolicy && e.executionInfo.Attempt > 0)
+}
+
 func (e *mutableStateBuilder) HasInFlightDecisionTask() bool {
 	return e.executionInfo.DecisionStartedID > 0
 }
@@ -853,7 +883,7 @@ func (e *mutableStateBuilder) getHistoryEvent(serializedEvent []byte) (*workflow
 
 func (e *mutableStateBuilder) AddWorkflowExecutionStartedEventForContinueAsNew(domainID string,
 	execution workflow.WorkflowExecution, previousExecutionState *mutableStateBuilder,
-	attributes *workflow.ContinueAsNewWorkflowExecutionDecisionAttributes) *workflow.HistoryEvent {
+	attributes *workflow.ContinueAsNewWorkflowExecutionDecisionAttributes, now time.Time) *workflow.HistoryEvent {
 	taskList := previousExecutionState.executionInfo.TaskList
 	if attributes.TaskList != nil {
 		taskList = *attributes.TaskList.Name
@@ -881,15 +911,47 @@ func (e *mutableStateBuilder) AddWorkflowExecutionStartedEventForContinueAsNew(d
 		WorkflowType:                        wType,
 		TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(decisionTimeout),
 		ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(*attributes.ExecutionStartToCloseTimeoutSeconds),
-		Input:    attributes.Input,
-		Identity: nil,
+		Input:                               attributes.Input,
+		Identity:                            nil,
+		RetryPolicy:                         attributes.RetryPolicy,
+		CronSchedule:                        attributes.CronSchedule,
+		SearchAttributes:                    attributes.SearchAttributes,
+	}
+	if createRequest.SearchAttributes == nil {
+		createRequest.SearchAttributes = getSearchAttributes(previousExecutionState.executionInfo.SearchAttributes)
+	}
+
+	// Only a retry carries over the attempt and expiration of the retry policy, every other new run starts over
+	attempt := int32(0)
+	expirationTime := time.Time{}
+	if attributes.GetInitiator() == workflow.ContinueAsNewInitiatorRetryPolicy {
+		attempt = previousExecutionState.executionInfo.Attempt + 1
+		expirationTime = previousExecutionState.executionInfo.ExpirationTime
 	}
 
-	return e.AddWorkflowExecutionStartedEvent(domainID, execution, createRequest)
+	return e.addWorkflowExecutionStartedEventForRun(domainID, execution, createRequest, attempt, expirationTime,
+		attributes.GetBackoffStartIntervalInSeconds(), previousExecutionState.executionInfo.Priority, now)
 }
 
 func (e *mutableStateBuilder) AddWorkflowExecutionStartedEvent(domainID string, execution workflow.WorkflowExecution,
-	request *workflow.StartWorkflowExecutionRequest) *workflow.HistoryEvent {
+	request *workflow.StartWorkflowExecutionRequest, priority int32, now time.Time) *workflow.HistoryEvent {
+	// First decision of a cron workflow is delayed until the first time the cron schedule fires
+	firstDecisionTaskBackoffSeconds := int32(0)
+	if request.GetCronSchedule() != "" {
+		cronBackoff := backoff.GetBackoffForNextSchedule(request.GetCronSchedule(), now)
+		if cronBackoff != backoff.NoBackoff {
+			firstDecisionTaskBackoffSeconds = int32(cronBackoff.Seconds())
+		}
+	}
+
+	return e.addWorkflowExecutionStartedEventForRun(domainID, execution, request, 0, time.Time{},
+		firstDecisionTaskBackoffSeconds, priority, now)
+}
+
+func (e *mutableStateBuilder) addWorkflowExecutionStartedEventForRun(domainID string,
+	execution workflow.WorkflowExecution, request *workflow.StartWorkflowExecutionRequest, attempt int32,
+	expirationTime time.Time, firstDecisionTaskBackoffSeconds int32, priority int32,
+	now time.Time) *workflow.HistoryEvent {
 	eventID := e.GetNextEventID()
 	if eventID != firstEventID {
 		logging.LogInvalidHistoryActionEvent(e.logger, logging.TagValueActionWorkflowStarted, eventID, "")
@@ -913,7 +975,21 @@ func (e *mutableStateBuilder) AddWorkflowExecutionStartedEvent(domainID string,
 	e.executionInfo.DecisionRequestID = emptyUUID
 	e.executionInfo.DecisionTimeout = 0
 
-	return e.hBuilder.AddWorkflowExecutionStartedEvent(request)
+	e.executionInfo.Attempt = attempt
+	e.executionInfo.CronSchedule = request.GetCronSchedule()
+	e.executionInfo.SearchAttributes = request.GetSearchAttributes().GetIndexedFields()
+	e.executionInfo.Priority = priority
+	if request.RetryPolicy != nil {
+		if expirationTime.IsZero() && request.RetryPolicy.GetExpirationIntervalInSeconds() > 0 {
+			// Expiration is measured from the time the run actually starts, after the first decision backoff
+			expirationTime = now.Add(time.Duration(firstDecisionTaskBackoffSeconds+
+				request.RetryPolicy.GetExpirationIntervalInSeconds()) * time.Second)
+		}
+		setWorkflowRetryPolicy(e.executionInfo, request.RetryPolicy, expirationTime)
+	}
+
+	return e.hBuilder.AddWorkflowExecutionStartedEvent(request, attempt, expirationTime,
+		firstDecisionTaskBackoffSeconds, priority)
 }
 
 func (e *mutableStateBuilder) AddDecisionTaskScheduledEvent() *decisionInfo {
@@ -983,12 +1059,14 @@ func (e *mutableStateBuilder) AddDecisionTaskStartedEvent(scheduleEventID int64,
 	// Avoid creating new history events when decisions are continuously failing
 	if di.Attempt == 0 {
 		// Now create DecisionTaskStartedEvent
-		event = e.hBuilder.AddDecisionTaskStartedEvent(scheduleID, requestID, request.GetIdentity())
+		event = e.hBuilder.AddDecisionTaskStartedEvent(scheduleID, requestID, request.GetIdentity(),
+			request.GetBuildID())
 		startedID = event.GetEventId()
 		timestamp = int64(0)
 	}
 
 	e.executionInfo.State = persistence.WorkflowStateRunning
+	e.updateBuildID(request.GetBuildID())
 	// Update mutable decision state
 	di = &decisionInfo{
 		ScheduleID:      scheduleID,
@@ -1009,7 +1087,7 @@ func (e *mutableStateBuilder) createTransientDecisionEvents(di *decisionInfo, id
 	scheduledEvent := newDecisionTaskScheduledEventWithInfo(di.ScheduleID, di.Timestamp, tasklist, di.DecisionTimeout,
 		di.Attempt)
 	startedEvent := newDecisionTaskStartedEventWithInfo(di.StartedID, di.Timestamp, di.ScheduleID, di.RequestID,
-		identity)
+		identity, e.getFirstDecisionBuildID())
 
 	return scheduledEvent, startedEvent
 }
@@ -1032,7 +1110,7 @@ func (e *mutableStateBuilder) AddDecisionTaskCompletedEvent(scheduleEventID, sta
 		// Create corresponding DecisionTaskSchedule and DecisionTaskStarted events for decisions we have been retrying
 		scheduledEvent := e.hBuilder.AddDecisionTaskScheduledEvent(e.executionInfo.TaskList, di.DecisionTimeout, di.Attempt)
 		startedEvent := e.hBuilder.AddDecisionTaskStartedEvent(scheduledEvent.GetEventId(), di.RequestID,
-			request.GetIdentity())
+			request.GetIdentity(), e.getFirstDecisionBuildID())
 		startedEventID = startedEvent.GetEventId()
 	}
 	// Now write the completed event
@@ -1124,21 +1202,21 @@ func (e *mutableStateBuilder) AddActivityTaskScheduledEvent(decisionCompletedEve
 	scheduleEventID := *event.EventId
 	var scheduleToStartTimeout int32
 	if attributes.ScheduleToStartTimeoutSeconds == nil || *attributes.ScheduleToStartTimeoutSeconds <= 0 {
-		scheduleToStartTimeout = e.config.DefaultScheduleToStartActivityTimeoutInSecs
+		scheduleToStartTimeout = int32(e.config.DefaultScheduleToStartActivityTimeout().Seconds())
 	} else {
 		scheduleToStartTimeout = *attributes.ScheduleToStartTimeoutSeconds
 	}
 
 	var scheduleToCloseTimeout int32
 	if attributes.ScheduleToCloseTimeoutSeconds == nil || *attributes.ScheduleToCloseTimeoutSeconds <= 0 {
-		scheduleToCloseTimeout = e.config.DefaultScheduleToCloseActivityTimeoutInSecs
+		scheduleToCloseTimeout = int32(e.config.DefaultScheduleToCloseActivityTimeout().Seconds())
 	} else {
 		scheduleToCloseTimeout = *attributes.ScheduleToCloseTimeoutSeconds
 	}
 
 	var startToCloseTimeout int32
 	if attributes.StartToCloseTimeoutSeconds == nil || *attributes.StartToCloseTimeoutSeconds <= 0 {
-		startToCloseTimeout = e.config.DefaultStartToCloseActivityTimeoutInSecs
+		startToCloseTimeout = int32(e.config.DefaultStartToCloseActivityTimeout().Seconds())
 	} else {
 		startToCloseTimeout = *attributes.StartToCloseTimeoutSeconds
 	}
@@ -1163,7 +1241,9 @@ func (e *mutableStateBuilder) AddActivityTaskScheduledEvent(decisionCompletedEve
 		CancelRequestID:          emptyEventID,
 		LastHeartBeatUpdatedTime: time.Time{},
 		TimerTaskStatus:          TimerTaskStatusNone,
+		Priority:                 e.getActivityPriority(attributes.Priority),
 	}
+	setActivityRetryPolicy(ai, attributes.RetryPolicy)
 
 	e.pendingActivityInfoIDs[scheduleEventID] = ai
 	e.pendingActivityInfoByActivityID[ai.ActivityID] = scheduleEventID
@@ -1180,7 +1260,26 @@ func (e *mutableStateBuilder) AddActivityTaskStartedEvent(ai *persistence.Activi
 		return nil
 	}
 
-	event := e.hBuilder.AddActivityTaskStartedEvent(scheduleEventID, requestID, request)
+	if ai.HasRetryPolicy {
+		// Started event of an activity with retry policy is only written to history once the activity is closed,
+		// so attempts which are retried do not show up in history.
+		event := newActivityTaskStartedEventWithInfo(transientEventID, time.Now().UnixNano(), scheduleEventID,
+			ai.Attempt, requestID, request.GetIdentity())
+		startedEvent, err := e.eventSerializer.Serialize(event)
+		if err != nil {
+			return nil
+		}
+
+		ai.StartedID = transientEventID
+		ai.StartedEvent = startedEvent
+		ai.RequestID = requestID
+		ai.StartedTime = time.Unix(0, event.GetTimestamp())
+		e.updateActivityInfos = append(e.updateActivityInfos, ai)
+
+		return event
+	}
+
+	event := e.hBuilder.AddActivityTaskStartedEvent(scheduleEventID, ai.Attempt, requestID, request.GetIdentity())
 
 	ai.StartedID = *event.EventId
 	ai.RequestID = requestID
@@ -1190,14 +1289,97 @@ func (e *mutableStateBuilder) AddActivityTaskStartedEvent(ai *persistence.Activi
 	return event
 }
 
+// addTransientActivityStartedEvent writes the started event of an activity with retry policy to history before the
+// activity is closed.  It returns the ID of the started event which the close event should refer to.
+func (e *mutableStateBuilder) addTransientActivityStartedEvent(ai *persistence.ActivityInfo) int64 {
+	if ai.StartedID != transientEventID {
+		return ai.StartedID
+	}
+
+	var identity string
+	if startedEvent, ok := e.getHistoryEvent(ai.StartedEvent); ok {
+		identity = startedEvent.ActivityTaskStartedEventAttributes.GetIdentity()
+	}
+
+	event := e.hBuilder.AddActivityTaskStartedEvent(ai.ScheduleID, ai.Attempt, ai.RequestID, identity)
+	// Keep the time when the last attempt was actually started
+	event.Timestamp = common.Int64Ptr(ai.StartedTime.UnixNano())
+
+	return event.GetEventId()
+}
+
+// RetryActivity resets the activity for its next attempt if allowed by the retry policy of the activity.  The
+// ScheduledTime of the activity is moved to the time when the next attempt should be dispatched, which is computed
+// from the given shard time.
+func (e *mutableStateBuilder) RetryActivity(ai *persistence.ActivityInfo, failureReason string, now time.Time) bool {
+	if !ai.HasRetryPolicy || ai.CancelRequested {
+		return false
+	}
+
+	backoffInterval := getBackoffInterval(ai.Attempt, ai.MaximumAttempts, ai.InitialInterval, ai.MaximumInterval,
+		ai.BackoffCoefficient, now, ai.ExpirationTime, failureReason, ai.NonRetriableErrors)
+	if backoffInterval == noRetryBackoff {
+		return false
+	}
+
+	ai.Attempt++
+	ai.ScheduledTime = now.Add(backoffInterval)
+	ai.StartedID = emptyEventID
+	ai.StartedEvent = nil
+	ai.StartedTime = time.Time{}
+	ai.RequestID = ""
+	ai.LastHeartBeatUpdatedTime = time.Time{}
+	ai.TimerTaskStatus = TimerTaskStatusNone
+	e.updateActivityInfos = append(e.updateActivityInfos, ai)
+
+	return true
+}
+
+// getRetryBackoffInterval returns the backoff before the next run of a workflow execution which failed with the given
+// reason at the given time.  It returns noRetryBackoff if the workflow execution has no retry policy or should not be
+// retried.
+func (e *mutableStateBuilder) getRetryBackoffInterval(failureReason string, now time.Time) time.Duration {
+	info := e.executionInfo
+	if !info.HasRetryPolicy {
+		return noRetryBackoff
+	}
+
+	return getBackoffInterval(info.Attempt, info.MaximumAttempts, info.InitialInterval, info.MaximumInterval,
+		info.BackoffCoefficient, now, info.ExpirationTime, failureReason, info.NonRetriableErrors)
+}
+
+// getCronBackoffInterval returns the backoff from the given time to the next run of a cron workflow execution.  It
+// returns backoff.NoBackoff if the workflow execution has no cron schedule.
+func (e *mutableStateBuilder) getCronBackoffInterval(now time.Time) time.Duration {
+	if e.executionInfo.CronSchedule == "" {
+		return backoff.NoBackoff
+	}
+
+	return backoff.GetBackoffForNextSchedule(e.executionInfo.CronSchedule, now)
+}
+
+// getFailureBackoffInterval returns the backoff before the next run of a workflow execution which failed with the
+// given reason at the given time, along with what initiates the next run.  Retry policy takes precedence over cron
+// schedule.  It returns backoff.NoBackoff if no new run should be started.
+func (e *mutableStateBuilder) getFailureBackoffInterval(failureReason string, now time.Time) (time.Duration,
+	workflow.ContinueAsNewInitiator) {
+	if backoffInterval := e.getRetryBackoffInterval(failureReason, now); backoffInterval != noRetryBackoff {
+		return backoffInterval, workflow.ContinueAsNewInitiatorRetryPolicy
+	}
+
+	return e.getCronBackoffInterval(now), workflow.ContinueAsNewInitiatorCronSchedule
+}
+
 func (e *mutableStateBuilder) AddActivityTaskCompletedEvent(scheduleEventID, startedEventID int64,
 	request *workflow.RespondActivityTaskCompletedRequest) *workflow.HistoryEvent {
-	if ai, ok := e.GetActivityInfo(scheduleEventID); !ok || ai.StartedID != startedEventID {
+	ai, ok := e.GetActivityInfo(scheduleEventID)
+	if !ok || ai.StartedID != startedEventID {
 		logging.LogInvalidHistoryActionEvent(e.logger, logging.TagValueActionActivityTaskCompleted, e.GetNextEventID(), fmt.Sprintf(
 			"{ScheduleID: %v, StartedID: %v, Exist: %v}", scheduleEventID, startedEventID, ok))
 		return nil
 	}
 
+	startedEventID = e.addTransientActivityStartedEvent(ai)
 	if err := e.DeleteActivity(scheduleEventID); err != nil {
 		return nil
 	}
@@ -1207,12 +1389,14 @@ func (e *mutableStateBuilder) AddActivityTaskCompletedEvent(scheduleEventID, sta
 
 func (e *mutableStateBuilder) AddActivityTaskFailedEvent(scheduleEventID, startedEventID int64,
 	request *workflow.RespondActivityTaskFailedRequest) *workflow.HistoryEvent {
-	if ai, ok := e.GetActivityInfo(scheduleEventID); !ok || ai.StartedID != startedEventID {
+	ai, ok := e.GetActivityInfo(scheduleEventID)
+	if !ok || ai.StartedID != startedEventID {
 		logging.LogInvalidHistoryActionEvent(e.logger, logging.TagValueActionActivityTaskFailed, e.GetNextEventID(), fmt.Sprintf(
 			"{ScheduleID: %v, StartedID: %v, Exist: %v}", scheduleEventID, startedEventID, ok))
 		return nil
 	}
 
+	startedEventID = e.addTransientActivityStartedEvent(ai)
 	if err := e.DeleteActivity(scheduleEventID); err != nil {
 		return nil
 	}
@@ -1222,7 +1406,8 @@ func (e *mutableStateBuilder) AddActivityTaskFailedEvent(scheduleEventID, starte
 
 func (e *mutableStateBuilder) AddActivityTaskTimedOutEvent(scheduleEventID, startedEventID int64,
 	timeoutType workflow.TimeoutType, lastHeartBeatDetails []byte) *workflow.HistoryEvent {
-	if ai, ok := e.GetActivityInfo(scheduleEventID); !ok || ai.StartedID != startedEventID ||
+	ai, ok := e.GetActivityInfo(scheduleEventID)
+	if !ok || ai.StartedID != startedEventID ||
 		((timeoutType == workflow.TimeoutTypeStartToClose || timeoutType == workflow.TimeoutTypeHeartbeat) &&
 			ai.StartedID == emptyEventID) {
 		logging.LogInvalidHistoryActionEvent(e.logger, logging.TagValueActionActivityTaskTimedOut, e.GetNextEventID(), fmt.Sprintf(
@@ -1231,6 +1416,7 @@ func (e *mutableStateBuilder) AddActivityTaskTimedOutEvent(scheduleEventID, star
 		return nil
 	}
 
+	startedEventID = e.addTransientActivityStartedEvent(ai)
 	if err := e.DeleteActivity(scheduleEventID); err != nil {
 		return nil
 	}
@@ -1281,6 +1467,7 @@ func (e *mutableStateBuilder) AddActivityTaskCanceledEvent(scheduleEventID, star
 		return nil
 	}
 
+	startedEventID = e.addTransientActivityStartedEvent(ai)
 	if err := e.DeleteActivity(scheduleEventID); err != nil {
 		return nil
 	}
@@ -1565,6 +1752,28 @@ func (e *mutableStateBuilder) AddRecordMarkerEvent(decisionCompletedEventID int6
 	return e.hBuilder.AddMarkerRecordedEvent(decisionCompletedEventID, attributes)
 }
 
+func (e *mutableStateBuilder) AddUpsertWorkflowSearchAttributesEvent(decisionCompletedEventID int64,
+	attributes *workflow.UpsertWorkflowSearchAttributesDecisionAttributes) *workflow.HistoryEvent {
+	event := e.hBuilder.AddUpsertWorkflowSearchAttributesEvent(decisionCompletedEventID, attributes)
+	e.ReplicateUpsertWorkflowSearchAttributesEvent(event)
+
+	return event
+}
+
+// ReplicateUpsertWorkflowSearchAttributesEvent merges the upserted search attributes into the ones of the execution.
+// The map is replaced rather than updated as it can be shared with the persisted execution info.
+func (e *mutableStateBuilder) ReplicateUpsertWorkflowSearchAttributesEvent(event *workflow.HistoryEvent) {
+	upserted := event.UpsertWorkflowSearchAttributesEventAttributes.GetSearchAttributes().GetIndexedFields()
+	searchAttributes := make(map[string][]byte, len(e.executionInfo.SearchAttributes)+len(upserted))
+	for name, value := range e.executionInfo.SearchAttributes {
+		searchAttributes[name] = value
+	}
+	for name, value := range upserted {
+		searchAttributes[name] = value
+	}
+	e.executionInfo.SearchAttributes = searchAttributes
+}
+
 func (e *mutableStateBuilder) AddWorkflowExecutionTerminatedEvent(
 	request *workflow.TerminateWorkflowExecutionRequest) *workflow.HistoryEvent {
 	if e.executionInfo.State == persistence.WorkflowStateCompleted {
@@ -1593,8 +1802,8 @@ func (e *mutableStateBuilder) AddWorkflowExecutionSignaled(
 }
 
 func (e *mutableStateBuilder) AddContinueAsNewEvent(decisionCompletedEventID int64, domainID, newRunID string,
-	attributes *workflow.ContinueAsNewWorkflowExecutionDecisionAttributes) (*workflow.HistoryEvent, *mutableStateBuilder,
-	error) {
+	attributes *workflow.ContinueAsNewWorkflowExecutionDecisionAttributes, now time.Time) (*workflow.HistoryEvent,
+	*mutableStateBuilder, error) {
 	if e.hasPendingTasks() || e.HasPendingDecisionTask() {
 		logging.LogInvalidHistoryActionEvent(e.logger, logging.TagValueActionContinueAsNew, e.GetNextEventID(), fmt.Sprintf(
 			"{OutStandingActivityTasks: %v, HasPendingDecision: %v}", len(e.pendingActivityInfoIDs),
@@ -1610,14 +1819,30 @@ func (e *mutableStateBuilder) AddContinueAsNewEvent(decisionCompletedEventID int
 
 	newStateBuilder := newMutableStateBuilder(e.config, e.logger)
 	startedEvent := newStateBuilder.AddWorkflowExecutionStartedEventForContinueAsNew(domainID, newExecution, e,
-		attributes)
+		attributes, now)
 	if startedEvent == nil {
 		return nil, nil, &workflow.InternalServiceError{Message: "Failed to add workflow execution started event."}
 	}
 
-	di := newStateBuilder.AddDecisionTaskScheduledEvent()
-	if di == nil {
-		return nil, nil, &workflow.InternalServiceError{Message: "Failed to add decision started event."}
+	// First decision of the new run is scheduled by the backoff timer when the run is started with a backoff
+	var transferTasks []persistence.Task
+	decisionScheduleID := emptyEventID
+	decisionStartedID := emptyEventID
+	decisionTimeout := int32(0)
+	if attributes.GetBackoffStartIntervalInSeconds() == 0 {
+		di := newStateBuilder.AddDecisionTaskScheduledEvent()
+		if di == nil {
+			return nil, nil, &workflow.InternalServiceError{Message: "Failed to add decision started event."}
+		}
+
+		transferTasks = []persistence.Task{&persistence.DecisionTask{
+			DomainID:   domainID,
+			TaskList:   newStateBuilder.executionInfo.TaskList,
+			ScheduleID: di.ScheduleID,
+		}}
+		decisionScheduleID = di.ScheduleID
+		decisionStartedID = di.StartedID
+		decisionTimeout = di.DecisionTimeout
 	}
 
 	parentDomainID := ""
@@ -1640,7 +1865,7 @@ func (e *mutableStateBuilder) AddContinueAsNewEvent(decisionCompletedEventID int
 			CurrentVersion:   failoverVersion,
 			StartVersion:     failoverVersion,
 			LastWriteVersion: failoverVersion,
-			LastWriteEventID: di.ScheduleID,
+			LastWriteEventID: newStateBuilder.GetNextEventID() - 1,
 		}
 
 		replicationTask := &persistence.HistoryReplicationTask{
@@ -1653,31 +1878,38 @@ func (e *mutableStateBuilder) AddContinueAsNewEvent(decisionCompletedEventID int
 	}
 
 	e.continueAsNew = &persistence.CreateWorkflowExecutionRequest{
-		RequestID:            uuid.New(),
-		DomainID:             domainID,
-		Execution:            newExecution,
-		ParentDomainID:       parentDomainID,
-		ParentExecution:      parentExecution,
-		InitiatedID:          initiatedID,
-		TaskList:             newStateBuilder.executionInfo.TaskList,
-		WorkflowTypeName:     newStateBuilder.executionInfo.WorkflowTypeName,
-		WorkflowTimeout:      newStateBuilder.executionInfo.WorkflowTimeout,
-		DecisionTimeoutValue: newStateBuilder.executionInfo.DecisionTimeoutValue,
-		ExecutionContext:     nil,
-		NextEventID:          newStateBuilder.GetNextEventID(),
-		LastProcessedEvent:   common.EmptyEventID,
-		TransferTasks: []persistence.Task{&persistence.DecisionTask{
-			DomainID:   domainID,
-			TaskList:   newStateBuilder.executionInfo.TaskList,
-			ScheduleID: di.ScheduleID,
-		}},
-		DecisionScheduleID:          di.ScheduleID,
-		DecisionStartedID:           di.StartedID,
-		DecisionStartToCloseTimeout: di.DecisionTimeout,
+		RequestID:                   uuid.New(),
+		DomainID:                    domainID,
+		Execution:                   newExecution,
+		ParentDomainID:              parentDomainID,
+		ParentExecution:             parentExecution,
+		InitiatedID:                 initiatedID,
+		TaskList:                    newStateBuilder.executionInfo.TaskList,
+		WorkflowTypeName:            newStateBuilder.executionInfo.WorkflowTypeName,
+		WorkflowTimeout:             newStateBuilder.executionInfo.WorkflowTimeout,
+		DecisionTimeoutValue:        newStateBuilder.executionInfo.DecisionTimeoutValue,
+		ExecutionContext:            nil,
+		NextEventID:                 newStateBuilder.GetNextEventID(),
+		LastProcessedEvent:          common.EmptyEventID,
+		TransferTasks:               transferTasks,
+		DecisionScheduleID:          decisionScheduleID,
+		DecisionStartedID:           decisionStartedID,
+		DecisionStartToCloseTimeout: decisionTimeout,
 		ContinueAsNew:               true,
 		PreviousRunID:               prevRunID,
 		ReplicationState:            replicationState,
 		ReplicationTasks:            replicationTasks,
+		Attempt:                     newStateBuilder.executionInfo.Attempt,
+		HasRetryPolicy:              newStateBuilder.executionInfo.HasRetryPolicy,
+		InitialInterval:             newStateBuilder.executionInfo.InitialInterval,
+		BackoffCoefficient:          newStateBuilder.executionInfo.BackoffCoefficient,
+		MaximumInterval:             newStateBuilder.executionInfo.MaximumInterval,
+		ExpirationTime:              newStateBuilder.executionInfo.ExpirationTime,
+		MaximumAttempts:             newStateBuilder.executionInfo.MaximumAttempts,
+		NonRetriableErrors:          newStateBuilder.executionInfo.NonRetriableErrors,
+		CronSchedule:                newStateBuilder.executionInfo.CronSchedule,
+		SearchAttributes:            newStateBuilder.executionInfo.SearchAttributes,
+		Priority:                    newStateBuilder.executionInfo.Priority,
 	}
 
 	return e.hBuilder.AddContinuedAsNewEvent(decisionCompletedEventID, newRunID, attributes), newStateBuilder, nil
@@ -1861,3 +2093,310 @@ func (e *mutableStateBuilder) AddChildWorkflowExecutionTimedOutEvent(initiatedID
 
 	return nil
 }
+
+func (e *mutableStateBuilder) ReplicateWorkflowExecutionStartedEvent(domainID, requestID string,
+	execution workflow.WorkflowExecution, event *workflow.HistoryEvent) {
+	attributes := event.WorkflowExecutionStartedEventAttributes
+	e.executionInfo.DomainID = domainID
+	e.executionInfo.WorkflowID = execution.GetWorkflowId()
+	e.executionInfo.RunID = execution.GetRunId()
+	e.executionInfo.TaskList = attributes.TaskList.GetName()
+	e.executionInfo.WorkflowTypeName = attributes.WorkflowType.GetName()
+	e.executionInfo.WorkflowTimeout = attributes.GetExecutionStartToCloseTimeoutSeconds()
+	e.executionInfo.DecisionTimeoutValue = attributes.GetTaskStartToCloseTimeoutSeconds()
+
+	e.executionInfo.State = persistence.WorkflowStateCreated
+	e.executionInfo.CloseStatus = persistence.WorkflowCloseStatusNone
+	e.executionInfo.LastProcessedEvent = emptyEventID
+	e.executionInfo.CreateRequestID = requestID
+	e.executionInfo.DecisionScheduleID = emptyEventID
+	e.executionInfo.DecisionStartedID = emptyEventID
+	e.executionInfo.DecisionRequestID = emptyUUID
+	e.executionInfo.DecisionTimeout = 0
+
+	e.executionInfo.Attempt = attributes.GetAttempt()
+	e.executionInfo.CronSchedule = attributes.GetCronSchedule()
+	e.executionInfo.SearchAttributes = attributes.GetSearchAttributes().GetIndexedFields()
+	e.executionInfo.Priority = attributes.GetPriority()
+	if attributes.RetryPolicy != nil {
+		expirationTime := time.Time{}
+		if attributes.GetExpirationTimestamp() > 0 {
+			expirationTime = time.Unix(0, attributes.GetExpirationTimestamp())
+		}
+		setWorkflowRetryPolicy(e.executionInfo, attributes.RetryPolicy, expirationTime)
+	}
+}
+
+func (e *mutableStateBuilder) ReplicateDecisionTaskScheduledEvent(scheduleID int64, taskList string,
+	startToCloseTimeoutSeconds int32, attempt int64) *decisionInfo {
+	di := &decisionInfo{
+		ScheduleID:      scheduleID,
+		StartedID:       emptyEventID,
+		RequestID:       emptyUUID,
+		DecisionTimeout: startToCloseTimeoutSeconds,
+		Tasklist:        taskList,
+		Attempt:         attempt,
+	}
+	e.UpdateDecision(di)
+
+	return di
+}
+
+func (e *mutableStateBuilder) ReplicateDecisionTaskStartedEvent(scheduleID, startedID int64,
+	requestID string, buildID string) *decisionInfo {
+	e.executionInfo.State = persistence.WorkflowStateRunning
+	e.updateBuildID(buildID)
+	di := &decisionInfo{
+		ScheduleID:      scheduleID,
+		StartedID:       startedID,
+		RequestID:       requestID,
+		DecisionTimeout: e.executionInfo.DecisionTimeout,
+		Attempt:         e.executionInfo.DecisionAttempt,
+	}
+	e.UpdateDecision(di)
+
+	return di
+}
+
+// updateBuildID pins the workflow to the build ID of the worker which started its decision, until the first decision
+// is completed
+func (e *mutableStateBuilder) updateBuildID(buildID string) {
+	if e.executionInfo.LastProcessedEvent == emptyEventID {
+		e.executionInfo.BuildID = buildID
+	}
+}
+
+// getFirstDecisionBuildID returns the build ID for the started event of a transient decision, the build ID of the
+// worker is only known for the first decision of the workflow
+func (e *mutableStateBuilder) getFirstDecisionBuildID() string {
+	if e.executionInfo.LastProcessedEvent == emptyEventID {
+		return e.executionInfo.BuildID
+	}
+	return ""
+}
+
+// getPinnedBuildID returns the build ID the decision tasks of the workflow are dispatched to, workflows are pinned
+// once their first decision is completed
+func (e *mutableStateBuilder) getPinnedBuildID() string {
+	if e.executionInfo.LastProcessedEvent == emptyEventID {
+		return ""
+	}
+	return e.executionInfo.BuildID
+}
+
+func (e *mutableStateBuilder) ReplicateDecisionTaskCompletedEvent(startedEventID int64) {
+	e.DeleteDecision()
+	e.executionInfo.LastProcessedEvent = startedEventID
+}
+
+func (e *mutableStateBuilder) ReplicateActivityTaskScheduledEvent(
+	event *workflow.HistoryEvent) (*persistence.ActivityInfo, error) {
+	attributes := event.ActivityTaskScheduledEventAttributes
+	scheduleEvent, err := e.eventSerializer.Serialize(event)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleEventID := event.GetEventId()
+	ai := &persistence.ActivityInfo{
+		ScheduleID:               scheduleEventID,
+		ScheduledEvent:           scheduleEvent,
+		ScheduledTime:            time.Unix(0, event.GetTimestamp()),
+		StartedID:                emptyEventID,
+		StartedTime:              time.Time{},
+		ActivityID:               attributes.GetActivityId(),
+		ScheduleToStartTimeout:   attributes.GetScheduleToStartTimeoutSeconds(),
+		ScheduleToCloseTimeout:   attributes.GetScheduleToCloseTimeoutSeconds(),
+		StartToCloseTimeout:      attributes.GetStartToCloseTimeoutSeconds(),
+		HeartbeatTimeout:         attributes.GetHeartbeatTimeoutSeconds(),
+		CancelRequested:          false,
+		CancelRequestID:          emptyEventID,
+		LastHeartBeatUpdatedTime: time.Time{},
+		TimerTaskStatus:          TimerTaskStatusNone,
+		Priority:                 e.getActivityPriority(attributes.Priority),
+	}
+	setActivityRetryPolicy(ai, attributes.RetryPolicy)
+
+	e.pendingActivityInfoIDs[scheduleEventID] = ai
+	e.pendingActivityInfoByActivityID[ai.ActivityID] = scheduleEventID
+	e.updateActivityInfos = append(e.updateActivityInfos, ai)
+
+	return ai, nil
+}
+
+func (e *mutableStateBuilder) ReplicateActivityTaskStartedEvent(event *workflow.HistoryEvent) error {
+	attributes := event.ActivityTaskStartedEventAttributes
+	scheduleID := attributes.GetScheduledEventId()
+	ai, ok := e.GetActivityInfo(scheduleID)
+	if !ok {
+		return fmt.Errorf("Unable to find activity with schedule event id: %v in mutable state", scheduleID)
+	}
+
+	ai.StartedID = event.GetEventId()
+	ai.RequestID = attributes.GetRequestId()
+	ai.StartedTime = time.Unix(0, event.GetTimestamp())
+	ai.Attempt = attributes.GetAttempt()
+	e.updateActivityInfos = append(e.updateActivityInfos, ai)
+
+	return nil
+}
+
+func (e *mutableStateBuilder) ReplicateActivityTaskCancelRequestedEvent(event *workflow.HistoryEvent) {
+	activityID := event.ActivityTaskCancelRequestedEventAttributes.GetActivityId()
+	ai, ok := e.GetActivityByActivityID(activityID)
+	if !ok {
+		// Cancellation could be requested for an activity which already completed
+		return
+	}
+
+	ai.CancelRequested = true
+	ai.CancelRequestID = event.GetEventId()
+	e.updateActivityInfos = append(e.updateActivityInfos, ai)
+}
+
+func (e *mutableStateBuilder) ReplicateTimerStartedEvent(event *workflow.HistoryEvent) *persistence.TimerInfo {
+	attributes := event.TimerStartedEventAttributes
+	timerID := attributes.GetTimerId()
+
+	fireTimeout := time.Duration(attributes.GetStartToFireTimeoutSeconds()) * time.Second
+	// Use the timestamp of the event from source cluster to compute the expiry
+	expiryTime := time.Unix(0, event.GetTimestamp()).Add(fireTimeout)
+	ti := &persistence.TimerInfo{
+		TimerID:    timerID,
+		ExpiryTime: expiryTime,
+		StartedID:  event.GetEventId(),
+		TaskID:     TimerTaskStatusNone,
+	}
+
+	e.pendingTimerInfoIDs[timerID] = ti
+	e.updateTimerInfos = append(e.updateTimerInfos, ti)
+
+	return ti
+}
+
+func (e *mutableStateBuilder) ReplicateStartChildWorkflowExecutionInitiatedEvent(event *workflow.HistoryEvent,
+	createRequestID string) (*persistence.ChildExecutionInfo, error) {
+	initiatedEvent, err := e.eventSerializer.Serialize(event)
+	if err != nil {
+		return nil, err
+	}
+
+	initiatedEventID := event.GetEventId()
+	ci := &persistence.ChildExecutionInfo{
+		InitiatedID:     initiatedEventID,
+		InitiatedEvent:  initiatedEvent,
+		StartedID:       emptyEventID,
+		CreateRequestID: createRequestID,
+	}
+
+	e.pendingChildExecutionInfoIDs[initiatedEventID] = ci
+	e.updateChildExecutionInfos = append(e.updateChildExecutionInfos, ci)
+
+	return ci, nil
+}
+
+func (e *mutableStateBuilder) ReplicateChildWorkflowExecutionStartedEvent(event *workflow.HistoryEvent) error {
+	initiatedID := event.ChildWorkflowExecutionStartedEventAttributes.GetInitiatedEventId()
+	ci, ok := e.GetChildExecutionInfo(initiatedID)
+	if !ok {
+		return fmt.Errorf("Unable to find child execution with initiated event id: %v in mutable state", initiatedID)
+	}
+
+	startedEvent, err := e.eventSerializer.Serialize(event)
+	if err != nil {
+		return err
+	}
+
+	ci.StartedID = event.GetEventId()
+	ci.StartedEvent = startedEvent
+	e.updateChildExecutionInfos = append(e.updateChildExecutionInfos, ci)
+
+	return nil
+}
+
+func (e *mutableStateBuilder) ReplicateRequestCancelExternalWorkflowExecutionInitiatedEvent(
+	event *workflow.HistoryEvent, cancelRequestID string) *persistence.RequestCancelInfo {
+	initiatedEventID := event.GetEventId()
+	ri := &persistence.RequestCancelInfo{
+		InitiatedID:     initiatedEventID,
+		CancelRequestID: cancelRequestID,
+	}
+
+	e.pendingRequestCancelInfoIDs[initiatedEventID] = ri
+	e.updateRequestCancelInfos = append(e.updateRequestCancelInfos, ri)
+
+	return ri
+}
+
+func (e *mutableStateBuilder) ReplicateSignalExternalWorkflowExecutionInitiatedEvent(event *workflow.HistoryEvent,
+	signalRequestID string) *persistence.SignalInfo {
+	attributes := event.SignalExternalWorkflowExecutionInitiatedEventAttributes
+	initiatedEventID := event.GetEventId()
+	si := &persistence.SignalInfo{
+		InitiatedID:     initiatedEventID,
+		SignalRequestID: signalRequestID,
+		SignalName:      attributes.GetSignalName(),
+		Input:           attributes.Input,
+		Control:         attributes.Control,
+	}
+
+	e.pendingSignalInfoIDs[initiatedEventID] = si
+	e.updateSignalInfos = append(e.updateSignalInfos, si)
+
+	return si
+}
+
+func (e *mutableStateBuilder) ReplicateWorkflowExecutionCancelRequestedEvent() {
+	e.executionInfo.CancelRequested = true
+}
+
+func (e *mutableStateBuilder) ReplicateWorkflowExecutionClosedEvent(closeStatus int,
+	event *workflow.HistoryEvent) error {
+	e.executionInfo.State = persistence.WorkflowStateCompleted
+	e.executionInfo.CloseStatus = closeStatus
+
+	return e.writeCompletionEventToMutableState(event)
+}
+
+// getActivityPriority returns the dispatch priority of an activity.  Activities which do not set a priority inherit
+// the priority of the workflow execution which schedules them.
+func (e *mutableStateBuilder) getActivityPriority(priority *int32) int32 {
+	if priority == nil {
+		return e.executionInfo.Priority
+	}
+	return *priority
+}
+
+func setActivityRetryPolicy(ai *persistence.ActivityInfo, retryPolicy *workflow.RetryPolicy) {
+	if retryPolicy == nil {
+		return
+	}
+
+	ai.HasRetryPolicy = true
+	ai.InitialInterval = retryPolicy.GetInitialIntervalInSeconds()
+	ai.BackoffCoefficient = retryPolicy.GetBackoffCoefficient()
+	ai.MaximumInterval = retryPolicy.GetMaximumIntervalInSeconds()
+	ai.MaximumAttempts = retryPolicy.GetMaximumAttempts()
+	ai.NonRetriableErrors = retryPolicy.NonRetriableErrorReasons
+	if retryPolicy.GetExpirationIntervalInSeconds() > 0 {
+		ai.ExpirationTime = ai.ScheduledTime.Add(time.Duration(retryPolicy.GetExpirationIntervalInSeconds()) * time.Second)
+	}
+}
+
+func setWorkflowRetryPolicy(executionInfo *persistence.WorkflowExecutionInfo, retryPolicy *workflow.RetryPolicy,
+	expirationTime time.Time) {
+	executionInfo.HasRetryPolicy = true
+	executionInfo.InitialInterval = retryPolicy.GetInitialIntervalInSeconds()
+	executionInfo.BackoffCoefficient = retryPolicy.GetBackoffCoefficient()
+	executionInfo.MaximumInterval = retryPolicy.GetMaximumIntervalInSeconds()
+	executionInfo.MaximumAttempts = retryPolicy.GetMaximumAttempts()
+	executionInfo.NonRetriableErrors = retryPolicy.NonRetriableErrorReasons
+	executionInfo.ExpirationTime = expirationTime
+}
+
+func getSearchAttributes(indexedFields map[string][]byte) *workflow.SearchAttributes {
+	if len(indexedFields) == 0 {
+		return nil
+	}
+	return &workflow.SearchAttributes{IndexedFields: indexedFields}
+}
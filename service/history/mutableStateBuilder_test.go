@@ -23,11 +23,14 @@ package history
 import (
 	"os"
 	"testing"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/suite"
 	"github.com/uber-common/bark"
 	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/service/dynamicconfig"
 )
 
@@ -138,3 +141,205 @@ OtherEventsLoop:
 	s.Equal(len(workflow.DecisionType_Values())+1, len(decisionEvents),
 		"This assertaion will be broken a new decision is added and no corresponding logic added to shouldBufferEvent()")
 }
+
+func (s *mutableStateSuite) TestGetFailureBackoffInterval() {
+	info := s.msBuilder.executionInfo
+	now := time.Date(2018, 12, 17, 10, 30, 0, 0, time.UTC)
+
+	// no retry policy and no cron schedule
+	backoffInterval, _ := s.msBuilder.getFailureBackoffInterval("some reason", now)
+	s.Equal(backoff.NoBackoff, backoffInterval)
+
+	// cron schedule only, the next run is at the next hour
+	info.CronSchedule = "0 * * * *"
+	backoffInterval, initiator := s.msBuilder.getFailureBackoffInterval("some reason", now)
+	s.Equal(30*time.Minute, backoffInterval)
+	s.Equal(workflow.ContinueAsNewInitiatorCronSchedule, initiator)
+
+	// retry policy takes precedence over cron schedule
+	info.HasRetryPolicy = true
+	info.InitialInterval = 1
+	info.BackoffCoefficient = 2
+	info.MaximumAttempts = 3
+	info.Attempt = 1
+	info.NonRetriableErrors = []string{"bad reason"}
+	backoffInterval, initiator = s.msBuilder.getFailureBackoffInterval("some reason", now)
+	s.Equal(2*time.Second, backoffInterval)
+	s.Equal(workflow.ContinueAsNewInitiatorRetryPolicy, initiator)
+
+	// non retriable error falls back to cron schedule
+	backoffInterval, initiator = s.msBuilder.getFailureBackoffInterval("bad reason", now)
+	s.Equal(30*time.Minute, backoffInterval)
+	s.Equal(workflow.ContinueAsNewInitiatorCronSchedule, initiator)
+
+	// the retry policy expires before the next attempt, which is measured from the given time
+	info.ExpirationTime = now.Add(time.Second)
+	backoffInterval, initiator = s.msBuilder.getFailureBackoffInterval("some reason", now)
+	s.Equal(30*time.Minute, backoffInterval)
+	s.Equal(workflow.ContinueAsNewInitiatorCronSchedule, initiator)
+	backoffInterval, initiator = s.msBuilder.getFailureBackoffInterval("some reason", now.Add(-time.Second))
+	s.Equal(2*time.Second, backoffInterval)
+	s.Equal(workflow.ContinueAsNewInitiatorRetryPolicy, initiator)
+	info.ExpirationTime = time.Time{}
+
+	// no more attempts and no cron schedule
+	info.CronSchedule = ""
+	info.Attempt = 2
+	backoffInterval, _ = s.msBuilder.getFailureBackoffInterval("some reason", now)
+	s.Equal(backoff.NoBackoff, backoffInterval)
+}
+
+func (s *mutableStateSuite) TestAddWorkflowExecutionStartedEventCronAndRetryPolicy() {
+	now := time.Date(2018, 12, 17, 10, 30, 0, 0, time.UTC)
+	execution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("wId"),
+		RunId:      common.StringPtr("rId"),
+	}
+
+	event := s.msBuilder.AddWorkflowExecutionStartedEvent("domainId", execution, &workflow.StartWorkflowExecutionRequest{
+		WorkflowId:                          execution.WorkflowId,
+		WorkflowType:                        &workflow.WorkflowType{Name: common.StringPtr("wType")},
+		TaskList:                            &workflow.TaskList{Name: common.StringPtr("testTaskList")},
+		ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(100),
+		TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(10),
+		CronSchedule:                        common.StringPtr("0 * * * *"),
+		RetryPolicy: &workflow.RetryPolicy{
+			InitialIntervalInSeconds:    common.Int32Ptr(1),
+			BackoffCoefficient:          common.Float64Ptr(2),
+			ExpirationIntervalInSeconds: common.Int32Ptr(60),
+		},
+	}, 0, now)
+	s.NotNil(event)
+
+	// the first decision waits for the next hour and the retry policy expires one minute after it, both measured
+	// from the given time rather than from the wall clock
+	attributes := event.WorkflowExecutionStartedEventAttributes
+	s.Equal(int32(30*60), attributes.GetFirstDecisionTaskBackoffSeconds())
+	s.Equal(now.Add(31*time.Minute), s.msBuilder.executionInfo.ExpirationTime)
+	s.Equal(now.Add(31*time.Minute).UnixNano(), attributes.GetExpirationTimestamp())
+}
+
+func (s *mutableStateSuite) TestHasPendingFirstDecisionTaskBackoff() {
+	info := s.msBuilder.executionInfo
+	s.False(s.msBuilder.hasPendingFirstDecisionTaskBackoff())
+
+	info.HasRetryPolicy = true
+	s.False(s.msBuilder.hasPendingFirstDecisionTaskBackoff())
+
+	info.Attempt = 1
+	s.True(s.msBuilder.hasPendingFirstDecisionTaskBackoff())
+
+	info.HasRetryPolicy = false
+	info.CronSchedule = "@every 10s"
+	s.True(s.msBuilder.hasPendingFirstDecisionTaskBackoff())
+
+	info.LastProcessedEvent = 4
+	s.False(s.msBuilder.hasPendingFirstDecisionTaskBackoff())
+}
+
+func (s *mutableStateSuite) TestActivityPriority() {
+	s.msBuilder.executionInfo.Priority = 5
+
+	// activity without priority inherits the priority of the workflow execution
+	_, ai := s.msBuilder.AddActivityTaskScheduledEvent(4, &workflow.ScheduleActivityTaskDecisionAttributes{
+		ActivityId: common.StringPtr("activity-1"),
+	})
+	s.NotNil(ai)
+	s.Equal(int32(5), ai.Priority)
+
+	_, ai = s.msBuilder.AddActivityTaskScheduledEvent(4, &workflow.ScheduleActivityTaskDecisionAttributes{
+		ActivityId: common.StringPtr("activity-2"),
+		Priority:   common.Int32Ptr(-1),
+	})
+	s.NotNil(ai)
+	s.Equal(int32(-1), ai.Priority)
+}
+
+func (s *mutableStateSuite) TestPinnedBuildID() {
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("pinned-build-id-test"),
+		RunId:      common.StringPtr("6b8b4e3c-2f1a-4c5d-9e7f-0a1b2c3d4e5f"),
+	}
+	taskList := "pinned-build-id-queue"
+	identity := "pinned-build-id-test"
+	addWorkflowExecutionStartedEvent(s.msBuilder, workflowExecution, "wType", taskList, nil, 100, 10, identity)
+
+	// the workflow is not pinned until its first decision is completed
+	di := addDecisionTaskScheduledEvent(s.msBuilder)
+	startedEvent, _ := s.msBuilder.AddDecisionTaskStartedEvent(di.ScheduleID, "request-1",
+		&workflow.PollForDecisionTaskRequest{
+			TaskList: &workflow.TaskList{Name: common.StringPtr(taskList)},
+			Identity: common.StringPtr(identity),
+			BuildID:  common.StringPtr("v1"),
+		})
+	s.Equal("", s.msBuilder.getPinnedBuildID())
+	s.Equal("v1", s.msBuilder.getFirstDecisionBuildID())
+
+	addDecisionTaskCompletedEvent(s.msBuilder, di.ScheduleID, startedEvent.GetEventId(), nil, identity)
+	s.Equal("v1", s.msBuilder.getPinnedBuildID())
+	s.Equal("", s.msBuilder.getFirstDecisionBuildID())
+
+	// the later decisions do not change the build ID the workflow is pinned to
+	di = addDecisionTaskScheduledEvent(s.msBuilder)
+	s.msBuilder.AddDecisionTaskStartedEvent(di.ScheduleID, "request-2", &workflow.PollForDecisionTaskRequest{
+		TaskList: &workflow.TaskList{Name: common.StringPtr(taskList)},
+		Identity: common.StringPtr(identity),
+		BuildID:  common.StringPtr("v2"),
+	})
+	s.Equal("v1", s.msBuilder.getPinnedBuildID())
+
+	// replicated decisions pin the workflow the same way
+	replicated := newMutableStateBuilder(NewConfig(dynamicconfig.NewNopCollection(), 1), s.logger)
+	replicated.ReplicateDecisionTaskStartedEvent(2, 3, "request-1", "v1")
+	s.Equal("", replicated.getPinnedBuildID())
+	replicated.ReplicateDecisionTaskCompletedEvent(3)
+	s.Equal("v1", replicated.getPinnedBuildID())
+	replicated.ReplicateDecisionTaskStartedEvent(5, 6, "request-2", "v2")
+	s.Equal("v1", replicated.getPinnedBuildID())
+}
+
+func (s *mutableStateSuite) TestTransientActivityStartedEvent() {
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("transient-activity-started-test"),
+		RunId:      common.StringPtr("0d00698f-08e1-4d36-a3e2-3bf109f5d2d6"),
+	}
+	taskList := "transient-activity-started-queue"
+	identity := "transient-activity-started-test"
+	addWorkflowExecutionStartedEvent(s.msBuilder, workflowExecution, "wType", taskList, nil, 100, 10, identity)
+	di := addDecisionTaskScheduledEvent(s.msBuilder)
+	startedEvent := addDecisionTaskStartedEvent(s.msBuilder, di.ScheduleID, taskList, identity)
+	completedEvent := addDecisionTaskCompletedEvent(s.msBuilder, di.ScheduleID, startedEvent.GetEventId(), nil, identity)
+	scheduledEvent, ai := addActivityTaskScheduledEventWithRetry(s.msBuilder, completedEvent.GetEventId(), "activity1",
+		"activity_type1", taskList, nil, 100, 10, 0, &workflow.RetryPolicy{
+			InitialIntervalInSeconds: common.Int32Ptr(1),
+			BackoffCoefficient:       common.Float64Ptr(1),
+			MaximumAttempts:          common.Int32Ptr(3),
+		})
+	scheduleID := scheduledEvent.GetEventId()
+	ai.Attempt = 2
+
+	// the started event of an activity with retry policy is only kept in mutable state
+	activityStartedEvent := addActivityTaskStartedEvent(s.msBuilder, scheduleID, taskList, identity)
+	s.Equal(transientEventID, activityStartedEvent.GetEventId())
+	s.Equal(int32(2), activityStartedEvent.ActivityTaskStartedEventAttributes.GetAttempt())
+	s.Equal(scheduleID+1, s.msBuilder.GetNextEventID())
+	s.Equal(transientEventID, ai.StartedID)
+	s.NotNil(ai.StartedEvent)
+	startedTime := ai.StartedTime
+
+	// closing the activity writes the started event of its last attempt to history first
+	closeEvent := addActivityTaskCompletedEvent(s.msBuilder, scheduleID, transientEventID, nil, identity)
+	s.NotNil(closeEvent)
+	s.Equal(scheduleID+1, closeEvent.ActivityTaskCompletedEventAttributes.GetStartedEventId())
+	s.Equal(scheduleID+2, closeEvent.GetEventId())
+	s.Equal(scheduleID+3, s.msBuilder.GetNextEventID())
+
+	history := s.msBuilder.hBuilder.history
+	writtenStartedEvent := history[len(history)-2]
+	s.Equal(workflow.EventTypeActivityTaskStarted, writtenStartedEvent.GetEventType())
+	s.Equal(scheduleID+1, writtenStartedEvent.GetEventId())
+	s.Equal(scheduleID, writtenStartedEvent.ActivityTaskStartedEventAttributes.GetScheduledEventId())
+	s.Equal(int32(2), writtenStartedEvent.ActivityTaskStartedEventAttributes.GetAttempt())
+	s.Equal(identity, writtenStartedEvent.ActivityTaskStartedEventAttributes.GetIdentity())
+	s.Equal(startedTime.UnixNano(), writtenStartedEvent.GetTimestamp())
+}
@@ -37,14 +37,15 @@ import (
 
 type (
 	// QueueProcessorOptions is options passed to queue processor implementation
+	// the options are read at each use so they follow the changes of the dynamic config
 	QueueProcessorOptions struct {
-		BatchSize           int
-		WorkerCount         int
-		MaxPollRPS          int
-		MaxPollInterval     time.Duration
-		UpdateAckInterval   time.Duration
-		ForceUpdateInterval time.Duration
-		MaxRetryCount       int
+		BatchSize           func() int
+		WorkerCount         func() int
+		MaxPollRPS          func() int
+		MaxPollInterval     func() time.Duration
+		UpdateAckInterval   func() time.Duration
+		ForceUpdateInterval func() time.Duration
+		MaxRetryCount       func() int
 		MetricScope         int
 	}
 
@@ -55,6 +56,7 @@ type (
 		logger        bark.Logger
 		metricsClient metrics.Client
 		rateLimiter   common.TokenBucket // Read rate limiter
+		maxPollRPS    int                // Rate of the rate limiter
 		ackMgr        *ackManager
 
 		notifyCh   chan struct{}
@@ -96,11 +98,13 @@ func newQueueProcessor(shard ShardContext, options *QueueProcessorOptions, proce
 		logging.TagWorkflowComponent: processor.GetName(),
 	})
 
+	maxPollRPS := options.MaxPollRPS()
 	p := &queueProcessorBase{
 		shard:         shard,
 		options:       options,
 		processor:     processor,
-		rateLimiter:   common.NewTokenBucket(options.MaxPollRPS, common.NewRealTimeSource()),
+		rateLimiter:   common.NewTokenBucket(maxPollRPS, common.NewRealTimeSource()),
+		maxPollRPS:    maxPollRPS,
 		notifyCh:      make(chan struct{}, 1),
 		shutdownCh:    make(chan struct{}),
 		metricsClient: shard.GetMetricsClient(),
@@ -167,16 +171,15 @@ func (p *queueProcessorBase) NotifyNewTask() {
 
 func (p *queueProcessorBase) processorPump() {
 	defer p.shutdownWG.Done()
-	tasksCh := make(chan queueTaskInfo, p.options.BatchSize)
+	tasksCh := make(chan queueTaskInfo, p.options.BatchSize())
 
-	var workerWG sync.WaitGroup
-	for i := 0; i < p.options.WorkerCount; i++ {
-		workerWG.Add(1)
-		go p.taskWorker(tasksCh, &workerWG)
-	}
+	workerPool := newTaskWorkerPool(p.options.WorkerCount, func(stopCh <-chan struct{}) {
+		p.taskWorker(tasksCh, stopCh)
+	})
+	workerPool.resize()
 
-	pollTimer := time.NewTimer(p.options.MaxPollInterval)
-	updateAckTimer := time.NewTimer(p.options.UpdateAckInterval)
+	pollTimer := time.NewTimer(p.options.MaxPollInterval())
+	updateAckTimer := time.NewTimer(p.options.UpdateAckInterval())
 
 processorPumpLoop:
 	for {
@@ -187,17 +190,18 @@ processorPumpLoop:
 			p.processBatch(tasksCh)
 		case <-pollTimer.C:
 			p.processBatch(tasksCh)
-			pollTimer = time.NewTimer(p.options.MaxPollInterval)
+			pollTimer = time.NewTimer(p.options.MaxPollInterval())
 		case <-updateAckTimer.C:
 			p.ackMgr.updateAckLevel()
-			updateAckTimer = time.NewTimer(p.options.UpdateAckInterval)
+			workerPool.resize()
+			updateAckTimer = time.NewTimer(p.options.UpdateAckInterval())
 		}
 	}
 
 	p.logger.Info("Queue processor pump shutting down.")
 	// This is the only pump which writes to tasksCh, so it is safe to close channel here
 	close(tasksCh)
-	if success := common.AwaitWaitGroup(&workerWG, 10*time.Second); !success {
+	if success := workerPool.await(10 * time.Second); !success {
 		p.logger.Warn("Queue processor timed out on worker shutdown.")
 	}
 	updateAckTimer.Stop()
@@ -206,7 +210,12 @@ processorPumpLoop:
 
 func (p *queueProcessorBase) processBatch(tasksCh chan<- queueTaskInfo) {
 
-	if !p.rateLimiter.Consume(1, p.options.MaxPollInterval) {
+	if maxPollRPS := p.options.MaxPollRPS(); maxPollRPS != p.maxPollRPS {
+		p.rateLimiter = common.NewTokenBucket(maxPollRPS, common.NewRealTimeSource())
+		p.maxPollRPS = maxPollRPS
+	}
+
+	if !p.rateLimiter.Consume(1, p.options.MaxPollInterval()) {
 		p.NotifyNewTask() // re-enqueue the event
 		return
 	}
@@ -227,7 +236,7 @@ func (p *queueProcessorBase) processBatch(tasksCh chan<- queueTaskInfo) {
 		tasksCh <- tsk
 	}
 
-	if len(tasks) == p.options.BatchSize {
+	if len(tasks) == p.options.BatchSize() {
 		// There might be more task
 		// We return now to yield, but enqueue an event to poll later
 		p.NotifyNewTask()
@@ -236,11 +245,11 @@ func (p *queueProcessorBase) processBatch(tasksCh chan<- queueTaskInfo) {
 	return
 }
 
-func (p *queueProcessorBase) taskWorker(tasksCh <-chan queueTaskInfo, workerWG *sync.WaitGroup) {
-	defer workerWG.Done()
-
+func (p *queueProcessorBase) taskWorker(tasksCh <-chan queueTaskInfo, stopCh <-chan struct{}) {
 	for {
 		select {
+		case <-stopCh:
+			return
 		case task, ok := <-tasksCh:
 			if !ok {
 				return
@@ -254,7 +263,7 @@ func (p *queueProcessorBase) taskWorker(tasksCh <-chan queueTaskInfo, workerWG *
 func (p *queueProcessorBase) processWithRetry(task queueTaskInfo) {
 	p.logger.Debugf("Processing task: %v, type: %v", task.GetTaskID(), task.GetTaskType())
 ProcessRetryLoop:
-	for retryCount := 1; retryCount <= p.options.MaxRetryCount; retryCount++ {
+	for retryCount := 1; retryCount <= p.options.MaxRetryCount(); retryCount++ {
 		select {
 		case <-p.shutdownCh:
 			return
@@ -350,7 +359,7 @@ MoveAckLevelLoop:
 	a.Unlock()
 
 	// Do not update Acklevel if nothing changed upto force update interval
-	if initialAckLevel == updatedAckLevel && time.Since(a.lastUpdated) < a.options.ForceUpdateInterval {
+	if initialAckLevel == updatedAckLevel && time.Since(a.lastUpdated) < a.options.ForceUpdateInterval() {
 		return
 	}
 
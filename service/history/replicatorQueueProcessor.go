@@ -22,6 +22,7 @@ package history
 
 import (
 	"errors"
+	"time"
 
 	"github.com/uber/cadence/.gen/go/replicator"
 	"github.com/uber/cadence/.gen/go/shared"
@@ -30,6 +31,7 @@ import (
 	"github.com/uber/cadence/common/messaging"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/service/dynamicconfig"
 )
 
 type (
@@ -53,14 +55,15 @@ func newReplicatorQueueProcessor(shard ShardContext, replicator messaging.Produc
 	hSerializerFactory persistence.HistorySerializerFactory) queueProcessor {
 
 	config := shard.GetConfig()
+	shardFilter := dynamicconfig.ShardIDFilter(shard.GetShardID())
 	options := &QueueProcessorOptions{
-		BatchSize:           config.ReplicatorTaskBatchSize,
-		WorkerCount:         config.ReplicatorTaskWorkerCount,
-		MaxPollRPS:          config.ReplicatorProcessorMaxPollRPS,
-		MaxPollInterval:     config.ReplicatorProcessorMaxPollInterval,
-		UpdateAckInterval:   config.ReplicatorProcessorUpdateAckInterval,
-		ForceUpdateInterval: config.ReplicatorProcessorForceUpdateInterval,
-		MaxRetryCount:       config.ReplicatorTaskMaxRetryCount,
+		BatchSize:           func() int { return config.ReplicatorTaskBatchSize(shardFilter) },
+		WorkerCount:         func() int { return config.ReplicatorTaskWorkerCount(shardFilter) },
+		MaxPollRPS:          func() int { return config.ReplicatorProcessorMaxPollRPS(shardFilter) },
+		MaxPollInterval:     func() time.Duration { return config.ReplicatorProcessorMaxPollInterval(shardFilter) },
+		UpdateAckInterval:   func() time.Duration { return config.ReplicatorProcessorUpdateAckInterval(shardFilter) },
+		ForceUpdateInterval: func() time.Duration { return config.ReplicatorProcessorForceUpdateInterval(shardFilter) },
+		MaxRetryCount:       func() int { return config.ReplicatorTaskMaxRetryCount(shardFilter) },
 		MetricScope:         metrics.ReplicatorQueueProcessorScope,
 	}
 
@@ -133,7 +136,7 @@ func (p *replicatorQueueProcessorImpl) ReadTasks(readLevel int64) ([]queueTaskIn
 	response, err := p.executionMgr.GetReplicationTasks(&persistence.GetReplicationTasksRequest{
 		ReadLevel:    readLevel,
 		MaxReadLevel: p.shard.GetTransferMaxReadLevel(),
-		BatchSize:    p.options.BatchSize,
+		BatchSize:    p.options.BatchSize(),
 	})
 
 	if err != nil {
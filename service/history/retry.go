@@ -0,0 +1,70 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"math"
+	"time"
+
+	workflow "github.com/uber/cadence/.gen/go/shared"
+)
+
+const (
+	// noRetryBackoff is returned by getBackoffInterval when no more retries should be attempted
+	noRetryBackoff = time.Duration(-1)
+	// defaultMaximumIntervalCoefficient caps the retry interval when maximum interval is not set on retry policy
+	defaultMaximumIntervalCoefficient = 100
+	// timeoutErrorReasonPrefix is used as failure reason for retry policy when an activity attempt times out
+	timeoutErrorReasonPrefix = "cadenceInternal:Timeout "
+)
+
+// getBackoffInterval calculates the backoff before the next attempt of an activity.  The attempt is 0 based, so an
+// activity with maxAttempts of 1 is never retried.  It returns noRetryBackoff if the activity should not be retried.
+func getBackoffInterval(currAttempt, maxAttempts, initInterval, maxInterval int32, backoffCoefficient float64,
+	now, expirationTime time.Time, failureReason string, nonRetriableErrors []string) time.Duration {
+	if maxAttempts > 0 && currAttempt+1 >= maxAttempts {
+		return noRetryBackoff
+	}
+
+	for _, reason := range nonRetriableErrors {
+		if reason == failureReason {
+			return noRetryBackoff
+		}
+	}
+
+	maxIntervalSeconds := float64(maxInterval)
+	if maxIntervalSeconds <= 0 {
+		maxIntervalSeconds = float64(initInterval) * defaultMaximumIntervalCoefficient
+	}
+
+	nextIntervalSeconds := float64(initInterval) * math.Pow(backoffCoefficient, float64(currAttempt))
+	nextIntervalSeconds = math.Min(nextIntervalSeconds, maxIntervalSeconds)
+	nextInterval := time.Duration(nextIntervalSeconds) * time.Second
+	if !expirationTime.IsZero() && now.Add(nextInterval).After(expirationTime) {
+		return noRetryBackoff
+	}
+
+	return nextInterval
+}
+
+func getTimeoutErrorReason(timeoutType workflow.TimeoutType) string {
+	return timeoutErrorReasonPrefix + timeoutType.String()
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	retrySuite struct {
+		suite.Suite
+	}
+)
+
+func TestRetrySuite(t *testing.T) {
+	s := new(retrySuite)
+	suite.Run(t, s)
+}
+
+func (s *retrySuite) TestGetBackoffInterval_Exponential() {
+	now := time.Now()
+	s.Equal(time.Second, getBackoffInterval(0, 0, 1, 0, 2, now, now.Add(time.Hour), "reason", nil))
+	s.Equal(2*time.Second, getBackoffInterval(1, 0, 1, 0, 2, now, now.Add(time.Hour), "reason", nil))
+	s.Equal(4*time.Second, getBackoffInterval(2, 0, 1, 0, 2, now, now.Add(time.Hour), "reason", nil))
+}
+
+func (s *retrySuite) TestGetBackoffInterval_MaximumInterval() {
+	now := time.Now()
+	s.Equal(5*time.Second, getBackoffInterval(10, 0, 1, 5, 2, now, now.Add(time.Hour), "reason", nil))
+	// without maximum interval the backoff is capped at 100 times the initial interval
+	s.Equal(100*time.Second, getBackoffInterval(10, 0, 1, 0, 2, now, now.Add(time.Hour), "reason", nil))
+}
+
+func (s *retrySuite) TestGetBackoffInterval_MaximumAttempts() {
+	now := time.Now()
+	s.Equal(noRetryBackoff, getBackoffInterval(0, 1, 1, 0, 2, now, time.Time{}, "reason", nil))
+	s.Equal(2*time.Second, getBackoffInterval(1, 3, 1, 0, 2, now, time.Time{}, "reason", nil))
+	s.Equal(noRetryBackoff, getBackoffInterval(2, 3, 1, 0, 2, now, time.Time{}, "reason", nil))
+}
+
+func (s *retrySuite) TestGetBackoffInterval_NonRetriableError() {
+	now := time.Now()
+	nonRetriable := []string{"bad request", getTimeoutErrorReason(0)}
+	s.Equal(noRetryBackoff, getBackoffInterval(0, 0, 1, 0, 2, now, now.Add(time.Hour), "bad request", nonRetriable))
+	s.Equal(noRetryBackoff, getBackoffInterval(0, 0, 1, 0, 2, now, now.Add(time.Hour), getTimeoutErrorReason(0), nonRetriable))
+	s.Equal(time.Second, getBackoffInterval(0, 0, 1, 0, 2, now, now.Add(time.Hour), "other", nonRetriable))
+}
+
+func (s *retrySuite) TestGetBackoffInterval_Expiration() {
+	now := time.Now()
+	s.Equal(noRetryBackoff, getBackoffInterval(3, 0, 1, 0, 2, now, now.Add(5*time.Second), "reason", nil))
+	s.Equal(4*time.Second, getBackoffInterval(2, 0, 1, 0, 2, now, now.Add(5*time.Second), "reason", nil))
+}
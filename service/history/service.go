@@ -26,6 +26,7 @@ import (
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/searchattribute"
 	"github.com/uber/cadence/common/service"
 	"github.com/uber/cadence/common/service/dynamicconfig"
 )
@@ -35,92 +36,110 @@ type Config struct {
 	NumberOfShards int
 
 	// HistoryCache settings
-	HistoryCacheInitialSize int
-	HistoryCacheMaxSize     int
-	HistoryCacheTTL         time.Duration
+	// Change of the initial size and the TTL requires shard restart, the max size is read on every insertion
+	HistoryCacheInitialSize dynamicconfig.IntPropertyFn
+	HistoryCacheMaxSize     dynamicconfig.IntPropertyFn
+	HistoryCacheTTL         dynamicconfig.DurationPropertyFn
 
 	// ShardController settings
 	RangeSizeBits        uint
-	AcquireShardInterval time.Duration
+	AcquireShardInterval dynamicconfig.DurationPropertyFn
 
 	// Timeout settings
-	DefaultScheduleToStartActivityTimeoutInSecs int32
-	DefaultScheduleToCloseActivityTimeoutInSecs int32
-	DefaultStartToCloseActivityTimeoutInSecs    int32
+	DefaultScheduleToStartActivityTimeout dynamicconfig.DurationPropertyFn
+	DefaultScheduleToCloseActivityTimeout dynamicconfig.DurationPropertyFn
+	DefaultStartToCloseActivityTimeout    dynamicconfig.DurationPropertyFn
 
 	// TimerQueueProcessor settings
-	TimerTaskBatchSize                    int
-	ProcessTimerTaskWorkerCount           int
-	TimerProcessorUpdateFailureRetryCount int
-	TimerProcessorGetFailureRetryCount    int
-	TimerProcessorUpdateAckInterval       time.Duration
-	TimerProcessorForceUpdateInterval     time.Duration
+	TimerTaskBatchSize                    dynamicconfig.IntPropertyFn
+	ProcessTimerTaskWorkerCount           dynamicconfig.IntPropertyFn
+	TimerProcessorUpdateFailureRetryCount dynamicconfig.IntPropertyFn
+	TimerProcessorGetFailureRetryCount    dynamicconfig.IntPropertyFn
+	TimerProcessorUpdateAckInterval       dynamicconfig.DurationPropertyFn
+	TimerProcessorForceUpdateInterval     dynamicconfig.DurationPropertyFn
 
 	// TransferQueueProcessor settings
-	TransferTaskBatchSize                int
-	TransferProcessorMaxPollRPS          int
-	TransferProcessorMaxPollInterval     time.Duration
-	TransferProcessorUpdateAckInterval   time.Duration
-	TransferProcessorForceUpdateInterval time.Duration
-	TransferTaskWorkerCount              int
-	TransferTaskMaxRetryCount            int
+	TransferTaskBatchSize                dynamicconfig.IntPropertyFn
+	TransferProcessorMaxPollRPS          dynamicconfig.IntPropertyFn
+	TransferProcessorMaxPollInterval     dynamicconfig.DurationPropertyFn
+	TransferProcessorUpdateAckInterval   dynamicconfig.DurationPropertyFn
+	TransferProcessorForceUpdateInterval dynamicconfig.DurationPropertyFn
+	TransferTaskWorkerCount              dynamicconfig.IntPropertyFn
+	TransferTaskMaxRetryCount            dynamicconfig.IntPropertyFn
 
 	// ReplicatorQueueProcessor settings
-	ReplicatorTaskBatchSize                int
-	ReplicatorProcessorMaxPollRPS          int
-	ReplicatorProcessorMaxPollInterval     time.Duration
-	ReplicatorProcessorUpdateAckInterval   time.Duration
-	ReplicatorProcessorForceUpdateInterval time.Duration
-	ReplicatorTaskWorkerCount              int
-	ReplicatorTaskMaxRetryCount            int
+	ReplicatorTaskBatchSize                dynamicconfig.IntPropertyFn
+	ReplicatorProcessorMaxPollRPS          dynamicconfig.IntPropertyFn
+	ReplicatorProcessorMaxPollInterval     dynamicconfig.DurationPropertyFn
+	ReplicatorProcessorUpdateAckInterval   dynamicconfig.DurationPropertyFn
+	ReplicatorProcessorForceUpdateInterval dynamicconfig.DurationPropertyFn
+	ReplicatorTaskWorkerCount              dynamicconfig.IntPropertyFn
+	ReplicatorTaskMaxRetryCount            dynamicconfig.IntPropertyFn
 
 	// Persistence settings
-	ExecutionMgrNumConns int
-	HistoryMgrNumConns   int
+	// Change of these configs require service restart
+	ExecutionMgrNumConns dynamicconfig.IntPropertyFn
+	HistoryMgrNumConns   dynamicconfig.IntPropertyFn
+
+	// ArchivalHistoryPageSize is the number of history batches archived in each blob
+	ArchivalHistoryPageSize dynamicconfig.IntPropertyFn
+	// ArchivalTimeout bounds how long the history deletion timer waits on the archival of a history
+	ArchivalTimeout dynamicconfig.DurationPropertyFn
 
 	// Time to hold a poll request before returning an empty response
 	// right now only used by GetMutableState
 	LongPollExpirationInterval dynamicconfig.DurationPropertyFn
+
+	// EventEncodingType is the encoding type used when persisting new history events
+	EventEncodingType dynamicconfig.StringPropertyFn
+
+	// ValidSearchAttributes is the map of search attribute names which decisions can upsert to their value types
+	ValidSearchAttributes dynamicconfig.MapPropertyFn
 }
 
 // NewConfig returns new service config with default values
 func NewConfig(dc *dynamicconfig.Collection, numberOfShards int) *Config {
 	return &Config{
-		NumberOfShards:                              numberOfShards,
-		HistoryCacheInitialSize:                     128,
-		HistoryCacheMaxSize:                         512,
-		HistoryCacheTTL:                             time.Hour,
-		RangeSizeBits:                               20, // 20 bits for sequencer, 2^20 sequence number for any range
-		AcquireShardInterval:                        time.Minute,
-		DefaultScheduleToStartActivityTimeoutInSecs: 10,
-		DefaultScheduleToCloseActivityTimeoutInSecs: 10,
-		DefaultStartToCloseActivityTimeoutInSecs:    10,
-		TimerTaskBatchSize:                          100,
-		ProcessTimerTaskWorkerCount:                 30,
-		TimerProcessorUpdateFailureRetryCount:       5,
-		TimerProcessorGetFailureRetryCount:          5,
-		TimerProcessorUpdateAckInterval:             10 * time.Second,
-		TimerProcessorForceUpdateInterval:           10 * time.Minute,
-		TransferTaskBatchSize:                       10,
-		TransferProcessorMaxPollRPS:                 100,
-		TransferProcessorMaxPollInterval:            60 * time.Second,
-		TransferProcessorUpdateAckInterval:          10 * time.Second,
-		TransferProcessorForceUpdateInterval:        10 * time.Minute,
-		TransferTaskWorkerCount:                     10,
-		TransferTaskMaxRetryCount:                   100,
-		ReplicatorTaskBatchSize:                     10,
-		ReplicatorProcessorMaxPollRPS:               100,
-		ReplicatorProcessorMaxPollInterval:          60 * time.Second,
-		ReplicatorProcessorUpdateAckInterval:        10 * time.Second,
-		ReplicatorProcessorForceUpdateInterval:      10 * time.Minute,
-		ReplicatorTaskWorkerCount:                   10,
-		ReplicatorTaskMaxRetryCount:                 100,
-		ExecutionMgrNumConns:                        100,
-		HistoryMgrNumConns:                          100,
+		NumberOfShards:                         numberOfShards,
+		HistoryCacheInitialSize:                dc.GetIntProperty(dynamicconfig.HistoryCacheInitialSize, 128),
+		HistoryCacheMaxSize:                    dc.GetIntProperty(dynamicconfig.HistoryCacheMaxSize, 512),
+		HistoryCacheTTL:                        dc.GetDurationProperty(dynamicconfig.HistoryCacheTTL, time.Hour),
+		RangeSizeBits:                          20, // 20 bits for sequencer, 2^20 sequence number for any range
+		AcquireShardInterval:                   dc.GetDurationProperty(dynamicconfig.AcquireShardInterval, time.Minute),
+		DefaultScheduleToStartActivityTimeout:  dc.GetDurationProperty(dynamicconfig.DefaultScheduleToStartActivityTimeout, 10*time.Second),
+		DefaultScheduleToCloseActivityTimeout:  dc.GetDurationProperty(dynamicconfig.DefaultScheduleToCloseActivityTimeout, 10*time.Second),
+		DefaultStartToCloseActivityTimeout:     dc.GetDurationProperty(dynamicconfig.DefaultStartToCloseActivityTimeout, 10*time.Second),
+		TimerTaskBatchSize:                     dc.GetIntProperty(dynamicconfig.TimerTaskBatchSize, 100),
+		ProcessTimerTaskWorkerCount:            dc.GetIntProperty(dynamicconfig.TimerTaskWorkerCount, 30),
+		TimerProcessorUpdateFailureRetryCount:  dc.GetIntProperty(dynamicconfig.TimerProcessorUpdateFailureRetryCount, 5),
+		TimerProcessorGetFailureRetryCount:     dc.GetIntProperty(dynamicconfig.TimerProcessorGetFailureRetryCount, 5),
+		TimerProcessorUpdateAckInterval:        dc.GetDurationProperty(dynamicconfig.TimerProcessorUpdateAckInterval, 10*time.Second),
+		TimerProcessorForceUpdateInterval:      dc.GetDurationProperty(dynamicconfig.TimerProcessorForceUpdateInterval, 10*time.Minute),
+		TransferTaskBatchSize:                  dc.GetIntProperty(dynamicconfig.TransferTaskBatchSize, 10),
+		TransferProcessorMaxPollRPS:            dc.GetIntProperty(dynamicconfig.TransferProcessorMaxPollRPS, 100),
+		TransferProcessorMaxPollInterval:       dc.GetDurationProperty(dynamicconfig.TransferProcessorMaxPollInterval, 60*time.Second),
+		TransferProcessorUpdateAckInterval:     dc.GetDurationProperty(dynamicconfig.TransferProcessorUpdateAckInterval, 10*time.Second),
+		TransferProcessorForceUpdateInterval:   dc.GetDurationProperty(dynamicconfig.TransferProcessorForceUpdateInterval, 10*time.Minute),
+		TransferTaskWorkerCount:                dc.GetIntProperty(dynamicconfig.TransferTaskWorkerCount, 10),
+		TransferTaskMaxRetryCount:              dc.GetIntProperty(dynamicconfig.TransferTaskMaxRetryCount, 100),
+		ReplicatorTaskBatchSize:                dc.GetIntProperty(dynamicconfig.ReplicatorTaskBatchSize, 10),
+		ReplicatorProcessorMaxPollRPS:          dc.GetIntProperty(dynamicconfig.ReplicatorProcessorMaxPollRPS, 100),
+		ReplicatorProcessorMaxPollInterval:     dc.GetDurationProperty(dynamicconfig.ReplicatorProcessorMaxPollInterval, 60*time.Second),
+		ReplicatorProcessorUpdateAckInterval:   dc.GetDurationProperty(dynamicconfig.ReplicatorProcessorUpdateAckInterval, 10*time.Second),
+		ReplicatorProcessorForceUpdateInterval: dc.GetDurationProperty(dynamicconfig.ReplicatorProcessorForceUpdateInterval, 10*time.Minute),
+		ReplicatorTaskWorkerCount:              dc.GetIntProperty(dynamicconfig.ReplicatorTaskWorkerCount, 10),
+		ReplicatorTaskMaxRetryCount:            dc.GetIntProperty(dynamicconfig.ReplicatorTaskMaxRetryCount, 100),
+		ExecutionMgrNumConns:                   dc.GetIntProperty(dynamicconfig.ExecutionMgrNumConns, 100),
+		HistoryMgrNumConns:                     dc.GetIntProperty(dynamicconfig.HistoryMgrNumConns, 100),
+		ArchivalHistoryPageSize:                dc.GetIntProperty(dynamicconfig.ArchivalHistoryPageSize, 100),
+		ArchivalTimeout:                        dc.GetDurationProperty(dynamicconfig.ArchivalTimeout, time.Minute),
 		// history client: client/history/client.go set the client timeout 30s
 		LongPollExpirationInterval: dc.GetDurationProperty(
 			dynamicconfig.HistoryLongPollExpirationInterval, time.Second*20,
 		),
+		EventEncodingType: dc.GetStringProperty(dynamicconfig.HistoryEventEncodingType, string(common.EncodingTypeJSON)),
+		ValidSearchAttributes: dc.GetMapProperty(dynamicconfig.ValidSearchAttributes,
+			searchattribute.GetDefaultValidSearchAttributes()),
 	}
 }
 
@@ -161,14 +180,31 @@ func (s *Service) Start() {
 
 	s.metricsClient = base.GetMetricsClient()
 
-	shardMgr, err := persistence.NewCassandraShardPersistence(p.CassandraConfig.Hosts,
-		p.CassandraConfig.Port,
-		p.CassandraConfig.User,
-		p.CassandraConfig.Password,
-		p.CassandraConfig.Datacenter,
-		p.CassandraConfig.Keyspace,
-		p.ClusterMetadata.GetCurrentClusterName(),
-		p.Logger)
+	var shardMgr persistence.ShardManager
+	var err error
+	if p.InMemoryStore != nil {
+		shardMgr, err = persistence.NewInMemoryShardPersistence(p.InMemoryStore, p.ClusterMetadata.GetCurrentClusterName(), p.Logger)
+	} else if p.SQLConfig != nil {
+		shardMgr, err = persistence.NewSQLShardPersistence(p.SQLConfig.DriverName,
+			p.SQLConfig.Host,
+			p.SQLConfig.Port,
+			p.SQLConfig.User,
+			p.SQLConfig.Password,
+			p.SQLConfig.DatabaseName,
+			p.SQLConfig.MaxConns,
+			p.ClusterMetadata.GetCurrentClusterName(),
+			p.Logger,
+		)
+	} else {
+		shardMgr, err = persistence.NewCassandraShardPersistence(p.CassandraConfig.Hosts,
+			p.CassandraConfig.Port,
+			p.CassandraConfig.User,
+			p.CassandraConfig.Password,
+			p.CassandraConfig.Datacenter,
+			p.CassandraConfig.Keyspace,
+			p.ClusterMetadata.GetCurrentClusterName(),
+			p.Logger)
+	}
 
 	if err != nil {
 		log.Fatalf("failed to create shard manager: %v", err)
@@ -192,57 +228,121 @@ func (s *Service) Start() {
 		}
 	}
 
-	metadata, err := persistence.NewCassandraMetadataPersistence(p.CassandraConfig.Hosts,
-		p.CassandraConfig.Port,
-		p.CassandraConfig.User,
-		p.CassandraConfig.Password,
-		p.CassandraConfig.Datacenter,
-		p.CassandraConfig.Keyspace,
-		p.ClusterMetadata.GetCurrentClusterName(),
-		p.Logger)
+	var metadata persistence.MetadataManager
+	if p.InMemoryStore != nil {
+		metadata, err = persistence.NewInMemoryMetadataPersistence(p.InMemoryStore, p.ClusterMetadata.GetCurrentClusterName(), p.Logger)
+	} else if p.SQLConfig != nil {
+		metadata, err = persistence.NewSQLMetadataPersistence(p.SQLConfig.DriverName,
+			p.SQLConfig.Host,
+			p.SQLConfig.Port,
+			p.SQLConfig.User,
+			p.SQLConfig.Password,
+			p.SQLConfig.DatabaseName,
+			p.SQLConfig.MaxConns,
+			p.ClusterMetadata.GetCurrentClusterName(),
+			p.Logger,
+		)
+	} else {
+		metadata, err = persistence.NewCassandraMetadataPersistence(p.CassandraConfig.Hosts,
+			p.CassandraConfig.Port,
+			p.CassandraConfig.User,
+			p.CassandraConfig.Password,
+			p.CassandraConfig.Datacenter,
+			p.CassandraConfig.Keyspace,
+			p.ClusterMetadata.GetCurrentClusterName(),
+			p.Logger)
+	}
 
 	if err != nil {
 		log.Fatalf("failed to create metadata manager: %v", err)
 	}
 	metadata = persistence.NewMetadataPersistenceClient(metadata, base.GetMetricsClient())
 
-	visibility, err := persistence.NewCassandraVisibilityPersistence(p.CassandraConfig.Hosts,
-		p.CassandraConfig.Port,
-		p.CassandraConfig.User,
-		p.CassandraConfig.Password,
-		p.CassandraConfig.Datacenter,
-		p.CassandraConfig.VisibilityKeyspace,
-		p.Logger)
+	var visibility persistence.VisibilityManager
+	if p.BoltVisibilityStore != nil {
+		visibility, err = persistence.NewBoltVisibilityPersistence(p.BoltVisibilityStore, p.Logger)
+	} else if p.InMemoryStore != nil {
+		visibility, err = persistence.NewInMemoryVisibilityPersistence(p.InMemoryStore, p.Logger)
+	} else if p.SQLConfig != nil {
+		visibility, err = persistence.NewSQLVisibilityPersistence(p.SQLConfig.DriverName,
+			p.SQLConfig.Host,
+			p.SQLConfig.Port,
+			p.SQLConfig.User,
+			p.SQLConfig.Password,
+			p.SQLConfig.VisibilityDatabaseName,
+			p.SQLConfig.MaxConns,
+			p.Logger,
+		)
+	} else {
+		visibility, err = persistence.NewCassandraVisibilityPersistence(p.CassandraConfig.Hosts,
+			p.CassandraConfig.Port,
+			p.CassandraConfig.User,
+			p.CassandraConfig.Password,
+			p.CassandraConfig.Datacenter,
+			p.CassandraConfig.VisibilityKeyspace,
+			p.Logger)
+	}
 
 	if err != nil {
 		log.Fatalf("failed to create visiblity manager: %v", err)
 	}
 	visibility = persistence.NewVisibilityPersistenceClient(visibility, base.GetMetricsClient())
 
-	history, err := persistence.NewCassandraHistoryPersistence(p.CassandraConfig.Hosts,
-		p.CassandraConfig.Port,
-		p.CassandraConfig.User,
-		p.CassandraConfig.Password,
-		p.CassandraConfig.Datacenter,
-		p.CassandraConfig.Keyspace,
-		s.config.HistoryMgrNumConns,
-		p.Logger)
+	var history persistence.HistoryManager
+	if p.InMemoryStore != nil {
+		history, err = persistence.NewInMemoryHistoryPersistence(p.InMemoryStore, p.Logger)
+	} else if p.SQLConfig != nil {
+		history, err = persistence.NewSQLHistoryPersistence(p.SQLConfig.DriverName,
+			p.SQLConfig.Host,
+			p.SQLConfig.Port,
+			p.SQLConfig.User,
+			p.SQLConfig.Password,
+			p.SQLConfig.DatabaseName,
+			p.SQLConfig.MaxConns,
+			p.Logger,
+		)
+	} else {
+		history, err = persistence.NewCassandraHistoryPersistence(p.CassandraConfig.Hosts,
+			p.CassandraConfig.Port,
+			p.CassandraConfig.User,
+			p.CassandraConfig.Password,
+			p.CassandraConfig.Datacenter,
+			p.CassandraConfig.Keyspace,
+			s.config.HistoryMgrNumConns(),
+			p.Logger)
+	}
 
 	if err != nil {
 		log.Fatalf("Creating Cassandra history manager persistence failed: %v", err)
 	}
 	history = persistence.NewHistoryPersistenceClient(history, base.GetMetricsClient())
 
-	execMgrFactory, err := persistence.NewCassandraPersistenceClientFactory(p.CassandraConfig.Hosts,
-		p.CassandraConfig.Port,
-		p.CassandraConfig.User,
-		p.CassandraConfig.Password,
-		p.CassandraConfig.Datacenter,
-		p.CassandraConfig.Keyspace,
-		s.config.ExecutionMgrNumConns,
-		p.Logger,
-		s.metricsClient,
-	)
+	var execMgrFactory persistence.ExecutionManagerFactory
+	if p.InMemoryStore != nil {
+		execMgrFactory, err = persistence.NewInMemoryPersistenceClientFactory(p.InMemoryStore, p.Logger, s.metricsClient)
+	} else if p.SQLConfig != nil {
+		execMgrFactory, err = persistence.NewSQLPersistenceClientFactory(p.SQLConfig.DriverName,
+			p.SQLConfig.Host,
+			p.SQLConfig.Port,
+			p.SQLConfig.User,
+			p.SQLConfig.Password,
+			p.SQLConfig.DatabaseName,
+			p.SQLConfig.MaxConns,
+			p.Logger,
+			s.metricsClient,
+		)
+	} else {
+		execMgrFactory, err = persistence.NewCassandraPersistenceClientFactory(p.CassandraConfig.Hosts,
+			p.CassandraConfig.Port,
+			p.CassandraConfig.User,
+			p.CassandraConfig.Password,
+			p.CassandraConfig.Datacenter,
+			p.CassandraConfig.Keyspace,
+			s.config.ExecutionMgrNumConns(),
+			p.Logger,
+			s.metricsClient,
+		)
+	}
 	if err != nil {
 		log.Fatalf("Creating Cassandra execution manager persistence factory failed: %v", err)
 	}
@@ -253,7 +353,8 @@ func (s *Service) Start() {
 		metadata,
 		visibility,
 		history,
-		execMgrFactory)
+		execMgrFactory,
+		p.Archiver)
 
 	handler.Start()
 
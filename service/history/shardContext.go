@@ -41,6 +41,7 @@ import (
 type (
 	// ShardContext represents a history engine shard
 	ShardContext interface {
+		GetShardID() int
 		GetService() service.Service
 		GetExecutionManager() persistence.ExecutionManager
 		GetHistoryManager() persistence.HistoryManager
@@ -88,6 +89,10 @@ type (
 
 var _ ShardContext = (*shardContextImpl)(nil)
 
+func (s *shardContextImpl) GetShardID() int {
+	return s.shardID
+}
+
 func (s *shardContextImpl) GetService() service.Service {
 	return s.service
 }
@@ -314,6 +319,9 @@ func (s *shardContextImpl) UpdateWorkflowExecution(request *persistence.UpdateWo
 	defer s.updateMaxReadLevelLocked(transferMaxReadLevel)
 
 	s.allocateTimerIDsLocked(request.TimerTasks)
+	if request.ContinueAsNew != nil {
+		s.allocateTimerIDsLocked(request.ContinueAsNew.TimerTasks)
+	}
 
 Update_Loop:
 	for attempt := 0; attempt < conditionalRetryCount; attempt++ {
@@ -266,8 +266,8 @@ func (c *shardController) shardManagementPump() {
 
 	defer c.shutdownWG.Done()
 
-	acquireTicker := time.NewTicker(c.config.AcquireShardInterval)
-	defer acquireTicker.Stop()
+	acquireTimer := time.NewTimer(c.config.AcquireShardInterval())
+	defer acquireTimer.Stop()
 
 	for {
 
@@ -275,8 +275,9 @@ func (c *shardController) shardManagementPump() {
 		case <-c.shutdownCh:
 			c.doShutdown()
 			return
-		case <-acquireTicker.C:
+		case <-acquireTimer.C:
 			c.acquireShards()
+			acquireTimer.Reset(c.config.AcquireShardInterval())
 		case changedEvent := <-c.membershipUpdateCh:
 			c.metricsClient.IncCounter(metrics.HistoryShardControllerScope, metrics.MembershipChangedCounter)
 			logging.LogRingMembershipChangedEvent(c.logger, c.host.Identity(), len(changedEvent.HostsAdded),
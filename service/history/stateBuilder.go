@@ -0,0 +1,221 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"fmt"
+
+	"github.com/pborman/uuid"
+	"github.com/uber-common/bark"
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common/persistence"
+)
+
+type (
+	// stateBuilder applies history events generated by a remote cluster to the mutable state of the local copy of
+	// a workflow execution.  Unlike mutableStateBuilder.AddXXX methods, events are taken as is and no new events
+	// are generated.
+	stateBuilder struct {
+		msBuilder *mutableStateBuilder
+		logger    bark.Logger
+	}
+)
+
+func newStateBuilder(msBuilder *mutableStateBuilder, logger bark.Logger) *stateBuilder {
+	return &stateBuilder{
+		msBuilder: msBuilder,
+		logger:    logger,
+	}
+}
+
+// applyEvents updates the mutable state for each of the replicated events and appends them to the history builder
+// of the mutable state, so they get persisted as part of the next update.  It returns the last applied event.
+func (b *stateBuilder) applyEvents(domainID, requestID string, execution workflow.WorkflowExecution,
+	history []*workflow.HistoryEvent) (*workflow.HistoryEvent, error) {
+	var lastEvent *workflow.HistoryEvent
+	for _, event := range history {
+		if lastEvent != nil && event.GetEventId() != lastEvent.GetEventId()+1 {
+			return nil, fmt.Errorf("Replicated history events are not contiguous. Expected EventID: %v, Actual: %v",
+				lastEvent.GetEventId()+1, event.GetEventId())
+		}
+		lastEvent = event
+
+		if err := b.applyEvent(domainID, requestID, execution, event); err != nil {
+			return nil, err
+		}
+
+		b.msBuilder.hBuilder.history = append(b.msBuilder.hBuilder.history, event)
+		b.msBuilder.executionInfo.NextEventID = event.GetEventId() + 1
+	}
+
+	return lastEvent, nil
+}
+
+func (b *stateBuilder) applyEvent(domainID, requestID string, execution workflow.WorkflowExecution,
+	event *workflow.HistoryEvent) error {
+	switch event.GetEventType() {
+	case workflow.EventTypeWorkflowExecutionStarted:
+		b.msBuilder.ReplicateWorkflowExecutionStartedEvent(domainID, requestID, execution, event)
+
+	case workflow.EventTypeDecisionTaskScheduled:
+		attributes := event.DecisionTaskScheduledEventAttributes
+		b.msBuilder.ReplicateDecisionTaskScheduledEvent(event.GetEventId(), attributes.TaskList.GetName(),
+			attributes.GetStartToCloseTimeoutSeconds(), attributes.GetAttempt())
+
+	case workflow.EventTypeDecisionTaskStarted:
+		attributes := event.DecisionTaskStartedEventAttributes
+		b.msBuilder.ReplicateDecisionTaskStartedEvent(attributes.GetScheduledEventId(), event.GetEventId(),
+			attributes.GetRequestId(), attributes.GetBuildID())
+
+	case workflow.EventTypeDecisionTaskCompleted:
+		b.msBuilder.ReplicateDecisionTaskCompletedEvent(
+			event.DecisionTaskCompletedEventAttributes.GetStartedEventId())
+
+	case workflow.EventTypeDecisionTaskTimedOut, workflow.EventTypeDecisionTaskFailed:
+		b.msBuilder.FailDecision()
+
+	case workflow.EventTypeActivityTaskScheduled:
+		if _, err := b.msBuilder.ReplicateActivityTaskScheduledEvent(event); err != nil {
+			return err
+		}
+
+	case workflow.EventTypeActivityTaskStarted:
+		return b.msBuilder.ReplicateActivityTaskStartedEvent(event)
+
+	case workflow.EventTypeActivityTaskCompleted:
+		return b.msBuilder.DeleteActivity(event.ActivityTaskCompletedEventAttributes.GetScheduledEventId())
+
+	case workflow.EventTypeActivityTaskFailed:
+		return b.msBuilder.DeleteActivity(event.ActivityTaskFailedEventAttributes.GetScheduledEventId())
+
+	case workflow.EventTypeActivityTaskTimedOut:
+		return b.msBuilder.DeleteActivity(event.ActivityTaskTimedOutEventAttributes.GetScheduledEventId())
+
+	case workflow.EventTypeActivityTaskCancelRequested:
+		b.msBuilder.ReplicateActivityTaskCancelRequestedEvent(event)
+
+	case workflow.EventTypeActivityTaskCanceled:
+		return b.msBuilder.DeleteActivity(event.ActivityTaskCanceledEventAttributes.GetScheduledEventId())
+
+	case workflow.EventTypeTimerStarted:
+		b.msBuilder.ReplicateTimerStartedEvent(event)
+
+	case workflow.EventTypeTimerFired:
+		return b.msBuilder.DeleteUserTimer(event.TimerFiredEventAttributes.GetTimerId())
+
+	case workflow.EventTypeTimerCanceled:
+		return b.msBuilder.DeleteUserTimer(event.TimerCanceledEventAttributes.GetTimerId())
+
+	case workflow.EventTypeStartChildWorkflowExecutionInitiated:
+		// CreateRequestID is only used for deduping the start of child execution which is never performed by the
+		// standby cluster, so generate a new one
+		if _, err := b.msBuilder.ReplicateStartChildWorkflowExecutionInitiatedEvent(event, uuid.New()); err != nil {
+			return err
+		}
+
+	case workflow.EventTypeStartChildWorkflowExecutionFailed:
+		return b.msBuilder.DeletePendingChildExecution(
+			event.StartChildWorkflowExecutionFailedEventAttributes.GetInitiatedEventId())
+
+	case workflow.EventTypeChildWorkflowExecutionStarted:
+		return b.msBuilder.ReplicateChildWorkflowExecutionStartedEvent(event)
+
+	case workflow.EventTypeChildWorkflowExecutionCompleted:
+		return b.msBuilder.DeletePendingChildExecution(
+			event.ChildWorkflowExecutionCompletedEventAttributes.GetInitiatedEventId())
+
+	case workflow.EventTypeChildWorkflowExecutionFailed:
+		return b.msBuilder.DeletePendingChildExecution(
+			event.ChildWorkflowExecutionFailedEventAttributes.GetInitiatedEventId())
+
+	case workflow.EventTypeChildWorkflowExecutionCanceled:
+		return b.msBuilder.DeletePendingChildExecution(
+			event.ChildWorkflowExecutionCanceledEventAttributes.GetInitiatedEventId())
+
+	case workflow.EventTypeChildWorkflowExecutionTimedOut:
+		return b.msBuilder.DeletePendingChildExecution(
+			event.ChildWorkflowExecutionTimedOutEventAttributes.GetInitiatedEventId())
+
+	case workflow.EventTypeChildWorkflowExecutionTerminated:
+		return b.msBuilder.DeletePendingChildExecution(
+			event.ChildWorkflowExecutionTerminatedEventAttributes.GetInitiatedEventId())
+
+	case workflow.EventTypeRequestCancelExternalWorkflowExecutionInitiated:
+		// CancelRequestID is only used for deduping the cancellation which is never performed by the standby cluster
+		b.msBuilder.ReplicateRequestCancelExternalWorkflowExecutionInitiatedEvent(event, uuid.New())
+
+	case workflow.EventTypeRequestCancelExternalWorkflowExecutionFailed:
+		return b.msBuilder.DeletePendingRequestCancel(
+			event.RequestCancelExternalWorkflowExecutionFailedEventAttributes.GetInitiatedEventId())
+
+	case workflow.EventTypeExternalWorkflowExecutionCancelRequested:
+		return b.msBuilder.DeletePendingRequestCancel(
+			event.ExternalWorkflowExecutionCancelRequestedEventAttributes.GetInitiatedEventId())
+
+	case workflow.EventTypeSignalExternalWorkflowExecutionInitiated:
+		// SignalRequestID is only used for deduping the signal which is never performed by the standby cluster
+		b.msBuilder.ReplicateSignalExternalWorkflowExecutionInitiatedEvent(event, uuid.New())
+
+	case workflow.EventTypeSignalExternalWorkflowExecutionFailed:
+		return b.msBuilder.DeletePendingSignal(
+			event.SignalExternalWorkflowExecutionFailedEventAttributes.GetInitiatedEventId())
+
+	case workflow.EventTypeExternalWorkflowExecutionSignaled:
+		return b.msBuilder.DeletePendingSignal(
+			event.ExternalWorkflowExecutionSignaledEventAttributes.GetInitiatedEventId())
+
+	case workflow.EventTypeWorkflowExecutionCancelRequested:
+		b.msBuilder.ReplicateWorkflowExecutionCancelRequestedEvent()
+
+	case workflow.EventTypeWorkflowExecutionCompleted:
+		return b.msBuilder.ReplicateWorkflowExecutionClosedEvent(persistence.WorkflowCloseStatusCompleted, event)
+
+	case workflow.EventTypeWorkflowExecutionFailed:
+		return b.msBuilder.ReplicateWorkflowExecutionClosedEvent(persistence.WorkflowCloseStatusFailed, event)
+
+	case workflow.EventTypeWorkflowExecutionTimedOut:
+		return b.msBuilder.ReplicateWorkflowExecutionClosedEvent(persistence.WorkflowCloseStatusTimedOut, event)
+
+	case workflow.EventTypeWorkflowExecutionCanceled:
+		return b.msBuilder.ReplicateWorkflowExecutionClosedEvent(persistence.WorkflowCloseStatusCanceled, event)
+
+	case workflow.EventTypeWorkflowExecutionTerminated:
+		return b.msBuilder.ReplicateWorkflowExecutionClosedEvent(persistence.WorkflowCloseStatusTerminated, event)
+
+	case workflow.EventTypeWorkflowExecutionContinuedAsNew:
+		// The new run is replicated through its own replication task
+		return b.msBuilder.ReplicateWorkflowExecutionClosedEvent(persistence.WorkflowCloseStatusContinuedAsNew, event)
+
+	case workflow.EventTypeUpsertWorkflowSearchAttributes:
+		b.msBuilder.ReplicateUpsertWorkflowSearchAttributesEvent(event)
+
+	case workflow.EventTypeWorkflowExecutionSignaled, workflow.EventTypeMarkerRecorded,
+		workflow.EventTypeRequestCancelActivityTaskFailed, workflow.EventTypeCancelTimerFailed:
+		// No mutable state changes for these events
+
+	default:
+		b.logger.Warnf("Unable to replicate event of unknown type. EventID: %v, EventType: %v", event.GetEventId(),
+			event.GetEventType())
+		return &workflow.BadRequestError{Message: fmt.Sprintf("Unknown event type: %v", event.GetEventType())}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"sync"
+	"time"
+
+	"github.com/uber/cadence/common"
+)
+
+type (
+	// taskWorkerPool runs the workers of a queue processor and follows the changes of their count,
+	// it is only used from the pump goroutine of the processor so it does not need any locking
+	taskWorkerPool struct {
+		workerCount func() int
+		worker      func(stopCh <-chan struct{})
+		stopChs     []chan struct{}
+		workerWG    sync.WaitGroup
+	}
+)
+
+func newTaskWorkerPool(workerCount func() int, worker func(stopCh <-chan struct{})) *taskWorkerPool {
+	return &taskWorkerPool{
+		workerCount: workerCount,
+		worker:      worker,
+	}
+}
+
+// resize starts or stops workers until the number of workers matches the current worker count,
+// a stopped worker finishes the task it is processing before exiting
+func (p *taskWorkerPool) resize() {
+	count := p.workerCount()
+	if count < 1 {
+		count = 1
+	}
+
+	for len(p.stopChs) < count {
+		stopCh := make(chan struct{})
+		p.stopChs = append(p.stopChs, stopCh)
+		p.workerWG.Add(1)
+		go func() {
+			defer p.workerWG.Done()
+			p.worker(stopCh)
+		}()
+	}
+
+	for len(p.stopChs) > count {
+		last := len(p.stopChs) - 1
+		close(p.stopChs[last])
+		p.stopChs = p.stopChs[:last]
+	}
+}
+
+// await waits for all the workers to exit, the workers are expected to exit once their task channel is closed
+func (p *taskWorkerPool) await(timeout time.Duration) bool {
+	return common.AwaitWaitGroup(&p.workerWG, timeout)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	taskWorkerPoolSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		workerCount int
+		running     int32
+		tasksCh     chan int
+		pool        *taskWorkerPool
+	}
+)
+
+func TestTaskWorkerPoolSuite(t *testing.T) {
+	s := new(taskWorkerPoolSuite)
+	suite.Run(t, s)
+}
+
+func (s *taskWorkerPoolSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.workerCount = 3
+	s.running = 0
+	s.tasksCh = make(chan int)
+	s.pool = newTaskWorkerPool(func() int { return s.workerCount }, func(stopCh <-chan struct{}) {
+		atomic.AddInt32(&s.running, 1)
+		defer atomic.AddInt32(&s.running, -1)
+		for {
+			select {
+			case <-stopCh:
+				return
+			case _, ok := <-s.tasksCh:
+				if !ok {
+					return
+				}
+			}
+		}
+	})
+}
+
+func (s *taskWorkerPoolSuite) TestResize() {
+	s.pool.resize()
+	s.waitForRunning(3)
+
+	s.workerCount = 5
+	s.pool.resize()
+	s.waitForRunning(5)
+
+	s.workerCount = 2
+	s.pool.resize()
+	s.waitForRunning(2)
+
+	// at least one worker is always running
+	s.workerCount = 0
+	s.pool.resize()
+	s.waitForRunning(1)
+
+	close(s.tasksCh)
+	s.True(s.pool.await(time.Second))
+	s.Equal(int32(0), atomic.LoadInt32(&s.running))
+}
+
+func (s *taskWorkerPoolSuite) waitForRunning(count int32) {
+	for i := 0; i < 100 && atomic.LoadInt32(&s.running) != count; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	s.Equal(count, atomic.LoadInt32(&s.running))
+}
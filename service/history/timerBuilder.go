@@ -45,6 +45,9 @@ const (
 	TimerTaskStatusCreatedScheduleToStart
 	TimerTaskStatusCreatedScheduleToClose
 	TimerTaskStatusCreatedHeartbeat
+	// The next attempt of the activity is dispatched when its retry timer fires, the activity has no other timer until
+	// then
+	TimerTaskStatusCreatedRetry
 )
 
 type (
@@ -56,6 +59,7 @@ type (
 		TimeoutType     w.TimeoutType
 		EventID         int64
 		TimeoutSec      int32
+		IsRetry         bool
 	}
 
 	timers []*timerDetails
@@ -110,8 +114,9 @@ func (t timers) Less(i, j int) bool {
 }
 
 func (td *timerDetails) String() string {
-	return fmt.Sprintf("TimerDetails: SeqID: %s, TimerID: %v, ActivityID: %v, TaskCreated: %v, EventID: %v, TimeoutType: %v, TimeoutSec: %v",
-		td.TimerSequenceID, td.TimerID, td.ActivityID, td.TaskCreated, td.EventID, td.TimeoutType.String(), td.TimeoutSec)
+	return fmt.Sprintf("TimerDetails: SeqID: %s, TimerID: %v, ActivityID: %v, TaskCreated: %v, EventID: %v, TimeoutType: %v, TimeoutSec: %v, IsRetry: %v",
+		td.TimerSequenceID, td.TimerID, td.ActivityID, td.TaskCreated, td.EventID, td.TimeoutType.String(), td.TimeoutSec,
+		td.IsRetry)
 }
 
 func (l *localSeqNumGenerator) NextSeq() int64 {
@@ -178,6 +183,40 @@ func (tb *timerBuilder) AddActivityTimeoutTask(scheduleID int64,
 	return timeOutTask
 }
 
+// AddActivityRetryTimer - Adds an activity timer task which fires at the scheduled time of the next attempt of a
+// retried activity, the attempt is dispatched by the timer queue processor when the timer fires.
+func (tb *timerBuilder) AddActivityRetryTimer(ai *persistence.ActivityInfo) *persistence.ActivityTimeoutTask {
+	ai.TimerTaskStatus = ai.TimerTaskStatus | TimerTaskStatusCreatedRetry
+	tb.logger.Debugf("Adding Activity Retry Timer: ScheduledTime: %s, Attempt: %v, EventID: %v",
+		ai.ScheduledTime, ai.Attempt, ai.ScheduleID)
+	return &persistence.ActivityTimeoutTask{
+		VisibilityTimestamp: ai.ScheduledTime,
+		TimeoutType:         int(w.TimeoutTypeScheduleToStart),
+		EventID:             ai.ScheduleID,
+	}
+}
+
+// GetWorkflowStartTimerTasks - Gets the workflow timeout timer task of a new run and, when the first decision of the
+// run is delayed by a retry or cron backoff, the backoff timer task which schedules the first decision.
+func (tb *timerBuilder) GetWorkflowStartTimerTasks(workflowTimeoutSeconds, backoffSeconds int32,
+	backoffTimeoutType int) []persistence.Task {
+	now := tb.timeSource.Now()
+	backoffDuration := time.Duration(backoffSeconds) * time.Second
+	timerTasks := []persistence.Task{&persistence.WorkflowTimeoutTask{
+		VisibilityTimestamp: now.Add(backoffDuration + time.Duration(workflowTimeoutSeconds)*time.Second),
+	}}
+	if backoffDuration > 0 {
+		tb.logger.Debugf("Adding Workflow Backoff Timer: with backoff: %v sec, TimeoutType: %v",
+			backoffSeconds, backoffTimeoutType)
+		timerTasks = append(timerTasks, &persistence.WorkflowBackoffTimerTask{
+			VisibilityTimestamp: now.Add(backoffDuration),
+			TimeoutType:         backoffTimeoutType,
+		})
+	}
+
+	return timerTasks
+}
+
 // AddUserTimer - Adds an user timeout request.
 func (tb *timerBuilder) AddUserTimer(ti *persistence.TimerInfo, msBuilder *mutableStateBuilder) {
 	if !tb.isLoadedUserTimers {
@@ -274,6 +313,18 @@ func (tb *timerBuilder) loadActivityTimers(msBuilder *mutableStateBuilder) {
 	tb.activityTimers = make(timers, 0, len(msBuilder.pendingActivityInfoIDs))
 	for _, v := range msBuilder.pendingActivityInfoIDs {
 		if v.ScheduleID != emptyEventID {
+			if (v.TimerTaskStatus & TimerTaskStatusCreatedRetry) != 0 {
+				td := &timerDetails{
+					TimerSequenceID: TimerSequenceID{VisibilityTimestamp: v.ScheduledTime},
+					ActivityID:      v.ScheduleID,
+					EventID:         v.ScheduleID,
+					TimeoutType:     w.TimeoutTypeScheduleToStart,
+					IsRetry:         true,
+					TaskCreated:     true}
+				tb.activityTimers = append(tb.activityTimers, td)
+				continue
+			}
+
 			scheduleToCloseExpiry := v.ScheduledTime.Add(time.Duration(v.ScheduleToCloseTimeout) * time.Second)
 			td := &timerDetails{
 				TimerSequenceID: TimerSequenceID{VisibilityTimestamp: scheduleToCloseExpiry},
@@ -29,6 +29,7 @@ import (
 	"github.com/uber-common/bark"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/service/dynamicconfig"
 )
 
 var (
@@ -46,6 +47,7 @@ type (
 		metricsClient metrics.Client
 		lastUpdated   time.Time
 		config        *Config
+		shardFilter   dynamicconfig.FilterOption
 
 		sync.Mutex
 		// outstanding timer task -> finished (true)
@@ -91,6 +93,7 @@ func newTimerQueueAckMgr(shard ShardContext, metricsClient metrics.Client, execu
 		logger:           logger,
 		lastUpdated:      time.Now(),
 		config:           config,
+		shardFilter:      dynamicconfig.ShardIDFilter(shard.GetShardID()),
 		outstandingTasks: make(map[TimerSequenceID]bool),
 		retryTasks:       []*persistence.TimerTaskInfo{},
 		readLevel:        TimerSequenceID{VisibilityTimestamp: ackLevel},
@@ -109,9 +112,9 @@ func (t *timerQueueAckMgrImpl) readTimerTasks() ([]*persistence.TimerTaskInfo, *
 	var tasks []*persistence.TimerTaskInfo
 	morePage := timerTaskRetrySize > 0
 	var err error
-	if timerTaskRetrySize < t.config.TimerTaskBatchSize {
+	if batchSize := t.config.TimerTaskBatchSize(t.shardFilter); timerTaskRetrySize < batchSize {
 		var token []byte
-		tasks, token, err = t.getTimerTasks(readLevel.VisibilityTimestamp, timerQueueAckMgrMaxTimestamp, t.config.TimerTaskBatchSize)
+		tasks, token, err = t.getTimerTasks(readLevel.VisibilityTimestamp, timerQueueAckMgrMaxTimestamp, batchSize)
 		if err != nil {
 			return nil, nil, false, err
 		}
@@ -227,7 +230,7 @@ MoveAckLevelLoop:
 	t.Unlock()
 
 	// Do not update Acklevel if nothing changed upto force update interval
-	if initialAckLevel == updatedAckLevel && time.Since(t.lastUpdated) < t.config.TimerProcessorForceUpdateInterval {
+	if initialAckLevel == updatedAckLevel && time.Since(t.lastUpdated) < t.config.TimerProcessorForceUpdateInterval(t.shardFilter) {
 		return
 	}
 
@@ -251,7 +254,7 @@ func (t *timerQueueAckMgrImpl) getTimerTasks(minTimestamp time.Time, maxTimestam
 		BatchSize:    batchSize,
 	}
 
-	retryCount := t.config.TimerProcessorGetFailureRetryCount
+	retryCount := t.config.TimerProcessorGetFailureRetryCount(t.shardFilter)
 	for attempt := 0; attempt < retryCount; attempt++ {
 		response, err := t.executionMgr.GetTimerIndexTasks(request)
 		if err == nil {
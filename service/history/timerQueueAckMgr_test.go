@@ -188,7 +188,7 @@ func (s *timerQueueAckMgrSuite) TestReadTimerTasks_NoLookAhead_NoNextPage() {
 	request := &persistence.GetTimerIndexTasksRequest{
 		MinTimestamp: readLevel.VisibilityTimestamp,
 		MaxTimestamp: timerQueueAckMgrMaxTimestamp,
-		BatchSize:    s.mockShard.GetConfig().TimerTaskBatchSize,
+		BatchSize:    s.mockShard.GetConfig().TimerTaskBatchSize(),
 	}
 	response := &persistence.GetTimerIndexTasksResponse{
 		Timers:        []*persistence.TimerTaskInfo{timer},
@@ -237,7 +237,7 @@ func (s *timerQueueAckMgrSuite) TestReadTimerTasks_NoLookAhead_HasNextPage() {
 	request := &persistence.GetTimerIndexTasksRequest{
 		MinTimestamp: readLevel.VisibilityTimestamp,
 		MaxTimestamp: timerQueueAckMgrMaxTimestamp,
-		BatchSize:    s.mockShard.GetConfig().TimerTaskBatchSize,
+		BatchSize:    s.mockShard.GetConfig().TimerTaskBatchSize(),
 	}
 	response := &persistence.GetTimerIndexTasksResponse{
 		Timers:        []*persistence.TimerTaskInfo{timer},
@@ -287,7 +287,7 @@ func (s *timerQueueAckMgrSuite) TestReadTimerTasks_HasLookAhead_NoNextPage() {
 	request := &persistence.GetTimerIndexTasksRequest{
 		MinTimestamp: readLevel.VisibilityTimestamp,
 		MaxTimestamp: timerQueueAckMgrMaxTimestamp,
-		BatchSize:    s.mockShard.GetConfig().TimerTaskBatchSize,
+		BatchSize:    s.mockShard.GetConfig().TimerTaskBatchSize(),
 	}
 	response := &persistence.GetTimerIndexTasksResponse{
 		Timers:        []*persistence.TimerTaskInfo{timer},
@@ -336,7 +336,7 @@ func (s *timerQueueAckMgrSuite) TestReadTimerTasks_HasLookAhead_HasNextPage() {
 	request := &persistence.GetTimerIndexTasksRequest{
 		MinTimestamp: readLevel.VisibilityTimestamp,
 		MaxTimestamp: timerQueueAckMgrMaxTimestamp,
-		BatchSize:    s.mockShard.GetConfig().TimerTaskBatchSize,
+		BatchSize:    s.mockShard.GetConfig().TimerTaskBatchSize(),
 	}
 	response := &persistence.GetTimerIndexTasksResponse{
 		Timers:        []*persistence.TimerTaskInfo{timer},
@@ -403,7 +403,7 @@ func (s *timerQueueAckMgrSuite) TestReadCompleteUpdateTimerTasks() {
 	request := &persistence.GetTimerIndexTasksRequest{
 		MinTimestamp: readLevel.VisibilityTimestamp,
 		MaxTimestamp: timerQueueAckMgrMaxTimestamp,
-		BatchSize:    s.mockShard.GetConfig().TimerTaskBatchSize,
+		BatchSize:    s.mockShard.GetConfig().TimerTaskBatchSize(),
 	}
 	response := &persistence.GetTimerIndexTasksResponse{
 		Timers:        []*persistence.TimerTaskInfo{timer1, timer2, timer3},
@@ -469,7 +469,7 @@ func (s *timerQueueAckMgrSuite) TestReadRetryCompleteUpdateTimerTasks() {
 	request := &persistence.GetTimerIndexTasksRequest{
 		MinTimestamp: readLevel.VisibilityTimestamp,
 		MaxTimestamp: timerQueueAckMgrMaxTimestamp,
-		BatchSize:    s.mockShard.GetConfig().TimerTaskBatchSize,
+		BatchSize:    s.mockShard.GetConfig().TimerTaskBatchSize(),
 	}
 	response := &persistence.GetTimerIndexTasksResponse{
 		Timers:        []*persistence.TimerTaskInfo{timer},
@@ -494,7 +494,7 @@ func (s *timerQueueAckMgrSuite) TestReadRetryCompleteUpdateTimerTasks() {
 	request = &persistence.GetTimerIndexTasksRequest{
 		MinTimestamp: s.timerQueueAckMgr.readLevel.VisibilityTimestamp,
 		MaxTimestamp: timerQueueAckMgrMaxTimestamp,
-		BatchSize:    s.mockShard.GetConfig().TimerTaskBatchSize,
+		BatchSize:    s.mockShard.GetConfig().TimerTaskBatchSize(),
 	}
 	response = &persistence.GetTimerIndexTasksResponse{
 		Timers:        []*persistence.TimerTaskInfo{},
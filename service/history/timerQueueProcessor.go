@@ -31,16 +31,19 @@ import (
 	"github.com/uber-common/bark"
 	workflow "github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/archiver"
 	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/logging"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/service/dynamicconfig"
 )
 
 var (
 	errTimerTaskNotFound          = errors.New("Timer task not found")
 	errFailedToAddTimeoutEvent    = errors.New("Failed to add timeout event")
 	errFailedToAddTimerFiredEvent = errors.New("Failed to add timer fired event")
+	errArchivalTimedOut           = errors.New("Timed out archiving history")
 	emptyTime                     = time.Time{}
 	maxTimestamp                  = time.Unix(0, math.MaxInt64)
 )
@@ -57,6 +60,7 @@ type (
 		shutdownCh       chan struct{}
 		newTimerCh       chan struct{}
 		config           *Config
+		shardFilter      dynamicconfig.FilterOption
 		logger           bark.Logger
 		metricsClient    metrics.Client
 		timerFiredCount  uint64
@@ -79,6 +83,7 @@ func newTimerQueueProcessor(shard ShardContext, historyService *historyEngineImp
 		shutdownCh:       make(chan struct{}),
 		newTimerCh:       make(chan struct{}, 1),
 		config:           shard.GetConfig(),
+		shardFilter:      dynamicconfig.ShardIDFilter(shard.GetShardID()),
 		logger:           log,
 		metricsClient:    historyService.metricsClient,
 		timerQueueAckMgr: newTimerQueueAckMgr(shard, historyService.metricsClient, executionManager, shard.GetService().GetClusterMetadata().GetCurrentClusterName(), log),
@@ -92,7 +97,7 @@ func (t *timerQueueProcessorImpl) Start() {
 	}
 
 	t.shutdownWG.Add(1)
-	go t.processorPump(t.config.ProcessTimerTaskWorkerCount)
+	go t.processorPump()
 
 	t.logger.Info("Timer queue processor started.")
 }
@@ -139,6 +144,8 @@ func (t *timerQueueProcessorImpl) NotifyNewTimers(timerTasks []persistence.Task)
 			t.metricsClient.IncCounter(metrics.TimerTaskWorkflowTimeoutScope, metrics.NewActiveTimerCounter)
 		case persistence.TaskTypeDeleteHistoryEvent:
 			t.metricsClient.IncCounter(metrics.TimerTaskDeleteHistoryEvent, metrics.NewActiveTimerCounter)
+		case persistence.TaskTypeWorkflowBackoffTimer:
+			t.metricsClient.IncCounter(metrics.TimerTaskWorkflowBackoffTimerScope, metrics.NewActiveTimerCounter)
 			// TODO add default
 		}
 	}
@@ -162,16 +169,17 @@ func (t *timerQueueProcessorImpl) NotifyNewTimerEvents() {
 	// TODO pending implementation
 }
 
-func (t *timerQueueProcessorImpl) processorPump(taskWorkerCount int) {
+func (t *timerQueueProcessorImpl) processorPump() {
 	defer t.shutdownWG.Done()
 
 	// Workers to process timer tasks that are expired.
-	tasksCh := make(chan *persistence.TimerTaskInfo, 10*t.config.TimerTaskBatchSize)
-	var workerWG sync.WaitGroup
-	for i := 0; i < taskWorkerCount; i++ {
-		workerWG.Add(1)
-		go t.processTaskWorker(tasksCh, &workerWG)
-	}
+	tasksCh := make(chan *persistence.TimerTaskInfo, 10*t.config.TimerTaskBatchSize(t.shardFilter))
+	workerPool := newTaskWorkerPool(func() int {
+		return t.config.ProcessTimerTaskWorkerCount(t.shardFilter)
+	}, func(stopCh <-chan struct{}) {
+		t.processTaskWorker(tasksCh, stopCh)
+	})
+	workerPool.resize()
 
 RetryProcessor:
 	for {
@@ -179,12 +187,12 @@ RetryProcessor:
 		case <-t.shutdownCh:
 			t.logger.Info("Timer queue processor pump shutting down.")
 			close(tasksCh)
-			if success := common.AwaitWaitGroup(&workerWG, 10*time.Second); !success {
+			if success := workerPool.await(10 * time.Second); !success {
 				t.logger.Warn("Timer queue processor timed out on worker shutdown.")
 			}
 			break RetryProcessor
 		default:
-			err := t.internalProcessor(tasksCh)
+			err := t.internalProcessor(tasksCh, workerPool)
 			if err != nil {
 				t.logger.Error("processor pump failed with error: ", err)
 			}
@@ -193,11 +201,13 @@ RetryProcessor:
 	t.logger.Info("Timer processor exiting.")
 }
 
-func (t *timerQueueProcessorImpl) internalProcessor(tasksCh chan<- *persistence.TimerTaskInfo) error {
+func (t *timerQueueProcessorImpl) internalProcessor(tasksCh chan<- *persistence.TimerTaskInfo,
+	workerPool *taskWorkerPool) error {
 	timerGate := NewTimerGate()
 	defer timerGate.Close()
 
-	updateAckChan := time.NewTicker(t.config.TimerProcessorUpdateAckInterval).C
+	updateAckTimer := time.NewTimer(t.config.TimerProcessorUpdateAckInterval(t.shardFilter))
+	defer updateAckTimer.Stop()
 	var nextKeyTask *persistence.TimerTaskInfo
 
 continueProcessor:
@@ -218,8 +228,10 @@ continueProcessor:
 			case <-timerGate.FireChan():
 				// Timer Fired.
 
-			case <-updateAckChan:
+			case <-updateAckTimer.C:
 				t.timerQueueAckMgr.updateAckLevel()
+				workerPool.resize()
+				updateAckTimer.Reset(t.config.TimerProcessorUpdateAckInterval(t.shardFilter))
 				continue continueProcessor
 
 			case <-t.newTimerCh:
@@ -275,10 +287,11 @@ continueProcessor:
 	}
 }
 
-func (t *timerQueueProcessorImpl) processTaskWorker(tasksCh <-chan *persistence.TimerTaskInfo, workerWG *sync.WaitGroup) {
-	defer workerWG.Done()
+func (t *timerQueueProcessorImpl) processTaskWorker(tasksCh <-chan *persistence.TimerTaskInfo, stopCh <-chan struct{}) {
 	for {
 		select {
+		case <-stopCh:
+			return
 		case task, ok := <-tasksCh:
 			if !ok {
 				return
@@ -287,7 +300,7 @@ func (t *timerQueueProcessorImpl) processTaskWorker(tasksCh <-chan *persistence.
 			var err error
 
 		UpdateFailureLoop:
-			for attempt := 1; attempt <= t.config.TimerProcessorUpdateFailureRetryCount; attempt++ {
+			for attempt := 1; attempt <= t.config.TimerProcessorUpdateFailureRetryCount(t.shardFilter); attempt++ {
 				taskID := TimerSequenceID{VisibilityTimestamp: task.VisibilityTimestamp, TaskID: task.TaskID}
 				err = t.processTimerTask(task)
 				if err != nil && err != errTimerTaskNotFound {
@@ -334,6 +347,10 @@ func (t *timerQueueProcessorImpl) processTimerTask(timerTask *persistence.TimerT
 	case persistence.TaskTypeDeleteHistoryEvent:
 		scope = metrics.TimerTaskDeleteHistoryEvent
 		err = t.processDeleteHistoryEvent(timerTask)
+
+	case persistence.TaskTypeWorkflowBackoffTimer:
+		scope = metrics.TimerTaskWorkflowBackoffTimerScope
+		err = t.processWorkflowBackoffTimer(timerTask)
 	}
 
 	if err != nil {
@@ -423,7 +440,7 @@ Update_History_Loop:
 
 		// We apply the update to execution using optimistic concurrency.  If it fails due to a conflict than reload
 		// the history and try the operation again.
-		err := t.updateWorkflowExecution(context, msBuilder, scheduleNewDecision, false, timerTasks, nil)
+		err := t.updateWorkflowExecution(context, msBuilder, scheduleNewDecision, false, nil, timerTasks, nil)
 		if err != nil {
 			if err == ErrConflict {
 				continue Update_History_Loop
@@ -477,19 +494,32 @@ Update_History_Loop:
 			return nil
 		}
 
+		var transferTasks []persistence.Task
 		var timerTasks []persistence.Task
 		updateHistory := false
 		createNewTimer := false
+		retriedActivities := make(map[int64]bool)
 
 	ExpireActivityTimers:
 		for _, td := range tBuilder.GetActivityTimers(msBuilder) {
 			ai, isRunning := msBuilder.GetActivityInfo(td.ActivityID)
-			if !isRunning {
-				//  We might have time out this activity already.
+			if !isRunning || retriedActivities[td.ActivityID] {
+				//  We might have time out or retried this activity already.
 				continue ExpireActivityTimers
 			}
 
 			if isExpired := tBuilder.IsTimerExpired(td, timerTask.VisibilityTimestamp); isExpired {
+				if td.IsRetry {
+					// The backoff of the activity is over, dispatch its next attempt
+					transferTask, err := t.dispatchActivityRetry(msBuilder, ai)
+					if err != nil {
+						return err
+					}
+					transferTasks = append(transferTasks, transferTask)
+					createNewTimer = true
+					continue ExpireActivityTimers
+				}
+
 				timeoutType := td.TimeoutType
 				t.logger.Debugf("Activity TimeoutType: %v, scheduledID: %v, startedId: %v. \n",
 					timeoutType, ai.ScheduleID, ai.StartedID)
@@ -508,6 +538,12 @@ Update_History_Loop:
 					{
 						t.metricsClient.IncCounter(metrics.TimerTaskActivityTimeoutScope, metrics.StartToCloseTimeoutCounter)
 						if ai.StartedID != emptyEventID {
+							if t.retryActivity(msBuilder, tBuilder, ai, timeoutType, &timerTasks) {
+								retriedActivities[ai.ScheduleID] = true
+								createNewTimer = true
+								continue ExpireActivityTimers
+							}
+
 							if msBuilder.AddActivityTaskTimedOutEvent(ai.ScheduleID, ai.StartedID, timeoutType, nil) == nil {
 								return errFailedToAddTimeoutEvent
 							}
@@ -520,6 +556,12 @@ Update_History_Loop:
 						t.metricsClient.IncCounter(metrics.TimerTaskActivityTimeoutScope, metrics.HeartbeatTimeoutCounter)
 						t.logger.Debugf("Activity Heartbeat expired: %+v", *ai)
 
+						if t.retryActivity(msBuilder, tBuilder, ai, timeoutType, &timerTasks) {
+							retriedActivities[ai.ScheduleID] = true
+							createNewTimer = true
+							continue ExpireActivityTimers
+						}
+
 						if msBuilder.AddActivityTaskTimedOutEvent(ai.ScheduleID, ai.StartedID, timeoutType, ai.Details) == nil {
 							return errFailedToAddTimeoutEvent
 						}
@@ -547,7 +589,7 @@ Update_History_Loop:
 				// where we create next timer task based on that new updated timestamp.
 				if !td.TaskCreated || (isHeartBeatTask && td.EventID == scheduleID) {
 					nextTask := tBuilder.createNewTask(td)
-					timerTasks = []persistence.Task{nextTask}
+					timerTasks = append(timerTasks, nextTask)
 					at := nextTask.(*persistence.ActivityTimeoutTask)
 
 					ai.TimerTaskStatus = ai.TimerTaskStatus | getActivityTimerStatus(workflow.TimeoutType(at.TimeoutType))
@@ -563,11 +605,20 @@ Update_History_Loop:
 			}
 		}
 
+		if len(transferTasks) > 0 {
+			// The timeouts of the dispatched attempts were not known when the activity timers were loaded
+			retryTimerBuilder := t.historyService.getTimerBuilder(&context.workflowExecution)
+			if tt := retryTimerBuilder.GetActivityTimerTaskIfNeeded(msBuilder); tt != nil {
+				timerTasks = append(timerTasks, tt)
+			}
+		}
+
 		if updateHistory || createNewTimer {
 			// We apply the update to execution using optimistic concurrency.  If it fails due to a conflict than reload
 			// the history and try the operation again.
 			scheduleNewDecision := updateHistory && !msBuilder.HasPendingDecisionTask()
-			err := t.updateWorkflowExecution(context, msBuilder, scheduleNewDecision, false, timerTasks, nil)
+			err := t.updateWorkflowExecution(context, msBuilder, scheduleNewDecision, false, transferTasks, timerTasks,
+				nil)
 			if err != nil {
 				if err == ErrConflict {
 					continue Update_History_Loop
@@ -583,11 +634,61 @@ Update_History_Loop:
 	return ErrMaxAttemptsExceeded
 }
 
+// retryActivity schedules the next attempt of an activity which timed out if its retry policy allows.
+func (t *timerQueueProcessorImpl) retryActivity(msBuilder *mutableStateBuilder, tBuilder *timerBuilder,
+	ai *persistence.ActivityInfo, timeoutType workflow.TimeoutType, timerTasks *[]persistence.Task) bool {
+	if !msBuilder.RetryActivity(ai, getTimeoutErrorReason(timeoutType), t.shard.GetTimeSource().Now()) {
+		return false
+	}
+
+	t.metricsClient.IncCounter(metrics.TimerTaskActivityTimeoutScope, metrics.ActivityRetryCounter)
+	t.logger.Debugf("Retrying activity after timeout. TimeoutType: %v, ScheduleID: %v, Attempt: %v",
+		timeoutType, ai.ScheduleID, ai.Attempt)
+	*timerTasks = append(*timerTasks, tBuilder.AddActivityRetryTimer(ai))
+	return true
+}
+
+// dispatchActivityRetry resets the retry timer of an activity and returns the transfer task which dispatches its next
+// attempt, as it is done for newly scheduled activities.
+func (t *timerQueueProcessorImpl) dispatchActivityRetry(msBuilder *mutableStateBuilder,
+	ai *persistence.ActivityInfo) (persistence.Task, error) {
+	scheduledEvent, ok := msBuilder.GetActivityScheduledEvent(ai.ScheduleID)
+	if !ok {
+		return nil, &workflow.InternalServiceError{Message: "Unable to get activity schedule event."}
+	}
+	attributes := scheduledEvent.ActivityTaskScheduledEventAttributes
+
+	targetDomainID := msBuilder.executionInfo.DomainID
+	if attributes.Domain != nil {
+		domainEntry, err := t.shard.GetDomainCache().GetDomain(attributes.GetDomain())
+		if err != nil {
+			return nil, &workflow.InternalServiceError{Message: "Unable to re-schedule activity across domain."}
+		}
+		targetDomainID = domainEntry.GetInfo().ID
+	}
+
+	ai.TimerTaskStatus = ai.TimerTaskStatus &^ TimerTaskStatusCreatedRetry
+	msBuilder.UpdateActivity(ai)
+	t.logger.Debugf("Dispatching activity retry. ScheduleID: %v, Attempt: %v", ai.ScheduleID, ai.Attempt)
+
+	return &persistence.ActivityTask{
+		DomainID:   targetDomainID,
+		TaskList:   attributes.TaskList.GetName(),
+		ScheduleID: ai.ScheduleID,
+	}, nil
+}
+
 func (t *timerQueueProcessorImpl) processDeleteHistoryEvent(task *persistence.TimerTaskInfo) error {
 	t.metricsClient.IncCounter(metrics.TimerTaskDeleteHistoryEvent, metrics.TaskRequests)
 	sw := t.metricsClient.StartTimer(metrics.TimerTaskDeleteHistoryEvent, metrics.TaskLatency)
 	defer sw.Stop()
 
+	// The history is archived before anything gets deleted, a failed or timed out archival is retried along with the task
+	domainID, workflowExecution := getDomainIDAndWorkflowExecution(task)
+	if err := t.archiveHistoryWithTimeout(domainID, workflowExecution); err != nil {
+		return err
+	}
+
 	op := func() error {
 		return t.executionManager.DeleteWorkflowExecution(&persistence.DeleteWorkflowExecutionRequest{
 			DomainID:   task.DomainID,
@@ -601,7 +702,6 @@ func (t *timerQueueProcessorImpl) processDeleteHistoryEvent(task *persistence.Ti
 		return err
 	}
 
-	domainID, workflowExecution := getDomainIDAndWorkflowExecution(task)
 	op = func() error {
 		return t.historyService.historyMgr.DeleteWorkflowExecutionHistory(
 			&persistence.DeleteWorkflowExecutionHistoryRequest{
@@ -613,6 +713,156 @@ func (t *timerQueueProcessorImpl) processDeleteHistoryEvent(task *persistence.Ti
 	return backoff.Retry(op, persistenceOperationRetryPolicy, common.IsPersistenceTransientError)
 }
 
+// archiveHistoryWithTimeout archives the history asynchronously and blocks the timer worker for at most
+// ArchivalTimeout on it.  On timeout the archival is told to stop and errArchivalTimedOut fails the task, so the task is
+// retried later and the history is only deleted once an attempt has uploaded all of it.
+func (t *timerQueueProcessorImpl) archiveHistoryWithTimeout(domainID string, execution workflow.WorkflowExecution) error {
+	stopCh := make(chan struct{})
+	doneCh := make(chan error, 1)
+	go func() {
+		doneCh <- t.archiveHistory(domainID, execution, stopCh)
+	}()
+
+	timer := time.NewTimer(t.config.ArchivalTimeout(t.shardFilter))
+	defer timer.Stop()
+	select {
+	case err := <-doneCh:
+		return err
+	case <-timer.C:
+		close(stopCh)
+		t.logger.Warnf("Timed out archiving history. DomainID: %v, WorkflowID: %v, RunID: %v",
+			domainID, execution.GetWorkflowId(), execution.GetRunId())
+		return errArchivalTimedOut
+	case <-t.shutdownCh:
+		close(stopCh)
+		return errArchivalTimedOut
+	}
+}
+
+// archiveHistory uploads the history of the execution to the archiver when the domain has archival enabled.  The
+// history is read in pages of ArchivalHistoryPageSize batches and each page is uploaded as a blob.  The archival gives
+// up with errArchivalTimedOut before its next read or upload once stopCh is closed.
+func (t *timerQueueProcessorImpl) archiveHistory(domainID string, execution workflow.WorkflowExecution,
+	stopCh <-chan struct{}) error {
+	isStopped := func() bool {
+		select {
+		case <-stopCh:
+			return true
+		default:
+			return false
+		}
+	}
+
+	historyArchiver := t.historyService.archiver
+	if historyArchiver == nil {
+		return nil
+	}
+
+	domainEntry, err := t.shard.GetDomainCache().GetDomainByID(domainID)
+	if err != nil {
+		if _, ok := err.(*workflow.EntityNotExistsError); ok {
+			// the domain got deleted along with its archival config
+			return nil
+		}
+		return err
+	}
+	if domainEntry.GetConfig().ArchivalStatus != workflow.ArchivalStatusEnabled {
+		return nil
+	}
+	bucket := domainEntry.GetConfig().ArchivalBucket
+
+	serializer, err := t.historyService.hSerializerFactory.Get(common.EncodingTypeThriftRWGzip)
+	if err != nil {
+		return err
+	}
+	putBlob := func(page int, blob *archiver.HistoryBlob) error {
+		op := func() error {
+			if isStopped() {
+				return errArchivalTimedOut
+			}
+			return historyArchiver.PutHistoryBlob(&archiver.PutHistoryBlobRequest{
+				Bucket:     bucket,
+				DomainID:   domainID,
+				WorkflowID: execution.GetWorkflowId(),
+				RunID:      execution.GetRunId(),
+				Page:       page,
+				Blob:       blob,
+			})
+		}
+		return backoff.Retry(op, persistenceOperationRetryPolicy, common.IsPersistenceTransientError)
+	}
+
+	// a page is only uploaded once the next one is read, so the last page which has events gets marked as such
+	var blob *archiver.HistoryBlob
+	page := 0
+	var nextPageToken []byte
+	for {
+		var response *persistence.GetWorkflowExecutionHistoryResponse
+		op := func() error {
+			if isStopped() {
+				return errArchivalTimedOut
+			}
+			var err error
+			response, err = t.historyService.historyMgr.GetWorkflowExecutionHistory(
+				&persistence.GetWorkflowExecutionHistoryRequest{
+					DomainID:      domainID,
+					Execution:     execution,
+					FirstEventID:  common.FirstEventID,
+					NextEventID:   common.EndEventID,
+					PageSize:      t.config.ArchivalHistoryPageSize(t.shardFilter),
+					NextPageToken: nextPageToken,
+				})
+			return err
+		}
+		if err := backoff.Retry(op, persistenceOperationRetryPolicy, common.IsPersistenceTransientError); err != nil {
+			if _, ok := err.(*workflow.EntityNotExistsError); ok && blob == nil {
+				// the history got deleted by a previous attempt of the task, once archived
+				return nil
+			}
+			return err
+		}
+
+		events := []*workflow.HistoryEvent{}
+		for _, serializedBatch := range response.Events {
+			batchSerializer, err := t.historyService.hSerializerFactory.Get(serializedBatch.EncodingType)
+			if err != nil {
+				return err
+			}
+			history, err := batchSerializer.Deserialize(&serializedBatch)
+			if err != nil {
+				return err
+			}
+			events = append(events, history.Events...)
+		}
+
+		if len(events) > 0 {
+			if blob != nil {
+				if err := putBlob(page, blob); err != nil {
+					return err
+				}
+				page++
+			}
+			serializedEvents, err := serializer.Serialize(persistence.NewHistoryEventBatch(
+				persistence.GetDefaultHistoryVersion(), events))
+			if err != nil {
+				return err
+			}
+			blob = &archiver.HistoryBlob{Events: serializedEvents}
+		}
+
+		nextPageToken = response.NextPageToken
+		if len(nextPageToken) == 0 {
+			break
+		}
+	}
+
+	if blob == nil {
+		return nil
+	}
+	blob.IsLast = true
+	return putBlob(page, blob)
+}
+
 func (t *timerQueueProcessorImpl) processDecisionTimeout(task *persistence.TimerTaskInfo) error {
 	t.metricsClient.IncCounter(metrics.TimerTaskDecisionTimeoutScope, metrics.TaskRequests)
 	sw := t.metricsClient.StartTimer(metrics.TimerTaskDecisionTimeoutScope, metrics.TaskLatency)
@@ -672,7 +922,7 @@ Update_History_Loop:
 		if scheduleNewDecision {
 			// We apply the update to execution using optimistic concurrency.  If it fails due to a conflict than reload
 			// the history and try the operation again.
-			err := t.updateWorkflowExecution(context, msBuilder, scheduleNewDecision, false, nil, nil)
+			err := t.updateWorkflowExecution(context, msBuilder, scheduleNewDecision, false, nil, nil, nil)
 			if err != nil {
 				if err == ErrConflict {
 					continue Update_History_Loop
@@ -709,6 +959,19 @@ Update_History_Loop:
 			return nil
 		}
 
+		// Start the next run instead of timing out if the workflow should be retried or it is a cron workflow
+		timeoutReason := getTimeoutErrorReason(workflow.TimeoutTypeStartToClose)
+		backoffInterval, initiator := msBuilder.getFailureBackoffInterval(timeoutReason, t.shard.GetTimeSource().Now())
+		if backoffInterval != backoff.NoBackoff {
+			err := t.continueAsNewWorkflowExecution(context, msBuilder, backoffInterval, initiator, timeoutReason)
+			if err != nil {
+				if err == ErrConflict {
+					continue Update_History_Loop
+				}
+			}
+			return err
+		}
+
 		if e := msBuilder.AddTimeoutWorkflowEvent(); e == nil {
 			// If we failed to add the event that means the workflow is already completed.
 			// we drop this timeout event.
@@ -717,7 +980,7 @@ Update_History_Loop:
 
 		// We apply the update to execution using optimistic concurrency.  If it fails due to a conflict than reload
 		// the history and try the operation again.
-		err := t.updateWorkflowExecution(context, msBuilder, false, true, nil, nil)
+		err := t.updateWorkflowExecution(context, msBuilder, false, true, nil, nil, nil)
 		if err != nil {
 			if err == ErrConflict {
 				continue Update_History_Loop
@@ -728,23 +991,105 @@ Update_History_Loop:
 	return ErrMaxAttemptsExceeded
 }
 
+func (t *timerQueueProcessorImpl) processWorkflowBackoffTimer(task *persistence.TimerTaskInfo) error {
+	t.metricsClient.IncCounter(metrics.TimerTaskWorkflowBackoffTimerScope, metrics.TaskRequests)
+	sw := t.metricsClient.StartTimer(metrics.TimerTaskWorkflowBackoffTimerScope, metrics.TaskLatency)
+	defer sw.Stop()
+
+	context, release, err0 := t.cache.getOrCreateWorkflowExecution(getDomainIDAndWorkflowExecution(task))
+	if err0 != nil {
+		return err0
+	}
+	defer release()
+
+Update_History_Loop:
+	for attempt := 0; attempt < conditionalRetryCount; attempt++ {
+		msBuilder, err1 := context.loadWorkflowExecution()
+		if err1 != nil {
+			return err1
+		}
+
+		if !msBuilder.isWorkflowExecutionRunning() {
+			// Workflow is completed.
+			return nil
+		}
+
+		if msBuilder.HasPendingDecisionTask() || msBuilder.executionInfo.LastProcessedEvent != emptyEventID {
+			// The first decision is already scheduled or processed, nothing to do.
+			return nil
+		}
+
+		// Schedule the first decision of the run now that the backoff is over.
+		err := t.updateWorkflowExecution(context, msBuilder, true, false, nil, nil, nil)
+		if err != nil {
+			if err == ErrConflict {
+				continue Update_History_Loop
+			}
+		}
+		return err
+	}
+	return ErrMaxAttemptsExceeded
+}
+
+// continueAsNewWorkflowExecution closes the current run on timeout and starts the next run after the given backoff
+func (t *timerQueueProcessorImpl) continueAsNewWorkflowExecution(
+	context *workflowExecutionContext,
+	msBuilder *mutableStateBuilder,
+	backoffInterval time.Duration,
+	initiator workflow.ContinueAsNewInitiator,
+	failureReason string,
+) error {
+	tBuilder := t.historyService.getTimerBuilder(&context.workflowExecution)
+	newStateBuilder, newRunTimerTasks, err := t.historyService.addContinueAsNewEventWithBackoff(msBuilder, tBuilder,
+		emptyEventID, backoffInterval, initiator, common.StringPtr(failureReason), nil)
+	if err != nil {
+		return err
+	}
+
+	tranT, timerT, err := t.historyService.getDeleteWorkflowTasks(msBuilder.executionInfo.DomainID, tBuilder)
+	if err != nil {
+		return err
+	}
+	transferTasks := []persistence.Task{tranT}
+	timerTasks := []persistence.Task{timerT}
+
+	// Generate a transaction ID for appending events to history
+	transactionID, err1 := t.historyService.shard.GetNextTransferTaskID()
+	if err1 != nil {
+		return err1
+	}
+
+	err = context.continueAsNewWorkflowExecution(msBuilder.executionInfo.ExecutionContext, newStateBuilder,
+		transferTasks, timerTasks, transactionID)
+	if err != nil {
+		if isShardOwnershiptLostError(err) {
+			// Shard is stolen.  Stop timer processing to reduce duplicates
+			t.Stop()
+		}
+		return err
+	}
+
+	t.NotifyNewTimers(append(timerTasks, newRunTimerTasks...))
+	return nil
+}
+
 func (t *timerQueueProcessorImpl) updateWorkflowExecution(
 	context *workflowExecutionContext,
 	msBuilder *mutableStateBuilder,
 	scheduleNewDecision bool,
 	createDeletionTask bool,
+	transferTasks []persistence.Task,
 	timerTasks []persistence.Task,
 	clearTimerTask persistence.Task,
 ) error {
-	var transferTasks []persistence.Task
 	if scheduleNewDecision {
 		// Schedule a new decision.
 		di := msBuilder.AddDecisionTaskScheduledEvent()
-		transferTasks = []persistence.Task{&persistence.DecisionTask{
+		transferTasks = append(transferTasks, &persistence.DecisionTask{
 			DomainID:   msBuilder.executionInfo.DomainID,
 			TaskList:   di.Tasklist,
 			ScheduleID: di.ScheduleID,
-		}}
+		})
 		if msBuilder.isStickyTaskListEnabled() {
 			tBuilder := t.historyService.getTimerBuilder(&context.workflowExecution)
 			stickyTaskTimeoutTimer := tBuilder.AddScheduleToStartDecisionTimoutTask(di.ScheduleID, di.Attempt,
@@ -792,6 +1137,8 @@ func (t *timerQueueProcessorImpl) getTimerTaskType(taskType int) string {
 		return "WorkflowTimeout"
 	case persistence.TaskTypeDeleteHistoryEvent:
 		return "DeleteHistoryEvent"
+	case persistence.TaskTypeWorkflowBackoffTimer:
+		return "WorkflowBackoffTimer"
 	}
 	return "UnKnown"
 }
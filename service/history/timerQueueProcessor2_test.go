@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/archiver"
 	"github.com/uber/cadence/common/cache"
 	"github.com/uber/cadence/common/cluster"
 	"github.com/uber/cadence/common/metrics"
@@ -165,7 +166,7 @@ func (s *timerQueueProcessor2Suite) TestTimerUpdateTimesOut() {
 		TaskList:     common.TaskListPtr(workflow.TaskList{Name: common.StringPtr(taskList)}),
 		ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(2),
 		TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(1),
-	})
+	}, 0, time.Now())
 
 	di := addDecisionTaskScheduledEvent(builder)
 	addDecisionTaskStartedEvent(builder, di.ScheduleID, taskList, uuid.New())
@@ -235,7 +236,7 @@ func (s *timerQueueProcessor2Suite) TestWorkflowTimeout() {
 		TaskList:     common.TaskListPtr(workflow.TaskList{Name: common.StringPtr(taskList)}),
 		ExecutionStartToCloseTimeoutSeconds: common.Int32Ptr(1),
 		TaskStartToCloseTimeoutSeconds:      common.Int32Ptr(1),
-	})
+	}, 0, time.Now())
 
 	di := addDecisionTaskScheduledEvent(builder)
 	addDecisionTaskStartedEvent(builder, di.ScheduleID, taskList, uuid.New())
@@ -285,3 +286,308 @@ func (s *timerQueueProcessor2Suite) TestWorkflowTimeout() {
 	<-waitCh
 	processor.Stop()
 }
+
+func (s *timerQueueProcessor2Suite) TestActivityRetryOnTimeout() {
+	domainID := testDomainActiveID
+	we := workflow.WorkflowExecution{WorkflowId: common.StringPtr("activity-retry-on-timeout-test"),
+		RunId: common.StringPtr("0d00698f-08e1-4d36-a3e2-3bf109f5d2d6")}
+	taskList := "activity-retry-on-timeout"
+	identity := "activity-retry-on-timeout-test"
+
+	builder := newMutableStateBuilder(s.config, s.logger)
+	addWorkflowExecutionStartedEvent(builder, we, "wType", taskList, nil, 100, 10, identity)
+	di := addDecisionTaskScheduledEvent(builder)
+	startedEvent := addDecisionTaskStartedEvent(builder, di.ScheduleID, taskList, identity)
+	completedEvent := addDecisionTaskCompletedEvent(builder, di.ScheduleID, startedEvent.GetEventId(), nil, identity)
+	scheduledEvent, _ := addActivityTaskScheduledEventWithRetry(builder, completedEvent.GetEventId(), "activity1",
+		"activity_type1", taskList, nil, 100, 10, 0, &workflow.RetryPolicy{
+			InitialIntervalInSeconds: common.Int32Ptr(1),
+			BackoffCoefficient:       common.Float64Ptr(1),
+			MaximumAttempts:          common.Int32Ptr(2),
+		})
+	scheduleID := scheduledEvent.GetEventId()
+	addActivityTaskStartedEvent(builder, scheduleID, taskList, identity)
+
+	ms := createMutableState(builder)
+	// the attempt was started long enough ago for its start to close timeout to expire
+	ms.ActivitInfos[scheduleID].ScheduledTime = time.Now().Add(-2 * time.Second)
+	ms.ActivitInfos[scheduleID].StartedTime = time.Now().Add(-2 * time.Second)
+	wfResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(wfResponse, nil).Once()
+
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(arguments mock.Arguments) {
+		updateRequest = arguments.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+
+	processor := newTimerQueueProcessor(s.mockShard, s.mockHistoryEngine, s.mockExecutionMgr, s.logger).(*timerQueueProcessorImpl)
+	err := processor.processActivityTimeout(&persistence.TimerTaskInfo{
+		DomainID:            domainID,
+		WorkflowID:          we.GetWorkflowId(),
+		RunID:               we.GetRunId(),
+		TaskID:              int64(100),
+		TaskType:            persistence.TaskTypeActivityTimeout,
+		TimeoutType:         int(workflow.TimeoutTypeStartToClose),
+		VisibilityTimestamp: time.Now(),
+		EventID:             scheduleID,
+	})
+	s.Nil(err)
+
+	// the timeout is not recorded in history, the next attempt is scheduled by a retry timer instead
+	s.Empty(updateRequest.TransferTasks)
+	s.Equal(1, len(updateRequest.TimerTasks))
+	retryTimer, ok := updateRequest.TimerTasks[0].(*persistence.ActivityTimeoutTask)
+	s.True(ok)
+	s.Equal(scheduleID, retryTimer.EventID)
+	s.Equal(1, len(updateRequest.UpsertActivityInfos))
+	s.Equal(int32(1), updateRequest.UpsertActivityInfos[0].Attempt)
+	s.Equal(emptyEventID, updateRequest.UpsertActivityInfos[0].StartedID)
+	s.Equal(int32(TimerTaskStatusCreatedRetry), updateRequest.UpsertActivityInfos[0].TimerTaskStatus)
+	s.Equal(updateRequest.UpsertActivityInfos[0].ScheduledTime, retryTimer.VisibilityTimestamp)
+	s.Equal(scheduleID+1, updateRequest.ExecutionInfo.NextEventID)
+}
+
+func (s *timerQueueProcessor2Suite) TestActivityRetryTimer() {
+	domainID := testDomainActiveID
+	we := workflow.WorkflowExecution{WorkflowId: common.StringPtr("activity-retry-timer-test"),
+		RunId: common.StringPtr("0d00698f-08e1-4d36-a3e2-3bf109f5d2d6")}
+	taskList := "activity-retry-timer"
+	identity := "activity-retry-timer-test"
+
+	builder := newMutableStateBuilder(s.config, s.logger)
+	addWorkflowExecutionStartedEvent(builder, we, "wType", taskList, nil, 100, 10, identity)
+	di := addDecisionTaskScheduledEvent(builder)
+	startedEvent := addDecisionTaskStartedEvent(builder, di.ScheduleID, taskList, identity)
+	completedEvent := addDecisionTaskCompletedEvent(builder, di.ScheduleID, startedEvent.GetEventId(), nil, identity)
+	scheduledEvent, _ := addActivityTaskScheduledEventWithRetry(builder, completedEvent.GetEventId(), "activity1",
+		"activity_type1", taskList, nil, 100, 10, 0, &workflow.RetryPolicy{
+			InitialIntervalInSeconds: common.Int32Ptr(1),
+			BackoffCoefficient:       common.Float64Ptr(1),
+			MaximumAttempts:          common.Int32Ptr(3),
+		})
+	scheduleID := scheduledEvent.GetEventId()
+
+	// the second attempt waits for its retry timer
+	ms := createMutableState(builder)
+	ms.ActivitInfos[scheduleID].Attempt = 1
+	ms.ActivitInfos[scheduleID].ScheduledTime = time.Now()
+	ms.ActivitInfos[scheduleID].TimerTaskStatus = TimerTaskStatusCreatedRetry
+	wfResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(wfResponse, nil).Once()
+
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(arguments mock.Arguments) {
+		updateRequest = arguments.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+
+	processor := newTimerQueueProcessor(s.mockShard, s.mockHistoryEngine, s.mockExecutionMgr, s.logger).(*timerQueueProcessorImpl)
+	timerTask := &persistence.TimerTaskInfo{
+		DomainID:            domainID,
+		WorkflowID:          we.GetWorkflowId(),
+		RunID:               we.GetRunId(),
+		TaskID:              int64(100),
+		TaskType:            persistence.TaskTypeActivityTimeout,
+		TimeoutType:         int(workflow.TimeoutTypeScheduleToStart),
+		VisibilityTimestamp: time.Now(),
+		EventID:             scheduleID,
+	}
+	s.Nil(processor.processActivityTimeout(timerTask))
+
+	// the next attempt is dispatched through the transfer queue along with its schedule to start timeout
+	s.Equal(1, len(updateRequest.TransferTasks))
+	activityTask, ok := updateRequest.TransferTasks[0].(*persistence.ActivityTask)
+	s.True(ok)
+	s.Equal(domainID, activityTask.DomainID)
+	s.Equal(taskList, activityTask.TaskList)
+	s.Equal(scheduleID, activityTask.ScheduleID)
+	s.Equal(1, len(updateRequest.TimerTasks))
+	timeoutTask, ok := updateRequest.TimerTasks[0].(*persistence.ActivityTimeoutTask)
+	s.True(ok)
+	s.Equal(int(workflow.TimeoutTypeScheduleToStart), timeoutTask.TimeoutType)
+	s.NotEmpty(updateRequest.UpsertActivityInfos)
+	s.Equal(int32(TimerTaskStatusCreatedScheduleToStart), updateRequest.UpsertActivityInfos[0].TimerTaskStatus)
+	s.Equal(scheduleID+1, updateRequest.ExecutionInfo.NextEventID)
+
+	// the retry timer firing again does not dispatch the attempt twice
+	s.Nil(processor.processActivityTimeout(timerTask))
+}
+
+func (s *timerQueueProcessor2Suite) TestWorkflowBackoffTimer() {
+	domainID := testDomainActiveID
+	we := workflow.WorkflowExecution{WorkflowId: common.StringPtr("workflow-backoff-timer-test"),
+		RunId: common.StringPtr("0d00698f-08e1-4d36-a3e2-3bf109f5d2d6")}
+	taskList := "workflow-backoff-timer"
+
+	// the first decision of the run is delayed by the backoff
+	builder := newMutableStateBuilder(s.config, s.logger)
+	addWorkflowExecutionStartedEvent(builder, we, "wType", taskList, nil, 100, 10, "workflow-backoff-timer-test")
+
+	ms := createMutableState(builder)
+	wfResponse := &persistence.GetWorkflowExecutionResponse{State: ms}
+	s.mockExecutionMgr.On("GetWorkflowExecution", mock.Anything).Return(wfResponse, nil).Once()
+
+	var updateRequest *persistence.UpdateWorkflowExecutionRequest
+	s.mockHistoryMgr.On("AppendHistoryEvents", mock.Anything).Return(nil).Once()
+	s.mockExecutionMgr.On("UpdateWorkflowExecution", mock.Anything).Return(nil).Run(func(arguments mock.Arguments) {
+		updateRequest = arguments.Get(0).(*persistence.UpdateWorkflowExecutionRequest)
+	}).Once()
+	s.mockClusterMetadata.On("IsGlobalDomainEnabled").Return(false).Once()
+
+	processor := newTimerQueueProcessor(s.mockShard, s.mockHistoryEngine, s.mockExecutionMgr, s.logger).(*timerQueueProcessorImpl)
+	timerTask := &persistence.TimerTaskInfo{
+		DomainID:            domainID,
+		WorkflowID:          we.GetWorkflowId(),
+		RunID:               we.GetRunId(),
+		TaskID:              int64(100),
+		TaskType:            persistence.TaskTypeWorkflowBackoffTimer,
+		TimeoutType:         persistence.WorkflowBackoffTimeoutTypeRetry,
+		VisibilityTimestamp: time.Now(),
+	}
+	s.Nil(processor.processWorkflowBackoffTimer(timerTask))
+
+	// the first decision is scheduled once the backoff is over
+	s.Equal(1, len(updateRequest.TransferTasks))
+	decisionTask, ok := updateRequest.TransferTasks[0].(*persistence.DecisionTask)
+	s.True(ok)
+	s.Equal(taskList, decisionTask.TaskList)
+	s.Equal(int64(2), decisionTask.ScheduleID)
+	s.Equal(int64(2), updateRequest.ExecutionInfo.DecisionScheduleID)
+
+	// a duplicate timer does not schedule another decision
+	s.Nil(processor.processWorkflowBackoffTimer(timerTask))
+}
+
+func (s *timerQueueProcessor2Suite) TestDeleteHistoryEventArchivesHistory() {
+	domainID := testDomainActiveID
+	we := workflow.WorkflowExecution{WorkflowId: common.StringPtr("delete-history-archives-test"),
+		RunId: common.StringPtr("0d00698f-08e1-4d36-a3e2-3bf109f5d2d6")}
+	mockArchiver := s.setupArchival(domainID)
+
+	builder := newMutableStateBuilder(s.config, s.logger)
+	addWorkflowExecutionStartedEvent(builder, we, "wType", "delete-history-archives", nil, 100, 10, "identity")
+	addDecisionTaskScheduledEvent(builder)
+	serializedHistory, err := builder.hBuilder.Serialize()
+	s.Nil(err)
+
+	// the history is archived before the execution and its history get deleted
+	var calls []string
+	s.mockHistoryMgr.On("GetWorkflowExecutionHistory", mock.Anything).Return(&persistence.GetWorkflowExecutionHistoryResponse{
+		Events: []persistence.SerializedHistoryEventBatch{*serializedHistory},
+	}, nil).Once()
+	mockArchiver.On("PutHistoryBlob", mock.MatchedBy(func(request *archiver.PutHistoryBlobRequest) bool {
+		return request.Bucket == "archival-bucket" && request.DomainID == domainID &&
+			request.WorkflowID == we.GetWorkflowId() && request.RunID == we.GetRunId() && request.Page == 0 &&
+			request.Blob.IsLast
+	})).Return(nil).Run(func(arguments mock.Arguments) {
+		calls = append(calls, "PutHistoryBlob")
+	}).Once()
+	s.mockExecutionMgr.On("DeleteWorkflowExecution", mock.Anything).Return(nil).Run(func(arguments mock.Arguments) {
+		calls = append(calls, "DeleteWorkflowExecution")
+	}).Once()
+	s.mockHistoryMgr.On("DeleteWorkflowExecutionHistory", mock.Anything).Return(nil).Run(func(arguments mock.Arguments) {
+		calls = append(calls, "DeleteWorkflowExecutionHistory")
+	}).Once()
+
+	processor := newTimerQueueProcessor(s.mockShard, s.mockHistoryEngine, s.mockExecutionMgr, s.logger).(*timerQueueProcessorImpl)
+	err = processor.processDeleteHistoryEvent(&persistence.TimerTaskInfo{
+		DomainID:            domainID,
+		WorkflowID:          we.GetWorkflowId(),
+		RunID:               we.GetRunId(),
+		TaskID:              int64(100),
+		TaskType:            persistence.TaskTypeDeleteHistoryEvent,
+		VisibilityTimestamp: time.Now(),
+	})
+	s.Nil(err)
+	s.Equal([]string{"PutHistoryBlob", "DeleteWorkflowExecution", "DeleteWorkflowExecutionHistory"}, calls)
+	mockArchiver.AssertExpectations(s.T())
+}
+
+func (s *timerQueueProcessor2Suite) TestDeleteHistoryEventArchiverFailure() {
+	domainID := testDomainActiveID
+	we := workflow.WorkflowExecution{WorkflowId: common.StringPtr("delete-history-archiver-failure-test"),
+		RunId: common.StringPtr("0d00698f-08e1-4d36-a3e2-3bf109f5d2d6")}
+	mockArchiver := s.setupArchival(domainID)
+
+	builder := newMutableStateBuilder(s.config, s.logger)
+	addWorkflowExecutionStartedEvent(builder, we, "wType", "delete-history-archiver-failure", nil, 100, 10, "identity")
+	serializedHistory, err := builder.hBuilder.Serialize()
+	s.Nil(err)
+
+	s.mockHistoryMgr.On("GetWorkflowExecutionHistory", mock.Anything).Return(&persistence.GetWorkflowExecutionHistoryResponse{
+		Events: []persistence.SerializedHistoryEventBatch{*serializedHistory},
+	}, nil).Once()
+	archiverErr := errors.New("archiver failure")
+	mockArchiver.On("PutHistoryBlob", mock.Anything).Return(archiverErr).Once()
+
+	// nothing gets deleted until the history is archived, the task is retried instead
+	processor := newTimerQueueProcessor(s.mockShard, s.mockHistoryEngine, s.mockExecutionMgr, s.logger).(*timerQueueProcessorImpl)
+	err = processor.processDeleteHistoryEvent(&persistence.TimerTaskInfo{
+		DomainID:            domainID,
+		WorkflowID:          we.GetWorkflowId(),
+		RunID:               we.GetRunId(),
+		TaskID:              int64(100),
+		TaskType:            persistence.TaskTypeDeleteHistoryEvent,
+		VisibilityTimestamp: time.Now(),
+	})
+	s.Equal(archiverErr, err)
+	s.mockExecutionMgr.AssertNotCalled(s.T(), "DeleteWorkflowExecution", mock.Anything)
+	s.mockHistoryMgr.AssertNotCalled(s.T(), "DeleteWorkflowExecutionHistory", mock.Anything)
+	mockArchiver.AssertExpectations(s.T())
+}
+
+func (s *timerQueueProcessor2Suite) TestDeleteHistoryEventArchiverTimeout() {
+	domainID := testDomainActiveID
+	we := workflow.WorkflowExecution{WorkflowId: common.StringPtr("delete-history-archiver-timeout-test"),
+		RunId: common.StringPtr("0d00698f-08e1-4d36-a3e2-3bf109f5d2d6")}
+	mockArchiver := s.setupArchival(domainID)
+	archivalTimeout := s.config.ArchivalTimeout
+	s.config.ArchivalTimeout = dynamicconfig.GetDurationPropertyFn(10 * time.Millisecond)
+	defer func() { s.config.ArchivalTimeout = archivalTimeout }()
+
+	builder := newMutableStateBuilder(s.config, s.logger)
+	addWorkflowExecutionStartedEvent(builder, we, "wType", "delete-history-archiver-timeout", nil, 100, 10, "identity")
+	serializedHistory, err := builder.hBuilder.Serialize()
+	s.Nil(err)
+
+	s.mockHistoryMgr.On("GetWorkflowExecutionHistory", mock.Anything).Return(&persistence.GetWorkflowExecutionHistoryResponse{
+		Events: []persistence.SerializedHistoryEventBatch{*serializedHistory},
+	}, nil).Once()
+	releaseCh := make(chan struct{})
+	defer close(releaseCh)
+	mockArchiver.On("PutHistoryBlob", mock.Anything).Return(nil).WaitUntil(releaseCh).Once()
+
+	// a stuck archiver only holds the worker until the timeout, nothing gets deleted and the task is retried instead
+	processor := newTimerQueueProcessor(s.mockShard, s.mockHistoryEngine, s.mockExecutionMgr, s.logger).(*timerQueueProcessorImpl)
+	err = processor.processDeleteHistoryEvent(&persistence.TimerTaskInfo{
+		DomainID:            domainID,
+		WorkflowID:          we.GetWorkflowId(),
+		RunID:               we.GetRunId(),
+		TaskID:              int64(100),
+		TaskType:            persistence.TaskTypeDeleteHistoryEvent,
+		VisibilityTimestamp: time.Now(),
+	})
+	s.Equal(errArchivalTimedOut, err)
+	s.mockExecutionMgr.AssertNotCalled(s.T(), "DeleteWorkflowExecution", mock.Anything)
+	s.mockHistoryMgr.AssertNotCalled(s.T(), "DeleteWorkflowExecutionHistory", mock.Anything)
+}
+
+// setupArchival enables archival on the domain and returns the archiver the history engine uploads histories to
+func (s *timerQueueProcessor2Suite) setupArchival(domainID string) *mocks.Archiver {
+	metadataMgr := &mocks.MetadataManager{}
+	metadataMgr.On("GetDomain", mock.Anything).Return(&persistence.GetDomainResponse{
+		Info: &persistence.DomainInfo{ID: domainID, Name: "archival-domain"},
+		Config: &persistence.DomainConfig{
+			Retention:      1,
+			ArchivalBucket: "archival-bucket",
+			ArchivalStatus: workflow.ArchivalStatusEnabled,
+		},
+		ReplicationConfig: &persistence.DomainReplicationConfig{ActiveClusterName: cluster.TestCurrentClusterName},
+	}, nil)
+	s.mockShard.(*shardContextImpl).domainCache = cache.NewDomainCache(metadataMgr, s.mockClusterMetadata, s.logger)
+
+	mockArchiver := &mocks.Archiver{}
+	s.mockHistoryEngine.archiver = mockArchiver
+	return mockArchiver
+}
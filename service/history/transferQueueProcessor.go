@@ -21,6 +21,7 @@
 package history
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/uber-common/bark"
@@ -38,6 +39,7 @@ import (
 	"github.com/uber/cadence/common/logging"
 	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/service/dynamicconfig"
 )
 
 const identityHistoryService = "history-service"
@@ -54,6 +56,13 @@ type (
 		historyService    *historyEngineImpl
 		*queueProcessorBase
 	}
+
+	// childExecutionToClose is a started child execution which needs its child policy applied after its parent closed
+	childExecutionToClose struct {
+		domainID    string
+		execution   workflow.WorkflowExecution
+		childPolicy workflow.ChildPolicy
+	}
 )
 
 var (
@@ -64,14 +73,15 @@ func newTransferQueueProcessor(shard ShardContext, historyService *historyEngine
 	visibilityMgr persistence.VisibilityManager, matching matching.Client, historyClient hc.Client) transferQueueProcessor {
 	executionManager := shard.GetExecutionManager()
 	config := shard.GetConfig()
+	shardFilter := dynamicconfig.ShardIDFilter(shard.GetShardID())
 	options := &QueueProcessorOptions{
-		BatchSize:           config.TransferTaskBatchSize,
-		WorkerCount:         config.TransferTaskWorkerCount,
-		MaxPollRPS:          config.TransferProcessorMaxPollRPS,
-		MaxPollInterval:     config.TransferProcessorMaxPollInterval,
-		UpdateAckInterval:   config.TransferProcessorUpdateAckInterval,
-		ForceUpdateInterval: config.TransferProcessorForceUpdateInterval,
-		MaxRetryCount:       config.TransferTaskMaxRetryCount,
+		BatchSize:           func() int { return config.TransferTaskBatchSize(shardFilter) },
+		WorkerCount:         func() int { return config.TransferTaskWorkerCount(shardFilter) },
+		MaxPollRPS:          func() int { return config.TransferProcessorMaxPollRPS(shardFilter) },
+		MaxPollInterval:     func() time.Duration { return config.TransferProcessorMaxPollInterval(shardFilter) },
+		UpdateAckInterval:   func() time.Duration { return config.TransferProcessorUpdateAckInterval(shardFilter) },
+		ForceUpdateInterval: func() time.Duration { return config.TransferProcessorForceUpdateInterval(shardFilter) },
+		MaxRetryCount:       func() int { return config.TransferTaskMaxRetryCount(shardFilter) },
 		MetricScope:         metrics.TransferQueueProcessorScope,
 	}
 
@@ -122,6 +132,9 @@ func (t *transferQueueProcessorImpl) Process(qTask queueTaskInfo) error {
 	case persistence.TransferTaskTypeStartChildExecution:
 		scope = metrics.TransferTaskStartChildExecutionScope
 		err = t.processStartChildExecution(task)
+	case persistence.TransferTaskTypeUpsertWorkflowSearchAttributes:
+		scope = metrics.TransferTaskUpsertWorkflowSearchAttributesScope
+		err = t.processUpsertWorkflowSearchAttributes(task)
 	default:
 		err = errUnknownTransferTask
 	}
@@ -136,7 +149,7 @@ func (t *transferQueueProcessorImpl) ReadTasks(readLevel int64) ([]queueTaskInfo
 	response, err := t.executionManager.GetTransferTasks(&persistence.GetTransferTasksRequest{
 		ReadLevel:    readLevel,
 		MaxReadLevel: t.shard.GetTransferMaxReadLevel(),
-		BatchSize:    t.options.BatchSize,
+		BatchSize:    t.options.BatchSize(),
 	})
 
 	if err != nil {
@@ -181,6 +194,7 @@ func (t *transferQueueProcessorImpl) processActivityTask(task *persistence.Trans
 	var msBuilder *mutableStateBuilder
 	msBuilder, err = context.loadWorkflowExecution()
 	timeout := int32(0)
+	priority := int32(0)
 	if err != nil {
 		if _, ok := err.(*workflow.EntityNotExistsError); ok {
 			// this could happen if this is a duplicate processing of the task, and the execution has already completed.
@@ -191,6 +205,7 @@ func (t *transferQueueProcessorImpl) processActivityTask(task *persistence.Trans
 
 	if ai, found := msBuilder.GetActivityInfo(task.ScheduleID); found {
 		timeout = ai.ScheduleToStartTimeout
+		priority = ai.Priority
 	} else {
 		logging.LogDuplicateTransferTaskEvent(t.logger, persistence.TransferTaskTypeActivityTask, task.TaskID, task.ScheduleID)
 	}
@@ -206,6 +221,7 @@ func (t *transferQueueProcessorImpl) processActivityTask(task *persistence.Trans
 			TaskList:                      taskList,
 			ScheduleId:                    &task.ScheduleID,
 			ScheduleToStartTimeoutSeconds: common.Int32Ptr(timeout),
+			Priority:                      common.Int32Ptr(priority),
 		})
 	}
 
@@ -247,6 +263,9 @@ func (t *transferQueueProcessorImpl) processDecisionTask(task *persistence.Trans
 	timeout := msBuilder.executionInfo.WorkflowTimeout
 	wfTypeName := msBuilder.executionInfo.WorkflowTypeName
 	startTimestamp := msBuilder.executionInfo.StartTimestamp
+	searchAttributes := msBuilder.executionInfo.SearchAttributes
+	priority := msBuilder.executionInfo.Priority
+	buildID := msBuilder.getPinnedBuildID()
 	if msBuilder.isStickyTaskListEnabled() {
 		taskList.Name = common.StringPtr(msBuilder.executionInfo.StickyTaskList)
 		taskList.Kind = common.TaskListKindPtr(workflow.TaskListKindSticky)
@@ -262,6 +281,8 @@ func (t *transferQueueProcessorImpl) processDecisionTask(task *persistence.Trans
 		TaskList:                      taskList,
 		ScheduleId:                    &task.ScheduleID,
 		ScheduleToStartTimeoutSeconds: common.Int32Ptr(timeout),
+		Priority:                      common.Int32Ptr(priority),
+		BuildID:                       common.StringPtr(buildID),
 	})
 
 	if err != nil {
@@ -269,7 +290,7 @@ func (t *transferQueueProcessorImpl) processDecisionTask(task *persistence.Trans
 	}
 
 	if task.ScheduleID == firstEventID+1 {
-		err = t.recordWorkflowExecutionStarted(execution, task, wfTypeName, startTimestamp, timeout)
+		err = t.recordWorkflowExecutionStarted(execution, task, wfTypeName, startTimestamp, timeout, searchAttributes)
 	}
 
 	if err != nil {
@@ -323,6 +344,12 @@ func (t *transferQueueProcessorImpl) processCloseExecution(task *persistence.Tra
 	workflowCloseTimestamp := msBuilder.getLastUpdatedTimestamp()
 	workflowCloseStatus := getWorkflowExecutionCloseStatus(msBuilder.executionInfo.CloseStatus)
 	workflowHistoryLength := msBuilder.GetNextEventID()
+	searchAttributes := msBuilder.executionInfo.SearchAttributes
+
+	children, err := t.getChildExecutionsToClose(msBuilder)
+	if err != nil {
+		return err
+	}
 
 	// release the context lock since we no longer need mutable state builder and
 	// the rest of logic is making RPC call, which takes time.
@@ -353,6 +380,14 @@ func (t *transferQueueProcessorImpl) processCloseExecution(task *persistence.Tra
 		return err
 	}
 
+	// Apply the child policy to the children which are still running.  Children can belong to other shards so
+	// requests are routed through the history client.
+	for _, child := range children {
+		if err = t.applyChildPolicy(task, child); err != nil {
+			return err
+		}
+	}
+
 	// Record closing in visibility store
 	retentionSeconds := int64(0)
 	domainEntry, err := t.shard.GetDomainCache().GetDomainByID(task.DomainID)
@@ -375,9 +410,148 @@ func (t *transferQueueProcessorImpl) processCloseExecution(task *persistence.Tra
 		Status:           workflowCloseStatus,
 		HistoryLength:    workflowHistoryLength,
 		RetentionSeconds: retentionSeconds,
+		SearchAttributes: searchAttributes,
 	})
 }
 
+func (t *transferQueueProcessorImpl) processUpsertWorkflowSearchAttributes(task *persistence.TransferTaskInfo) error {
+	t.metricsClient.IncCounter(metrics.TransferTaskUpsertWorkflowSearchAttributesScope, metrics.TaskRequests)
+	sw := t.metricsClient.StartTimer(metrics.TransferTaskUpsertWorkflowSearchAttributesScope, metrics.TaskLatency)
+	defer sw.Stop()
+
+	domainID := task.DomainID
+	execution := workflow.WorkflowExecution{WorkflowId: common.StringPtr(task.WorkflowID),
+		RunId: common.StringPtr(task.RunID)}
+
+	context, release, err := t.cache.getOrCreateWorkflowExecution(domainID, execution)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	msBuilder, err := context.loadWorkflowExecution()
+	if err != nil {
+		if _, ok := err.(*workflow.EntityNotExistsError); ok {
+			// this could happen if this is a duplicate processing of the task, and the execution has already completed.
+			return nil
+		}
+		return err
+	}
+
+	// The close execution task records the final search attributes of a closed execution
+	if !msBuilder.isWorkflowExecutionRunning() {
+		return nil
+	}
+
+	request := &persistence.UpsertWorkflowExecutionRequest{
+		DomainUUID:       domainID,
+		Execution:        execution,
+		WorkflowTypeName: msBuilder.executionInfo.WorkflowTypeName,
+		StartTimestamp:   msBuilder.executionInfo.StartTimestamp.UnixNano(),
+		WorkflowTimeout:  int64(msBuilder.executionInfo.WorkflowTimeout),
+		UpdateTimestamp:  msBuilder.getLastUpdatedTimestamp(),
+		SearchAttributes: msBuilder.executionInfo.SearchAttributes,
+	}
+
+	// release the context lock since we no longer need mutable state builder and
+	// the rest of logic is making RPC call, which takes time.
+	release()
+	return t.visibilityManager.UpsertWorkflowExecution(request)
+}
+
+// getChildExecutionsToClose returns the started child executions of a closed workflow execution.  Children which are
+// not started yet are skipped, as their start is abandoned once the parent is closed.
+func (t *transferQueueProcessorImpl) getChildExecutionsToClose(
+	msBuilder *mutableStateBuilder) ([]*childExecutionToClose, error) {
+	var children []*childExecutionToClose
+	for initiatedID, ci := range msBuilder.pendingChildExecutionInfoIDs {
+		if ci.StartedID == emptyEventID {
+			continue
+		}
+
+		initiatedEvent, ok := msBuilder.GetChildExecutionInitiatedEvent(initiatedID)
+		if !ok {
+			return nil, &workflow.InternalServiceError{Message: "Unable to get child execution initiated event."}
+		}
+		startedEvent, ok := msBuilder.GetChildExecutionStartedEvent(initiatedID)
+		if !ok {
+			return nil, &workflow.InternalServiceError{Message: "Unable to get child execution started event."}
+		}
+		initiatedAttributes := initiatedEvent.StartChildWorkflowExecutionInitiatedEventAttributes
+		startedAttributes := startedEvent.ChildWorkflowExecutionStartedEventAttributes
+
+		domainID := msBuilder.executionInfo.DomainID
+		if initiatedAttributes.GetDomain() != "" {
+			domainEntry, err := t.shard.GetDomainCache().GetDomain(initiatedAttributes.GetDomain())
+			if err != nil {
+				return nil, err
+			}
+			domainID = domainEntry.GetInfo().ID
+		}
+
+		children = append(children, &childExecutionToClose{
+			domainID: domainID,
+			execution: workflow.WorkflowExecution{
+				WorkflowId: startedAttributes.WorkflowExecution.WorkflowId,
+				RunId:      startedAttributes.WorkflowExecution.RunId,
+			},
+			childPolicy: initiatedAttributes.GetChildPolicy(),
+		})
+	}
+
+	return children, nil
+}
+
+// applyChildPolicy terminates, cancels or abandons a child execution of a closed workflow execution according to
+// the child policy it was started with
+func (t *transferQueueProcessorImpl) applyChildPolicy(task *persistence.TransferTaskInfo,
+	child *childExecutionToClose) error {
+	var op func() error
+	switch child.childPolicy {
+	case workflow.ChildPolicyTerminate:
+		t.metricsClient.IncCounter(metrics.TransferTaskCloseExecutionScope, metrics.ChildPolicyTerminateCounter)
+		op = func() error {
+			return t.historyClient.TerminateWorkflowExecution(nil, &history.TerminateWorkflowExecutionRequest{
+				DomainUUID: common.StringPtr(child.domainID),
+				TerminateRequest: &workflow.TerminateWorkflowExecutionRequest{
+					Domain:            common.StringPtr(child.domainID),
+					WorkflowExecution: &child.execution,
+					Reason:            common.StringPtr("by parent close policy"),
+					Identity:          common.StringPtr(identityHistoryService),
+				},
+			})
+		}
+
+	case workflow.ChildPolicyRequestCancel:
+		t.metricsClient.IncCounter(metrics.TransferTaskCloseExecutionScope, metrics.ChildPolicyRequestCancelCounter)
+		op = func() error {
+			return t.historyClient.RequestCancelWorkflowExecution(nil, &history.RequestCancelWorkflowExecutionRequest{
+				DomainUUID: common.StringPtr(child.domainID),
+				CancelRequest: &workflow.RequestCancelWorkflowExecutionRequest{
+					Domain:            common.StringPtr(child.domainID),
+					WorkflowExecution: &child.execution,
+					Identity:          common.StringPtr(identityHistoryService),
+					// Use the close task to dedupe RequestCancelWorkflowExecution calls
+					RequestId: common.StringPtr(fmt.Sprintf("%v:%v", task.RunID, task.TaskID)),
+				},
+			})
+		}
+
+	default:
+		t.metricsClient.IncCounter(metrics.TransferTaskCloseExecutionScope, metrics.ChildPolicyAbandonCounter)
+		return nil
+	}
+
+	err := backoff.Retry(op, persistenceOperationRetryPolicy, common.IsPersistenceTransientError)
+	switch err.(type) {
+	case *workflow.EntityNotExistsError, *workflow.CancellationAlreadyRequestedError:
+		// The child is already closed or its cancellation is already requested
+		return nil
+	}
+
+	return err
+}
+
 func (t *transferQueueProcessorImpl) processCancelExecution(task *persistence.TransferTaskInfo) error {
 	t.metricsClient.IncCounter(metrics.TransferTaskCancelExecutionScope, metrics.TaskRequests)
 	sw := t.metricsClient.StartTimer(metrics.TransferTaskCancelExecutionScope, metrics.TaskLatency)
@@ -675,6 +849,8 @@ func (t *transferQueueProcessorImpl) processStartChildExecution(task *persistenc
 					// Use the same request ID to dedupe StartWorkflowExecution calls
 					RequestId:             common.StringPtr(ci.CreateRequestID),
 					WorkflowIdReusePolicy: attributes.WorkflowIdReusePolicy,
+					RetryPolicy:           attributes.RetryPolicy,
+					CronSchedule:          attributes.CronSchedule,
 				},
 				ParentExecutionInfo: &history.ParentExecutionInfo{
 					DomainUUID: common.StringPtr(domainID),
@@ -684,6 +860,7 @@ func (t *transferQueueProcessorImpl) processStartChildExecution(task *persistenc
 					},
 					InitiatedId: common.Int64Ptr(initiatedEventID),
 				},
+				Priority: attributes.Priority,
 			}
 
 			var startResponse *workflow.StartWorkflowExecutionResponse
@@ -727,7 +904,7 @@ func (t *transferQueueProcessorImpl) processStartChildExecution(task *persistenc
 
 func (t *transferQueueProcessorImpl) recordWorkflowExecutionStarted(
 	execution workflow.WorkflowExecution, task *persistence.TransferTaskInfo, wfTypeName string,
-	startTimestamp time.Time, timeout int32,
+	startTimestamp time.Time, timeout int32, searchAttributes map[string][]byte,
 ) error {
 	err := t.visibilityManager.RecordWorkflowExecutionStarted(&persistence.RecordWorkflowExecutionStartedRequest{
 		DomainUUID:       task.DomainID,
@@ -735,6 +912,7 @@ func (t *transferQueueProcessorImpl) recordWorkflowExecutionStarted(
 		WorkflowTypeName: wfTypeName,
 		StartTimestamp:   startTimestamp.UnixNano(),
 		WorkflowTimeout:  int64(timeout),
+		SearchAttributes: searchAttributes,
 	})
 
 	return err
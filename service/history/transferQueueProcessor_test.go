@@ -31,6 +31,7 @@ import (
 	"github.com/pborman/uuid"
 	"github.com/uber-common/bark"
 	"github.com/uber-go/tally"
+	"github.com/uber/cadence/.gen/go/history"
 	m "github.com/uber/cadence/.gen/go/matching"
 	workflow "github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/common"
@@ -659,6 +660,109 @@ workerPump:
 	}
 }
 
+func (s *transferQueueProcessorSuite) TestUpsertWorkflowSearchAttributesTransferTask() {
+	domainID := testDomainActiveID
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("upsert-search-attributes-transfertasks-test"),
+		RunId:      common.StringPtr("5b0b0a57-25c3-4b3b-9d50-4a5e35c8f4a1"),
+	}
+	taskList := "upsert-search-attributes-transfertasks-queue"
+	identity := "upsert-search-attributes-transfertasks-test"
+	searchAttributes := map[string][]byte{"CustomKeywordField": []byte(`"keyword"`)}
+
+	_, err0 := s.CreateWorkflowExecution(domainID, workflowExecution, taskList, "wType", 20, 10, nil, 3, 0, 2, nil)
+	s.Nil(err0, "No error expected.")
+
+	builder := newMutableStateBuilder(s.ShardContext.GetConfig(), s.logger)
+	info1, _ := s.GetWorkflowExecutionInfo(domainID, workflowExecution)
+	builder.Load(info1)
+	startedEvent := addDecisionTaskStartedEvent(builder, int64(2), taskList, identity)
+	completedEvent := addDecisionTaskCompletedEvent(builder, int64(2), *startedEvent.EventId, nil, identity)
+	addUpsertWorkflowSearchAttributesEvent(builder, *completedEvent.EventId, searchAttributes)
+
+	transferTasks := []persistence.Task{&persistence.UpsertWorkflowSearchAttributesTask{
+		TaskID: s.GetNextSequenceNumber(),
+	}}
+	updatedInfo := copyWorkflowExecutionInfo(builder.executionInfo)
+	err1 := s.UpdateWorkflowExecutionWithTransferTasks(updatedInfo, int64(3), transferTasks, nil)
+	s.Nil(err1, "No error expected.")
+
+	tasksCh := make(chan queueTaskInfo, 10)
+	s.processor.processBatch(tasksCh)
+workerPump:
+	for {
+		select {
+		case t := <-tasksCh:
+			task := t.(*persistence.TransferTaskInfo)
+			if task.TaskType == persistence.TransferTaskTypeDecisionTask {
+				s.mockMatching.On("AddDecisionTask", mock.Anything, mock.Anything).Once().Return(nil)
+				s.mockVisibilityMgr.On("RecordWorkflowExecutionStarted", mock.Anything).Once().Return(nil)
+			} else if task.TaskType == persistence.TransferTaskTypeUpsertWorkflowSearchAttributes {
+				s.mockVisibilityMgr.On("UpsertWorkflowExecution", mock.MatchedBy(
+					func(request *persistence.UpsertWorkflowExecutionRequest) bool {
+						return request.DomainUUID == domainID &&
+							request.Execution.GetRunId() == workflowExecution.GetRunId() &&
+							request.WorkflowTypeName == "wType" &&
+							request.WorkflowTimeout == int64(20) &&
+							string(request.SearchAttributes["CustomKeywordField"]) == `"keyword"`
+					},
+				)).Once().Return(nil)
+			}
+			s.processor.processWithRetry(task)
+		default:
+			break workerPump
+		}
+	}
+}
+
+func (s *transferQueueProcessorSuite) TestUpsertWorkflowSearchAttributesTransferTaskExecutionClosed() {
+	domainID := testDomainActiveID
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("upsert-search-attributes-closed-transfertasks-test"),
+		RunId:      common.StringPtr("b8e4e1b2-0f7e-4b0c-8f4e-1d2b6c3f7a90"),
+	}
+	taskList := "upsert-search-attributes-closed-transfertasks-queue"
+	identity := "upsert-search-attributes-closed-transfertasks-test"
+	searchAttributes := map[string][]byte{"CustomKeywordField": []byte(`"keyword"`)}
+
+	_, err0 := s.CreateWorkflowExecution(domainID, workflowExecution, taskList, "wType", 20, 10, nil, 3, 0, 2, nil)
+	s.Nil(err0, "No error expected.")
+
+	builder := newMutableStateBuilder(s.ShardContext.GetConfig(), s.logger)
+	info1, _ := s.GetWorkflowExecutionInfo(domainID, workflowExecution)
+	builder.Load(info1)
+	startedEvent := addDecisionTaskStartedEvent(builder, int64(2), taskList, identity)
+	completedEvent := addDecisionTaskCompletedEvent(builder, int64(2), *startedEvent.EventId, nil, identity)
+	addUpsertWorkflowSearchAttributesEvent(builder, *completedEvent.EventId, searchAttributes)
+	addCompleteWorkflowEvent(builder, *completedEvent.EventId, []byte("result"))
+
+	// the close execution task records the search attributes of the closed execution instead
+	transferTasks := []persistence.Task{&persistence.UpsertWorkflowSearchAttributesTask{
+		TaskID: s.GetNextSequenceNumber(),
+	}}
+	updatedInfo := copyWorkflowExecutionInfo(builder.executionInfo)
+	err1 := s.UpdateWorkflowExecutionWithTransferTasks(updatedInfo, int64(3), transferTasks, nil)
+	s.Nil(err1, "No error expected.")
+
+	tasksCh := make(chan queueTaskInfo, 10)
+	s.processor.processBatch(tasksCh)
+workerPump:
+	for {
+		select {
+		case t := <-tasksCh:
+			task := t.(*persistence.TransferTaskInfo)
+			if task.TaskType == persistence.TransferTaskTypeDecisionTask {
+				s.mockMatching.On("AddDecisionTask", mock.Anything, mock.Anything).Once().Return(nil)
+				s.mockVisibilityMgr.On("RecordWorkflowExecutionStarted", mock.Anything).Once().Return(nil)
+			}
+			s.processor.processWithRetry(task)
+		default:
+			break workerPump
+		}
+	}
+	s.mockVisibilityMgr.AssertNotCalled(s.T(), "UpsertWorkflowExecution", mock.Anything)
+}
+
 func (s *transferQueueProcessorSuite) createChildExecutionState(domain, domainID string,
 	workflowExecution workflow.WorkflowExecution, taskList, identity string) chan queueTaskInfo {
 	_, err0 := s.CreateWorkflowExecution(domainID, workflowExecution, taskList, "wType", 20, 10, nil, 3, 0, 2, nil)
@@ -712,6 +816,7 @@ func createAddRequestFromTask(task *persistence.TransferTaskInfo, scheduleToStar
 			TaskList:                      taskList,
 			ScheduleId:                    &task.ScheduleID,
 			ScheduleToStartTimeoutSeconds: common.Int32Ptr(scheduleToStartTimeout),
+			Priority:                      common.Int32Ptr(0),
 		}
 	} else if task.TaskType == persistence.TransferTaskTypeDecisionTask {
 		res = &m.AddDecisionTaskRequest{
@@ -720,7 +825,65 @@ func createAddRequestFromTask(task *persistence.TransferTaskInfo, scheduleToStar
 			TaskList:                      taskList,
 			ScheduleId:                    &task.ScheduleID,
 			ScheduleToStartTimeoutSeconds: common.Int32Ptr(scheduleToStartTimeout),
+			Priority:                      common.Int32Ptr(0),
+			BuildID:                       common.StringPtr(""),
 		}
 	}
 	return res
 }
+
+func (s *transferQueueProcessorSuite) TestCloseExecutionChildPolicy() {
+	domain := testDomainActiveName
+	domainID := testDomainActiveID
+	workflowExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("close-execution-child-policy-test"),
+		RunId:      common.StringPtr("0d00698f-08e1-4d36-a3e2-3bf109f5d2d6"),
+	}
+	taskList := "close-execution-child-policy-queue"
+	identity := "close-execution-child-policy-test"
+
+	builder := newMutableStateBuilder(s.ShardContext.GetConfig(), s.logger)
+	addWorkflowExecutionStartedEvent(builder, workflowExecution, "wType", taskList, []byte("input"), 100, 10, identity)
+	di := addDecisionTaskScheduledEvent(builder)
+	startedEvent := addDecisionTaskStartedEvent(builder, di.ScheduleID, taskList, identity)
+	completedEvent := addDecisionTaskCompletedEvent(builder, di.ScheduleID, *startedEvent.EventId, nil, identity)
+
+	childExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr("close-execution-child-policy-test-child-workflow-id"),
+		RunId:      common.StringPtr("e1d7d1a6-4f0b-4d6c-9a2e-45fd2f0dc0b4"),
+	}
+	_, ci := addStartChildWorkflowExecutionInitiatedEvent(builder, *completedEvent.EventId, uuid.New(), domain,
+		childExecution.GetWorkflowId(), "child-workflow-type", taskList, nil, 100, 10)
+	builder.AddChildWorkflowExecutionStartedEvent(common.StringPtr(domain), &childExecution,
+		&workflow.WorkflowType{Name: common.StringPtr("child-workflow-type")}, ci.InitiatedID)
+	// a child which is not started yet is not affected by the child policy
+	addStartChildWorkflowExecutionInitiatedEvent(builder, *completedEvent.EventId, uuid.New(), domain,
+		"close-execution-child-policy-test-pending-child", "child-workflow-type", taskList, nil, 100, 10)
+
+	children, err := s.processor.getChildExecutionsToClose(builder)
+	s.Nil(err)
+	s.Equal(1, len(children))
+	s.Equal(domainID, children[0].domainID)
+	s.Equal(childExecution, children[0].execution)
+	s.Equal(workflow.ChildPolicyTerminate, children[0].childPolicy)
+
+	task := &persistence.TransferTaskInfo{
+		DomainID:   domainID,
+		WorkflowID: workflowExecution.GetWorkflowId(),
+		RunID:      workflowExecution.GetRunId(),
+		TaskID:     1,
+	}
+	s.mockHistoryClient.On("TerminateWorkflowExecution", mock.Anything, mock.MatchedBy(
+		func(request *history.TerminateWorkflowExecutionRequest) bool {
+			return request.GetDomainUUID() == domainID &&
+				request.TerminateRequest.WorkflowExecution.GetRunId() == childExecution.GetRunId()
+		})).Return(&workflow.EntityNotExistsError{}).Once()
+	s.Nil(s.processor.applyChildPolicy(task, children[0]))
+
+	children[0].childPolicy = workflow.ChildPolicyRequestCancel
+	s.mockHistoryClient.On("RequestCancelWorkflowExecution", mock.Anything, mock.Anything).Return(nil).Once()
+	s.Nil(s.processor.applyChildPolicy(task, children[0]))
+
+	children[0].childPolicy = workflow.ChildPolicyAbandon
+	s.Nil(s.processor.applyChildPolicy(task, children[0]))
+}
@@ -25,6 +25,7 @@ import (
 	"sync"
 	"time"
 
+	h "github.com/uber/cadence/.gen/go/history"
 	workflow "github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/backoff"
@@ -134,6 +135,30 @@ func (c *workflowExecutionContext) updateWorkflowExecution(transferTasks []persi
 
 		c.msBuilder.ApplyReplicationStateUpdates(domainEntry.GetFailoverVersion())
 	}
+
+	return c.update(transferTasks, timerTasks, transactionID, createReplicationTask)
+}
+
+func (c *workflowExecutionContext) replicateWorkflowExecution(request *h.ReplicateEventsRequest,
+	transferTasks []persistence.Task, timerTasks []persistence.Task, lastEventID, transactionID int64) (errRet error) {
+
+	defer func() {
+		if errRet != nil {
+			// Clear all cached state in case of error
+			c.clear()
+		}
+	}()
+
+	nextEventID := lastEventID + 1
+	c.msBuilder.executionInfo.NextEventID = nextEventID
+	c.msBuilder.ApplyReplicationStateUpdatesFromRemote(request.GetSourceCluster(), request.GetVersion(), lastEventID)
+
+	// Events received from remote cluster are never replicated back, so no replication task is created
+	return c.update(transferTasks, timerTasks, transactionID, false)
+}
+
+func (c *workflowExecutionContext) update(transferTasks []persistence.Task, timerTasks []persistence.Task,
+	transactionID int64, createReplicationTask bool) error {
 	// Take a snapshot of all updates we have accumulated for this execution
 	updates, err := c.msBuilder.CloseUpdateSession(createReplicationTask)
 	if err != nil {
@@ -0,0 +1,422 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package history
+
+import (
+	"fmt"
+
+	"github.com/pborman/uuid"
+	"github.com/uber-common/bark"
+	h "github.com/uber/cadence/.gen/go/history"
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/logging"
+	"github.com/uber/cadence/common/persistence"
+)
+
+const (
+	resetHistoryPageSize = 100
+)
+
+type (
+	// workflowResetor resets a workflow execution to one of its completed decisions.  It creates a new run whose
+	// history is copied from the base run up to the decision, fails that decision and re-applies the signals received
+	// after it.  The new run replaces the base run as the current run of the workflow.
+	workflowResetor struct {
+		shard         ShardContext
+		historyEngine *historyEngineImpl
+		historyCache  *historyCache
+		historyMgr    persistence.HistoryManager
+		logger        bark.Logger
+	}
+)
+
+func newWorkflowResetor(shard ShardContext, historyEngine *historyEngineImpl, historyCache *historyCache,
+	historyMgr persistence.HistoryManager, logger bark.Logger) *workflowResetor {
+	return &workflowResetor{
+		shard:         shard,
+		historyEngine: historyEngine,
+		historyCache:  historyCache,
+		historyMgr:    historyMgr,
+		logger: logger.WithFields(bark.Fields{
+			logging.TagWorkflowComponent: logging.TagValueWorkflowResetorComponent,
+		}),
+	}
+}
+
+// ResetWorkflowExecution terminates the base run if it is still running and starts a new run from the given
+// DecisionTaskCompleted event.  Requests are deduplicated by request ID.
+func (r *workflowResetor) ResetWorkflowExecution(resetRequest *h.ResetWorkflowExecutionRequest) (
+	*workflow.ResetWorkflowExecutionResponse, error) {
+	domainID, err := getDomainUUID(resetRequest.DomainUUID)
+	if err != nil {
+		return nil, err
+	}
+	request := resetRequest.ResetRequest
+	baseExecution := workflow.WorkflowExecution{
+		WorkflowId: request.WorkflowExecution.WorkflowId,
+		RunId:      request.WorkflowExecution.RunId,
+	}
+
+	baseContext, baseRelease, err := r.historyCache.getOrCreateWorkflowExecution(domainID, baseExecution)
+	if err != nil {
+		return nil, err
+	}
+	defer baseRelease()
+
+	baseMutableState, err := baseContext.loadWorkflowExecution()
+	if err != nil {
+		return nil, err
+	}
+	baseInfo := baseMutableState.executionInfo
+
+	// The new run is created with the request ID of the reset, so a retried request resolves to the same run
+	if baseInfo.CreateRequestID == request.GetRequestId() {
+		return &workflow.ResetWorkflowExecutionResponse{RunId: common.StringPtr(baseInfo.RunID)}, nil
+	}
+
+	if baseMutableState.replicationState != nil {
+		return nil, &workflow.BadRequestError{Message: "Reset of workflow executions in global domains is not supported."}
+	}
+	if baseMutableState.hasParentExecution() {
+		return nil, &workflow.BadRequestError{Message: "Reset of child workflow executions is not supported."}
+	}
+	if request.GetDecisionFinishEventId() >= baseMutableState.GetNextEventID() {
+		return nil, &workflow.BadRequestError{Message: "DecisionFinishEventId is beyond the history of the execution."}
+	}
+
+	if !baseMutableState.isWorkflowExecutionRunning() {
+		current, err := r.historyCache.getCurrentExecutionWithRetry(&persistence.GetCurrentExecutionRequest{
+			DomainID:   domainID,
+			WorkflowID: baseInfo.WorkflowID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if current.StartRequestID == request.GetRequestId() {
+			return &workflow.ResetWorkflowExecutionResponse{RunId: common.StringPtr(current.RunID)}, nil
+		}
+		if current.RunID != baseInfo.RunID {
+			return nil, &workflow.BadRequestError{Message: "Only the current run of a workflow can be reset."}
+		}
+	}
+
+	history, err := r.getHistory(domainID, baseContext.workflowExecution, baseMutableState.GetNextEventID())
+	if err != nil {
+		return nil, err
+	}
+
+	newExecution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(baseInfo.WorkflowID),
+		RunId:      common.StringPtr(uuid.New()),
+	}
+	newMutableState, di, err := r.buildNewMutableState(domainID, newExecution, request, history)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new run is created along with its pending activities and timers and the tasks to dispatch them in a single
+	// write, so a failed reset leaves nothing behind and a retried reset finds either no new run or a complete one
+	tBuilder := r.historyEngine.getTimerBuilder(&newExecution)
+	transferTasks, timerTasks, err := r.getNewRunTasks(newMutableState, tBuilder, di)
+	if err != nil {
+		return nil, err
+	}
+	timerTasks = append(tBuilder.GetWorkflowStartTimerTasks(newMutableState.executionInfo.WorkflowTimeout, 0,
+		persistence.WorkflowBackoffTimeoutTypeCron), timerTasks...)
+	var activityInfos []*persistence.ActivityInfo
+	for _, ai := range newMutableState.pendingActivityInfoIDs {
+		activityInfos = append(activityInfos, ai)
+	}
+	var timerInfos []*persistence.TimerInfo
+	for _, ti := range newMutableState.pendingTimerInfoIDs {
+		timerInfos = append(timerInfos, ti)
+	}
+	createRequest := &persistence.CreateWorkflowExecutionRequest{
+		RequestID:                   request.GetRequestId(),
+		DomainID:                    domainID,
+		Execution:                   newExecution,
+		TaskList:                    newMutableState.executionInfo.TaskList,
+		WorkflowTypeName:            newMutableState.executionInfo.WorkflowTypeName,
+		WorkflowTimeout:             newMutableState.executionInfo.WorkflowTimeout,
+		DecisionTimeoutValue:        newMutableState.executionInfo.DecisionTimeoutValue,
+		ExecutionContext:            nil,
+		NextEventID:                 newMutableState.GetNextEventID(),
+		LastProcessedEvent:          newMutableState.executionInfo.LastProcessedEvent,
+		TransferTasks:               transferTasks,
+		TimerTasks:                  timerTasks,
+		DecisionScheduleID:          di.ScheduleID,
+		DecisionStartedID:           di.StartedID,
+		DecisionStartToCloseTimeout: di.DecisionTimeout,
+		ContinueAsNew:               true,
+		PreviousRunID:               baseInfo.RunID,
+		Attempt:                     newMutableState.executionInfo.Attempt,
+		HasRetryPolicy:              newMutableState.executionInfo.HasRetryPolicy,
+		InitialInterval:             newMutableState.executionInfo.InitialInterval,
+		BackoffCoefficient:          newMutableState.executionInfo.BackoffCoefficient,
+		MaximumInterval:             newMutableState.executionInfo.MaximumInterval,
+		ExpirationTime:              newMutableState.executionInfo.ExpirationTime,
+		MaximumAttempts:             newMutableState.executionInfo.MaximumAttempts,
+		NonRetriableErrors:          newMutableState.executionInfo.NonRetriableErrors,
+		CronSchedule:                newMutableState.executionInfo.CronSchedule,
+		SearchAttributes:            newMutableState.executionInfo.SearchAttributes,
+		Priority:                    newMutableState.executionInfo.Priority,
+		BuildID:                     newMutableState.executionInfo.BuildID,
+		ActivityInfos:               activityInfos,
+		TimerInfos:                  timerInfos,
+	}
+
+	var baseTimerTasks []persistence.Task
+	if baseMutableState.isWorkflowExecutionRunning() {
+		baseTimerTasks, err = r.terminateBaseRun(baseContext, baseMutableState, newMutableState, request,
+			createRequest)
+	} else {
+		err = r.startNewRun(newMutableState, createRequest)
+	}
+	if err != nil {
+		baseContext.clear()
+		return nil, err
+	}
+	r.historyEngine.timerProcessor.NotifyNewTimers(append(baseTimerTasks, timerTasks...))
+
+	return &workflow.ResetWorkflowExecutionResponse{RunId: newExecution.RunId}, nil
+}
+
+// getHistory reads all the history events of the execution before nextEventID
+func (r *workflowResetor) getHistory(domainID string, execution workflow.WorkflowExecution,
+	nextEventID int64) ([]*workflow.HistoryEvent, error) {
+	var history []*workflow.HistoryEvent
+	var nextPageToken []byte
+	for {
+		response, err := r.historyMgr.GetWorkflowExecutionHistory(&persistence.GetWorkflowExecutionHistoryRequest{
+			DomainID:      domainID,
+			Execution:     execution,
+			FirstEventID:  firstEventID,
+			NextEventID:   nextEventID,
+			PageSize:      resetHistoryPageSize,
+			NextPageToken: nextPageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, batch := range response.Events {
+			persistence.SetSerializedHistoryDefaults(&batch)
+			serializer, err := r.historyEngine.hSerializerFactory.Get(batch.EncodingType)
+			if err != nil {
+				return nil, err
+			}
+			events, err := serializer.Deserialize(&batch)
+			if err != nil {
+				return nil, err
+			}
+			history = append(history, events.Events...)
+		}
+
+		if len(response.NextPageToken) == 0 {
+			return history, nil
+		}
+		nextPageToken = response.NextPageToken
+	}
+}
+
+// buildNewMutableState replays the base history up to the reset point into the mutable state of the new run, fails
+// the decision being reset and schedules a new decision after re-applying the signals received since then
+func (r *workflowResetor) buildNewMutableState(domainID string, newExecution workflow.WorkflowExecution,
+	request *workflow.ResetWorkflowExecutionRequest, history []*workflow.HistoryEvent) (*mutableStateBuilder,
+	*decisionInfo, error) {
+	resetEventID := request.GetDecisionFinishEventId()
+	var replayEvents []*workflow.HistoryEvent
+	var signalEvents []*workflow.HistoryEvent
+	var completedEvent *workflow.HistoryEvent
+	for _, event := range history {
+		switch {
+		case event.GetEventId() < resetEventID:
+			replayEvents = append(replayEvents, event)
+		case event.GetEventId() == resetEventID:
+			completedEvent = event
+		case event.GetEventType() == workflow.EventTypeWorkflowExecutionSignaled:
+			signalEvents = append(signalEvents, event)
+		}
+	}
+	if completedEvent == nil || completedEvent.GetEventType() != workflow.EventTypeDecisionTaskCompleted {
+		return nil, nil, &workflow.BadRequestError{Message: "DecisionFinishEventId must be a DecisionTaskCompleted event."}
+	}
+
+	newMutableState := newMutableStateBuilder(r.shard.GetConfig(), r.logger)
+	sBuilder := newStateBuilder(newMutableState, r.logger)
+	if _, err := sBuilder.applyEvents(domainID, request.GetRequestId(), newExecution, replayEvents); err != nil {
+		return nil, nil, err
+	}
+
+	if len(newMutableState.pendingChildExecutionInfoIDs) > 0 || len(newMutableState.pendingRequestCancelInfoIDs) > 0 ||
+		len(newMutableState.pendingSignalInfoIDs) > 0 {
+		return nil, nil, &workflow.BadRequestError{
+			Message: "Reset to a point with pending child workflows, cancellations or signals is not supported.",
+		}
+	}
+
+	// Fail the decision being reset.  The failure is recorded in history even if the decision was a transient one, and
+	// the decision is cleared without counting a failed attempt so the next decision is scheduled as a regular decision.
+	attributes := completedEvent.DecisionTaskCompletedEventAttributes
+	if pending, ok := newMutableState.GetPendingDecision(attributes.GetScheduledEventId()); !ok ||
+		pending.StartedID != attributes.GetStartedEventId() {
+		return nil, nil, &workflow.InternalServiceError{Message: "Unable to fail decision task of the new run."}
+	}
+	newMutableState.hBuilder.AddDecisionTaskFailedEvent(attributes.GetScheduledEventId(),
+		attributes.GetStartedEventId(), workflow.DecisionTaskFailedCauseResetWorkflow, []byte(request.GetReason()),
+		request.GetIdentity())
+	newMutableState.clearStickyness()
+	newMutableState.DeleteDecision()
+
+	for _, event := range signalEvents {
+		signalAttributes := event.WorkflowExecutionSignaledEventAttributes
+		if newMutableState.AddWorkflowExecutionSignaled(&workflow.SignalWorkflowExecutionRequest{
+			SignalName: signalAttributes.SignalName,
+			Input:      signalAttributes.Input,
+			Identity:   signalAttributes.Identity,
+		}) == nil {
+			return nil, nil, &workflow.InternalServiceError{Message: "Unable to re-apply signal to the new run."}
+		}
+	}
+
+	di := newMutableState.AddDecisionTaskScheduledEvent()
+	if di == nil {
+		return nil, nil, &workflow.InternalServiceError{Message: "Failed to add decision scheduled event."}
+	}
+	newMutableState.executionInfo.LastFirstEventID = firstEventID
+
+	return newMutableState, di, nil
+}
+
+// terminateBaseRun terminates the base run and atomically replaces it with the new run as the current run.  It
+// returns the timer tasks created for the base run.
+func (r *workflowResetor) terminateBaseRun(baseContext *workflowExecutionContext,
+	baseMutableState *mutableStateBuilder, newMutableState *mutableStateBuilder,
+	request *workflow.ResetWorkflowExecutionRequest, createRequest *persistence.CreateWorkflowExecutionRequest) (
+	[]persistence.Task, error) {
+	if baseMutableState.AddWorkflowExecutionTerminatedEvent(&workflow.TerminateWorkflowExecutionRequest{
+		Reason:   request.Reason,
+		Identity: request.Identity,
+	}) == nil {
+		return nil, &workflow.InternalServiceError{Message: "Unable to terminate workflow execution."}
+	}
+	baseMutableState.continueAsNew = createRequest
+
+	tBuilder := r.historyEngine.getTimerBuilder(&baseContext.workflowExecution)
+	closeTask, cleanupTask, err := r.historyEngine.getDeleteWorkflowTasks(baseMutableState.executionInfo.DomainID,
+		tBuilder)
+	if err != nil {
+		return nil, err
+	}
+	timerTasks := []persistence.Task{cleanupTask}
+
+	// Generate a transaction ID for appending events to history
+	transactionID, err := r.shard.GetNextTransferTaskID()
+	if err != nil {
+		return nil, err
+	}
+
+	err = baseContext.continueAsNewWorkflowExecution(baseMutableState.executionInfo.ExecutionContext,
+		newMutableState, []persistence.Task{closeTask}, timerTasks, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return timerTasks, nil
+}
+
+// startNewRun creates the new run as the current run of a workflow whose base run is already closed
+func (r *workflowResetor) startNewRun(newMutableState *mutableStateBuilder,
+	createRequest *persistence.CreateWorkflowExecutionRequest) error {
+	serializedHistory, err := newMutableState.hBuilder.Serialize()
+	if err != nil {
+		logging.LogHistorySerializationErrorEvent(r.logger, err, fmt.Sprintf(
+			"HistoryEventBatch serialization error on reset workflow.  WorkflowID: %v, RunID: %v",
+			createRequest.Execution.GetWorkflowId(), createRequest.Execution.GetRunId()))
+		return err
+	}
+
+	err = r.shard.AppendHistoryEvents(&persistence.AppendHistoryEventsRequest{
+		DomainID:  createRequest.DomainID,
+		Execution: createRequest.Execution,
+		// It is ok to use 0 for TransactionID because RunID is unique so there are
+		// no potential duplicates to override.
+		TransactionID: 0,
+		FirstEventID:  firstEventID,
+		Events:        serializedHistory,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.shard.CreateWorkflowExecution(createRequest)
+	return err
+}
+
+// getNewRunTasks returns the tasks dispatching the scheduled decision and the pending activities of the new run, and
+// the timer tasks of its pending activities and timers
+func (r *workflowResetor) getNewRunTasks(newMutableState *mutableStateBuilder, tBuilder *timerBuilder,
+	di *decisionInfo) ([]persistence.Task, []persistence.Task, error) {
+	domainID := newMutableState.executionInfo.DomainID
+	transferTasks := []persistence.Task{&persistence.DecisionTask{
+		DomainID:   domainID,
+		TaskList:   di.Tasklist,
+		ScheduleID: di.ScheduleID,
+	}}
+	for _, ai := range newMutableState.pendingActivityInfoIDs {
+		// Activities which were started by the base run are left to time out and get retried
+		if ai.StartedID != emptyEventID {
+			continue
+		}
+
+		scheduledEvent, ok := newMutableState.GetActivityScheduledEvent(ai.ScheduleID)
+		if !ok {
+			return nil, nil, &workflow.InternalServiceError{Message: "Unable to get activity schedule event."}
+		}
+		attributes := scheduledEvent.ActivityTaskScheduledEventAttributes
+		targetDomainID := domainID
+		if attributes.Domain != nil {
+			domainEntry, err := r.shard.GetDomainCache().GetDomain(attributes.GetDomain())
+			if err != nil {
+				return nil, nil, &workflow.InternalServiceError{Message: "Unable to schedule activity across domain."}
+			}
+			targetDomainID = domainEntry.GetInfo().ID
+		}
+		transferTasks = append(transferTasks, &persistence.ActivityTask{
+			DomainID:   targetDomainID,
+			TaskList:   attributes.TaskList.GetName(),
+			ScheduleID: ai.ScheduleID,
+		})
+	}
+
+	// The timer builder marks the activities and timers it creates a timer task for, before they are persisted
+	var timerTasks []persistence.Task
+	tBuilder.loadUserTimers(newMutableState)
+	if tt := tBuilder.GetUserTimerTaskIfNeeded(newMutableState); tt != nil {
+		timerTasks = append(timerTasks, tt)
+	}
+	if tt := tBuilder.GetActivityTimerTaskIfNeeded(newMutableState); tt != nil {
+		timerTasks = append(timerTasks, tt)
+	}
+
+	return transferTasks, timerTasks, nil
+}
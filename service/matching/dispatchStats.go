@@ -0,0 +1,83 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"sync"
+
+	"github.com/uber/cadence/common"
+)
+
+// dispatchStatsWindowSeconds is the length of the sliding window of the dispatch statistics
+const dispatchStatsWindowSeconds = 60
+
+type (
+	// dispatchStats counts the tasks dispatched to the pollers of a task list over a sliding window,
+	// with one bucket per second
+	dispatchStats struct {
+		sync.Mutex
+		timeSource common.TimeSource
+		buckets    [dispatchStatsWindowSeconds]dispatchStatsBucket
+	}
+
+	dispatchStatsBucket struct {
+		second    int64
+		total     int64
+		syncMatch int64
+	}
+)
+
+func newDispatchStats(timeSource common.TimeSource) *dispatchStats {
+	return &dispatchStats{timeSource: timeSource}
+}
+
+// add records a task dispatched to a poller
+func (d *dispatchStats) add(syncMatch bool) {
+	now := d.timeSource.Now().Unix()
+	d.Lock()
+	defer d.Unlock()
+	bucket := &d.buckets[now%dispatchStatsWindowSeconds]
+	if bucket.second != now {
+		*bucket = dispatchStatsBucket{second: now}
+	}
+	bucket.total++
+	if syncMatch {
+		bucket.syncMatch++
+	}
+}
+
+// get returns the tasks dispatched per second and the ratio of them which were sync matched over the window
+func (d *dispatchStats) get() (ratePerSecond float64, syncMatchRatio float64) {
+	now := d.timeSource.Now().Unix()
+	var total, syncMatch int64
+	d.Lock()
+	for _, bucket := range d.buckets {
+		if now-bucket.second < dispatchStatsWindowSeconds {
+			total += bucket.total
+			syncMatch += bucket.syncMatch
+		}
+	}
+	d.Unlock()
+	if total == 0 {
+		return 0, 0
+	}
+	return float64(total) / dispatchStatsWindowSeconds, float64(syncMatch) / float64(total)
+}
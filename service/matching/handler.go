@@ -67,9 +67,13 @@ func (h *Handler) Start() error {
 	if err != nil {
 		return err
 	}
+	matchingClient, err := h.Service.GetClientFactory().NewMatchingClient()
+	if err != nil {
+		return err
+	}
 	h.metricsClient = h.Service.GetMetricsClient()
 	h.engine = NewEngine(
-		h.taskPersistence, history, h.config, h.Service.GetLogger(), h.Service.GetMetricsClient(),
+		h.taskPersistence, history, matchingClient, h.config, h.Service.GetLogger(), h.Service.GetMetricsClient(),
 	)
 	h.startWG.Done()
 	return nil
@@ -182,6 +186,46 @@ func (h *Handler) DescribeTaskList(ctx context.Context, request *m.DescribeTaskL
 	return response, h.handleErr(err, scope)
 }
 
+// UpdateTaskListBuildIDs marks a build ID of a decision task list as compatible with other build IDs or as retired.
+func (h *Handler) UpdateTaskListBuildIDs(ctx context.Context, request *m.UpdateTaskListBuildIDsRequest) error {
+	scope := metrics.MatchingUpdateTaskListBuildIDsScope
+	sw := h.startRequestProfile("UpdateTaskListBuildIDs", scope)
+	defer sw.Stop()
+
+	err := h.engine.UpdateTaskListBuildIDs(ctx, request)
+	return h.handleErr(err, scope)
+}
+
+// PauseTaskList stops dispatching the tasks of a task list partition to pollers.
+func (h *Handler) PauseTaskList(ctx context.Context, request *m.PauseTaskListRequest) error {
+	scope := metrics.MatchingPauseTaskListScope
+	sw := h.startRequestProfile("PauseTaskList", scope)
+	defer sw.Stop()
+
+	err := h.engine.PauseTaskList(ctx, request)
+	return h.handleErr(err, scope)
+}
+
+// ResumeTaskList resumes dispatching the tasks of a paused task list partition.
+func (h *Handler) ResumeTaskList(ctx context.Context, request *m.ResumeTaskListRequest) error {
+	scope := metrics.MatchingResumeTaskListScope
+	sw := h.startRequestProfile("ResumeTaskList", scope)
+	defer sw.Stop()
+
+	err := h.engine.ResumeTaskList(ctx, request)
+	return h.handleErr(err, scope)
+}
+
+// ListTaskLists returns a page of the task lists of a domain.
+func (h *Handler) ListTaskLists(ctx context.Context, request *m.ListTaskListsRequest) (*gen.ListTaskListsResponse, error) {
+	scope := metrics.MatchingListTaskListsScope
+	sw := h.startRequestProfile("ListTaskLists", scope)
+	defer sw.Stop()
+
+	response, err := h.engine.ListTaskLists(ctx, request)
+	return response, h.handleErr(err, scope)
+}
+
 func (h *Handler) handleErr(err error, scope int) error {
 
 	if err == nil {
@@ -207,6 +251,8 @@ func (h *Handler) handleErr(err error, scope int) error {
 	case *gen.QueryFailedError:
 		h.metricsClient.IncCounter(scope, metrics.CadenceErrQueryFailedCounter)
 		return err
+	case *m.RemoteSyncMatchFailedError:
+		return err
 	default:
 		h.metricsClient.IncCounter(scope, metrics.CadenceFailures)
 		return &gen.InternalServiceError{Message: err.Error()}
@@ -24,12 +24,15 @@ import (
 	"context"
 	"errors"
 	"math"
+	"strings"
 	"sync"
+	"time"
 
 	h "github.com/uber/cadence/.gen/go/history"
 	m "github.com/uber/cadence/.gen/go/matching"
 	workflow "github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/client/history"
+	"github.com/uber/cadence/client/matching"
 	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/client"
 	"github.com/uber/cadence/common/logging"
@@ -46,6 +49,7 @@ import (
 type matchingEngineImpl struct {
 	taskManager     persistence.TaskManager
 	historyService  history.Client
+	matchingClient  matching.Client
 	tokenSerializer common.TaskTokenSerializer
 	logger          bark.Logger
 	metricsClient   metrics.Client
@@ -76,6 +80,13 @@ var (
 	persistenceOperationRetryPolicy    = common.CreatePersistanceRetryPolicy()
 	historyServiceOperationRetryPolicy = common.CreateHistoryServiceRetryPolicy()
 
+	// forwardTaskTimeout is how long a child partition waits for the root partition to match a forwarded task, and
+	// how long a decision task can take to be redirected to the task list of its build ID
+	forwardTaskTimeout = 10 * time.Second
+
+	// defaultListTaskListsPageSize is the page size of ListTaskLists requests which do not specify one
+	defaultListTaskListsPageSize = 100
+
 	// ErrNoTasks is exported temporarily for integration test
 	ErrNoTasks    = errors.New("No tasks")
 	errPumpClosed = errors.New("Task list pump closed its channel")
@@ -102,6 +113,7 @@ var _ Engine = (*matchingEngineImpl)(nil) // Asserts that interface is indeed im
 // NewEngine creates an instance of matching engine
 func NewEngine(taskManager persistence.TaskManager,
 	historyService history.Client,
+	matchingClient matching.Client,
 	config *Config,
 	logger bark.Logger,
 	metricsClient metrics.Client,
@@ -110,6 +122,7 @@ func NewEngine(taskManager persistence.TaskManager,
 	return &matchingEngineImpl{
 		taskManager:     taskManager,
 		historyService:  historyService,
+		matchingClient:  matchingClient,
 		tokenSerializer: common.NewJSONTaskTokenSerializer(),
 		taskLists:       make(map[taskListID]taskListManager),
 		logger: logger.WithFields(bark.Fields{
@@ -220,6 +233,28 @@ func (e *matchingEngineImpl) AddDecisionTask(addRequest *m.AddDecisionTaskReques
 		WorkflowID:             addRequest.Execution.GetWorkflowId(),
 		ScheduleID:             addRequest.GetScheduleId(),
 		ScheduleToStartTimeout: addRequest.GetScheduleToStartTimeoutSeconds(),
+		Priority:               addRequest.GetPriority(),
+		BuildID:                addRequest.GetBuildID(),
+	}
+	if addRequest.GetForwardedFrom() != "" {
+		return e.syncMatchForwardedTask(tlMgr, taskInfo)
+	}
+	// the tasks added to a paused task list stay on it and are redirected once they are dispatched after it is resumed
+	if e.routesBuildIDs(addRequest.TaskList, addRequest.GetForwardedFrom()) && !tlMgr.IsPaused() {
+		if target := tlMgr.GetBuildIDs().taskTarget(taskInfo.BuildID); target != "" {
+			return e.redirectDecisionTask(domainID, addRequest.Execution, taskInfo, taskListName, target)
+		}
+	}
+	if rootName, ok := e.getForwardTarget(addRequest.TaskList, addRequest.GetForwardedFrom()); ok {
+		matched, err := e.forwardTask(tlMgr, taskInfo, func(ctx context.Context) error {
+			request := *addRequest
+			request.TaskList = &workflow.TaskList{Name: common.StringPtr(rootName), Kind: addRequest.TaskList.Kind}
+			request.ForwardedFrom = common.StringPtr(taskListName)
+			return e.matchingClient.AddDecisionTask(ctx, &request)
+		})
+		if err != nil || matched {
+			return err
+		}
 	}
 	return tlMgr.AddTask(addRequest.Execution, taskInfo)
 }
@@ -243,6 +278,21 @@ func (e *matchingEngineImpl) AddActivityTask(addRequest *m.AddActivityTaskReques
 		WorkflowID:             addRequest.Execution.GetWorkflowId(),
 		ScheduleID:             addRequest.GetScheduleId(),
 		ScheduleToStartTimeout: addRequest.GetScheduleToStartTimeoutSeconds(),
+		Priority:               addRequest.GetPriority(),
+	}
+	if addRequest.GetForwardedFrom() != "" {
+		return e.syncMatchForwardedTask(tlMgr, taskInfo)
+	}
+	if rootName, ok := e.getForwardTarget(addRequest.TaskList, addRequest.GetForwardedFrom()); ok {
+		matched, err := e.forwardTask(tlMgr, taskInfo, func(ctx context.Context) error {
+			request := *addRequest
+			request.TaskList = &workflow.TaskList{Name: common.StringPtr(rootName), Kind: addRequest.TaskList.Kind}
+			request.ForwardedFrom = common.StringPtr(taskListName)
+			return e.matchingClient.AddActivityTask(ctx, &request)
+		})
+		if err != nil || matched {
+			return err
+		}
 	}
 	return tlMgr.AddTask(addRequest.Execution, taskInfo)
 }
@@ -255,6 +305,35 @@ func (e *matchingEngineImpl) PollForDecisionTask(ctx context.Context, req *m.Pol
 	request := req.PollRequest
 	taskListName := request.TaskList.GetName()
 	e.logger.Debugf("Received PollForDecisionTask for taskList=%v", taskListName)
+	if e.routesBuildIDs(request.TaskList, req.GetForwardedFrom()) && request.GetBuildID() != "" {
+		taskList := newTaskListID(domainID, taskListName, persistence.TaskListTypeDecision)
+		tlMgr, err := e.getTaskListManager(taskList, common.TaskListKindPtr(request.TaskList.GetKind()))
+		if err != nil {
+			return nil, err
+		}
+		// workers of a build ID which is not a default build ID poll the task list of their set of build IDs
+		if target := tlMgr.GetBuildIDs().pollerTarget(request.GetBuildID()); target != "" {
+			pollRequest := *request
+			pollRequest.TaskList = &workflow.TaskList{
+				Name: common.StringPtr(common.TaskListBuildIDName(taskListName, target)),
+				Kind: request.TaskList.Kind,
+			}
+			redirectRequest := *req
+			redirectRequest.PollRequest = &pollRequest
+			return e.matchingClient.PollForDecisionTask(ctx, &redirectRequest)
+		}
+	}
+	var forwardPoll func(ctx context.Context) (interface{}, error)
+	if rootName, ok := e.getForwardTarget(request.TaskList, req.GetForwardedFrom()); ok {
+		forwardPoll = func(ctx context.Context) (interface{}, error) {
+			pollRequest := *request
+			pollRequest.TaskList = &workflow.TaskList{Name: common.StringPtr(rootName), Kind: request.TaskList.Kind}
+			forwardRequest := *req
+			forwardRequest.PollRequest = &pollRequest
+			forwardRequest.ForwardedFrom = common.StringPtr(taskListName)
+			return e.matchingClient.PollForDecisionTask(ctx, &forwardRequest)
+		}
+	}
 pollLoop:
 	for {
 		err := common.IsValidContext(ctx)
@@ -264,10 +343,11 @@ pollLoop:
 		// Add frontend generated pollerID to context so tasklistMgr can support cancellation of
 		// long-poll when frontend calls CancelOutstandingPoll API
 		pollerCtx := context.WithValue(ctx, pollerIDKey, pollerID)
-		pollerCtx = context.WithValue(pollerCtx, identityKey, request.GetIdentity())
+		pollerCtx = context.WithValue(pollerCtx, identityKey,
+			newPollerIdentity(ctx, request.GetIdentity(), request.GetBuildID()))
 		taskList := newTaskListID(domainID, taskListName, persistence.TaskListTypeDecision)
 		taskListKind := common.TaskListKindPtr(request.TaskList.GetKind())
-		tCtx, err := e.getTask(pollerCtx, taskList, nil, taskListKind)
+		tCtx, forwardedResp, err := e.getTask(pollerCtx, taskList, nil, taskListKind, forwardPoll)
 		if err != nil {
 			// TODO: Is empty poll the best reply for errPumpClosed?
 			if err == ErrNoTasks || err == errPumpClosed {
@@ -275,6 +355,9 @@ pollLoop:
 			}
 			return nil, err
 		}
+		if forwardedResp != nil {
+			return forwardedResp.(*m.PollForDecisionTaskResponse), nil
+		}
 
 		if tCtx.queryTaskInfo != nil {
 			// for query task, we don't need to update history to record decision task started. but we need to know
@@ -316,6 +399,15 @@ pollLoop:
 			return e.createPollForDecisionTaskResponse(tCtx, resp), nil
 		}
 
+		if tCtx.syncResponseCh == nil && e.routesBuildIDs(request.TaskList, req.GetForwardedFrom()) {
+			// the task was persisted before the build ID of its workflow stopped being a default build ID
+			if target := tCtx.tlMgr.GetBuildIDs().taskTarget(tCtx.info.BuildID); target != "" {
+				tCtx.completeTask(e.redirectDecisionTask(domainID, &tCtx.workflowExecution, tCtx.info, taskListName,
+					target))
+				continue pollLoop
+			}
+		}
+
 		// Generate a unique requestId for this task which will be used for all retries
 		requestID := uuid.New()
 		resp, err := tCtx.RecordDecisionTaskStartedWithRetry(&h.RecordDecisionTaskStartedRequest{
@@ -353,6 +445,17 @@ func (e *matchingEngineImpl) PollForActivityTask(ctx context.Context, req *m.Pol
 	request := req.PollRequest
 	taskListName := request.TaskList.GetName()
 	e.logger.Debugf("Received PollForActivityTask for taskList=%v", taskListName)
+	var forwardPoll func(ctx context.Context) (interface{}, error)
+	if rootName, ok := e.getForwardTarget(request.TaskList, req.GetForwardedFrom()); ok {
+		forwardPoll = func(ctx context.Context) (interface{}, error) {
+			pollRequest := *request
+			pollRequest.TaskList = &workflow.TaskList{Name: common.StringPtr(rootName), Kind: request.TaskList.Kind}
+			forwardRequest := *req
+			forwardRequest.PollRequest = &pollRequest
+			forwardRequest.ForwardedFrom = common.StringPtr(taskListName)
+			return e.matchingClient.PollForActivityTask(ctx, &forwardRequest)
+		}
+	}
 pollLoop:
 	for {
 		err := common.IsValidContext(ctx)
@@ -368,9 +471,10 @@ pollLoop:
 		// Add frontend generated pollerID to context so tasklistMgr can support cancellation of
 		// long-poll when frontend calls CancelOutstandingPoll API
 		pollerCtx := context.WithValue(ctx, pollerIDKey, pollerID)
-		pollerCtx = context.WithValue(pollerCtx, identityKey, request.GetIdentity())
+		pollerCtx = context.WithValue(pollerCtx, identityKey,
+			newPollerIdentity(ctx, request.GetIdentity(), request.GetBuildID()))
 		taskListKind := common.TaskListKindPtr(request.TaskList.GetKind())
-		tCtx, err := e.getTask(pollerCtx, taskList, maxDispatch, taskListKind)
+		tCtx, forwardedResp, err := e.getTask(pollerCtx, taskList, maxDispatch, taskListKind, forwardPoll)
 		if err != nil {
 			// TODO: Is empty poll the best reply for errPumpClosed?
 			if err == ErrNoTasks || err == errPumpClosed {
@@ -378,6 +482,9 @@ pollLoop:
 			}
 			return nil, err
 		}
+		if forwardedResp != nil {
+			return forwardedResp.(*workflow.PollForActivityTaskResponse), nil
+		}
 		// Generate a unique requestId for this task which will be used for all retries
 		requestID := uuid.New()
 		resp, err := tCtx.RecordActivityTaskStartedWithRetry(&h.RecordActivityTaskStartedRequest{
@@ -474,6 +581,12 @@ func (e *matchingEngineImpl) CancelOutstandingPoll(ctx context.Context, request
 	}
 
 	tlMgr.CancelPoller(pollerID)
+	// the poll may have been forwarded to the root partition
+	if rootName, ok := e.getForwardTarget(request.TaskList, ""); ok {
+		forwardRequest := *request
+		forwardRequest.TaskList = &workflow.TaskList{Name: common.StringPtr(rootName), Kind: request.TaskList.Kind}
+		return e.matchingClient.CancelOutstandingPoll(ctx, &forwardRequest)
+	}
 	return nil
 }
 
@@ -495,22 +608,204 @@ func (e *matchingEngineImpl) DescribeTaskList(ctx context.Context, request *m.De
 	pollers := []*workflow.PollerInfo{}
 	for _, poller := range tlMgr.GetAllPollerInfo() {
 		pollers = append(pollers, &workflow.PollerInfo{
-			Identity:       common.StringPtr(poller.identity),
-			LastAccessTime: common.Int64Ptr(poller.lastAccessTime.UnixNano()),
+			Identity:             common.StringPtr(poller.identity),
+			LastAccessTime:       common.Int64Ptr(poller.lastAccessTime.UnixNano()),
+			RatePerSecond:        common.Float64Ptr(poller.ratePerSecond),
+			IpAddress:            common.StringPtr(poller.ipAddress),
+			ClientLibraryVersion: common.StringPtr(poller.libraryVersion),
+			ClientImpl:           common.StringPtr(poller.clientImpl),
+			BuildID:              common.StringPtr(poller.buildID),
 		})
 	}
-	return &workflow.DescribeTaskListResponse{Pollers: pollers}, nil
+	response := &workflow.DescribeTaskListResponse{
+		Pollers:        pollers,
+		TaskListStatus: tlMgr.GetTaskListStatus(),
+		Paused:         common.BoolPtr(tlMgr.IsPaused()),
+	}
+	if taskListType == persistence.TaskListTypeDecision {
+		response.BuildIDs = tlMgr.GetBuildIDs().toThrift()
+	}
+	return response, nil
 }
 
-// Loads a task from persistence and wraps it in a task context
+// UpdateTaskListBuildIDs retires a build ID of a decision task list or adds it to a set of compatible build IDs
+func (e *matchingEngineImpl) UpdateTaskListBuildIDs(ctx context.Context, request *m.UpdateTaskListBuildIDsRequest) error {
+	updateRequest := request.UpdateRequest
+	taskList := newTaskListID(request.GetDomainUUID(), updateRequest.TaskList.GetName(), persistence.TaskListTypeDecision)
+	tlMgr, err := e.getTaskListManager(taskList, common.TaskListKindPtr(workflow.TaskListKindNormal))
+	if err != nil {
+		return err
+	}
+	return tlMgr.UpdateBuildIDs(updateRequest.GetBuildID(), updateRequest.GetCompatibleBuildID(),
+		updateRequest.GetRetire())
+}
+
+// PauseTaskList stops dispatching the tasks of a task list partition to pollers
+func (e *matchingEngineImpl) PauseTaskList(ctx context.Context, request *m.PauseTaskListRequest) error {
+	pauseRequest := request.PauseRequest
+	return e.setTaskListPaused(request.GetDomainUUID(), pauseRequest.TaskList, pauseRequest.GetTaskListType(), true)
+}
+
+// ResumeTaskList resumes dispatching the tasks of a paused task list partition
+func (e *matchingEngineImpl) ResumeTaskList(ctx context.Context, request *m.ResumeTaskListRequest) error {
+	resumeRequest := request.ResumeRequest
+	return e.setTaskListPaused(request.GetDomainUUID(), resumeRequest.TaskList, resumeRequest.GetTaskListType(), false)
+}
+
+// ListTaskLists returns a page of the task lists of a domain which are known to persistence
+func (e *matchingEngineImpl) ListTaskLists(ctx context.Context, request *m.ListTaskListsRequest) (*workflow.ListTaskListsResponse, error) {
+	listRequest := request.ListRequest
+	pageSize := int(listRequest.GetMaximumPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultListTaskListsPageSize
+	}
+	resp, err := e.taskManager.ListTaskList(&persistence.ListTaskListRequest{
+		DomainID:      request.GetDomainUUID(),
+		PageSize:      pageSize,
+		NextPageToken: listRequest.NextPageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	taskLists := make([]*workflow.TaskListInfo, 0, len(resp.Items))
+	for _, info := range resp.Items {
+		// the partitions, priority backlogs and build ID task lists are internal to their root task list
+		if strings.HasPrefix(info.Name, common.ReservedTaskListPrefix) {
+			continue
+		}
+		taskListType := workflow.TaskListTypeDecision
+		if info.TaskType == persistence.TaskListTypeActivity {
+			taskListType = workflow.TaskListTypeActivity
+		}
+		taskListInfo := &workflow.TaskListInfo{
+			Name:         common.StringPtr(info.Name),
+			TaskListType: taskListType.Ptr(),
+			Kind:         common.TaskListKindPtr(workflow.TaskListKind(info.Kind)),
+			AckLevel:     common.Int64Ptr(info.AckLevel),
+		}
+		// task lists written before the last updated time was recorded do not have one
+		if !info.LastUpdated.IsZero() {
+			taskListInfo.LastUpdatedTime = common.Int64Ptr(info.LastUpdated.UnixNano())
+		}
+		taskLists = append(taskLists, taskListInfo)
+	}
+	return &workflow.ListTaskListsResponse{
+		TaskLists:     taskLists,
+		NextPageToken: resp.NextPageToken,
+	}, nil
+}
+
+func (e *matchingEngineImpl) setTaskListPaused(domainID string, taskList *workflow.TaskList,
+	taskListType workflow.TaskListType, paused bool) error {
+	tlType := persistence.TaskListTypeDecision
+	if taskListType == workflow.TaskListTypeActivity {
+		tlType = persistence.TaskListTypeActivity
+	}
+	tlMgr, err := e.getTaskListManager(newTaskListID(domainID, taskList.GetName(), tlType),
+		common.TaskListKindPtr(workflow.TaskListKindNormal))
+	if err != nil {
+		return err
+	}
+	return tlMgr.SetPaused(paused)
+}
+
+// Loads a task from persistence and wraps it in a task context. A poll on a child partition takes a local task
+// only if one is available right away and is forwarded to the root partition otherwise, the response of the root
+// partition is returned instead of a task context then. The poll waits on the child partition if forwarding fails.
 func (e *matchingEngineImpl) getTask(
 	ctx context.Context, taskList *taskListID, maxDispatchPerSecond *float64, taskListKind *workflow.TaskListKind,
-) (*taskContext, error) {
+	forwardPoll func(ctx context.Context) (interface{}, error),
+) (*taskContext, interface{}, error) {
 	tlMgr, err := e.getTaskListManager(taskList, taskListKind)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if forwardPoll != nil {
+		tCtx, err := tlMgr.TryGetTaskContext(ctx, maxDispatchPerSecond)
+		if err != ErrNoTasks {
+			return tCtx, nil, err
+		}
+		e.metricsClient.IncCounter(metrics.MatchingTaskListMgrScope, metrics.ForwardPollCounter)
+		resp, err := forwardPoll(ctx)
+		if err == nil {
+			return nil, resp, nil
+		}
+		e.logger.Debugf("Failed to forward poll of taskList=%v to the root partition: %v", taskList.taskListName, err)
+	}
+	tCtx, err := tlMgr.GetTaskContext(ctx, maxDispatchPerSecond)
+	return tCtx, nil, err
+}
+
+// getForwardTarget returns the name of the root partition requests on a child partition of a task list are
+// forwarded to, requests which were already forwarded and requests on the root partition or on a sticky
+// task list are not forwarded
+func (e *matchingEngineImpl) getForwardTarget(taskList *workflow.TaskList, forwardedFrom string) (string, bool) {
+	if e.matchingClient == nil || forwardedFrom != "" || taskList.GetKind() == workflow.TaskListKindSticky {
+		return "", false
+	}
+	rootName, partition := common.ParseTaskListPartitionName(taskList.GetName())
+	return rootName, partition != 0
+}
+
+// routesBuildIDs returns whether the decision tasks and polls of a task list are routed by their build ID, which is
+// done by the root partition of a normal task list for the requests which were not forwarded to it
+func (e *matchingEngineImpl) routesBuildIDs(taskList *workflow.TaskList, forwardedFrom string) bool {
+	return e.matchingClient != nil && forwardedFrom == "" && taskList.GetKind() != workflow.TaskListKindSticky &&
+		!strings.HasPrefix(taskList.GetName(), common.ReservedTaskListPrefix)
+}
+
+// redirectDecisionTask adds a decision task to the task list of the set of build IDs its workflow is pinned to
+func (e *matchingEngineImpl) redirectDecisionTask(domainID string, execution *workflow.WorkflowExecution,
+	taskInfo *persistence.TaskInfo, taskListName string, target string) error {
+	e.metricsClient.IncCounter(metrics.MatchingTaskListMgrScope, metrics.RedirectBuildIDTaskCounter)
+	ctx, cancel := context.WithTimeout(context.Background(), forwardTaskTimeout)
+	defer cancel()
+	return e.matchingClient.AddDecisionTask(ctx, &m.AddDecisionTaskRequest{
+		DomainUUID: common.StringPtr(domainID),
+		Execution:  execution,
+		TaskList: &workflow.TaskList{
+			Name: common.StringPtr(common.TaskListBuildIDName(taskListName, target)),
+			Kind: common.TaskListKindPtr(workflow.TaskListKindNormal),
+		},
+		ScheduleId:                    common.Int64Ptr(taskInfo.ScheduleID),
+		ScheduleToStartTimeoutSeconds: common.Int32Ptr(taskInfo.ScheduleToStartTimeout),
+		Priority:                      common.Int32Ptr(taskInfo.Priority),
+		BuildID:                       common.StringPtr(taskInfo.BuildID),
+	})
+}
+
+// forwardTask tries to match a task added to a child partition with a local poller first and forwards it to the
+// root partition otherwise, the task has to be persisted by the child partition if neither of them matched it
+func (e *matchingEngineImpl) forwardTask(tlMgr taskListManager, taskInfo *persistence.TaskInfo,
+	forward func(ctx context.Context) error) (bool, error) {
+	matched, err := tlMgr.TrySyncMatch(taskInfo)
+	if err != nil || matched {
+		return matched, err
+	}
+	e.metricsClient.IncCounter(metrics.MatchingTaskListMgrScope, metrics.ForwardTaskCounter)
+	ctx, cancel := context.WithTimeout(context.Background(), forwardTaskTimeout)
+	defer cancel()
+	if err := forward(ctx); err != nil {
+		if _, ok := err.(*m.RemoteSyncMatchFailedError); !ok {
+			e.logger.Debugf("Failed to forward task to the root partition: %v", err)
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// syncMatchForwardedTask matches a task forwarded by a child partition with a poller of the root partition, the task
+// is never persisted by the root partition as the child partition persists it when there is no poller
+func (e *matchingEngineImpl) syncMatchForwardedTask(tlMgr taskListManager, taskInfo *persistence.TaskInfo) error {
+	matched, err := tlMgr.TrySyncMatch(taskInfo)
+	if err != nil {
+		return err
 	}
-	return tlMgr.GetTaskContext(ctx, maxDispatchPerSecond)
+	if !matched {
+		return &m.RemoteSyncMatchFailedError{Message: "no poller is waiting on the root partition"}
+	}
+	return nil
 }
 
 func (e *matchingEngineImpl) unloadTaskList(id *taskListID) {
@@ -592,6 +887,7 @@ func (e *matchingEngineImpl) createPollForActivityTaskResponse(context *taskCont
 	response.StartedTimestamp = common.Int64Ptr(*startedEvent.Timestamp)
 	response.StartToCloseTimeoutSeconds = common.Int32Ptr(*attributes.StartToCloseTimeoutSeconds)
 	response.HeartbeatTimeoutSeconds = common.Int32Ptr(*attributes.HeartbeatTimeoutSeconds)
+	response.Attempt = common.Int32Ptr(int32(historyResponse.GetAttempt()))
 
 	token := &common.TaskToken{
 		DomainID:   task.DomainID,
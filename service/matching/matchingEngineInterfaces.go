@@ -39,5 +39,9 @@ type (
 		RespondQueryTaskCompleted(ctx context.Context, request *m.RespondQueryTaskCompletedRequest) error
 		CancelOutstandingPoll(ctx context.Context, request *m.CancelOutstandingPollRequest) error
 		DescribeTaskList(ctx context.Context, request *m.DescribeTaskListRequest) (*workflow.DescribeTaskListResponse, error)
+		UpdateTaskListBuildIDs(ctx context.Context, request *m.UpdateTaskListBuildIDsRequest) error
+		PauseTaskList(ctx context.Context, request *m.PauseTaskListRequest) error
+		ResumeTaskList(ctx context.Context, request *m.ResumeTaskListRequest) error
+		ListTaskLists(ctx context.Context, request *m.ListTaskListsRequest) (*workflow.ListTaskListsResponse, error)
 	}
 )
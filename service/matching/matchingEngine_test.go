@@ -1246,12 +1246,12 @@ func (s *matchingEngineSuite) TestAddTaskAfterStartFailure() {
 	s.NoError(err)
 	s.EqualValues(1, s.taskManager.getTaskCount(tlID))
 
-	ctx, err := s.matchingEngine.getTask(context.Background(), tlID, nil, tlKind)
+	ctx, _, err := s.matchingEngine.getTask(context.Background(), tlID, nil, tlKind, nil)
 	s.NoError(err)
 
 	ctx.completeTask(errors.New("test error"))
 	s.EqualValues(1, s.taskManager.getTaskCount(tlID))
-	ctx2, err := s.matchingEngine.getTask(context.Background(), tlID, nil, tlKind)
+	ctx2, _, err := s.matchingEngine.getTask(context.Background(), tlID, nil, tlKind, nil)
 	s.NoError(err)
 
 	s.NotEqual(ctx.info.TaskID, ctx2.info.TaskID)
@@ -1263,6 +1263,242 @@ func (s *matchingEngineSuite) TestAddTaskAfterStartFailure() {
 	s.EqualValues(0, s.taskManager.getTaskCount(tlID))
 }
 
+func (s *matchingEngineSuite) TestAddTaskForwardedToRootPartition() {
+	matchingClient := &mocks.MatchingClient{}
+	s.matchingEngine.matchingClient = matchingClient
+
+	domainID := "domainId"
+	tl := "makeToast"
+	partition := common.TaskListPartitionName(tl, 1)
+	tlID := &taskListID{domainID: domainID, taskListName: partition, taskType: persistence.TaskListTypeActivity}
+	workflowExecution := workflow.WorkflowExecution{RunId: common.StringPtr("run1"), WorkflowId: common.StringPtr("workflow1")}
+
+	matchingClient.On("AddActivityTask", mock.Anything, mock.MatchedBy(func(request *matching.AddActivityTaskRequest) bool {
+		return request.TaskList.GetName() == tl && request.GetForwardedFrom() == partition
+	})).Return(nil).Once()
+	err := s.matchingEngine.AddActivityTask(&matching.AddActivityTaskRequest{
+		SourceDomainUUID:              common.StringPtr(domainID),
+		DomainUUID:                    common.StringPtr(domainID),
+		Execution:                     &workflowExecution,
+		ScheduleId:                    common.Int64Ptr(1),
+		TaskList:                      &workflow.TaskList{Name: common.StringPtr(partition)},
+		ScheduleToStartTimeoutSeconds: common.Int32Ptr(1),
+	})
+	s.NoError(err)
+	s.EqualValues(0, s.taskManager.getTaskCount(tlID))
+
+	// the child partition persists the task when the root partition has no poller
+	matchingClient.On("AddActivityTask", mock.Anything, mock.Anything).Return(
+		&matching.RemoteSyncMatchFailedError{}).Once()
+	err = s.matchingEngine.AddActivityTask(&matching.AddActivityTaskRequest{
+		SourceDomainUUID:              common.StringPtr(domainID),
+		DomainUUID:                    common.StringPtr(domainID),
+		Execution:                     &workflowExecution,
+		ScheduleId:                    common.Int64Ptr(2),
+		TaskList:                      &workflow.TaskList{Name: common.StringPtr(partition)},
+		ScheduleToStartTimeoutSeconds: common.Int32Ptr(1),
+	})
+	s.NoError(err)
+	s.EqualValues(1, s.taskManager.getTaskCount(tlID))
+	matchingClient.AssertExpectations(s.T())
+}
+
+func (s *matchingEngineSuite) TestForwardedTaskIsNotPersistedByRootPartition() {
+	domainID := "domainId"
+	tl := "makeToast"
+	tlID := &taskListID{domainID: domainID, taskListName: tl, taskType: persistence.TaskListTypeDecision}
+	workflowExecution := workflow.WorkflowExecution{RunId: common.StringPtr("run1"), WorkflowId: common.StringPtr("workflow1")}
+
+	err := s.matchingEngine.AddDecisionTask(&matching.AddDecisionTaskRequest{
+		DomainUUID:                    common.StringPtr(domainID),
+		Execution:                     &workflowExecution,
+		ScheduleId:                    common.Int64Ptr(1),
+		TaskList:                      &workflow.TaskList{Name: common.StringPtr(tl)},
+		ScheduleToStartTimeoutSeconds: common.Int32Ptr(1),
+		ForwardedFrom:                 common.StringPtr(common.TaskListPartitionName(tl, 1)),
+	})
+	s.IsType(&matching.RemoteSyncMatchFailedError{}, err)
+	s.EqualValues(0, s.taskManager.getTaskCount(tlID))
+}
+
+func (s *matchingEngineSuite) TestPollForwardedToRootPartition() {
+	matchingClient := &mocks.MatchingClient{}
+	s.matchingEngine.matchingClient = matchingClient
+
+	domainID := "domainId"
+	tl := "makeToast"
+	partition := common.TaskListPartitionName(tl, 1)
+	forwardedResp := &workflow.PollForActivityTaskResponse{ActivityId: common.StringPtr("activity1")}
+
+	matchingClient.On("PollForActivityTask", mock.Anything, mock.MatchedBy(func(request *matching.PollForActivityTaskRequest) bool {
+		return request.PollRequest.TaskList.GetName() == tl && request.GetForwardedFrom() == partition
+	})).Return(forwardedResp, nil).Once()
+	resp, err := s.matchingEngine.PollForActivityTask(s.callContext, &matching.PollForActivityTaskRequest{
+		DomainUUID: common.StringPtr(domainID),
+		PollRequest: &workflow.PollForActivityTaskRequest{
+			TaskList: &workflow.TaskList{Name: common.StringPtr(partition)},
+			Identity: common.StringPtr("selfDrivingToaster"),
+		},
+	})
+	s.NoError(err)
+	s.Equal(forwardedResp, resp)
+
+	// the poll waits on the child partition when the root partition can't be reached
+	matchingClient.On("PollForActivityTask", mock.Anything, mock.Anything).Return(
+		nil, &workflow.InternalServiceError{Message: "unreachable"}).Once()
+	resp, err = s.matchingEngine.PollForActivityTask(s.callContext, &matching.PollForActivityTaskRequest{
+		DomainUUID: common.StringPtr(domainID),
+		PollRequest: &workflow.PollForActivityTaskRequest{
+			TaskList: &workflow.TaskList{Name: common.StringPtr(partition)},
+			Identity: common.StringPtr("selfDrivingToaster"),
+		},
+	})
+	s.NoError(err)
+	s.Equal(emptyPollForActivityTaskResponse, resp)
+	matchingClient.AssertExpectations(s.T())
+}
+
+func (s *matchingEngineSuite) TestAddDecisionTaskRedirectedByBuildID() {
+	matchingClient := &mocks.MatchingClient{}
+	s.matchingEngine.matchingClient = matchingClient
+
+	domainID := "domainId"
+	tl := "makeToast"
+	tlID := &taskListID{domainID: domainID, taskListName: tl, taskType: persistence.TaskListTypeDecision}
+	workflowExecution := workflow.WorkflowExecution{RunId: common.StringPtr("run1"), WorkflowId: common.StringPtr("workflow1")}
+
+	tlMgr, err := s.matchingEngine.getTaskListManager(tlID, common.TaskListKindPtr(workflow.TaskListKindNormal))
+	s.NoError(err)
+	// v2 is the default build ID, so the decision tasks of the workflows pinned to v1 go to the task list of v1
+	s.NoError(tlMgr.UpdateBuildIDs("v1", "", false))
+	s.NoError(tlMgr.UpdateBuildIDs("v2", "", false))
+
+	matchingClient.On("AddDecisionTask", mock.MatchedBy(func(ctx context.Context) bool {
+		_, ok := ctx.Deadline()
+		return ok
+	}), mock.MatchedBy(func(request *matching.AddDecisionTaskRequest) bool {
+		return request.TaskList.GetName() == common.TaskListBuildIDName(tl, "v1") && request.GetBuildID() == "v1" &&
+			request.GetScheduleId() == 1
+	})).Return(nil).Once()
+	err = s.matchingEngine.AddDecisionTask(&matching.AddDecisionTaskRequest{
+		DomainUUID:                    common.StringPtr(domainID),
+		Execution:                     &workflowExecution,
+		ScheduleId:                    common.Int64Ptr(1),
+		TaskList:                      &workflow.TaskList{Name: common.StringPtr(tl)},
+		ScheduleToStartTimeoutSeconds: common.Int32Ptr(1),
+		BuildID:                       common.StringPtr("v1"),
+	})
+	s.NoError(err)
+	s.EqualValues(0, s.taskManager.getTaskCount(tlID))
+
+	// the workflows pinned to the default build ID and the ones not pinned yet are dispatched by the task list itself
+	for i, buildID := range []string{"v2", ""} {
+		err = s.matchingEngine.AddDecisionTask(&matching.AddDecisionTaskRequest{
+			DomainUUID:                    common.StringPtr(domainID),
+			Execution:                     &workflowExecution,
+			ScheduleId:                    common.Int64Ptr(int64(i + 2)),
+			TaskList:                      &workflow.TaskList{Name: common.StringPtr(tl)},
+			ScheduleToStartTimeoutSeconds: common.Int32Ptr(1),
+			BuildID:                       common.StringPtr(buildID),
+		})
+		s.NoError(err)
+	}
+	s.EqualValues(2, s.taskManager.getTaskCount(tlID))
+	matchingClient.AssertExpectations(s.T())
+}
+
+func (s *matchingEngineSuite) TestPollForDecisionTaskRoutedByBuildID() {
+	s.matchingEngine.config.LongPollExpirationInterval = func(...dynamicconfig.FilterOption) time.Duration { return 10 * time.Millisecond }
+	matchingClient := &mocks.MatchingClient{}
+	s.matchingEngine.matchingClient = matchingClient
+
+	domainID := "domainId"
+	tl := "makeToast"
+	tlID := &taskListID{domainID: domainID, taskListName: tl, taskType: persistence.TaskListTypeDecision}
+
+	tlMgr, err := s.matchingEngine.getTaskListManager(tlID, common.TaskListKindPtr(workflow.TaskListKindNormal))
+	s.NoError(err)
+	s.NoError(tlMgr.UpdateBuildIDs("v1", "", false))
+	s.NoError(tlMgr.UpdateBuildIDs("v1.1", "v1", false))
+	s.NoError(tlMgr.UpdateBuildIDs("v2", "", false))
+
+	// the workers of a build ID compatible with v1 poll the task list of v1
+	redirectedResp := &matching.PollForDecisionTaskResponse{TaskToken: []byte("token")}
+	matchingClient.On("PollForDecisionTask", mock.Anything, mock.MatchedBy(func(request *matching.PollForDecisionTaskRequest) bool {
+		return request.PollRequest.TaskList.GetName() == common.TaskListBuildIDName(tl, "v1")
+	})).Return(redirectedResp, nil).Once()
+	resp, err := s.matchingEngine.PollForDecisionTask(s.callContext, &matching.PollForDecisionTaskRequest{
+		DomainUUID: common.StringPtr(domainID),
+		PollRequest: &workflow.PollForDecisionTaskRequest{
+			TaskList: &workflow.TaskList{Name: common.StringPtr(tl)},
+			Identity: common.StringPtr("selfDrivingToaster"),
+			BuildID:  common.StringPtr("v1.1"),
+		},
+	})
+	s.NoError(err)
+	s.Equal(redirectedResp, resp)
+
+	// the workers of the default build ID poll the task list itself
+	resp, err = s.matchingEngine.PollForDecisionTask(s.callContext, &matching.PollForDecisionTaskRequest{
+		DomainUUID: common.StringPtr(domainID),
+		PollRequest: &workflow.PollForDecisionTaskRequest{
+			TaskList: &workflow.TaskList{Name: common.StringPtr(tl)},
+			Identity: common.StringPtr("selfDrivingToaster"),
+			BuildID:  common.StringPtr("v2"),
+		},
+	})
+	s.NoError(err)
+	s.Equal(emptyPollForDecisionTaskResponse, resp)
+	matchingClient.AssertExpectations(s.T())
+}
+
+func (s *matchingEngineSuite) TestPollRedirectsPersistedTaskOfPinnedBuildID() {
+	s.matchingEngine.config.LongPollExpirationInterval = func(...dynamicconfig.FilterOption) time.Duration { return 10 * time.Millisecond }
+	matchingClient := &mocks.MatchingClient{}
+	s.matchingEngine.matchingClient = matchingClient
+
+	domainID := "domainId"
+	tl := "makeToast"
+	tlID := &taskListID{domainID: domainID, taskListName: tl, taskType: persistence.TaskListTypeDecision}
+	workflowExecution := workflow.WorkflowExecution{RunId: common.StringPtr("run1"), WorkflowId: common.StringPtr("workflow1")}
+
+	tlMgr, err := s.matchingEngine.getTaskListManager(tlID, common.TaskListKindPtr(workflow.TaskListKindNormal))
+	s.NoError(err)
+	s.NoError(tlMgr.UpdateBuildIDs("v1", "", false))
+
+	// the task is persisted while v1 is the default build ID
+	err = s.matchingEngine.AddDecisionTask(&matching.AddDecisionTaskRequest{
+		DomainUUID:                    common.StringPtr(domainID),
+		Execution:                     &workflowExecution,
+		ScheduleId:                    common.Int64Ptr(1),
+		TaskList:                      &workflow.TaskList{Name: common.StringPtr(tl)},
+		ScheduleToStartTimeoutSeconds: common.Int32Ptr(100),
+		BuildID:                       common.StringPtr("v1"),
+	})
+	s.NoError(err)
+	s.EqualValues(1, s.taskManager.getTaskCount(tlID))
+
+	// once v2 is the default build ID the task is redirected to the task list of v1 instead of being dispatched
+	s.NoError(tlMgr.UpdateBuildIDs("v2", "", false))
+	matchingClient.On("AddDecisionTask", mock.MatchedBy(func(ctx context.Context) bool {
+		_, ok := ctx.Deadline()
+		return ok
+	}), mock.MatchedBy(func(request *matching.AddDecisionTaskRequest) bool {
+		return request.TaskList.GetName() == common.TaskListBuildIDName(tl, "v1") && request.GetScheduleId() == 1
+	})).Return(nil).Once()
+	resp, err := s.matchingEngine.PollForDecisionTask(s.callContext, &matching.PollForDecisionTaskRequest{
+		DomainUUID: common.StringPtr(domainID),
+		PollRequest: &workflow.PollForDecisionTaskRequest{
+			TaskList: &workflow.TaskList{Name: common.StringPtr(tl)},
+			Identity: common.StringPtr("selfDrivingToaster"),
+		},
+	})
+	s.NoError(err)
+	s.Equal(emptyPollForDecisionTaskResponse, resp)
+	s.EqualValues(0, s.taskManager.getTaskCount(tlID))
+	matchingClient.AssertExpectations(s.T())
+}
+
 func (s *matchingEngineSuite) TestTaskListManagerGetTaskBatch() {
 	runID := "run1"
 	workflowID := "workflow1"
@@ -1376,6 +1612,30 @@ func (s *matchingEngineSuite) TestTaskListManagerGetTaskBatch() {
 	tlMgr.engine.removeTaskListManager(tlMgr.taskListID)
 }
 
+func (s *matchingEngineSuite) TestListTaskListsSkipsInternalTaskLists() {
+	domainID := "domainId"
+	tl := "makeToast"
+	kind := common.TaskListKindPtr(workflow.TaskListKindNormal)
+	// starting the root and a partition of the task list leases their priority backlogs as well
+	for _, name := range []string{tl, common.TaskListPartitionName(tl, 1)} {
+		tlID := newTaskListID(domainID, name, persistence.TaskListTypeActivity)
+		_, err := s.matchingEngine.getTaskListManager(tlID, kind)
+		s.NoError(err)
+		s.True(s.taskManager.getTaskListManager(tlID).rangeID > 0)
+	}
+	priorityID := newTaskListID(domainID, common.TaskListPriorityName(tl, 1), persistence.TaskListTypeActivity)
+	s.True(s.taskManager.getTaskListManager(priorityID).rangeID > 0)
+
+	resp, err := s.matchingEngine.ListTaskLists(s.callContext, &matching.ListTaskListsRequest{
+		DomainUUID:  common.StringPtr(domainID),
+		ListRequest: &workflow.ListTaskListsRequest{},
+	})
+	s.NoError(err)
+	s.Equal(1, len(resp.TaskLists))
+	s.Equal(tl, resp.TaskLists[0].GetName())
+	s.Equal(workflow.TaskListTypeActivity, resp.TaskLists[0].GetTaskListType())
+}
+
 func newActivityTaskScheduledEvent(eventID int64, decisionTaskCompletedEventID int64,
 	scheduleAttributes *workflow.ScheduleActivityTaskDecisionAttributes) *workflow.HistoryEvent {
 	historyEvent := newHistoryEvent(eventID, workflow.EventTypeActivityTaskScheduled)
@@ -1448,6 +1708,7 @@ type testTaskListManager struct {
 	rangeID         int64
 	ackLevel        int64
 	createTaskCount int
+	paused          bool
 	tasks           *treemap.Map
 }
 
@@ -1484,6 +1745,7 @@ func (m *testTaskManager) LeaseTaskList(request *persistence.LeaseTaskListReques
 			TaskType: request.TaskType,
 			RangeID:  tlm.rangeID,
 			Kind:     request.TaskListKind,
+			Paused:   tlm.paused,
 		},
 	}, nil
 }
@@ -1503,9 +1765,33 @@ func (m *testTaskManager) UpdateTaskList(request *persistence.UpdateTaskListRequ
 		}
 	}
 	tlm.ackLevel = tli.AckLevel
+	tlm.paused = tli.Paused
 	return &persistence.UpdateTaskListResponse{}, nil
 }
 
+// ListTaskList provides a mock function with given fields: request
+func (m *testTaskManager) ListTaskList(request *persistence.ListTaskListRequest) (*persistence.ListTaskListResponse, error) {
+	m.Lock()
+	defer m.Unlock()
+	var items []*persistence.TaskListInfo
+	for id, tlm := range m.taskLists {
+		if id.domainID != request.DomainID {
+			continue
+		}
+		tlm.Lock()
+		items = append(items, &persistence.TaskListInfo{
+			DomainID: id.domainID,
+			Name:     id.taskListName,
+			TaskType: id.taskType,
+			RangeID:  tlm.rangeID,
+			AckLevel: tlm.ackLevel,
+			Paused:   tlm.paused,
+		})
+		tlm.Unlock()
+	}
+	return &persistence.ListTaskListResponse{Items: items}, nil
+}
+
 // CompleteTask provides a mock function with given fields: request
 func (m *testTaskManager) CompleteTask(request *persistence.CompleteTaskRequest) error {
 	m.logger.Debugf("CompleteTask taskID=%v, ackLevel=%v", request.TaskID, request.TaskList.AckLevel)
@@ -1565,6 +1851,9 @@ func (m *testTaskManager) CreateTasks(request *persistence.CreateTasksRequest) (
 			ScheduleID: scheduleID,
 			TaskID:     task.TaskID,
 			WorkflowID: *task.Execution.WorkflowId,
+			Priority:   task.Data.Priority,
+			BuildID:    task.Data.BuildID,
+			Expiry:     task.Data.Expiry,
 		})
 		tlm.createTaskCount++
 	}
@@ -21,9 +21,13 @@
 package matching
 
 import (
+	"context"
 	"time"
 
+	"github.com/uber/cadence/common"
 	"github.com/uber/cadence/common/cache"
+	"github.com/uber/cadence/common/client"
+	"go.uber.org/yarpc"
 )
 
 const (
@@ -34,24 +38,27 @@ const (
 
 type (
 	pollerIdentity struct {
-		identity string
-		// TODO add IP, T1396795
+		identity       string
+		ipAddress      string
+		libraryVersion string
+		clientImpl     string
+		buildID        string
 	}
 
 	pollerInfo struct {
-		identity string
-		// TODO add IP, T1396795
+		pollerIdentity
+		ratePerSecond  float64
 		lastAccessTime time.Time
 	}
 )
 
 type pollerHistory struct {
-	// poller ID -> last access time
-	// pollers map[pollerID]time.Time
-	history cache.Cache
+	// poller ID -> polls of the poller over the sliding window of the dispatch statistics
+	history    cache.Cache
+	timeSource common.TimeSource
 }
 
-func newPollerHistory() *pollerHistory {
+func newPollerHistory(timeSource common.TimeSource) *pollerHistory {
 	opts := &cache.Options{
 		InitialCapacity: pollerHistoryInitSize,
 		TTL:             pollerHistoryTTL,
@@ -59,12 +66,40 @@ func newPollerHistory() *pollerHistory {
 	}
 
 	return &pollerHistory{
-		history: cache.New(pollerHistoryInitMaxSize, opts),
+		history:    cache.New(pollerHistoryInitMaxSize, opts),
+		timeSource: timeSource,
 	}
 }
 
+// newPollerIdentity returns the identity of a poller along with the IP address and the client library of the
+// worker, which the frontend passes on as headers of the poll request
+func newPollerIdentity(ctx context.Context, identity string, buildID string) pollerIdentity {
+	call := yarpc.CallFromContext(ctx)
+	feature := client.NewFeatureImpl(
+		call.Header(common.LibraryVersionHeaderName),
+		call.Header(common.FeatureVersionHeaderName),
+		call.Header(common.ClientImplHeaderName),
+	)
+	return pollerIdentity{
+		identity:       identity,
+		ipAddress:      call.Header(common.CallerIPHeaderName),
+		libraryVersion: feature.LibraryVersion(),
+		clientImpl:     feature.ClientImpl(),
+		buildID:        buildID,
+	}
+}
+
+// updatePollerInfo records a poll of the poller.  The polls are counted per second like the dispatched tasks, so
+// the rate of a poller is the number of polls it made over the last minute.
 func (pollers *pollerHistory) updatePollerInfo(id pollerIdentity) {
-	pollers.history.Put(id, nil)
+	value, err := pollers.history.PutIfNotExist(id, newDispatchStats(pollers.timeSource))
+	if err != nil {
+		return
+	}
+	polls := value.(*dispatchStats)
+	polls.add(false)
+	// Put again to refresh the last access time and the TTL of the poller
+	pollers.history.Put(id, polls)
 }
 
 func (pollers *pollerHistory) getAllPollerInfo() []*pollerInfo {
@@ -75,10 +110,11 @@ func (pollers *pollerHistory) getAllPollerInfo() []*pollerInfo {
 	for ite.HasNext() {
 		entry := ite.Next()
 		key := entry.Key().(pollerIdentity)
+		ratePerSecond, _ := entry.Value().(*dispatchStats).get()
 		lastAccessTime := entry.CreateTime()
 		result = append(result, &pollerInfo{
-			identity: key.identity,
-			// TODO add IP, T1396795
+			pollerIdentity: key,
+			ratePerSecond:  ratePerSecond,
 			lastAccessTime: lastAccessTime,
 		})
 	}
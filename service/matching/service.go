@@ -41,6 +41,8 @@ type Config struct {
 	// Time to hold a poll request before returning an empty response if there are no tasks
 	LongPollExpirationInterval dynamicconfig.DurationPropertyFn
 	MinTaskThrottlingBurstSize dynamicconfig.IntPropertyFn
+	// Number of tasks dispatched ahead of the oldest buffered task before it is dispatched regardless of priority
+	PriorityStarvationThreshold dynamicconfig.IntPropertyFn
 
 	// taskWriter configuration
 	OutstandingTaskAppendsThreshold int
@@ -69,6 +71,9 @@ func NewConfig(dc *dynamicconfig.Collection) *Config {
 		MinTaskThrottlingBurstSize: dc.GetIntProperty(
 			dynamicconfig.MatchingMinTaskThrottlingBurstSize, 1,
 		),
+		PriorityStarvationThreshold: dc.GetIntProperty(
+			dynamicconfig.MatchingPriorityStarvationThreshold, 10,
+		),
 		OutstandingTaskAppendsThreshold: 250,
 		MaxTaskBatchSize:                100,
 	}
@@ -100,13 +105,29 @@ func (s *Service) Start() {
 
 	base := service.New(p)
 
-	taskPersistence, err := persistence.NewCassandraTaskPersistence(p.CassandraConfig.Hosts,
-		p.CassandraConfig.Port,
-		p.CassandraConfig.User,
-		p.CassandraConfig.Password,
-		p.CassandraConfig.Datacenter,
-		p.CassandraConfig.Keyspace,
-		base.GetLogger())
+	var taskPersistence persistence.TaskManager
+	var err error
+	if p.InMemoryStore != nil {
+		taskPersistence, err = persistence.NewInMemoryTaskPersistence(p.InMemoryStore, p.Logger)
+	} else if p.SQLConfig != nil {
+		taskPersistence, err = persistence.NewSQLTaskPersistence(p.SQLConfig.DriverName,
+			p.SQLConfig.Host,
+			p.SQLConfig.Port,
+			p.SQLConfig.User,
+			p.SQLConfig.Password,
+			p.SQLConfig.DatabaseName,
+			p.SQLConfig.MaxConns,
+			p.Logger,
+		)
+	} else {
+		taskPersistence, err = persistence.NewCassandraTaskPersistence(p.CassandraConfig.Hosts,
+			p.CassandraConfig.Port,
+			p.CassandraConfig.User,
+			p.CassandraConfig.Password,
+			p.CassandraConfig.Datacenter,
+			p.CassandraConfig.Keyspace,
+			base.GetLogger())
+	}
 
 	if err != nil {
 		log.Fatalf("failed to create task persistence: %v", err)
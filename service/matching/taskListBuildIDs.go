@@ -0,0 +1,158 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"fmt"
+
+	s "github.com/uber/cadence/.gen/go/shared"
+)
+
+type (
+	// taskListBuildIDs holds the build IDs of the workers polling a decision task list.  Build IDs are grouped into
+	// sets of compatible build IDs, the last set holds the default build IDs which new workflows are dispatched to.
+	// The decision tasks of a workflow pinned to a build ID of another set are dispatched by a separate task list,
+	// named after the first build ID of the set, which is only polled by the workers of that set.  Retired build IDs
+	// are no longer polled for, so their workflows are dispatched to the default build IDs again.
+	// A taskListBuildIDs is never modified once created.
+	taskListBuildIDs struct {
+		compatible [][]string
+		retired    []string
+	}
+)
+
+func newTaskListBuildIDs(compatible [][]string, retired []string) *taskListBuildIDs {
+	return &taskListBuildIDs{compatible: compatible, retired: retired}
+}
+
+// taskTarget returns the build ID of the task list the decision tasks of a workflow pinned to the given build ID are
+// dispatched by, "" if they are dispatched by the task list itself
+func (b *taskListBuildIDs) taskTarget(buildID string) string {
+	if buildID == "" || len(b.compatible) == 0 || b.isRetired(buildID) {
+		return ""
+	}
+	return b.target(buildID)
+}
+
+// pollerTarget returns the build ID of the task list the workers with the given build ID poll, "" if they poll the
+// task list itself
+func (b *taskListBuildIDs) pollerTarget(buildID string) string {
+	if buildID == "" || len(b.compatible) == 0 {
+		return ""
+	}
+	if b.isRetired(buildID) {
+		return buildID
+	}
+	return b.target(buildID)
+}
+
+func (b *taskListBuildIDs) target(buildID string) string {
+	set := b.findSet(buildID)
+	switch set {
+	case -1:
+		// an unknown build ID keeps its own task list until it is added to a set
+		return buildID
+	case len(b.compatible) - 1:
+		return ""
+	default:
+		return b.compatible[set][0]
+	}
+}
+
+// update returns the build IDs after retiring a build ID or adding it to the set of compatibleBuildID, a build ID
+// added without compatibleBuildID becomes the default build ID
+func (b *taskListBuildIDs) update(buildID string, compatibleBuildID string, retire bool) (*taskListBuildIDs, error) {
+	if retire {
+		if b.isRetired(buildID) {
+			return b, nil
+		}
+		return newTaskListBuildIDs(b.compatible, append(copyStrings(b.retired), buildID)), nil
+	}
+
+	retired := []string{}
+	for _, id := range b.retired {
+		if id != buildID {
+			retired = append(retired, id)
+		}
+	}
+	compatible := make([][]string, 0, len(b.compatible)+1)
+	compatible = append(compatible, b.compatible...)
+	set := b.findSet(buildID)
+	if compatibleBuildID == "" {
+		if set == -1 {
+			compatible = append(compatible, []string{buildID})
+		} else {
+			// an existing set becomes the default set again
+			defaultSet := compatible[set]
+			compatible = append(append(compatible[:set], compatible[set+1:]...), defaultSet)
+		}
+		return newTaskListBuildIDs(compatible, retired), nil
+	}
+
+	compatibleSet := b.findSet(compatibleBuildID)
+	if compatibleSet == -1 {
+		return nil, &s.BadRequestError{Message: fmt.Sprintf("Unknown compatible build ID %v.", compatibleBuildID)}
+	}
+	if set != -1 && set != compatibleSet {
+		return nil, &s.BadRequestError{
+			Message: fmt.Sprintf("Build ID %v is not compatible with %v.", buildID, compatibleBuildID),
+		}
+	}
+	if set == -1 {
+		compatible[compatibleSet] = append(copyStrings(compatible[compatibleSet]), buildID)
+	}
+	return newTaskListBuildIDs(compatible, retired), nil
+}
+
+func (b *taskListBuildIDs) findSet(buildID string) int {
+	for i, set := range b.compatible {
+		for _, id := range set {
+			if id == buildID {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func (b *taskListBuildIDs) isRetired(buildID string) bool {
+	for _, id := range b.retired {
+		if id == buildID {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *taskListBuildIDs) toThrift() *s.TaskListBuildIDs {
+	result := &s.TaskListBuildIDs{
+		CompatibleBuildIDs: []*s.CompatibleBuildIDs{},
+		RetiredBuildIDs:    copyStrings(b.retired),
+	}
+	for _, set := range b.compatible {
+		result.CompatibleBuildIDs = append(result.CompatibleBuildIDs, &s.CompatibleBuildIDs{BuildIDs: copyStrings(set)})
+	}
+	return result
+}
+
+func copyStrings(values []string) []string {
+	return append([]string{}, values...)
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	workflow "github.com/uber/cadence/.gen/go/shared"
+)
+
+func TestBuildIDsTargetWithoutSets(t *testing.T) {
+	b := newTaskListBuildIDs(nil, nil)
+	assert.Equal(t, "", b.taskTarget(""))
+	assert.Equal(t, "", b.taskTarget("v1"))
+	assert.Equal(t, "", b.pollerTarget("v1"))
+}
+
+func TestBuildIDsTarget(t *testing.T) {
+	b := newTaskListBuildIDs([][]string{{"v1", "v1.1"}, {"v2"}}, []string{"v0"})
+
+	assert.Equal(t, "", b.taskTarget(""))
+	assert.Equal(t, "v1", b.taskTarget("v1.1"))
+	assert.Equal(t, "", b.taskTarget("v2"))
+	assert.Equal(t, "", b.taskTarget("v0"))
+	assert.Equal(t, "v3", b.taskTarget("v3"))
+
+	assert.Equal(t, "", b.pollerTarget(""))
+	assert.Equal(t, "v1", b.pollerTarget("v1.1"))
+	assert.Equal(t, "", b.pollerTarget("v2"))
+	assert.Equal(t, "v0", b.pollerTarget("v0"))
+	assert.Equal(t, "v3", b.pollerTarget("v3"))
+}
+
+func TestBuildIDsUpdate(t *testing.T) {
+	b := newTaskListBuildIDs(nil, nil)
+
+	b1, err := b.update("v1", "", false)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"v1"}}, b1.compatible)
+
+	b2, err := b1.update("v2", "", false)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"v1"}, {"v2"}}, b2.compatible)
+
+	b3, err := b2.update("v1.1", "v1", false)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"v1", "v1.1"}, {"v2"}}, b3.compatible)
+	assert.Equal(t, [][]string{{"v1"}, {"v2"}}, b2.compatible)
+
+	b4, err := b3.update("v1.1", "", false)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"v2"}, {"v1", "v1.1"}}, b4.compatible)
+	assert.Equal(t, [][]string{{"v1", "v1.1"}, {"v2"}}, b3.compatible)
+
+	b5, err := b4.update("v2", "", true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v2"}, b5.retired)
+	assert.Empty(t, b4.retired)
+	assert.Equal(t, "", b5.taskTarget("v2"))
+
+	b6, err := b5.update("v2", "", false)
+	assert.NoError(t, err)
+	assert.Empty(t, b6.retired)
+	assert.Equal(t, [][]string{{"v1", "v1.1"}, {"v2"}}, b6.compatible)
+}
+
+func TestBuildIDsUpdateErrors(t *testing.T) {
+	b := newTaskListBuildIDs([][]string{{"v1"}, {"v2"}}, nil)
+
+	_, err := b.update("v3", "v0", false)
+	assert.IsType(t, &workflow.BadRequestError{}, err)
+
+	_, err = b.update("v2", "v1", false)
+	assert.IsType(t, &workflow.BadRequestError{}, err)
+}
+
+func TestBuildIDsToThrift(t *testing.T) {
+	b := newTaskListBuildIDs([][]string{{"v1", "v1.1"}, {"v2"}}, []string{"v0"})
+	result := b.toThrift()
+	assert.Equal(t, 2, len(result.CompatibleBuildIDs))
+	assert.Equal(t, []string{"v1", "v1.1"}, result.CompatibleBuildIDs[0].BuildIDs)
+	assert.Equal(t, []string{"v2"}, result.CompatibleBuildIDs[1].BuildIDs)
+	assert.Equal(t, []string{"v0"}, result.RetiredBuildIDs)
+}
@@ -54,6 +54,12 @@ const (
 	_defaultTaskDispatchRPSTTL = 60 * time.Second
 )
 
+// maxBacklogPriority is the highest priority level whose tasks are persisted to a backlog of their own, the tasks of
+// a normal task list with a priority above 0 are persisted to the backlog of their level so they are read from
+// persistence ahead of the backlog of tasks with a lower priority.  Tasks with a higher priority share the backlog
+// of the highest level.
+const maxBacklogPriority = 2
+
 var errAddTasklistThrottled = errors.New("cannot add to tasklist, limit exceeded")
 
 type taskListManager interface {
@@ -61,9 +67,16 @@ type taskListManager interface {
 	Stop()
 	AddTask(execution *s.WorkflowExecution, taskInfo *persistence.TaskInfo) error
 	GetTaskContext(ctx context.Context, maxDispatchPerSecond *float64) (*taskContext, error)
+	TryGetTaskContext(ctx context.Context, maxDispatchPerSecond *float64) (*taskContext, error)
+	TrySyncMatch(taskInfo *persistence.TaskInfo) (bool, error)
 	SyncMatchQueryTask(ctx context.Context, queryTask *queryTaskInfo) error
 	CancelPoller(pollerID string)
 	GetAllPollerInfo() []*pollerInfo
+	GetTaskListStatus() *s.TaskListStatus
+	GetBuildIDs() *taskListBuildIDs
+	UpdateBuildIDs(buildID string, compatibleBuildID string, retire bool) error
+	IsPaused() bool
+	SetPaused(paused bool) error
 	String() string
 }
 
@@ -76,6 +89,8 @@ type taskListConfig struct {
 	UpdateAckInterval          func() time.Duration
 	IdleTasklistCheckInterval  func() time.Duration
 	MinTaskThrottlingBurstSize func() int
+	// Number of tasks dispatched ahead of the oldest buffered task before it is dispatched regardless of priority
+	PriorityStarvationThreshold func() int
 	// taskWriter configuration
 	OutstandingTaskAppendsThreshold int
 	MaxTaskBatchSize                int
@@ -98,6 +113,9 @@ func newTaskListConfig(id *taskListID, config *Config) *taskListConfig {
 		MinTaskThrottlingBurstSize: func() int {
 			return config.MinTaskThrottlingBurstSize(tlOpt)
 		},
+		PriorityStarvationThreshold: func() int {
+			return config.PriorityStarvationThreshold(tlOpt)
+		},
 		EnableSyncMatch: func() bool {
 			return config.EnableSyncMatch(tlOpt)
 		},
@@ -168,6 +186,12 @@ func (rl *rateLimiter) shouldUpdate(maxDispatchPerSecond *float64) bool {
 	}
 }
 
+func (rl *rateLimiter) GetMaxDispatch() float64 {
+	rl.RLock()
+	defer rl.RUnlock()
+	return *rl.maxDispatchPerSecond
+}
+
 func (rl *rateLimiter) Wait(ctx context.Context) error {
 	limiter := rl.globalLimiter.Load().(*rate.Limiter)
 	return limiter.Wait(ctx)
@@ -195,12 +219,32 @@ func newTaskListManagerWithRateLimiter(
 	e *matchingEngineImpl, taskList *taskListID, taskListKind *s.TaskListKind, config *taskListConfig,
 	rl rateLimiter,
 ) taskListManager {
+	tlMgr := createTaskListManager(e, taskList, taskListKind, config, rl)
+	if tlMgr.getTaskListKind() == int(s.TaskListKindNormal) {
+		for level := 1; level <= maxBacklogPriority; level++ {
+			backlogID := newTaskListID(taskList.domainID, common.TaskListPriorityName(taskList.taskListName, level),
+				taskList.taskType)
+			backlog := createTaskListManager(e, backlogID, taskListKind, config, rl)
+			backlog.owner = tlMgr
+			backlog.pollerHistory = tlMgr.pollerHistory
+			tlMgr.priorityBacklogs = append(tlMgr.priorityBacklogs, backlog)
+		}
+	}
+	return tlMgr
+}
+
+func createTaskListManager(
+	e *matchingEngineImpl, taskList *taskListID, taskListKind *s.TaskListKind, config *taskListConfig,
+	rl rateLimiter,
+) *taskListManagerImpl {
 	// To perform one db operation if there are no pollers
 	taskBufferSize := config.GetTasksBatchSize() - 1
 	ctx, cancel := context.WithCancel(context.Background())
 	tlMgr := &taskListManagerImpl{
 		engine:                  e,
 		taskBuffer:              make(chan *persistence.TaskInfo, taskBufferSize),
+		taskQueue:               newTaskPriorityQueue(config.PriorityStarvationThreshold),
+		bufferedTaskCh:          make(chan struct{}, 1),
 		notifyCh:                make(chan struct{}, 1),
 		shutdownCh:              make(chan struct{}),
 		deliverBufferShutdownCh: make(chan struct{}),
@@ -215,10 +259,13 @@ func newTaskListManagerWithRateLimiter(
 		taskAckManager:      newAckManager(e.logger),
 		tasksForPoll:        make(chan *getTaskResult),
 		config:              config,
-		pollerHistory:       newPollerHistory(),
+		pollerHistory:       newPollerHistory(common.NewRealTimeSource()),
 		outstandingPollsMap: make(map[string]context.CancelFunc),
 		rateLimiter:         rl,
+		dispatchStats:       newDispatchStats(common.NewRealTimeSource()),
 		taskListKind:        taskListKind,
+		buildIDs:            newTaskListBuildIDs(nil, nil),
+		pauseStateCh:        make(chan struct{}),
 	}
 	tlMgr.taskWriter = newTaskWriter(tlMgr)
 	tlMgr.startWG.Add(1)
@@ -233,6 +280,9 @@ type taskContext struct {
 	workflowExecution s.WorkflowExecution
 	queryTaskInfo     *queryTaskInfo
 	backlogCountHint  int64
+	// backlog is the task list manager which loaded the task from persistence, it is tlMgr unless the task was
+	// loaded from a priority backlog of tlMgr
+	backlog *taskListManagerImpl
 }
 
 type queryTaskInfo struct {
@@ -257,6 +307,16 @@ type taskListManagerImpl struct {
 	persistenceLock sync.Mutex
 	taskWriter      *taskWriter
 	taskBuffer      chan *persistence.TaskInfo // tasks loaded from persistence
+	// taskQueue orders the tasks moved out of taskBuffer and the task buffers of the priority backlogs by priority
+	// before they are delivered to pollers
+	taskQueue *taskPriorityQueue
+	// bufferedTaskCh notifies the loop delivering tasks to pollers of tasks added to a task buffer
+	bufferedTaskCh chan struct{}
+	// priorityBacklogs persist the tasks with a priority above 0 by priority level, starting from level 1.  Their
+	// pumps load the tasks which are delivered to the pollers of this task list.
+	priorityBacklogs []*taskListManagerImpl
+	// owner is the task list manager a priority backlog belongs to, nil if this is not a priority backlog
+	owner *taskListManagerImpl
 	// tasksForPoll is used to deliver tasks to pollers.
 	// It must to be unbuffered. addTask publishes to it asynchronously and expects publish to succeed
 	// only if there is waiting poll that consumes from it. Tasks in taskBuffer will blocking-add to
@@ -282,6 +342,11 @@ type taskListManagerImpl struct {
 	rangeID                 int64      // Current range of the task list. Starts from 1.
 	taskSequenceNumber      int64      // Sequence number of the next task. Starts from 1.
 	nextRangeSequenceNumber int64      // Current range boundary
+	buildIDs                *taskListBuildIDs
+	// tasks of a paused task list are persisted and stay buffered instead of being dispatched to pollers
+	paused bool
+	// pauseStateCh is closed and replaced whenever the task list is paused or resumed
+	pauseStateCh chan struct{}
 
 	// outstandingPollsMap is needed to keep track of all outstanding pollers for a
 	// particular tasklist.  PollerID generated by frontend is used as the key and
@@ -292,6 +357,8 @@ type taskListManagerImpl struct {
 	outstandingPollsMap  map[string]context.CancelFunc
 	// Rate limiter for task dispatch
 	rateLimiter rateLimiter
+	// dispatchStats tracks the recent dispatch rate and sync match ratio reported by DescribeTaskList
+	dispatchStats *dispatchStats
 
 	taskListKind *s.TaskListKind // sticky taskList has different process in persistence
 }
@@ -303,6 +370,8 @@ type getTaskResult struct {
 	C         chan *syncMatchResponse
 	queryTask *queryTaskInfo
 	syncMatch bool
+	// backlog is the task list manager which loaded the task from persistence, nil for a task which is not persisted
+	backlog *taskListManagerImpl
 }
 
 // syncMatchResponse result of sync match delivered to a createTask caller
@@ -327,6 +396,12 @@ func (c *taskListManagerImpl) Start() error {
 	c.signalNewTask()
 	go c.getTasksPump()
 
+	for _, backlog := range c.priorityBacklogs {
+		if err := backlog.Start(); err != nil {
+			c.Stop()
+			return err
+		}
+	}
 	return nil
 }
 
@@ -339,26 +414,55 @@ func (c *taskListManagerImpl) Stop() {
 	c.cancelFunc()
 	close(c.shutdownCh)
 	c.taskWriter.Stop()
+	// a task list and its priority backlogs are loaded and unloaded together
+	for _, backlog := range c.priorityBacklogs {
+		backlog.Stop()
+	}
+	if c.owner != nil {
+		c.owner.Stop()
+	}
 	c.engine.removeTaskListManager(c.taskListID)
 	logging.LogTaskListUnloadedEvent(c.logger)
 }
 
 func (c *taskListManagerImpl) AddTask(execution *s.WorkflowExecution, taskInfo *persistence.TaskInfo) error {
 	c.startWG.Wait()
-	_, err := c.executeWithRetry(func(rangeID int64) (interface{}, error) {
+	backlog := c.getBacklog(taskInfo.Priority)
+	_, err := backlog.executeWithRetry(func(rangeID int64) (interface{}, error) {
 		r, err := c.trySyncMatch(taskInfo)
 		if (err != nil && err != errAddTasklistThrottled) || r != nil {
 			return r, err
 		}
-		r, err = c.taskWriter.appendTask(execution, taskInfo, rangeID)
+		r, err = backlog.taskWriter.appendTask(execution, taskInfo, rangeID)
 		return r, err
 	})
 	if err == nil {
-		c.signalNewTask()
+		backlog.signalNewTask()
 	}
 	return err
 }
 
+// getBacklog returns the task list manager which persists the tasks of the given priority
+func (c *taskListManagerImpl) getBacklog(priority int32) *taskListManagerImpl {
+	if priority <= 0 || len(c.priorityBacklogs) == 0 {
+		return c
+	}
+	if int(priority) > len(c.priorityBacklogs) {
+		return c.priorityBacklogs[len(c.priorityBacklogs)-1]
+	}
+	return c.priorityBacklogs[priority-1]
+}
+
+// TrySyncMatch hands the task to a waiting poller without persisting it, it returns false when no poller is waiting
+func (c *taskListManagerImpl) TrySyncMatch(taskInfo *persistence.TaskInfo) (bool, error) {
+	c.startWG.Wait()
+	r, err := c.trySyncMatch(taskInfo)
+	if err == errAddTasklistThrottled {
+		return false, nil
+	}
+	return r != nil, err
+}
+
 func (c *taskListManagerImpl) SyncMatchQueryTask(ctx context.Context, queryTask *queryTaskInfo) error {
 	c.startWG.Wait()
 
@@ -384,9 +488,31 @@ func (c *taskListManagerImpl) SyncMatchQueryTask(ctx context.Context, queryTask
 func (c *taskListManagerImpl) GetTaskContext(
 	ctx context.Context,
 	maxDispatchPerSecond *float64,
+) (*taskContext, error) {
+	return c.getTaskContext(ctx, maxDispatchPerSecond, false)
+}
+
+// TryGetTaskContext is GetTaskContext returning ErrNoTasks right away instead of waiting for a task
+func (c *taskListManagerImpl) TryGetTaskContext(
+	ctx context.Context,
+	maxDispatchPerSecond *float64,
+) (*taskContext, error) {
+	return c.getTaskContext(ctx, maxDispatchPerSecond, true)
+}
+
+func (c *taskListManagerImpl) getTaskContext(
+	ctx context.Context,
+	maxDispatchPerSecond *float64,
+	noWait bool,
 ) (*taskContext, error) {
 	c.rateLimiter.UpdateMaxDispatch(maxDispatchPerSecond)
-	result, err := c.getTask(ctx)
+
+	identity, ok := ctx.Value(identityKey).(pollerIdentity)
+	if ok && identity.identity != "" {
+		c.pollerHistory.updatePollerInfo(identity)
+	}
+
+	result, err := c.getTask(ctx, noWait)
 	if err != nil {
 		return nil, err
 	}
@@ -395,17 +521,40 @@ func (c *taskListManagerImpl) GetTaskContext(
 		WorkflowId: common.StringPtr(task.WorkflowID),
 		RunId:      common.StringPtr(task.RunID),
 	}
+	backlog := c
+	if result.backlog != nil {
+		backlog = result.backlog
+	}
 	tCtx := &taskContext{
 		info:              task,
 		workflowExecution: workflowExecution,
 		tlMgr:             c,
+		backlog:           backlog,
 		syncResponseCh:    result.C,         // nil if task is loaded from persistence
 		queryTaskInfo:     result.queryTask, // non-nil for query task
-		backlogCountHint:  c.taskAckManager.getBacklogCountHint(),
+		backlogCountHint:  c.getBacklogCountHint(),
 	}
 	return tCtx, nil
 }
 
+// getBacklogCountHint returns the number of tasks loaded from the persisted backlogs of this task list which are not
+// completed yet
+func (c *taskListManagerImpl) getBacklogCountHint() int64 {
+	count := c.taskAckManager.getBacklogCountHint()
+	for _, backlog := range c.priorityBacklogs {
+		count += backlog.taskAckManager.getBacklogCountHint()
+	}
+	return count
+}
+
+// hasBacklog returns true if tasks persisted to this task list may not be completed yet
+func (c *taskListManagerImpl) hasBacklog() bool {
+	maxReadLevel := c.taskWriter.GetMaxReadLevel()
+	c.Lock()
+	defer c.Unlock()
+	return c.taskAckManager.getBacklogCountHint() > 0 || c.taskAckManager.getReadLevel() < maxReadLevel
+}
+
 func (c *taskListManagerImpl) getRangeID() int64 {
 	c.Lock()
 	defer c.Unlock()
@@ -420,22 +569,104 @@ func (c *taskListManagerImpl) isEqualRangeID(rangeID int64) bool {
 }
 
 func (c *taskListManagerImpl) persistAckLevel() error {
+	// the request is created while holding the persistence lock so it never overwrites newer build IDs
+	c.persistenceLock.Lock()
+	defer c.persistenceLock.Unlock()
 	c.Lock()
-	updateTaskListRequest := &persistence.UpdateTaskListRequest{
-		TaskListInfo: &persistence.TaskListInfo{
-			DomainID: c.taskListID.domainID,
-			Name:     c.taskListID.taskListName,
-			TaskType: c.taskListID.taskType,
-			AckLevel: c.taskAckManager.getAckLevel(),
-			RangeID:  c.rangeID,
-			Kind:     c.getTaskListKind(),
-		},
+	updateTaskListRequest := c.newUpdateTaskListRequestLocked(c.buildIDs, c.paused)
+	c.Unlock()
+	_, err := c.engine.taskManager.UpdateTaskList(updateTaskListRequest)
+	return err
+}
+
+// GetBuildIDs returns the build IDs of the workers polling this task list
+func (c *taskListManagerImpl) GetBuildIDs() *taskListBuildIDs {
+	c.Lock()
+	defer c.Unlock()
+	return c.buildIDs
+}
+
+// UpdateBuildIDs retires a build ID or adds it to the set of compatibleBuildID, a build ID added without
+// compatibleBuildID becomes the default build ID of the task list
+func (c *taskListManagerImpl) UpdateBuildIDs(buildID string, compatibleBuildID string, retire bool) error {
+	c.persistenceLock.Lock()
+	defer c.persistenceLock.Unlock()
+	c.Lock()
+	buildIDs, err := c.buildIDs.update(buildID, compatibleBuildID, retire)
+	if err != nil {
+		c.Unlock()
+		return err
+	}
+	updateTaskListRequest := c.newUpdateTaskListRequestLocked(buildIDs, c.paused)
+	c.Unlock()
+	if _, err := c.engine.taskManager.UpdateTaskList(updateTaskListRequest); err != nil {
+		return err
 	}
+	c.Lock()
+	c.buildIDs = buildIDs
 	c.Unlock()
+	return nil
+}
+
+// IsPaused returns true if the tasks of this task list are not dispatched to pollers
+func (c *taskListManagerImpl) IsPaused() bool {
+	c.Lock()
+	defer c.Unlock()
+	return c.paused
+}
+
+// SetPaused pauses or resumes dispatching the tasks of this task list to pollers.  Query tasks are not persisted
+// and are still dispatched while the task list is paused.
+func (c *taskListManagerImpl) SetPaused(paused bool) error {
 	c.persistenceLock.Lock()
 	defer c.persistenceLock.Unlock()
-	_, err := c.engine.taskManager.UpdateTaskList(updateTaskListRequest)
-	return err
+	c.Lock()
+	if c.paused == paused {
+		c.Unlock()
+		return nil
+	}
+	updateTaskListRequest := c.newUpdateTaskListRequestLocked(c.buildIDs, paused)
+	c.Unlock()
+	if _, err := c.engine.taskManager.UpdateTaskList(updateTaskListRequest); err != nil {
+		return err
+	}
+	c.Lock()
+	c.setPausedLocked(paused)
+	c.Unlock()
+	return nil
+}
+
+func (c *taskListManagerImpl) setPausedLocked(paused bool) {
+	if c.paused == paused {
+		return
+	}
+	c.paused = paused
+	close(c.pauseStateCh)
+	c.pauseStateCh = make(chan struct{})
+}
+
+// getPauseState returns whether the task list is paused and a channel which is closed once that changes
+func (c *taskListManagerImpl) getPauseState() (bool, <-chan struct{}) {
+	c.Lock()
+	defer c.Unlock()
+	return c.paused, c.pauseStateCh
+}
+
+func (c *taskListManagerImpl) newUpdateTaskListRequestLocked(
+	buildIDs *taskListBuildIDs, paused bool) *persistence.UpdateTaskListRequest {
+	return &persistence.UpdateTaskListRequest{
+		TaskListInfo: &persistence.TaskListInfo{
+			DomainID:           c.taskListID.domainID,
+			Name:               c.taskListID.taskListName,
+			TaskType:           c.taskListID.taskType,
+			AckLevel:           c.taskAckManager.getAckLevel(),
+			RangeID:            c.rangeID,
+			Kind:               c.getTaskListKind(),
+			CompatibleBuildIDs: buildIDs.compatible,
+			RetiredBuildIDs:    buildIDs.retired,
+			Paused:             paused,
+		},
+	}
 }
 
 // newTaskIDs taskID to use to persist the task
@@ -483,8 +714,9 @@ func (c *taskListManagerImpl) completeTaskPoll(taskID int64) (ackLevel int64) {
 	return
 }
 
-// Loads task from taskBuffer (which is populated from persistence) or from sync match to add task call
-func (c *taskListManagerImpl) getTask(ctx context.Context) (*getTaskResult, error) {
+// Loads task from taskBuffer (which is populated from persistence) or from sync match to add task call,
+// returns ErrNoTasks without waiting when noWait is set and no task is available
+func (c *taskListManagerImpl) getTask(ctx context.Context, noWait bool) (*getTaskResult, error) {
 	scope := metrics.MatchingTaskListMgrScope
 	timer := time.NewTimer(c.config.LongPollExpirationInterval())
 	defer timer.Stop()
@@ -507,19 +739,19 @@ func (c *taskListManagerImpl) getTask(ctx context.Context) (*getTaskResult, erro
 		}()
 	}
 
-	identity, ok := ctx.Value(identityKey).(string)
-	if ok && identity != "" {
-		c.pollerHistory.updatePollerInfo(pollerIdentity{
-			identity: identity,
-		})
+	if noWait {
+		select {
+		case result := <-c.tasksForPoll:
+			c.recordPollSuccess(result)
+			return result, nil
+		default:
+			return nil, ErrNoTasks
+		}
 	}
 
 	select {
 	case result := <-c.tasksForPoll:
-		if result.syncMatch {
-			c.metricsClient.IncCounter(scope, metrics.PollSuccessWithSyncCounter)
-		}
-		c.metricsClient.IncCounter(scope, metrics.PollSuccessCounter)
+		c.recordPollSuccess(result)
 		return result, nil
 	case <-timer.C:
 		c.metricsClient.IncCounter(scope, metrics.PollTimeoutCounter)
@@ -534,6 +766,17 @@ func (c *taskListManagerImpl) getTask(ctx context.Context) (*getTaskResult, erro
 	}
 }
 
+func (c *taskListManagerImpl) recordPollSuccess(result *getTaskResult) {
+	scope := metrics.MatchingTaskListMgrScope
+	if result.queryTask == nil {
+		c.dispatchStats.add(result.syncMatch)
+	}
+	if result.syncMatch {
+		c.metricsClient.IncCounter(scope, metrics.PollSuccessWithSyncCounter)
+	}
+	c.metricsClient.IncCounter(scope, metrics.PollSuccessCounter)
+}
+
 func (c *taskListManagerImpl) CancelPoller(pollerID string) {
 	c.outstandingPollsLock.Lock()
 	cancel, ok := c.outstandingPollsMap[pollerID]
@@ -617,13 +860,19 @@ func (c *taskListManagerImpl) updateRangeIfNeededLocked(e *matchingEngineImpl) e
 
 	if err != nil {
 		c.metricsClient.IncCounter(metrics.MatchingTaskListMgrScope, metrics.LeaseFailureCounter)
-		c.engine.unloadTaskList(c.taskListID)
+		if c.owner != nil {
+			c.engine.unloadTaskList(c.owner.taskListID)
+		} else {
+			c.engine.unloadTaskList(c.taskListID)
+		}
 		return err
 	}
 
 	tli := resp.TaskListInfo
 	c.rangeID = tli.RangeID // Starts from 1
 	c.taskAckManager.setAckLevel(tli.AckLevel)
+	c.buildIDs = newTaskListBuildIDs(tli.CompatibleBuildIDs, tli.RetiredBuildIDs)
+	c.setPausedLocked(tli.Paused)
 	c.taskSequenceNumber = (tli.RangeID-1)*c.config.RangeSize + 1
 	c.nextRangeSequenceNumber = (tli.RangeID)*c.config.RangeSize + 1
 	c.logger.Debugf("updateRangeLocked rangeID=%v, c.taskSequenceNumber=%v, c.nextRangeSequenceNumber=%v",
@@ -661,12 +910,36 @@ func (c *taskListManagerImpl) GetAllPollerInfo() []*pollerInfo {
 	return c.pollerHistory.getAllPollerInfo()
 }
 
+// GetTaskListStatus returns the backlog and the dispatch statistics of this tasklist
+func (c *taskListManagerImpl) GetTaskListStatus() *s.TaskListStatus {
+	ratePerSecond, syncMatchRatio := c.dispatchStats.get()
+	maxRatePerSecond := c.rateLimiter.GetMaxDispatch()
+
+	backlogCountHint := c.getBacklogCountHint()
+
+	c.Lock()
+	defer c.Unlock()
+	return &s.TaskListStatus{
+		BacklogCountHint: common.Int64Ptr(backlogCountHint),
+		ReadLevel:        common.Int64Ptr(c.taskAckManager.getReadLevel()),
+		AckLevel:         common.Int64Ptr(c.taskAckManager.getAckLevel()),
+		RangeID:          common.Int64Ptr(c.rangeID),
+		RatePerSecond:    common.Float64Ptr(ratePerSecond),
+		MaxRatePerSecond: common.Float64Ptr(maxRatePerSecond),
+		SyncMatchRatio:   common.Float64Ptr(syncMatchRatio),
+	}
+}
+
 // Tries to match task to a poller that is already waiting on getTask.
 // When this method returns non nil response without error it is guaranteed that the task is started
 // and sent to a poller. So it not necessary to persist it.
 // Returns (nil, nil) if there is no waiting poller which indicates that task has to be persisted.
 func (c *taskListManagerImpl) trySyncMatch(task *persistence.TaskInfo) (*persistence.CreateTasksResponse, error) {
-	if !c.config.EnableSyncMatch() {
+	if !c.config.EnableSyncMatch() || c.IsPaused() {
+		return nil, nil
+	}
+	// A task is persisted instead of being matched while tasks with a higher priority wait to be dispatched
+	if c.hasHigherPriorityBacklog(task.Priority) {
 		return nil, nil
 	}
 	// Request from the point of view of Add(Activity|Decision)Task operation.
@@ -708,23 +981,113 @@ deliverBufferTasksLoop:
 			runtime.Gosched()
 			continue
 		}
-		select {
-		case task, ok := <-c.taskBuffer:
-			if !ok { // Task list getTasks pump is shutdown
+		if !c.drainTaskBuffers() { // Task list getTasks pump is shutdown
+			break deliverBufferTasksLoop
+		}
+		for c.taskQueue.len() == 0 {
+			select {
+			case task, ok := <-c.taskBuffer:
+				if !ok { // Task list getTasks pump is shutdown
+					break deliverBufferTasksLoop
+				}
+				c.taskQueue.add(task, c)
+			case <-c.bufferedTaskCh: // a task was loaded by a priority backlog
+			case <-c.deliverBufferShutdownCh:
 				break deliverBufferTasksLoop
 			}
-			c.tasksForPoll <- &getTaskResult{task: task}
-		case <-c.deliverBufferShutdownCh:
+			if !c.drainTaskBuffers() {
+				break deliverBufferTasksLoop
+			}
+		}
+		if !c.dispatchBufferTask(c.taskQueue.remove()) {
 			break deliverBufferTasksLoop
 		}
 	}
 }
 
+// hasHigherPriorityBacklog returns true if tasks with a higher priority than the given one are buffered or may be
+// persisted to the backlog of a higher priority level
+func (c *taskListManagerImpl) hasHigherPriorityBacklog(priority int32) bool {
+	if maxPriority, ok := c.taskQueue.maxPriority(); ok && maxPriority > priority {
+		return true
+	}
+	for i, backlog := range c.priorityBacklogs {
+		if int32(i+1) > priority && backlog.hasBacklog() {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatchBufferTask blocks until the task is delivered to a poller, the task is held back while the task list is
+// paused.  Returns false if the task list is shut down while it is paused.
+func (c *taskListManagerImpl) dispatchBufferTask(task *persistence.TaskInfo, backlog *taskListManagerImpl) bool {
+	for {
+		paused, pauseStateCh := c.getPauseState()
+		if paused {
+			select {
+			case <-pauseStateCh:
+				continue
+			case <-c.deliverBufferShutdownCh:
+				return false
+			}
+		}
+		select {
+		case c.tasksForPoll <- &getTaskResult{task: task, backlog: backlog}:
+			return true
+		case <-pauseStateCh: // paused while waiting for a poller
+		}
+	}
+}
+
+// drainTaskBuffers moves the tasks waiting in taskBuffer and in the task buffers of the priority backlogs to taskQueue,
+// the backlog with the highest priority first, so the task with the highest priority is delivered next.  The size of
+// taskQueue is bounded by the size of taskBuffer so the pumps do not load the whole backlog into memory.  Returns
+// false if a task buffer is closed.
+func (c *taskListManagerImpl) drainTaskBuffers() bool {
+	for i := len(c.priorityBacklogs) - 1; i >= 0; i-- {
+		if !c.drainTaskBuffer(c.priorityBacklogs[i]) {
+			return false
+		}
+	}
+	return c.drainTaskBuffer(c)
+}
+
+func (c *taskListManagerImpl) drainTaskBuffer(backlog *taskListManagerImpl) bool {
+	for c.taskQueue.len() <= cap(c.taskBuffer) {
+		select {
+		case task, ok := <-backlog.taskBuffer:
+			if !ok {
+				return false
+			}
+			c.taskQueue.add(task, backlog)
+		default:
+			return true
+		}
+	}
+	return true
+}
+
+// notifyBufferedTask notifies the loop delivering tasks to the pollers of the task list of a task added to taskBuffer
+func (c *taskListManagerImpl) notifyBufferedTask() {
+	owner := c
+	if c.owner != nil {
+		owner = c.owner
+	}
+	select {
+	case owner.bufferedTaskCh <- struct{}{}:
+	default: // channel already has an event, don't block
+	}
+}
+
 func (c *taskListManagerImpl) getTasksPump() {
 	defer close(c.taskBuffer)
 	c.startWG.Wait()
 
-	go c.deliverBufferTasksForPoll()
+	// the tasks of a priority backlog are delivered to pollers by the task list it belongs to
+	if c.owner == nil {
+		go c.deliverBufferTasksForPoll()
+	}
 	updateAckTimer := time.NewTimer(c.config.UpdateAckInterval())
 	checkPollerTimer := time.NewTimer(c.config.IdleTasklistCheckInterval())
 getTasksPumpLoop:
@@ -749,9 +1112,17 @@ getTasksPumpLoop:
 					}
 				}
 				c.Unlock()
+				now := time.Now()
 				for _, t := range tasks {
+					if isTaskExpired(t, now) {
+						// the schedule-to-start timeout already fired, history would reject the task
+						c.metricsClient.IncCounter(metrics.MatchingTaskListMgrScope, metrics.ExpiredTasksCounter)
+						c.completeTask(t.TaskID)
+						continue
+					}
 					select {
 					case c.taskBuffer <- t:
+						c.notifyBufferedTask()
 					case <-c.shutdownCh:
 						break getTasksPumpLoop
 					}
@@ -879,7 +1250,7 @@ func (c *taskContext) RecordActivityTaskStartedWithRetry(
 // If poll received task from addTask directly the addTask goroutine is notified about start task result.
 // If poll received task from persistence then task is deleted from it if no error was reported.
 func (c *taskContext) completeTask(err error) {
-	tlMgr := c.tlMgr
+	tlMgr := c.backlog
 	tlMgr.logger.Debugf("completeTask task taskList=%v, taskID=%v, err=%v",
 		tlMgr.taskListID.taskListName, c.info.TaskID, err)
 	if c.syncResponseCh != nil {
@@ -914,26 +1285,36 @@ func (c *taskContext) completeTask(err error) {
 		tlMgr.signalNewTask()
 	}
 
-	tlMgr.completeTaskPoll(c.info.TaskID)
+	tlMgr.completeTask(c.info.TaskID)
+}
+
+// completeTask acks a task loaded from persistence and deletes it from persistence
+func (c *taskListManagerImpl) completeTask(taskID int64) {
+	c.completeTaskPoll(taskID)
 
 	// TODO: use range deletes to complete all tasks below ack level instead of completing
 	// tasks one by one.
-	err2 := tlMgr.engine.taskManager.CompleteTask(&persistence.CompleteTaskRequest{
+	err := c.engine.taskManager.CompleteTask(&persistence.CompleteTaskRequest{
 		TaskList: &persistence.TaskListInfo{
-			DomainID: tlMgr.taskListID.domainID,
-			Name:     tlMgr.taskListID.taskListName,
-			TaskType: tlMgr.taskListID.taskType,
+			DomainID: c.taskListID.domainID,
+			Name:     c.taskListID.taskListName,
+			TaskType: c.taskListID.taskType,
 		},
-		TaskID: c.info.TaskID,
+		TaskID: taskID,
 	})
 
-	if err2 != nil {
-		logging.LogPersistantStoreErrorEvent(tlMgr.logger, logging.TagValueStoreOperationCompleteTask, err2,
+	if err != nil {
+		logging.LogPersistantStoreErrorEvent(c.logger, logging.TagValueStoreOperationCompleteTask, err,
 			fmt.Sprintf("{taskID: %v, taskType: %v, taskList: %v}",
-				c.info.TaskID, tlMgr.taskListID.taskType, tlMgr.taskListID.taskListName))
+				taskID, c.taskListID.taskType, c.taskListID.taskListName))
 	}
 }
 
+// isTaskExpired returns true if the schedule-to-start timeout of the task fired before now
+func isTaskExpired(t *persistence.TaskInfo, now time.Time) bool {
+	return !t.Expiry.IsZero() && !t.Expiry.After(now)
+}
+
 func createServiceBusyError(msg string) *s.ServiceBusyError {
 	return &s.ServiceBusyError{Message: msg}
 }
@@ -21,24 +21,36 @@
 package matching
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/uber-go/tally"
 	"golang.org/x/time/rate"
 
 	"github.com/uber/cadence/common/mocks"
+	"github.com/uber/cadence/common/service/dynamicconfig"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/uber-common/bark"
 	workflow "github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/metrics"
 	"github.com/uber/cadence/common/persistence"
 )
 
 const _minBurst = 10000
 
+type mockTimeSource struct {
+	currTime time.Time
+}
+
+func (ts *mockTimeSource) Now() time.Time {
+	return ts.currTime
+}
+
 func TestDeliverBufferTasks(t *testing.T) {
 	tests := []func(tlm *taskListManagerImpl){
 		func(tlm *taskListManagerImpl) { close(tlm.taskBuffer) },
@@ -66,6 +78,325 @@ func TestNewRateLimiter(t *testing.T) {
 	assert.Equal(t, _minBurst, limiter.Burst())
 }
 
+func TestDispatchStats(t *testing.T) {
+	timeSource := &mockTimeSource{currTime: time.Now()}
+	stats := newDispatchStats(timeSource)
+	ratePerSecond, syncMatchRatio := stats.get()
+	assert.Equal(t, float64(0), ratePerSecond)
+	assert.Equal(t, float64(0), syncMatchRatio)
+
+	for i := 0; i < 30; i++ {
+		stats.add(i%2 == 0)
+		stats.add(false)
+		timeSource.currTime = timeSource.currTime.Add(time.Second)
+	}
+	ratePerSecond, syncMatchRatio = stats.get()
+	assert.Equal(t, float64(1), ratePerSecond)
+	assert.Equal(t, 0.25, syncMatchRatio)
+
+	// the dispatches older than the window are dropped
+	timeSource.currTime = timeSource.currTime.Add((dispatchStatsWindowSeconds - 15) * time.Second)
+	ratePerSecond, syncMatchRatio = stats.get()
+	assert.Equal(t, float64(28)/dispatchStatsWindowSeconds, ratePerSecond)
+	assert.Equal(t, 0.25, syncMatchRatio)
+}
+
+func TestGetTaskListStatus(t *testing.T) {
+	tlm := createTestTaskListManager()
+	tlm.rangeID = 3
+	tlm.taskAckManager.setAckLevel(5)
+	tlm.taskAckManager.addTask(6)
+	tlm.taskAckManager.addTask(7)
+	tlm.dispatchStats.add(true)
+
+	status := tlm.GetTaskListStatus()
+	assert.Equal(t, int64(2), status.GetBacklogCountHint())
+	assert.Equal(t, int64(7), status.GetReadLevel())
+	assert.Equal(t, int64(5), status.GetAckLevel())
+	assert.Equal(t, int64(3), status.GetRangeID())
+	assert.Equal(t, float64(1)/dispatchStatsWindowSeconds, status.GetRatePerSecond())
+	assert.Equal(t, float64(_defaultTaskDispatchRPS), status.GetMaxRatePerSecond())
+	assert.Equal(t, float64(1), status.GetSyncMatchRatio())
+}
+
+func TestPollerInfo(t *testing.T) {
+	tlm := createTestTaskListManager()
+	ctx := context.WithValue(context.Background(), identityKey, pollerIdentity{
+		identity:       "worker1",
+		ipAddress:      "10.0.0.1",
+		libraryVersion: "0.5.1",
+		clientImpl:     "uber-go",
+	})
+	maxDispatch := float64(200)
+	_, err := tlm.TryGetTaskContext(ctx, &maxDispatch)
+	assert.Equal(t, ErrNoTasks, err)
+
+	pollers := tlm.GetAllPollerInfo()
+	assert.Equal(t, 1, len(pollers))
+	assert.Equal(t, "worker1", pollers[0].identity)
+	assert.Equal(t, "10.0.0.1", pollers[0].ipAddress)
+	assert.Equal(t, "0.5.1", pollers[0].libraryVersion)
+	assert.Equal(t, "uber-go", pollers[0].clientImpl)
+	assert.Equal(t, float64(1)/dispatchStatsWindowSeconds, pollers[0].ratePerSecond)
+	assert.False(t, pollers[0].lastAccessTime.IsZero())
+}
+
+func TestPollerHistoryRate(t *testing.T) {
+	timeSource := &mockTimeSource{currTime: time.Now()}
+	pollers := newPollerHistory(timeSource)
+	worker1 := pollerIdentity{identity: "worker1"}
+	worker2 := pollerIdentity{identity: "worker2"}
+
+	// worker1 polls twice per second and worker2 every other second
+	for i := 0; i < 30; i++ {
+		pollers.updatePollerInfo(worker1)
+		pollers.updatePollerInfo(worker1)
+		if i%2 == 0 {
+			pollers.updatePollerInfo(worker2)
+		}
+		timeSource.currTime = timeSource.currTime.Add(time.Second)
+	}
+
+	rates := make(map[string]float64)
+	for _, poller := range pollers.getAllPollerInfo() {
+		rates[poller.identity] = poller.ratePerSecond
+	}
+	assert.Equal(t, float64(60)/dispatchStatsWindowSeconds, rates["worker1"])
+	assert.Equal(t, float64(15)/dispatchStatsWindowSeconds, rates["worker2"])
+
+	// the polls older than the window are dropped
+	timeSource.currTime = timeSource.currTime.Add((dispatchStatsWindowSeconds - 20) * time.Second)
+	rates = make(map[string]float64)
+	for _, poller := range pollers.getAllPollerInfo() {
+		rates[poller.identity] = poller.ratePerSecond
+	}
+	assert.Equal(t, float64(38)/dispatchStatsWindowSeconds, rates["worker1"])
+	assert.Equal(t, float64(9)/dispatchStatsWindowSeconds, rates["worker2"])
+}
+
+func TestTaskPriorityQueue(t *testing.T) {
+	q := newTaskPriorityQueue(func() int { return 2 })
+	_, ok := q.maxPriority()
+	assert.False(t, ok)
+
+	task, _ := q.remove()
+	assert.Nil(t, task)
+
+	q.add(&persistence.TaskInfo{TaskID: 1, Priority: 0}, nil)
+	q.add(&persistence.TaskInfo{TaskID: 3, Priority: 1}, nil)
+	q.add(&persistence.TaskInfo{TaskID: 2, Priority: 1}, nil)
+	q.add(&persistence.TaskInfo{TaskID: 4, Priority: 1}, nil)
+	q.add(&persistence.TaskInfo{TaskID: 5, Priority: 0}, nil)
+	priority, ok := q.maxPriority()
+	assert.True(t, ok)
+	assert.Equal(t, int32(1), priority)
+
+	// the oldest task is dispatched after two tasks were dispatched ahead of it
+	var taskIDs []int64
+	for q.len() > 0 {
+		task, _ := q.remove()
+		taskIDs = append(taskIDs, task.TaskID)
+	}
+	assert.Equal(t, []int64{2, 3, 1, 4, 5}, taskIDs)
+}
+
+func TestTrySyncMatchPriority(t *testing.T) {
+	tlm := createTestTaskListManager()
+	for _, backlog := range tlm.priorityBacklogs {
+		assert.NoError(t, backlog.Start())
+	}
+	tlm.taskQueue.add(&persistence.TaskInfo{TaskID: 1, Priority: 1}, tlm)
+	polled := make(chan *getTaskResult, 1)
+	go func() {
+		result := <-tlm.tasksForPoll
+		result.C <- &syncMatchResponse{response: &persistence.CreateTasksResponse{}}
+		polled <- result
+	}()
+
+	// a task with lower priority than the buffered task is persisted instead of being matched
+	r, err := tlm.trySyncMatch(&persistence.TaskInfo{Priority: 0})
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+
+	task := &persistence.TaskInfo{Priority: 1}
+	for i := 0; r == nil && i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+		r, err = tlm.trySyncMatch(task)
+		assert.NoError(t, err)
+	}
+	assert.NotNil(t, r)
+	assert.Equal(t, task, (<-polled).task)
+}
+
+func TestPausedTaskList(t *testing.T) {
+	tlm := createTestTaskListManager()
+	assert.NoError(t, tlm.updateRangeIfNeeded())
+	assert.NoError(t, tlm.SetPaused(true))
+	assert.True(t, tlm.IsPaused())
+
+	polled := make(chan *getTaskResult, 1)
+	go func() {
+		polled <- <-tlm.tasksForPoll
+	}()
+
+	// tasks are neither sync matched nor dispatched from the buffer while the task list is paused
+	r, err := tlm.trySyncMatch(&persistence.TaskInfo{TaskID: 1})
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+	task := &persistence.TaskInfo{TaskID: 2}
+	dispatched := make(chan bool, 1)
+	go func() {
+		dispatched <- tlm.dispatchBufferTask(task, tlm)
+	}()
+	select {
+	case <-polled:
+		t.Fatal("task dispatched while the task list is paused")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.NoError(t, tlm.SetPaused(false))
+	assert.False(t, tlm.IsPaused())
+	assert.Equal(t, task, (<-polled).task)
+	assert.True(t, <-dispatched)
+
+	// the paused flag is restored when the task list is leased again
+	assert.NoError(t, tlm.SetPaused(true))
+	resp, err := tlm.engine.taskManager.LeaseTaskList(&persistence.LeaseTaskListRequest{
+		DomainID: tlm.taskListID.domainID,
+		TaskList: tlm.taskListID.taskListName,
+		TaskType: tlm.taskListID.taskType,
+	})
+	assert.NoError(t, err)
+	assert.True(t, resp.TaskListInfo.Paused)
+}
+
+func TestIsTaskExpired(t *testing.T) {
+	now := time.Now()
+	assert.False(t, isTaskExpired(&persistence.TaskInfo{}, now))
+	assert.False(t, isTaskExpired(&persistence.TaskInfo{Expiry: now.Add(time.Second)}, now))
+	assert.True(t, isTaskExpired(&persistence.TaskInfo{Expiry: now}, now))
+	assert.True(t, isTaskExpired(&persistence.TaskInfo{Expiry: now.Add(-time.Second)}, now))
+}
+
+func TestExpiredTasksAreCompletedWhenLoaded(t *testing.T) {
+	logger := bark.NewLoggerFromLogrus(log.New())
+	tm := newTestTaskManager(logger)
+	cfg := defaultTestConfig()
+	cfg.EnableSyncMatch = func(...dynamicconfig.FilterOption) bool { return false }
+	me := newMatchingEngine(cfg, tm, &mocks.HistoryClient{}, logger)
+	scope := tally.NewTestScope("test", nil)
+	me.metricsClient = metrics.NewClient(scope, metrics.Matching)
+	tlID := &taskListID{domainID: "domain", taskListName: "tl", taskType: persistence.TaskListTypeActivity}
+	// tasks stay in the buffer so that only the tasks loaded from persistence are observed
+	maxDispatch := float64(0)
+	rl := newRateLimiter(&maxDispatch, time.Millisecond, 0)
+	tlm := newTaskListManagerWithRateLimiter(me, tlID, common.TaskListKindPtr(workflow.TaskListKindNormal),
+		newTaskListConfig(tlID, cfg), rl).(*taskListManagerImpl)
+	assert.NoError(t, tlm.Start())
+	defer tlm.Stop()
+
+	execution := &workflow.WorkflowExecution{WorkflowId: common.StringPtr("wid"), RunId: common.StringPtr("rid")}
+	now := time.Now()
+	for i, expiry := range []time.Time{now.Add(-time.Second), {}, now.Add(-time.Minute), now.Add(time.Hour)} {
+		err := tlm.AddTask(execution, &persistence.TaskInfo{
+			DomainID:   "domain",
+			WorkflowID: "wid",
+			RunID:      "rid",
+			ScheduleID: int64(i),
+			Expiry:     expiry,
+		})
+		assert.NoError(t, err)
+	}
+
+	for i := 0; len(tlm.taskBuffer) < 2 && i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	// the expired tasks are deleted from persistence instead of being buffered for pollers
+	assert.Equal(t, 2, len(tlm.taskBuffer))
+	assert.Equal(t, 2, tm.getTaskCount(tlID))
+	expiredCtr := scope.Snapshot().Counters()["test.expired.tasks.count+operation=TaskListMgr"]
+	assert.NotNil(t, expiredCtr)
+	assert.Equal(t, int64(2), expiredCtr.Value())
+
+	time.Sleep(time.Millisecond)
+	pollerMaxDispatch := float64(_defaultTaskDispatchRPS)
+	var scheduleIDs []int64
+	for i := 0; i < 2; i++ {
+		tCtx, err := tlm.GetTaskContext(context.Background(), &pollerMaxDispatch)
+		assert.NoError(t, err)
+		scheduleIDs = append(scheduleIDs, tCtx.info.ScheduleID)
+		tCtx.completeTask(nil)
+	}
+	assert.Equal(t, []int64{1, 3}, scheduleIDs)
+	assert.Equal(t, 0, tm.getTaskCount(tlID))
+}
+
+func TestDispatchPriorityBacklog(t *testing.T) {
+	logger := bark.NewLoggerFromLogrus(log.New())
+	tm := newTestTaskManager(logger)
+	cfg := defaultTestConfig()
+	cfg.EnableSyncMatch = func(...dynamicconfig.FilterOption) bool { return false }
+	me := newMatchingEngine(cfg, tm, &mocks.HistoryClient{}, logger)
+	tlID := &taskListID{domainID: "domain", taskListName: "tl", taskType: persistence.TaskListTypeActivity}
+	// tasks are not dispatched until all of them are loaded from persistence
+	maxDispatch := float64(0)
+	rl := newRateLimiter(&maxDispatch, time.Millisecond, 0)
+	tlm := newTaskListManagerWithRateLimiter(me, tlID, common.TaskListKindPtr(workflow.TaskListKindNormal),
+		newTaskListConfig(tlID, cfg), rl).(*taskListManagerImpl)
+	assert.NoError(t, tlm.Start())
+	defer tlm.Stop()
+
+	execution := &workflow.WorkflowExecution{WorkflowId: common.StringPtr("wid"), RunId: common.StringPtr("rid")}
+	for i, priority := range []int32{0, 1, 3, 0, 2, 1} {
+		err := tlm.AddTask(execution, &persistence.TaskInfo{
+			DomainID:   "domain",
+			WorkflowID: "wid",
+			RunID:      "rid",
+			ScheduleID: int64(i),
+			Priority:   priority,
+		})
+		assert.NoError(t, err)
+	}
+
+	// the tasks are persisted to the backlog of their priority level, priority 3 shares the highest level
+	assert.Equal(t, 2, tm.getTaskCount(tlID))
+	assert.Equal(t, 2, tm.getTaskCount(tlm.priorityBacklogs[0].taskListID))
+	assert.Equal(t, 2, tm.getTaskCount(tlm.priorityBacklogs[1].taskListID))
+	assert.True(t, tlm.hasHigherPriorityBacklog(1))
+	assert.False(t, tlm.hasHigherPriorityBacklog(3))
+
+	bufferedCount := func() int {
+		count := len(tlm.taskBuffer)
+		for _, backlog := range tlm.priorityBacklogs {
+			count += len(backlog.taskBuffer)
+		}
+		return count
+	}
+	for i := 0; bufferedCount() < 6 && i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 6, bufferedCount())
+	assert.Equal(t, int64(6), tlm.getBacklogCountHint())
+
+	time.Sleep(time.Millisecond)
+	pollerMaxDispatch := float64(_defaultTaskDispatchRPS)
+	var scheduleIDs []int64
+	for i := 0; i < 6; i++ {
+		tCtx, err := tlm.GetTaskContext(context.Background(), &pollerMaxDispatch)
+		assert.NoError(t, err)
+		scheduleIDs = append(scheduleIDs, tCtx.info.ScheduleID)
+		tCtx.completeTask(nil)
+	}
+	assert.Equal(t, []int64{2, 4, 1, 5, 0, 3}, scheduleIDs)
+
+	// the tasks are completed by the backlog they were loaded from
+	assert.Equal(t, 0, tm.getTaskCount(tlID))
+	for _, backlog := range tlm.priorityBacklogs {
+		assert.Equal(t, 0, tm.getTaskCount(backlog.taskListID))
+	}
+	assert.Equal(t, int64(0), tlm.getBacklogCountHint())
+}
+
 func createTestTaskListManager() *taskListManagerImpl {
 	logger := bark.NewLoggerFromLogrus(log.New())
 	tm := newTestTaskManager(logger)
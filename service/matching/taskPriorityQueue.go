@@ -0,0 +1,148 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"container/heap"
+	"sync"
+
+	"github.com/uber/cadence/common/persistence"
+)
+
+type (
+	// taskPriorityQueue orders the tasks loaded from persistence for dispatch.  Tasks with higher priority are
+	// dispatched first and tasks with the same priority are dispatched in the order they were added to the task list.
+	// To prevent starvation of low priority tasks, the task waiting the longest is dispatched regardless of its
+	// priority once starvationThreshold tasks in a row were dispatched ahead of it.
+	taskPriorityQueue struct {
+		sync.Mutex
+		tasks               taskHeap
+		starvationThreshold func() int
+		// number of consecutive tasks dispatched ahead of the task waiting the longest
+		skipped int
+		// sequence number of the next task added to the queue
+		nextSeq int64
+	}
+
+	// queuedTask is a task with the task list manager of the backlog it was loaded from.  Task IDs are only
+	// comparable between the tasks of the same backlog, the sequence number orders the tasks of all backlogs.
+	queuedTask struct {
+		task    *persistence.TaskInfo
+		backlog *taskListManagerImpl
+		seq     int64
+	}
+
+	taskHeap []*queuedTask
+)
+
+func newTaskPriorityQueue(starvationThreshold func() int) *taskPriorityQueue {
+	return &taskPriorityQueue{
+		starvationThreshold: starvationThreshold,
+	}
+}
+
+func (q *taskPriorityQueue) add(task *persistence.TaskInfo, backlog *taskListManagerImpl) {
+	q.Lock()
+	defer q.Unlock()
+	heap.Push(&q.tasks, &queuedTask{task: task, backlog: backlog, seq: q.nextSeq})
+	q.nextSeq++
+}
+
+// remove returns the next task to dispatch with the backlog it was loaded from, the task is nil if the queue is empty
+func (q *taskPriorityQueue) remove() (*persistence.TaskInfo, *taskListManagerImpl) {
+	q.Lock()
+	defer q.Unlock()
+	if len(q.tasks) == 0 {
+		return nil, nil
+	}
+
+	var next *queuedTask
+	oldest := q.tasks.oldest()
+	switch {
+	case oldest == 0:
+		q.skipped = 0
+		next = heap.Pop(&q.tasks).(*queuedTask)
+	case q.skipped >= q.starvationThreshold():
+		q.skipped = 0
+		next = heap.Remove(&q.tasks, oldest).(*queuedTask)
+	default:
+		q.skipped++
+		next = heap.Pop(&q.tasks).(*queuedTask)
+	}
+	return next.task, next.backlog
+}
+
+func (q *taskPriorityQueue) len() int {
+	q.Lock()
+	defer q.Unlock()
+	return len(q.tasks)
+}
+
+// maxPriority returns the highest priority of the queued tasks, the bool is false if the queue is empty
+func (q *taskPriorityQueue) maxPriority() (int32, bool) {
+	q.Lock()
+	defer q.Unlock()
+	if len(q.tasks) == 0 {
+		return 0, false
+	}
+	return q.tasks[0].task.Priority, true
+}
+
+// oldest returns the index of the task which was added to the queue first
+func (h taskHeap) oldest() int {
+	oldest := 0
+	for i, t := range h {
+		if t.seq < h[oldest].seq {
+			oldest = i
+		}
+	}
+	return oldest
+}
+
+func (h taskHeap) Len() int {
+	return len(h)
+}
+
+func (h taskHeap) Less(i, j int) bool {
+	if h[i].task.Priority != h[j].task.Priority {
+		return h[i].task.Priority > h[j].task.Priority
+	}
+	if h[i].backlog == h[j].backlog {
+		return h[i].task.TaskID < h[j].task.TaskID
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h taskHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *taskHeap) Push(x interface{}) {
+	*h = append(*h, x.(*queuedTask))
+}
+
+func (h *taskHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	task := old[n-1]
+	*h = old[:n-1]
+	return task
+}
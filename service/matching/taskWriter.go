@@ -24,6 +24,7 @@ import (
 	"errors"
 	"fmt"
 	"sync/atomic"
+	"time"
 
 	"github.com/uber-common/bark"
 	s "github.com/uber/cadence/.gen/go/shared"
@@ -96,6 +97,11 @@ func (w *taskWriter) appendTask(execution *s.WorkflowExecution,
 		return nil, errShutdown
 	}
 
+	// a task which is written back to persistence keeps the expiry of its first write
+	if taskInfo.ScheduleToStartTimeout > 0 && taskInfo.Expiry.IsZero() {
+		taskInfo.Expiry = time.Now().Add(time.Duration(taskInfo.ScheduleToStartTimeout) * time.Second)
+	}
+
 	ch := make(chan *writeTaskResponse)
 	req := &writeTaskRequest{
 		execution:  execution,
@@ -156,18 +162,23 @@ writerLoop:
 					maxReadLevel = taskIDs[i]
 				}
 
+				// the task list info is read while holding the persistence lock so it never overwrites newer build IDs
+				// or pause state
+				w.tlMgr.persistenceLock.Lock()
+				buildIDs := w.tlMgr.GetBuildIDs()
 				tlInfo := &persistence.TaskListInfo{
 					DomainID: w.taskListID.domainID,
 					Name:     w.taskListID.taskListName,
 					TaskType: w.taskListID.taskType,
 					// Note that newTaskID could increment range, so rangeID parameter
 					// might be out of sync. This is OK as caller can just retry.
-					RangeID:  rangeID,
-					AckLevel: w.tlMgr.getAckLevel(),
-					Kind:     w.tlMgr.getTaskListKind(),
+					RangeID:            rangeID,
+					AckLevel:           w.tlMgr.getAckLevel(),
+					Kind:               w.tlMgr.getTaskListKind(),
+					CompatibleBuildIDs: buildIDs.compatible,
+					RetiredBuildIDs:    buildIDs.retired,
+					Paused:             w.tlMgr.IsPaused(),
 				}
-
-				w.tlMgr.persistenceLock.Lock()
 				r, err := w.taskManager.CreateTasks(&persistence.CreateTasksRequest{
 					TaskListInfo: tlInfo,
 					Tasks:        tasks,
@@ -100,8 +100,10 @@ func (domainReplicator *domainReplicatorImpl) handleDomainCreationReplicationTas
 			OwnerEmail:  task.Info.GetOwnerEmail(),
 		},
 		Config: &persistence.DomainConfig{
-			Retention:  task.Config.GetWorkflowExecutionRetentionPeriodInDays(),
-			EmitMetric: task.Config.GetEmitMetric(),
+			Retention:      task.Config.GetWorkflowExecutionRetentionPeriodInDays(),
+			EmitMetric:     task.Config.GetEmitMetric(),
+			ArchivalBucket: task.Config.GetArchivalBucketName(),
+			ArchivalStatus: task.Config.GetArchivalStatus(),
 		},
 		ReplicationConfig: &persistence.DomainReplicationConfig{
 			ActiveClusterName: task.ReplicationConfig.GetActiveClusterName(),
@@ -159,8 +161,10 @@ func (domainReplicator *domainReplicatorImpl) handleDomainUpdateReplicationTask(
 			OwnerEmail:  task.Info.GetOwnerEmail(),
 		}
 		request.Config = &persistence.DomainConfig{
-			Retention:  task.Config.GetWorkflowExecutionRetentionPeriodInDays(),
-			EmitMetric: task.Config.GetEmitMetric(),
+			Retention:      task.Config.GetWorkflowExecutionRetentionPeriodInDays(),
+			EmitMetric:     task.Config.GetEmitMetric(),
+			ArchivalBucket: task.Config.GetArchivalBucketName(),
+			ArchivalStatus: task.Config.GetArchivalStatus(),
 		}
 		request.ReplicationConfig.Clusters = domainReplicator.convertClusterReplicationConfigFromThrift(task.ReplicationConfig.Clusters)
 		request.ConfigVersion = task.GetConfigVersion()
@@ -79,6 +79,8 @@ func (s *domainReplicatorSuite) TestHandleReceivingTask_RegisterDomainTask() {
 	ownerEmail := "some random test owner"
 	retention := int32(10)
 	emitMetric := true
+	archivalStatus := shared.ArchivalStatusEnabled
+	archivalBucket := "some-random-archival-bucket"
 	clusterActive := "some random active cluster name"
 	clusterStandby := "some random standby cluster name"
 	configVersion := int64(0)
@@ -104,6 +106,8 @@ func (s *domainReplicatorSuite) TestHandleReceivingTask_RegisterDomainTask() {
 		Config: &shared.DomainConfiguration{
 			WorkflowExecutionRetentionPeriodInDays: common.Int32Ptr(retention),
 			EmitMetric:                             common.BoolPtr(emitMetric),
+			ArchivalStatus:                         archivalStatus.Ptr(),
+			ArchivalBucketName:                     common.StringPtr(archivalBucket),
 		},
 		ReplicationConfig: &shared.DomainReplicationConfiguration{
 			ActiveClusterName: common.StringPtr(clusterActive),
@@ -126,6 +130,8 @@ func (s *domainReplicatorSuite) TestHandleReceivingTask_RegisterDomainTask() {
 	s.Equal(ownerEmail, resp.Info.OwnerEmail)
 	s.Equal(retention, resp.Config.Retention)
 	s.Equal(emitMetric, resp.Config.EmitMetric)
+	s.Equal(archivalStatus, resp.Config.ArchivalStatus)
+	s.Equal(archivalBucket, resp.Config.ArchivalBucket)
 	s.Equal(clusterActive, resp.ReplicationConfig.ActiveClusterName)
 	s.Equal(s.domainReplicator.convertClusterReplicationConfigFromThrift(clusters), resp.ReplicationConfig.Clusters)
 	s.Equal(configVersion, resp.ConfigVersion)
@@ -249,6 +255,8 @@ func (s *domainReplicatorSuite) TestHandleReceivingTask_UpdateDomainTask_UpdateC
 	updateOwnerEmail := "other random domain test owner"
 	updateRetention := int32(122)
 	updateEmitMetric := true
+	updateArchivalStatus := shared.ArchivalStatusEnabled
+	updateArchivalBucket := "some-random-update-archival-bucket"
 	updateClusterActive := "other random active cluster name"
 	updateClusterStandby := "other random standby cluster name"
 	updateConfigVersion := configVersion + 1
@@ -273,6 +281,8 @@ func (s *domainReplicatorSuite) TestHandleReceivingTask_UpdateDomainTask_UpdateC
 		Config: &shared.DomainConfiguration{
 			WorkflowExecutionRetentionPeriodInDays: common.Int32Ptr(updateRetention),
 			EmitMetric:                             common.BoolPtr(updateEmitMetric),
+			ArchivalStatus:                         updateArchivalStatus.Ptr(),
+			ArchivalBucketName:                     common.StringPtr(updateArchivalBucket),
 		},
 		ReplicationConfig: &shared.DomainReplicationConfiguration{
 			ActiveClusterName: common.StringPtr(updateClusterActive),
@@ -293,6 +303,8 @@ func (s *domainReplicatorSuite) TestHandleReceivingTask_UpdateDomainTask_UpdateC
 	s.Equal(updateOwnerEmail, resp.Info.OwnerEmail)
 	s.Equal(updateRetention, resp.Config.Retention)
 	s.Equal(updateEmitMetric, resp.Config.EmitMetric)
+	s.Equal(updateArchivalStatus, resp.Config.ArchivalStatus)
+	s.Equal(updateArchivalBucket, resp.Config.ArchivalBucket)
 	s.Equal(updateClusterActive, resp.ReplicationConfig.ActiveClusterName)
 	s.Equal(s.domainReplicator.convertClusterReplicationConfigFromThrift(updateClusters), resp.ReplicationConfig.Clusters)
 	s.Equal(updateConfigVersion, resp.ConfigVersion)
@@ -21,6 +21,7 @@
 package worker
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
@@ -31,8 +32,12 @@ import (
 
 	"github.com/uber-common/bark"
 	"github.com/uber-go/kafka-client/kafka"
+	h "github.com/uber/cadence/.gen/go/history"
 	"github.com/uber/cadence/.gen/go/replicator"
+	"github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/client/history"
 	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/backoff"
 	"github.com/uber/cadence/common/logging"
 	"github.com/uber/cadence/common/messaging"
 	"github.com/uber/cadence/common/metrics"
@@ -46,6 +51,7 @@ type (
 	}
 
 	replicationTaskProcessor struct {
+		sourceCluster    string
 		topicName        string
 		consumerName     string
 		client           messaging.Client
@@ -58,6 +64,7 @@ type (
 		logger           bark.Logger
 		metricsClient    metrics.Client
 		domainReplicator DomainReplicator
+		historyClient    history.Client
 	}
 )
 
@@ -66,16 +73,20 @@ var (
 	ErrEmptyReplicationTask = errors.New("empty replication task")
 	// ErrUnknownReplicationTask is the error to indicate unknown replication task type
 	ErrUnknownReplicationTask = errors.New("unknown replication task")
+
+	historyReplicationRetryPolicy = common.CreateHistoryServiceRetryPolicy()
 )
 
-func newReplicationTaskProcessor(topic, consumer string, client messaging.Client, config *Config,
-	logger bark.Logger, metricsClient metrics.Client, domainReplicator DomainReplicator) *replicationTaskProcessor {
+func newReplicationTaskProcessor(sourceCluster, topic, consumer string, client messaging.Client, config *Config,
+	logger bark.Logger, metricsClient metrics.Client, domainReplicator DomainReplicator,
+	historyClient history.Client) *replicationTaskProcessor {
 	return &replicationTaskProcessor{
-		topicName:    topic,
-		consumerName: consumer,
-		client:       client,
-		shutdownCh:   make(chan struct{}),
-		config:       config,
+		sourceCluster: sourceCluster,
+		topicName:     topic,
+		consumerName:  consumer,
+		client:        client,
+		shutdownCh:    make(chan struct{}),
+		config:        config,
 		logger: logger.WithFields(bark.Fields{
 			logging.TagWorkflowComponent: logging.TagValueReplicationTaskProcessorComponent,
 			logging.TagTopicName:         topic,
@@ -83,6 +94,7 @@ func newReplicationTaskProcessor(topic, consumer string, client messaging.Client
 		}),
 		metricsClient:    metricsClient,
 		domainReplicator: domainReplicator,
+		historyClient:    historyClient,
 	}
 }
 
@@ -163,34 +175,20 @@ func (p *replicationTaskProcessor) worker(workerWG *sync.WaitGroup) {
 			p.metricsClient.IncCounter(metrics.ReplicatorScope, metrics.ReplicatorMessages)
 			sw := p.metricsClient.StartTimer(metrics.ReplicatorScope, metrics.ReplicatorLatency)
 
-			// TODO: We skip over any messages which cannot be deserialized.  Figure out DLQ story for corrupted messages.
-			task, err := deserialize(msg.Value())
-			if err != nil {
-				err = fmt.Errorf("Deserialize Error. Value: %v, Error: %v", string(msg.Value()), err)
-			} else {
-
-				// TODO: We need to figure out DLQ story for corrupted payload
-				if task.TaskType == nil {
-					err = ErrEmptyReplicationTask
-				} else {
-					switch task.GetTaskType() {
-					case replicator.ReplicationTaskTypeDomain:
-						p.logger.Debugf("Recieved domain replication task %v.", task.DomainTaskAttributes)
-						err = p.domainReplicator.HandleReceivingTask(task.DomainTaskAttributes)
-					case replicator.ReplicationTaskTypeHistory:
-						p.logger.Debugf("Recieved history replication task %v.", task.HistoryTaskAttributes)
-					default:
-						err = ErrUnknownReplicationTask
-					}
-				}
-			}
-
+			retry, err := p.handleMessage(msg.Value())
 			if err != nil {
 				p.logger.WithField(logging.TagErr, err).Error("Error processing replication task.")
 				p.metricsClient.IncCounter(metrics.ReplicatorScope, metrics.ReplicatorFailures)
 			}
 			sw.Stop()
-			msg.Ack()
+
+			if retry {
+				// Nack moves the message to the retry queue of the consumer and to the DLQ once its retries are
+				// exhausted, so events which are still out of order after the backoff are not lost.
+				msg.Nack()
+			} else {
+				msg.Ack()
+			}
 		case <-p.consumer.Closed():
 			p.logger.Info("Consumer closed. Processor shutting down.")
 			return
@@ -198,6 +196,62 @@ func (p *replicationTaskProcessor) worker(workerWG *sync.WaitGroup) {
 	}
 }
 
+// handleMessage processes the replication task of a message.  It returns true when the message needs to be nacked to
+// be redelivered, which is only the case for a history replication task failing with a retryable error.  Any other
+// failure is acked so that a message which can never be processed is not redelivered forever.
+func (p *replicationTaskProcessor) handleMessage(payload []byte) (bool, error) {
+	// TODO: We skip over any messages which cannot be deserialized.  Figure out DLQ story for corrupted messages.
+	task, err := deserialize(payload)
+	if err != nil {
+		return false, fmt.Errorf("Deserialize Error. Value: %v, Error: %v", string(payload), err)
+	}
+
+	// TODO: We need to figure out DLQ story for corrupted payload
+	if task.TaskType == nil {
+		return false, ErrEmptyReplicationTask
+	}
+
+	switch task.GetTaskType() {
+	case replicator.ReplicationTaskTypeDomain:
+		p.logger.Debugf("Recieved domain replication task %v.", task.DomainTaskAttributes)
+		return false, p.domainReplicator.HandleReceivingTask(task.DomainTaskAttributes)
+	case replicator.ReplicationTaskTypeHistory:
+		p.logger.Debugf("Recieved history replication task %v.", task.HistoryTaskAttributes)
+		err = p.handleHistoryReplicationTask(task.HistoryTaskAttributes)
+		return err != nil && err != ErrEmptyReplicationTask && common.IsServiceTransientError(err), err
+	default:
+		return false, ErrUnknownReplicationTask
+	}
+}
+
+func (p *replicationTaskProcessor) handleHistoryReplicationTask(attributes *replicator.HistoryTaskAttributes) error {
+	if attributes == nil {
+		return ErrEmptyReplicationTask
+	}
+
+	request := &h.ReplicateEventsRequest{
+		SourceCluster: common.StringPtr(p.sourceCluster),
+		DomainUUID:    attributes.DomainId,
+		WorkflowExecution: &shared.WorkflowExecution{
+			WorkflowId: attributes.WorkflowId,
+			RunId:      attributes.RunId,
+		},
+		FirstEventId: attributes.FirstEventId,
+		NextEventId:  attributes.NextEventId,
+		Version:      attributes.Version,
+		History:      attributes.History,
+	}
+
+	// Events could be received out of order in which case history service asks for the task to be retried.  If the
+	// missing events are still not applied once the retry policy expires, the error is returned so the message is
+	// nacked instead of acked.
+	op := func() error {
+		return p.historyClient.ReplicateEvents(context.Background(), request)
+	}
+
+	return backoff.Retry(op, historyReplicationRetryPolicy, common.IsServiceTransientError)
+}
+
 func deserialize(payload []byte) (*replicator.ReplicationTask, error) {
 	var task replicator.ReplicationTask
 	if err := json.Unmarshal(payload, &task); err != nil {
@@ -0,0 +1,140 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package worker
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-common/bark"
+	"github.com/uber-go/tally"
+	"github.com/uber/cadence/.gen/go/replicator"
+	"github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/backoff"
+	"github.com/uber/cadence/common/metrics"
+	"github.com/uber/cadence/common/mocks"
+)
+
+type (
+	replicationTaskProcessorSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		mockHistoryClient *mocks.HistoryClient
+		processor         *replicationTaskProcessor
+		retryPolicy       backoff.RetryPolicy
+	}
+)
+
+func TestReplicationTaskProcessorSuite(t *testing.T) {
+	s := new(replicationTaskProcessorSuite)
+	suite.Run(t, s)
+}
+
+func (s *replicationTaskProcessorSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	// keep the retries of the history replication tasks short
+	s.retryPolicy = historyReplicationRetryPolicy
+	policy := backoff.NewExponentialRetryPolicy(time.Millisecond)
+	policy.SetMaximumAttempts(2)
+	historyReplicationRetryPolicy = policy
+
+	s.mockHistoryClient = &mocks.HistoryClient{}
+	s.processor = newReplicationTaskProcessor("standby", "test-topic", "test-consumer", nil, nil,
+		bark.NewLoggerFromLogrus(logrus.New()), metrics.NewClient(tally.NoopScope, metrics.Worker), nil,
+		s.mockHistoryClient)
+}
+
+func (s *replicationTaskProcessorSuite) TearDownTest() {
+	historyReplicationRetryPolicy = s.retryPolicy
+	s.mockHistoryClient.AssertExpectations(s.T())
+}
+
+func (s *replicationTaskProcessorSuite) TestMalformedMessageIsNotRedelivered() {
+	retry, err := s.processor.handleMessage([]byte("not a replication task"))
+	s.Error(err)
+	s.False(retry)
+
+	retry, err = s.processor.handleMessage(s.serialize(&replicator.ReplicationTask{}))
+	s.Equal(ErrEmptyReplicationTask, err)
+	s.False(retry)
+
+	retry, err = s.processor.handleMessage(s.serialize(&replicator.ReplicationTask{
+		TaskType: replicator.ReplicationTaskTypeHistory.Ptr(),
+	}))
+	s.Equal(ErrEmptyReplicationTask, err)
+	s.False(retry)
+}
+
+func (s *replicationTaskProcessorSuite) TestHistoryTaskNonRetryableErrorIsNotRedelivered() {
+	badRequestErr := &shared.BadRequestError{Message: "invalid event range"}
+	s.mockHistoryClient.On("ReplicateEvents", mock.Anything, mock.Anything).Return(badRequestErr).Once()
+
+	retry, err := s.processor.handleMessage(s.serialize(s.newHistoryTask()))
+	s.Equal(badRequestErr, err)
+	s.False(retry)
+}
+
+func (s *replicationTaskProcessorSuite) TestHistoryTaskRetryableErrorIsRedelivered() {
+	retryErr := &shared.InternalServiceError{Message: "Missing replication events, retry later."}
+	s.mockHistoryClient.On("ReplicateEvents", mock.Anything, mock.Anything).Return(retryErr)
+
+	// the task is nacked once the retries of the processor are exhausted
+	retry, err := s.processor.handleMessage(s.serialize(s.newHistoryTask()))
+	s.Equal(retryErr, err)
+	s.True(retry)
+}
+
+func (s *replicationTaskProcessorSuite) TestHistoryTaskApplied() {
+	s.mockHistoryClient.On("ReplicateEvents", mock.Anything, mock.Anything).Return(nil).Once()
+
+	retry, err := s.processor.handleMessage(s.serialize(s.newHistoryTask()))
+	s.NoError(err)
+	s.False(retry)
+}
+
+func (s *replicationTaskProcessorSuite) newHistoryTask() *replicator.ReplicationTask {
+	return &replicator.ReplicationTask{
+		TaskType: replicator.ReplicationTaskTypeHistory.Ptr(),
+		HistoryTaskAttributes: &replicator.HistoryTaskAttributes{
+			DomainId:     common.StringPtr("test-domain-id"),
+			WorkflowId:   common.StringPtr("test-workflow-id"),
+			RunId:        common.StringPtr("test-run-id"),
+			FirstEventId: common.Int64Ptr(1),
+			NextEventId:  common.Int64Ptr(3),
+			Version:      common.Int64Ptr(1),
+			History:      &shared.History{},
+		},
+	}
+}
+
+func (s *replicationTaskProcessorSuite) serialize(task *replicator.ReplicationTask) []byte {
+	payload, err := json.Marshal(task)
+	s.NoError(err)
+	return payload
+}
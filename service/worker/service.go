@@ -68,24 +68,48 @@ func (s *Service) Start() {
 
 	log.Infof("%v starting", common.WorkerServiceName)
 	base := service.New(p)
+	base.Start()
 
 	s.metricsClient = base.GetMetricsClient()
 
-	metadataManager, err := persistence.NewCassandraMetadataPersistence(p.CassandraConfig.Hosts,
-		p.CassandraConfig.Port,
-		p.CassandraConfig.User,
-		p.CassandraConfig.Password,
-		p.CassandraConfig.Datacenter,
-		p.CassandraConfig.Keyspace,
-		p.ClusterMetadata.GetCurrentClusterName(),
-		p.Logger)
+	var metadataManager persistence.MetadataManager
+	var err error
+	if p.InMemoryStore != nil {
+		metadataManager, err = persistence.NewInMemoryMetadataPersistence(p.InMemoryStore, p.ClusterMetadata.GetCurrentClusterName(), p.Logger)
+	} else if p.SQLConfig != nil {
+		metadataManager, err = persistence.NewSQLMetadataPersistence(p.SQLConfig.DriverName,
+			p.SQLConfig.Host,
+			p.SQLConfig.Port,
+			p.SQLConfig.User,
+			p.SQLConfig.Password,
+			p.SQLConfig.DatabaseName,
+			p.SQLConfig.MaxConns,
+			p.ClusterMetadata.GetCurrentClusterName(),
+			p.Logger,
+		)
+	} else {
+		metadataManager, err = persistence.NewCassandraMetadataPersistence(p.CassandraConfig.Hosts,
+			p.CassandraConfig.Port,
+			p.CassandraConfig.User,
+			p.CassandraConfig.Password,
+			p.CassandraConfig.Datacenter,
+			p.CassandraConfig.Keyspace,
+			p.ClusterMetadata.GetCurrentClusterName(),
+			p.Logger)
+	}
 
 	if err != nil {
 		log.Fatalf("failed to create metadata manager: %v", err)
 	}
 	metadataManager = persistence.NewMetadataPersistenceClient(metadataManager, base.GetMetricsClient())
 
-	replicator := NewReplicator(p.ClusterMetadata, metadataManager, s.config, p.MessagingClient, log, s.metricsClient)
+	history, err := base.GetClientFactory().NewHistoryClient()
+	if err != nil {
+		log.Fatalf("failed to create history service client: %v", err)
+	}
+
+	replicator := NewReplicator(p.ClusterMetadata, metadataManager, history, s.config, p.MessagingClient, log,
+		s.metricsClient)
 	if err := replicator.Start(); err != nil {
 		replicator.Stop()
 		log.Fatalf("Fail to start replicator: %v", err)
@@ -46,6 +46,11 @@ func NewCliApp() *cli.App {
 			Usage:  "cadence workflow domain",
 			EnvVar: "CADENCE_CLI_DOMAIN",
 		},
+		cli.StringFlag{
+			Name:   FlagAuthToken,
+			Usage:  "token authenticating the caller to the cadence frontend service",
+			EnvVar: "CADENCE_CLI_AUTH_TOKEN",
+		},
 	}
 	app.Commands = []cli.Command{
 		{
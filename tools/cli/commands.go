@@ -37,6 +37,9 @@ import (
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
 	"github.com/pborman/uuid"
+	serverAdmin "github.com/uber/cadence/.gen/go/admin/adminserviceclient"
+	serverFrontend "github.com/uber/cadence/.gen/go/cadence/workflowserviceclient"
+	"github.com/uber/cadence/.gen/go/shared"
 	"github.com/uber/cadence/common"
 	"github.com/urfave/cli"
 
@@ -51,6 +54,7 @@ Flags used to specify cli command line arguments
 const (
 	FlagAddress                   = "address"
 	FlagAddressWithAlias          = FlagAddress + ", ad"
+	FlagAuthToken                 = "auth_token"
 	FlagDomain                    = "domain"
 	FlagDomainWithAlias           = FlagDomain + ", do"
 	FlagWorkflowID                = "workflow_id"
@@ -103,6 +107,15 @@ const (
 	FlagOutputFilenameWithAlias   = FlagOutputFilename + ", of"
 	FlagQueryType                 = "query_type"
 	FlagQueryTypeWithAlias        = FlagQueryType + ", qt"
+	FlagEventID                   = "event_id"
+	FlagEventIDWithAlias          = FlagEventID + ", eid"
+	FlagBuildID                   = "build_id"
+	FlagBuildIDWithAlias          = FlagBuildID + ", bid"
+	FlagCompatible                = "compatible_build_id"
+	FlagCompatibleWithAlias       = FlagCompatible + ", cbid"
+	FlagRetire                    = "retire"
+	FlagListQuery                 = "query"
+	FlagListQueryWithAlias        = FlagListQuery + ", q"
 )
 
 const (
@@ -563,6 +576,40 @@ func SignalWorkflow(c *cli.Context) {
 	}
 }
 
+// ResetWorkflow resets a workflow execution to the given DecisionTaskCompleted event
+func ResetWorkflow(c *cli.Context) {
+	frontendClient := getFrontendClient(c)
+
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	wid := getRequiredOption(c, FlagWorkflowID)
+	rid := c.String(FlagRunID)
+	reason := getRequiredOption(c, FlagReason)
+	eventID := c.Int64(FlagEventID)
+	if eventID <= 0 {
+		ExitIfError(fmt.Errorf("%s is required", FlagEventID))
+	}
+
+	tcCtx, cancel := newContext()
+	defer cancel()
+	resp, err := frontendClient.ResetWorkflowExecution(tcCtx, &shared.ResetWorkflowExecutionRequest{
+		Domain: common.StringPtr(domain),
+		WorkflowExecution: &shared.WorkflowExecution{
+			WorkflowId: common.StringPtr(wid),
+			RunId:      getPtrOrNilIfEmpty(rid),
+		},
+		Reason:                common.StringPtr(reason),
+		DecisionFinishEventId: common.Int64Ptr(eventID),
+		RequestId:             common.StringPtr(uuid.New()),
+		Identity:              common.StringPtr(getCliIdentity()),
+	})
+
+	if err != nil {
+		ErrorAndExit("Reset workflow failed", err)
+	} else {
+		fmt.Printf("Reset workflow succeed, new run id: %v\n", resp.GetRunId())
+	}
+}
+
 // QueryWorkflow query workflow execution
 func QueryWorkflow(c *cli.Context) {
 	getRequiredGlobalOption(c, FlagDomain) // for pre-check and alert if not provided
@@ -791,6 +838,7 @@ func listWorkflow(c *cli.Context, table *tablewriter.Table) func([]byte) ([]byte
 	latestTime := parseTime(c.String(FlagLatestTime), time.Now().UnixNano())
 	workflowID := c.String(FlagWorkflowID)
 	workflowType := c.String(FlagWorkflowType)
+	query := c.String(FlagListQuery)
 	printRawTime := c.Bool(FlagPrintRawTime)
 	printDateTime := c.Bool(FlagPrintDateTime)
 	pageSize := c.Int(FlagPageSize)
@@ -802,7 +850,28 @@ func listWorkflow(c *cli.Context, table *tablewriter.Table) func([]byte) ([]byte
 		ExitIfError(errors.New("you can filter on workflow_id or workflow_type, but not on both"))
 	}
 
+	appendRow := func(workflowTypeName, workflowID, runID string, start, close int64) {
+		var startTime, closeTime string
+		if printRawTime {
+			startTime = fmt.Sprintf("%d", start)
+			closeTime = fmt.Sprintf("%d", close)
+		} else {
+			startTime = convertTime(start, !printDateTime)
+			closeTime = convertTime(close, !printDateTime)
+		}
+		table.Append([]string{trimWorkflowType(workflowTypeName), workflowID, runID, startTime, closeTime})
+	}
+
 	prepareTable := func(next []byte) ([]byte, int) {
+		if len(query) > 0 {
+			// Query based listing goes through the server API as the client library does not support it yet
+			result, nextPageToken := listWorkflowByQuery(c, pageSize, query, next)
+			for _, e := range result {
+				appendRow(e.Type.GetName(), e.Execution.GetWorkflowId(), e.Execution.GetRunId(), e.GetStartTime(), e.GetCloseTime())
+			}
+			return nextPageToken, len(result)
+		}
+
 		var result []*s.WorkflowExecutionInfo
 		var nextPageToken []byte
 		if queryOpen {
@@ -812,15 +881,7 @@ func listWorkflow(c *cli.Context, table *tablewriter.Table) func([]byte) ([]byte
 		}
 
 		for _, e := range result {
-			var startTime, closeTime string
-			if printRawTime {
-				startTime = fmt.Sprintf("%d", e.GetStartTime())
-				closeTime = fmt.Sprintf("%d", e.GetCloseTime())
-			} else {
-				startTime = convertTime(e.GetStartTime(), !printDateTime)
-				closeTime = convertTime(e.GetCloseTime(), !printDateTime)
-			}
-			table.Append([]string{trimWorkflowType(e.Type.GetName()), e.Execution.GetWorkflowId(), e.Execution.GetRunId(), startTime, closeTime})
+			appendRow(e.Type.GetName(), e.Execution.GetWorkflowId(), e.Execution.GetRunId(), e.GetStartTime(), e.GetCloseTime())
 		}
 
 		return nextPageToken, len(result)
@@ -828,6 +889,44 @@ func listWorkflow(c *cli.Context, table *tablewriter.Table) func([]byte) ([]byte
 	return prepareTable
 }
 
+// listWorkflowByQuery lists one page of the open and closed executions which match the query.  Clusters which keep
+// visibility records in Cassandra do not support queries and fail the request.
+func listWorkflowByQuery(c *cli.Context, pageSize int, query string, nextPageToken []byte) ([]*shared.WorkflowExecutionInfo, []byte) {
+	frontendClient := getFrontendClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+
+	ctx, cancel := newContext()
+	defer cancel()
+	response, err := frontendClient.ListWorkflowExecutions(ctx, &shared.ListWorkflowExecutionsRequest{
+		Domain:        common.StringPtr(domain),
+		PageSize:      common.Int32Ptr(int32(pageSize)),
+		NextPageToken: nextPageToken,
+		Query:         common.StringPtr(query),
+	})
+	if err != nil {
+		ErrorAndExit("List workflow executions by query failed", err)
+	}
+	return response.Executions, response.NextPageToken
+}
+
+// CountWorkflow counts the open and closed workflow executions which match a query
+func CountWorkflow(c *cli.Context) {
+	frontendClient := getFrontendClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	query := c.String(FlagListQuery)
+
+	ctx, cancel := newContext()
+	defer cancel()
+	response, err := frontendClient.CountWorkflowExecutions(ctx, &shared.CountWorkflowExecutionsRequest{
+		Domain: common.StringPtr(domain),
+		Query:  common.StringPtr(query),
+	})
+	if err != nil {
+		ErrorAndExit("Count workflow executions failed", err)
+	}
+	fmt.Println(response.GetCount())
+}
+
 func listOpenWorkflow(client client.Client, pageSize int, earliestTime, latestTime int64, workflowID, workflowType string, nextPageToken []byte) ([]*s.WorkflowExecutionInfo, []byte) {
 	request := &s.ListOpenWorkflowExecutionsRequest{
 		MaximumPageSize: common.Int32Ptr(int32(pageSize)),
@@ -878,37 +977,217 @@ func listClosedWorkflow(client client.Client, pageSize int, earliestTime, latest
 	return response.Executions, response.NextPageToken
 }
 
-// DescribeTaskList show pollers info of a given tasklist
+// DescribeTaskList show the backlog status and pollers info of a given tasklist
 func DescribeTaskList(c *cli.Context) {
-	wfClient := getWorkflowClient(c)
+	frontendClient := getFrontendClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
 	taskList := getRequiredOption(c, FlagTaskList)
-	taskListType := strToTaskListType(c.String(FlagTaskListType)) // default type is decision
+	taskListType := shared.TaskListType(strToTaskListType(c.String(FlagTaskListType))) // default type is decision
 
 	ctx, cancel := newContext()
 	defer cancel()
-	response, err := wfClient.DescribeTaskList(ctx, taskList, taskListType)
+	response, err := frontendClient.DescribeTaskList(ctx, &shared.DescribeTaskListRequest{
+		Domain:       common.StringPtr(domain),
+		TaskList:     &shared.TaskList{Name: common.StringPtr(taskList)},
+		TaskListType: &taskListType,
+	})
 	if err != nil {
 		ErrorAndExit("DescribeTaskList failed", err)
 	}
 
+	if response.GetPaused() {
+		fmt.Println(colorRed("Tasklist is paused, its tasks are not dispatched to pollers"))
+	}
+
+	if status := response.TaskListStatus; status != nil {
+		fmt.Println(colorMagenta("Status:"))
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetBorder(false)
+		table.SetColumnSeparator(":")
+		table.AppendBulk([][]string{
+			{"Backlog Count Hint", strconv.FormatInt(status.GetBacklogCountHint(), 10)},
+			{"Read Level", strconv.FormatInt(status.GetReadLevel(), 10)},
+			{"Ack Level", strconv.FormatInt(status.GetAckLevel(), 10)},
+			{"Range ID", strconv.FormatInt(status.GetRangeID(), 10)},
+			{"Dispatch Rate", fmt.Sprintf("%.2f/s of max %.2f/s", status.GetRatePerSecond(), status.GetMaxRatePerSecond())},
+			{"Sync Match Ratio", fmt.Sprintf("%.2f", status.GetSyncMatchRatio())},
+		})
+		table.Render()
+	}
+
+	if buildIDs := response.BuildIDs; buildIDs != nil && len(buildIDs.CompatibleBuildIDs) > 0 {
+		fmt.Println(colorMagenta("Build IDs:"))
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetBorder(false)
+		table.SetColumnSeparator(":")
+		for i, set := range buildIDs.CompatibleBuildIDs {
+			name := "Compatible"
+			if i == len(buildIDs.CompatibleBuildIDs)-1 {
+				name = "Default"
+			}
+			table.Append([]string{name, strings.Join(set.BuildIDs, ", ")})
+		}
+		if len(buildIDs.RetiredBuildIDs) > 0 {
+			table.Append([]string{"Retired", strings.Join(buildIDs.RetiredBuildIDs, ", ")})
+		}
+		table.Render()
+	}
+
 	pollers := response.Pollers
 	if len(pollers) == 0 {
 		fmt.Println(colorMagenta("No poller for tasklist: " + taskList))
 		return
 	}
 
+	fmt.Println(colorMagenta("Pollers:"))
 	table := tablewriter.NewWriter(os.Stdout)
 	table.SetBorder(false)
 	table.SetColumnSeparator("|")
-	table.SetHeader([]string{"Poller Identity", "Last Access Time"})
+	table.SetHeader([]string{"Poller Identity", "IP Address", "Client", "Build ID", "Rate Per Second", "Last Access Time"})
 	table.SetHeaderLine(false)
-	table.SetHeaderColor(tableHeaderBlue, tableHeaderBlue)
+	table.SetHeaderColor(tableHeaderBlue, tableHeaderBlue, tableHeaderBlue, tableHeaderBlue, tableHeaderBlue, tableHeaderBlue)
 	for _, poller := range pollers {
-		table.Append([]string{poller.GetIdentity(), convertTime(poller.GetLastAccessTime(), false)})
+		table.Append([]string{
+			poller.GetIdentity(),
+			poller.GetIpAddress(),
+			strings.TrimSpace(poller.GetClientImpl() + " " + poller.GetClientLibraryVersion()),
+			poller.GetBuildID(),
+			strconv.FormatFloat(poller.GetRatePerSecond(), 'f', -1, 64),
+			convertTime(poller.GetLastAccessTime(), false),
+		})
 	}
 	table.Render()
 }
 
+// UpdateTaskListBuildIDs adds a worker build ID to a decision tasklist or retires it
+func UpdateTaskListBuildIDs(c *cli.Context) {
+	adminClient := getAdminClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	taskList := getRequiredOption(c, FlagTaskList)
+	buildID := getRequiredOption(c, FlagBuildID)
+	compatibleBuildID := c.String(FlagCompatible)
+	retire := c.Bool(FlagRetire)
+	if retire && compatibleBuildID != "" {
+		ExitIfError(fmt.Errorf("%s cannot be used with %s", FlagCompatible, FlagRetire))
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	err := adminClient.UpdateTaskListBuildIDs(ctx, &shared.UpdateTaskListBuildIDsRequest{
+		Domain:            common.StringPtr(domain),
+		TaskList:          &shared.TaskList{Name: common.StringPtr(taskList)},
+		BuildID:           common.StringPtr(buildID),
+		CompatibleBuildID: common.StringPtr(compatibleBuildID),
+		Retire:            common.BoolPtr(retire),
+	})
+	if err != nil {
+		ErrorAndExit("UpdateTaskListBuildIDs failed", err)
+	}
+	fmt.Println("Build IDs of tasklist " + taskList + " updated.")
+}
+
+// PauseTaskList stops dispatching the tasks of a tasklist to pollers
+func PauseTaskList(c *cli.Context) {
+	adminClient := getAdminClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	taskList := getRequiredOption(c, FlagTaskList)
+	taskListType := shared.TaskListType(strToTaskListType(c.String(FlagTaskListType))) // default type is decision
+
+	ctx, cancel := newContext()
+	defer cancel()
+	err := adminClient.PauseTaskList(ctx, &shared.PauseTaskListRequest{
+		Domain:       common.StringPtr(domain),
+		TaskList:     &shared.TaskList{Name: common.StringPtr(taskList)},
+		TaskListType: &taskListType,
+	})
+	if err != nil {
+		ErrorAndExit("PauseTaskList failed", err)
+	}
+	fmt.Println("Tasklist " + taskList + " paused.")
+}
+
+// ResumeTaskList resumes dispatching the tasks of a paused tasklist
+func ResumeTaskList(c *cli.Context) {
+	adminClient := getAdminClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	taskList := getRequiredOption(c, FlagTaskList)
+	taskListType := shared.TaskListType(strToTaskListType(c.String(FlagTaskListType))) // default type is decision
+
+	ctx, cancel := newContext()
+	defer cancel()
+	err := adminClient.ResumeTaskList(ctx, &shared.ResumeTaskListRequest{
+		Domain:       common.StringPtr(domain),
+		TaskList:     &shared.TaskList{Name: common.StringPtr(taskList)},
+		TaskListType: &taskListType,
+	})
+	if err != nil {
+		ErrorAndExit("ResumeTaskList failed", err)
+	}
+	fmt.Println("Tasklist " + taskList + " resumed.")
+}
+
+// ListTaskLists lists the tasklists of a domain
+func ListTaskLists(c *cli.Context) {
+	adminClient := getAdminClient(c)
+	domain := getRequiredGlobalOption(c, FlagDomain)
+	more := c.Bool(FlagMore)
+	pageSize := c.Int(FlagPageSize)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetBorder(false)
+	table.SetColumnSeparator("|")
+	table.SetHeader([]string{"Name", "Type", "Kind", "Ack Level", "Last Updated"})
+	table.SetHeaderLine(false)
+	table.SetHeaderColor(tableHeaderBlue, tableHeaderBlue, tableHeaderBlue, tableHeaderBlue, tableHeaderBlue)
+
+	var nextPageToken []byte
+	for {
+		ctx, cancel := newContext()
+		response, err := adminClient.ListTaskLists(ctx, &shared.ListTaskListsRequest{
+			Domain:          common.StringPtr(domain),
+			MaximumPageSize: common.Int32Ptr(int32(pageSize)),
+			NextPageToken:   nextPageToken,
+		})
+		cancel()
+		if err != nil {
+			ErrorAndExit("ListTaskLists failed", err)
+		}
+
+		nextPageToken = response.NextPageToken
+		if len(response.TaskLists) == 0 && len(nextPageToken) > 0 {
+			// the pages of the Cassandra task store can be empty before the last one
+			continue
+		}
+		for _, info := range response.TaskLists {
+			lastUpdated := ""
+			if info.LastUpdatedTime != nil {
+				lastUpdated = convertTime(info.GetLastUpdatedTime(), false)
+			}
+			table.Append([]string{
+				info.GetName(),
+				info.GetTaskListType().String(),
+				info.GetKind().String(),
+				strconv.FormatInt(info.GetAckLevel(), 10),
+				lastUpdated,
+			})
+		}
+		table.Render()
+		table.ClearRows()
+
+		if !more || len(nextPageToken) == 0 {
+			break
+		}
+
+		fmt.Printf("Press %s to show next page, press %s to quit: ",
+			color.GreenString("Enter"), color.RedString("any other key then Enter"))
+		var input string
+		fmt.Scanln(&input)
+		if strings.Trim(input, " ") != "" {
+			break
+		}
+	}
+}
+
 func getDomainClient(c *cli.Context) client.DomainClient {
 	service, err := cBuilder.BuildServiceClient(c)
 	if err != nil {
@@ -947,6 +1226,24 @@ func getWorkflowServiceClient(c *cli.Context) workflowserviceclient.Interface {
 	return client
 }
 
+func getFrontendClient(c *cli.Context) serverFrontend.Interface {
+	client, err := cBuilder.BuildFrontendClient(c)
+	if err != nil {
+		ExitIfError(err)
+	}
+
+	return client
+}
+
+func getAdminClient(c *cli.Context) serverAdmin.Interface {
+	client, err := cBuilder.BuildAdminClient(c)
+	if err != nil {
+		ExitIfError(err)
+	}
+
+	return client
+}
+
 func getRequiredOption(c *cli.Context, optionName string) string {
 	value := c.String(optionName)
 	if len(value) == 0 {
@@ -21,11 +21,16 @@
 package cli
 
 import (
+	"context"
 	"errors"
 
+	serverAdmin "github.com/uber/cadence/.gen/go/admin/adminserviceclient"
+	serverFrontend "github.com/uber/cadence/.gen/go/cadence/workflowserviceclient"
+	"github.com/uber/cadence/common"
 	"github.com/urfave/cli"
 	"go.uber.org/cadence/.gen/go/cadence/workflowserviceclient"
 	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/api/transport"
 	"go.uber.org/yarpc/transport/tchannel"
 	"go.uber.org/zap"
 )
@@ -41,15 +46,23 @@ const (
 // The customized builder may have more processing on Env, Address and other info.
 type WorkflowClientBuilderInterface interface {
 	BuildServiceClient(c *cli.Context) (workflowserviceclient.Interface, error)
+	BuildFrontendClient(c *cli.Context) (serverFrontend.Interface, error)
+	BuildAdminClient(c *cli.Context) (serverAdmin.Interface, error)
 }
 
 // WorkflowClientBuilder build client to cadence service
 type WorkflowClientBuilder struct {
 	hostPort   string
+	authToken  string
 	dispatcher *yarpc.Dispatcher
 	logger     *zap.Logger
 }
 
+// authTokenMiddleware sends the authorization token in the header of every outbound call
+type authTokenMiddleware struct {
+	token string
+}
+
 // NewBuilder creates a new WorkflowClientBuilder
 func NewBuilder() *WorkflowClientBuilder {
 	logger, err := zap.NewDevelopment()
@@ -68,6 +81,7 @@ func (b *WorkflowClientBuilder) BuildServiceClient(c *cli.Context) (workflowserv
 	if addr := c.GlobalString(FlagAddress); addr != "" {
 		b.hostPort = addr
 	}
+	b.authToken = c.GlobalString(FlagAuthToken)
 
 	if err := b.build(); err != nil {
 		return nil, err
@@ -80,6 +94,45 @@ func (b *WorkflowClientBuilder) BuildServiceClient(c *cli.Context) (workflowserv
 	return workflowserviceclient.New(b.dispatcher.ClientConfig(_cadenceFrontendService)), nil
 }
 
+// BuildFrontendClient builds a rpc client to cadence frontend using the server IDL, which is required by the APIs
+// not exposed by the client library yet
+func (b *WorkflowClientBuilder) BuildFrontendClient(c *cli.Context) (serverFrontend.Interface, error) {
+	b.hostPort = localHostPort
+	if addr := c.GlobalString(FlagAddress); addr != "" {
+		b.hostPort = addr
+	}
+	b.authToken = c.GlobalString(FlagAuthToken)
+
+	if err := b.build(); err != nil {
+		return nil, err
+	}
+
+	if b.dispatcher == nil {
+		b.logger.Fatal("No RPC dispatcher provided to create a connection to Cadence Service")
+	}
+
+	return serverFrontend.New(b.dispatcher.ClientConfig(_cadenceFrontendService)), nil
+}
+
+// BuildAdminClient builds a rpc client to the admin APIs of cadence frontend
+func (b *WorkflowClientBuilder) BuildAdminClient(c *cli.Context) (serverAdmin.Interface, error) {
+	b.hostPort = localHostPort
+	if addr := c.GlobalString(FlagAddress); addr != "" {
+		b.hostPort = addr
+	}
+	b.authToken = c.GlobalString(FlagAuthToken)
+
+	if err := b.build(); err != nil {
+		return nil, err
+	}
+
+	if b.dispatcher == nil {
+		b.logger.Fatal("No RPC dispatcher provided to create a connection to Cadence Service")
+	}
+
+	return serverAdmin.New(b.dispatcher.ClientConfig(_cadenceFrontendService)), nil
+}
+
 func (b *WorkflowClientBuilder) build() error {
 	if b.dispatcher != nil {
 		return nil
@@ -95,12 +148,16 @@ func (b *WorkflowClientBuilder) build() error {
 		b.logger.Fatal("Failed to create transport channel", zap.Error(err))
 	}
 
-	b.dispatcher = yarpc.NewDispatcher(yarpc.Config{
+	config := yarpc.Config{
 		Name: _cadenceClientName,
 		Outbounds: yarpc.Outbounds{
 			_cadenceFrontendService: {Unary: ch.NewSingleOutbound(b.hostPort)},
 		},
-	})
+	}
+	if b.authToken != "" {
+		config.OutboundMiddleware = yarpc.OutboundMiddleware{Unary: &authTokenMiddleware{token: b.authToken}}
+	}
+	b.dispatcher = yarpc.NewDispatcher(config)
 
 	if b.dispatcher != nil {
 		if err := b.dispatcher.Start(); err != nil {
@@ -110,3 +167,9 @@ func (b *WorkflowClientBuilder) build() error {
 
 	return nil
 }
+
+func (m *authTokenMiddleware) Call(
+	ctx context.Context, request *transport.Request, out transport.UnaryOutbound) (*transport.Response, error) {
+	request.Headers = request.Headers.With(common.AuthorizationTokenHeaderName, m.token)
+	return out.Call(ctx, request)
+}
@@ -27,7 +27,7 @@ func newTaskListCommands() []cli.Command {
 		{
 			Name:    "describe",
 			Aliases: []string{"desc"},
-			Usage:   "Describe pollers info of tasklist",
+			Usage:   "Describe backlog status and pollers info of tasklist",
 			Flags: []cli.Flag{
 				cli.StringFlag{
 					Name:  FlagTaskListWithAlias,
@@ -43,5 +43,87 @@ func newTaskListCommands() []cli.Command {
 				DescribeTaskList(c)
 			},
 		},
+		{
+			Name:    "update-build-ids",
+			Aliases: []string{"ubid"},
+			Usage:   "Add a worker build ID to a decision tasklist, or retire it",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagBuildIDWithAlias,
+					Usage: "Worker build ID",
+				},
+				cli.StringFlag{
+					Name:  FlagCompatibleWithAlias,
+					Usage: "Optional existing build ID which the new build ID is compatible with; if not set the new build ID becomes the default",
+				},
+				cli.BoolFlag{
+					Name:  FlagRetire,
+					Usage: "Retire the build ID",
+				},
+			},
+			Action: func(c *cli.Context) {
+				UpdateTaskListBuildIDs(c)
+			},
+		},
+		{
+			Name:  "pause",
+			Usage: "Stop dispatching the tasks of tasklist to pollers, new tasks are kept until it is resumed",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Value: "decision",
+					Usage: "Optional TaskList type [decision|activity]",
+				},
+			},
+			Action: func(c *cli.Context) {
+				PauseTaskList(c)
+			},
+		},
+		{
+			Name:  "resume",
+			Usage: "Resume dispatching the tasks of a paused tasklist",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagTaskListWithAlias,
+					Usage: "TaskList name",
+				},
+				cli.StringFlag{
+					Name:  FlagTaskListTypeWithAlias,
+					Value: "decision",
+					Usage: "Optional TaskList type [decision|activity]",
+				},
+			},
+			Action: func(c *cli.Context) {
+				ResumeTaskList(c)
+			},
+		},
+		{
+			Name:        "list",
+			Aliases:     []string{"l"},
+			Usage:       "List the tasklists of a domain",
+			Description: "with Cassandra persistence the tasklists of every domain are scanned, which is slow on large clusters",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  FlagMoreWithAlias,
+					Usage: "List more pages, default is to list one page of default page size 100",
+				},
+				cli.IntFlag{
+					Name:  FlagPageSizeWithAlias,
+					Value: 100,
+					Usage: "Result page size",
+				},
+			},
+			Action: func(c *cli.Context) {
+				ListTaskLists(c)
+			},
+		},
 	}
 }
@@ -150,6 +150,9 @@ func HistoryEventToString(e *s.HistoryEvent) string {
 	case s.EventTypeMarkerRecorded:
 		data = e.MarkerRecordedEventAttributes
 
+	case s.EventTypeUpsertWorkflowSearchAttributes:
+		data = e.UpsertWorkflowSearchAttributesEventAttributes
+
 	case s.EventTypeWorkflowExecutionSignaled:
 		data = e.WorkflowExecutionSignaledEventAttributes
 
@@ -342,6 +345,9 @@ func ColorEvent(e *s.HistoryEvent) string {
 	case s.EventTypeMarkerRecorded:
 		data = e.EventType.String()
 
+	case s.EventTypeUpsertWorkflowSearchAttributes:
+		data = e.EventType.String()
+
 	case s.EventTypeWorkflowExecutionSignaled:
 		data = e.EventType.String()
 
@@ -227,6 +227,31 @@ func newWorkflowCommands() []cli.Command {
 				TerminateWorkflow(c)
 			},
 		},
+		{
+			Name:  "reset",
+			Usage: "reset the workflow, by creating a new run from the given DecisionTaskCompleted event and terminating the current run",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagWorkflowIDWithAlias,
+					Usage: "WorkflowID",
+				},
+				cli.StringFlag{
+					Name:  FlagRunIDWithAlias,
+					Usage: "RunID",
+				},
+				cli.Int64Flag{
+					Name:  FlagEventIDWithAlias,
+					Usage: "The eventID of a DecisionTaskCompleted event to reset to",
+				},
+				cli.StringFlag{
+					Name:  FlagReasonWithAlias,
+					Usage: "The reason you want to reset the workflow",
+				},
+			},
+			Action: func(c *cli.Context) {
+				ResetWorkflow(c)
+			},
+		},
 		{
 			Name:        "list",
 			Aliases:     []string{"l"},
@@ -262,6 +287,12 @@ func newWorkflowCommands() []cli.Command {
 					Name:  FlagWorkflowTypeWithAlias,
 					Usage: "WorkflowTypeName",
 				},
+				cli.StringFlag{
+					Name: FlagListQueryWithAlias,
+					Usage: "Query to list open and closed executions with, instead of the other filters, e.g. " +
+						"\"WorkflowType = 'main' AND CloseTime IS NULL ORDER BY StartTime DESC\". " +
+						"Not supported by clusters which keep visibility records in Cassandra",
+				},
 				cli.BoolFlag{
 					Name:  FlagPrintRawTimeWithAlias,
 					Usage: "Print raw time stamp",
@@ -275,6 +306,21 @@ func newWorkflowCommands() []cli.Command {
 				ListWorkflow(c)
 			},
 		},
+		{
+			Name:        "count",
+			Aliases:     []string{"cnt"},
+			Usage:       "count open and closed workflow executions which match a query",
+			Description: "queries are not supported by clusters which keep visibility records in Cassandra",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  FlagListQueryWithAlias,
+					Usage: "Query to count executions with, e.g. \"WorkflowType = 'main' AND CloseStatus = 1\", counts every execution if not set",
+				},
+			},
+			Action: func(c *cli.Context) {
+				CountWorkflow(c)
+			},
+		},
 		{
 			Name:    "listall",
 			Aliases: []string{"la"},
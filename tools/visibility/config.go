@@ -0,0 +1,88 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package visibility
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// BackfillConfig holds the config params of the backfill of the visibility store
+	BackfillConfig struct {
+		// RootDir, ConfigDir, Env and Zone locate the config of the server, like the flags of the server
+		RootDir   string
+		ConfigDir string
+		Env       string
+		Zone      string
+		// Path overrides the path of the visibility store of the server config when specified
+		Path string
+		// Reset deletes all the records of the visibility store before the backfill
+		Reset    bool
+		PageSize int
+	}
+
+	// ConfigError is an error type that
+	// represents a problem with the config
+	ConfigError struct {
+		msg string
+	}
+)
+
+const (
+	cliOptRoot     = "root"
+	cliOptConfig   = "config"
+	cliOptEnv      = "env"
+	cliOptZone     = "zone"
+	cliOptQuiet    = "quiet"
+	cliOptPath     = "path"
+	cliOptReset    = "reset"
+	cliOptPageSize = "page-size"
+
+	cliFlagRoot     = cliOptRoot + ", r"
+	cliFlagConfig   = cliOptConfig + ", c"
+	cliFlagEnv      = cliOptEnv + ", e"
+	cliFlagZone     = cliOptZone + ", az"
+	cliFlagQuiet    = cliOptQuiet + ", q"
+	cliFlagPath     = cliOptPath + ", p"
+	cliFlagReset    = cliOptReset
+	cliFlagPageSize = cliOptPageSize
+
+	defaultPageSize = 100
+)
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("Config Error:%v", e.msg)
+}
+
+func newConfigError(msg string) error {
+	return &ConfigError{msg: msg}
+}
+
+func validateBackfillConfig(config *BackfillConfig) error {
+	if len(strings.TrimSpace(config.ConfigDir)) == 0 {
+		return newConfigError("missing " + flag(cliOptConfig) + " argument")
+	}
+	if config.PageSize <= 0 {
+		return newConfigError(fmt.Sprintf("invalid %v argument: %v", flag(cliOptPageSize), config.PageSize))
+	}
+	return nil
+}
@@ -0,0 +1,341 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package visibility
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/uber-common/bark"
+	workflow "github.com/uber/cadence/.gen/go/shared"
+	"github.com/uber/cadence/common"
+	"github.com/uber/cadence/common/persistence"
+	"github.com/uber/cadence/common/service/config"
+	"github.com/urfave/cli"
+)
+
+// executionMgrNumConns is the number of connections to cassandra opened by the backfill
+const executionMgrNumConns = 10
+
+// closeStatuses maps the close statuses of the persistence to the ones of the visibility records
+var closeStatuses = map[int]workflow.WorkflowExecutionCloseStatus{
+	persistence.WorkflowCloseStatusCompleted:      workflow.WorkflowExecutionCloseStatusCompleted,
+	persistence.WorkflowCloseStatusFailed:         workflow.WorkflowExecutionCloseStatusFailed,
+	persistence.WorkflowCloseStatusCanceled:       workflow.WorkflowExecutionCloseStatusCanceled,
+	persistence.WorkflowCloseStatusTerminated:     workflow.WorkflowExecutionCloseStatusTerminated,
+	persistence.WorkflowCloseStatusContinuedAsNew: workflow.WorkflowExecutionCloseStatusContinuedAsNew,
+	persistence.WorkflowCloseStatusTimedOut:       workflow.WorkflowExecutionCloseStatusTimedOut,
+}
+
+type (
+	// backfillTask rebuilds the visibility store from the executions of all the shards
+	backfillTask struct {
+		config         *BackfillConfig
+		numShards      int
+		execMgrFactory persistence.ExecutionManagerFactory
+		metadataMgr    persistence.MetadataManager
+		visibilityMgr  persistence.VisibilityManager
+		store          *persistence.BoltVisibilityStore
+		logger         bark.Logger
+		retentionByID  map[string]int64
+		recordedCount  int
+	}
+)
+
+// backfill executes the backfillTask
+// using the given command line arguments
+// as input
+func backfill(cli *cli.Context) error {
+	config, err := newBackfillConfig(cli)
+	if err != nil {
+		return handleErr(newConfigError(err.Error()))
+	}
+	if err := handleBackfill(config); err != nil {
+		return handleErr(err)
+	}
+	return nil
+}
+
+// Backfill rebuilds the visibility store from the executions of the persistence
+func Backfill(config *BackfillConfig) error {
+	if err := validateBackfillConfig(config); err != nil {
+		return err
+	}
+	return handleBackfill(config)
+}
+
+func handleBackfill(config *BackfillConfig) error {
+	task, err := newBackfillTask(config)
+	if err != nil {
+		return fmt.Errorf("error creating task, err=%v", err)
+	}
+	defer task.close()
+	if err := task.run(); err != nil {
+		return fmt.Errorf("error backfilling the visibility store, err=%v", err)
+	}
+	return nil
+}
+
+func newBackfillConfig(cli *cli.Context) (*BackfillConfig, error) {
+	config := new(BackfillConfig)
+	config.RootDir = cli.GlobalString(cliOptRoot)
+	config.ConfigDir = cli.GlobalString(cliOptConfig)
+	config.Env = strings.TrimSpace(cli.GlobalString(cliOptEnv))
+	config.Zone = strings.TrimSpace(cli.GlobalString(cliOptZone))
+	config.Path = cli.String(cliOptPath)
+	config.Reset = cli.Bool(cliOptReset)
+	config.PageSize = cli.Int(cliOptPageSize)
+
+	if err := validateBackfillConfig(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func newBackfillTask(backfillConfig *BackfillConfig) (*backfillTask, error) {
+	var cfg config.Config
+	if err := config.Load(backfillConfig.Env, filepath.Join(backfillConfig.RootDir, backfillConfig.ConfigDir),
+		backfillConfig.Zone, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.InMemory {
+		return nil, newConfigError("the in-memory persistence does not outlive the server, there is nothing to backfill")
+	}
+
+	path := backfillConfig.Path
+	if len(path) == 0 {
+		if cfg.BoltVisibility == nil {
+			return nil, newConfigError("missing " + flag(cliOptPath) + " argument, the server config has no boltVisibility")
+		}
+		path = cfg.BoltVisibility.Path
+	}
+
+	task := &backfillTask{
+		config:        backfillConfig,
+		numShards:     cfg.Cassandra.NumHistoryShards,
+		logger:        cfg.Log.NewBarkLogger(),
+		retentionByID: make(map[string]int64),
+	}
+	clusterName := cfg.ClustersInfo.CurrentClusterName
+
+	var err error
+	if cfg.SQL != nil {
+		task.execMgrFactory, err = persistence.NewSQLPersistenceClientFactory(cfg.SQL.DriverName,
+			cfg.SQL.Host,
+			cfg.SQL.Port,
+			cfg.SQL.User,
+			cfg.SQL.Password,
+			cfg.SQL.DatabaseName,
+			cfg.SQL.MaxConns,
+			task.logger,
+			nil,
+		)
+		if err == nil {
+			task.metadataMgr, err = persistence.NewSQLMetadataPersistence(cfg.SQL.DriverName,
+				cfg.SQL.Host,
+				cfg.SQL.Port,
+				cfg.SQL.User,
+				cfg.SQL.Password,
+				cfg.SQL.DatabaseName,
+				cfg.SQL.MaxConns,
+				clusterName,
+				task.logger,
+			)
+		}
+	} else {
+		task.execMgrFactory, err = persistence.NewCassandraPersistenceClientFactory(cfg.Cassandra.Hosts,
+			cfg.Cassandra.Port,
+			cfg.Cassandra.User,
+			cfg.Cassandra.Password,
+			cfg.Cassandra.Datacenter,
+			cfg.Cassandra.Keyspace,
+			executionMgrNumConns,
+			task.logger,
+			nil,
+		)
+		if err == nil {
+			task.metadataMgr, err = persistence.NewCassandraMetadataPersistence(cfg.Cassandra.Hosts,
+				cfg.Cassandra.Port,
+				cfg.Cassandra.User,
+				cfg.Cassandra.Password,
+				cfg.Cassandra.Datacenter,
+				cfg.Cassandra.Keyspace,
+				clusterName,
+				task.logger,
+			)
+		}
+	}
+	if err != nil {
+		task.close()
+		return nil, err
+	}
+
+	task.store, err = persistence.NewBoltVisibilityStore(path)
+	if err != nil {
+		task.close()
+		return nil, err
+	}
+	task.visibilityMgr, err = persistence.NewBoltVisibilityPersistence(task.store, task.logger)
+	if err != nil {
+		task.close()
+		return nil, err
+	}
+	return task, nil
+}
+
+// run records the executions of every shard in the visibility store, the records of the executions already in the
+// store are overwritten by the closed ones
+func (t *backfillTask) run() error {
+	if t.config.Reset {
+		if err := t.store.Clear(); err != nil {
+			return err
+		}
+	}
+
+	for shardID := 0; shardID < t.numShards; shardID++ {
+		if err := t.backfillShard(shardID); err != nil {
+			return fmt.Errorf("shard %v: %v", shardID, err)
+		}
+	}
+	log.Printf("Recorded %v executions of %v shards\n", t.recordedCount, t.numShards)
+	return nil
+}
+
+func (t *backfillTask) backfillShard(shardID int) error {
+	executionMgr, err := t.execMgrFactory.CreateExecutionManager(shardID)
+	if err != nil {
+		return err
+	}
+	defer executionMgr.Close()
+
+	var nextPageToken []byte
+	for {
+		response, err := executionMgr.ListConcreteExecutions(&persistence.ListConcreteExecutionsRequest{
+			PageSize:      t.config.PageSize,
+			NextPageToken: nextPageToken,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, executionInfo := range response.ExecutionInfos {
+			if err := t.recordExecution(executionInfo); err != nil {
+				return fmt.Errorf("execution %v/%v/%v: %v", executionInfo.DomainID, executionInfo.WorkflowID,
+					executionInfo.RunID, err)
+			}
+		}
+
+		nextPageToken = response.NextPageToken
+		if len(nextPageToken) == 0 {
+			return nil
+		}
+	}
+}
+
+// recordExecution records the execution the way the history service does when it is started or closed
+func (t *backfillTask) recordExecution(executionInfo *persistence.WorkflowExecutionInfo) error {
+	execution := workflow.WorkflowExecution{
+		WorkflowId: common.StringPtr(executionInfo.WorkflowID),
+		RunId:      common.StringPtr(executionInfo.RunID),
+	}
+	startTimestamp := executionInfo.StartTimestamp.UnixNano()
+
+	if executionInfo.State != persistence.WorkflowStateCompleted {
+		t.recordedCount++
+		return t.visibilityMgr.RecordWorkflowExecutionStarted(&persistence.RecordWorkflowExecutionStartedRequest{
+			DomainUUID:       executionInfo.DomainID,
+			Execution:        execution,
+			WorkflowTypeName: executionInfo.WorkflowTypeName,
+			StartTimestamp:   startTimestamp,
+			SearchAttributes: executionInfo.SearchAttributes,
+		})
+	}
+
+	closeStatus, ok := closeStatuses[executionInfo.CloseStatus]
+	if !ok {
+		return fmt.Errorf("invalid close status %v", executionInfo.CloseStatus)
+	}
+	// the close time is the last update, which is always after the start time
+	closeTimestamp := executionInfo.LastUpdatedTimestamp.UnixNano()
+	if closeTimestamp <= startTimestamp {
+		closeTimestamp = startTimestamp + 1
+	}
+	retentionSeconds, err := t.getRetentionSeconds(executionInfo.DomainID)
+	if err != nil {
+		return err
+	}
+
+	t.recordedCount++
+	return t.visibilityMgr.RecordWorkflowExecutionClosed(&persistence.RecordWorkflowExecutionClosedRequest{
+		DomainUUID:       executionInfo.DomainID,
+		Execution:        execution,
+		WorkflowTypeName: executionInfo.WorkflowTypeName,
+		StartTimestamp:   startTimestamp,
+		CloseTimestamp:   closeTimestamp,
+		Status:           closeStatus,
+		HistoryLength:    executionInfo.NextEventID,
+		RetentionSeconds: retentionSeconds,
+		SearchAttributes: executionInfo.SearchAttributes,
+	})
+}
+
+// getRetentionSeconds returns the retention of the domain, no retention is used for the domains which got deleted
+func (t *backfillTask) getRetentionSeconds(domainID string) (int64, error) {
+	if retentionSeconds, ok := t.retentionByID[domainID]; ok {
+		return retentionSeconds, nil
+	}
+
+	retentionSeconds := int64(0)
+	response, err := t.metadataMgr.GetDomain(&persistence.GetDomainRequest{ID: domainID})
+	if err != nil {
+		if _, ok := err.(*workflow.EntityNotExistsError); !ok {
+			return 0, err
+		}
+	} else {
+		// retention in domain config is in days, convert to seconds
+		retentionSeconds = int64(response.Config.Retention) * 24 * 60 * 60
+	}
+	t.retentionByID[domainID] = retentionSeconds
+	return retentionSeconds, nil
+}
+
+func (t *backfillTask) close() {
+	if t.execMgrFactory != nil {
+		t.execMgrFactory.Close()
+	}
+	if t.metadataMgr != nil {
+		t.metadataMgr.Close()
+	}
+	if t.store != nil {
+		t.store.Close()
+	}
+}
+
+func flag(opt string) string {
+	return "(-" + opt + ")"
+}
+
+func handleErr(err error) error {
+	log.Println(err)
+	return err
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2017 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package visibility
+
+import (
+	"os"
+
+	"github.com/uber/cadence/common/service/config"
+	"github.com/urfave/cli"
+)
+
+// RunTool runs the cadence-visibility-tool command line tool
+func RunTool(args []string) error {
+	app := buildCLIOptions()
+	return app.Run(args)
+}
+
+// root handler for all cli commands
+func cliHandler(c *cli.Context, handler func(c *cli.Context) error) {
+	quiet := c.GlobalBool(cliOptQuiet)
+	err := handler(c)
+	if err != nil && !quiet {
+		os.Exit(1)
+	}
+}
+
+func buildCLIOptions() *cli.App {
+
+	app := cli.NewApp()
+	app.Name = "cadence-visibility-tool"
+	app.Usage = "Command line tool for the cadence BoltDB visibility store"
+	app.Version = "0.0.1"
+
+	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:   cliFlagRoot,
+			Value:  ".",
+			Usage:  "root directory of execution environment",
+			EnvVar: config.EnvKeyRoot,
+		},
+		cli.StringFlag{
+			Name:   cliFlagConfig,
+			Value:  "config",
+			Usage:  "config dir path relative to root",
+			EnvVar: config.EnvKeyConfigDir,
+		},
+		cli.StringFlag{
+			Name:   cliFlagEnv,
+			Value:  "development",
+			Usage:  "runtime environment",
+			EnvVar: config.EnvKeyEnvironment,
+		},
+		cli.StringFlag{
+			Name:   cliFlagZone,
+			Value:  "",
+			Usage:  "availability zone",
+			EnvVar: config.EnvKeyAvailabilityZone,
+		},
+		cli.BoolFlag{
+			Name:  cliFlagQuiet,
+			Usage: "Don't set exit status to 1 on error",
+		},
+	}
+
+	app.Commands = []cli.Command{
+		{
+			Name:  "backfill",
+			Usage: "rebuild the visibility store from the executions of the persistence, the server must be stopped",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  cliFlagPath,
+					Usage: "path of the visibility store file, defaults to the one of the server config",
+				},
+				cli.BoolFlag{
+					Name:  cliFlagReset,
+					Usage: "delete all the records of the visibility store before the backfill",
+				},
+				cli.IntFlag{
+					Name:  cliFlagPageSize,
+					Value: defaultPageSize,
+					Usage: "number of executions read from the persistence at once",
+				},
+			},
+			Action: func(c *cli.Context) {
+				cliHandler(c, backfill)
+			},
+		},
+	}
+
+	return app
+}